@@ -16,28 +16,54 @@ var (
 		Short:   "print information about the object",
 		Aliases: []string{"prin", "pri", "pr"},
 	}
+	subAllSnap = &cobra.Command{
+		Use:   "snap",
+		Short: "data snapshot commands",
+	}
+	subAllSync = &cobra.Command{
+		Use:   "sync",
+		Short: "data replication commands",
+	}
 )
 
 func init() {
 	var (
+		cmdBoot             commands.CmdObjectBoot
+		cmdConsole          commands.CmdObjectConsole
 		cmdCreate           commands.CmdObjectCreate
 		cmdDelete           commands.CmdObjectDelete
 		cmdEdit             commands.CmdObjectEdit
 		cmdEditConfig       commands.CmdObjectEditConfig
+		cmdEnter            commands.CmdObjectEnter
 		cmdEval             commands.CmdObjectEval
 		cmdFreeze           commands.CmdObjectFreeze
 		cmdGet              commands.CmdObjectGet
+		cmdGiveback         commands.CmdObjectGiveback
+		cmdLogs             commands.CmdObjectLogs
 		cmdLs               commands.CmdObjectLs
 		cmdMonitor          commands.CmdObjectMonitor
 		cmdPrintConfig      commands.CmdObjectPrintConfig
 		cmdPrintConfigMtime commands.CmdObjectPrintConfigMtime
+		cmdPrintDevices     commands.CmdObjectPrintDevices
+		cmdPrintKeywords    commands.CmdObjectPrintKeywords
 		cmdPrintSchedule    commands.CmdObjectPrintSchedule
+		cmdPrintRun         commands.CmdObjectPrintRun
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdProvision        commands.CmdObjectProvision
+		cmdRun              commands.CmdObjectRun
 		cmdSet              commands.CmdObjectSet
+		cmdSnapCreate       commands.CmdObjectSnapCreate
+		cmdSnapPrune        commands.CmdObjectSnapPrune
+		cmdSnapRollback     commands.CmdObjectSnapRollback
 		cmdStart            commands.CmdObjectStart
+		cmdStartStandby     commands.CmdObjectStartStandby
 		cmdStatus           commands.CmdObjectStatus
 		cmdStop             commands.CmdObjectStop
+		cmdSwitch           commands.CmdObjectSwitch
+		cmdSyncFull         commands.CmdObjectSyncFull
+		cmdSyncResync       commands.CmdObjectSyncResync
+		cmdSyncUpdate       commands.CmdObjectSyncUpdate
+		cmdTOC              commands.CmdObjectTOC
 		cmdUnfreeze         commands.CmdObjectUnfreeze
 		cmdUnprovision      commands.CmdObjectUnprovision
 		cmdUnset            commands.CmdObjectUnset
@@ -46,29 +72,51 @@ func init() {
 	kind := ""
 	head := subAll
 	subPrint := subAllPrint
+	subSnap := subAllSnap
+	subSync := subAllSync
 	root := rootCmd
 
 	root.AddCommand(head)
 	head.AddCommand(subPrint)
+	head.AddCommand(subSnap)
+	head.AddCommand(subSync)
 
+	cmdBoot.Init(kind, head, &selectorFlag)
+	cmdConsole.Init(kind, head, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
 	cmdEdit.Init(kind, head, &selectorFlag)
 	cmdEditConfig.Init(kind, cmdEdit.Command, &selectorFlag)
+	cmdEnter.Init(kind, head, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
 	cmdFreeze.Init(kind, head, &selectorFlag)
 	cmdGet.Init(kind, head, &selectorFlag)
+	cmdGiveback.Init(kind, head, &selectorFlag)
+	cmdLogs.Init(kind, head, &selectorFlag)
 	cmdLs.Init(kind, head, &selectorFlag)
 	cmdMonitor.Init(kind, head, &selectorFlag)
 	cmdPrintConfig.Init(kind, subPrint, &selectorFlag)
 	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &selectorFlag)
+	cmdPrintDevices.Init(kind, subPrint, &selectorFlag)
+	cmdPrintKeywords.Init(kind, subPrint, &selectorFlag)
 	cmdPrintSchedule.Init(kind, subPrint, &selectorFlag)
+	cmdPrintRun.Init(kind, subPrint, &selectorFlag)
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
 	cmdProvision.Init(kind, head, &selectorFlag)
+	cmdRun.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
+	cmdSnapCreate.Init(kind, subSnap, &selectorFlag)
+	cmdSnapPrune.Init(kind, subSnap, &selectorFlag)
+	cmdSnapRollback.Init(kind, subSnap, &selectorFlag)
 	cmdStart.Init(kind, head, &selectorFlag)
+	cmdStartStandby.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
 	cmdStop.Init(kind, head, &selectorFlag)
+	cmdSwitch.Init(kind, head, &selectorFlag)
+	cmdSyncFull.Init(kind, subSync, &selectorFlag)
+	cmdSyncResync.Init(kind, subSync, &selectorFlag)
+	cmdSyncUpdate.Init(kind, subSync, &selectorFlag)
+	cmdTOC.Init(kind, head, &selectorFlag)
 	cmdUnfreeze.Init(kind, head, &selectorFlag)
 	cmdUnprovision.Init(kind, head, &selectorFlag)
 	cmdUnset.Init(kind, head, &selectorFlag)
@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	rootApply commands.CmdApply
+)
+
+func init() {
+	rootApply.Init(rootCmd)
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	arrayCmd = &cobra.Command{
+		Use:   "array",
+		Short: "Manage the storage arrays",
+		Long:  ` An array is a storage appliance declared in a array#<name> section of the node or cluster configuration. Array drivers allocate disks for the disk.disk resources provisioning.`,
+	}
+	arrayAddCmd = &cobra.Command{
+		Use:   "add",
+		Short: "add an array item",
+	}
+	arrayDelCmd = &cobra.Command{
+		Use:   "del",
+		Short: "delete an array item",
+	}
+	arrayResizeCmd = &cobra.Command{
+		Use:   "resize",
+		Short: "resize an array item",
+	}
+)
+
+func init() {
+	var (
+		cmdArrayLs         commands.ArrayLs
+		cmdArrayAddDisk    commands.ArrayAddDisk
+		cmdArrayDelDisk    commands.ArrayDelDisk
+		cmdArrayResizeDisk commands.ArrayResizeDisk
+	)
+	rootCmd.AddCommand(arrayCmd)
+	arrayCmd.AddCommand(arrayAddCmd)
+	arrayCmd.AddCommand(arrayDelCmd)
+	arrayCmd.AddCommand(arrayResizeCmd)
+
+	cmdArrayLs.Init(arrayCmd)
+	cmdArrayAddDisk.Init(arrayAddCmd)
+	cmdArrayDelDisk.Init(arrayDelCmd)
+	cmdArrayResizeDisk.Init(arrayResizeCmd)
+}
@@ -40,10 +40,12 @@ func init() {
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
 		cmdGet              commands.CmdObjectGet
+		cmdLogs             commands.CmdObjectLogs
 		cmdLs               commands.CmdObjectLs
 		cmdMonitor          commands.CmdObjectMonitor
 		cmdPrintConfig      commands.CmdObjectPrintConfig
 		cmdPrintConfigMtime commands.CmdObjectPrintConfigMtime
+		cmdPrintKeywords    commands.CmdObjectPrintKeywords
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdSet              commands.CmdObjectSet
 		cmdStatus           commands.CmdObjectStatus
@@ -74,10 +76,12 @@ func init() {
 	cmdEval.Init(kind, head, &selectorFlag)
 	cmdGet.Init(kind, head, &selectorFlag)
 	cmdKeys.Init(kind, head, &selectorFlag)
+	cmdLogs.Init(kind, head, &selectorFlag)
 	cmdLs.Init(kind, head, &selectorFlag)
 	cmdMonitor.Init(kind, head, &selectorFlag)
 	cmdPrintConfig.Init(kind, subPrint, &selectorFlag)
 	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &selectorFlag)
+	cmdPrintKeywords.Init(kind, subPrint, &selectorFlag)
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
 	cmdRemove.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	subCluster = &cobra.Command{
+		Use:   "cluster",
+		Short: "Manage the cluster configuration",
+		Long: ` The cluster configuration is a ccfg kind object, stored in cluster.conf.
+
+It accepts the same keywords as node.conf, except the node-private ones, and
+is replicated to all the cluster nodes by the daemons. The node.conf keywords
+override the cluster.conf ones.`,
+	}
+	subClusterEdit = &cobra.Command{
+		Use:     "edit",
+		Short:   "Edit the cluster configuration",
+		Aliases: []string{"edi", "ed", "e"},
+	}
+	subClusterPrint = &cobra.Command{
+		Use:     "print",
+		Short:   "print information about the cluster configuration",
+		Aliases: []string{"prin", "pri", "pr"},
+	}
+
+	// clusterSelector is the selector of the ccfg object, not changeable
+	// from the command line.
+	clusterSelector = "cluster"
+)
+
+func init() {
+	var (
+		cmdArbitrate        commands.CmdClusterArbitrate
+		cmdEditConfig       commands.CmdClusterEditConfig
+		cmdEval             commands.CmdObjectEval
+		cmdGet              commands.CmdObjectGet
+		cmdInit             commands.CmdClusterInit
+		cmdPrintConfig      commands.CmdObjectPrintConfig
+		cmdPrintConfigMtime commands.CmdObjectPrintConfigMtime
+		cmdSet              commands.CmdClusterSet
+		cmdUnset            commands.CmdClusterUnset
+	)
+
+	kind := "ccfg"
+	head := subCluster
+	root := rootCmd
+
+	root.AddCommand(head)
+	head.AddCommand(subClusterEdit)
+	head.AddCommand(subClusterPrint)
+
+	cmdArbitrate.Init(head)
+	cmdEditConfig.Init(subClusterEdit)
+	cmdEval.Init(kind, head, &clusterSelector)
+	cmdGet.Init(kind, head, &clusterSelector)
+	cmdInit.Init(head)
+	cmdPrintConfig.Init(kind, subClusterPrint, &clusterSelector)
+	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &clusterSelector)
+	cmdSet.Init(head)
+	cmdUnset.Init(head)
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"opensvc.com/opensvc/core/completion"
+	"opensvc.com/opensvc/core/flag"
 )
 
 var completionCmd = &cobra.Command{
@@ -68,3 +71,72 @@ PowerShell:
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
+
+type flagCompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// flagCompletions maps the core/flag tags to the completion function of
+// the flags they describe.
+var flagCompletions = map[string]flagCompletionFunc{
+	"object":      completeObjectPaths,
+	"objselector": completeObjectPaths,
+	"node":        completeNodes,
+	"rid":         completeRIDs,
+	"kw":          completeKeywords,
+	"kws":         completeKeywords,
+	"kwops":       completeKeywordOps,
+}
+
+//
+// installFlagCompletions registers the completion functions of the flags
+// of cmd and its sub commands. A flag is identified by its name and
+// description, as declared in the core/flag tags.
+//
+func installFlagCompletions(cmd *cobra.Command) {
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		for tag, fn := range flagCompletions {
+			opt := flag.Tags[tag]
+			if opt.Long != f.Name || opt.Desc != f.Usage {
+				continue
+			}
+			_ = cmd.RegisterFlagCompletionFunc(f.Name, fn)
+		}
+	})
+	for _, c := range cmd.Commands() {
+		installFlagCompletions(c)
+	}
+}
+
+// completionSelector returns the object selector of the command line
+// being completed, from the --service or --selector flags, or from the
+// leading selector argument.
+func completionSelector(cmd *cobra.Command) string {
+	for _, name := range []string{"service", "selector"} {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() != "" {
+			return f.Value.String()
+		}
+	}
+	return selectorFlag
+}
+
+func completeObjectPaths(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.Paths(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeNodes(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.Nodes(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeRIDs(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.RIDs(completionSelector(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeKeywords(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.Keywords(completionSelector(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeKeywordOps completes the keyword part of a <kw><op><value>
+// keyword operation, without trailing space so the user can type the
+// operator and value.
+func completeKeywordOps(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completion.Keywords(completionSelector(cmd), toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var daemonCollectStatsCmd = &cobra.Command{
+	Use:   "collect-stats",
+	Short: "Sample the node resources usage into the stats ring database.",
+	Long: `Sample the node resources usage into the stats ring database.
+
+Sample the cpu, memory, swap, block devices, network devices and
+filesystems usage every stats.interval, and store the samples in the
+<var>/stats ring database, which keeps about one month of samples.
+
+The samples are printed by "om node print stats", and pushed to the
+collector by the scheduled "om node pushstats".`,
+	Run: daemonCollectStatsCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonCollectStatsCmd)
+}
+
+func daemonCollectStatsCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonCollectStats{}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
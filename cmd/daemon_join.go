@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var (
+	daemonJoinSecretFlag string
+	daemonJoinNodeFlag   string
+)
+
+var daemonJoinCmd = &cobra.Command{
+	Use:   "join",
+	Short: "Join the cluster of a seed node.",
+	Long: `Join the cluster of a seed node.
+
+The join request is sent to the seed node raw listener, encrypted with
+the cluster secret. The seed node adds the local node to the cluster
+nodes and returns the cluster configuration, installed as the local
+cluster.conf with its heartbeat sections. The listener ca and certificate
+secrets are then fetched from the seed node.`,
+	Run: daemonJoinCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonJoinCmd)
+	daemonJoinCmd.Flags().StringVar(&daemonJoinSecretFlag, "secret", "", "the cluster secret")
+	daemonJoinCmd.Flags().StringVar(&daemonJoinNodeFlag, "node", "", "the seed node name or raw api url")
+	_ = daemonJoinCmd.MarkFlagRequired("secret")
+	_ = daemonJoinCmd.MarkFlagRequired("node")
+}
+
+func daemonJoinCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonJoin{
+		Secret: daemonJoinSecretFlag,
+		Node:   daemonJoinNodeFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var daemonLeaveCmd = &cobra.Command{
+	Use:   "leave",
+	Short: "Leave the cluster.",
+	Long: `Leave the cluster.
+
+Each peer node is asked to remove the local node from its cluster nodes.
+The local cluster.conf is then reset to a single-node cluster, without
+heartbeats, stonith and arbitrators.`,
+	Run: daemonLeaveCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonLeaveCmd)
+}
+
+func daemonLeaveCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonLeave{
+		Server: serverFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var (
+	daemonMetricsListenFlag bool
+)
+
+var daemonMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Print or serve the cluster status as prometheus metrics.",
+	Long: `Print or serve the cluster status as prometheus metrics.
+
+Without --listen, the metrics are printed once in the prometheus text
+exposition format, usable by the node_exporter textfile collector.
+
+With --listen, the metrics are served on the /metrics path of the listener
+configured by the metrics.addr, metrics.port, metrics.cert and metrics.key
+node keywords.
+
+The daemon serves the local cluster metrics on this listener when the
+node.conf metrics section is set, so --listen is only useful to export
+the metrics of a remote cluster api, selected by --server.`,
+	Run: daemonMetricsCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonMetricsCmd)
+	daemonMetricsCmd.Flags().BoolVar(&daemonMetricsListenFlag, "listen", false, "serve the metrics on the configured listener")
+}
+
+func daemonMetricsCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonMetrics{
+		Server: serverFlag,
+		Listen: daemonMetricsListenFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
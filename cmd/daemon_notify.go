@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var daemonNotifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Post the notable cluster events to the configured notifiers.",
+	Long: `Post the notable cluster events to the configured notifiers.
+
+Follow the daemon event stream and post the object transitions, the
+failovers, the node and object freezes, the node monitor status changes
+and the stale heartbeats to the webhooks, slack-compatible endpoints and
+snmp trap receivers configured by the node.conf notify#<name> sections.
+
+The snmp traps are defined by the OPENSVC-MIB module, found in the
+core/notify directory of the source tree.
+
+Each notifier is rate limited by its notify#<name>.rate_limit and
+notify#<name>.rate_interval keywords, and its notification text can be
+formatted by its notify#<name>.template keyword.
+
+The daemon runs this notifier on the local cluster events when a notify
+section is set, so this command is only useful to notify the events of a
+remote cluster api, selected by --server.`,
+	Run: daemonNotifyCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonNotifyCmd)
+}
+
+func daemonNotifyCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonNotify{
+		Server: serverFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
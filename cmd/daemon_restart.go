@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/daemon"
+)
+
+var daemonRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the daemon.",
+	Run:   daemonRestartCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonRestartCmd)
+}
+
+func daemonRestartCmdRun(_ *cobra.Command, _ []string) {
+	if err := daemon.Restart(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
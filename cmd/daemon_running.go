@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/daemon"
+)
+
+var daemonRunningCmd = &cobra.Command{
+	Use:   "running",
+	Short: "Exit 0 if the daemon is running, 1 if not.",
+	Long: `Exit 0 if the daemon is running, 1 if not.
+
+The daemon is running if the process recorded in the pid file is alive,
+or if the api unix domain sockets accept connections.`,
+	Run: daemonRunningCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonRunningCmd)
+}
+
+func daemonRunningCmdRun(_ *cobra.Command, _ []string) {
+	if !daemon.IsRunning() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+)
+
+var (
+	daemonStartForegroundFlag bool
+)
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the daemon.",
+	Long: `Start the daemon.
+
+If the opensvc-agent systemd unit is installed, the start is delegated to
+systemd. Otherwise a detached daemon process is spawned.
+
+With --foreground, the daemon runs in the current process, logging to
+stderr, until a SIGTERM or SIGINT signal. This is the mode the systemd
+unit ExecStart must use.`,
+	Run: daemonStartCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStartCmd)
+	daemonStartCmd.Flags().BoolVar(&daemonStartForegroundFlag, "foreground", false, "run the daemon in the current process, for debugging or supervision")
+}
+
+func daemonStartCmdRun(_ *cobra.Command, _ []string) {
+	err := entrypoints.DaemonStart{
+		Foreground: daemonStartForegroundFlag,
+	}.Do()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
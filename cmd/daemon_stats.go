@@ -6,23 +6,34 @@ import (
 
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/entrypoints"
+	"opensvc.com/opensvc/core/flag"
+)
+
+var (
+	daemonStatsNodeFlag string
 )
 
 var daemonStatsCmd = &cobra.Command{
 	Use:   "stats",
-	Short: "Print the resource usage statistics.",
-	Run:   daemonStatsCmdRun,
+	Short: "Print the daemon threads and objects resource usage statistics.",
+	Long: `Print the daemon threads and objects resource usage statistics.
+
+The statistics of the nodes selected by --node are fetched through the
+daemon api, and aggregated in a per-node tree.`,
+	Run: daemonStatsCmdRun,
 }
 
 func init() {
 	daemonCmd.AddCommand(daemonStatsCmd)
+	daemonStatsCmd.Flags().StringVar(&daemonStatsNodeFlag, "node", "", flag.Tags["node"].Desc)
 }
 
 func daemonStatsCmdRun(_ *cobra.Command, _ []string) {
 	err := entrypoints.DaemonStats{
-		Format: formatFlag,
-		Color:  colorFlag,
-		Server: serverFlag,
+		Format:       formatFlag,
+		Color:        colorFlag,
+		Server:       serverFlag,
+		NodeSelector: daemonStatsNodeFlag,
 	}.Do()
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
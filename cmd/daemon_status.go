@@ -12,6 +12,7 @@ import (
 var (
 	daemonStatusWatchFlag    bool
 	daemonStatusSelectorFlag string
+	daemonStatusSectionsFlag string
 )
 
 var daemonStatusCmd = &cobra.Command{
@@ -26,12 +27,16 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonStatusCmd.Flags().BoolVarP(&daemonStatusWatchFlag, "watch", "w", false, "Watch the monitor changes")
 	daemonStatusCmd.Flags().StringVarP(&daemonStatusSelectorFlag, "selector", "s", "**", "Select opensvc objects (ex: **/db*,*/svc/db*)")
+	daemonStatusCmd.Flags().StringVar(&daemonStatusSectionsFlag, "sections", "", "Comma-separated list of sections to display (threads,arbitrators,nodes,objects). Default is all sections")
 }
 
 func daemonStatusCmdRun(_ *cobra.Command, _ []string) {
 	m := monitor.New()
 	m.SetColor(colorFlag)
 	m.SetFormat(formatFlag)
+	m.SetSelector(daemonStatusSelectorFlag)
+	m.SetNamespace(namespace())
+	m.SetSections(splitSections(daemonStatusSectionsFlag))
 
 	cli, err := client.New(client.WithURL(serverFlag))
 	if err != nil {
@@ -40,9 +45,13 @@ func daemonStatusCmdRun(_ *cobra.Command, _ []string) {
 	}
 	if daemonStatusWatchFlag {
 		getter := cli.NewGetEvents().SetSelector(daemonStatusSelectorFlag)
+		m.SetInput(os.Stdin)
+		m.SetStatusGetter(cli.NewGetDaemonStatus().SetSelector(daemonStatusSelectorFlag))
 		_ = m.DoWatch(getter, os.Stdout)
 	} else {
-		getter := cli.NewGetDaemonStatus().SetSelector(daemonStatusSelectorFlag)
+		getter := cli.NewGetDaemonStatus().
+			SetSelector(daemonStatusSelectorFlag).
+			SetSections(splitSections(daemonStatusSectionsFlag))
 		m.Do(getter, os.Stdout)
 	}
 }
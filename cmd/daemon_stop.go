@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/daemon"
+)
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon.",
+	Long: `Stop the daemon.
+
+If the opensvc-agent systemd unit is installed, the stop is delegated to
+systemd. Otherwise a SIGTERM signal is sent to the pid file process, and
+the command waits for its exit.`,
+	Run: daemonStopCmdRun,
+}
+
+func init() {
+	daemonCmd.AddCommand(daemonStopCmd)
+}
+
+func daemonStopCmdRun(_ *cobra.Command, _ []string) {
+	if err := daemon.Stop(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/client"
@@ -12,6 +13,7 @@ import (
 var (
 	monWatchFlag    bool
 	monSelectorFlag string
+	monSectionsFlag string
 )
 
 var monCmd = &cobra.Command{
@@ -26,12 +28,16 @@ func init() {
 	rootCmd.AddCommand(monCmd)
 	monCmd.Flags().StringVarP(&monSelectorFlag, "selector", "s", "*", "An object selector expression")
 	monCmd.Flags().BoolVarP(&monWatchFlag, "watch", "w", false, "Watch the monitor changes")
+	monCmd.Flags().StringVar(&monSectionsFlag, "sections", "", "Comma-separated list of sections to display (threads,arbitrators,nodes,objects). Default is all sections")
 }
 
 func monCmdRun(_ *cobra.Command, _ []string) {
 	m := monitor.New()
 	m.SetColor(colorFlag)
 	m.SetFormat(formatFlag)
+	m.SetSelector(monSelectorFlag)
+	m.SetNamespace(namespace())
+	m.SetSections(splitSections(monSectionsFlag))
 	cli, err := client.New(client.WithURL(serverFlag))
 	if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
@@ -39,12 +45,26 @@ func monCmdRun(_ *cobra.Command, _ []string) {
 	}
 	if monWatchFlag {
 		getter := cli.NewGetEvents().SetSelector(monSelectorFlag)
+		m.SetInput(os.Stdin)
+		m.SetStatusGetter(cli.NewGetDaemonStatus().SetSelector(monSelectorFlag))
 		if err = m.DoWatch(getter, os.Stdout); err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, err)
 			return
 		}
 	} else {
-		getter := cli.NewGetDaemonStatus().SetSelector(monSelectorFlag)
+		getter := cli.NewGetDaemonStatus().
+			SetSelector(monSelectorFlag).
+			SetSections(splitSections(monSectionsFlag))
 		m.Do(getter, os.Stdout)
 	}
 }
+
+// splitSections returns the list of sections from the comma-separated
+// --sections flag value. An empty value returns an empty list, interpreted
+// as all sections by the monitor.
+func splitSections(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
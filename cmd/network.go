@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/commands"
+)
+
+var (
+	networkCmd = &cobra.Command{
+		Use:   "network",
+		Short: "Manage the cluster backend networks",
+		Long:  ` A network is declared in a network#<name> section of the node or cluster configuration. The ip resources can allocate their address from a network, and the routed_bridge networks make the containers addresses reachable cluster-wide.`,
+	}
+)
+
+func init() {
+	var (
+		cmdNetworkLs     commands.NetworkLs
+		cmdNetworkSetup  commands.NetworkSetup
+		cmdNetworkStatus commands.NetworkStatus
+	)
+	rootCmd.AddCommand(networkCmd)
+
+	cmdNetworkLs.Init(networkCmd)
+	cmdNetworkSetup.Init(networkCmd)
+	cmdNetworkStatus.Init(networkCmd)
+}
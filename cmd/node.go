@@ -14,24 +14,56 @@ var (
 		Use:   "print",
 		Short: "Print node",
 	}
+	nodeComplianceCmd = &cobra.Command{
+		Use:     "compliance",
+		Short:   "Run the compliance modules",
+		Aliases: []string{"compli", "comp", "com"},
+	}
 	nodeScanCmd = &cobra.Command{
 		Use:   "scan",
 		Short: "Scan node",
 	}
 
 	cmdNodeChecks            commands.CmdNodeChecks
+	cmdNodeComplianceAuto    commands.NodeComplianceAuto
+	cmdNodeComplianceCheck   commands.NodeComplianceCheck
+	cmdNodeComplianceFix     commands.NodeComplianceFix
+	cmdNodeFreeze            commands.NodeFreeze
 	cmdNodeLs                commands.NodeLs
 	cmdNodePrintCapabilities commands.NodePrintCapabilities
+	cmdNodePrintDrivers      commands.NodePrintDrivers
+	cmdNodePrintPRKey        commands.NodePrintPRKey
+	cmdNodePrintSchedule     commands.NodePrintSchedule
+	cmdNodePrintStats        commands.NodePrintStats
+	cmdNodePushStats         commands.NodePushStats
+	cmdNodeReboot            commands.NodeReboot
 	cmdNodeScanCapabilities  commands.NodeScanCapabilities
+	cmdNodeScanSCSI          commands.NodeScanSCSI
+	cmdNodeShutdown          commands.NodeShutdown
+	cmdNodeUnfreeze          commands.NodeUnfreeze
 )
 
 func init() {
 	rootCmd.AddCommand(nodeCmd)
 	nodeCmd.AddCommand(nodePrintCmd)
+	nodeCmd.AddCommand(nodeComplianceCmd)
 	nodeCmd.AddCommand(nodeScanCmd)
 
 	cmdNodeChecks.Init(nodeCmd)
+	cmdNodeComplianceAuto.Init(nodeComplianceCmd)
+	cmdNodeComplianceCheck.Init(nodeComplianceCmd)
+	cmdNodeComplianceFix.Init(nodeComplianceCmd)
+	cmdNodeFreeze.Init(nodeCmd)
 	cmdNodeLs.Init(nodeCmd)
 	cmdNodePrintCapabilities.Init(nodePrintCmd)
+	cmdNodePrintDrivers.Init(nodePrintCmd)
+	cmdNodePrintPRKey.Init(nodePrintCmd)
+	cmdNodePrintSchedule.Init(nodePrintCmd)
+	cmdNodePrintStats.Init(nodePrintCmd)
+	cmdNodePushStats.Init(nodeCmd)
+	cmdNodeReboot.Init(nodeCmd)
 	cmdNodeScanCapabilities.Init(nodeScanCmd)
+	cmdNodeScanSCSI.Init(nodeScanCmd)
+	cmdNodeShutdown.Init(nodeCmd)
+	cmdNodeUnfreeze.Init(nodeCmd)
 }
@@ -12,8 +12,17 @@ var nodeEventsCmd = &cobra.Command{
 	Run:     nodeEventsCmdRun,
 }
 
+var (
+	nodeEventsSinceFlag string
+	nodeEventsUntilFlag string
+	nodeEventsKindFlag  []string
+)
+
 func init() {
 	nodeCmd.AddCommand(nodeEventsCmd)
+	nodeEventsCmd.Flags().StringVar(&nodeEventsSinceFlag, "since", "", "print the persisted events, including the daemon events and the actions audit records, more recent than this duration or RFC3339 date before following the stream (ex: 1h)")
+	nodeEventsCmd.Flags().StringVar(&nodeEventsUntilFlag, "until", "", "print the persisted events older than this duration or RFC3339 date, and do not follow the stream")
+	nodeEventsCmd.Flags().StringSliceVar(&nodeEventsKindFlag, "kind", []string{}, "print only the events of these kinds (ex: event,patch,audit)")
 }
 
 func nodeEventsCmdRun(_ *cobra.Command, _ []string) {
@@ -21,6 +30,9 @@ func nodeEventsCmdRun(_ *cobra.Command, _ []string) {
 		Format: formatFlag,
 		Color:  colorFlag,
 		Server: serverFlag,
+		Since:  nodeEventsSinceFlag,
+		Until:  nodeEventsUntilFlag,
+		Kinds:  nodeEventsKindFlag,
 	}
 	e.Do()
 }
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,7 +12,10 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/exitcode"
 	"opensvc.com/opensvc/core/osagentservice"
+	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/util/file"
@@ -23,13 +28,14 @@ import (
 )
 
 var (
-	configFlag   string
-	colorFlag    string
-	colorLogFlag string
-	formatFlag   string
-	selectorFlag string
-	serverFlag   string
-	debugFlag    bool
+	configFlag    string
+	colorFlag     string
+	colorLogFlag  string
+	formatFlag    string
+	selectorFlag  string
+	serverFlag    string
+	debugFlag     bool
+	namespaceFlag string
 )
 
 var rootCmd = &cobra.Command{
@@ -39,7 +45,7 @@ var rootCmd = &cobra.Command{
 	ValidArgsFunction: validArgs,
 	BashCompletionFunction: `__opensvc_handle_word()
 {
-    [ $cword -gt 1 ] && [ ! -z "${words[1]}" ] && ! __opensvc_contains_word ${words[1]} svc vol sec cfg usr ccfg nscfg all completion create daemon monitor help && {
+    [ $cword -gt 1 ] && [ ! -z "${words[1]}" ] && ! __opensvc_contains_word ${words[1]} svc vol sec cfg usr ccfg nscfg all cluster completion create daemon monitor help && {
         words[1]="all"
     }
     ___opensvc_handle_word
@@ -107,6 +113,9 @@ func configureLogger() {
 		zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	}
 
+	sinks, errs := newLogSinks()
+	logging.SetDefaultSinks(sinks...)
+
 	l := logging.Configure(logging.Config{
 		ConsoleLoggingEnabled: true,
 		EncodeLogsAsJSON:      true,
@@ -122,21 +131,108 @@ func configureLogger() {
 		Str("sid", xsession.ID).
 		Logger()
 	log.Logger = l
+	for _, err := range errs {
+		log.Warn().Err(err).Msg("configure log sink")
+	}
+}
+
+// newLogSinks returns the writers of the sinks set by the node.conf
+// log.sinks keyword, and the errors of the sinks that can not be opened.
+func newLogSinks() ([]io.Writer, []error) {
+	var (
+		sinks []io.Writer
+		errs  []error
+	)
+	for _, name := range strings.Fields(rawconfig.Node.Log.Sinks) {
+		var (
+			w   io.Writer
+			err error
+		)
+		switch name {
+		case "syslog":
+			w, err = newSyslogSink()
+		case "journald":
+			w, err = newJournaldSink()
+		default:
+			err = fmt.Errorf("unknown log sink %s", name)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sinks = append(sinks, w)
+	}
+	return sinks, errs
 }
 
-func persistentPreRunE(_ *cobra.Command, _ []string) error {
+func newSyslogSink() (io.Writer, error) {
+	c := rawconfig.Node.Syslog
+	level, err := logging.ParseLevel(c.Level)
+	if err != nil {
+		return nil, err
+	}
+	config := logging.SyslogConfig{
+		Network:  c.Protocol,
+		Facility: c.Facility,
+		Level:    level,
+		SDID:     c.SDID,
+		Hostname: hostname.Hostname(),
+	}
+	host, port := c.Host, c.Port
+	switch {
+	case host == "" && port == "" && file.Exists("/dev/log"):
+		config.Network = "unixgram"
+		config.Addr = "/dev/log"
+	default:
+		if host == "" {
+			host = "localhost"
+		}
+		if port == "" {
+			port = "514"
+		}
+		config.Addr = net.JoinHostPort(host, port)
+	}
+	return logging.NewSyslogWriter(config)
+}
+
+func newJournaldSink() (io.Writer, error) {
+	level, err := logging.ParseLevel(rawconfig.Node.Journald.Level)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewJournaldWriter(logging.JournaldConfig{Level: level})
+}
+
+func persistentPreRunE(cmd *cobra.Command, _ []string) error {
 	if err := hostname.Error(); err != nil {
 		return err
 	}
+	if f := cmd.Flags().Lookup("format"); f != nil {
+		if err := output.Validate(f.Value.String()); err != nil {
+			return err
+		}
+	}
 	configureLogger()
 	if env.HasDaemonOrigin() {
 		if err := osagentservice.Join(); err != nil {
 			log.Logger.Debug().Err(err).Msg("")
 		}
+		storeAudit()
 	}
 	return nil
 }
 
+// storeAudit records the command, its requester and its result in the
+// node events store when the command terminates.
+func storeAudit() {
+	audit := event.NewAudit(os.Args)
+	exitcode.AtExit(func(code int) {
+		if err := event.NewStore(hostname.Hostname()).Append(audit.Event(code)); err != nil {
+			log.Logger.Warn().Err(err).Msg("store the action audit record")
+		}
+	})
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -150,6 +246,7 @@ func setExecuteArgs(args []string) {
 	//   Cobra uses __complete and __completeNoDesc hidden actions
 	//
 	if len(args) > 0 && strings.HasPrefix(args[0], "__complete") {
+		installFlagCompletions(rootCmd)
 		//
 		// Example:
 		//   args = [__completeNoDesc test/svc/s1 pri]
@@ -191,8 +288,10 @@ func ExecuteArgs(args []string) {
 	setExecuteArgs(args)
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
+		exitcode.Done(exitcode.Error)
 		os.Exit(1)
 	}
+	exitcode.Done(exitcode.OK)
 }
 
 func guessSubsystem(s string) string {
@@ -208,9 +307,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&configFlag, "config", "", "config file (default \"$HOME/.opensvc.yaml\")")
 	rootCmd.PersistentFlags().StringVar(&colorFlag, "color", "auto", "output colorization yes|no|auto")
 	rootCmd.PersistentFlags().StringVar(&colorLogFlag, "colorlog", "auto", "log output colorization yes|no|auto")
-	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "auto", "output format json|flat|auto")
+	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "auto", "output format json|flat|csv|yaml|auto")
 	rootCmd.PersistentFlags().StringVar(&serverFlag, "server", "", "uri of the opensvc api server. scheme raw|https")
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "show debug log")
+	rootCmd.PersistentFlags().StringVar(&namespaceFlag, "namespace", "", "restrict the selection to the objects of this namespace, and create the new objects in this namespace. defaults to the OSVC_NAMESPACE environment variable value")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -239,6 +339,15 @@ func initConfig() {
 	}
 }
 
+// namespace returns the namespace of the --namespace flag, or the
+// OSVC_NAMESPACE environment variable value if the flag is not set.
+func namespace() string {
+	if namespaceFlag != "" {
+		return namespaceFlag
+	}
+	return env.Namespace()
+}
+
 // mergeSelector returns the selector from argv[1], or falls back to
 // the selector passed by the -s flag.
 func mergeSelector(subsysSelector string, kind string, deft string) string {
@@ -40,10 +40,12 @@ func init() {
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
 		cmdGet              commands.CmdObjectGet
+		cmdLogs             commands.CmdObjectLogs
 		cmdLs               commands.CmdObjectLs
 		cmdMonitor          commands.CmdObjectMonitor
 		cmdPrintConfig      commands.CmdObjectPrintConfig
 		cmdPrintConfigMtime commands.CmdObjectPrintConfigMtime
+		cmdPrintKeywords    commands.CmdObjectPrintKeywords
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdSet              commands.CmdObjectSet
 		cmdStatus           commands.CmdObjectStatus
@@ -55,6 +57,7 @@ func init() {
 		cmdKeys    commands.CmdKeystoreKeys
 		cmdRemove  commands.CmdKeystoreRemove
 		cmdGenCert commands.CmdSecGenCert
+		cmdRekey   commands.CmdSecRekey
 	)
 
 	kind := "sec"
@@ -76,11 +79,14 @@ func init() {
 	cmdGenCert.Init(kind, head, &selectorFlag)
 	cmdGet.Init(kind, head, &selectorFlag)
 	cmdKeys.Init(kind, head, &selectorFlag)
+	cmdLogs.Init(kind, head, &selectorFlag)
 	cmdLs.Init(kind, head, &selectorFlag)
 	cmdMonitor.Init(kind, head, &selectorFlag)
 	cmdPrintConfig.Init(kind, subPrint, &selectorFlag)
 	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &selectorFlag)
+	cmdPrintKeywords.Init(kind, subPrint, &selectorFlag)
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
+	cmdRekey.Init(kind, head, &selectorFlag)
 	cmdRemove.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
@@ -11,6 +11,13 @@ var (
 		Short: "Manage users",
 		Long: ` A user stores the grants and credentials of user of the agent API.
 
+The grant keyword lists the <role>[:<namespace pattern>] granted to the
+user, for example "admin:test* guest:*".
+
+The credentials are stored as encrypted keys: a "password" key for basic
+authentication, or a certificate generated by gencert for x509
+authentication.
+
 User objects are not necessary with OpenID authentication, as the
 grants are embedded in the trusted bearer tokens.`,
 	}
@@ -29,14 +36,24 @@ func init() {
 		cmdEditConfig       commands.CmdObjectEditConfig
 		cmdEval             commands.CmdObjectEval
 		cmdGet              commands.CmdObjectGet
+		cmdLogs             commands.CmdObjectLogs
 		cmdLs               commands.CmdObjectLs
 		cmdMonitor          commands.CmdObjectMonitor
 		cmdPrintConfig      commands.CmdObjectPrintConfig
 		cmdPrintConfigMtime commands.CmdObjectPrintConfigMtime
+		cmdPrintKeywords    commands.CmdObjectPrintKeywords
 		cmdPrintStatus      commands.CmdObjectPrintStatus
 		cmdSet              commands.CmdObjectSet
 		cmdStatus           commands.CmdObjectStatus
 		cmdUnset            commands.CmdObjectUnset
+
+		cmdAdd     commands.CmdKeystoreAdd
+		cmdChange  commands.CmdKeystoreChange
+		cmdDecode  commands.CmdKeystoreDecode
+		cmdKeys    commands.CmdKeystoreKeys
+		cmdRemove  commands.CmdKeystoreRemove
+		cmdGenCert commands.CmdSecGenCert
+		cmdRekey   commands.CmdSecRekey
 	)
 
 	kind := "usr"
@@ -47,17 +64,26 @@ func init() {
 	root.AddCommand(head)
 	head.AddCommand(subPrint)
 
+	cmdAdd.Init(kind, head, &selectorFlag)
+	cmdChange.Init(kind, head, &selectorFlag)
 	cmdCreate.Init(kind, head, &selectorFlag)
 	cmdDelete.Init(kind, head, &selectorFlag)
+	cmdDecode.Init(kind, head, &selectorFlag)
 	cmdEdit.Init(kind, head, &selectorFlag)
 	cmdEditConfig.Init(kind, cmdEdit.Command, &selectorFlag)
 	cmdEval.Init(kind, head, &selectorFlag)
+	cmdGenCert.Init(kind, head, &selectorFlag)
 	cmdGet.Init(kind, head, &selectorFlag)
+	cmdKeys.Init(kind, head, &selectorFlag)
+	cmdLogs.Init(kind, head, &selectorFlag)
 	cmdLs.Init(kind, head, &selectorFlag)
 	cmdMonitor.Init(kind, head, &selectorFlag)
 	cmdPrintConfig.Init(kind, subPrint, &selectorFlag)
 	cmdPrintConfigMtime.Init(kind, cmdPrintConfig.Command, &selectorFlag)
+	cmdPrintKeywords.Init(kind, subPrint, &selectorFlag)
 	cmdPrintStatus.Init(kind, subPrint, &selectorFlag)
+	cmdRekey.Init(kind, head, &selectorFlag)
+	cmdRemove.Init(kind, head, &selectorFlag)
 	cmdSet.Init(kind, head, &selectorFlag)
 	cmdStatus.Init(kind, head, &selectorFlag)
 	cmdUnset.Init(kind, head, &selectorFlag)
@@ -2,6 +2,7 @@ package actioncontext
 
 import (
 	"context"
+	"time"
 
 	"opensvc.com/opensvc/core/actionrollback"
 	"opensvc.com/opensvc/core/objectactionprops"
@@ -22,6 +23,9 @@ type (
 	isLeaderer interface {
 		IsLeader() bool
 	}
+	targetser interface {
+		Targets() []string
+	}
 	toStrer interface {
 		ToStr() string
 	}
@@ -31,14 +35,26 @@ type (
 	isRollbackDisableder interface {
 		IsRollbackDisabled() bool
 	}
+
+	// detached is a context never cancelled, carrying the values of its
+	// parent context.
+	detached struct {
+		parent context.Context
+	}
 )
 
 const (
 	tKey key = 0
 )
 
-func New(options interface{}, props objectactionprops.T) context.Context {
-	ctx := context.WithValue(context.Background(), tKey, &T{
+//
+// New returns a context derived from the parent context, so the action
+// is cancelled with its caller, and carrying the action options and
+// properties. A new rollback stack is added to the context if the action
+// supports rollback.
+//
+func New(parent context.Context, options interface{}, props objectactionprops.T) context.Context {
+	ctx := context.WithValue(parent, tKey, &T{
 		Props:   props,
 		Options: options,
 	})
@@ -48,8 +64,30 @@ func New(options interface{}, props objectactionprops.T) context.Context {
 	return ctx
 }
 
+// Value returns the action options and properties carried by the
+// context, or a zero value if the context is not an action context, like
+// the context of a driver executed standalone.
 func Value(ctx context.Context) *T {
-	return ctx.Value(tKey).(*T)
+	if t, ok := ctx.Value(tKey).(*T); ok {
+		return t
+	}
+	return &T{}
+}
+
+//
+// Detach returns a context carrying the values of ctx, but never
+// cancelled, for the tasks that must be done even if the action timed
+// out, like the post-action status evaluation.
+//
+func Detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+func (t detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (t detached) Done() <-chan struct{}       { return nil }
+func (t detached) Err() error                  { return nil }
+func (t detached) Value(k interface{}) interface{} {
+	return t.parent.Value(k)
 }
 
 func Options(ctx context.Context) interface{} {
@@ -101,3 +139,12 @@ func IsLeader(ctx context.Context) bool {
 	}
 	return false
 }
+
+// Targets returns the sync targets selected by the action options. An
+// empty list means all targets.
+func Targets(ctx context.Context) []string {
+	if o, ok := Value(ctx).Options.(targetser); ok {
+		return o.Targets()
+	}
+	return []string{}
+}
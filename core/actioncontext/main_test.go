@@ -0,0 +1,36 @@
+package actioncontext
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/objectactionprops"
+)
+
+type dryRunOptions struct{}
+
+func (t dryRunOptions) IsDryRun() bool { return true }
+
+func TestNew(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Hour)
+	ctx := New(parent, dryRunOptions{}, objectactionprops.Start)
+	assert.True(t, IsDryRun(ctx))
+	assert.Equal(t, objectactionprops.Start.Name, Props(ctx).Name)
+	_, ok := ctx.Deadline()
+	assert.True(t, ok, "the parent deadline is inherited")
+
+	detached := Detach(ctx)
+	cancel()
+	assert.Error(t, ctx.Err(), "the action is cancelled with its parent")
+	assert.NoError(t, detached.Err(), "the detached context is not cancelled")
+	assert.True(t, IsDryRun(detached), "the detached context keeps the action values")
+}
+
+func TestValueWithoutActionContext(t *testing.T) {
+	ctx := context.TODO()
+	assert.False(t, IsDryRun(ctx))
+	assert.False(t, IsLeader(ctx))
+	assert.Equal(t, "", To(ctx))
+}
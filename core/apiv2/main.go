@@ -0,0 +1,250 @@
+//
+// Package apiv2 translates the requests of the v2 agents clients to the
+// requests of the listeners, and their responses back, so the v2 and v3
+// agents of a cluster being migrated interoperate.
+//
+// A v2 client posts its request document to the root path of the
+// listener:
+//
+//   {"action": "daemon_status", "node": "*", "options": {...}}
+//
+// The translation routes the request to the listener path and method of
+// the action, renames the options to the listener conventions, moves the
+// query options to the url query, and sets the node selector in the
+// o-node header.
+//
+// The responses are wrapped in the v2 response document, whose non-zero
+// status denotes an error, unless the action response is raw or a
+// stream, or the listener response already is a status document.
+//
+package apiv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type (
+	// Action describes the translation of a v2 action.
+	Action struct {
+		// Method is the listener method of the action.
+		Method string
+
+		// Path is the listener path of the action. The default is
+		// "/" followed by the action name.
+		Path string
+
+		// Options maps the v2 option names to the listener option
+		// names.
+		Options map[string]string
+
+		// Query lists the listener options sent in the url query
+		// instead of the request body.
+		Query []string
+
+		// Raw disables the response wrapping, for the actions whose
+		// v2 response is the raw data.
+		Raw bool
+
+		// Epoch lists the time fields of the response data, converted
+		// from RFC3339 dates to the v2 epoch floats.
+		Epoch []string
+	}
+
+	// Actions maps the v2 action names to their translation.
+	Actions map[string]Action
+
+	// Response is the v2 response document.
+	Response struct {
+		Status int             `json:"status"`
+		Error  string          `json:"error,omitempty"`
+		Data   json.RawMessage `json:"data,omitempty"`
+	}
+
+	// request is the v2 request document.
+	request struct {
+		Action  string                 `json:"action"`
+		Node    string                 `json:"node"`
+		Options map[string]interface{} `json:"options"`
+	}
+
+	// responseWriter buffers the listener response, to translate it
+	// when the handler returns. The event streams are not buffered.
+	responseWriter struct {
+		http.ResponseWriter
+		code   int
+		buf    bytes.Buffer
+		stream bool
+	}
+)
+
+var (
+	// MaxRequestSize is the maximum size of a v2 request document.
+	MaxRequestSize int64 = 1024 * 1024
+)
+
+//
+// Handler returns the handler translating the v2 requests, posted to the
+// root path, to requests served by next. The other requests are served
+// by next unchanged.
+//
+func (t Actions) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		action, req, err := t.translate(w, r)
+		if err != nil {
+			writeResponse(w, http.StatusBadRequest, Response{Status: 1, Error: err.Error()})
+			return
+		}
+		rw := &responseWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, req)
+		if rw.stream {
+			return
+		}
+		rw.translate(action)
+	})
+}
+
+// translate returns the action and the listener request of the v2
+// request.
+func (t Actions) translate(w http.ResponseWriter, r *http.Request) (Action, *http.Request, error) {
+	var v2 request
+	body := http.MaxBytesReader(w, r.Body, MaxRequestSize)
+	if err := json.NewDecoder(body).Decode(&v2); err != nil {
+		return Action{}, nil, fmt.Errorf("invalid v2 request: %w", err)
+	}
+	action, ok := t[v2.Action]
+	if !ok {
+		return action, nil, fmt.Errorf("unsupported v2 action: %s", v2.Action)
+	}
+	options := make(map[string]interface{})
+	for k, v := range v2.Options {
+		if name, ok := action.Options[k]; ok {
+			k = name
+		}
+		options[k] = v
+	}
+	req := r.Clone(r.Context())
+	req.Method = action.Method
+	req.URL.Path = action.Path
+	if req.URL.Path == "" {
+		req.URL.Path = "/" + v2.Action
+	}
+	query := req.URL.Query()
+	for _, k := range action.Query {
+		if v, ok := options[k]; ok {
+			query.Set(k, fmt.Sprint(v))
+			delete(options, k)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
+	req.RequestURI = req.URL.RequestURI()
+	b, err := json.Marshal(options)
+	if err != nil {
+		return action, nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+	if v2.Node != "" && req.Header.Get("o-node") == "" {
+		req.Header.Set("o-node", v2.Node)
+	}
+	return action, req, nil
+}
+
+func (t *responseWriter) WriteHeader(code int) {
+	if t.code != 0 {
+		return
+	}
+	t.code = code
+	if strings.HasPrefix(t.Header().Get("Content-Type"), "text/event-stream") {
+		t.stream = true
+		t.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (t *responseWriter) Write(b []byte) (int, error) {
+	t.WriteHeader(http.StatusOK)
+	if t.stream {
+		return t.ResponseWriter.Write(b)
+	}
+	return t.buf.Write(b)
+}
+
+// Flush flushes the event streams.
+func (t *responseWriter) Flush() {
+	if !t.stream {
+		return
+	}
+	if f, ok := t.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// translate writes the buffered listener response, translated to the v2
+// conventions.
+func (t *responseWriter) translate(action Action) {
+	if t.code == 0 {
+		t.code = http.StatusOK
+	}
+	b := t.buf.Bytes()
+	var doc map[string]json.RawMessage
+	isDoc := json.Unmarshal(b, &doc) == nil
+	switch {
+	case t.code >= 400:
+		msg := strings.TrimSpace(string(b))
+		if s, ok := doc["error"]; ok {
+			_ = json.Unmarshal(s, &msg)
+		}
+		writeResponse(t.ResponseWriter, t.code, Response{Status: 1, Error: msg})
+	case action.Raw:
+		t.ResponseWriter.Header().Del("Content-Length")
+		t.ResponseWriter.WriteHeader(t.code)
+		_, _ = t.ResponseWriter.Write(b)
+	case isDoc && doc["status"] != nil:
+		// already a v2 status document
+		t.ResponseWriter.WriteHeader(t.code)
+		_, _ = t.ResponseWriter.Write(b)
+	default:
+		switch {
+		case isDoc:
+			b = toEpoch(doc, action.Epoch, b)
+		case len(b) > 0 && !json.Valid(b):
+			b, _ = json.Marshal(string(b))
+		}
+		writeResponse(t.ResponseWriter, t.code, Response{Data: b})
+	}
+}
+
+// toEpoch returns the json document with the RFC3339 time fields
+// converted to epoch floats.
+func toEpoch(doc map[string]json.RawMessage, fields []string, b []byte) []byte {
+	if len(fields) == 0 {
+		return b
+	}
+	for _, k := range fields {
+		var tm time.Time
+		if v, ok := doc[k]; !ok || json.Unmarshal(v, &tm) != nil {
+			continue
+		}
+		doc[k] = json.RawMessage(fmt.Sprintf("%.6f", float64(tm.UnixNano())/1e9))
+	}
+	if converted, err := json.Marshal(doc); err == nil {
+		return converted
+	}
+	return b
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp Response) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
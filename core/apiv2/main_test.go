@@ -0,0 +1,90 @@
+package apiv2
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	var received *http.Request
+	var receivedBody string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slot", func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		b, _ := ioutil.ReadAll(r.Body)
+		receivedBody = string(b)
+		fmt.Fprintf(w, `{"updated": %q}`, time.Unix(1600000000, 0).UTC().Format(time.RFC3339))
+	})
+	mux.HandleFunc("/raw", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"monitor": {}}`)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": 0, "info": "done"}`)
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such slot", http.StatusNotFound)
+	})
+	handler := Actions{
+		"slot_get": {
+			Method:  http.MethodGet,
+			Path:    "/slot",
+			Options: map[string]string{"slot": "nodename"},
+			Query:   []string{"nodename"},
+			Epoch:   []string{"updated"},
+		},
+		"raw":    {Method: http.MethodGet, Raw: true},
+		"status": {Method: http.MethodPost},
+		"fail":   {Method: http.MethodGet},
+	}.Handler(mux)
+
+	do := func(method, target, body string) (*httptest.ResponseRecorder, Response) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		var resp Response
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	t.Run("translate the request and the response", func(t *testing.T) {
+		w, resp := do(http.MethodPost, "/", `{"action": "slot_get", "node": "n*", "options": {"slot": "n1", "since": 1}}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, http.MethodGet, received.Method)
+		assert.Equal(t, "n1", received.URL.Query().Get("nodename"))
+		assert.Equal(t, "n*", received.Header.Get("o-node"))
+		assert.JSONEq(t, `{"since": 1}`, receivedBody)
+		assert.Equal(t, 0, resp.Status)
+		assert.JSONEq(t, `{"updated": 1600000000.000000}`, string(resp.Data))
+	})
+
+	t.Run("pass the raw and status responses", func(t *testing.T) {
+		w, _ := do(http.MethodPost, "/", `{"action": "raw"}`)
+		assert.JSONEq(t, `{"monitor": {}}`, w.Body.String())
+		w, _ = do(http.MethodPost, "/", `{"action": "status"}`)
+		assert.JSONEq(t, `{"status": 0, "info": "done"}`, w.Body.String())
+	})
+
+	t.Run("wrap the errors", func(t *testing.T) {
+		w, resp := do(http.MethodPost, "/", `{"action": "fail"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, 1, resp.Status)
+		assert.Equal(t, "no such slot", resp.Error)
+
+		w, resp = do(http.MethodPost, "/", `{"action": "unknown"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "unsupported v2 action: unknown", resp.Error)
+	})
+
+	t.Run("serve the other requests unchanged", func(t *testing.T) {
+		w, _ := do(http.MethodGet, "/raw", "")
+		assert.JSONEq(t, `{"monitor": {}}`, w.Body.String())
+	})
+}
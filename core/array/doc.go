@@ -0,0 +1,9 @@
+// Package array provides the storage array driver framework.
+//
+// The arrays are declared in array#<name> sections of the node or
+// cluster configuration. Their type keyword selects the driver
+// registered by a drivers/array<type> package, which allocates,
+// resizes and frees the disks requested by the "om array" commands and
+// by the disk resources provisioning.
+
+package array
@@ -0,0 +1,240 @@
+package array
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	// T is the base type embedded by the array drivers.
+	T struct {
+		driver string
+		name   string
+		config *xconfig.T
+	}
+
+	// Driver is the interface implemented by all array drivers.
+	Driver interface {
+		SetName(string)
+		SetDriver(string)
+		SetConfig(*xconfig.T)
+		Name() string
+		Type() string
+		Config() *xconfig.T
+	}
+
+	// Disker is implemented by the array drivers able to allocate disks.
+	Disker interface {
+		AddDisk(OptsAddDisk) (Disk, error)
+		DelDisk(OptsDelDisk) (Disk, error)
+		ResizeDisk(OptsResizeDisk) (Disk, error)
+	}
+
+	// Disk describes a disk allocated from an array.
+	Disk struct {
+		// ID is the array-side identifier of the disk.
+		ID string `json:"id"`
+
+		// Name is the array-side name of the disk.
+		Name string `json:"name"`
+
+		// DiskID is the world wide identifier the disk is seen with on
+		// the nodes it is mapped to.
+		DiskID string `json:"disk_id"`
+
+		// Size unit is B
+		Size int64 `json:"size"`
+
+		// Mappings is the list of array targets the disk is mapped to.
+		Mappings []string `json:"mappings,omitempty"`
+	}
+
+	// Disks is a list of disks, renderable as a tree.
+	Disks []Disk
+
+	// OptsAddDisk is the options of the AddDisk driver method.
+	OptsAddDisk struct {
+		Name      string   `flag:"diskname"`
+		Size      string   `flag:"disksize"`
+		DiskGroup string   `flag:"diskgroup"`
+		Mappings  []string `flag:"mapping"`
+	}
+
+	// OptsDelDisk is the options of the DelDisk driver method.
+	OptsDelDisk struct {
+		Name string `flag:"diskname"`
+		ID   string `flag:"diskid"`
+	}
+
+	// OptsResizeDisk is the options of the ResizeDisk driver method.
+	// The size can be prefixed by + or - to grow or shrink the disk by
+	// the specified amount.
+	OptsResizeDisk struct {
+		Name string `flag:"diskname"`
+		ID   string `flag:"diskid"`
+		Size string `flag:"disksize"`
+	}
+)
+
+var (
+	drivers = make(map[string]func() Driver)
+)
+
+// Register makes an array driver available to New, for the arrays
+// configured with type=<t>.
+func Register(t string, fn func() Driver) {
+	drivers[t] = fn
+}
+
+// Drivers returns the sorted names of the registered array drivers.
+func Drivers() []string {
+	l := make([]string, 0, len(drivers))
+	for s := range drivers {
+		l = append(l, s)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func sectionName(name string) string {
+	return "array#" + name
+}
+
+// Names returns the names of the arrays declared in the configuration.
+func Names(config *xconfig.T) []string {
+	l := make([]string, 0)
+	for _, s := range config.SectionStrings() {
+		if !strings.HasPrefix(s, "array#") {
+			continue
+		}
+		l = append(l, s[6:])
+	}
+	return l
+}
+
+// New returns the driver of the array <name> declared in the configuration.
+func New(name string, config *xconfig.T) (Driver, error) {
+	section := sectionName(name)
+	if !config.HasSectionString(section) {
+		return nil, fmt.Errorf("array %s is not declared in the configuration", name)
+	}
+	arrayType := config.GetString(key.New(section, "type"))
+	fn, ok := drivers[arrayType]
+	if !ok {
+		return nil, fmt.Errorf("array %s: unsupported type '%s'", name, arrayType)
+	}
+	t := fn()
+	t.SetName(name)
+	t.SetDriver(arrayType)
+	t.SetConfig(config)
+	return t, nil
+}
+
+// Name returns the name of the array, as set in the section name suffix.
+func (t T) Name() string {
+	return t.name
+}
+
+// SetName sets the name of the array.
+func (t *T) SetName(name string) {
+	t.name = name
+}
+
+// SetDriver sets the driver name of the array.
+func (t *T) SetDriver(driver string) {
+	t.driver = driver
+}
+
+// Type returns the driver name of the array.
+func (t T) Type() string {
+	return t.driver
+}
+
+// Config returns the configuration hosting the array section.
+func (t *T) Config() *xconfig.T {
+	return t.config
+}
+
+// SetConfig sets the configuration hosting the array section.
+func (t *T) SetConfig(c *xconfig.T) {
+	t.config = c
+}
+
+// Key returns the configuration key of an option of the array section.
+func (t T) Key(option string) key.T {
+	return key.New(sectionName(t.name), option)
+}
+
+// GetString returns the string value of an option of the array section.
+func (t *T) GetString(option string) string {
+	return t.config.GetString(t.Key(option))
+}
+
+// GetDuration returns the duration value of an option of the array section.
+func (t *T) GetDuration(option string) *time.Duration {
+	return t.config.GetDuration(t.Key(option))
+}
+
+// GetInt returns the integer value of an option of the array section.
+func (t *T) GetInt(option string) int {
+	return t.config.GetInt(t.Key(option))
+}
+
+//
+// ResizedSize returns the new size in bytes of a disk of current size
+// <current>, for a size specification accepting a +/- relative prefix.
+//
+func ResizedSize(current int64, s string) (int64, error) {
+	var op byte
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		op = s[0]
+		s = s[1:]
+	}
+	size, err := sizeconv.FromSize(s)
+	if err != nil {
+		return 0, err
+	}
+	switch op {
+	case '+':
+		return current + size, nil
+	case '-':
+		if size > current {
+			return 0, fmt.Errorf("can not shrink a %d bytes disk by %d bytes", current, size)
+		}
+		return current - size, nil
+	default:
+		return size, nil
+	}
+}
+
+// Render is a human renderer of a disk.
+func (t Disk) Render() string {
+	return Disks{t}.Render()
+}
+
+// Render is a human renderer of a disk list.
+func (t Disks) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText("Name").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("ID").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Disk ID").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Size").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Mappings").SetColor(rawconfig.Node.Color.Bold)
+	for _, d := range t {
+		n := tr.AddNode()
+		n.AddColumn().AddText(d.Name).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(d.ID)
+		n.AddColumn().AddText(d.DiskID)
+		n.AddColumn().AddText(sizeconv.BSizeCompact(float64(d.Size)))
+		n.AddColumn().AddText(strings.Join(d.Mappings, " "))
+	}
+	return tr.Render()
+}
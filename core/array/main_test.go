@@ -0,0 +1,31 @@
+package array
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizedSize(t *testing.T) {
+	cases := []struct {
+		current int64
+		spec    string
+		size    int64
+		err     bool
+	}{
+		{current: 1024, spec: "2k", size: 2048},
+		{current: 1024, spec: "+1k", size: 2048},
+		{current: 2048, spec: "-1k", size: 1024},
+		{current: 1024, spec: "-2k", err: true},
+		{current: 1024, spec: "foo", err: true},
+	}
+	for _, c := range cases {
+		size, err := ResizedSize(c.current, c.spec)
+		if c.err {
+			assert.Error(t, err, c.spec)
+			continue
+		}
+		assert.NoError(t, err, c.spec)
+		assert.Equal(t, c.size, size, c.spec)
+	}
+}
@@ -2,6 +2,14 @@ package client
 
 import "opensvc.com/opensvc/core/client/api"
 
+func (t T) NewDeleteKey() *api.DeleteKey {
+	return api.NewDeleteKey(t)
+}
+
+func (t T) NewDeleteObjectLock() *api.DeleteObjectLock {
+	return api.NewDeleteObjectLock(t)
+}
+
 func (t T) NewGetDaemonStats() *api.GetDaemonStats {
 	return api.NewGetDaemonStats(t)
 }
@@ -22,6 +30,10 @@ func (t T) NewGetKey() *api.GetKey {
 	return api.NewGetKey(t)
 }
 
+func (t T) NewGetNetworks() *api.GetNetworks {
+	return api.NewGetNetworks(t)
+}
+
 func (t T) NewGetNodesInfo() *api.GetNodesInfo {
 	return api.NewGetNodesInfo(t)
 }
@@ -42,10 +54,22 @@ func (t T) NewGetPools() *api.GetPools {
 	return api.NewGetPools(t)
 }
 
+func (t T) NewPostArbitrate() *api.PostArbitrate {
+	return api.NewPostArbitrate(t)
+}
+
+func (t T) NewPostJoin() *api.PostJoin {
+	return api.NewPostJoin(t)
+}
+
 func (t T) NewPostKey() *api.PostKey {
 	return api.NewPostKey(t)
 }
 
+func (t T) NewPostLeave() *api.PostLeave {
+	return api.NewPostLeave(t)
+}
+
 func (t T) NewPostNodeAction() *api.PostNodeAction {
 	return api.NewPostNodeAction(t)
 }
@@ -54,6 +78,10 @@ func (t T) NewPostNodeMonitor() *api.PostNodeMonitor {
 	return api.NewPostNodeMonitor(t)
 }
 
+func (t T) NewPostNodeScanCapabilities() *api.PostNodeScanCapabilities {
+	return api.NewPostNodeScanCapabilities(t)
+}
+
 func (t T) NewPostObjectAction() *api.PostObjectAction {
 	return api.NewPostObjectAction(t)
 }
@@ -62,6 +90,14 @@ func (t T) NewPostObjectCreate() *api.PostObjectCreate {
 	return api.NewPostObjectCreate(t)
 }
 
+func (t T) NewPostObjectDeregister() *api.PostObjectDeregister {
+	return api.NewPostObjectDeregister(t)
+}
+
+func (t T) NewPostObjectLock() *api.PostObjectLock {
+	return api.NewPostObjectLock(t)
+}
+
 func (t T) NewPostObjectMonitor() *api.PostObjectMonitor {
 	return api.NewPostObjectMonitor(t)
 }
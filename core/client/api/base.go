@@ -1,10 +1,13 @@
 package api
 
+import "context"
+
 type Base struct {
 	action string
 	method string
 	node   string
 	client Requester
+	ctx    context.Context
 }
 
 func (t Base) GetAction() string {
@@ -45,3 +48,18 @@ func (t *Base) SetNode(s string) {
 func (t *Base) SetClient(i interface{}) {
 	t.client = i.(Requester)
 }
+
+// SetContext sets the context the request is submitted with, so the
+// caller can cancel it or set a deadline.
+func (t *Base) SetContext(ctx context.Context) {
+	t.ctx = ctx
+}
+
+// Context returns the context the request is submitted with, defaulting
+// to the background context.
+func (t Base) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"opensvc.com/opensvc/core/client/request"
@@ -8,19 +9,19 @@ import (
 
 type (
 	GetStreamer interface {
-		GetStream(r request.T) (chan []byte, error)
+		GetStream(ctx context.Context, r request.T) (chan []byte, error)
 	}
 	Getter interface {
-		Get(r request.T) ([]byte, error)
+		Get(ctx context.Context, r request.T) ([]byte, error)
 	}
 	Poster interface {
-		Post(r request.T) ([]byte, error)
+		Post(ctx context.Context, r request.T) ([]byte, error)
 	}
 	Putter interface {
-		Put(r request.T) ([]byte, error)
+		Put(ctx context.Context, r request.T) ([]byte, error)
 	}
 	Deleter interface {
-		Delete(r request.T) ([]byte, error)
+		Delete(ctx context.Context, r request.T) ([]byte, error)
 	}
 
 	// Requester abstracts the requesting details of supported protocols
@@ -34,16 +35,16 @@ type (
 )
 
 // Route submits the request via a requester
-func Route(requester Requester, req request.T) ([]byte, error) {
+func Route(ctx context.Context, requester Requester, req request.T) ([]byte, error) {
 	switch req.Method {
 	case "GET":
-		return requester.Get(req)
+		return requester.Get(ctx, req)
 	case "POST":
-		return requester.Post(req)
+		return requester.Post(ctx, req)
 	case "PUT":
-		return requester.Put(req)
+		return requester.Put(ctx, req)
 	case "DELETE":
-		return requester.Delete(req)
+		return requester.Delete(ctx, req)
 	default:
 		return nil, fmt.Errorf("unsupported method: %s", req.Method)
 	}
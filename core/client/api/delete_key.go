@@ -0,0 +1,28 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// DeleteKey describes the options supported by DELETE /key
+type DeleteKey struct {
+	Base
+	Path string `json:"path"`
+	Key  string `json:"key"`
+}
+
+// NewDeleteKey allocates a DeleteKey struct and sets
+// default values to its keys.
+func NewDeleteKey(t Deleter) *DeleteKey {
+	r := &DeleteKey{}
+	r.SetClient(t)
+	r.SetAction("key")
+	r.SetMethod("DELETE")
+	return r
+}
+
+// Do removes a key from an object
+func (t DeleteKey) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
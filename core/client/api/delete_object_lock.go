@@ -0,0 +1,29 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// DeleteObjectLock describes the options of the daemon request releasing
+// the action lock of an object.
+type DeleteObjectLock struct {
+	Base
+	Path      string `json:"path"`
+	SessionID string `json:"session_id"`
+}
+
+// NewDeleteObjectLock allocates a DeleteObjectLock struct and sets
+// default values to its keys.
+func NewDeleteObjectLock(t Deleter) *DeleteObjectLock {
+	r := &DeleteObjectLock{}
+	r.SetClient(t)
+	r.SetAction("object_lock")
+	r.SetMethod("DELETE")
+	return r
+}
+
+// Do releases the lock via the agent api
+func (t DeleteObjectLock) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
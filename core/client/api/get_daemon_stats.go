@@ -30,5 +30,5 @@ func NewGetDaemonStats(t Getter) *GetDaemonStats {
 // Do fetchs the daemon statistics structure from the agent api
 func (t GetDaemonStats) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+
 	"opensvc.com/opensvc/core/client/request"
 )
 
@@ -9,6 +11,8 @@ type GetDaemonStatus struct {
 	namespace string
 	selector  string
 	relatives bool
+	sections  []string
+	ctx       context.Context
 }
 
 func (t *GetDaemonStatus) SetNamespace(s string) *GetDaemonStatus {
@@ -26,6 +30,21 @@ func (t *GetDaemonStatus) SetRelatives(s bool) *GetDaemonStatus {
 	return t
 }
 
+// SetSections limits the dataset returned by the daemon to the data
+// needed to render these sections (threads, arbitrators, nodes, objects).
+// An empty list, the default, requests the full dataset.
+func (t *GetDaemonStatus) SetSections(l []string) *GetDaemonStatus {
+	t.sections = l
+	return t
+}
+
+// SetContext sets the context the request is submitted with, so the
+// caller can cancel it or set a deadline.
+func (t *GetDaemonStatus) SetContext(ctx context.Context) *GetDaemonStatus {
+	t.ctx = ctx
+	return t
+}
+
 func (t GetDaemonStatus) Namespace() string {
 	return t.namespace
 }
@@ -38,12 +57,17 @@ func (t GetDaemonStatus) Relatives() bool {
 	return t.relatives
 }
 
+func (t GetDaemonStatus) Sections() []string {
+	return t.sections
+}
+
 func NewGetDaemonStatus(t Getter) *GetDaemonStatus {
 	options := &GetDaemonStatus{
 		client:    t,
 		namespace: "",
 		selector:  "*",
 		relatives: false,
+		ctx:       context.Background(),
 	}
 	return options
 }
@@ -55,7 +79,10 @@ func (t GetDaemonStatus) Do() ([]byte, error) {
 	req.Options["namespace"] = t.namespace
 	req.Options["selector"] = t.selector
 	req.Options["relatives"] = t.relatives
-	return t.client.Get(*req)
+	if len(t.sections) > 0 {
+		req.Options["sections"] = t.sections
+	}
+	return t.client.Get(t.ctx, *req)
 }
 
 func (t GetDaemonStatus) Get() ([]byte, error) {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/rs/zerolog/log"
@@ -14,6 +15,7 @@ type GetEvents struct {
 	namespace string
 	selector  string
 	relatives bool
+	ctx       context.Context
 }
 
 func (t *GetEvents) SetNamespace(s string) *GetEvents {
@@ -31,6 +33,13 @@ func (t *GetEvents) SetRelatives(s bool) *GetEvents {
 	return t
 }
 
+// SetContext sets the context the stream is requested with. Cancelling
+// the context closes the stream.
+func (t *GetEvents) SetContext(ctx context.Context) *GetEvents {
+	t.ctx = ctx
+	return t
+}
+
 func (t GetEvents) Namespace() string {
 	return t.namespace
 }
@@ -51,6 +60,7 @@ func NewGetEvents(t GetStreamer) *GetEvents {
 		namespace: "*",
 		selector:  "",
 		relatives: true,
+		ctx:       context.Background(),
 	}
 	return options
 }
@@ -92,7 +102,7 @@ func marshalMessages(q chan []byte, out chan event.Event) {
 
 func (t GetEvents) eventsBase() (chan []byte, error) {
 	req := t.newRequest()
-	return t.client.GetStream(*req)
+	return t.client.GetStream(t.ctx, *req)
 }
 
 func (t GetEvents) newRequest() *request.T {
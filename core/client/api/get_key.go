@@ -21,8 +21,18 @@ func NewGetKey(t Getter) *GetKey {
 	return r
 }
 
-// Do returns the decoded value of an object key
+// Do returns the api response embedding the decoded value of an object key
 func (t GetKey) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
+}
+
+// Data returns the decoded value of an object key, extracted from the
+// api response.
+func (t GetKey) Data() ([]byte, error) {
+	b, err := t.Do()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeKeyData(b)
 }
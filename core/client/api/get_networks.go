@@ -0,0 +1,43 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// GetNetworks describes the daemon networks api handler options.
+type GetNetworks struct {
+	Base
+	Server  string `json:"server"`
+	Name    string `json:"name"`
+	Verbose bool   `json:"verbose"`
+}
+
+// NewGetNetworks allocates a GetNetworks struct and sets
+// default values to its keys.
+func NewGetNetworks(t Getter) *GetNetworks {
+	r := &GetNetworks{
+		Server: "",
+	}
+	r.SetClient(t)
+	r.SetAction("networks")
+	r.SetMethod("GET")
+	return r
+}
+
+// SetName filters the response on a network name.
+func (t *GetNetworks) SetName(name string) *GetNetworks {
+	t.Name = name
+	return t
+}
+
+// SetVerbose requests the networks leases.
+func (t *GetNetworks) SetVerbose(v bool) *GetNetworks {
+	t.Verbose = v
+	return t
+}
+
+// Do fetchs the networks status from the agent api
+func (t GetNetworks) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
@@ -22,5 +22,5 @@ func NewGetNodesInfo(t Getter) *GetNodesInfo {
 // Do returns the decoded value of an object key
 func (t GetNodesInfo) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -29,5 +29,5 @@ func NewGetObjectConfig(t Getter) *GetObjectConfig {
 // Do submits the request.
 func (t GetObjectConfig) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -26,5 +26,5 @@ func NewGetObjectSelector(t Getter) *GetObjectSelector {
 // Do fetchs the daemon statistics structure from the agent api
 func (t GetObjectSelector) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
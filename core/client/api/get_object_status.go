@@ -24,5 +24,5 @@ func NewGetObjectStatus(t Getter) *GetObjectStatus {
 // Do fetchs the daemon statistics structure from the agent api
 func (t GetObjectStatus) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
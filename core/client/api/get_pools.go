@@ -7,8 +7,9 @@ import (
 // GetPools describes the daemon pools api handler options.
 type GetPools struct {
 	Base
-	Server string `json:"server"`
-	Name   string `json:"name"`
+	Server  string `json:"server"`
+	Name    string `json:"name"`
+	Verbose bool   `json:"verbose"`
 }
 
 // NewGetPools allocates a DaemonPoolsCmdConfig struct and sets
@@ -28,8 +29,13 @@ func (t *GetPools) SetName(name string) *GetPools {
 	return t
 }
 
+func (t *GetPools) SetVerbose(v bool) *GetPools {
+	t.Verbose = v
+	return t
+}
+
 // Do fetchs the daemon statistics structure from the agent api
 func (t GetPools) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
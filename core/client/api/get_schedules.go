@@ -31,5 +31,5 @@ func NewGetSchedules(t Getter) *GetSchedules {
 // Do fetchs the daemon statistics structure from the agent api
 func (t GetSchedules) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type (
+	//
+	// KeyData is the body of the GET /key responses. The json framing
+	// base64-encodes the value, so the binary values are transferred
+	// unaltered.
+	//
+	KeyData struct {
+		Data []byte `json:"data"`
+	}
+)
+
+var (
+	// KeyMaxSize is the maximum size in bytes of a key value transferred
+	// through the api.
+	KeyMaxSize = 1024 * 1024
+
+	// ErrKeyTooLarge is returned when a key value is larger than
+	// KeyMaxSize.
+	ErrKeyTooLarge = errors.New("key value too large")
+)
+
+// CheckKeySize returns ErrKeyTooLarge if the value is larger than
+// KeyMaxSize.
+func CheckKeySize(b []byte) error {
+	if len(b) > KeyMaxSize {
+		return fmt.Errorf("%w: %d bytes, max %d", ErrKeyTooLarge, len(b), KeyMaxSize)
+	}
+	return nil
+}
+
+// DecodeKeyData returns the key value of a GET /key response.
+func DecodeKeyData(b []byte) ([]byte, error) {
+	var resp KeyData
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, err
+	}
+	if err := CheckKeySize(resp.Data); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
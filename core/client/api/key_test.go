@@ -0,0 +1,30 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyData(t *testing.T) {
+	value := []byte{0, 1, 2, 0xff, '\n'}
+	b, err := json.Marshal(KeyData{Data: value})
+	require.NoError(t, err)
+	assert.Equal(t, `{"data":"AAEC/wo="}`, string(b))
+	decoded, err := DecodeKeyData(b)
+	require.NoError(t, err)
+	assert.Equal(t, value, decoded)
+
+	saved := KeyMaxSize
+	KeyMaxSize = 4
+	defer func() { KeyMaxSize = saved }()
+	_, err = DecodeKeyData(b)
+	assert.ErrorIs(t, err, ErrKeyTooLarge)
+
+	req := NewPostKey(&recorder{})
+	req.Data = value
+	_, err = req.Do()
+	assert.ErrorIs(t, err, ErrKeyTooLarge)
+}
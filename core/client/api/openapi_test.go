@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"opensvc.com/opensvc/core/client/request"
+)
+
+type (
+	// recorder is a Requester keeping the last submitted request.
+	recorder struct {
+		req request.T
+	}
+
+	openapiDoc struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]interface{} `yaml:"properties"`
+					} `yaml:"schema"`
+				} `yaml:"content"`
+			} `yaml:"requestBody"`
+		} `yaml:"paths"`
+	}
+)
+
+func (t *recorder) record(r request.T) ([]byte, error) {
+	t.req = r
+	return []byte("{}"), nil
+}
+
+func (t *recorder) Get(_ context.Context, r request.T) ([]byte, error) {
+	r.Method = "GET"
+	return t.record(r)
+}
+
+func (t *recorder) Post(_ context.Context, r request.T) ([]byte, error) {
+	r.Method = "POST"
+	return t.record(r)
+}
+
+func (t *recorder) Put(_ context.Context, r request.T) ([]byte, error) {
+	r.Method = "PUT"
+	return t.record(r)
+}
+
+func (t *recorder) Delete(_ context.Context, r request.T) ([]byte, error) {
+	r.Method = "DELETE"
+	return t.record(r)
+}
+
+func (t *recorder) GetStream(_ context.Context, r request.T) (chan []byte, error) {
+	r.Method = "GET"
+	_, err := t.record(r)
+	return nil, err
+}
+
+// TestOpenAPI verifies the openapi document describes the actions, methods
+// and options of all the api request types.
+func TestOpenAPI(t *testing.T) {
+	b, err := ioutil.ReadFile("openapi.yaml")
+	require.NoError(t, err)
+	var doc openapiDoc
+	require.NoError(t, yaml.Unmarshal(b, &doc))
+
+	c := &recorder{}
+	requests := map[string]func() interface{}{
+		"DeleteKey":        func() interface{} { r := NewDeleteKey(c); _, _ = r.Do(); return r },
+		"DeleteObjectLock": func() interface{} { r := NewDeleteObjectLock(c); _, _ = r.Do(); return r },
+		"GetDaemonStats":   func() interface{} { r := NewGetDaemonStats(c); _, _ = r.Do(); return r },
+		"GetDaemonStatus": func() interface{} {
+			_, _ = NewGetDaemonStatus(c).SetSections([]string{"nodes"}).Do()
+			return nil
+		},
+		"GetEvents": func() interface{} {
+			_, _ = NewGetEvents(c).GetRaw()
+			return nil
+		},
+		"GetKey":                   func() interface{} { r := NewGetKey(c); _, _ = r.Do(); return r },
+		"GetNetworks":              func() interface{} { r := NewGetNetworks(c); _, _ = r.Do(); return r },
+		"GetNodesInfo":             func() interface{} { r := NewGetNodesInfo(c); _, _ = r.Do(); return r },
+		"GetObjectConfig":          func() interface{} { r := NewGetObjectConfig(c); _, _ = r.Do(); return r },
+		"GetObjectSelector":        func() interface{} { r := NewGetObjectSelector(c); _, _ = r.Do(); return r },
+		"GetObjectStatus":          func() interface{} { r := NewGetObjectStatus(c); _, _ = r.Do(); return r },
+		"GetPools":                 func() interface{} { r := NewGetPools(c); _, _ = r.Do(); return r },
+		"GetSchedules":             func() interface{} { r := NewGetSchedules(c); _, _ = r.Do(); return r },
+		"PostArbitrate":            func() interface{} { r := NewPostArbitrate(c); _, _ = r.Do(); return r },
+		"PostJoin":                 func() interface{} { r := NewPostJoin(c); _, _ = r.Do(); return r },
+		"PostKey":                  func() interface{} { r := NewPostKey(c); _, _ = r.Do(); return r },
+		"PostLeave":                func() interface{} { r := NewPostLeave(c); _, _ = r.Do(); return r },
+		"PostNodeAction":           func() interface{} { r := NewPostNodeAction(c); _, _ = r.Do(); return r },
+		"PostNodeMonitor":          func() interface{} { r := NewPostNodeMonitor(c); _, _ = r.Do(); return r },
+		"PostNodeScanCapabilities": func() interface{} { r := NewPostNodeScanCapabilities(c); _, _ = r.Do(); return r },
+		"PostObjectAction":         func() interface{} { r := NewPostObjectAction(c); _, _ = r.Do(); return r },
+		"PostObjectCreate":         func() interface{} { r := NewPostObjectCreate(c); _, _ = r.Do(); return r },
+		"PostObjectDeregister":     func() interface{} { r := NewPostObjectDeregister(c); _, _ = r.Do(); return r },
+		"PostObjectLock":           func() interface{} { r := NewPostObjectLock(c); _, _ = r.Do(); return r },
+		"PostObjectMonitor":        func() interface{} { r := NewPostObjectMonitor(c); _, _ = r.Do(); return r },
+		"PostObjectStatus":         func() interface{} { r := NewPostObjectStatus(c); _, _ = r.Do(); return r },
+	}
+	for name, do := range requests {
+		t.Run(name, func(t *testing.T) {
+			r := do()
+			methods, ok := doc.Paths["/"+c.req.Action]
+			require.True(t, ok, "path /%s is not described", c.req.Action)
+			op, ok := methods[strings.ToLower(c.req.Method)]
+			require.True(t, ok, "%s /%s is not described", c.req.Method, c.req.Action)
+			props := op.RequestBody.Content["application/json"].Schema.Properties
+			for k := range c.req.Options {
+				assert.Contains(t, props, k, "%s /%s option", c.req.Method, c.req.Action)
+			}
+			for _, k := range jsonTags(r) {
+				assert.Contains(t, props, k, "%s /%s option", c.req.Method, c.req.Action)
+			}
+		})
+	}
+}
+
+// jsonTags returns the json names of the exported fields of the request
+// struct pointed by i, including the omitempty ones.
+func jsonTags(i interface{}) []string {
+	l := make([]string, 0)
+	if i == nil {
+		return l
+	}
+	v := reflect.ValueOf(i).Elem().Type()
+	for n := 0; n < v.NumField(); n++ {
+		tag := v.Field(n).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		l = append(l, name)
+	}
+	return l
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostArbitrate describes the manual split arbitration request options.
+// The daemon votes for the segment of the winner node, as an arbitrator
+// would.
+type PostArbitrate struct {
+	Base
+	Winner string `json:"winner"`
+}
+
+// NewPostArbitrate allocates a PostArbitrate struct and sets default
+// values to its keys.
+func NewPostArbitrate(t Poster) *PostArbitrate {
+	r := &PostArbitrate{}
+	r.SetClient(t)
+	r.SetMethod("POST")
+	r.SetAction("arbitrate")
+	return r
+}
+
+// Do posts the arbitration request.
+func (t PostArbitrate) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
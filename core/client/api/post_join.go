@@ -0,0 +1,29 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostJoin describes the cluster join request options. The request is
+// sent by the joining node to a seed node, which adds it to the cluster
+// nodes and returns the cluster configuration.
+type PostJoin struct {
+	Base
+	NodeName string `json:"node"`
+}
+
+// NewPostJoin allocates a PostJoin struct and sets default values to its
+// keys.
+func NewPostJoin(t Poster) *PostJoin {
+	r := &PostJoin{}
+	r.SetClient(t)
+	r.SetMethod("POST")
+	r.SetAction("join")
+	return r
+}
+
+// Do posts the join request and returns the cluster configuration.
+func (t PostJoin) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
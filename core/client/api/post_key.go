@@ -22,8 +22,12 @@ func NewPostKey(t Poster) *PostKey {
 	return r
 }
 
-// Do returns the decoded value of an object key
+// Do adds or changes the value of an object key. The values larger than
+// KeyMaxSize are refused before submission.
 func (t PostKey) Do() ([]byte, error) {
+	if err := CheckKeySize(t.Data); err != nil {
+		return nil, err
+	}
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
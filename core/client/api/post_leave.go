@@ -0,0 +1,29 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostLeave describes the cluster leave request options. The request is
+// sent by the leaving node to each peer node, which removes it from the
+// cluster nodes.
+type PostLeave struct {
+	Base
+	NodeName string `json:"node"`
+}
+
+// NewPostLeave allocates a PostLeave struct and sets default values to
+// its keys.
+func NewPostLeave(t Poster) *PostLeave {
+	r := &PostLeave{}
+	r.SetClient(t)
+	r.SetMethod("POST")
+	r.SetAction("leave")
+	return r
+}
+
+// Do posts the leave request.
+func (t PostLeave) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
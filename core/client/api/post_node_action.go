@@ -28,5 +28,5 @@ func NewPostNodeAction(t Poster) *PostNodeAction {
 // Do fetchs the daemon statistics structure from the agent api
 func (t PostNodeAction) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
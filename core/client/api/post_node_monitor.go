@@ -24,5 +24,5 @@ func NewPostNodeMonitor(t Poster) *PostNodeMonitor {
 // Do ...
 func (t PostNodeMonitor) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
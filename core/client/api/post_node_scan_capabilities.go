@@ -0,0 +1,29 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostNodeScanCapabilities describes the daemon node capabilities rescan
+// options. The rescan is needed after a package installation adds or
+// removes capabilities.
+type PostNodeScanCapabilities struct {
+	Base
+	NodeSelector string `json:"node"`
+}
+
+// NewPostNodeScanCapabilities allocates a PostNodeScanCapabilities struct
+// and sets default values to its keys.
+func NewPostNodeScanCapabilities(t Poster) *PostNodeScanCapabilities {
+	r := &PostNodeScanCapabilities{}
+	r.SetClient(t)
+	r.SetMethod("POST")
+	r.SetAction("node_scan_capabilities")
+	return r
+}
+
+// Do posts the rescan request and returns the new capabilities list.
+func (t PostNodeScanCapabilities) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
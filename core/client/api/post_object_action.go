@@ -27,5 +27,5 @@ func NewPostObjectAction(t Poster) *PostObjectAction {
 // Do fetchs the daemon statistics structure from the agent api
 func (t PostObjectAction) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -4,16 +4,38 @@ import (
 	"opensvc.com/opensvc/core/client/request"
 )
 
-// PostObjectCreate are options supported by the api handler.
-type PostObjectCreate struct {
-	Base
-	ObjectSelector string                 `json:"path,omitempty"`
-	Namespace      string                 `json:"namespace,omitempty"`
-	Template       string                 `json:"template,omitempty"`
-	Provision      bool                   `json:"provision,omitempty"`
-	Restore        bool                   `json:"restore,omitempty"`
-	Data           map[string]interface{} `json:"data,omitempty"`
-}
+type (
+	//
+	// PostObjectCreate are options supported by the api handler.
+	//
+	// The objects definitions are either the Data map of raw configurations
+	// indexed by object path, or the Config ini or json formatted
+	// configuration of the single object designated by ObjectSelector, or
+	// the Template provisioning template. The Env map sets the env section
+	// keys of the new objects.
+	//
+	PostObjectCreate struct {
+		Base
+		ObjectSelector string                 `json:"path,omitempty"`
+		Namespace      string                 `json:"namespace,omitempty"`
+		Template       string                 `json:"template,omitempty"`
+		Config         string                 `json:"config,omitempty"`
+		Env            map[string]string      `json:"env,omitempty"`
+		Provision      bool                   `json:"provision,omitempty"`
+		Restore        bool                   `json:"restore,omitempty"`
+		Data           map[string]interface{} `json:"data,omitempty"`
+	}
+
+	// ObjectCreateResult is the response of the object create api handler.
+	ObjectCreateResult struct {
+		// Created are the paths of the objects not installed before.
+		Created []string `json:"created"`
+
+		// Updated are the paths of the objects whose existing
+		// configuration was replaced.
+		Updated []string `json:"updated"`
+	}
+)
 
 // NewPostObjectCreate allocates a PostObjectCreate struct and sets
 // default values to its keys.
@@ -30,5 +52,5 @@ func NewPostObjectCreate(t Poster) *PostObjectCreate {
 // Do executes the request and returns the undecoded bytes.
 func (t PostObjectCreate) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostObjectDeregister describes the options of the daemon request
+// dropping a deleted object instance from the cluster data.
+type PostObjectDeregister struct {
+	Base
+	Path string `json:"path"`
+}
+
+// NewPostObjectDeregister allocates a PostObjectDeregister struct and sets
+// default values to its keys.
+func NewPostObjectDeregister(t Poster) *PostObjectDeregister {
+	r := &PostObjectDeregister{}
+	r.SetClient(t)
+	r.SetAction("object_deregister")
+	r.SetMethod("POST")
+	return r
+}
+
+// Do posts the deregister request to the agent api
+func (t PostObjectDeregister) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
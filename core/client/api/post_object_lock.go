@@ -0,0 +1,31 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostObjectLock describes the options of the daemon request acquiring
+// the cluster-wide action lock of an object.
+type PostObjectLock struct {
+	Base
+	Path      string `json:"path"`
+	Intent    string `json:"intent"`
+	SessionID string `json:"session_id"`
+	PID       int    `json:"pid"`
+}
+
+// NewPostObjectLock allocates a PostObjectLock struct and sets default
+// values to its keys.
+func NewPostObjectLock(t Poster) *PostObjectLock {
+	r := &PostObjectLock{}
+	r.SetClient(t)
+	r.SetAction("object_lock")
+	r.SetMethod("POST")
+	return r
+}
+
+// Do posts the lock request to the agent api
+func (t PostObjectLock) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.Context(), t.client, *req)
+}
@@ -27,5 +27,5 @@ func NewPostObjectMonitor(t Poster) *PostObjectMonitor {
 // Do ...
 func (t PostObjectMonitor) Do() ([]byte, error) {
 	req := request.NewFor(t)
-	return Route(t.client, *req)
+	return Route(t.Context(), t.client, *req)
 }
@@ -71,6 +71,11 @@ func parse(b []byte, err error) ([]byte, error) {
 			return json.Marshal(data.Data)
 		}
 	default:
+		if err == nil {
+			// a failure status without error message, like the
+			// action responses exit code: let the caller decode
+			return b, nil
+		}
 		return nil, err
 	}
 }
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	b, err := parse([]byte(`{"status": 1, "error": "e1"}`), nil)
+	assert.EqualError(t, err, "e1")
+	assert.Nil(t, b)
+
+	b, err = parse([]byte(`{"status": 0, "data": {"a": 1}}`), nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"a": 1}`, string(b))
+
+	action := []byte(`{"status": 3, "out": "o1", "err": "e1"}`)
+	b, err = parse(action, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, action, b, "the action responses with a failure exit code are not altered")
+}
@@ -1,9 +1,13 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
 	"github.com/stretchr/testify/assert"
 	"opensvc.com/opensvc/core/client/request"
-	"testing"
 )
 
 type (
@@ -13,23 +17,23 @@ type (
 	}
 )
 
-func (m mockRequest) Get(request.T) ([]byte, error) {
+func (m mockRequest) Get(context.Context, request.T) ([]byte, error) {
 	return m.doRequest()
 }
 
-func (m mockRequest) Post(request.T) ([]byte, error) {
+func (m mockRequest) Post(context.Context, request.T) ([]byte, error) {
 	return m.doRequest()
 }
 
-func (m mockRequest) Put(request.T) ([]byte, error) {
+func (m mockRequest) Put(context.Context, request.T) ([]byte, error) {
 	return m.doRequest()
 }
 
-func (m mockRequest) Delete(request.T) ([]byte, error) {
+func (m mockRequest) Delete(context.Context, request.T) ([]byte, error) {
 	return m.doRequest()
 }
 
-func (_ mockRequest) GetStream(request.T) (chan []byte, error) {
+func (_ mockRequest) GetStream(context.Context, request.T) (chan []byte, error) {
 	return nil, nil
 }
 
@@ -42,12 +46,12 @@ func TestApiMethods(t *testing.T) {
 		c := &T{}
 		cases := []struct {
 			Name   string
-			Method func() func(req request.T) ([]byte, error)
+			Method func() func(ctx context.Context, req request.T) ([]byte, error)
 		}{
-			{"Get", func() func(req request.T) ([]byte, error) { return c.Get }},
-			{"Post", func() func(req request.T) ([]byte, error) { return c.Post }},
-			{"Put", func() func(req request.T) ([]byte, error) { return c.Put }},
-			{"Delete", func() func(req request.T) ([]byte, error) { return c.Delete }},
+			{"Get", func() func(ctx context.Context, req request.T) ([]byte, error) { return c.Get }},
+			{"Post", func() func(ctx context.Context, req request.T) ([]byte, error) { return c.Post }},
+			{"Put", func() func(ctx context.Context, req request.T) ([]byte, error) { return c.Put }},
+			{"Delete", func() func(ctx context.Context, req request.T) ([]byte, error) { return c.Delete }},
 		}
 		for _, tc := range cases {
 			t.Run("method "+tc.Name, func(t *testing.T) {
@@ -67,7 +71,7 @@ func TestApiMethods(t *testing.T) {
 							result: []byte(result),
 							err:    nil,
 						}
-						b, err := tc.Method()(request.T{})
+						b, err := tc.Method()(context.Background(), request.T{})
 						assert.Equal(t, nil, err)
 						assert.NotNil(t, b)
 						assert.Equal(t, "{\"Count\":3,\"Name\":\"foo\"}", string(b))
@@ -77,3 +81,80 @@ func TestApiMethods(t *testing.T) {
 		}
 	})
 }
+
+type (
+	// flakyRequest fails the first <failures> requests, and blocks until
+	// the context is done if <block> is set.
+	flakyRequest struct {
+		mockRequest
+		failures int
+		block    bool
+		calls    *int
+	}
+)
+
+func (m flakyRequest) doRequest(ctx context.Context) ([]byte, error) {
+	*m.calls++
+	if m.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if *m.calls <= m.failures {
+		return nil, errors.New("connection refused")
+	}
+	return []byte(`{"status": 0}`), nil
+}
+
+func (m flakyRequest) Get(ctx context.Context, _ request.T) ([]byte, error) {
+	return m.doRequest(ctx)
+}
+
+func (m flakyRequest) Post(ctx context.Context, _ request.T) ([]byte, error) {
+	return m.doRequest(ctx)
+}
+
+func TestApiMethodsRetry(t *testing.T) {
+	newClient := func(failures, retries int) (*T, *int) {
+		calls := 0
+		c := &T{
+			retries:    retries,
+			retryDelay: time.Millisecond,
+			requester:  flakyRequest{failures: failures, calls: &calls},
+		}
+		return c, &calls
+	}
+	t.Run("get is not retried by default", func(t *testing.T) {
+		c, calls := newClient(1, 0)
+		_, err := c.Get(context.Background(), request.T{})
+		assert.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+	t.Run("get is retried until success", func(t *testing.T) {
+		c, calls := newClient(2, 3)
+		_, err := c.Get(context.Background(), request.T{})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, *calls)
+	})
+	t.Run("get gives up after the retries", func(t *testing.T) {
+		c, calls := newClient(5, 2)
+		_, err := c.Get(context.Background(), request.T{})
+		assert.Error(t, err)
+		assert.Equal(t, 3, *calls)
+	})
+	t.Run("post is never retried", func(t *testing.T) {
+		c, calls := newClient(1, 3)
+		_, err := c.Post(context.Background(), request.T{})
+		assert.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestApiMethodsTimeout(t *testing.T) {
+	calls := 0
+	c := &T{
+		timeout:   10 * time.Millisecond,
+		requester: flakyRequest{block: true, calls: &calls},
+	}
+	_, err := c.Post(context.Background(), request.T{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -1,41 +1,78 @@
 package client
 
 import (
+	"context"
+	"time"
+
 	"github.com/rs/zerolog/log"
 	"opensvc.com/opensvc/core/client/request"
 )
 
+type requestFunc func(context.Context, request.T) ([]byte, error)
+
 // HasRequester returns true if the client has a requester defined.
 func (t T) HasRequester() bool {
 	return t.requester != nil
 }
 
 // GetStream wraps the requester's GetStream method
-func (t T) GetStream(req request.T) (chan []byte, error) {
+func (t T) GetStream(ctx context.Context, req request.T) (chan []byte, error) {
 	log.Debug().Msgf("GETSTREAM %s via %s", req, t.requester)
-	return t.requester.GetStream(req)
+	return t.requester.GetStream(ctx, req)
 }
 
 // Get wraps the requester's Get method
-func (t T) Get(req request.T) ([]byte, error) {
+func (t T) Get(ctx context.Context, req request.T) ([]byte, error) {
 	log.Debug().Msgf("GET %s via %s", req, t.requester)
-	return parse(t.requester.Get(req))
+	return parse(t.retry(ctx, req, t.requester.Get))
 }
 
 // Post wraps the requester's Post method
-func (t T) Post(req request.T) ([]byte, error) {
+func (t T) Post(ctx context.Context, req request.T) ([]byte, error) {
 	log.Debug().Msgf("POST %s via %s", req, t.requester)
-	return parse(t.requester.Post(req))
+	return parse(t.try(ctx, req, t.requester.Post))
 }
 
 // Put wraps the requester's Put method
-func (t T) Put(req request.T) ([]byte, error) {
+func (t T) Put(ctx context.Context, req request.T) ([]byte, error) {
 	log.Debug().Msgf("PUT %s via %s", req, t.requester)
-	return parse(t.requester.Put(req))
+	return parse(t.retry(ctx, req, t.requester.Put))
 }
 
 // Delete wraps the requester's Delete method
-func (t T) Delete(req request.T) ([]byte, error) {
+func (t T) Delete(ctx context.Context, req request.T) ([]byte, error) {
 	log.Debug().Msgf("DELETE %s via %s", req, t.requester)
-	return parse(t.requester.Delete(req))
+	return parse(t.retry(ctx, req, t.requester.Delete))
+}
+
+// try submits the request once, within the client timeout.
+func (t T) try(ctx context.Context, req request.T, f requestFunc) ([]byte, error) {
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	return f(ctx, req)
+}
+
+//
+// retry submits an idempotent request, retrying on transport error up to
+// the client retries count, with an exponential backoff. The api errors
+// are not retried, as they are found in the response body.
+//
+func (t T) retry(ctx context.Context, req request.T, f requestFunc) ([]byte, error) {
+	delay := t.retryDelay
+	for attempt := 0; ; attempt++ {
+		b, err := t.try(ctx, req, f)
+		if err == nil || attempt >= t.retries || ctx.Err() != nil {
+			return b, err
+		}
+		log.Debug().Err(err).Msgf("%s: retry %d/%d in %s", req, attempt+1, t.retries, delay)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
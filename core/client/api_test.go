@@ -16,6 +16,17 @@ func TestNewGetDaemonStatusHasCustomValues(t *testing.T) {
 	assert.Equal(t, a.Namespace(), "ns1")
 }
 
+func TestNewGetDaemonStatusSections(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatalf("unexepected error during New: %v", err)
+	}
+	a := c.NewGetDaemonStatus()
+	assert.Len(t, a.Sections(), 0)
+	a.SetSections([]string{"nodes"})
+	assert.Equal(t, []string{"nodes"}, a.Sections())
+}
+
 func TestNewGetDaemonStatusHasDefaultNullNamespace(t *testing.T) {
 	c, err := New()
 	if err != nil {
@@ -2,6 +2,7 @@ package client
 
 import (
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"opensvc.com/opensvc/core/client/api"
@@ -19,16 +20,32 @@ type (
 		clientCertificate  string
 		clientKey          string
 		requester          api.Requester
+		timeout            time.Duration
+		retries            int
+		retryDelay         time.Duration
 	}
 )
 
+const (
+	// DefaultTimeout is the maximum duration of a request, unless
+	// changed by the WithTimeout option.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultRetryDelay is the delay before the first retry of a failed
+	// idempotent request. The delay doubles on each new retry.
+	DefaultRetryDelay = 200 * time.Millisecond
+)
+
 //
 // New allocates a new client configuration and returns the reference
 // so users are not tempted to use client.Config{} dereferenced, which would
 // make loadContext useless.
 //
 func New(opts ...funcopt.O) (*T, error) {
-	t := &T{}
+	t := &T{
+		timeout:    DefaultTimeout,
+		retryDelay: DefaultRetryDelay,
+	}
 	if err := funcopt.Apply(t, opts...); err != nil {
 		return nil, err
 	}
@@ -100,6 +117,31 @@ func WithKey(s string) funcopt.O {
 	})
 }
 
+//
+// WithTimeout sets the maximum duration of each request attempt. The
+// event streams are not limited. A zero duration disables the timeout.
+//
+func WithTimeout(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.timeout = d
+		return nil
+	})
+}
+
+//
+// WithRetries sets the number of times an idempotent request (GET, PUT,
+// DELETE) is retried on transport error, with an exponential backoff
+// starting at DefaultRetryDelay. Requests are not retried by default.
+//
+func WithRetries(n int) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.retries = n
+		return nil
+	})
+}
+
 // configure allocates a new requester with a requester for the server found in Config,
 // or for the server found in Context.
 func (t *T) configure() error {
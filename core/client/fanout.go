@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/client/request"
+)
+
+type (
+	// NodeResponse is the response of a node to a request fanned out to
+	// many nodes.
+	NodeResponse struct {
+		Node string
+		Data []byte
+		Err  error
+	}
+
+	// NodeResponses is the list of per-node responses of a fanned out
+	// request, sorted by node name.
+	NodeResponses []NodeResponse
+
+	// NodeRequestFunc submits a request to the agent api of <node>.
+	NodeRequestFunc func(ctx context.Context, node string) ([]byte, error)
+)
+
+//
+// FanOut calls f for each node concurrently, and returns the per-node
+// responses sorted by node name. Cancelling the context cancels the
+// pending requests.
+//
+func FanOut(ctx context.Context, nodes []string, f NodeRequestFunc) NodeResponses {
+	l := make(NodeResponses, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node string) {
+			defer wg.Done()
+			b, err := f(ctx, node)
+			l[i] = NodeResponse{Node: node, Data: b, Err: err}
+		}(i, node)
+	}
+	wg.Wait()
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Node < l[j].Node
+	})
+	return l
+}
+
+// FanOut submits the same request to each node concurrently, and returns
+// the per-node responses sorted by node name.
+func (t T) FanOut(ctx context.Context, nodes []string, req request.T) NodeResponses {
+	return FanOut(ctx, nodes, func(ctx context.Context, node string) ([]byte, error) {
+		r := req
+		r.Node = node
+		return api.Route(ctx, t, r)
+	})
+}
+
+// Failed returns the responses with an error.
+func (t NodeResponses) Failed() NodeResponses {
+	l := make(NodeResponses, 0)
+	for _, r := range t {
+		if r.Err != nil {
+			l = append(l, r)
+		}
+	}
+	return l
+}
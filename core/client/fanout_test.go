@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/client/request"
+)
+
+type nodeRequest struct {
+	mockRequest
+}
+
+func (m nodeRequest) Get(_ context.Context, r request.T) ([]byte, error) {
+	if r.Node == "n2" {
+		return nil, errors.New("connection refused")
+	}
+	return []byte(r.Node), nil
+}
+
+func TestFanOut(t *testing.T) {
+	c := &T{requester: nodeRequest{}}
+	req := request.New()
+	req.Method = "GET"
+	req.Action = "node_status"
+	rs := c.FanOut(context.Background(), []string{"n3", "n2", "n1"}, *req)
+	require.Len(t, rs, 3)
+	assert.Equal(t, NodeResponse{Node: "n1", Data: []byte("n1")}, rs[0])
+	assert.Equal(t, "n2", rs[1].Node)
+	assert.Error(t, rs[1].Err)
+	assert.Equal(t, NodeResponse{Node: "n3", Data: []byte("n3")}, rs[2])
+	assert.Equal(t, "", req.Node, "the request template is not modified")
+
+	failed := rs.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "n2", failed[0].Node)
+}
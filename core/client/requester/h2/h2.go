@@ -3,6 +3,7 @@ package reqh2
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -14,8 +15,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"opensvc.com/opensvc/core/client/request"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/waitfor"
 
 	"golang.org/x/net/http2"
 )
@@ -34,8 +37,8 @@ const (
 )
 
 var (
-	udsRetryConnect      = 10
-	udsRetryConnectDelay = 10 * time.Millisecond
+	udsRetryConnectDelay   = 10 * time.Millisecond
+	udsRetryConnectTimeout = 100 * time.Millisecond
 )
 
 func (t T) String() string {
@@ -55,25 +58,27 @@ func NewUDS(url string) (*T, error) {
 	tp := &http2.Transport{
 		AllowHTTP: true,
 		DialTLS: func(network, addr string, cfg *tls.Config) (con net.Conn, err error) {
-			i := 0
-			for {
-				i++
-				con, err = net.Dial("unix", url)
-				if err == nil {
-					return
-				}
-				if i >= udsRetryConnect {
-					return
-				}
-				if strings.Contains(err.Error(), "connect: connection refused") {
-					time.Sleep(udsRetryConnectDelay)
-					continue
+			var dialErr error
+			err = waitfor.WaitFor(context.Background(), udsRetryConnectDelay, udsRetryConnectTimeout, func() (bool, error) {
+				con, dialErr = net.Dial("unix", url)
+				switch {
+				case dialErr == nil:
+					return true, nil
+				case strings.Contains(dialErr.Error(), "connect: connection refused"):
+					// the listener may be restarting
+					return false, nil
+				default:
+					return false, dialErr
 				}
+			})
+			if errors.Is(err, waitfor.ErrTimeout) {
+				err = dialErr
 			}
+			return
 		},
 	}
 	r.URL = "http://localhost"
-	r.Client = http.Client{Transport: tp, Timeout: 30 * time.Second}
+	r.Client = http.Client{Transport: tp}
 	return r, nil
 }
 
@@ -94,10 +99,10 @@ func NewInet(url, clientCertificate, clientKey string, insecureSkipVerify bool)
 	return r, nil
 }
 
-func (t T) newRequest(method string, r request.T) (*http.Request, error) {
+func (t T) newRequest(ctx context.Context, method string, r request.T) (*http.Request, error) {
 	jsonStr, _ := json.Marshal(r.Options)
 	body := bytes.NewBuffer(jsonStr)
-	req, err := http.NewRequest(method, t.URL+"/"+r.Action, body)
+	req, err := http.NewRequestWithContext(ctx, method, t.URL+"/"+r.Action, body)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +110,8 @@ func (t T) newRequest(method string, r request.T) (*http.Request, error) {
 	return req, nil
 }
 
-func (t T) doReq(method string, r request.T) (*http.Response, error) {
-	req, err := t.newRequest(method, r)
+func (t T) doReq(ctx context.Context, method string, r request.T) (*http.Response, error) {
+	req, err := t.newRequest(ctx, method, r)
 	if err != nil {
 		return nil, err
 	}
@@ -117,8 +122,8 @@ func (t T) doReq(method string, r request.T) (*http.Response, error) {
 	return resp, nil
 }
 
-func (t T) doReqReadResponse(method string, r request.T) ([]byte, error) {
-	resp, err := t.doReq(method, r)
+func (t T) doReqReadResponse(ctx context.Context, method string, r request.T) ([]byte, error) {
+	resp, err := t.doReq(ctx, method, r)
 	if err != nil {
 		return nil, err
 	}
@@ -131,36 +136,34 @@ func (t T) doReqReadResponse(method string, r request.T) ([]byte, error) {
 }
 
 // Get implements the Get interface for the H2 protocol
-func (t T) Get(r request.T) ([]byte, error) {
-	return t.doReqReadResponse("GET", r)
+func (t T) Get(ctx context.Context, r request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "GET", r)
 }
 
 // Post implements the Post interface for the H2 protocol
-func (t T) Post(r request.T) ([]byte, error) {
-	return t.doReqReadResponse("POST", r)
+func (t T) Post(ctx context.Context, r request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "POST", r)
 }
 
 // Put implements the Put interface for the H2 protocol
-func (t T) Put(r request.T) ([]byte, error) {
-	return t.doReqReadResponse("PUT", r)
+func (t T) Put(ctx context.Context, r request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "PUT", r)
 }
 
 // Delete implements the Delete interface for the H2 protocol
-func (t T) Delete(r request.T) ([]byte, error) {
-	return t.doReqReadResponse("DELETE", r)
+func (t T) Delete(ctx context.Context, r request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "DELETE", r)
 }
 
-// GetStream returns a chan of raw json messages
-func (t T) GetStream(r request.T) (chan []byte, error) {
-	req, err := t.newRequest("GET", r)
+// GetStream returns a chan of raw json messages. Cancelling the context
+// closes the stream.
+func (t T) GetStream(ctx context.Context, r request.T) (chan []byte, error) {
+	req, err := t.newRequest(ctx, "GET", r)
 	if err != nil {
 		return nil, err
 	}
-	// override default Timeout for server side calm events
-	client := t.Client
-	client.Timeout = 0
 	req.Header.Set("Accept", "text/event-stream")
-	resp, err := client.Do(req)
+	resp, err := t.Client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -182,12 +185,18 @@ func getServerSideEvents(q chan<- []byte, resp *http.Response) error {
 		}
 
 		if len(bs) < 2 {
+			if err == io.EOF {
+				break
+			}
 			continue
 		}
 
 		spl := bytes.Split(bs, delim)
 
 		if len(spl) < 2 {
+			if err == io.EOF {
+				break
+			}
 			continue
 		}
 
@@ -3,6 +3,7 @@ package reqjsonrpc
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -38,22 +39,31 @@ func defaultUDSPath() string {
 	return filepath.FromSlash(fmt.Sprintf("%s/lsnr/lsnr.sock", rawconfig.NodeViper.GetString("paths.var")))
 }
 
-// Get implements the Get interface method for the JSONRPC api
-func (t T) doReq(method string, req request.T) (io.ReadCloser, error) {
+// doReq sends the request and returns the connection to read the
+// response from. The context deadline, if any, applies to the connection
+// reads and writes.
+func (t T) doReq(ctx context.Context, method string, req request.T) (net.Conn, error) {
 	var (
-		conn net.Conn
-		err  error
-		b    []byte
+		conn   net.Conn
+		err    error
+		b      []byte
+		dialer net.Dialer
 	)
 	if t.Inet {
-		conn, err = net.Dial("tcp", t.URL)
+		conn, err = dialer.DialContext(ctx, "tcp", t.URL)
 	} else {
-		conn, err = net.Dial("unix", t.URL)
+		conn, err = dialer.DialContext(ctx, "unix", t.URL)
 	}
 
 	if err != nil {
 		return nil, err
 	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
 	req.Method = method
 	b, err = json.Marshal(req)
 	if err != nil {
@@ -68,26 +78,45 @@ func (t T) doReq(method string, req request.T) (io.ReadCloser, error) {
 		}
 		b, err = m.Encrypt()
 		if err != nil {
+			conn.Close()
 			return nil, err
 		}
 	}
-	conn.Write(b)
-	conn.Write([]byte("\x00"))
-	if err != nil {
+	if _, err = conn.Write(append(b, '\x00')); err != nil {
 		conn.Close()
 		return nil, err
 	}
-	return conn, err
+	return conn, nil
 }
 
-func (t T) doReqReadResponse(method string, req request.T) ([]byte, error) {
+//
+// closeOnDone closes the connection when the context is done, to
+// interrupt the blocking reads. The returned function stops the watch.
+//
+func closeOnDone(ctx context.Context, conn io.Closer) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (t T) doReqReadResponse(ctx context.Context, method string, req request.T) ([]byte, error) {
 	var b []byte
-	rc, err := t.doReq(method, req)
+	rc, err := t.doReq(ctx, method, req)
 	if err != nil {
 		return b, err
 	}
 	defer rc.Close()
+	defer closeOnDone(ctx, rc)()
 	b, err = ioutil.ReadAll(rc)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		return b, err
 	}
@@ -103,33 +132,38 @@ func (t T) doReqReadResponse(method string, req request.T) ([]byte, error) {
 }
 
 // Get implements the Get interface method for the JSONRPC api
-func (t T) Get(req request.T) ([]byte, error) {
-	return t.doReqReadResponse("GET", req)
+func (t T) Get(ctx context.Context, req request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "GET", req)
 }
 
 // Post implements the Post interface method for the JSONRPC api
-func (t T) Post(req request.T) ([]byte, error) {
-	return t.doReqReadResponse("POST", req)
+func (t T) Post(ctx context.Context, req request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "POST", req)
 }
 
 // Put implements the Put interface method for the JSONRPC api
-func (t T) Put(req request.T) ([]byte, error) {
-	return t.doReqReadResponse("PUT", req)
+func (t T) Put(ctx context.Context, req request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "PUT", req)
 }
 
 // Delete implements the Delete interface method for the JSONRPC api
-func (t T) Delete(req request.T) ([]byte, error) {
-	return t.doReqReadResponse("DELETE", req)
+func (t T) Delete(ctx context.Context, req request.T) ([]byte, error) {
+	return t.doReqReadResponse(ctx, "DELETE", req)
 }
 
-// GetStream returns a chan of raw json messages
-func (t T) GetStream(req request.T) (chan []byte, error) {
+// GetStream returns a chan of raw json messages. Cancelling the context
+// closes the stream.
+func (t T) GetStream(ctx context.Context, req request.T) (chan []byte, error) {
 	q := make(chan []byte, 1000)
-	rc, err := t.doReq("GET", req)
+	rc, err := t.doReq(ctx, "GET", req)
 	if err != nil {
 		return q, err
 	}
-	go getMessages(q, rc)
+	stop := closeOnDone(ctx, rc)
+	go func() {
+		defer stop()
+		getMessages(q, rc)
+	}()
 	return q, nil
 }
 
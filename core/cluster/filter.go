@@ -0,0 +1,178 @@
+package cluster
+
+import (
+	"strings"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// Filter limits a cluster status dataset to the objects and the
+	// sections a client needs, so the daemon does not serialize the full
+	// dataset for each request.
+	Filter struct {
+		// Selector is a comma-separated list of path patterns.
+		// Empty means all objects.
+		Selector string
+
+		// Namespace is the namespace of the selected objects.
+		// Empty means all namespaces.
+		Namespace string
+
+		// Relatives adds the parents, children and slaves of the
+		// selected objects.
+		Relatives bool
+
+		// Sections limits the dataset to the data needed to render
+		// these sections (threads, arbitrators, nodes, objects).
+		// Empty means all sections.
+		Sections []string
+	}
+)
+
+// IsZero returns true if the filter selects all the objects.
+func (t Filter) IsZero() bool {
+	return (t.Selector == "" || t.Selector == "**") && t.Namespace == ""
+}
+
+// Match returns true if the object path passes the filter. The relatives
+// are not considered.
+func (t Filter) Match(s string) bool {
+	if t.IsZero() {
+		return true
+	}
+	p, err := path.Parse(s)
+	if err != nil {
+		return false
+	}
+	if t.Namespace != "" && p.Namespace != t.Namespace {
+		return false
+	}
+	if t.Selector == "" {
+		return true
+	}
+	for _, pattern := range strings.Split(t.Selector, ",") {
+		if p.Match(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSection returns true if the section data is kept.
+func (t Filter) hasSection(s string) bool {
+	if len(t.Sections) == 0 {
+		return true
+	}
+	for _, section := range t.Sections {
+		if sectionToID[section] == sectionToID[s] {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Apply returns a copy of the cluster status data limited to the objects
+// passing the filter, their relatives if requested, and the sections
+// data. The source data is not modified.
+//
+func (t Filter) Apply(data Status) Status {
+	filtered := data
+	if !t.hasSection("threads") {
+		filtered.Collector = CollectorThreadStatus{}
+		filtered.DNS = DNSThreadStatus{}
+		filtered.Scheduler = SchedulerThreadStatus{}
+		filtered.Listener = ListenerThreadStatus{}
+		filtered.Heartbeats = nil
+		filtered.Monitor.ThreadStatus = ThreadStatus{}
+		filtered.Monitor.Resumed = nil
+	}
+	selected := t.selected(data)
+	matchInstance := func(s string) bool {
+		if _, ok := selected[s]; ok {
+			return true
+		}
+		// the scaler slave instances are keyed <index>.<scaler path>
+		l := strings.SplitN(s, ".", 2)
+		_, ok := selected[l[len(l)-1]]
+		return len(l) == 2 && ok
+	}
+	filtered.Monitor.Services = make(map[string]object.AggregatedStatus)
+	if t.hasSection("objects") {
+		for p, d := range data.Monitor.Services {
+			if _, ok := selected[p]; ok {
+				filtered.Monitor.Services[p] = d
+			}
+		}
+	}
+	filtered.Monitor.Nodes = make(map[string]NodeStatus, len(data.Monitor.Nodes))
+	for nodename, nodeData := range data.Monitor.Nodes {
+		config := make(map[string]instance.Config)
+		status := make(map[string]instance.Status)
+		if t.hasSection("objects") {
+			for p, d := range nodeData.Services.Config {
+				if matchInstance(p) {
+					config[p] = d
+				}
+			}
+			for p, d := range nodeData.Services.Status {
+				if matchInstance(p) {
+					status[p] = d
+				}
+			}
+		}
+		nodeData.Services.Config = config
+		nodeData.Services.Status = status
+		if !t.hasSection("arbitrators") {
+			nodeData.Arbitrators = nil
+		}
+		if !t.hasSection("nodes") {
+			nodeData.Stats = NodeStatusStats{}
+			nodeData.Labels = nil
+			nodeData.Gen = nil
+			nodeData.ConfigGen = nil
+		}
+		filtered.Monitor.Nodes[nodename] = nodeData
+	}
+	return filtered
+}
+
+// selected returns the paths of the objects passing the filter, and of
+// their relatives if requested.
+func (t Filter) selected(data Status) map[string]interface{} {
+	m := make(map[string]interface{})
+	add := func(p string) {
+		if t.Match(p) {
+			m[p] = nil
+		}
+	}
+	for p := range data.Monitor.Services {
+		add(p)
+	}
+	for _, nodeData := range data.Monitor.Nodes {
+		for p := range nodeData.Services.Status {
+			add(p)
+		}
+	}
+	if !t.Relatives || t.IsZero() {
+		return m
+	}
+	for _, nodeData := range data.Monitor.Nodes {
+		for p, d := range nodeData.Services.Status {
+			if _, ok := m[p]; !ok {
+				continue
+			}
+			for _, l := range [][]path.Relation{d.Parents, d.Children, d.Slaves} {
+				for _, relation := range l {
+					if rp, err := relation.Path(); err == nil {
+						m[rp.String()] = nil
+					}
+				}
+			}
+		}
+	}
+	return m
+}
@@ -0,0 +1,63 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	data := Status{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"scheduler": {"delayed": [{"action": "status"}]},
+		"monitor": {
+			"state": "idle",
+			"services": {"db1": {}, "web1": {}, "lb1": {}, "test/svc/db2": {}},
+			"nodes": {"n1": {
+				"arbitrators": {"a1": {"name": "a1"}},
+				"stats": {"score": 90},
+				"services": {
+					"config": {"db1": {}, "web1": {}, "lb1": {}},
+					"status": {
+						"db1": {"children": ["web1"]},
+						"web1": {"parents": ["db1"], "children": ["lb1@n1"]},
+						"lb1": {"parents": ["web1"]}
+					}
+				}
+			}}
+		}
+	}`), &data))
+
+	filtered := Filter{}.Apply(data)
+	assert.Len(t, filtered.Monitor.Services, 4)
+	assert.Len(t, filtered.Scheduler.Delayed, 1)
+
+	filtered = Filter{Selector: "db1"}.Apply(data)
+	assert.Len(t, filtered.Monitor.Services, 1)
+	assert.Len(t, filtered.Monitor.Nodes["n1"].Services.Status, 1)
+	assert.Len(t, data.Monitor.Nodes["n1"].Services.Status, 3, "the source data is not modified")
+
+	t.Run("relatives", func(t *testing.T) {
+		filtered := Filter{Selector: "web1", Relatives: true}.Apply(data)
+		assert.Len(t, filtered.Monitor.Services, 3, "the parents and children are added")
+		assert.Contains(t, filtered.Monitor.Nodes["n1"].Services.Status, "lb1")
+		assert.NotContains(t, filtered.Monitor.Services, "test/svc/db2")
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		filtered := Filter{Sections: []string{"objects"}}.Apply(data)
+		assert.Len(t, filtered.Monitor.Services, 4)
+		assert.Empty(t, filtered.Scheduler.Delayed)
+		assert.Empty(t, filtered.Monitor.State)
+		assert.Empty(t, filtered.Monitor.Nodes["n1"].Arbitrators)
+		assert.Zero(t, filtered.Monitor.Nodes["n1"].Stats.Score)
+
+		filtered = Filter{Sections: []string{"nodes", "arbitrators"}}.Apply(data)
+		assert.Empty(t, filtered.Monitor.Services)
+		assert.Empty(t, filtered.Monitor.Nodes["n1"].Services.Status)
+		assert.Len(t, filtered.Monitor.Nodes["n1"].Arbitrators, 1)
+		assert.Equal(t, uint(90), filtered.Monitor.Nodes["n1"].Stats.Score)
+	})
+}
@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+)
+
+type (
+	// GenLag describes a node not having applied the latest status or
+	// configuration generation of a peer.
+	GenLag struct {
+		Node    string `json:"node"`
+		Peer    string `json:"peer"`
+		Kind    string `json:"kind"`
+		Applied uint64 `json:"applied"`
+		Latest  uint64 `json:"latest"`
+	}
+)
+
+const (
+	// GenStatus is the kind of the status generation lags.
+	GenStatus = "status"
+
+	// GenConfig is the kind of the configuration generation lags.
+	GenConfig = "config"
+)
+
+func (t GenLag) String() string {
+	return fmt.Sprintf("%s applied %s %s gen %d/%d", t.Node, t.Peer, t.Kind, t.Applied, t.Latest)
+}
+
+//
+// GenLags returns the generation lags of the cluster nodes on the changes
+// of the peers nodes, sorted by node and peer. A peer not reporting its
+// own generation of a kind is not verified for this kind, so the daemons
+// not reporting the configuration generations are supported.
+//
+func (t Status) GenLags(peers []string) []GenLag {
+	l := make([]GenLag, 0)
+	nodes := make([]string, 0, len(t.Monitor.Nodes))
+	for node := range t.Monitor.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		data := t.Monitor.Nodes[node]
+		for _, peer := range peers {
+			peerData, ok := t.Monitor.Nodes[peer]
+			if !ok {
+				continue
+			}
+			if lag, ok := genLag(node, peer, GenStatus, data.Gen, peerData.Gen); ok {
+				l = append(l, lag)
+			}
+			if lag, ok := genLag(node, peer, GenConfig, data.ConfigGen, peerData.ConfigGen); ok {
+				l = append(l, lag)
+			}
+		}
+	}
+	return l
+}
+
+func genLag(node, peer, kind string, applied, latest map[string]uint64) (GenLag, bool) {
+	want, ok := latest[peer]
+	if !ok {
+		return GenLag{}, false
+	}
+	got := applied[peer]
+	if got >= want {
+		return GenLag{}, false
+	}
+	return GenLag{Node: node, Peer: peer, Kind: kind, Applied: got, Latest: want}, true
+}
+
+// Converged returns true if all the cluster nodes applied the latest
+// status and configuration generations of the peers nodes.
+func (t Status) Converged(peers []string) bool {
+	return len(t.GenLags(peers)) == 0
+}
@@ -0,0 +1,36 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenLags(t *testing.T) {
+	var data Status
+	s := `{
+		"monitor": {
+			"nodes": {
+				"n1": {"gen": {"n1": 12, "n2": 7, "n3": 1}, "config_gen": {"n1": 3, "n2": 2}},
+				"n2": {"gen": {"n1": 11, "n2": 7, "n3": 1}, "config_gen": {"n1": 2, "n2": 2}},
+				"n3": {"gen": {"n1": 12, "n2": 7, "n3": 1}, "config_gen": {"n2": 2}}
+			}
+		}
+	}`
+	require.NoError(t, json.Unmarshal([]byte(s), &data))
+
+	lags := data.GenLags([]string{"n1"})
+	assert.Equal(t, []GenLag{
+		{Node: "n2", Peer: "n1", Kind: GenStatus, Applied: 11, Latest: 12},
+		{Node: "n2", Peer: "n1", Kind: GenConfig, Applied: 2, Latest: 3},
+		{Node: "n3", Peer: "n1", Kind: GenConfig, Applied: 0, Latest: 3},
+	}, lags)
+	assert.Equal(t, "n2 applied n1 status gen 11/12", lags[0].String())
+	assert.False(t, data.Converged([]string{"n1"}))
+
+	assert.True(t, data.Converged([]string{"n2"}), "all nodes applied n2 generations")
+	assert.True(t, data.Converged([]string{"n3"}), "n3 reports no config generation")
+	assert.True(t, data.Converged([]string{"n4"}), "unknown peers are ignored")
+}
@@ -5,8 +5,26 @@ import (
 	"strings"
 )
 
-// MarshalJSON transforms a cluster.Status struct into a []byte
-//func (t *Status) MarshalJSON()([]byte, error) {}
+// MarshalJSON transforms a cluster.Status struct into a []byte. The
+// heartbeats are marshaled as top-level hb#<n> keys, like UnmarshalJSON
+// expects them.
+func (t Status) MarshalJSON() ([]byte, error) {
+	type status Status
+	b, err := json.Marshal(status(t))
+	if err != nil || len(t.Heartbeats) == 0 {
+		return b, err
+	}
+	m := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	for k, v := range t.Heartbeats {
+		if m[k], err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(m)
+}
 
 // UnmarshalJSON loads a byte array into a cluster.Status struct
 func (t *Status) UnmarshalJSON(b []byte) error {
@@ -59,6 +77,7 @@ func (t *Status) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	ds.Cluster.Split = ds.DetectSplit()
 	*t = ds
 	return nil
 }
@@ -0,0 +1,73 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"opensvc.com/opensvc/core/objectactionprops"
+)
+
+var (
+	// ErrLocked is wrapped in the errors returned when an action lock is
+	// refused.
+	ErrLocked = errors.New("locked")
+)
+
+//
+// LockConflict returns an error wrapping ErrLocked if a peer of the node
+// holds a lock on the object p, or runs an action on its instance of p.
+//
+// If the node lock l is not nil, only the peer locks winning over l
+// conflict: the oldest lock wins, and the locks created at the same time
+// are won by the node whose name sorts first. So the nodes having locked
+// the same object concurrently agree on the lock owner, once they see
+// each other lock.
+//
+func (t Status) LockConflict(node, p string, l *Lock) error {
+	peers := make([]string, 0, len(t.Monitor.Nodes))
+	for peer := range t.Monitor.Nodes {
+		if peer != node {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Strings(peers)
+	for _, peer := range peers {
+		data := t.Monitor.Nodes[peer]
+		if pl, ok := data.Locks[p]; ok && (l == nil || lockWins(pl, peer, *l, node)) {
+			return fmt.Errorf("%w: %s held by node %s session %s", ErrLocked, pl.Intent, peer, pl.SessionID)
+		}
+		if s := data.Services.Status[p].Monitor.Status; objectactionprops.IsProgress(s) {
+			return fmt.Errorf("%w: action %s in progress on node %s", ErrLocked, s, peer)
+		}
+	}
+	return nil
+}
+
+func lockWins(a Lock, aNode string, b Lock, bNode string) bool {
+	at, bt := a.Created.Time(), b.Created.Time()
+	if !at.Equal(bt) {
+		return at.Before(bt)
+	}
+	return aNode < bNode
+}
+
+//
+// LockSettled returns true if the peers beating on a heartbeat applied
+// the status generation gen of the node. A lock published by the node in
+// the generation gen is then known to the peers, and the peer locks
+// created before the peers learned it are known to the node.
+//
+func (t Status) LockSettled(node string, gen uint64) bool {
+	for _, hb := range t.Heartbeats {
+		for peer, data := range hb.Peers {
+			if !data.Beating || peer == node {
+				continue
+			}
+			if t.Monitor.Nodes[peer].Gen[node] < gen {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestLockConflict(t *testing.T) {
+	var data Status
+	s := `{
+		"monitor": {
+			"nodes": {
+				"n1": {"locks": {"svc1": {"intent": "start", "session_id": "s1", "created": 100.000000000}}},
+				"n2": {"locks": {"svc1": {"intent": "stop", "session_id": "s2", "created": 100.000000000}}},
+				"n3": {"services": {"status": {
+					"svc2": {"monitor": {"status": "rolling back"}},
+					"svc3": {"monitor": {"status": "start failed"}}
+				}}}
+			}
+		}
+	}`
+	require.NoError(t, json.Unmarshal([]byte(s), &data))
+	l := data.Monitor.Nodes["n1"].Locks["svc1"]
+
+	err := data.LockConflict("n1", "svc1", nil)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.Equal(t, "locked: stop held by node n2 session s2", err.Error())
+	assert.NoError(t, data.LockConflict("n1", "svc1", &l), "same age: n1 sorts first")
+	assert.Error(t, data.LockConflict("n2", "svc1", &l), "same age: n1 wins over n2")
+
+	older := Lock{Intent: "start", SessionID: "s1", Created: timestamp.New(time.Unix(99, 0))}
+	assert.NoError(t, data.LockConflict("n3", "svc1", &older), "the oldest lock wins")
+
+	err = data.LockConflict("n1", "svc2", nil)
+	assert.Equal(t, "locked: action rolling back in progress on node n3", err.Error())
+	assert.NoError(t, data.LockConflict("n1", "svc3", nil), "a failed action is not in progress")
+	assert.NoError(t, data.LockConflict("n3", "svc2", nil), "the node own actions are not peer actions")
+}
+
+func TestLockSettled(t *testing.T) {
+	var data Status
+	s := `{
+		"monitor": {
+			"nodes": {
+				"n1": {"gen": {"n1": 5}},
+				"n2": {"gen": {"n1": 5, "n2": 3}},
+				"n3": {"gen": {"n1": 4, "n3": 3}}
+			}
+		},
+		"hb#1": {"peers": {"n2": {"beating": true}, "n3": {"beating": true}}}
+	}`
+	require.NoError(t, json.Unmarshal([]byte(s), &data))
+	assert.True(t, data.LockSettled("n1", 4))
+	assert.False(t, data.LockSettled("n1", 5), "n3 did not apply the n1 gen 5")
+
+	hb := data.Heartbeats["hb#1"]
+	ps := hb.Peers["n3"]
+	ps.Beating = false
+	hb.Peers["n3"] = ps
+	assert.True(t, data.LockSettled("n1", 5), "the stale peers are not waited for")
+}
@@ -49,6 +49,7 @@ var (
 	iconNotApplicable  = hiblack("/")
 	iconPreserved      = hiblack("?")
 	iconStandbyUp      = hiblack("x")
+	iconSpeaker        = hiblack("@")
 )
 
 type (
@@ -97,6 +98,7 @@ func (f *Frame) Render() string {
 	f.setSectionMask()
 	f.scanData()
 	f.w = tabwriter.NewTabWriter(&builder, 1, 1, 1, ' ', 0)
+	f.wSplit()
 	if f.hasSection("threads") {
 		f.wThreads()
 	}
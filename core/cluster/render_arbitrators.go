@@ -11,3 +11,30 @@ func (f Frame) wArbitrators() {
 	fmt.Fprintln(f.w, f.title("Arbitrators"))
 	fmt.Fprintln(f.w, f.info.empty)
 }
+
+// wSplit warns about a cluster split, marking the lost nodes, whatever
+// the selected sections.
+func (f Frame) wSplit() {
+	split := f.Current.Cluster.Split
+	if !split.Split {
+		return
+	}
+	lost := make(map[string]interface{})
+	for _, node := range split.Lost {
+		lost[node] = nil
+	}
+	s := bold(" split") + "\t"
+	s += red("lost") + "\t"
+	s += "arbitration required\t"
+	s += f.info.separator + "\t"
+	for _, node := range f.Current.Cluster.Nodes {
+		if _, ok := lost[node]; ok {
+			s += iconDownIssue + "\t"
+		} else {
+			s += iconUp + "\t"
+		}
+	}
+	fmt.Fprintln(f.w, f.title("Cluster"))
+	fmt.Fprintln(f.w, s)
+	fmt.Fprintln(f.w, f.info.empty)
+}
@@ -42,8 +42,14 @@ func (f Frame) sNodeSwapLine() string {
 func (f Frame) sNodeWarningsLine() string {
 	s := fmt.Sprintf("%s\t\t\t%s\t", bold("state"), f.info.separator)
 	for _, n := range f.Current.Cluster.Nodes {
+		if _, ok := f.Current.Monitor.Nodes[n]; !ok {
+			// no dataset received from this node
+			s += iconUndef + "\t"
+			continue
+		}
 		s += f.sNodeMonState(n)
 		s += f.sNodeFrozen(n)
+		s += f.sNodeSpeaker(n)
 		s += f.sNodeMonTarget(n)
 		s += "\t"
 	}
@@ -53,6 +59,10 @@ func (f Frame) sNodeWarningsLine() string {
 func (f Frame) sNodeVersionLine() string {
 	versions := set.New()
 	for _, n := range f.Current.Cluster.Nodes {
+		if _, ok := f.Current.Monitor.Nodes[n]; !ok {
+			// no dataset received from this node
+			continue
+		}
 		versions.Insert(f.sNodeVersion(n))
 	}
 	if versions.Len() == 1 {
@@ -151,13 +161,22 @@ func (f Frame) sNodeMonState(n string) string {
 
 func (f Frame) sNodeFrozen(n string) string {
 	if val, ok := f.Current.Monitor.Nodes[n]; ok {
-		if !val.Frozen.IsZero() {
+		if val.IsFrozen() {
 			return iconFrozen
 		}
 	}
 	return ""
 }
 
+func (f Frame) sNodeSpeaker(n string) string {
+	if val, ok := f.Current.Monitor.Nodes[n]; ok {
+		if val.Speaker {
+			return iconSpeaker
+		}
+	}
+	return ""
+}
+
 func (f Frame) sNodeMonTarget(n string) string {
 	if val, ok := f.Current.Monitor.Nodes[n]; ok {
 		if val.Monitor.GlobalExpect != "" {
@@ -0,0 +1,47 @@
+package cluster
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderNodes(t *testing.T) {
+	var data Status
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"cluster": {"nodes": ["n1", "n2", "n3"]},
+		"monitor": {
+			"compat": true,
+			"nodes": {
+				"n1": {
+					"agent": "v3", "speaker": true, "frozen": 1619529517,
+					"min_avail_mem": 2, "min_avail_swap": 10,
+					"monitor": {"status": "idle"},
+					"stats": {"score": 91, "load_15m": 0.51, "mem_avail": 50, "mem_total": 4096, "swap_avail": 5, "swap_total": 1024}
+				},
+				"n2": {
+					"agent": "v3",
+					"monitor": {"status": "thawing"},
+					"stats": {"score": 80, "load_15m": 2}
+				}
+			}
+		}
+	}`), &data))
+	f := Frame{
+		Current:  data,
+		Sections: []string{"nodes"},
+	}
+	lines := strings.Split(f.Render(), "\n")
+	fields := func(i int) []string {
+		return strings.Fields(lines[i])
+	}
+	assert.Equal(t, []string{"Nodes", "n1", "n2", "n3"}, fields(0))
+	assert.Equal(t, []string{"score", "|", "91", "80"}, fields(1))
+	assert.Equal(t, []string{"load15m", "|", "0.5", "2.0"}, fields(2))
+	assert.Equal(t, []string{"mem", "|", "50/98%:4g", "-"}, fields(3))
+	assert.Equal(t, []string{"swap", "|", "95/90%:1g", "-"}, fields(4))
+	assert.Equal(t, []string{"state", "|", "*@", "thawing", "?"}, fields(5))
+}
@@ -0,0 +1,99 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+// Render returns a human friendly string representation of the daemon
+// statistics, one tree branch per node, with the resource usage of each
+// daemon thread and object, and the resource driver actions call count and
+// mean duration.
+func (t Stats) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText("Name").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Cpu Time").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Mem").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Procs").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Threads").SetColor(rawconfig.Node.Color.Bold)
+	nodenames := make([]string, 0, len(t))
+	for nodename := range t {
+		nodenames = append(nodenames, nodename)
+	}
+	sort.Strings(nodenames)
+	for _, nodename := range nodenames {
+		ns := t[nodename]
+		n := tr.AddNode()
+		n.AddColumn().AddText(nodename).SetColor(rawconfig.Node.Color.Primary)
+		threads := map[string]ThreadStats{
+			"collector": ns.Collector,
+			"daemon":    ns.Daemon,
+			"dns":       ns.DNS,
+			"listener":  ns.Listener,
+			"monitor":   ns.Monitor,
+			"scheduler": ns.Scheduler,
+		}
+		for name, hb := range ns.Heartbeats {
+			threads[name] = hb
+		}
+		names := make([]string, 0, len(threads))
+		for name := range threads {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ts := threads[name]
+			c := n.AddNode()
+			c.AddColumn().AddText(name)
+			c.AddColumn().AddText(cpuTime(ts.CPU))
+			c.AddColumn().AddText(sizeconv.BSizeCompact(float64(ts.Mem.Total)))
+			c.AddColumn().AddText(fmt.Sprint(ts.Procs))
+			c.AddColumn().AddText(fmt.Sprint(ts.Threads))
+		}
+		paths := make([]string, 0, len(ns.Services))
+		for p := range ns.Services {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			obj := ns.Services[p]
+			c := n.AddNode()
+			c.AddColumn().AddText(p)
+			c.AddColumn().AddText(cpuTime(obj.CPU))
+			c.AddColumn().AddText(sizeconv.BSizeCompact(float64(obj.Mem.Total)))
+			c.AddColumn().AddText("")
+			c.AddColumn().AddText(fmt.Sprint(obj.Tasks))
+		}
+		if len(ns.Drivers) > 0 {
+			d := n.AddNode()
+			d.AddColumn().AddText("drivers")
+			for _, driver := range ns.Drivers.Drivers() {
+				actions := make([]string, 0, len(ns.Drivers[driver]))
+				for action := range ns.Drivers[driver] {
+					actions = append(actions, action)
+				}
+				sort.Strings(actions)
+				for _, action := range actions {
+					h := ns.Drivers[driver][action]
+					c := d.AddNode()
+					c.AddColumn().AddText(driver + " " + action)
+					c.AddColumn().AddText(fmt.Sprintf("%d x %.2fs", h.Count, h.Mean().Seconds()))
+				}
+			}
+		}
+	}
+	return tr.Render()
+}
+
+// cpuTime returns the cumulated cpu time in seconds.
+func cpuTime(t CPUStats) string {
+	tm := t.Time.Time()
+	if tm.Unix() < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fs", float64(tm.UnixNano())/1e9)
+}
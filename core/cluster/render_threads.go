@@ -3,6 +3,7 @@ package cluster
 import (
 	"fmt"
 
+	"opensvc.com/opensvc/core/orchestjournal"
 	"opensvc.com/opensvc/util/render/listener"
 )
 
@@ -50,6 +51,16 @@ func (f Frame) wThreadListener() string {
 	return s
 }
 
+func (f Frame) wThreadMonitorResumed(i orchestjournal.Intent) string {
+	var s string
+	s += "  resumed\t"
+	s += yellow(i.GlobalExpect) + "\t"
+	s += i.Key() + "\t"
+	s += f.info.separator + "\t"
+	s += f.info.emptyNodes
+	return s
+}
+
 func (f Frame) wThreadScheduler() string {
 	var s string
 	s += bold(" scheduler") + "\t"
@@ -100,12 +111,26 @@ func (f Frame) wThreadHeartbeat(name string, data HeartbeatThreadStatus) string
 	} else {
 		s += red("stopped") + sThreadAlerts(data.Alerts) + "\t"
 	}
-	s += "\t"
+	s += sHeartbeatRates(data.Peers) + "\t"
 	s += f.info.separator + "\t"
 	s += f.info.emptyNodes
 	return s
 }
 
+// sHeartbeatRates returns the sent and received messages rates of the
+// heartbeat, summed over its peers.
+func sHeartbeatRates(peers map[string]HeartbeatPeerStatus) string {
+	var tx, rx float64
+	for _, peer := range peers {
+		tx += peer.TxMsgRate
+		rx += peer.RxMsgRate
+	}
+	if tx == 0 && rx == 0 {
+		return ""
+	}
+	return fmt.Sprintf("tx %.1f/s rx %.1f/s", tx, rx)
+}
+
 func sThreadAlerts(data []ThreadAlert) string {
 	if len(data) > 0 {
 		return yellow("!")
@@ -123,6 +148,9 @@ func (f Frame) wThreads() {
 	}
 	fmt.Fprintln(f.w, f.wThreadListener())
 	fmt.Fprintln(f.w, f.wThreadMonitor())
+	for _, i := range f.Current.Monitor.Resumed {
+		fmt.Fprintln(f.w, f.wThreadMonitorResumed(i))
+	}
 	fmt.Fprintln(f.w, f.wThreadScheduler())
 	fmt.Fprintln(f.w, f.info.empty)
 }
@@ -0,0 +1,65 @@
+package cluster
+
+import "sort"
+
+type (
+	//
+	// SplitStatus describes the cluster membership seen through the
+	// heartbeats of the reporting node.
+	//
+	// A peer is lost when it is not beating on any heartbeat, and the
+	// heartbeats disagree on a peer beating on some heartbeats only. The
+	// cluster is split when peers are lost and the segment of the
+	// reporting node does not hold the majority of the known cluster nodes, in
+	// which case an arbitrator vote, or an operator arbitration, is
+	// required to decide which segment runs the services.
+	//
+	SplitStatus struct {
+		Split    bool     `json:"split"`
+		Segment  []string `json:"segment,omitempty"`
+		Lost     []string `json:"lost,omitempty"`
+		Disagree []string `json:"disagree,omitempty"`
+	}
+)
+
+// DetectSplit returns the cluster membership status computed from the
+// heartbeats peers status.
+func (t Status) DetectSplit() SplitStatus {
+	beating := make(map[string]int)
+	seen := make(map[string]int)
+	for _, hb := range t.Heartbeats {
+		for peer, data := range hb.Peers {
+			seen[peer]++
+			if data.Beating {
+				beating[peer]++
+			}
+		}
+	}
+	s := SplitStatus{
+		Segment:  make([]string, 0),
+		Lost:     make([]string, 0),
+		Disagree: make([]string, 0),
+	}
+	for peer, n := range seen {
+		switch beating[peer] {
+		case 0:
+			s.Lost = append(s.Lost, peer)
+		case n:
+		default:
+			s.Disagree = append(s.Disagree, peer)
+		}
+	}
+	lost := make(map[string]interface{})
+	for _, node := range s.Lost {
+		lost[node] = nil
+	}
+	for _, node := range t.Cluster.Nodes {
+		if _, ok := lost[node]; !ok {
+			s.Segment = append(s.Segment, node)
+		}
+	}
+	sort.Strings(s.Lost)
+	sort.Strings(s.Disagree)
+	s.Split = len(s.Lost) > 0 && len(t.Cluster.Nodes) > 0 && 2*len(s.Segment) <= len(t.Cluster.Nodes)
+	return s
+}
@@ -14,10 +14,11 @@ type (
 
 	// Info decribes the cluster id, name and nodes
 	// The cluster name is used as the right most part of cluster dns
-	// names.
+	// names. Split is the membership status detected from the heartbeats.
 	Info struct {
-		ID    string   `json:"id"`
-		Name  string   `json:"name"`
-		Nodes []string `json:"nodes"`
+		ID    string      `json:"id"`
+		Name  string      `json:"name"`
+		Nodes []string    `json:"nodes"`
+		Split SplitStatus `json:"split"`
 	}
 )
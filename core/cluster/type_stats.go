@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 
+	"opensvc.com/opensvc/core/driverstats"
 	"opensvc.com/opensvc/util/timestamp"
 )
 
@@ -23,6 +24,7 @@ type (
 		Monitor    ThreadStats            `json:"monitor"`
 		Heartbeats map[string]ThreadStats `json:"-"`
 		Services   map[string]ObjectStats `json:"services"`
+		Drivers    driverstats.T          `json:"drivers,omitempty"`
 	}
 
 	// ThreadStats holds a daemon thread system resource usage metrics
@@ -116,6 +118,10 @@ func (t *NodeStats) UnmarshalJSON(b []byte) error {
 			if err := json.Unmarshal(tmp, &ns.Listener); err != nil {
 				return err
 			}
+		case "drivers":
+			if err := json.Unmarshal(tmp, &ns.Drivers); err != nil {
+				return err
+			}
 		default:
 			if strings.HasPrefix(k, "hb#") {
 				var hb ThreadStats
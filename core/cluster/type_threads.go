@@ -45,8 +45,22 @@ type (
 
 	// HeartbeatPeerStatus describes the status of the communication
 	// with a specific peer node.
+	// The traffic counters are cumulated since the thread start. The raw
+	// bytes are the message sizes before compression.
 	HeartbeatPeerStatus struct {
-		Beating bool        `json:"beating"`
-		Last    timestamp.T `json:"last"`
+		Beating    bool        `json:"beating"`
+		Last       timestamp.T `json:"last"`
+		Codec      string      `json:"codec,omitempty"`
+		TxBytes    uint64      `json:"tx_bytes"`
+		TxRawBytes uint64      `json:"tx_raw_bytes"`
+		TxMsgs     uint64      `json:"tx_msgs"`
+		TxFull     uint64      `json:"tx_full"`
+		TxMsgRate  float64     `json:"tx_msg_rate"`
+		RxBytes    uint64      `json:"rx_bytes"`
+		RxRawBytes uint64      `json:"rx_raw_bytes"`
+		RxMsgs     uint64      `json:"rx_msgs"`
+		RxFull     uint64      `json:"rx_full"`
+		RxMsgRate  float64     `json:"rx_msg_rate"`
+		Resyncs    uint64      `json:"resyncs"`
 	}
 )
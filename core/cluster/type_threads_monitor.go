@@ -3,7 +3,9 @@ package cluster
 import (
 	"opensvc.com/opensvc/core/instance"
 	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/orchestjournal"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/placement"
 	"opensvc.com/opensvc/core/status"
 	"opensvc.com/opensvc/util/timestamp"
 )
@@ -19,9 +21,20 @@ type (
 		Frozen   bool                               `json:"frozen"`
 		Nodes    map[string]NodeStatus              `json:"nodes,omitempty"`
 		Services map[string]object.AggregatedStatus `json:"services,omitempty"`
+
+		// Resumed lists the orchestrations found in progress in the
+		// journal at daemon startup, and resumed.
+		Resumed []orchestjournal.Intent `json:"resumed,omitempty"`
 	}
 
 	// NodeStatus holds a node DataSet.
+	//
+	// Gen and ConfigGen are the status and cluster configuration
+	// generations of each node applied by this node. The node own entry
+	// is its latest generation, incremented on each change.
+	//
+	// Locks are the action locks held on the node, indexed by object
+	// path.
 	NodeStatus struct {
 		Agent           string                      `json:"agent"`
 		Speaker         bool                        `json:"speaker"`
@@ -31,13 +44,24 @@ type (
 		Env             string                      `json:"env"`
 		Frozen          timestamp.T                 `json:"frozen"`
 		Gen             map[string]uint64           `json:"gen"`
+		ConfigGen       map[string]uint64           `json:"config_gen,omitempty"`
 		Labels          map[string]string           `json:"labels"`
 		MinAvailMemPct  uint64                      `json:"min_avail_mem"`
 		MinAvailSwapPct uint64                      `json:"min_avail_swap"`
 		Monitor         NodeMonitor                 `json:"monitor"`
 		Services        NodeServices                `json:"services,omitempty"`
 		Stats           NodeStatusStats             `json:"stats"`
-		//Locks map[string]Lock `json:"locks"`
+		Locks           map[string]Lock             `json:"locks,omitempty"`
+	}
+
+	// Lock is an action lock held by a session of a node on an object.
+	// The lock is published in the node entry, so the peers refuse the
+	// actions conflicting with the locked one.
+	Lock struct {
+		Intent    string      `json:"intent"`
+		SessionID string      `json:"session_id"`
+		PID       int         `json:"pid"`
+		Created   timestamp.T `json:"created"`
 	}
 
 	// NodeStatusStats describes systems (cpu, mem, swap) resource usage of a node
@@ -108,5 +132,44 @@ func (t Status) GetObjectStatus(p path.T) object.Status {
 			data.Slaves[ps] = t.Monitor.Services[ps]
 		}
 	}
+	t.setPlacement(data)
 	return *data
 }
+
+// PlacementStats returns the node metrics the placement policies rank
+// the candidate nodes with.
+func (t Status) PlacementStats() placement.Stats {
+	stats := make(placement.Stats)
+	for nodename, ndata := range t.Monitor.Nodes {
+		stats[nodename] = placement.NodeStats{
+			Load15M: ndata.Stats.Load15M,
+			Score:   ndata.Stats.Score,
+		}
+	}
+	return stats
+}
+
+// setPlacement computes the object placement state and the instances
+// placement leadership, if not already provided by the daemon.
+func (t Status) setPlacement(data *object.Status) {
+	stats := t.PlacementStats()
+	if data.Object.Placement == "" {
+		data.Object.Placement = data.PlacementState(stats)
+	}
+	for _, i := range data.Instances {
+		if i.Status.Monitor.Placement != "" {
+			return
+		}
+	}
+	for _, nodename := range data.PlacementLeaders(stats) {
+		i := data.Instances[nodename]
+		i.Status.Monitor.Placement = "leader"
+		data.Instances[nodename] = i
+	}
+}
+
+// IsFrozen returns true if the node is frozen, so the daemon does not
+// orchestrate the object instances on this node.
+func (t NodeStatus) IsFrozen() bool {
+	return !t.Frozen.IsZero() && !t.Frozen.Time().IsZero()
+}
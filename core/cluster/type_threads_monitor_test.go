@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestGetObjectStatusPlacement(t *testing.T) {
+	load := func(t *testing.T, placement, avail1, avail2 string) Status {
+		var data Status
+		s := `{
+			"monitor": {
+				"nodes": {
+					"n1": {
+						"stats": {"score": 10, "load_15m": 3},
+						"services": {
+							"config": {"svc1": {"scope": ["n1", "n2"]}},
+							"status": {"svc1": {"avail": "` + avail1 + `", "overall": "up", "topology": "failover", "placement": "` + placement + `"}}
+						}
+					},
+					"n2": {
+						"stats": {"score": 90, "load_15m": 0.1},
+						"services": {
+							"config": {"svc1": {"scope": ["n1", "n2"]}},
+							"status": {"svc1": {"avail": "` + avail2 + `", "overall": "up", "topology": "failover", "placement": "` + placement + `"}}
+						}
+					}
+				}
+			}
+		}`
+		require.NoError(t, json.Unmarshal([]byte(s), &data))
+		return data
+	}
+	p, _ := path.Parse("svc1")
+	cases := []struct {
+		placement string
+		avail1    string
+		avail2    string
+		state     string
+		leader    string
+	}{
+		{"nodes order", "up", "down", "optimal", "n1"},
+		{"nodes order", "down", "up", "non-optimal", "n1"},
+		{"score", "up", "down", "non-optimal", "n2"},
+		{"load avg", "down", "up", "optimal", "n2"},
+		{"nodes order", "down", "down", "n/a", "n1"},
+		{"nodes order", "warn", "up", "optimal", "n2"},
+	}
+	for _, c := range cases {
+		t.Run(c.placement+" "+c.avail1+" "+c.avail2, func(t *testing.T) {
+			data := load(t, c.placement, c.avail1, c.avail2).GetObjectStatus(p)
+			assert.Equal(t, c.state, data.Object.Placement)
+			for nodename, i := range data.Instances {
+				if nodename == c.leader {
+					assert.Equal(t, "leader", i.Status.Monitor.Placement, nodename)
+				} else {
+					assert.Equal(t, "", i.Status.Monitor.Placement, nodename)
+				}
+			}
+		})
+	}
+}
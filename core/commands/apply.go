@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/entrypoints/apply"
+	"opensvc.com/opensvc/core/flag"
+)
+
+type (
+	// CmdApply is the cobra flag set of the apply command.
+	CmdApply struct {
+		Server    string `flag:"server"`
+		Local     bool   `flag:"local"`
+		DryRun    bool   `flag:"dry-run"`
+		Dir       string `flag:"manifests"`
+		Namespace string `flag:"applynamespace"`
+		Prune     bool   `flag:"prune"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdApply) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdApply) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply",
+		Short: "reconcile the objects with a directory of configuration manifests",
+		Long: `Reconcile the objects with a directory of configuration manifests.
+
+The manifests are ini or json formatted configuration files. The object
+path is read from the json "metadata" section if present, or derived from
+the manifest path relative to the directory:
+
+  <namespace>/<kind>/<name>.conf
+  <kind>/<name>.conf
+  <name>.conf
+
+The objects with no manifest are created, the objects whose configuration
+differs from the manifest are updated, and with --prune the objects of
+the manifests namespaces having no manifest are deleted. Use
+--to-namespace to apply all the manifests to a namespace.
+
+The change plan is printed before being applied. Use --dry-run to only
+print the plan.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdApply) run() {
+	if err := t.runErr(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func (t *CmdApply) runErr() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	a, err := apply.New(
+		apply.WithClient(c),
+		apply.WithDir(t.Dir),
+		apply.WithNamespace(t.Namespace),
+		apply.WithPrune(t.Prune),
+		apply.WithDryRun(t.DryRun),
+		apply.WithLocal(t.Local),
+	)
+	if err != nil {
+		return err
+	}
+	return a.Do()
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/array"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// ArrayAddDisk is the cobra flag set of the array add disk command.
+	ArrayAddDisk struct {
+		Global object.OptsGlobal
+		Array  string `flag:"array"`
+		array.OptsAddDisk
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *ArrayAddDisk) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *ArrayAddDisk) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disk",
+		Short: "allocate a disk from the array",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *ArrayAddDisk) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithLocal(true),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().ArrayAddDisk(t.Array, t.OptsAddDisk)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
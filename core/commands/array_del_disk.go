@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/array"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// ArrayDelDisk is the cobra flag set of the array del disk command.
+	ArrayDelDisk struct {
+		Global object.OptsGlobal
+		Array  string `flag:"array"`
+		array.OptsDelDisk
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *ArrayDelDisk) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *ArrayDelDisk) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disk",
+		Short: "free a disk allocated from the array",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *ArrayDelDisk) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithLocal(true),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().ArrayDelDisk(t.Array, t.OptsDelDisk)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// ArrayLs is the cobra flag set of the array ls command.
+	ArrayLs struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *ArrayLs) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *ArrayLs) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "list the arrays declared in the node and cluster configuration",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *ArrayLs) run() {
+	data := object.NewNode().ListArrays()
+	output.Renderer{
+		Format: t.Global.Format,
+		Color:  t.Global.Color,
+		Data:   data,
+		HumanRenderer: func() string {
+			s := ""
+			for _, e := range data {
+				s += e + "\n"
+			}
+			return s
+		},
+		Colorize: rawconfig.Node.Colorize,
+	}.Print()
+}
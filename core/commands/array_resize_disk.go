@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/array"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// ArrayResizeDisk is the cobra flag set of the array resize disk command.
+	ArrayResizeDisk struct {
+		Global object.OptsGlobal
+		Array  string `flag:"array"`
+		array.OptsResizeDisk
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *ArrayResizeDisk) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *ArrayResizeDisk) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disk",
+		Short: "grow or shrink a disk allocated from the array",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *ArrayResizeDisk) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithLocal(true),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().ArrayResizeDisk(t.Array, t.OptsResizeDisk)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+	"opensvc.com/opensvc/core/flag"
+)
+
+type (
+	// CmdClusterArbitrate is the cobra flag set of the cluster arbitrate
+	// command.
+	CmdClusterArbitrate struct {
+		Server string `flag:"server"`
+		Winner string `flag:"winner"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdClusterArbitrate) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdClusterArbitrate) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "arbitrate",
+		Short: "resolve a cluster split in favor of the segment of a node",
+		Long: `Resolve a cluster split in favor of the segment of a node.
+
+The manual alternative to the arbitrators vote, for the clusters
+configuring no arbitrator. The daemon votes for the segment of the
+--winner node: this segment keeps the quorum, and the other segments
+apply the node split_action if cluster.quorum is set.
+
+Refuse to arbitrate if an arbitrator is configured, or if the daemon
+detects no split.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdClusterArbitrate) run() {
+	err := entrypoints.ClusterArbitrate{
+		Server: t.Server,
+		Winner: t.Winner,
+	}.Do()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
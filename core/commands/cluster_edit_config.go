@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdClusterEditConfig is the cobra flag set of the cluster edit
+	// config command.
+	CmdClusterEditConfig struct {
+		CmdObjectEditConfig
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdClusterEditConfig) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.CmdObjectEditConfig)
+}
+
+func (t *CmdClusterEditConfig) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "Edit the cluster configuration",
+		Aliases: []string{"confi", "conf", "con", "co", "c", "cf", "cfg"},
+		Long: `Edit the cluster configuration.
+
+The configuration is fetched from the daemon, and the edited configuration
+is posted back to the daemon, which replicates it to the other cluster
+nodes. Use --local to edit the local cluster configuration file only, for
+example when the daemon is down.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdClusterEditConfig) do(c *client.T) error {
+	p, err := path.Parse(clusterSelector)
+	if err != nil {
+		return err
+	}
+	if t.Global.Local {
+		return t.doLocal(object.NewCcfg(p), c)
+	}
+	return t.doRemote(p, c)
+}
+
+func (t *CmdClusterEditConfig) run() {
+	c, err := client.New(client.WithURL(t.Global.Server))
+	if err != nil {
+		log.Error().Err(err).Msg("")
+		os.Exit(1)
+	}
+	if err := t.do(c); err != nil {
+		log.Error().Err(err).Msg("")
+		os.Exit(1)
+	}
+}
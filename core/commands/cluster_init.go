@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints"
+	"opensvc.com/opensvc/core/flag"
+)
+
+type (
+	// CmdClusterInit is the cobra flag set of the cluster init command.
+	CmdClusterInit struct {
+		Name   string `flag:"clustername"`
+		Secret string `flag:"clustersecret"`
+		Force  bool   `flag:"force"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdClusterInit) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdClusterInit) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init",
+		Short: "bootstrap a one-node cluster on the local node",
+		Long: `Bootstrap a one-node cluster on the local node.
+
+Write the cluster id, name, secret and the local node as the only cluster
+node in cluster.conf, and generate the listener certificate authority and
+the node certificate secrets. The other nodes can then join the cluster
+with 'om daemon join --node <this node> --secret <secret>'.
+
+Refuse to reinitialize an already initialized cluster, unless --force is
+set.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdClusterInit) run() {
+	err := entrypoints.ClusterInit{
+		Name:   t.Name,
+		Secret: t.Secret,
+		Force:  t.Force,
+	}.Do()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdClusterSet is the cobra flag set of the cluster set command.
+	CmdClusterSet struct {
+		object.OptsSet
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdClusterSet) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdClusterSet) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set",
+		Short: "set a cluster configuration key raw value",
+		Long: `Set a cluster configuration key raw value.
+
+The change is posted to the local daemon, which replicates the cluster
+configuration to the other cluster nodes. Use --local to change the local
+cluster configuration file only, for example when the daemon is down.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdClusterSet) run() {
+	objectaction.New(
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithServer(t.Global.Server),
+		objectaction.WithObjectSelector(clusterSelector),
+		objectaction.WithRemoteNodes(clusterNodeSelector(t.Global)),
+		objectaction.WithRemoteAction("set"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"kw": t.KeywordOps,
+		}),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewConfigurerFromPath(p).Set(t.OptsSet)
+		}),
+	).Do()
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdClusterUnset is the cobra flag set of the cluster set command.
+	CmdClusterUnset struct {
+		object.OptsUnset
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdClusterUnset) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdClusterUnset) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset",
+		Short: "unset a cluster configuration key",
+		Long: `Unset a cluster configuration key.
+
+The change is posted to the local daemon, which replicates the cluster
+configuration to the other cluster nodes. Use --local to change the local
+cluster configuration file only, for example when the daemon is down.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *CmdClusterUnset) run() {
+	objectaction.New(
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithServer(t.Global.Server),
+		objectaction.WithObjectSelector(clusterSelector),
+		objectaction.WithRemoteNodes(clusterNodeSelector(t.Global)),
+		objectaction.WithRemoteAction("unset"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"kw": t.Keywords,
+		}),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewConfigurerFromPath(p).Unset(t.OptsUnset)
+		}),
+	).Do()
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,12 +35,18 @@ func (t *CmdKeystoreAdd) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdKeystoreAdd) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	if keystoreUseAPI(t.Global) {
+		keystoreAPIExit(keystorePostKey(t.OptsAdd, mergedSelector))
+		return
+	}
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
 		objectaction.WithRemoteAction("add"),
@@ -47,7 +55,7 @@ func (t *CmdKeystoreAdd) run(selector *string, kind string) {
 			"from":  t.From,
 			"value": t.Value,
 		}),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return nil, object.NewFromPath(p).(object.Keystorer).Add(t.OptsAdd)
 		}),
 	).Do()
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/file"
+)
+
+//
+// keystoreUseAPI returns true if the key commands are submitted to the
+// daemon key api instead of being executed by the local objects: the
+// --local flag is not set, and a daemon is designated by --server or by
+// the client context.
+//
+func keystoreUseAPI(global object.OptsGlobal) bool {
+	if global.Local {
+		return false
+	}
+	return global.Server != "" || clientcontext.IsSet()
+}
+
+// keystoreAPIPaths returns the api client and the paths of the objects
+// selected by the daemon.
+func keystoreAPIPaths(global object.OptsGlobal, selector string) (*client.T, []path.T, error) {
+	c, err := client.New(client.WithURL(global.Server))
+	if err != nil {
+		return nil, nil, err
+	}
+	paths := object.NewSelection(selector, object.SelectionWithClient(c)).Expand()
+	if len(paths) == 0 {
+		return nil, nil, fmt.Errorf("%s: no object selected", selector)
+	}
+	return c, paths, nil
+}
+
+//
+// keystoreAPIValue returns the key value to post to the api, read from
+// the --from local regular file or from --value. The other value sources
+// are only supported by the local objects.
+//
+func keystoreAPIValue(opts object.OptsAdd) ([]byte, error) {
+	switch {
+	case opts.From == "":
+		return []byte(opts.Value), nil
+	case file.ExistsAndRegular(opts.From):
+		return file.ReadAll(opts.From)
+	default:
+		return nil, fmt.Errorf("value source %s is not supported through the api, use a local file or set --local", opts.From)
+	}
+}
+
+// keystorePostKey adds or changes the key of the api selected objects.
+func keystorePostKey(opts object.OptsAdd, selector string) error {
+	b, err := keystoreAPIValue(opts)
+	if err != nil {
+		return err
+	}
+	c, paths, err := keystoreAPIPaths(opts.Global, selector)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		req := c.NewPostKey()
+		req.Path = p.String()
+		req.Key = opts.Key
+		req.Data = b
+		if _, err := req.Do(); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// keystoreAPIExit prints the error of an api key command and exits.
+func keystoreAPIExit(err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
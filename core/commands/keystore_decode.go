@@ -1,6 +1,10 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,20 +37,47 @@ func (t *CmdKeystoreDecode) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdKeystoreDecode) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	if keystoreUseAPI(t.Global) {
+		keystoreAPIExit(t.doAPI(mergedSelector))
+		return
+	}
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
 		objectaction.WithRemoteAction("decode"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"key": t.Key,
 		}),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return object.NewFromPath(p).(object.Keystorer).Decode(t.OptsDecode)
 		}),
 	).Do()
 }
+
+// doAPI writes the key values of the api selected objects to stdout.
+func (t *CmdKeystoreDecode) doAPI(selector string) error {
+	c, paths, err := keystoreAPIPaths(t.Global, selector)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		req := c.NewGetKey()
+		req.Path = p.String()
+		req.Key = t.Key
+		b, err := req.Data()
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if _, err := os.Stdout.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
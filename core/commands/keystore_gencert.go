@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,17 +35,19 @@ func (t *CmdSecGenCert) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdSecGenCert) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
 		objectaction.WithRemoteAction("gencert"),
 		//objectaction.WithRemoteOptions(map[string]interface{}{}),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return nil, object.NewFromPath(p).(object.SecureKeystorer).GenCert(t.OptsGenCert)
 		}),
 	).Do()
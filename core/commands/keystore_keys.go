@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,19 +35,21 @@ func (t *CmdKeystoreKeys) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdKeystoreKeys) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
 		objectaction.WithRemoteAction("keys"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"match": t.Match,
 		}),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return object.NewFromPath(p).(object.Keystorer).Keys(t.OptsKeys)
 		}),
 	).Do()
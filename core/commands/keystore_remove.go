@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,20 +36,43 @@ func (t *CmdKeystoreRemove) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdKeystoreRemove) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	if keystoreUseAPI(t.Global) {
+		keystoreAPIExit(t.doAPI(mergedSelector))
+		return
+	}
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
-		objectaction.WithRemoteAction("keys"),
+		objectaction.WithRemoteAction("remove"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"key": t.Key,
 		}),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return nil, object.NewFromPath(p).(object.Keystorer).Remove(t.OptsRemove)
 		}),
 	).Do()
 }
+
+// doAPI removes the key from the api selected objects.
+func (t *CmdKeystoreRemove) doAPI(selector string) error {
+	c, paths, err := keystoreAPIPaths(t.Global, selector)
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		req := c.NewDeleteKey()
+		req.Path = p.String()
+		req.Key = t.Key
+		if _, err := req.Do(); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return nil
+}
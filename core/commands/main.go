@@ -2,9 +2,23 @@ package commands
 
 import (
 	"fmt"
+
+	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/hostname"
 )
 
-func mergeSelector(selector string, subsysSelector string, kind string, defaultSelector string) string {
+// clusterSelector is the object selector of the cluster configuration.
+const clusterSelector = "cluster"
+
+//
+// mergeSelector returns the object selector of the command: the selector
+// from argv[1], or the selector of the -s flag, or the default selector,
+// restricted to the kind and to the namespace of the --namespace flag or
+// the OSVC_NAMESPACE environment variable.
+//
+func mergeSelector(selector string, subsysSelector string, namespace string, kind string, defaultSelector string) string {
 	var s string
 	switch {
 	case selector != "":
@@ -18,5 +32,31 @@ func mergeSelector(selector string, subsysSelector string, kind string, defaultS
 	default:
 		s = defaultSelector
 	}
-	return s
+	return object.ConstrainNamespace(s, selectorNamespace(namespace))
+}
+
+// selectorNamespace returns the namespace of the --namespace flag, or
+// the OSVC_NAMESPACE environment variable value if the flag is not set.
+func selectorNamespace(namespace string) string {
+	if namespace != "" {
+		return namespace
+	}
+	return env.Namespace()
+}
+
+//
+// clusterNodeSelector returns the selector of the nodes to post a cluster
+// configuration change to. Unless --local or --node is set, the change is
+// posted to the local daemon, which replicates cluster.conf to the other
+// cluster nodes.
+//
+func clusterNodeSelector(global object.OptsGlobal) string {
+	switch {
+	case global.NodeSelector != "":
+		return global.NodeSelector
+	case global.Local, clientcontext.IsSet():
+		return ""
+	default:
+		return hostname.Hostname()
+	}
 }
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// NetworkLs is the cobra flag set of the command.
+	NetworkLs struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkLs) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkLs) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "list the cluster networks",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkLs) run() {
+	var (
+		data []string
+		err  error
+	)
+	if t.Global.Local || !clientcontext.IsSet() {
+		data = t.extractLocal()
+	} else if data, err = t.extractDaemon(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	output.Renderer{
+		Format: t.Global.Format,
+		Color:  t.Global.Color,
+		Data:   data,
+		HumanRenderer: func() string {
+			s := ""
+			for _, e := range data {
+				s += e + "\n"
+			}
+			return s
+		},
+		Colorize: rawconfig.Node.Colorize,
+	}.Print()
+}
+
+func (t *NetworkLs) extractLocal() []string {
+	l := object.NewNode().ListNetworks()
+	sort.Strings(l)
+	return l
+}
+
+func (t *NetworkLs) extractDaemon() ([]string, error) {
+	c, err := client.New(client.WithURL(t.Global.Server))
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]network.Status)
+	b, err := c.NewGetNetworks().Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal GET /networks")
+	}
+	l := make([]string, 0, len(data))
+	for name := range data {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l, nil
+}
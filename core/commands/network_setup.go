@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NetworkSetup is the cobra flag set of the command.
+	NetworkSetup struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkSetup) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkSetup) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "setup",
+		Short: "install the routes and tunnels to the peer nodes subnets",
+		Long:  "Install the tunnels and the routes to the subnets of the peer nodes, for the routed_bridge networks, so the containers addresses are reachable cluster-wide.",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkSetup) run() {
+	if err := object.NewNode().NetworkSetup(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
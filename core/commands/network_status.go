@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// NetworkStatus is the cobra flag set of the command.
+	NetworkStatus struct {
+		Global  object.OptsGlobal
+		Name    string `flag:"networkstatusname"`
+		Verbose bool   `flag:"networkstatusverbose"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NetworkStatus) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NetworkStatus) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "status",
+		Short:   "show the networks usage and setup state",
+		Aliases: []string{"statu", "stat", "sta", "st"},
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NetworkStatus) run() {
+	var (
+		data network.StatusList
+		err  error
+	)
+	if t.Global.Local || !clientcontext.IsSet() {
+		data = object.NewNode().NetworkStatus(t.Name, t.Verbose)
+	} else if data, err = t.extractDaemon(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	output.Renderer{
+		Format:   t.Global.Format,
+		Color:    t.Global.Color,
+		Data:     data,
+		Colorize: rawconfig.Node.Colorize,
+		HumanRenderer: func() string {
+			return data.Render()
+		},
+	}.Print()
+}
+
+func (t *NetworkStatus) extractDaemon() (network.StatusList, error) {
+	c, err := client.New(client.WithURL(t.Global.Server))
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]network.Status)
+	b, err := c.NewGetNetworks().SetName(t.Name).SetVerbose(t.Verbose).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal GET /networks")
+	}
+	l := make(network.StatusList, 0, len(data))
+	for name, d := range data {
+		d.Name = name
+		l = append(l, d)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l, nil
+}
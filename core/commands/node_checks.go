@@ -3,6 +3,7 @@ package commands
 import (
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
 )
@@ -33,7 +34,7 @@ func (t *CmdNodeChecks) cmd() *cobra.Command {
 }
 
 func (t *CmdNodeChecks) run() {
-	nodeaction.New(
+	if err := nodeaction.New(
 		nodeaction.WithLocal(t.Global.Local),
 		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
 		nodeaction.WithFormat(t.Global.Format),
@@ -46,5 +47,7 @@ func (t *CmdNodeChecks) run() {
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			return object.NewNode().Checks(), nil
 		}),
-	).Do()
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
 }
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/compliance"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodeComplianceCheck is the cobra flag set of the node compliance check command.
+	NodeComplianceCheck struct {
+		object.OptsNodeCompliance
+	}
+
+	// NodeComplianceFix is the cobra flag set of the node compliance fix command.
+	NodeComplianceFix struct {
+		object.OptsNodeCompliance
+	}
+
+	// NodeComplianceAuto is the cobra flag set of the node compliance auto command.
+	NodeComplianceAuto struct {
+		object.OptsNodeCompliance
+	}
+)
+
+const nodeComplianceLong = `
+
+The compliance modules are the executables installed in the
+<var>/compliance directory. Their file name can be prefixed by
+S<order>- to set their run order. A module is executed with the
+check or fix action as first argument, and returns 0 if the node is
+compliant, 1 if not, and 2 if the module does not apply to the node.
+
+The modulesets and rulesets attached to the node are read from the
+<var>/compliance.json file. The rulesets variables are exported in the
+modules environment as OSVC_COMP_<NAME>.
+
+If node.dbcompliance or node.dbopensvc is set, the results are reported
+to the collector.`
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeComplianceCheck) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodeCompliance)
+}
+
+func (t *NodeComplianceCheck) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "check",
+		Short:   "Run the check action of the compliance modules",
+		Long:    "Run the check action of the compliance modules." + nodeComplianceLong,
+		Aliases: []string{"chec", "che", "ch"},
+		Run: func(_ *cobra.Command, _ []string) {
+			runNodeCompliance(t.OptsNodeCompliance, "compliance check", object.Node.ComplianceCheck)
+		},
+	}
+}
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeComplianceFix) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodeCompliance)
+}
+
+func (t *NodeComplianceFix) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "fix",
+		Short: "Run the fix action of the non compliant compliance modules",
+		Long: "Run the check action of the compliance modules, then the fix action of the non\n" +
+			"compliant modules. With --force, the fix action is run without checking first." + nodeComplianceLong,
+		Aliases: []string{"fi"},
+		Run: func(_ *cobra.Command, _ []string) {
+			runNodeCompliance(t.OptsNodeCompliance, "compliance fix", object.Node.ComplianceFix)
+		},
+	}
+}
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeComplianceAuto) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodeCompliance)
+}
+
+func (t *NodeComplianceAuto) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "auto",
+		Short: "Check the compliance modules, and fix the autofix ones",
+		Long: "Run the check action of the compliance modules, then the fix action of the non\n" +
+			"compliant modules flagged autofix in their moduleset. This is the action run\n" +
+			"by the scheduler, as configured by compliance.schedule." + nodeComplianceLong,
+		Aliases: []string{"aut", "au"},
+		Run: func(_ *cobra.Command, _ []string) {
+			runNodeCompliance(t.OptsNodeCompliance, "compliance auto", object.Node.ComplianceAuto)
+		},
+	}
+}
+
+func runNodeCompliance(options object.OptsNodeCompliance, action string, f func(object.Node, object.OptsNodeCompliance) (compliance.Results, error)) {
+	if err := nodeaction.New(
+		nodeaction.WithLocal(options.Global.Local),
+		nodeaction.WithRemoteNodes(options.Global.NodeSelector),
+		nodeaction.WithFormat(options.Global.Format),
+		nodeaction.WithColor(options.Global.Color),
+		nodeaction.WithServer(options.Global.Server),
+		nodeaction.WithRemoteAction(action),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format":     options.Global.Format,
+			"modulesets": options.Modulesets,
+			"modules":    options.Modules,
+			"force":      options.Force,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return f(*object.NewNode(), options)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
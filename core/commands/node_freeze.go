@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/nodeselector"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+type (
+	// NodeFreeze is the cobra flag set of the node freeze command.
+	NodeFreeze struct {
+		Global object.OptsGlobal
+		Async  object.OptsAsync
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeFreeze) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NodeFreeze) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "freeze",
+		Short: "freeze the node, blocking the daemon orchestrations of its object instances",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodeFreeze) run() {
+	err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("freeze"),
+		nodeaction.WithAsyncTarget("frozen"),
+		nodeaction.WithAsyncWatch(t.Async.Watch),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return nil, object.NewNode().Freeze()
+		}),
+	).Do()
+	if err != nil {
+		exitcode.Exit(err)
+	}
+	if t.Async.Wait {
+		if err := waitNodesFrozen(t.Global.Server, t.Global.NodeSelector, t.Global.Local, true, t.Async.Time); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitcode.Exit(err)
+		}
+	}
+}
+
+//
+// waitNodesFrozen waits until the daemon reports the frozen state of the
+// nodes is the expected state, and all the cluster nodes applied the
+// latest generation of these nodes, so the orchestrations running on the
+// peers see the new frozen state. The nodes are the selected nodes, the
+// local node with --local, or all the cluster nodes for a cluster-wide
+// global expect.
+//
+func waitNodesFrozen(server, selector string, local, frozen bool, timeout time.Duration) error {
+	c, err := client.New(client.WithURL(server))
+	if err != nil {
+		return err
+	}
+	var nodes []string
+	switch {
+	case selector != "":
+		nodes = nodeselector.New(selector, nodeselector.WithServer(server)).Expand()
+	case local:
+		nodes = []string{hostname.Hostname()}
+	}
+	var lags []cluster.GenLag
+	isReached := func() (bool, error) {
+		var data cluster.Status
+		b, err := c.NewGetDaemonStatus().Do()
+		if err != nil {
+			return false, err
+		}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return false, err
+		}
+		l := nodes
+		if l == nil {
+			l = data.Cluster.Nodes
+		}
+		if len(l) == 0 {
+			for node := range data.Monitor.Nodes {
+				l = append(l, node)
+			}
+		}
+		for _, node := range l {
+			if data.Monitor.Nodes[node].IsFrozen() != frozen {
+				return false, nil
+			}
+		}
+		lags = data.GenLags(l)
+		return len(lags) == 0, nil
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		if ok, err := isReached(); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		select {
+		case <-timer.C:
+			if len(lags) > 0 {
+				return fmt.Errorf("%w waiting for the nodes to apply the frozen=%v generation: %s", exitcode.ErrTimeout, frozen, lags[0])
+			}
+			return fmt.Errorf("%w waiting for the daemon to report the nodes frozen=%v", exitcode.ErrTimeout, frozen)
+		case <-ticker.C:
+		}
+	}
+}
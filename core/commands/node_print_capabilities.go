@@ -3,6 +3,7 @@ package commands
 import (
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
 )
@@ -32,7 +33,7 @@ func (t *NodePrintCapabilities) cmd() *cobra.Command {
 }
 
 func (t *NodePrintCapabilities) run() {
-	nodeaction.New(
+	if err := nodeaction.New(
 		nodeaction.WithFormat(t.Global.Format),
 		nodeaction.WithColor(t.Global.Color),
 		nodeaction.WithServer(t.Global.Server),
@@ -47,5 +48,7 @@ func (t *NodePrintCapabilities) run() {
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			return object.NewNode().PrintCapabilities()
 		}),
-	).Do()
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
 }
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodePrintDrivers is the cobra flag set of the node print drivers command.
+	NodePrintDrivers struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodePrintDrivers) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.Global)
+}
+
+func (t *NodePrintDrivers) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drivers",
+		Short: "print the registered resource drivers",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodePrintDrivers) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("node print drivers"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Global.Format,
+		}),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().PrintDrivers()
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
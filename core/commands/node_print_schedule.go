@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodePrintSchedule is the cobra flag set of the node print schedule command.
+	NodePrintSchedule struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodePrintSchedule) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.Global)
+}
+
+func (t *NodePrintSchedule) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "schedule",
+		Short:   "print the node scheduling table",
+		Aliases: []string{"schedul", "schedu", "sched", "sche", "sch", "sc"},
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodePrintSchedule) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("node print schedule"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Global.Format,
+		}),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().PrintSchedule(), nil
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodePrintStats is the cobra flag set of the node print stats command.
+	NodePrintStats struct {
+		object.OptsNodePrintStats
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodePrintStats) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodePrintStats)
+}
+
+func (t *NodePrintStats) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "print the node resources usage samples",
+		Long: `Print the node resources usage samples stored in the <var>/stats ring
+database by the "om daemon collect-stats" collector and the "om node pushstats"
+command. The database keeps about one month of samples.`,
+		Aliases: []string{"stat", "sta"},
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodePrintStats) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("node print stats"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Global.Format,
+			"from":   t.From,
+			"to":     t.To,
+		}),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().PrintStats(t.OptsNodePrintStats)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodePushStats is the cobra flag set of the node pushstats command.
+	NodePushStats struct {
+		object.OptsNodePushStats
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodePushStats) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsNodePushStats)
+}
+
+func (t *NodePushStats) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pushstats",
+		Short: "sample the node resources usage and push the samples to the collector",
+		Long: `Sample the node resources usage, store the sample in the <var>/stats ring
+database, and push the samples not yet pushed to the collector, if
+node.dbopensvc is set. The stats groups listed in stats.disable are not
+sampled.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodePushStats) run() {
+	if err := nodeaction.New(
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithRemoteAction("pushstats"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Global.Format,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().PushStats(t.OptsNodePushStats)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
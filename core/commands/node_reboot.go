@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodeReboot is the cobra flag set of the node reboot command.
+	NodeReboot struct {
+		Global object.OptsGlobal
+		object.OptsNodeReboot
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeReboot) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NodeReboot) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reboot",
+		Short: "stop all the local object instances, then reboot the node",
+		Long: `Stop all the local object instances, then reboot the node.
+
+With --force, the node is rebooted immediately, without stopping the
+object instances nor unmounting the filesystems.`,
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodeReboot) run() {
+	if err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("reboot"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"force": t.Force,
+		}),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return nil, object.NewNode().Reboot(t.OptsNodeReboot)
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
 )
@@ -39,7 +40,7 @@ installed software to be discovered without restarting the daemon.`
 }
 
 func (t *NodeScanCapabilities) run() {
-	nodeaction.New(
+	if err := nodeaction.New(
 		nodeaction.WithLocal(t.Global.Local),
 		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
 		nodeaction.WithFormat(t.Global.Format),
@@ -52,5 +53,7 @@ func (t *NodeScanCapabilities) run() {
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			return object.NewNode().NodeScanCapabilities()
 		}),
-	).Do()
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
 }
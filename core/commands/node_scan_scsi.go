@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodeScanSCSI is the cobra flag set of the node scan scsi command.
+	NodeScanSCSI struct {
+		Global object.OptsGlobal
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeScanSCSI) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.Global)
+}
+
+func (t *NodeScanSCSI) cmd() *cobra.Command {
+	long := `Scan the scsi hosts for new logical units.
+
+Rescan the host bus adapters, reload the multipath maps and wait for the
+udev events to settle, then report the block devices that appeared.
+
+Use this after new LUNs are mapped to the node, before provisioning the
+resources using them.`
+
+	return &cobra.Command{
+		Use:   "scsi",
+		Short: "scan the scsi hosts for new logical units",
+		Long:  long,
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodeScanSCSI) run() {
+	if err := nodeaction.New(
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+		nodeaction.WithRemoteAction("scan scsi"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Global.Format,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return object.NewNode().ScanSCSI()
+		}),
+	).Do(); err != nil {
+		exitcode.Exit(err)
+	}
+}
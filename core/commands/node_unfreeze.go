@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/entrypoints/nodeaction"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// NodeUnfreeze is the cobra flag set of the node unfreeze command.
+	NodeUnfreeze struct {
+		Global object.OptsGlobal
+		Async  object.OptsAsync
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *NodeUnfreeze) Init(parent *cobra.Command) {
+	cmd := t.cmd()
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *NodeUnfreeze) cmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "unfreeze",
+		Aliases: []string{"thaw"},
+		Short:   "unfreeze the node, allowing the daemon orchestrations of its object instances",
+		Run: func(_ *cobra.Command, _ []string) {
+			t.run()
+		},
+	}
+}
+
+func (t *NodeUnfreeze) run() {
+	err := nodeaction.New(
+		nodeaction.WithFormat(t.Global.Format),
+		nodeaction.WithColor(t.Global.Color),
+		nodeaction.WithServer(t.Global.Server),
+
+		nodeaction.WithRemoteNodes(t.Global.NodeSelector),
+		nodeaction.WithRemoteAction("unfreeze"),
+		nodeaction.WithAsyncTarget("thawed"),
+		nodeaction.WithAsyncWatch(t.Async.Watch),
+
+		nodeaction.WithLocal(t.Global.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return nil, object.NewNode().Unfreeze()
+		}),
+	).Do()
+	if err != nil {
+		exitcode.Exit(err)
+	}
+	if t.Async.Wait {
+		if err := waitNodesFrozen(t.Global.Server, t.Global.NodeSelector, t.Global.Local, false, t.Async.Time); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitcode.Exit(err)
+		}
+	}
+}
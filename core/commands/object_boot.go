@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectBoot is the cobra flag set of the boot command.
+	CmdObjectBoot struct {
+		object.OptsBoot
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectBoot) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectBoot) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:    "boot",
+		Short:  "clean up the leftovers of the previous node boot and start the standby resources",
+		Hidden: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectBoot) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, t.OptsGlobal.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocal(true),
+		objectaction.WithFormat(t.OptsGlobal.Format),
+		objectaction.WithParallel(t.OptsGlobal.Parallel),
+		objectaction.WithObjectTimeout(t.OptsGlobal.ObjectTimeout),
+		objectaction.WithColor(t.OptsGlobal.Color),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewActorFromPath(p).Boot(ctx, t.OptsBoot)
+		}),
+	).Do()
+}
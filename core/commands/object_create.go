@@ -55,11 +55,14 @@ func (t *CmdObjectCreate) runErr(selector *string, kind string) error {
 	cr, err := create.New(
 		create.WithClient(c),
 		create.WithPath(p),
-		create.WithNamespace(t.Namespace),
+		create.WithNamespace(selectorNamespace(t.Global.Namespace)),
 		create.WithTemplate(t.Template),
 		create.WithConfig(t.Config),
 		create.WithKeywords(t.Keywords),
+		create.WithEnv(t.Env),
+		create.WithInteractive(t.Interactive),
 		create.WithRestore(t.Restore),
+		create.WithNodes(t.Global.NodeSelector),
 	)
 	if err != nil {
 		return err
@@ -77,7 +80,7 @@ func (t *CmdObjectCreate) parseSelector(selector *string, kind string) (path.T,
 		return p, err
 	}
 	// now we know the path is valid. Verify it is non-existing or matches only one object.
-	objectSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "**")
+	objectSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "**")
 	paths := object.NewSelection(
 		objectSelector,
 		object.SelectionWithLocal(t.Global.Local),
@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -74,24 +73,11 @@ func (t *CmdObjectEdit) doLocal(obj object.Keystorer, c *client.T) error {
 	return obj.EditKey(t.EditKey)
 }
 
-func fetchKey(p path.T, key string, c *client.T) (s []byte, err error) {
-	var (
-		b []byte
-	)
+func fetchKey(p path.T, key string, c *client.T) ([]byte, error) {
 	handle := c.NewGetKey()
 	handle.Path = p.String()
 	handle.Key = key
-	b, err = handle.Do()
-	if err != nil {
-		return []byte{}, err
-	}
-	resp := struct {
-		data []byte
-	}{}
-	if err = json.Unmarshal(b, &resp); err != nil {
-		return []byte{}, err
-	}
-	return resp.data, nil
+	return handle.Data()
 }
 
 func pushKey(p path.T, key string, fName string, c *client.T) (err error) {
@@ -149,7 +135,7 @@ func (t *CmdObjectEdit) run(selector *string, kind string) {
 		c   *client.T
 		err error
 	)
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	if c, err = client.New(client.WithURL(t.Global.Server)); err != nil {
 		log.Error().Err(err).Msg("")
 		os.Exit(1)
@@ -147,7 +147,7 @@ func (t *CmdObjectEditConfig) run(selector *string, kind string) {
 		c   *client.T
 		err error
 	)
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	if c, err = client.New(client.WithURL(t.Global.Server)); err != nil {
 		log.Error().Err(err).Msg("")
 		os.Exit(1)
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+)
+
+type (
+	// CmdObjectEnter is the cobra flag set of the enter command.
+	CmdObjectEnter struct {
+		object.OptsEnter
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectEnter) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectEnter) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enter",
+		Short: "execute an interactive shell in a container of the selected object",
+		Long:  "Execute a shell inside the container, zone or vm selected by --rid, using the container runtime. The --rid flag can be omitted if the object has a single container.",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectEnter) do(selector string) error {
+	paths := object.NewSelection(selector).Expand()
+	if len(paths) != 1 {
+		return fmt.Errorf("the enter command requires a selection of exactly one object, got %d", len(paths))
+	}
+	return object.NewActorFromPath(paths[0]).Enter(t.OptsEnter)
+}
+
+func (t *CmdObjectEnter) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	if err := t.do(mergedSelector); err != nil {
+		log.Error().Err(err).Msg("")
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -34,18 +36,20 @@ func (t *CmdObjectFreeze) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdObjectFreeze) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	objectaction.New(
 		objectaction.WithLocal(t.Global.Local),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
 		objectaction.WithColor(t.Global.Color),
 		objectaction.WithServer(t.Global.Server),
 		objectaction.WithAsyncTarget("frozen"),
 		objectaction.WithAsyncWatch(t.Async.Watch),
 		objectaction.WithRemoteNodes(t.Global.NodeSelector),
 		objectaction.WithRemoteAction("freeze"),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			return nil, object.NewActorFromPath(p).Freeze()
 		}),
 	).Do()
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+)
+
+type (
+	// CmdObjectGiveback is the cobra flag set of the giveback command.
+	CmdObjectGiveback struct {
+		Global object.OptsGlobal
+		Async  object.OptsAsync
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectGiveback) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectGiveback) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "giveback",
+		Short: "orchestrate the selected objects instances back to their placement leaders",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectGiveback) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithServer(t.Global.Server),
+		objectaction.WithAsyncTarget("placed"),
+		objectaction.WithAsyncWatch(t.Async.Watch),
+	).Do()
+}
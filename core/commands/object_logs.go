@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectLogs is the cobra flag set of the logs command.
+	CmdObjectLogs struct {
+		object.OptsLogs
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectLogs) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectLogs) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "logs",
+		Short: "Print the selected objects log entries",
+		Long: `Print the selected objects log entries, read from the object log
+file and its rotated backups, oldest first.
+
+Each action logs its entries with the session id (sid) of the command
+execution, the resource entries included, so --sid <uuid> reconstructs
+the trace of a single action.`,
+		Aliases: []string{"log", "lo"},
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectLogs) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		// the log files are local to each node
+		objectaction.WithLocal(true),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			type logser interface {
+				Logs(object.OptsLogs) (object.LogEntries, error)
+			}
+			return object.NewFromPath(p).(logser).Logs(t.OptsLogs)
+		}),
+	).Do()
+}
@@ -33,7 +33,7 @@ func (t *CmdObjectLs) cmd(kind string, selector *string) *cobra.Command {
 
 func (t *CmdObjectLs) run(selector *string, kind string) {
 	entrypoints.List{
-		ObjectSelector: mergeSelector(*selector, t.Global.ObjectSelector, kind, "**"),
+		ObjectSelector: mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "**"),
 		Format:         t.Global.Format,
 		Color:          t.Global.Color,
 		Local:          t.Global.Local,
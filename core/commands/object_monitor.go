@@ -39,7 +39,7 @@ func (t *CmdObjectMonitor) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdObjectMonitor) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	cli, err := client.New(client.WithURL(t.Global.Server))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -50,15 +50,21 @@ func (t *CmdObjectMonitor) run(selector *string, kind string) {
 	m.SetColor(t.Global.Color)
 	m.SetFormat(t.Global.Format)
 	m.SetSections([]string{"objects"})
+	m.SetSelector(mergedSelector)
+	m.SetNamespace(selectorNamespace(t.Global.Namespace))
 
 	if t.Watch {
 		getter := cli.NewGetEvents().SetSelector(mergedSelector)
+		m.SetInput(os.Stdin)
+		m.SetStatusGetter(cli.NewGetDaemonStatus().SetSelector(mergedSelector))
 		if err := m.DoWatch(getter, os.Stdout); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 	} else {
-		getter := cli.NewGetDaemonStatus().SetSelector(mergedSelector)
+		getter := cli.NewGetDaemonStatus().
+			SetSelector(mergedSelector).
+			SetSections([]string{"objects"})
 		if err := m.Do(getter, os.Stdout); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog/log"
@@ -37,6 +38,20 @@ func (t *CmdObjectPrintConfig) cmd(kind string, selector *string) *cobra.Command
 	return &cobra.Command{
 		Use:     "config",
 		Short:   "Print selected object and instance configuration",
+		Long: `Print selected object and instance configuration.
+
+The --format ini output is uncolored and the --format json output keeps
+the sections and keys order and the section comments, so both can be fed
+back to the create --config command to convert a configuration from one
+format to the other. The --format flat output prints one
+<section>.<option> = <value> line per keyword.
+
+With --eval, the scoped keywords are replaced by their value on the local
+node, or on the node set by --impersonate, and the references are
+dereferenced.
+
+With --rev <n>, the n-th previous configuration generation is read from the
+local configuration history.`,
 		Aliases: []string{"confi", "conf", "con", "co", "c", "cf", "cfg"},
 		Run: func(cmd *cobra.Command, args []string) {
 			t.run(selector, kind)
@@ -63,6 +78,9 @@ func (t *CmdObjectPrintConfig) extract(selector string, c *client.T) (result, er
 }
 
 func (t *CmdObjectPrintConfig) extractOne(p path.T, c *client.T) (rawconfig.T, error) {
+	if t.Global.Local || t.Revision > 0 {
+		return t.extractLocal(p)
+	}
 	if data, err := t.extractFromDaemon(p, c); err == nil {
 		return data, nil
 	}
@@ -74,11 +92,17 @@ func (t *CmdObjectPrintConfig) extractOne(p path.T, c *client.T) (rawconfig.T, e
 
 func (t *CmdObjectPrintConfig) extractLocal(p path.T) (rawconfig.T, error) {
 	obj := object.NewConfigurerFromPath(p)
-	c := obj.Config()
-	if c == nil {
+	if obj.Config() == nil {
 		return rawconfig.T{}, fmt.Errorf("path %s: no configuration", p)
 	}
-	return c.Raw(), nil
+	type printConfiger interface {
+		PrintConfig(object.OptsPrintConfig) (rawconfig.T, error)
+	}
+	i, ok := obj.(printConfiger)
+	if !ok {
+		return rawconfig.T{}, fmt.Errorf("path %s: print config not supported", p)
+	}
+	return i.PrintConfig(t.OptsPrintConfig)
 }
 
 func (t *CmdObjectPrintConfig) extractFromDaemon(p path.T, c *client.T) (rawconfig.T, error) {
@@ -128,7 +152,7 @@ func (t *CmdObjectPrintConfig) run(selector *string, kind string) {
 		data result
 		err  error
 	)
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	if c, err = client.New(client.WithURL(t.Global.Server)); err != nil {
 		log.Error().Err(err).Msg("")
 		os.Exit(1)
@@ -141,6 +165,13 @@ func (t *CmdObjectPrintConfig) run(selector *string, kind string) {
 		fmt.Fprintln(os.Stderr, "no match")
 		os.Exit(1)
 	}
+	if t.Global.Format == "ini" {
+		if err := t.printIni(*selector, data); err != nil {
+			log.Error().Err(err).Msg("")
+			os.Exit(1)
+		}
+		return
+	}
 	var render func() string
 	if _, err := path.Parse(*selector); err == nil {
 		render = func() string {
@@ -174,3 +205,33 @@ func (t *CmdObjectPrintConfig) run(selector *string, kind string) {
 		}.Print()
 	}
 }
+
+// printIni prints the configurations in the uncolored ini format, so the
+// output can be fed back to a create or edit config command.
+func (t *CmdObjectPrintConfig) printIni(selector string, data result) error {
+	if _, err := path.Parse(selector); err == nil {
+		s, err := data[selector].Ini()
+		if err != nil {
+			return err
+		}
+		fmt.Print(s)
+		return nil
+	}
+	paths := make([]string, 0, len(data))
+	for p := range data {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		s, err := data[p].Ini()
+		if err != nil {
+			return err
+		}
+		fmt.Println("#")
+		fmt.Println("# path: " + p)
+		fmt.Println("#")
+		fmt.Println(strings.Repeat("#", 78))
+		fmt.Print(s)
+	}
+	return nil
+}
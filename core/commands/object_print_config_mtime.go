@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -35,16 +37,18 @@ func (t *CmdObjectPrintConfigMtime) cmd(kind string, selector *string) *cobra.Co
 }
 
 func (t *CmdObjectPrintConfigMtime) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, t.OptsGlobal.Namespace, kind, "")
 	objectaction.New(
 		objectaction.LocalFirst(),
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithLocal(t.OptsGlobal.Local),
 		objectaction.WithFormat(t.OptsGlobal.Format),
+		objectaction.WithParallel(t.OptsGlobal.Parallel),
+		objectaction.WithObjectTimeout(t.OptsGlobal.ObjectTimeout),
 		objectaction.WithColor(t.OptsGlobal.Color),
 		objectaction.WithRemoteNodes(t.OptsGlobal.NodeSelector),
 		objectaction.WithRemoteAction("print_config_mtime"),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
 			tm := object.NewFromPath(p).(object.Configurer).Config().ModTime()
 			return timestamp.New(tm).String(), nil
 		}),
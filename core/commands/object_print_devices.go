@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/devicetree"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectPrintDevices is the cobra flag set of the print devices command.
+	CmdObjectPrintDevices struct {
+		object.OptsPrintDevices
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectPrintDevices) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectPrintDevices) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "devices",
+		Short:   "Print selected objects devices tree",
+		Aliases: []string{"device", "devs", "dev"},
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectPrintDevices) run(selector *string, kind string) {
+	type deviceTreer interface {
+		PrintDevices(object.OptsPrintDevices) *devicetree.T
+	}
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocal(true),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			i, ok := object.NewFromPath(p).(deviceTreer)
+			if !ok {
+				return nil, fmt.Errorf("%s has no devices", p)
+			}
+			return i.PrintDevices(t.OptsPrintDevices), nil
+		}),
+	).Do()
+}
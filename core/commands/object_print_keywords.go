@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectPrintKeywords is the cobra flag set of the print keywords command.
+	CmdObjectPrintKeywords struct {
+		object.OptsPrintKeywords
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectPrintKeywords) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectPrintKeywords) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "keywords",
+		Short: "Print the keywords set in the selected objects configuration",
+		Long: `Print the keywords set in the selected objects configuration, with their
+default value.
+
+With --changed, only the keywords whose value differs from the object or
+resource driver default are reported, to review what was customized.`,
+		Aliases: []string{"keyword", "keywor", "keywo", "keyw", "key", "kw"},
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectPrintKeywords) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		// the configuration is the same on all nodes: no need to route
+		objectaction.WithLocal(true),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			type printKeywordser interface {
+				PrintKeywords(object.OptsPrintKeywords) (object.KeywordsReport, error)
+			}
+			return object.NewFromPath(p).(printKeywordser).PrintKeywords(t.OptsPrintKeywords)
+		}),
+	).Do()
+}
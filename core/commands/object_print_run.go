@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectPrintRun is the cobra flag set of the print run command.
+	CmdObjectPrintRun struct {
+		object.OptsPrintRun
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectPrintRun) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectPrintRun) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "Print selected objects task run logs",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectPrintRun) run(selector *string, kind string) {
+	type runLogPrinter interface {
+		PrintRun(object.OptsPrintRun) (object.RunLogs, error)
+	}
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocal(true),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			i, ok := object.NewFromPath(p).(runLogPrinter)
+			if !ok {
+				return nil, fmt.Errorf("%s has no tasks", p)
+			}
+			return i.PrintRun(t.OptsPrintRun)
+		}),
+	).Do()
+}
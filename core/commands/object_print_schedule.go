@@ -92,7 +92,7 @@ func (t *CmdObjectPrintSchedule) extractFromDaemon(selector string, c *client.T)
 }
 
 func (t *CmdObjectPrintSchedule) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	c, err := client.New(client.WithURL(t.Global.Server))
 	if err != nil {
 		log.Error().Err(err).Msg("")
@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/json"
 	"os"
+	"sort"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -54,13 +55,19 @@ func (t *CmdObjectPrintStatus) cmd(kind string, selector *string) *cobra.Command
 }
 
 func (t *CmdObjectPrintStatus) extract(selector string, c *client.T) []object.Status {
-	if t.Refresh || t.Global.Local {
+	if t.Global.Local {
 		return t.extractLocal(selector)
 	}
-	if data, err := t.extractFromDaemon(selector, c); err == nil {
-		log.Debug().Err(err).Msg("extract cluster status")
+	if t.Refresh {
+		// refresh the local instances status, and complete with the
+		// remote instances status known by the daemon.
+		return t.merge(t.extractLocal(selector), selector, c)
+	}
+	data, err := t.extractFromDaemon(selector, c)
+	if err == nil {
 		return data
 	}
+	log.Debug().Err(err).Msg("extract cluster status")
 	if clientcontext.IsSet() {
 		log.Error().Msg("can not fetch daemon data")
 		return []object.Status{}
@@ -68,6 +75,42 @@ func (t *CmdObjectPrintStatus) extract(selector string, c *client.T) []object.St
 	return t.extractLocal(selector)
 }
 
+// merge adds to the local data the remote instances and the aggregated
+// status fetched from the daemon. The local instances status are kept,
+// as they are more recent than the daemon ones.
+func (t *CmdObjectPrintStatus) merge(local []object.Status, selector string, c *client.T) []object.Status {
+	if clientcontext.IsSet() {
+		return local
+	}
+	remote, err := t.extractFromDaemon(selector, c)
+	if err != nil {
+		log.Debug().Err(err).Msg("extract cluster status")
+		return local
+	}
+	m := make(map[string]object.Status)
+	for _, d := range remote {
+		m[d.Path.String()] = d
+	}
+	for i, d := range local {
+		r, ok := m[d.Path.String()]
+		if !ok {
+			continue
+		}
+		for nodename, instance := range r.Instances {
+			if _, ok := d.Instances[nodename]; !ok {
+				d.Instances[nodename] = instance
+			}
+		}
+		d.Object = r.Object
+		d.Compat = r.Compat
+		d.Parents = r.Parents
+		d.Children = r.Children
+		d.Slaves = r.Slaves
+		local[i] = d
+	}
+	return local
+}
+
 func (t *CmdObjectPrintStatus) extractLocal(selector string) []object.Status {
 	data := make([]object.Status, 0)
 	sel := object.NewSelection(
@@ -132,7 +175,7 @@ func (t *CmdObjectPrintStatus) extractFromDaemon(selector string, c *client.T) (
 
 func (t *CmdObjectPrintStatus) run(selector *string, kind string) {
 	var data []object.Status
-	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
 	c, err := client.New(client.WithURL(t.Global.Server))
 	if err != nil {
 		log.Error().Err(err).Msg("")
@@ -143,7 +186,16 @@ func (t *CmdObjectPrintStatus) run(selector *string, kind string) {
 		object.SelectionWithClient(c),
 	)
 	paths := sel.ExpandSet()
-	data = t.extract(mergedSelector, c)
+	data = make([]object.Status, 0)
+	for _, d := range t.extract(mergedSelector, c) {
+		// the daemon also returns the relatives of the selected objects
+		if paths.Has(d.Path) {
+			data = append(data, d)
+		}
+	}
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Path.Less(data[j].Path)
+	})
 
 	output.Renderer{
 		Format: t.Global.Format,
@@ -152,9 +204,6 @@ func (t *CmdObjectPrintStatus) run(selector *string, kind string) {
 		HumanRenderer: func() string {
 			s := ""
 			for _, d := range data {
-				if !paths.Has(d.Path) {
-					continue
-				}
 				s += d.Render()
 			}
 			return s
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
@@ -33,18 +35,20 @@ func (t *CmdObjectProvision) cmd(kind string, selector *string) *cobra.Command {
 }
 
 func (t *CmdObjectProvision) run(selector *string, kind string) {
-	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, kind, "")
+	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, t.OptsGlobal.Namespace, kind, "")
 	objectaction.New(
 		objectaction.WithObjectSelector(mergedSelector),
 		objectaction.WithLocal(t.OptsGlobal.Local),
 		objectaction.WithFormat(t.OptsGlobal.Format),
+		objectaction.WithParallel(t.OptsGlobal.Parallel),
+		objectaction.WithObjectTimeout(t.OptsGlobal.ObjectTimeout),
 		objectaction.WithColor(t.OptsGlobal.Color),
 		objectaction.WithRemoteNodes(t.OptsGlobal.NodeSelector),
 		objectaction.WithRemoteAction("provision"),
 		objectaction.WithAsyncTarget("provisioned"),
 		objectaction.WithAsyncWatch(t.OptsAsync.Watch),
-		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
-			return nil, object.NewActorFromPath(p).Provision(t.OptsProvision)
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewActorFromPath(p).Provision(ctx, t.OptsProvision)
 		}),
 	).Do()
 }
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectRun is the cobra flag set of the run command.
+	CmdObjectRun struct {
+		object.OptsRun
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectRun) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectRun) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "run",
+		Short: "run the selected tasks of the selected objects",
+		Long:  "Run the task resources selected by --rid, or all the task resources if no resource selector is set. The tasks configured with confirmation=true require the --confirm flag.",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectRun) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, t.OptsGlobal.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocal(t.OptsGlobal.Local),
+		objectaction.WithFormat(t.OptsGlobal.Format),
+		objectaction.WithParallel(t.OptsGlobal.Parallel),
+		objectaction.WithObjectTimeout(t.OptsGlobal.ObjectTimeout),
+		objectaction.WithColor(t.OptsGlobal.Color),
+		objectaction.WithRemoteNodes(t.OptsGlobal.NodeSelector),
+		objectaction.WithRemoteAction("run"),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewActorFromPath(p).Run(ctx, t.OptsRun)
+		}),
+	).Do()
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdObjectSnapCreate is the cobra flag set of the snap create command.
+	CmdObjectSnapCreate struct {
+		object.OptsSnapCreate
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectSnapCreate) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectSnapCreate) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "create a named snapshot of the selected resources data",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectSnapCreate) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.OptsGlobal.ObjectSelector, t.OptsGlobal.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocal(t.OptsGlobal.Local),
+		objectaction.WithFormat(t.OptsGlobal.Format),
+		objectaction.WithParallel(t.OptsGlobal.Parallel),
+		objectaction.WithObjectTimeout(t.OptsGlobal.ObjectTimeout),
+		objectaction.WithColor(t.OptsGlobal.Color),
+		objectaction.WithRemoteNodes(t.OptsGlobal.NodeSelector),
+		objectaction.WithRemoteAction("snap_create"),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewActorFromPath(p).SnapCreate(ctx, t.OptsSnapCreate)
+		}),
+	).Do()
+}
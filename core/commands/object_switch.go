@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+)
+
+type (
+	// CmdObjectSwitch is the cobra flag set of the switch command.
+	CmdObjectSwitch struct {
+		Global object.OptsGlobal
+		Async  object.OptsAsync
+		To     string `flag:"switch-to"`
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdObjectSwitch) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, t)
+}
+
+func (t *CmdObjectSwitch) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "switch",
+		Short: "orchestrate the selected objects instances relocation to the node specified by --to",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdObjectSwitch) run(selector *string, kind string) {
+	if t.To == "" {
+		fmt.Fprintln(os.Stderr, "the --to <node> flag is required")
+		os.Exit(1)
+	}
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithServer(t.Global.Server),
+		objectaction.WithAsyncTarget("placed@"+t.To),
+		objectaction.WithAsyncWatch(t.Async.Watch),
+	).Do()
+}
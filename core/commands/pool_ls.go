@@ -1,15 +1,19 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/clientcontext"
 	"opensvc.com/opensvc/core/flag"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/pool"
 	"opensvc.com/opensvc/core/rawconfig"
 )
 
@@ -38,11 +42,15 @@ func (t *PoolLs) cmd() *cobra.Command {
 }
 
 func (t *PoolLs) run() {
-	var data []string
+	var (
+		data []string
+		err  error
+	)
 	if t.Global.Local || !clientcontext.IsSet() {
 		data = t.extractLocal()
-	} else {
-		data = t.extractDaemon()
+	} else if data, err = t.extractDaemon(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 	output.Renderer{
 		Format: t.Global.Format,
@@ -63,16 +71,23 @@ func (t *PoolLs) extractLocal() []string {
 	return object.NewNode().ListPools()
 }
 
-func (t *PoolLs) extractDaemon() []string {
-	var (
-		c   *client.T
-		err error
-	)
-	if c, err = client.New(client.WithURL(t.Global.Server)); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+func (t *PoolLs) extractDaemon() ([]string, error) {
+	c, err := client.New(client.WithURL(t.Global.Server))
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]pool.Status)
+	b, err := c.NewGetPools().Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal GET /pools")
+	}
+	l := make([]string, 0, len(data))
+	for name := range data {
+		l = append(l, name)
 	}
-	panic("TODO")
-	fmt.Println(c)
-	return []string{}
+	sort.Strings(l)
+	return l, nil
 }
@@ -69,11 +69,7 @@ func (t *PoolStatus) run() {
 }
 
 func (t *PoolStatus) extractLocal() (pool.StatusList, error) {
-	if t.Name == "" {
-		return object.NewNode().ShowPools(), nil
-	} else {
-		return object.NewNode().ShowPoolsByName(t.Name), nil
-	}
+	return object.NewNode().ShowPoolsByName(t.Name, t.Verbose), nil
 }
 
 func (t *PoolStatus) extractDaemon() (pool.StatusList, error) {
@@ -85,6 +81,7 @@ func (t *PoolStatus) extractDaemon() (pool.StatusList, error) {
 	data := make(map[string]pool.Status)
 	req := c.NewGetPools()
 	req.SetName(t.Name)
+	req.SetVerbose(t.Verbose)
 	b, err := req.Do()
 	if err != nil {
 		return l, err
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+	"opensvc.com/opensvc/core/flag"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// CmdSecRekey is the cobra flag set of the rekey command.
+	CmdSecRekey struct {
+		object.OptsRekey
+	}
+)
+
+// Init configures a cobra command and adds it to the parent command.
+func (t *CmdSecRekey) Init(kind string, parent *cobra.Command, selector *string) {
+	cmd := t.cmd(kind, selector)
+	parent.AddCommand(cmd)
+	flag.Install(cmd, &t.OptsRekey)
+}
+
+func (t *CmdSecRekey) cmd(kind string, selector *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rekey",
+		Short: "re-encrypt all keys with the current cluster secret",
+		Long:  "Re-encrypt all keys with the current cluster secret and encryption format. Use --oldsecret after a cluster secret rotation.",
+		Run: func(cmd *cobra.Command, args []string) {
+			t.run(selector, kind)
+		},
+	}
+}
+
+func (t *CmdSecRekey) run(selector *string, kind string) {
+	mergedSelector := mergeSelector(*selector, t.Global.ObjectSelector, t.Global.Namespace, kind, "")
+	objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Global.Local),
+		objectaction.WithColor(t.Global.Color),
+		objectaction.WithFormat(t.Global.Format),
+		objectaction.WithParallel(t.Global.Parallel),
+		objectaction.WithObjectTimeout(t.Global.ObjectTimeout),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.Global.NodeSelector),
+		objectaction.WithRemoteAction("rekey"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"oldsecret": t.OldSecret,
+		}),
+		objectaction.WithLocalRun(func(ctx context.Context, p path.T) (interface{}, error) {
+			return nil, object.NewFromPath(p).(object.SecureKeystorer).Rekey(t.OptsRekey)
+		}),
+	).Do()
+}
@@ -0,0 +1,164 @@
+//
+// Package completion provides the shell completion candidates of the
+// command flags values, like object paths, node names, resource ids and
+// keyword names.
+//
+// The candidates are fetched from the daemon api, and from the local
+// configurations if the daemon is not reachable.
+//
+package completion
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/nodeselector"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/key"
+)
+
+// sections maps an object configuration section name to the value of
+// its type keyword.
+type sections map[string]string
+
+// Paths returns the object paths starting with prefix.
+func Paths(prefix string) []string {
+	l := make([]string, 0)
+	for _, p := range object.NewSelection("**").Expand() {
+		l = append(l, p.String())
+	}
+	return filter(l, prefix)
+}
+
+// Nodes returns the cluster node names starting with prefix.
+func Nodes(prefix string) []string {
+	return filter(nodeselector.New("*").Expand(), prefix)
+}
+
+// RIDs returns the resource ids of the objects selected by selector,
+// starting with prefix.
+func RIDs(selector, prefix string) []string {
+	m := make(map[string]interface{})
+	for _, p := range selection(selector) {
+		for section := range objectSections(p) {
+			switch section {
+			case "DEFAULT", "env", "data":
+				continue
+			}
+			m[section] = nil
+		}
+	}
+	return filter(keys(m), prefix)
+}
+
+// Keywords returns the names of the keywords supported by the objects
+// selected by selector, starting with prefix.
+func Keywords(selector, prefix string) []string {
+	m := make(map[string]interface{})
+	for _, p := range selection(selector) {
+		for _, s := range object.KeywordCandidates(p.Kind, objectSections(p)) {
+			m[s] = nil
+		}
+	}
+	return filter(keys(m), prefix)
+}
+
+func selection(selector string) []path.T {
+	if selector == "" {
+		return []path.T{}
+	}
+	return object.NewSelection(selector).Expand()
+}
+
+// objectSections returns the configuration sections of the object,
+// from the daemon or from the local configuration file.
+func objectSections(p path.T) sections {
+	if m, err := daemonSections(p); err == nil {
+		return m
+	}
+	return localSections(p)
+}
+
+func localSections(p path.T) sections {
+	m := make(sections)
+	o := object.NewConfigurerFromPath(p)
+	if !o.Exists() {
+		return m
+	}
+	cf := o.Config()
+	for _, section := range cf.SectionStrings() {
+		m[section] = cf.GetString(key.New(section, "type"))
+	}
+	return m
+}
+
+func daemonSections(p path.T) (sections, error) {
+	c, err := client.New()
+	if err != nil {
+		return nil, err
+	}
+	req := c.NewGetObjectConfig()
+	req.ObjectSelector = p.String()
+	b, err := req.Do()
+	if err != nil {
+		return nil, err
+	}
+	return parseSections(b)
+}
+
+//
+// parseSections returns the sections of the object_config api response,
+// either a <section>: {<option>: <value>} dataset or the same dataset
+// routed from a peer node, nested in a nodes: {<node>: <dataset>} dataset.
+//
+func parseSections(b []byte) (sections, error) {
+	type routedResponse struct {
+		Nodes map[string]json.RawMessage `json:"nodes"`
+	}
+	var routed routedResponse
+	if err := json.Unmarshal(b, &routed); err == nil {
+		for _, data := range routed.Nodes {
+			b = data
+			break
+		}
+	}
+	data := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	m := make(sections)
+	for section, raw := range data {
+		if section == "metadata" {
+			continue
+		}
+		options := make(map[string]interface{})
+		if err := json.Unmarshal(raw, &options); err != nil {
+			continue
+		}
+		m[section], _ = options["type"].(string)
+	}
+	return m, nil
+}
+
+func keys(m map[string]interface{}) []string {
+	l := make([]string, 0, len(m))
+	for s := range m {
+		l = append(l, s)
+	}
+	return l
+}
+
+// filter returns the sorted elements of l starting with prefix.
+func filter(l []string, prefix string) []string {
+	filtered := make([]string, 0)
+	for _, s := range l {
+		if strings.HasPrefix(s, prefix) {
+			filtered = append(filtered, s)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
@@ -0,0 +1,45 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSections(t *testing.T) {
+	cases := map[string]struct {
+		data     string
+		expected sections
+	}{
+		"local": {
+			data: `{"DEFAULT": {"nodes": "n1"}, "fs#1": {"type": "flag"}, "app#1": {}, "metadata": {"name": "s1"}}`,
+			expected: sections{
+				"DEFAULT": "",
+				"fs#1":    "flag",
+				"app#1":   "",
+			},
+		},
+		"routed": {
+			data: `{"nodes": {"n1": {"DEFAULT": {}, "ip#1": {"type": "host"}}}}`,
+			expected: sections{
+				"DEFAULT": "",
+				"ip#1":    "host",
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			m, err := parseSections([]byte(c.data))
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, m)
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	l := []string{"fs#2", "app#1", "fs#1"}
+	assert.Equal(t, []string{"fs#1", "fs#2"}, filter(l, "fs"))
+	assert.Equal(t, []string{"app#1", "fs#1", "fs#2"}, filter(l, ""))
+	assert.Equal(t, []string{}, filter(l, "ip"))
+}
@@ -0,0 +1,67 @@
+package compliance
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+
+	"opensvc.com/opensvc/util/xmlrpc"
+)
+
+type (
+	// Collector reports the compliance results to the collector compliance
+	// xmlrpc server, so they are available in the collector compliance
+	// logs and status views.
+	Collector struct {
+		client   *xmlrpc.T
+		nodename string
+		uuid     string
+		rsetMD5  string
+	}
+)
+
+// collectorVars are the column names of the comp_log_actions call values.
+var collectorVars = []string{
+	"run_module",
+	"run_nodename",
+	"run_status",
+	"run_log",
+	"run_action",
+	"rset_md5",
+}
+
+// NewCollector returns a Reporter posting to the collector compliance
+// xmlrpc server at url, authenticated by the node name and uuid. The
+// md5sum of the data rulesets is reported with the results, so the
+// collector can tell which rulesets the modules were run with.
+func NewCollector(url, nodename, uuid string, data Data) *Collector {
+	b, _ := json.Marshal(data.Rulesets)
+	return &Collector{
+		client:   xmlrpc.New(url),
+		nodename: nodename,
+		uuid:     uuid,
+		rsetMD5:  fmt.Sprintf("%x", md5.Sum(b)),
+	}
+}
+
+// Report posts the results to the collector.
+func (t Collector) Report(ctx context.Context, results Results) error {
+	vals := make([][]string, len(results))
+	for i, r := range results {
+		status := r.ExitCode
+		if r.Status == StatusError {
+			status = 1
+		}
+		vals[i] = []string{
+			r.Module,
+			t.nodename,
+			fmt.Sprint(status),
+			r.Log,
+			r.Action,
+			t.rsetMD5,
+		}
+	}
+	auth := []string{t.uuid, t.nodename}
+	return t.client.Call(ctx, "comp_log_actions", collectorVars, vals, auth)
+}
@@ -0,0 +1,253 @@
+// Package compliance runs the compliance modules installed on the node,
+// with the variables of the rulesets exported in their environment.
+//
+// A compliance module is an executable accepting the check, fix and
+// fixable actions as first argument, and returning 0 if the node is
+// compliant, 1 if it is not, and 2 if the module does not apply to the
+// node.
+//
+// The modules are grouped in modulesets, and a moduleset can flag its
+// modules as autofix, so the scheduled "compliance auto" action fixes
+// them when they are not compliant.
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// T runs the compliance modules.
+	T struct {
+		dir      string
+		data     Data
+		reporter Reporter
+		log      *zerolog.Logger
+	}
+
+	// Data is the compliance modulesets and rulesets attached to the node.
+	Data struct {
+		Modulesets map[string]Moduleset `json:"modulesets"`
+		Rulesets   map[string]Ruleset   `json:"rulesets"`
+	}
+
+	// Moduleset is a named group of modules.
+	Moduleset struct {
+		Modules []ModulesetModule `json:"modules"`
+	}
+
+	// ModulesetModule is a module reference in a moduleset.
+	ModulesetModule struct {
+		Name    string `json:"name"`
+		Autofix bool   `json:"autofix"`
+	}
+
+	// Ruleset is a named group of variables exported in the modules
+	// environment.
+	Ruleset struct {
+		Vars []Var `json:"vars"`
+	}
+
+	// Var is a ruleset variable. The Class hints the modules about the
+	// Value format. A Value which is not a string is exported json
+	// encoded.
+	Var struct {
+		Name  string      `json:"name"`
+		Class string      `json:"class"`
+		Value interface{} `json:"value"`
+	}
+
+	// Selection restricts the modules to run. An empty Selection selects
+	// the modules of all the modulesets.
+	Selection struct {
+		Modulesets []string
+		Modules    []string
+
+		// Force disables the check run before the fix.
+		Force bool
+	}
+
+	// Reporter is implemented by the compliance results collectors.
+	Reporter interface {
+		Report(context.Context, Results) error
+	}
+)
+
+// EnvPrefix is the prefix of the environment variables the ruleset
+// variables are exported as.
+const EnvPrefix = "OSVC_COMP_"
+
+var regexpEnvName = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// New returns a compliance modules runner.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		log: &log.Logger,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithDir sets the directory where the compliance modules are installed.
+func WithDir(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.dir = s
+		return nil
+	})
+}
+
+// WithData sets the modulesets and rulesets.
+func WithData(data Data) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.data = data
+		return nil
+	})
+}
+
+// WithReporter sets the collector the results are reported to.
+func WithReporter(r Reporter) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.reporter = r
+		return nil
+	})
+}
+
+// WithLogger sets the logger.
+func WithLogger(l *zerolog.Logger) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.log = l
+		return nil
+	})
+}
+
+// LoadData returns the modulesets and rulesets stored in the json file p.
+// A missing file is not an error, and returns an empty Data.
+func LoadData(p string) (Data, error) {
+	data := Data{}
+	b, err := ioutil.ReadFile(p)
+	switch {
+	case os.IsNotExist(err):
+		return data, nil
+	case err != nil:
+		return data, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, fmt.Errorf("%s: %w", p, err)
+	}
+	return data, nil
+}
+
+// Env returns the ruleset variables as environment variables, in the
+// NAME=value format. The rulesets are exported in their name order, so
+// a variable defined in more than one ruleset has the value of the last
+// one.
+func (t Data) Env() []string {
+	names := make([]string, 0, len(t.Rulesets))
+	for name := range t.Rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	m := make(map[string]string)
+	order := make([]string, 0)
+	for _, name := range names {
+		for _, v := range t.Rulesets[name].Vars {
+			k := EnvName(v.Name)
+			if _, ok := m[k]; !ok {
+				order = append(order, k)
+			}
+			m[k] = v.String()
+		}
+	}
+	env := make([]string, len(order))
+	for i, k := range order {
+		env[i] = k + "=" + m[k]
+	}
+	return env
+}
+
+// EnvName returns the name of the environment variable a ruleset
+// variable is exported as.
+func EnvName(s string) string {
+	return EnvPrefix + regexpEnvName.ReplaceAllString(strings.ToUpper(s), "_")
+}
+
+// String returns the variable value, json encoded if it is not a string.
+func (t Var) String() string {
+	switch v := t.Value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+// Check runs the check action of the selected modules.
+func (t T) Check(ctx context.Context, sel Selection) (Results, error) {
+	return t.do(ctx, sel, func(m Module) Results {
+		return Results{t.run(ctx, m, ActionCheck)}
+	})
+}
+
+// Fix runs the fix action of the selected modules. Unless sel.Force is
+// set, the modules are checked first, and only the non compliant modules
+// are fixed.
+func (t T) Fix(ctx context.Context, sel Selection) (Results, error) {
+	return t.do(ctx, sel, func(m Module) Results {
+		return t.fix(ctx, m, sel.Force)
+	})
+}
+
+// Auto runs the check action of the selected modules, and the fix action
+// of the non compliant modules flagged autofix in their moduleset.
+func (t T) Auto(ctx context.Context, sel Selection) (Results, error) {
+	return t.do(ctx, sel, func(m Module) Results {
+		if m.Autofix {
+			return t.fix(ctx, m, false)
+		}
+		return Results{t.run(ctx, m, ActionCheck)}
+	})
+}
+
+func (t T) fix(ctx context.Context, m Module, force bool) Results {
+	if !force {
+		r := t.run(ctx, m, ActionCheck)
+		if r.Status != StatusNotOk {
+			return Results{r}
+		}
+	}
+	return Results{t.run(ctx, m, ActionFix)}
+}
+
+func (t T) do(ctx context.Context, sel Selection, f func(Module) Results) (Results, error) {
+	modules, err := t.Modules(sel)
+	if err != nil {
+		return nil, err
+	}
+	results := make(Results, 0)
+	for _, m := range modules {
+		results = append(results, f(m)...)
+	}
+	if t.reporter != nil && len(results) > 0 {
+		if err := t.reporter.Report(ctx, results); err != nil {
+			t.log.Warn().Err(err).Msg("report compliance results to the collector")
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,159 @@
+package compliance
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reporterFunc func(context.Context, Results) error
+
+func (f reporterFunc) Report(ctx context.Context, r Results) error {
+	return f(ctx, r)
+}
+
+// module is a compliance module script: the check action exits with the
+// content of the state file, the fix action writes 0 to the state file.
+const module = `#!/bin/sh
+state=$(dirname $0)/$(basename $0).state
+case $1 in
+check)
+	echo "check $OSVC_COMP_MOTD"
+	exit $(cat $state)
+	;;
+fix)
+	echo 0 >$state
+	echo "fixed" >&2
+	;;
+esac
+`
+
+func setupModules(t *testing.T, states map[string]string) string {
+	dir, err := ioutil.TempDir("", "compliance")
+	require.NoError(t, err)
+	for name, state := range states {
+		p := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(p, []byte(module), 0755))
+		require.NoError(t, ioutil.WriteFile(p+".state", []byte(state), 0644))
+	}
+	return dir
+}
+
+var testData = Data{
+	Modulesets: map[string]Moduleset{
+		"ms1": {Modules: []ModulesetModule{{Name: "motd", Autofix: true}, {Name: "users"}}},
+		"ms2": {Modules: []ModulesetModule{{Name: "users"}, {Name: "missing"}}},
+	},
+	Rulesets: map[string]Ruleset{
+		"rs1": {Vars: []Var{{Name: "motd", Class: "raw", Value: "hello"}}},
+		"rs2": {Vars: []Var{{Name: "user.list", Class: "json", Value: []interface{}{"a", "b"}}}},
+	},
+}
+
+func TestEnv(t *testing.T) {
+	assert.Equal(t, []string{
+		"OSVC_COMP_MOTD=hello",
+		`OSVC_COMP_USER_LIST=["a","b"]`,
+	}, testData.Env())
+}
+
+func TestModules(t *testing.T) {
+	dir := setupModules(t, map[string]string{"S20-motd": "1", "S10-users": "0"})
+	defer os.RemoveAll(dir)
+	c := New(WithDir(dir), WithData(testData))
+
+	modules, err := c.Modules(Selection{})
+	require.NoError(t, err)
+	require.Len(t, modules, 3)
+	assert.Equal(t, "missing", modules[0].Name)
+	assert.Equal(t, "", modules[0].Path)
+	assert.Equal(t, "users", modules[1].Name)
+	assert.Equal(t, []string{"ms1", "ms2"}, modules[1].Modulesets)
+	assert.False(t, modules[1].Autofix)
+	assert.Equal(t, "motd", modules[2].Name)
+	assert.True(t, modules[2].Autofix)
+
+	modules, err = c.Modules(Selection{Modules: []string{"motd"}})
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+
+	_, err = c.Modules(Selection{Modulesets: []string{"ms3"}})
+	assert.EqualError(t, err, "moduleset ms3 is not attached to the node")
+}
+
+func TestCheckFix(t *testing.T) {
+	dir := setupModules(t, map[string]string{"S20-motd": "1", "S10-users": "2"})
+	defer os.RemoveAll(dir)
+	var reported Results
+	c := New(
+		WithDir(dir),
+		WithData(testData),
+		WithReporter(reporterFunc(func(_ context.Context, r Results) error {
+			reported = r
+			return nil
+		})),
+	)
+	ctx := context.Background()
+	sel := Selection{Modulesets: []string{"ms1"}}
+
+	results, err := c.Check(ctx, sel)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusNotApplicable, results[0].Status)
+	assert.Equal(t, StatusNotOk, results[1].Status)
+	assert.Equal(t, "check hello", results[1].Log)
+	assert.False(t, results.IsCompliant())
+	assert.Equal(t, results, reported)
+
+	results, err = c.Auto(ctx, sel)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, ActionFix, results[1].Action)
+	assert.Equal(t, StatusOk, results[1].Status)
+	assert.Equal(t, "fixed", results[1].Log)
+
+	results, err = c.Fix(ctx, sel)
+	require.NoError(t, err)
+	assert.Equal(t, ActionCheck, results[1].Action, "compliant modules are not fixed")
+	assert.True(t, results.IsCompliant())
+}
+
+func TestLoadData(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compliance")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "compliance.json")
+
+	data, err := LoadData(p)
+	require.NoError(t, err)
+	assert.Empty(t, data.Modulesets)
+
+	s := `{"modulesets": {"ms1": {"modules": [{"name": "motd", "autofix": true}]}}, "rulesets": {"rs1": {"vars": [{"name": "motd", "class": "raw", "value": "hello"}]}}}`
+	require.NoError(t, ioutil.WriteFile(p, []byte(s), 0644))
+	data, err = LoadData(p)
+	require.NoError(t, err)
+	assert.Equal(t, []ModulesetModule{{Name: "motd", Autofix: true}}, data.Modulesets["ms1"].Modules)
+	assert.Equal(t, []string{"OSVC_COMP_MOTD=hello"}, data.Env())
+}
+
+func TestCollectorReport(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><nil/></value></param></params></methodResponse>`))
+	}))
+	defer srv.Close()
+	c := NewCollector(srv.URL, "node1", "8a1d3a0b-2c4e-4f3d-9e55-1b7c6f0d2a11", testData)
+	err := c.Report(context.Background(), Results{{Module: "motd", Action: ActionCheck, Status: StatusError, ExitCode: 3, Log: "failed"}})
+	require.NoError(t, err)
+	assert.Contains(t, body, "<methodName>comp_log_actions</methodName>")
+	assert.Contains(t, body, "<value><string>motd</string></value><value><string>node1</string></value><value><string>1</string></value><value><string>failed</string></value>")
+}
@@ -0,0 +1,190 @@
+package compliance
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	// Module is a compliance module selected for a run.
+	Module struct {
+		Name string
+
+		// Order is the run order of the module, parsed from the S<order>-
+		// prefix of the module file name.
+		Order int
+
+		// Path is the module executable path, empty if the module is
+		// referenced by a moduleset but not installed.
+		Path string
+
+		// Modulesets are the names of the modulesets referencing the
+		// module.
+		Modulesets []string
+
+		// Autofix is true if one of the modulesets referencing the
+		// module flags it autofix.
+		Autofix bool
+	}
+)
+
+const (
+	// ActionCheck is the module action verifying the node compliance.
+	ActionCheck = "check"
+
+	// ActionFix is the module action making the node compliant.
+	ActionFix = "fix"
+)
+
+var regexpModuleFile = regexp.MustCompile(`^S([0-9]+)-(.+)$`)
+
+// Installed returns the executable modules found in the modules
+// directory, indexed by name. The module files can be prefixed by
+// S<order>- to set the modules run order.
+func (t T) Installed() (map[string]Module, error) {
+	m := make(map[string]Module)
+	entries, err := ioutil.ReadDir(t.dir)
+	switch {
+	case os.IsNotExist(err):
+		return m, nil
+	case err != nil:
+		return m, err
+	}
+	for _, e := range entries {
+		if !e.Mode().IsRegular() || e.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		mod := Module{
+			Name: e.Name(),
+			Path: filepath.Join(t.dir, e.Name()),
+		}
+		if l := regexpModuleFile.FindStringSubmatch(e.Name()); l != nil {
+			mod.Order, _ = strconv.Atoi(l[1])
+			mod.Name = l[2]
+		}
+		m[mod.Name] = mod
+	}
+	return m, nil
+}
+
+// Modules returns the selected modules, in run order.
+func (t T) Modules(sel Selection) ([]Module, error) {
+	installed, err := t.Installed()
+	if err != nil {
+		return nil, err
+	}
+	modulesets := sel.Modulesets
+	if len(modulesets) == 0 {
+		for name := range t.data.Modulesets {
+			modulesets = append(modulesets, name)
+		}
+		sort.Strings(modulesets)
+	}
+	selected := make(map[string]Module)
+	for _, msName := range modulesets {
+		ms, ok := t.data.Modulesets[msName]
+		if !ok {
+			return nil, fmt.Errorf("moduleset %s is not attached to the node", msName)
+		}
+		for _, ref := range ms.Modules {
+			mod, ok := selected[ref.Name]
+			if !ok {
+				mod = installed[ref.Name]
+				mod.Name = ref.Name
+			}
+			mod.Modulesets = append(mod.Modulesets, msName)
+			mod.Autofix = mod.Autofix || ref.Autofix
+			selected[ref.Name] = mod
+		}
+	}
+	if len(sel.Modules) > 0 {
+		filtered := make(map[string]Module)
+		for _, name := range sel.Modules {
+			mod, ok := selected[name]
+			if !ok {
+				mod, ok = installed[name]
+			}
+			if !ok {
+				return nil, fmt.Errorf("module %s is not installed", name)
+			}
+			filtered[name] = mod
+		}
+		selected = filtered
+	}
+	l := make([]Module, 0, len(selected))
+	for _, mod := range selected {
+		l = append(l, mod)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Order != l[j].Order {
+			return l[i].Order < l[j].Order
+		}
+		return l[i].Name < l[j].Name
+	})
+	return l, nil
+}
+
+// run executes the module action, with the ruleset variables exported in
+// its environment.
+func (t T) run(ctx context.Context, m Module, action string) Result {
+	r := Result{
+		Module:     m.Name,
+		Modulesets: m.Modulesets,
+		Action:     action,
+		Begin:      time.Now(),
+	}
+	if m.Path == "" {
+		r.Status = StatusError
+		r.Log = "module not installed"
+		return r
+	}
+	if err := ctx.Err(); err != nil {
+		r.Status = StatusError
+		r.Log = err.Error()
+		return r
+	}
+	var (
+		lines []string
+		mu    sync.Mutex
+	)
+	onLine := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, s)
+	}
+	cmd := command.New(
+		command.WithName(m.Path),
+		command.WithVarArgs(action),
+		command.WithEnv(append(os.Environ(), t.data.Env()...)),
+		command.WithLogger(t.log),
+		command.WithOnStdoutLine(onLine),
+		command.WithOnStderrLine(onLine),
+		// the module exit code is the compliance status
+		command.WithIgnoredExitCodes(),
+	)
+	t.log.Info().Str("module", m.Name).Msgf("compliance %s", action)
+	err := cmd.Run()
+	r.Duration = time.Since(r.Begin)
+	if err != nil {
+		lines = append(lines, err.Error())
+	}
+	r.Log = strings.Join(lines, "\n")
+	if err != nil {
+		r.Status = StatusError
+		return r
+	}
+	r.ExitCode = cmd.ExitCode()
+	r.Status = StatusFromExitCode(r.ExitCode)
+	return r
+}
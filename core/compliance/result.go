@@ -0,0 +1,100 @@
+package compliance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// Result is the outcome of a module action.
+	Result struct {
+		Module     string        `json:"module"`
+		Modulesets []string      `json:"modulesets"`
+		Action     string        `json:"action"`
+		Status     string        `json:"status"`
+		ExitCode   int           `json:"exitcode"`
+		Log        string        `json:"log"`
+		Begin      time.Time     `json:"begin"`
+		Duration   time.Duration `json:"duration"`
+	}
+
+	// Results is the list of the module action outcomes of a compliance
+	// run, in run order.
+	Results []Result
+)
+
+const (
+	// StatusOk is the status of a compliant module check, or of a
+	// successful module fix.
+	StatusOk = "ok"
+
+	// StatusNotOk is the status of a non compliant module check, or of a
+	// failed module fix.
+	StatusNotOk = "nok"
+
+	// StatusNotApplicable is the status of a module not applying to the
+	// node.
+	StatusNotApplicable = "n/a"
+
+	// StatusError is the status of a module that could not be executed,
+	// or that returned an unexpected exit code.
+	StatusError = "error"
+)
+
+// StatusFromExitCode returns the status of a module exit code.
+func StatusFromExitCode(i int) string {
+	switch i {
+	case 0:
+		return StatusOk
+	case 1:
+		return StatusNotOk
+	case 2:
+		return StatusNotApplicable
+	default:
+		return StatusError
+	}
+}
+
+// IsCompliant returns true if no module is in the nok or error status.
+func (t Results) IsCompliant() bool {
+	for _, r := range t {
+		switch r.Status {
+		case StatusNotOk, StatusError:
+			return false
+		}
+	}
+	return true
+}
+
+// Render returns a human friendly string representation of the results.
+func (t Results) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText(hostname.Hostname()).SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("action")
+	tree.AddColumn().AddText("status")
+	tree.AddColumn().AddText("duration")
+	for _, r := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(r.Module).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(r.Action)
+		c := n.AddColumn().AddText(r.Status)
+		switch r.Status {
+		case StatusOk:
+			c.SetColor(rawconfig.Node.Color.Optimal)
+		case StatusNotOk, StatusError:
+			c.SetColor(rawconfig.Node.Color.Error)
+		default:
+			c.SetColor(rawconfig.Node.Color.Secondary)
+		}
+		n.AddColumn().AddText(fmt.Sprint(r.Duration.Round(time.Millisecond)))
+		if r.Log != "" {
+			n.AddNode().AddColumn().AddText(strings.TrimRight(r.Log, "\n")).SetColor(rawconfig.Node.Color.Secondary)
+		}
+	}
+	return tree.Render()
+}
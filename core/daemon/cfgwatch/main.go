@@ -0,0 +1,373 @@
+//
+// Package cfgwatch detects the object configuration files changes in the
+// node etc directory, including the edits done outside the agent, and
+// publishes a change event to the subscribed daemon threads.
+//
+// The changes are detected with inotify when available, and by polling
+// the configuration files checksums otherwise. A changed file is parsed
+// before its event is published, so the subscribers never receive an
+// event for a configuration that can not be loaded.
+//
+package cfgwatch
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// Event is published when an object configuration file is created,
+	// changed or removed.
+	Event struct {
+		Path     path.T    `json:"path"`
+		File     string    `json:"file"`
+		Checksum string    `json:"csum,omitempty"`
+		Deleted  bool      `json:"deleted,omitempty"`
+		Time     time.Time `json:"time"`
+	}
+
+	// T is the configuration files watcher.
+	T struct {
+		dir          string
+		pollInterval time.Duration
+		polling      bool
+
+		mu          sync.Mutex
+		subscribers []chan Event
+		sums        map[string]string
+	}
+)
+
+var (
+	// DefaultPollInterval is the interval between two scans of the
+	// configuration files, when inotify is not available.
+	DefaultPollInterval = 5 * time.Second
+
+	// subscriberQueueLen is the number of events buffered per subscriber.
+	subscriberQueueLen = 100
+
+	// errEmpty is returned by checksum for an empty file, usually
+	// truncated by a writer not done yet.
+	errEmpty = errors.New("empty file")
+)
+
+// New allocates and returns a configuration files watcher.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		dir:          rawconfig.Node.Paths.Etc,
+		pollInterval: DefaultPollInterval,
+		sums:         make(map[string]string),
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithDir sets the watched etc directory. The default is the node etc
+// directory.
+func WithDir(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.dir = s
+		return nil
+	})
+}
+
+// WithPollInterval sets the interval between two scans in polling mode.
+func WithPollInterval(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.pollInterval = d
+		return nil
+	})
+}
+
+// WithPolling forces the polling mode, for the filesystems not
+// supporting inotify.
+func WithPolling(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.polling = v
+		return nil
+	})
+}
+
+//
+// Subscribe returns a channel receiving the configuration change events.
+// The events are dropped, with a warning, if the subscriber does not
+// consume them fast enough.
+//
+func (t *T) Subscribe() <-chan Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := make(chan Event, subscriberQueueLen)
+	t.subscribers = append(t.subscribers, c)
+	return c
+}
+
+//
+// Run records the installed configuration files checksums, then watches
+// the changes until the context is done. The subscribers channels are
+// closed when Run returns.
+//
+func (t *T) Run(ctx context.Context) error {
+	defer t.closeSubscribers()
+	t.scan(false)
+	if !t.polling {
+		w, err := fsnotify.NewWatcher()
+		if err == nil {
+			defer w.Close()
+			return t.watch(ctx, w)
+		}
+		log.Warn().Err(err).Msg("cfgwatch: inotify not available, fallback to polling")
+	}
+	return t.poll(ctx)
+}
+
+func (t *T) poll(ctx context.Context) error {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.scan(true)
+		}
+	}
+}
+
+func (t *T) watch(ctx context.Context, w *fsnotify.Watcher) error {
+	for _, dir := range t.dirs() {
+		t.addWatch(w, dir)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn().Err(err).Msg("cfgwatch")
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op&fsnotify.Create != 0 {
+				if fi, err := os.Stat(ev.Name); err == nil && fi.IsDir() {
+					// new kind or namespace directory: watch it and its
+					// subdirectories, and check the files created before
+					// the watches.
+					t.addWatchTree(w, ev.Name)
+					t.scan(true)
+					continue
+				}
+			}
+			if isConfigFile(ev.Name) {
+				t.check(ev.Name)
+			}
+		}
+	}
+}
+
+func (t *T) addWatch(w *fsnotify.Watcher, dir string) {
+	if err := w.Add(dir); err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("cfgwatch: add watch")
+	}
+}
+
+// addWatchTree watches the directory and its subdirectories.
+func (t *T) addWatchTree(w *fsnotify.Watcher, dir string) {
+	_ = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err == nil && fi.IsDir() {
+			t.addWatch(w, p)
+		}
+		return nil
+	})
+}
+
+// dirs returns the existing directories hosting configuration files.
+func (t *T) dirs() []string {
+	l := []string{t.dir}
+	for _, pattern := range []string{"*", "namespaces", "namespaces/*", "namespaces/*/*"} {
+		m, _ := filepath.Glob(filepath.Join(t.dir, pattern))
+		for _, p := range m {
+			if fi, err := os.Stat(p); err == nil && fi.IsDir() {
+				l = append(l, p)
+			}
+		}
+	}
+	return l
+}
+
+// files returns the installed configuration files.
+func (t *T) files() []string {
+	l := make([]string, 0)
+	for _, pattern := range []string{"*.conf", "*/*.conf", "namespaces/*/*/*.conf"} {
+		m, _ := filepath.Glob(filepath.Join(t.dir, pattern))
+		for _, p := range m {
+			if isConfigFile(p) {
+				l = append(l, p)
+			}
+		}
+	}
+	return l
+}
+
+//
+// scan checks all the configuration files, and the removal of the known
+// ones. The events are published only if publish is true, so the
+// initial scan only records the checksums.
+//
+func (t *T) scan(publish bool) {
+	seen := make(map[string]interface{})
+	for _, p := range t.files() {
+		seen[p] = nil
+		if publish {
+			t.check(p)
+		} else if sum, err := Checksum(p); err == nil {
+			t.setSum(p, sum)
+		}
+	}
+	for _, p := range t.known() {
+		if _, ok := seen[p]; !ok {
+			t.check(p)
+		}
+	}
+}
+
+// check publishes an event if the configuration file checksum changed
+// since the last check.
+func (t *T) check(p string) {
+	objectPath, err := t.objectPath(p)
+	if err != nil {
+		return
+	}
+	sum, err := Checksum(p)
+	switch {
+	case os.IsNotExist(err):
+		if _, ok := t.getSum(p); !ok {
+			return
+		}
+		t.delSum(p)
+		t.publish(Event{Path: objectPath, File: p, Deleted: true, Time: time.Now()})
+		return
+	case errors.Is(err, errEmpty):
+		// wait for the write event
+		return
+	case err != nil:
+		log.Warn().Err(err).Str("file", p).Msg("cfgwatch: checksum")
+		return
+	}
+	if prev, ok := t.getSum(p); ok && prev == sum {
+		return
+	}
+	t.setSum(p, sum)
+	if _, err := xconfig.NewObject(p); err != nil {
+		log.Warn().Err(err).Str("file", p).Msg("cfgwatch: ignore the unparsable configuration")
+		return
+	}
+	t.publish(Event{Path: objectPath, File: p, Checksum: sum, Time: time.Now()})
+}
+
+func (t *T) publish(ev Event) {
+	log.Debug().Stringer("path", ev.Path).Bool("deleted", ev.Deleted).Msg("cfgwatch: configuration changed")
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range t.subscribers {
+		select {
+		case c <- ev:
+		default:
+			log.Warn().Stringer("path", ev.Path).Msg("cfgwatch: subscriber queue full, event dropped")
+		}
+	}
+}
+
+func (t *T) closeSubscribers() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range t.subscribers {
+		close(c)
+	}
+	t.subscribers = nil
+}
+
+func (t *T) getSum(p string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.sums[p]
+	return s, ok
+}
+
+func (t *T) setSum(p, sum string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sums[p] = sum
+}
+
+func (t *T) delSum(p string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sums, p)
+}
+
+func (t *T) known() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l := make([]string, 0, len(t.sums))
+	for p := range t.sums {
+		l = append(l, p)
+	}
+	return l
+}
+
+// objectPath returns the object path of a configuration file. The
+// node.conf file is not an object configuration.
+func (t *T) objectPath(p string) (path.T, error) {
+	rel, err := filepath.Rel(t.dir, p)
+	if err != nil {
+		return path.T{}, err
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, ".conf"))
+	if rel == "node" {
+		return path.T{}, fmt.Errorf("%s is not an object configuration", p)
+	}
+	rel = strings.TrimPrefix(rel, "namespaces/")
+	return path.Parse(rel)
+}
+
+// isConfigFile returns false for the temporary files created by the
+// configuration writers and editors.
+func isConfigFile(p string) bool {
+	base := filepath.Base(p)
+	return strings.HasSuffix(base, ".conf") && !strings.HasPrefix(base, ".")
+}
+
+// Checksum returns the md5 digest of the configuration file. An empty
+// file is refused, as its writer is usually not done yet.
+func Checksum(p string) (string, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	if len(b) == 0 {
+		return "", errEmpty
+	}
+	return fmt.Sprintf("%x", md5.Sum(b)), nil
+}
@@ -0,0 +1,88 @@
+package cfgwatch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitEvent(t *testing.T, c <-chan Event) Event {
+	select {
+	case ev := <-c:
+		return ev
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no event received")
+	}
+	return Event{}
+}
+
+func TestWatch(t *testing.T) {
+	cases := map[string]bool{
+		"inotify": false,
+		"polling": true,
+	}
+	for name, polling := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cfgwatch")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			installed := filepath.Join(dir, "s1.conf")
+			require.NoError(t, ioutil.WriteFile(installed, []byte("[DEFAULT]\nnodes = n1\n"), 0644))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "node.conf"), []byte("[node]\n"), 0644))
+
+			w := New(WithDir(dir), WithPolling(polling), WithPollInterval(50*time.Millisecond))
+			events := w.Subscribe()
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- w.Run(ctx) }()
+			defer func() {
+				cancel()
+				<-done
+			}()
+
+			// let the initial scan record the installed files
+			time.Sleep(200 * time.Millisecond)
+
+			require.NoError(t, ioutil.WriteFile(installed, []byte("[DEFAULT]\nnodes = n1 n2\n"), 0644))
+			ev := waitEvent(t, events)
+			assert.Equal(t, "s1", ev.Path.String())
+			assert.NotEmpty(t, ev.Checksum)
+			assert.False(t, ev.Deleted)
+
+			nsDir := filepath.Join(dir, "namespaces", "ns1", "cfg")
+			require.NoError(t, os.MkdirAll(nsDir, 0755))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(nsDir, "c1.conf"), []byte("[data]\n"), 0644))
+			ev = waitEvent(t, events)
+			assert.Equal(t, "ns1/cfg/c1", ev.Path.String())
+
+			require.NoError(t, os.Remove(installed))
+			ev = waitEvent(t, events)
+			assert.Equal(t, "s1", ev.Path.String())
+			assert.True(t, ev.Deleted)
+
+			select {
+			case ev := <-events:
+				assert.Failf(t, "unexpected event", "%+v", ev)
+			case <-time.After(200 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestObjectPath(t *testing.T) {
+	w := New(WithDir("/etc/opensvc"))
+	p, err := w.objectPath("/etc/opensvc/namespaces/ns1/svc/s1.conf")
+	require.NoError(t, err)
+	assert.Equal(t, "ns1/svc/s1", p.String())
+	p, err = w.objectPath("/etc/opensvc/cluster.conf")
+	require.NoError(t, err)
+	assert.Equal(t, "cluster", p.String())
+	_, err = w.objectPath("/etc/opensvc/node.conf")
+	assert.Error(t, err)
+}
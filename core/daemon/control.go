@@ -0,0 +1,254 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/systemd"
+	"opensvc.com/opensvc/util/waitfor"
+)
+
+var (
+	// UnitName is the name of the systemd unit of the agent daemon.
+	UnitName = "opensvc-agent.service"
+
+	// StartTimeout is the maximum duration Start waits for the daemon
+	// to be running.
+	StartTimeout = 10 * time.Second
+
+	// StopTimeout is the maximum duration Stop waits for the daemon to
+	// exit.
+	StopTimeout = DefaultShutdownTimeout + 5*time.Second
+
+	probeTimeout = time.Second
+
+	// pidFile is the pid file opened and locked by the running daemon.
+	pidFile *os.File
+)
+
+// PidFile returns the path of the file holding the pid of the running
+// daemon.
+func PidFile() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "osvcd.pid")
+}
+
+// OutputFile returns the path of the file the stdout and stderr of the
+// daemon spawned by Start are redirected to.
+func OutputFile() string {
+	return filepath.Join(rawconfig.Node.Paths.Log, "osvcd.out")
+}
+
+// sockets returns the paths of the api unix domain sockets.
+func sockets() []string {
+	return []string{
+		filepath.Join(rawconfig.Node.Paths.Var, "lsnr", "h2.sock"),
+		filepath.Join(rawconfig.Node.Paths.Var, "lsnr", "lsnr.sock"),
+	}
+}
+
+//
+// Pid returns the pid of the daemon process recorded in the pid file, or
+// zero if the pid file is absent or not locked. The running daemon holds
+// an exclusive lock on its pid file, so a stale pid file, even recording
+// the pid of a live process reused by the system, is not trusted.
+//
+func Pid() int {
+	f, err := os.Open(PidFile())
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err == nil {
+		// no daemon holds the lock
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		return 0
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+	return pid
+}
+
+// IsRunning returns true if the pid file process is alive, or if the api
+// sockets accept connections.
+func IsRunning() bool {
+	if Pid() > 0 {
+		return true
+	}
+	return probe()
+}
+
+// probe returns true if one of the api sockets accepts connections.
+func probe() bool {
+	for _, p := range sockets() {
+		conn, err := net.DialTimeout("unix", p, probeTimeout)
+		if err != nil {
+			continue
+		}
+		_ = conn.Close()
+		return true
+	}
+	return false
+}
+
+// isSystemdManaged returns true if the daemon lifecycle is delegated to
+// the systemd agent unit.
+func isSystemdManaged() bool {
+	if !capabilities.Has(systemd.NodeCapability) {
+		return false
+	}
+	return systemd.IsUnitLoaded(UnitName)
+}
+
+func systemctl(action string) error {
+	cmd := command.New(
+		command.WithName("systemctl"),
+		command.WithVarArgs(action, UnitName),
+		command.WithBufferedStderr(),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s %s: %w: %s", action, UnitName, err, cmd.Stderr())
+	}
+	return nil
+}
+
+//
+// Start starts the daemon, using systemd if the agent unit is installed,
+// or spawning a detached "om daemon start --foreground" process, with its
+// stdout and stderr redirected to OutputFile. It returns when the daemon
+// is running, or after StartTimeout.
+//
+func Start() error {
+	if IsRunning() {
+		return nil
+	}
+	if isSystemdManaged() {
+		return systemctl("start")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(OutputFile()), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(OutputFile(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	cmd := exec.Command(exe, "daemon", "start", "--foreground")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// the daemon outlives this process
+	_ = cmd.Process.Release()
+	err = waitfor.WaitFor(context.Background(), 100*time.Millisecond, StartTimeout, func() (bool, error) {
+		return IsRunning(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("daemon start: %w", err)
+	}
+	return nil
+}
+
+//
+// Stop stops the daemon, using systemd if the agent unit is installed,
+// or sending a SIGTERM signal to the pid file process. It returns when
+// the daemon process is gone, or after StopTimeout.
+//
+func Stop() error {
+	if isSystemdManaged() {
+		return systemctl("stop")
+	}
+	pid := Pid()
+	if pid == 0 {
+		if probe() {
+			return fmt.Errorf("daemon stop: the api sockets are served by a process without pid file %s", PidFile())
+		}
+		return nil
+	}
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("daemon stop: kill %d: %w", pid, err)
+	}
+	err := waitfor.WaitFor(context.Background(), 100*time.Millisecond, StopTimeout, func() (bool, error) {
+		return Pid() == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("daemon stop: %w", err)
+	}
+	return nil
+}
+
+// Restart stops then starts the daemon.
+func Restart() error {
+	if isSystemdManaged() {
+		return systemctl("restart")
+	}
+	if err := Stop(); err != nil {
+		return err
+	}
+	return Start()
+}
+
+//
+// writePidFile writes the daemon pid to the pid file, and keeps it open
+// with an exclusive lock until removePidFile. It returns ErrRunning if
+// another daemon holds the lock.
+//
+func writePidFile() error {
+	p := PidFile()
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if err == syscall.EWOULDBLOCK {
+			return ErrRunning
+		}
+		return err
+	}
+	if err := f.Truncate(0); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if _, err := fmt.Fprintln(f, os.Getpid()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	pidFile = f
+	return nil
+}
+
+// removePidFile removes the pid file, then releases its lock.
+func removePidFile() {
+	_ = os.Remove(PidFile())
+	if pidFile != nil {
+		_ = pidFile.Close()
+		pidFile = nil
+	}
+}
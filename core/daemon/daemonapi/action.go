@@ -0,0 +1,152 @@
+package daemonapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rbac"
+)
+
+type (
+	// actionResponse is the body of the object and node actions
+	// responses: the exit code and outputs of the action command.
+	actionResponse struct {
+		Status int    `json:"status"`
+		Out    string `json:"out"`
+		Err    string `json:"err"`
+	}
+)
+
+var (
+	// executable returns the path of the om command executing the
+	// actions.
+	executable = os.Executable
+)
+
+//
+// postObjectAction executes the action on the local instance of the
+// object, if the requester is granted the admin role on the object
+// namespace.
+//
+func (t *Server) postObjectAction(w http.ResponseWriter, r *http.Request) {
+	var options postObjectActionOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, err := path.Parse(options.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path: "+err.Error())
+		return
+	}
+	req, ok := authorize(w, r, rbac.RoleAdmin, p.Namespace)
+	if !ok {
+		return
+	}
+	args := append([]string{p.String()}, actionWords(options.Action)...)
+	t.runAction(w, r, req, args, options.Options)
+}
+
+// postNodeAction executes the node action, if the requester is granted
+// the root role.
+func (t *Server) postNodeAction(w http.ResponseWriter, r *http.Request) {
+	var options postNodeActionOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	req, ok := authorize(w, r, rbac.RoleRoot, "")
+	if !ok {
+		return
+	}
+	words := actionWords(options.Action)
+	if len(words) > 0 && words[0] == "node" {
+		words = words[1:]
+	}
+	args := append([]string{"node"}, words...)
+	t.runAction(w, r, req, args, options.Options)
+}
+
+//
+// runAction executes the om command args, with the action options as
+// flags, and serves its exit code and outputs. The command is executed
+// with --local, and on behalf of the requester, so its audit record
+// and its events are attributed to the requester.
+//
+func (t *Server) runAction(w http.ResponseWriter, r *http.Request, req requester, args []string, options map[string]interface{}) {
+	exe, err := executable()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	args = append(args, actionFlags(options)...)
+	args = append(args, "--local")
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(r.Context(), exe, args...)
+	cmd.Env = append(os.Environ(),
+		"OSVC_ACTION_ORIGIN=daemon",
+		"OSVC_REQUESTER="+req.Name,
+	)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	resp := actionResponse{}
+	if err := cmd.Run(); err != nil {
+		exitError, ok := err.(*exec.ExitError)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		resp.Status = exitError.ExitCode()
+	}
+	resp.Out = stdout.String()
+	resp.Err = stderr.String()
+	writeJSON(w, resp)
+}
+
+// actionWords returns the om command words of the action, like
+// [print config mtime] for "print_config_mtime".
+func actionWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '_'
+	})
+}
+
+//
+// actionFlags returns the command line flags of the action options,
+// sorted by name. The true booleans are set as flags without value,
+// the lists as repeated flags, and the false, empty and null values
+// are omitted.
+//
+func actionFlags(options map[string]interface{}) []string {
+	names := make([]string, 0, len(options))
+	for k := range options {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	l := make([]string, 0)
+	for _, k := range names {
+		flag := "--" + strings.ReplaceAll(k, "_", "-")
+		switch v := options[k].(type) {
+		case nil:
+		case bool:
+			if v {
+				l = append(l, flag)
+			}
+		case string:
+			if v != "" {
+				l = append(l, flag, v)
+			}
+		case []interface{}:
+			for _, e := range v {
+				l = append(l, flag, fmt.Sprint(e))
+			}
+		default:
+			l = append(l, flag, fmt.Sprint(v))
+		}
+	}
+	return l
+}
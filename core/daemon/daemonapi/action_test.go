@@ -0,0 +1,86 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/rbac"
+)
+
+// fakeExecutable installs a om executable printing its args and its
+// requester, and exiting with the code 3 if the --fail flag is set.
+func fakeExecutable(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	exe := filepath.Join(dir, "om")
+	script := "#!/bin/sh\necho \"$OSVC_REQUESTER: $*\"\ncase \"$*\" in *--fail*) exit 3;; esac\n"
+	require.NoError(t, ioutil.WriteFile(exe, []byte(script), 0755))
+	saved := executable
+	executable = func() (string, error) { return exe, nil }
+	return func() {
+		executable = saved
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAction(t *testing.T) {
+	defer fakeExecutable(t)()
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+
+	decode := func(b []byte, err error) actionResponse {
+		require.NoError(t, err)
+		var resp actionResponse
+		require.NoError(t, json.Unmarshal(b, &resp))
+		return resp
+	}
+
+	req := c.NewPostObjectAction()
+	req.ObjectSelector = "svc1"
+	req.Action = "print_config_mtime"
+	req.Options = map[string]interface{}{"format": "json", "force": true, "dry_run": false, "rid": []interface{}{"fs#1", "ip#1"}}
+	resp := decode(req.Do())
+	assert.Equal(t, 0, resp.Status)
+	assert.Equal(t, "root: svc1 print config mtime --force --format json --rid fs#1 --rid ip#1 --local\n", resp.Out)
+
+	nodeReq := c.NewPostNodeAction()
+	nodeReq.Action = "node print capabilities"
+	nodeReq.Options = map[string]interface{}{"fail": true}
+	resp = decode(nodeReq.Do())
+	assert.Equal(t, 3, resp.Status, "the exit code is served")
+	assert.Equal(t, "root: node print capabilities --fail --local\n", resp.Out)
+}
+
+func TestActionGrants(t *testing.T) {
+	defer fakeExecutable(t)()
+	savedLookup, savedGrants := lookupUser, usrGrants
+	defer func() { lookupUser, usrGrants = savedLookup, savedGrants }()
+	lookupUser = func(uid string) (*user.User, error) {
+		return &user.User{Uid: uid, Username: "u1"}, nil
+	}
+	usrGrants = func(name string) (rbac.Grants, error) {
+		return rbac.ParseGrants([]string{"admin:prod"})
+	}
+	srv := &Server{}
+	do := func(target, body string) int {
+		r := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		r = r.WithContext(withPeerUID(r.Context(), 1000))
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, do("/object_action", `{"path": "prod/svc/s1", "action": "start"}`))
+	assert.Equal(t, http.StatusForbidden, do("/object_action", `{"path": "test/svc/s1", "action": "start"}`))
+	assert.Equal(t, http.StatusForbidden, do("/node_action", `{"action": "checks"}`), "the node actions require the root role")
+}
@@ -0,0 +1,80 @@
+package daemonapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rbac"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/stringslice"
+	"opensvc.com/opensvc/util/syspower"
+)
+
+var (
+	// splitAction commits the suicide of the local node, with the
+	// node.split_action method.
+	splitAction = func(action string) error {
+		if action == "reboot" {
+			return syspower.ForceReboot()
+		}
+		return syspower.Crash()
+	}
+)
+
+//
+// postArbitrate votes for the segment of the winner node, if the
+// requester is granted the root role, no arbitrator is configured and
+// the dataset reports a cluster split. If the local node is not in the winner segment and
+// cluster.quorum is set, the local node applies its split action after
+// the response is sent.
+//
+func (t *Server) postArbitrate(w http.ResponseWriter, r *http.Request) {
+	var options postArbitrateOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleRoot, ""); !ok {
+		return
+	}
+	config := object.NewNode().MergedConfig()
+	for _, section := range config.SectionStrings() {
+		if strings.HasPrefix(section, "arbitrator#") {
+			writeError(w, http.StatusConflict, "the splits are resolved by the arbitrators vote")
+			return
+		}
+	}
+	winner := strings.ToLower(options.Winner)
+	data := t.Data.Get()
+	if !stringslice.Has(winner, data.Cluster.Nodes) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s is not a cluster node: %s", options.Winner, strings.Join(data.Cluster.Nodes, " ")))
+		return
+	}
+	split := data.Cluster.Split
+	if !split.Split {
+		writeError(w, http.StatusConflict, "no cluster split detected")
+		return
+	}
+	if stringslice.Has(winner, split.Segment) {
+		log.Info().Str("winner", winner).Strs("segment", split.Segment).Msg("api: split arbitrated, the local segment wins")
+		writeJSON(w, infoResponse{Info: "the local segment wins: " + strings.Join(split.Segment, " ")})
+		return
+	}
+	if !config.GetBool(key.New("cluster", "quorum")) {
+		log.Warn().Str("winner", winner).Strs("segment", split.Segment).Msg("api: split arbitrated, the local segment loses")
+		writeJSON(w, infoResponse{Info: "the local segment loses, no split action as cluster.quorum is not set"})
+		return
+	}
+	action := config.GetString(key.New("node", "split_action"))
+	log.Warn().Str("winner", winner).Strs("segment", split.Segment).Str("action", action).Msg("api: split arbitrated, the local segment loses")
+	writeJSON(w, infoResponse{Info: "the local segment loses, split action " + action})
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	if err := splitAction(action); err != nil {
+		log.Error().Err(err).Str("action", action).Msg("api: split action")
+	}
+}
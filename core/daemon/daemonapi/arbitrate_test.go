@@ -0,0 +1,64 @@
+package daemonapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestArbitrate(t *testing.T) {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Etc, 0700))
+	cf := filepath.Join(rawconfig.Node.Paths.Etc, "cluster.conf")
+	require.NoError(t, ioutil.WriteFile(cf, []byte("[cluster]\nquorum = true\n"), 0600))
+
+	var actions []string
+	saved := splitAction
+	defer func() { splitAction = saved }()
+	splitAction = func(action string) error {
+		actions = append(actions, action)
+		return nil
+	}
+
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetCluster(cluster.Info{Nodes: []string{"n1", "n2"}})
+	setBeating := func(beating bool) {
+		data.SetHeartbeat("hb#1", cluster.HeartbeatThreadStatus{
+			Peers: map[string]cluster.HeartbeatPeerStatus{"n2": {Beating: beating}},
+		})
+	}
+	srv := &Server{Data: data}
+	do := func(winner string) int {
+		r := httptest.NewRequest(http.MethodPost, "/arbitrate", strings.NewReader(`{"winner": "`+winner+`"}`))
+		r = r.WithContext(withPeerUID(r.Context(), 0))
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, r)
+		return w.Code
+	}
+
+	setBeating(true)
+	assert.Equal(t, http.StatusConflict, do("n1"), "no split is detected while the peer is beating")
+
+	setBeating(false)
+	require.True(t, data.Get().Cluster.Split.Split, "the split is detected from the heartbeats status")
+	assert.Equal(t, http.StatusBadRequest, do("n3"), "the winner must be a cluster node")
+	assert.Equal(t, http.StatusOK, do("N1"))
+	assert.Len(t, actions, 0, "the winner segment does not apply the split action")
+	assert.Equal(t, http.StatusOK, do("n2"))
+	assert.Equal(t, []string{"crash"}, actions, "the loser segment applies the split action")
+}
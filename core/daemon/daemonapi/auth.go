@@ -0,0 +1,150 @@
+package daemonapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/user"
+	"strconv"
+
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rbac"
+)
+
+type (
+	// requester is the authenticated user of a request.
+	requester struct {
+		Name   string
+		Grants rbac.Grants
+	}
+
+	// peerUIDKey is the context key of the unix user id of the client
+	// connection, recorded by connContext.
+	peerUIDKey struct{}
+)
+
+var (
+	// errUnauthenticated is returned by requesterOf if the connection
+	// credentials are not known.
+	errUnauthenticated = errors.New("unauthenticated")
+
+	// rootRequester is the requester of the connections of the root user.
+	rootRequester = requester{
+		Name:   "root",
+		Grants: rbac.Grants{{Role: rbac.RoleRoot}},
+	}
+
+	// lookupUser returns the unix user of a user id.
+	lookupUser = user.LookupId
+
+	// usrGrants returns the grants of the system/usr/<name> object.
+	usrGrants = func(name string) (rbac.Grants, error) {
+		p, err := path.New(name, "system", kind.Usr.String())
+		if err != nil {
+			return nil, err
+		}
+		o := object.NewUsr(p)
+		if !o.Exists() {
+			return nil, nil
+		}
+		return o.Grants()
+	}
+)
+
+//
+// connContext records the unix user id of the peer of the client
+// connection in the context of its requests. The credentials are read
+// from the socket, so they can not be forged by the client.
+//
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	uid, err := peerUID(c)
+	if err != nil {
+		return ctx
+	}
+	return withPeerUID(ctx, uid)
+}
+
+func withPeerUID(ctx context.Context, uid int) context.Context {
+	return context.WithValue(ctx, peerUIDKey{}, uid)
+}
+
+//
+// requesterOf returns the authenticated user of the request. The root
+// user is granted the root role. The other users are granted the grants
+// of the usr object named after them in the system namespace, if any.
+//
+func requesterOf(r *http.Request) (requester, error) {
+	uid, ok := r.Context().Value(peerUIDKey{}).(int)
+	if !ok {
+		return requester{}, errUnauthenticated
+	}
+	if uid == 0 {
+		return rootRequester, nil
+	}
+	u, err := lookupUser(strconv.Itoa(uid))
+	if err != nil {
+		return requester{}, fmt.Errorf("%w: uid %d: %s", errUnauthenticated, uid, err)
+	}
+	grants, err := usrGrants(u.Username)
+	if err != nil {
+		return requester{}, fmt.Errorf("%s grants: %w", u.Username, err)
+	}
+	return requester{Name: u.Username, Grants: grants}, nil
+}
+
+// authenticate writes the error response and returns false if the
+// requester of the request can not be authenticated.
+func authenticate(w http.ResponseWriter, r *http.Request) (requester, bool) {
+	req, err := requesterOf(r)
+	switch {
+	case errors.Is(err, errUnauthenticated):
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return req, false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+//
+// authorize writes the error response and returns false if the
+// requester is not authenticated, or not granted the role on the
+// namespace.
+//
+func authorize(w http.ResponseWriter, r *http.Request, role rbac.Role, namespace string) (requester, bool) {
+	req, ok := authenticate(w, r)
+	if !ok {
+		return req, false
+	}
+	if !req.Grants.Allow(role, namespace) {
+		msg := fmt.Sprintf("%s: %s: role %s required", req.Name, rbac.ErrForbidden, role)
+		if !role.IsCluster() {
+			msg += " on namespace " + namespace
+		}
+		writeError(w, http.StatusForbidden, msg)
+		return req, false
+	}
+	return req, true
+}
+
+//
+// allowKey writes the error response and returns false if the requester
+// is not authenticated, or not granted the role required to read or
+// write the keys of the object p.
+//
+func allowKey(w http.ResponseWriter, r *http.Request, p path.T, write bool) bool {
+	req, ok := authenticate(w, r)
+	if !ok {
+		return false
+	}
+	if err := req.Grants.AllowKey(p, write); err != nil {
+		writeError(w, http.StatusForbidden, req.Name+": "+err.Error())
+		return false
+	}
+	return true
+}
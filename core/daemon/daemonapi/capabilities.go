@@ -0,0 +1,28 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/rbac"
+	"opensvc.com/opensvc/util/capabilities"
+)
+
+//
+// postNodeScanCapabilities rescans and saves the node capabilities, if
+// the requester is granted the root role, and serves the new
+// capabilities list. The daemon drivers usage checks see the new list.
+//
+func (t *Server) postNodeScanCapabilities(w http.ResponseWriter, r *http.Request) {
+	var options postNodeScanCapabilitiesOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleRoot, ""); !ok {
+		return
+	}
+	if err := capabilities.Scan(); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, capabilities.Data())
+}
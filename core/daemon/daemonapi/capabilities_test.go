@@ -0,0 +1,39 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/capabilities"
+)
+
+func TestNodeScanCapabilities(t *testing.T) {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Var, 0700))
+	capabilities.Register(func() ([]string, error) {
+		return []string{"test.daemonapi"}, nil
+	})
+
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+	b, err := c.NewPostNodeScanCapabilities().Do()
+	require.NoError(t, err)
+	var l []string
+	require.NoError(t, json.Unmarshal(b, &l))
+	assert.Contains(t, l, "test.daemonapi")
+	assert.True(t, capabilities.Has("test.daemonapi"))
+	loaded, err := capabilities.Load()
+	require.NoError(t, err)
+	assert.Contains(t, loaded, "test.daemonapi", "the scan is saved")
+}
@@ -0,0 +1,37 @@
+package daemonapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/entrypoints/create"
+)
+
+// postObjectCreate installs the objects definitions of the request, all
+// or none, and serves the paths of the created and updated objects.
+func (t *Server) postObjectCreate(w http.ResponseWriter, r *http.Request) {
+	var options postObjectCreateOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	req := api.PostObjectCreate{
+		ObjectSelector: options.Path,
+		Namespace:      options.Namespace,
+		Template:       options.Template,
+		Config:         options.Config,
+		Env:            make(map[string]string),
+		Provision:      options.Provision,
+		Restore:        options.Restore,
+		Data:           options.Data,
+	}
+	for k, v := range options.Env {
+		req.Env[k] = fmt.Sprint(v)
+	}
+	result, err := create.Handle(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, result)
+}
@@ -0,0 +1,47 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestObjectCreate(t *testing.T) {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+
+	req := c.NewPostObjectCreate()
+	req.Data = map[string]interface{}{
+		"svc1": map[string]interface{}{"DEFAULT": map[string]interface{}{"nodes": "n1"}},
+	}
+	req.Env = map[string]string{"greet": "hello"}
+	b, err := req.Do()
+	require.NoError(t, err)
+	var result api.ObjectCreateResult
+	require.NoError(t, json.Unmarshal(b, &result))
+	assert.Equal(t, []string{"svc1"}, result.Created)
+	assert.FileExists(t, filepath.Join(rawconfig.Node.Paths.Etc, "svc1.conf"))
+
+	req = c.NewPostObjectCreate()
+	req.Data = map[string]interface{}{
+		"svc2": map[string]interface{}{"DEFAULT": map[string]interface{}{"foo": "bar"}},
+	}
+	_, err = req.Do()
+	assert.Error(t, err, "the invalid definitions are refused")
+	assert.NoFileExists(t, filepath.Join(rawconfig.Node.Paths.Etc, "svc2.conf"))
+}
@@ -0,0 +1,23 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+// getDaemonStatus serves the daemon dataset limited to the selected
+// objects and the requested sections.
+func (t *Server) getDaemonStatus(w http.ResponseWriter, r *http.Request) {
+	options := getDaemonStatusOptions{Selector: "**"}
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	filter := cluster.Filter{
+		Selector:  options.Selector,
+		Namespace: options.Namespace,
+		Relatives: options.Relatives,
+		Sections:  options.Sections,
+	}
+	writeJSON(w, filter.Apply(t.Data.Get()))
+}
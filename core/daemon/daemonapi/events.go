@@ -0,0 +1,168 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/util/jsondelta"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	//
+	// eventsFilter converts the dataset events into the events of the
+	// filtered dataset. The filtered stream has its own patch ids, so a
+	// dataset change not visible through the filter does not leave a gap
+	// in the client sequence.
+	//
+	eventsFilter struct {
+		filter cluster.Filter
+		source *jsondelta.Sequence
+		doc    []byte
+		id     uint64
+	}
+)
+
+// getEvents streams the "full" event of the dataset, then the "patch"
+// events of its changes, as server-sent events.
+func (t *Server) getEvents(w http.ResponseWriter, r *http.Request) {
+	options := getEventsOptions{Selector: "**", Full: true}
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if options.Namespace == "*" {
+		options.Namespace = ""
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	full, events, cancel := t.Data.Subscribe()
+	defer cancel()
+	var f *eventsFilter
+	filter := cluster.Filter{
+		Selector:  options.Selector,
+		Namespace: options.Namespace,
+		Relatives: options.Full,
+	}
+	if !filter.IsZero() {
+		var err error
+		if f, full, err = newEventsFilter(filter, full); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := writeEvent(w, full); err != nil {
+		return
+	}
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-t.done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if f != nil {
+				var err error
+				if e, ok, err = f.next(e); err != nil {
+					// the client resyncs on reconnect
+					log.Debug().Err(err).Msg("api: events filter")
+					return
+				} else if !ok {
+					continue
+				}
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e event.Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", b)
+	return err
+}
+
+// newEventsFilter returns the events filter initialized with the dataset
+// "full" event, and the "full" event of the filtered dataset.
+func newEventsFilter(filter cluster.Filter, full event.Event) (*eventsFilter, event.Event, error) {
+	t := &eventsFilter{
+		filter: filter,
+		source: jsondelta.NewSequence(*full.Data, full.ID, true),
+		id:     full.ID,
+	}
+	doc, err := t.apply()
+	if err != nil {
+		return nil, full, err
+	}
+	t.doc = doc
+	data := json.RawMessage(doc)
+	full.Data = &data
+	return t, full, nil
+}
+
+// apply returns the filtered dataset.
+func (t *eventsFilter) apply() ([]byte, error) {
+	var data cluster.Status
+	if err := json.Unmarshal(t.source.Bytes(), &data); err != nil {
+		return nil, err
+	}
+	return json.Marshal(t.filter.Apply(data))
+}
+
+//
+// next applies the dataset "patch" event, and returns the "patch" event of
+// the filtered dataset. The returned bool is false if the filtered
+// dataset did not change.
+//
+func (t *eventsFilter) next(e event.Event) (event.Event, bool, error) {
+	if e.Kind != daemondata.KindPatch {
+		return e, true, nil
+	}
+	if err := t.source.Apply(e.ID, jsondelta.NewPatch(*e.Data)); err != nil {
+		return e, false, err
+	}
+	doc, err := t.apply()
+	if err != nil {
+		return e, false, err
+	}
+	patch, err := jsondelta.Diff(t.doc, doc)
+	if err != nil {
+		return e, false, err
+	}
+	if len(patch) == 0 {
+		return e, false, nil
+	}
+	b, err := patch.Marshal()
+	if err != nil {
+		return e, false, err
+	}
+	t.doc = doc
+	t.id++
+	data := json.RawMessage(b)
+	return event.Event{
+		Kind:      daemondata.KindPatch,
+		ID:        t.id,
+		Timestamp: timestamp.Now(),
+		Data:      &data,
+	}, true, nil
+}
@@ -0,0 +1,68 @@
+package daemonapi
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/jsondelta"
+)
+
+func TestEvents(t *testing.T) {
+	svc1, _ := path.Parse("svc1")
+	svc2, _ := path.Parse("svc2")
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetInstanceStatus(svc1, instance.Status{Avail: status.Up})
+	data.SetInstanceStatus(svc2, instance.Status{Avail: status.Up})
+	c, stop := startServer(t, data)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	all, err := c.NewGetEvents().SetContext(ctx).Do()
+	require.NoError(t, err)
+	selected, err := c.NewGetEvents().SetSelector("svc1").SetContext(ctx).Do()
+	require.NoError(t, err)
+
+	full := <-all
+	assert.Equal(t, daemondata.KindFull, full.Kind)
+	assert.Equal(t, uint64(2), full.ID)
+	seq := jsondelta.NewSequence(*full.Data, full.ID, true)
+
+	full = <-selected
+	assert.Equal(t, daemondata.KindFull, full.Kind)
+	selectedSeq := jsondelta.NewSequence(*full.Data, full.ID, true)
+	var st cluster.Status
+	require.NoError(t, json.Unmarshal(selectedSeq.Bytes(), &st))
+	assert.Contains(t, st.Monitor.Services, "svc1")
+	assert.NotContains(t, st.Monitor.Services, "svc2")
+
+	data.SetInstanceStatus(svc2, instance.Status{Avail: status.Down})
+	data.SetInstanceStatus(svc1, instance.Status{Avail: status.Down})
+
+	for _, id := range []uint64{3, 4} {
+		e := <-all
+		assert.Equal(t, id, e.ID, "the unfiltered stream has the dataset ids")
+		require.NoError(t, seq.Apply(e.ID, jsondelta.NewPatch(*e.Data)))
+	}
+	assert.JSONEq(t, string(data.GetJSON()), string(seq.Bytes()))
+
+	// the svc2 change is only visible as a node generation change
+	for i := 0; i < 2; i++ {
+		e := <-selected
+		assert.Equal(t, daemondata.KindPatch, e.Kind)
+		require.NoError(t, selectedSeq.Apply(e.ID, jsondelta.NewPatch(*e.Data)))
+	}
+	st = cluster.Status{}
+	require.NoError(t, json.Unmarshal(selectedSeq.Bytes(), &st))
+	assert.Equal(t, status.Down, st.Monitor.Services["svc1"].Avail)
+	assert.NotContains(t, st.Monitor.Services, "svc2")
+}
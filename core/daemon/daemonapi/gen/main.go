@@ -0,0 +1,247 @@
+//
+// Command gen generates the daemon api server stubs from the openapi
+// document of the api:
+//
+//   - the handlers interface, with one method per operation, named after
+//     the method and the path, like getDaemonStatus for GET /daemon_status
+//   - the unimplemented handlers, answering 501 to the operations not
+//     served by the daemon
+//   - the routes, dispatching the requests to the handlers by path and
+//     method
+//   - the request options types, from the operations json request body
+//     schemas
+//   - the openapi document, served on GET /api/doc
+//
+// Usage:
+//
+//   go run ./gen -spec <openapi.yaml> -out <file.go> -package <name>
+//
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	schema struct {
+		Type       string             `yaml:"type"`
+		Format     string             `yaml:"format"`
+		Items      *schema            `yaml:"items"`
+		Properties map[string]*schema `yaml:"properties"`
+	}
+
+	operation struct {
+		Summary     string `yaml:"summary"`
+		RequestBody struct {
+			Content map[string]struct {
+				Schema schema `yaml:"schema"`
+			} `yaml:"content"`
+		} `yaml:"requestBody"`
+	}
+
+	document struct {
+		Paths map[string]map[string]operation `yaml:"paths"`
+	}
+)
+
+var (
+	// methods are the http methods of the operations, in generation order.
+	methods = []string{"get", "post", "put", "delete"}
+
+	// initialisms are the name parts rendered upper case.
+	initialisms = map[string]string{
+		"api": "API",
+		"id":  "ID",
+		"url": "URL",
+	}
+)
+
+func main() {
+	spec := flag.String("spec", "openapi.yaml", "the openapi document path")
+	out := flag.String("out", "openapi_gen.go", "the generated go file path")
+	pkg := flag.String("package", "daemonapi", "the generated go package name")
+	flag.Parse()
+	if err := generate(*spec, *out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func generate(spec, out, pkg string) error {
+	b, err := ioutil.ReadFile(spec)
+	if err != nil {
+		return err
+	}
+	var doc document
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("%s: %w", spec, err)
+	}
+	src, err := render(doc, b, pkg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
+
+// goName returns the camel case name of the words of s, separated by
+// slashes or underscores. The first word is lower case if exported is
+// false.
+func goName(s string, exported bool) string {
+	var buf strings.Builder
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '_' || r == '-'
+	})
+	for i, w := range words {
+		switch {
+		case i == 0 && !exported:
+			buf.WriteString(strings.ToLower(w))
+		case initialisms[w] != "":
+			buf.WriteString(initialisms[w])
+		default:
+			buf.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		}
+	}
+	return buf.String()
+}
+
+// handlerName returns the name of the handler of the operation.
+func handlerName(method, p string) string {
+	return method + goName(p, true)
+}
+
+// goType returns the go type of the json schema.
+func goType(s *schema) string {
+	if s == nil {
+		return "interface{}"
+	}
+	switch s.Type {
+	case "string":
+		if s.Format == "byte" {
+			// base64 encoded in json, like the go []byte
+			return "[]byte"
+		}
+		return "string"
+	case "boolean":
+		return "bool"
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+func sortedPaths(doc document) []string {
+	l := make([]string, 0, len(doc.Paths))
+	for p := range doc.Paths {
+		l = append(l, p)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func sortedKeys(m map[string]*schema) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func render(doc document, spec []byte, pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := func(format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format, args...)
+	}
+	paths := sortedPaths(doc)
+	w("// Code generated by gen from the api openapi document. DO NOT EDIT.\n\n")
+	w("package %s\n\n", pkg)
+	w("import \"net/http\"\n\n")
+
+	w("type (\n")
+	w("// handlers are the api operations handlers.\n")
+	w("handlers interface {\n")
+	for _, p := range paths {
+		for _, m := range methods {
+			if _, ok := doc.Paths[p][m]; ok {
+				w("%s(w http.ResponseWriter, r *http.Request)\n", handlerName(m, p))
+			}
+		}
+	}
+	w("}\n\n")
+	w("// unimplemented answers 501 to the operations not served by the daemon.\n")
+	w("unimplemented struct{}\n")
+	for _, p := range paths {
+		for _, m := range methods {
+			op, ok := doc.Paths[p][m]
+			if !ok {
+				continue
+			}
+			body := op.RequestBody.Content["application/json"].Schema
+			if len(body.Properties) == 0 {
+				continue
+			}
+			name := handlerName(m, p) + "Options"
+			w("\n// %s are the %s %s request options.\n", name, strings.ToUpper(m), p)
+			w("%s struct {\n", name)
+			for _, k := range sortedKeys(body.Properties) {
+				w("%s %s `json:\"%s\"`\n", goName(k, true), goType(body.Properties[k]), k)
+			}
+			w("}\n")
+		}
+	}
+	w(")\n\n")
+
+	for _, p := range paths {
+		for _, m := range methods {
+			if _, ok := doc.Paths[p][m]; !ok {
+				continue
+			}
+			w("func (unimplemented) %s(w http.ResponseWriter, r *http.Request) {\n", handlerName(m, p))
+			w("writeError(w, http.StatusNotImplemented, %q)\n", strings.ToUpper(m)+" "+p+" is not implemented")
+			w("}\n\n")
+		}
+	}
+
+	w("// routes registers the handlers of the operations, dispatched by method.\n")
+	w("func routes(mux *http.ServeMux, h handlers) {\n")
+	for _, p := range paths {
+		w("mux.HandleFunc(%q, methods(map[string]http.HandlerFunc{\n", p)
+		for _, m := range methods {
+			if _, ok := doc.Paths[p][m]; ok {
+				w("http.Method%s: h.%s,\n", goName(m, true), handlerName(m, p))
+			}
+		}
+		w("}))\n")
+	}
+	w("}\n\n")
+
+	w("// openapiDoc is the api openapi document.\n")
+	w("var openapiDoc = []byte(%s)\n", quote(spec))
+	return format.Source(buf.Bytes())
+}
+
+// quote returns the go string literal of b, a raw string literal if
+// possible.
+func quote(b []byte) string {
+	if !bytes.Contains(b, []byte("`")) && !bytes.Contains(b, []byte("\r")) {
+		return "`" + string(b) + "`"
+	}
+	return strconv.Quote(string(b))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated verifies the generated server stubs are up to date with
+// the openapi document.
+func TestGenerated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "openapi_gen.go")
+	require.NoError(t, generate("../../../client/api/openapi.yaml", out, "daemonapi"))
+
+	expected, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	actual, err := ioutil.ReadFile("../openapi_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual), "run go generate ./core/daemon/daemonapi")
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "getAPIDoc", handlerName("get", "/api/doc"))
+	assert.Equal(t, "postNodeScanCapabilities", handlerName("post", "/node_scan_capabilities"))
+	assert.Equal(t, "GlobalExpect", goName("global_expect", true))
+}
+
+func TestGoType(t *testing.T) {
+	assert.Equal(t, "string", goType(&schema{Type: "string"}))
+	assert.Equal(t, "[]byte", goType(&schema{Type: "string", Format: "byte"}))
+	assert.Equal(t, "[]string", goType(&schema{Type: "array", Items: &schema{Type: "string"}}))
+}
@@ -0,0 +1,80 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/rbac"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+type (
+	// joinResponse is the body of the join responses: the cluster.conf
+	// sections the joining node installs.
+	joinResponse struct {
+		Status int         `json:"status"`
+		Data   rawconfig.T `json:"data"`
+	}
+)
+
+var (
+	// clusterPath is the path of the cluster configuration object.
+	clusterPath, _ = path.Parse("cluster")
+)
+
+//
+// postJoin adds the node to the cluster nodes, if the requester is
+// granted the root role, and serves the cluster configuration the node
+// installs to complete its join. Adding an already member node is not
+// an error, so a node can retry an interrupted join.
+//
+func (t *Server) postJoin(w http.ResponseWriter, r *http.Request) {
+	var options postJoinOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleRoot, ""); !ok {
+		return
+	}
+	if err := hostname.Validate(options.Node); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ccfg := object.NewCcfg(clusterPath)
+	if err := ccfg.SetKeywords([]string{"cluster.nodes|=" + options.Node}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, joinResponse{Data: ccfg.Config().Raw()})
+}
+
+//
+// postLeave removes the node from the cluster nodes, if the requester
+// is granted the root role. Removing a node that is not a member is not
+// an error.
+//
+func (t *Server) postLeave(w http.ResponseWriter, r *http.Request) {
+	var options postLeaveOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleRoot, ""); !ok {
+		return
+	}
+	if options.Node == "" {
+		writeError(w, http.StatusBadRequest, "node is required")
+		return
+	}
+	if options.Node == t.nodename() {
+		writeError(w, http.StatusBadRequest, "the local node can not be removed from the cluster by its own daemon")
+		return
+	}
+	ccfg := object.NewCcfg(clusterPath)
+	if err := ccfg.SetKeywords([]string{"cluster.nodes-=" + options.Node}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, infoResponse{Info: options.Node + " removed from the cluster nodes"})
+}
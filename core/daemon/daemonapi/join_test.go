@@ -0,0 +1,58 @@
+package daemonapi
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func clusterNodes() []string {
+	return strings.Fields(object.NewCcfg(clusterPath).Config().Get(key.New("cluster", "nodes")))
+}
+
+func TestJoinLeave(t *testing.T) {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Etc, 0700))
+	cf := filepath.Join(rawconfig.Node.Paths.Etc, "cluster.conf")
+	require.NoError(t, ioutil.WriteFile(cf, []byte("[cluster]\nname = c1\nnodes = n1\n\n[hb#1]\ntype = unicast\n"), 0600))
+
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+
+	join := c.NewPostJoin()
+	join.NodeName = "n2"
+	b, err := join.Do()
+	require.NoError(t, err)
+	data, err := rawconfig.Parse(b)
+	require.NoError(t, err)
+	require.False(t, data.IsZero(), "the response holds the cluster configuration")
+	assert.Equal(t, []string{"n1", "n2"}, clusterNodes())
+
+	_, err = join.Do()
+	require.NoError(t, err, "a member can join again")
+	assert.Equal(t, []string{"n1", "n2"}, clusterNodes())
+
+	join.NodeName = "N3"
+	_, err = join.Do()
+	assert.Error(t, err, "the node name is validated")
+
+	leave := c.NewPostLeave()
+	leave.NodeName = "n2"
+	_, err = leave.Do()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, clusterNodes())
+}
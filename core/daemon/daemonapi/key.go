@@ -0,0 +1,93 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+)
+
+// keystore returns the keystore object of the path option, or writes the
+// error response and returns nil if the path is invalid, not a keystore
+// kind, or not an existing object.
+func keystore(w http.ResponseWriter, s string) (path.T, object.Keystorer) {
+	p, err := path.Parse(s)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path: "+err.Error())
+		return p, nil
+	}
+	o, ok := object.NewFromPath(p).(object.Keystorer)
+	if !ok {
+		writeError(w, http.StatusBadRequest, p.String()+": not a keystore object")
+		return p, nil
+	}
+	if !o.(object.Baser).Exists() {
+		writeError(w, http.StatusNotFound, p.String()+": object not found")
+		return p, nil
+	}
+	return p, o
+}
+
+// getKey serves the decoded value of an object key, if the requester is
+// granted the key read.
+func (t *Server) getKey(w http.ResponseWriter, r *http.Request) {
+	var options getKeyOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, o := keystore(w, options.Path)
+	if o == nil || !allowKey(w, r, p, false) {
+		return
+	}
+	b, err := o.Decode(object.OptsDecode{Key: options.Key})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := api.CheckKeySize(b); err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	writeJSON(w, api.KeyData{Data: b})
+}
+
+// postKey adds or changes an object key, if the requester is granted the
+// key write.
+func (t *Server) postKey(w http.ResponseWriter, r *http.Request) {
+	var options postKeyOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if err := api.CheckKeySize(options.Data); err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	p, o := keystore(w, options.Path)
+	if o == nil || !allowKey(w, r, p, true) {
+		return
+	}
+	if err := o.Change(object.OptsAdd{Key: options.Key, Value: string(options.Data)}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, infoResponse{Info: "key " + options.Key + " changed"})
+}
+
+// deleteKey removes an object key, if the requester is granted the key
+// write.
+func (t *Server) deleteKey(w http.ResponseWriter, r *http.Request) {
+	var options deleteKeyOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, o := keystore(w, options.Path)
+	if o == nil || !allowKey(w, r, p, true) {
+		return
+	}
+	if err := o.Remove(object.OptsRemove{Key: options.Key}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, infoResponse{Info: "key " + options.Key + " removed"})
+}
@@ -0,0 +1,97 @@
+package daemonapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/rbac"
+)
+
+func setupKeystore(t *testing.T) func() {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	p, _ := path.Parse("prod/cfg/c1")
+	require.NoError(t, object.NewCfg(p).Add(object.OptsAdd{Key: "k0", Value: "v0"}))
+	return func() {
+		rawconfig.Load(map[string]string{})
+		os.RemoveAll(root)
+	}
+}
+
+func TestKey(t *testing.T) {
+	defer setupKeystore(t)()
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+
+	value := []byte{0, 1, 2, 255}
+	post := c.NewPostKey()
+	post.Path = "prod/cfg/c1"
+	post.Key = "k1"
+	post.Data = value
+	_, err := post.Do()
+	require.NoError(t, err)
+
+	get := c.NewGetKey()
+	get.Path = "prod/cfg/c1"
+	get.Key = "k1"
+	b, err := get.Data()
+	require.NoError(t, err)
+	assert.Equal(t, value, b, "the binary values are transferred unaltered")
+
+	del := c.NewDeleteKey()
+	del.Path = "prod/cfg/c1"
+	del.Key = "k1"
+	_, err = del.Do()
+	require.NoError(t, err)
+	_, err = get.Data()
+	assert.Error(t, err, "the key is removed")
+
+	get.Path = "prod/cfg/c2"
+	_, err = get.Data()
+	assert.Error(t, err, "the object does not exist")
+
+	sel := c.NewGetObjectSelector()
+	sel.ObjectSelector = "prod/cfg/*"
+	b, err = sel.Do()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["prod/cfg/c1"]`, string(b))
+}
+
+func TestKeyGrants(t *testing.T) {
+	defer setupKeystore(t)()
+	savedLookup, savedGrants := lookupUser, usrGrants
+	defer func() { lookupUser, usrGrants = savedLookup, savedGrants }()
+	lookupUser = func(uid string) (*user.User, error) {
+		return &user.User{Uid: uid, Username: "u1"}, nil
+	}
+	usrGrants = func(name string) (rbac.Grants, error) {
+		return rbac.ParseGrants([]string{"guest:prod"})
+	}
+	srv := &Server{}
+	do := func(method string, withUID bool) int {
+		r := httptest.NewRequest(method, "/key", strings.NewReader(`{"path": "prod/cfg/c1", "key": "k0", "data": "djE="}`))
+		if withUID {
+			r = r.WithContext(withPeerUID(r.Context(), 1000))
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, true), "a guest reads the cfg keys")
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPost, true), "a guest can not change a key")
+	assert.Equal(t, http.StatusForbidden, do(http.MethodDelete, true), "a guest can not remove a key")
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, false), "the requester credentials are required")
+}
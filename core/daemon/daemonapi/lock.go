@@ -0,0 +1,118 @@
+package daemonapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rbac"
+	"opensvc.com/opensvc/util/timestamp"
+	"opensvc.com/opensvc/util/waitfor"
+)
+
+var (
+	// lockSettleInterval is the delay between two verifications of the
+	// peers agreement on a lock.
+	lockSettleInterval = 100 * time.Millisecond
+
+	// lockSettleTimeout is the maximum duration of the peers agreement on
+	// a lock: four default heartbeat intervals.
+	lockSettleTimeout = 20 * time.Second
+)
+
+//
+// postObjectLock acquires the action lock of the object for the session,
+// if the requester is granted the admin role on the object namespace.
+//
+// The lock is refused with 409 if another session holds it, or if a peer
+// holds a lock or runs an action on the object. Otherwise the lock is
+// published to the peers, and served once the beating peers applied it.
+// The lock is released and refused if, meanwhile, a peer locked the
+// object first.
+//
+func (t *Server) postObjectLock(w http.ResponseWriter, r *http.Request) {
+	var options postObjectLockOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, err := path.Parse(options.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path: "+err.Error())
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleAdmin, p.Namespace); !ok {
+		return
+	}
+	if options.SessionID == "" || options.Intent == "" {
+		writeError(w, http.StatusBadRequest, "session_id and intent are required")
+		return
+	}
+	lock := cluster.Lock{
+		Intent:    options.Intent,
+		SessionID: options.SessionID,
+		PID:       options.Pid,
+		Created:   timestamp.Now(),
+	}
+	gen, err := t.Data.Lock(p, lock)
+	switch {
+	case errors.Is(err, cluster.ErrLocked):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	nodename := t.nodename()
+	var conflict error
+	err = waitfor.WaitFor(r.Context(), lockSettleInterval, lockSettleTimeout, func() (bool, error) {
+		data := t.Data.Get()
+		// compare the locks as published, so the peers compare the
+		// same creation times
+		own, ok := data.Monitor.Nodes[nodename].Locks[p.String()]
+		if !ok || own.SessionID != lock.SessionID {
+			conflict = fmt.Errorf("%w: lock released", cluster.ErrLocked)
+			return true, nil
+		}
+		settled := data.LockSettled(nodename, gen)
+		conflict = data.LockConflict(nodename, p.String(), &own)
+		return settled || conflict != nil, nil
+	})
+	switch {
+	case conflict != nil:
+	case errors.Is(err, waitfor.ErrTimeout):
+		conflict = fmt.Errorf("%w: the peers did not apply the lock in %s", cluster.ErrLocked, lockSettleTimeout)
+	case err != nil:
+		t.Data.Unlock(p, lock.SessionID)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if conflict != nil {
+		t.Data.Unlock(p, lock.SessionID)
+		writeError(w, http.StatusConflict, conflict.Error())
+		return
+	}
+	writeJSON(w, infoResponse{Info: fmt.Sprintf("%s locked for %s", p, lock.Intent)})
+}
+
+// deleteObjectLock releases the action lock of the object held by the
+// session, if the requester is granted the admin role on the object
+// namespace. Releasing a lock not held is not an error.
+func (t *Server) deleteObjectLock(w http.ResponseWriter, r *http.Request) {
+	var options deleteObjectLockOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, err := path.Parse(options.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path: "+err.Error())
+		return
+	}
+	if _, ok := authorize(w, r, rbac.RoleAdmin, p.Namespace); !ok {
+		return
+	}
+	t.Data.Unlock(p, options.SessionID)
+	writeJSON(w, infoResponse{Info: fmt.Sprintf("%s unlocked", p)})
+}
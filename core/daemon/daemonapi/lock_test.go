@@ -0,0 +1,82 @@
+package daemonapi
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestObjectLock(t *testing.T) {
+	savedInterval, savedTimeout := lockSettleInterval, lockSettleTimeout
+	defer func() { lockSettleInterval, lockSettleTimeout = savedInterval, savedTimeout }()
+	lockSettleInterval, lockSettleTimeout = 10*time.Millisecond, 500*time.Millisecond
+
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetHeartbeat("hb#1", cluster.HeartbeatThreadStatus{
+		Peers: map[string]cluster.HeartbeatPeerStatus{"n2": {Beating: true}},
+	})
+	c, stop := startServer(t, data)
+	defer stop()
+
+	// peer publishes its status, having applied the n1 generation
+	peer := func(locks map[string]cluster.Lock) {
+		gen := data.Get().Monitor.Nodes["n1"].Gen["n1"]
+		data.SetPeerStatus("n2", cluster.NodeStatus{Gen: map[string]uint64{"n1": gen}, Locks: locks})
+	}
+	lock := func(c *client.T, session string) error {
+		req := c.NewPostObjectLock()
+		req.Path = "svc1"
+		req.Intent = "start"
+		req.SessionID = session
+		req.PID = os.Getpid()
+		_, err := req.Do()
+		return err
+	}
+	unlock := func(session string) {
+		req := c.NewDeleteObjectLock()
+		req.Path = "svc1"
+		req.SessionID = session
+		_, err := req.Do()
+		require.NoError(t, err)
+	}
+	ackLater := func(locks map[string]cluster.Lock) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			peer(locks)
+		}()
+	}
+
+	ackLater(nil)
+	require.NoError(t, lock(c, "s1"), "the lock is served once applied by the beating peers")
+	err := lock(c, "s2")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locked: start held by pid")
+	unlock("s2")
+	assert.Contains(t, data.Get().Monitor.Nodes["n1"].Locks, "svc1", "another session lock is not released")
+	unlock("s1")
+	assert.Empty(t, data.Get().Monitor.Nodes["n1"].Locks)
+
+	err = lock(c, "s1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the peers did not apply the lock")
+	assert.Empty(t, data.Get().Monitor.Nodes["n1"].Locks, "the refused lock is released")
+
+	older := cluster.Lock{Intent: "stop", SessionID: "s3", Created: timestamp.New(time.Now().Add(-time.Second))}
+	ackLater(map[string]cluster.Lock{"svc1": older})
+	err = lock(c, "s1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locked: stop held by node n2 session s3", "a concurrent older peer lock wins")
+	assert.Empty(t, data.Get().Monitor.Nodes["n1"].Locks)
+
+	err = lock(c, "s1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "locked: stop held by node n2 session s3", "a peer lock is refused")
+}
@@ -0,0 +1,109 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/monstate"
+	"opensvc.com/opensvc/core/orchestjournal"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+const (
+	// globalExpectUnset is the global expect value clearing the
+	// orchestration in progress.
+	globalExpectUnset = "unset"
+)
+
+//
+// postObjectMonitor sets the local instance monitor states of the
+// object. A global expect starts an orchestration, recorded in the
+// orchestration journal so a daemon restart resumes it. A local expect
+// is saved in the monitor states, so a daemon restart restores it.
+//
+func (t *Server) postObjectMonitor(w http.ResponseWriter, r *http.Request) {
+	var options postObjectMonitorOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	p, err := path.Parse(options.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid path: "+err.Error())
+		return
+	}
+	m := t.Data.GetInstanceMonitor(p)
+	if options.State != "" {
+		m.Status = options.State
+		m.StatusUpdated = timestamp.Now()
+	}
+	if options.LocalExpect != "" {
+		m.LocalExpect = options.LocalExpect
+		if err := monstate.SetInstance(p.String(), monstate.NewInstanceState(m)); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if options.GlobalExpect != "" {
+		if err := journalGlobalExpect(orchestjournal.Intent{
+			Path:         p.String(),
+			Node:         t.Data.Nodename(),
+			GlobalExpect: options.GlobalExpect,
+		}); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		m.GlobalExpect = options.GlobalExpect
+		m.GlobalExpectUpdated = timestamp.Now()
+		if options.GlobalExpect == globalExpectUnset {
+			m.GlobalExpect = ""
+		}
+	}
+	t.Data.SetInstanceMonitor(p, m)
+	writeJSON(w, infoResponse{Info: "monitor states updated"})
+}
+
+//
+// postNodeMonitor sets the local node global expect. The global expect
+// starts an orchestration, recorded in the orchestration journal so a
+// daemon restart resumes it.
+//
+func (t *Server) postNodeMonitor(w http.ResponseWriter, r *http.Request) {
+	var options postNodeMonitorOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if options.GlobalExpect == "" {
+		writeError(w, http.StatusBadRequest, "global_expect is required")
+		return
+	}
+	if err := journalGlobalExpect(orchestjournal.Intent{
+		Node:         t.Data.Nodename(),
+		GlobalExpect: options.GlobalExpect,
+	}); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	m := t.Data.GetNodeMonitor()
+	m.GlobalExpect = options.GlobalExpect
+	m.GlobalExpectUpdated = timestamp.Now()
+	if options.GlobalExpect == globalExpectUnset {
+		m.GlobalExpect = ""
+	}
+	t.Data.SetNodeMonitor(m)
+	writeJSON(w, infoResponse{Info: "monitor states updated"})
+}
+
+// journalGlobalExpect records the orchestration intent, or removes it if
+// the global expect is unset.
+func journalGlobalExpect(i orchestjournal.Intent) error {
+	if i.GlobalExpect == globalExpectUnset {
+		return orchestjournal.End(i.Key())
+	}
+	if err := orchestjournal.Begin(i); err != nil {
+		return err
+	}
+	log.Info().Stringer("intent", i).Msg("api: orchestration journaled")
+	return nil
+}
@@ -0,0 +1,75 @@
+package daemonapi
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/monstate"
+	"opensvc.com/opensvc/core/orchestjournal"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestMonitor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	c, stop := startServer(t, data)
+	defer stop()
+	svc1, _ := path.Parse("svc1")
+
+	req := c.NewPostObjectMonitor()
+	req.ObjectSelector = "svc1"
+	req.GlobalExpect = "started"
+	_, err = req.Do()
+	require.NoError(t, err)
+	assert.Equal(t, "started", data.GetInstanceMonitor(svc1).GlobalExpect)
+
+	req = c.NewPostObjectMonitor()
+	req.ObjectSelector = "svc1"
+	req.State = "starting"
+	_, err = req.Do()
+	require.NoError(t, err)
+	m := data.GetInstanceMonitor(svc1)
+	assert.Equal(t, "starting", m.Status)
+	assert.Equal(t, "started", m.GlobalExpect, "the global expect is preserved")
+
+	nodeReq := c.NewPostNodeMonitor()
+	nodeReq.GlobalExpect = "frozen"
+	_, err = nodeReq.Do()
+	require.NoError(t, err)
+	assert.Equal(t, "frozen", data.GetNodeMonitor().GlobalExpect)
+
+	journal, err := orchestjournal.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "started", journal["svc1"].GlobalExpect)
+	assert.Equal(t, "frozen", journal["node:n1"].GlobalExpect)
+
+	req = c.NewPostObjectMonitor()
+	req.ObjectSelector = "svc1"
+	req.GlobalExpect = "unset"
+	_, err = req.Do()
+	require.NoError(t, err)
+	assert.Empty(t, data.GetInstanceMonitor(svc1).GlobalExpect)
+	journal, err = orchestjournal.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, journal, "svc1", "the unset orchestration is removed from the journal")
+
+	req = c.NewPostObjectMonitor()
+	req.ObjectSelector = "svc1"
+	req.LocalExpect = "started"
+	_, err = req.Do()
+	require.NoError(t, err)
+	assert.Equal(t, "started", data.GetInstanceMonitor(svc1).LocalExpect)
+	states, err := monstate.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "started", states.Instances["svc1"].LocalExpect, "the local expect is saved")
+}
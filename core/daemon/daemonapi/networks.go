@@ -0,0 +1,28 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/object"
+)
+
+//
+// getNetworks serves the usage and setup state of the networks on the
+// local node, indexed by network name. The name option restricts the
+// response to a network, and the verbose option adds the leases.
+//
+func (t *Server) getNetworks(w http.ResponseWriter, r *http.Request) {
+	var options getNetworksOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authenticate(w, r); !ok {
+		return
+	}
+	m := make(map[string]network.Status)
+	for _, st := range object.NewNode().NetworkStatus(options.Name, options.Verbose) {
+		m[st.Name] = st
+	}
+	writeJSON(w, m)
+}
@@ -0,0 +1,23 @@
+package daemonapi
+
+import (
+	"net/http"
+)
+
+type (
+	// nodeInfo is the information of a node used by the node selector
+	// expressions.
+	nodeInfo struct {
+		Labels map[string]string `json:"labels"`
+	}
+)
+
+// getNodesInfo serves the labels of the cluster nodes known by the
+// daemon, indexed by node name.
+func (t *Server) getNodesInfo(w http.ResponseWriter, r *http.Request) {
+	m := make(map[string]nodeInfo)
+	for node, st := range t.Data.Get().Monitor.Nodes {
+		m[node] = nodeInfo{Labels: st.Labels}
+	}
+	writeJSON(w, m)
+}
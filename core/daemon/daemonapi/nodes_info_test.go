@@ -0,0 +1,20 @@
+package daemonapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/nodeselector"
+)
+
+func TestNodesInfo(t *testing.T) {
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetNodeLabels(map[string]string{"az": "eu1"})
+	c, stop := startServer(t, data)
+	defer stop()
+
+	assert.Equal(t, []string{"n1"}, nodeselector.New("az=eu1", nodeselector.WithClient(c)).Expand())
+	assert.Empty(t, nodeselector.New("az=us1", nodeselector.WithClient(c)).Expand())
+}
@@ -0,0 +1,14 @@
+// +build !linux
+
+package daemonapi
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID is not supported on this os, so the requests are not
+// authenticated.
+func peerUID(c net.Conn) (int, error) {
+	return 0, errors.New("peer credentials are not supported on this os")
+}
@@ -0,0 +1,28 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/pool"
+)
+
+//
+// getPools serves the usage of the storage pools, indexed by pool name.
+// The name option restricts the response to a pool, and the verbose
+// option adds the volumes allocated from the pools.
+//
+func (t *Server) getPools(w http.ResponseWriter, r *http.Request) {
+	var options getPoolsOptions
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	if _, ok := authenticate(w, r); !ok {
+		return
+	}
+	m := make(map[string]pool.Status)
+	for _, st := range object.NewNode().ShowPoolsByName(options.Name, options.Verbose) {
+		m[st.Name] = st
+	}
+	writeJSON(w, m)
+}
@@ -0,0 +1,50 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestPoolsNetworks(t *testing.T) {
+	root, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Etc, 0700))
+	cf := filepath.Join(rawconfig.Node.Paths.Etc, "cluster.conf")
+	require.NoError(t, ioutil.WriteFile(cf, []byte("[network#n1]\ntype = bridge\nnetwork = 10.0.0.0/28\n"), 0600))
+
+	c, stop := startServer(t, daemondata.New())
+	defer stop()
+
+	t.Run("pools", func(t *testing.T) {
+		b, err := c.NewGetPools().SetName("default").Do()
+		require.NoError(t, err)
+		m := make(map[string]pool.Status)
+		require.NoError(t, json.Unmarshal(b, &m))
+		require.Contains(t, m, "default")
+		assert.Equal(t, "directory", m["default"].Type)
+		assert.NotContains(t, m, "shm", "the name option filters the pools")
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		b, err := c.NewGetNetworks().Do()
+		require.NoError(t, err)
+		m := make(map[string]network.Status)
+		require.NoError(t, json.Unmarshal(b, &m))
+		require.Contains(t, m, "n1")
+		assert.Equal(t, "10.0.0.0/28", m["n1"].Network)
+	})
+}
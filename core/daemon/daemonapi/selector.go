@@ -0,0 +1,18 @@
+package daemonapi
+
+import (
+	"net/http"
+
+	"opensvc.com/opensvc/core/object"
+)
+
+// getObjectSelector serves the paths of the local objects matching the
+// selector expression.
+func (t *Server) getObjectSelector(w http.ResponseWriter, r *http.Request) {
+	options := getObjectSelectorOptions{Selector: "**"}
+	if !decodeOptions(w, r, &options) {
+		return
+	}
+	paths := object.NewSelection(options.Selector, object.SelectionWithLocal(true)).Expand()
+	writeJSON(w, paths)
+}
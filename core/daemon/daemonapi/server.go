@@ -0,0 +1,254 @@
+//
+// Package daemonapi serves the daemon api to the local clients, on the
+// http/2 cleartext unix domain socket of the node.
+//
+// The handlers serve the daemon dataset, filtered by the request
+// options. The options are sent by the clients as a json body, even for
+// the GET requests.
+// The routes, the unimplemented handlers answering 501 and the request
+// options types are generated from the api openapi document.
+//
+package daemonapi
+
+//go:generate go run ./gen -spec ../../client/api/openapi.yaml -out openapi_gen.go -package daemonapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"opensvc.com/opensvc/core/apiv2"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+type (
+	//
+	// Server serves the daemon api:
+	//
+	//   GET  /api/doc        the api openapi document
+	//   GET  /daemon_status  the daemon dataset, filtered by the selector,
+	//                        namespace, relatives and sections options
+	//   GET  /events         the daemon dataset events stream, filtered by
+	//                        the selector, namespace and full options
+	//   POST /object_monitor the local instance monitor states of an object
+	//   POST /node_monitor   the local node global expect
+	//   GET  /key            the decoded value of a keystore object key
+	//   POST /key            add or change a keystore object key
+	//   DELETE /key          remove a keystore object key
+	//   GET  /object_selector the paths of the local objects selected
+	//   POST /object_create  install objects definitions, all or none
+	//   POST /object_action  execute an action on the local instance
+	//   POST /node_action    execute a node action
+	//   GET  /nodes_info     the labels of the cluster nodes
+	//   POST /node_scan_capabilities  rescan the node capabilities
+	//   POST /join           add a node to the cluster nodes, and serve
+	//                        the cluster configuration
+	//   POST /leave          remove a node from the cluster nodes
+	//   GET  /networks       the usage and setup state of the networks
+	//   GET  /pools          the usage of the storage pools
+	//   POST /arbitrate      vote for the segment of a node in a split
+	//   POST /object_lock    acquire the cluster-wide action lock of an
+	//                        object
+	//   DELETE /object_lock  release the action lock of an object
+	//
+	// The requests of the v2 agents clients, posted to the root path, are
+	// translated by the apiv2 layer.
+	//
+	// The requests addressed to a peer node by the o-node header are
+	// refused with 421: this daemon only serves the local node.
+	//
+	// The requester is the unix user of the socket peer process. The
+	// root user is granted the root role, and the other users the grants
+	// of their system/usr/<name> object. The key and action requests are
+	// authorized against these grants.
+	//
+	Server struct {
+		unimplemented
+
+		// Socket is the path of the unix domain socket.
+		Socket string
+
+		// Data is the dataset served.
+		Data *daemondata.T
+
+		// ctx is the context of the running server, done when the
+		// server is stopping.
+		ctx context.Context
+	}
+
+	// infoResponse is the body of the succeeded requests responses
+	// without data.
+	infoResponse struct {
+		Status int    `json:"status"`
+		Info   string `json:"info"`
+	}
+
+	// errorResponse is the body of the failed requests responses.
+	errorResponse struct {
+		Status int    `json:"status"`
+		Error  string `json:"error"`
+	}
+)
+
+var (
+	// MaxRequestSize is the maximum size of a request body.
+	MaxRequestSize int64 = 1024 * 1024
+
+	shutdownTimeout = 5 * time.Second
+
+	// v2Actions are the translations of the v2 actions served.
+	v2Actions = apiv2.Actions{
+		"daemon_status":  {Method: http.MethodGet, Raw: true},
+		"events":         {Method: http.MethodGet},
+		"node_monitor":   {Method: http.MethodPost},
+		"object_monitor": {Method: http.MethodPost, Options: map[string]string{"status": "state"}},
+	}
+)
+
+// DefaultSocket returns the path of the node api unix domain socket.
+func DefaultSocket() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "lsnr", "h2.sock")
+}
+
+// Handler returns the http handler serving the api requests.
+func (t *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	routes(mux, t)
+	return t.localOnly(v2Actions.Handler(mux))
+}
+
+//
+// localOnly refuses the requests addressed by the o-node header to a
+// peer node. This daemon does not relay the requests to its peers, so
+// the clients fanning out a request to many nodes get an error for the
+// nodes other than the local node, instead of executing the request
+// many times on the local node.
+//
+func (t *Server) localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch node := r.Header.Get("o-node"); {
+		case node == "", node == "*", node == "ANY", strings.EqualFold(node, t.nodename()):
+			next.ServeHTTP(w, r)
+		default:
+			writeError(w, http.StatusMisdirectedRequest, "node "+node+": the requests to the peer nodes are not relayed by the "+t.nodename()+" daemon")
+		}
+	})
+}
+
+// nodename returns the name of the local node.
+func (t *Server) nodename() string {
+	if t.Data != nil {
+		return t.Data.Nodename()
+	}
+	return hostname.Hostname()
+}
+
+// methods returns the handler dispatching the requests to the handlers
+// by method.
+func methods(m map[string]http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn, ok := m[r.Method]
+		if !ok {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// getAPIDoc serves the api openapi document.
+func (t *Server) getAPIDoc(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openapiDoc)
+}
+
+// done returns the channel closed when the server is stopping, so the
+// streaming handlers return before the server shutdown timeout.
+func (t *Server) done() <-chan struct{} {
+	if t.ctx == nil {
+		return nil
+	}
+	return t.ctx.Done()
+}
+
+//
+// decodeOptions decodes the request json body into the options. An empty
+// body leaves the options unchanged.
+//
+func decodeOptions(w http.ResponseWriter, r *http.Request, options interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestSize)
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(options); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid options: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Debug().Err(err).Msg("api: write response")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorResponse{Status: 1, Error: msg})
+}
+
+//
+// Run serves the api requests until the context is done or the listener
+// fails. The stale socket of a previous daemon is replaced.
+//
+func (t *Server) Run(ctx context.Context) error {
+	if t.Socket == "" {
+		t.Socket = DefaultSocket()
+	}
+	if err := os.MkdirAll(filepath.Dir(t.Socket), 0700); err != nil {
+		return err
+	}
+	if err := os.Remove(t.Socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// create the socket root only, so it is never exposed, even
+	// briefly, to other users
+	mask := syscall.Umask(0077)
+	l, err := net.Listen("unix", t.Socket)
+	syscall.Umask(mask)
+	if err != nil {
+		return err
+	}
+	t.ctx = ctx
+	srv := &http.Server{
+		Handler:     h2c.NewHandler(t.Handler(), &http2.Server{}),
+		ConnContext: connContext,
+	}
+	errC := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("api listener on %s", t.Socket)
+		errC <- srv.Serve(l)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errC:
+		return err
+	}
+}
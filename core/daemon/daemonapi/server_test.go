@@ -0,0 +1,122 @@
+package daemonapi
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+// startServer runs a server on a temporary socket, and returns a client
+// connected to it and the function stopping the server.
+func startServer(t *testing.T, data *daemondata.T) (*client.T, func()) {
+	dir, err := ioutil.TempDir("", "daemonapi")
+	require.NoError(t, err)
+	srv := &Server{
+		Socket: filepath.Join(dir, "h2.sock"),
+		Data:   data,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(srv.Socket)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	c, err := client.New(client.WithURL(srv.Socket))
+	require.NoError(t, err)
+	return c, func() {
+		cancel()
+		assert.NoError(t, <-done)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestDaemonStatus(t *testing.T) {
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	for _, s := range []string{"svc1", "svc2", "test/svc/svc3"} {
+		p, _ := path.Parse(s)
+		data.SetInstanceConfig(p, instance.Config{Checksum: "abc"})
+		data.SetInstanceStatus(p, instance.Status{Avail: status.Up})
+	}
+	c, stop := startServer(t, data)
+	defer stop()
+
+	get := func(req interface{ Do() ([]byte, error) }) cluster.Status {
+		b, err := req.Do()
+		require.NoError(t, err)
+		var st cluster.Status
+		require.NoError(t, json.Unmarshal(b, &st))
+		return st
+	}
+
+	st := get(c.NewGetDaemonStatus().SetSelector("**"))
+	assert.Len(t, st.Monitor.Services, 3)
+	assert.Equal(t, status.Up, st.Monitor.Services["svc1"].Avail)
+
+	st = get(c.NewGetDaemonStatus().SetSelector("svc1"))
+	assert.Len(t, st.Monitor.Services, 1)
+	assert.Len(t, st.Monitor.Nodes["n1"].Services.Status, 1)
+
+	st = get(c.NewGetDaemonStatus().SetSelector("**").SetNamespace("test"))
+	assert.Contains(t, st.Monitor.Services, "test/svc/svc3")
+	assert.Len(t, st.Monitor.Services, 1)
+
+	st = get(c.NewGetDaemonStatus().SetSelector("**").SetSections([]string{"nodes"}))
+	assert.Empty(t, st.Monitor.Services)
+	assert.Contains(t, st.Monitor.Nodes, "n1")
+}
+
+func TestRoutes(t *testing.T) {
+	srv := &Server{Data: daemondata.New(daemondata.WithNodename("n1"))}
+	do := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	w := do(http.MethodGet, "/api/doc")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, openapiDoc, w.Body.Bytes())
+
+	w = do(http.MethodPost, "/object_deregister")
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	var resp errorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "POST /object_deregister is not implemented", resp.Error)
+
+	w = do(http.MethodPost, "/daemon_status")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	for node, code := range map[string]int{"n1": http.StatusOK, "N1": http.StatusOK, "*": http.StatusOK, "n2": http.StatusMisdirectedRequest} {
+		w = httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/api/doc", nil)
+		r.Header.Set("o-node", node)
+		srv.Handler().ServeHTTP(w, r)
+		assert.Equal(t, code, w.Code, "o-node: %s", node)
+	}
+
+	w = httptest.NewRecorder()
+	body := strings.NewReader(`{"action": "daemon_status", "options": {"selector": "**"}}`)
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", body))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var st cluster.Status
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &st), "the v2 daemon_status response is the raw dataset")
+	assert.Contains(t, st.Monitor.Nodes, "n1")
+}
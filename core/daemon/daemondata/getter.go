@@ -0,0 +1,76 @@
+package daemondata
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type (
+	//
+	// StatusGetter returns the json encoded dataset to the in-process
+	// consumers of the daemon status api request, like the metrics
+	// exporter and the notifier.
+	//
+	StatusGetter struct {
+		Data *T
+	}
+
+	//
+	// EventsGetter opens an in-process dataset events stream, for the
+	// consumers of the daemon events api request, like the notifier. The
+	// streams are closed when Context is done.
+	//
+	EventsGetter struct {
+		Data    *T
+		Context context.Context
+	}
+)
+
+// Get returns the json encoded dataset.
+func (t StatusGetter) Get() ([]byte, error) {
+	return t.Data.GetJSON(), nil
+}
+
+//
+// GetRaw returns a stream of json encoded events: the "full" event of the
+// dataset, then the "patch" events of its changes. The stream is closed
+// when the context is done or the dataset is closed.
+//
+func (t EventsGetter) GetRaw() (chan []byte, error) {
+	ctx := t.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	full, events, cancel := t.Data.Subscribe()
+	b, err := json.Marshal(full)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	q := make(chan []byte, subscriberQueueLen)
+	q <- b
+	go func() {
+		defer close(q)
+		defer cancel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				b, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				select {
+				case q <- b:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return q, nil
+}
@@ -0,0 +1,419 @@
+//
+// Package daemondata holds the dataset served by the daemon api: the
+// cluster status as seen by the local node.
+//
+// The daemon threads update the dataset with the setters, and the api
+// handlers read copies with Get. Each change is published to the
+// subscribers as a "patch" event, holding the json-delta patch from the
+// previous dataset, so the event stream consumers can maintain their
+// copy without fetching the full dataset on each change.
+//
+package daemondata
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/orchestjournal"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/jsondelta"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	// T is the daemon dataset.
+	T struct {
+		nodename string
+
+		mu          sync.RWMutex
+		status      cluster.Status
+		doc         []byte
+		id          uint64
+		subscribers map[chan event.Event]interface{}
+	}
+)
+
+const (
+	// KindFull is the kind of the events holding the full dataset.
+	KindFull = "full"
+
+	// KindPatch is the kind of the events holding a dataset patch.
+	KindPatch = "patch"
+)
+
+var (
+	// subscriberQueueLen is the number of events buffered per subscriber.
+	subscriberQueueLen = 1000
+
+	// processExists returns true if the process of a lock holder is
+	// alive. The locks of the dead processes are replaced.
+	processExists = func(pid int) bool {
+		return pid > 0 && syscall.Kill(pid, 0) != syscall.ESRCH
+	}
+)
+
+// New allocates and returns a daemon dataset holding the local node
+// entry.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		nodename:    hostname.Hostname(),
+		subscribers: make(map[chan event.Event]interface{}),
+	}
+	_ = funcopt.Apply(t, opts...)
+	t.status.Monitor.Nodes = map[string]cluster.NodeStatus{
+		t.nodename: newNodeStatus(),
+	}
+	t.status.Monitor.Services = make(map[string]object.AggregatedStatus)
+	t.status.Monitor.State = "idle"
+	t.status.Monitor.Created = timestamp.Now()
+	t.doc, _ = json.Marshal(t.status)
+	return t
+}
+
+// WithNodename sets the name of the local node. The default is the node
+// hostname.
+func WithNodename(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.nodename = s
+		return nil
+	})
+}
+
+func newNodeStatus() cluster.NodeStatus {
+	return cluster.NodeStatus{
+		Gen:    make(map[string]uint64),
+		Labels: make(map[string]string),
+		Services: cluster.NodeServices{
+			Config: make(map[string]instance.Config),
+			Status: make(map[string]instance.Status),
+		},
+	}
+}
+
+// Nodename returns the name of the local node.
+func (t *T) Nodename() string {
+	return t.nodename
+}
+
+// Get returns a copy of the dataset.
+func (t *T) Get() cluster.Status {
+	var data cluster.Status
+	_ = json.Unmarshal(t.GetJSON(), &data)
+	return data
+}
+
+// GetJSON returns the json encoded dataset.
+func (t *T) GetJSON() []byte {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.doc
+}
+
+//
+// Subscribe returns the "full" event of the current dataset, the channel
+// receiving the "patch" events of the following changes, and the function
+// to call to unsubscribe. The events are dropped, with a warning, if the
+// subscriber does not consume them fast enough, in which case the
+// subscriber detects the patch id gap and resubscribes.
+//
+func (t *T) Subscribe() (event.Event, <-chan event.Event, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := make(chan event.Event, subscriberQueueLen)
+	t.subscribers[c] = nil
+	data := json.RawMessage(t.doc)
+	full := event.Event{
+		Kind:      KindFull,
+		ID:        t.id,
+		Timestamp: timestamp.Now(),
+		Data:      &data,
+	}
+	cancel := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subscribers[c]; ok {
+			delete(t.subscribers, c)
+			close(c)
+		}
+	}
+	return full, c, cancel
+}
+
+// Close closes the subscribers channels.
+func (t *T) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for c := range t.subscribers {
+		close(c)
+	}
+	t.subscribers = make(map[chan event.Event]interface{})
+}
+
+//
+// update applies fn to the dataset, and publishes the patch event if the
+// dataset changed. The local node dataset generation is incremented if
+// the local node entry changed, the applied generations of the peers
+// aside, so the heartbeats do not change the generations endlessly. The
+// cluster split status is recomputed from the heartbeats status.
+//
+func (t *T) update(fn func(*cluster.Status, *cluster.NodeStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	node := t.status.Monitor.Nodes[t.nodename]
+	before := marshalNoGen(node)
+	fn(&t.status, &node)
+	if after := marshalNoGen(node); string(after) != string(before) {
+		node.Gen[t.nodename]++
+	}
+	t.status.Monitor.Nodes[t.nodename] = node
+	t.status.Cluster.Split = t.status.DetectSplit()
+	doc, err := json.Marshal(t.status)
+	if err != nil {
+		log.Error().Err(err).Msg("daemondata: marshal")
+		return
+	}
+	patch, err := jsondelta.Diff(t.doc, doc)
+	if err != nil {
+		log.Error().Err(err).Msg("daemondata: diff")
+		return
+	}
+	if len(patch) == 0 {
+		return
+	}
+	b, err := patch.Marshal()
+	if err != nil {
+		log.Error().Err(err).Msg("daemondata: marshal patch")
+		return
+	}
+	t.doc = doc
+	t.id++
+	data := json.RawMessage(b)
+	t.publish(event.Event{
+		Kind:      KindPatch,
+		ID:        t.id,
+		Timestamp: timestamp.Now(),
+		Data:      &data,
+	})
+}
+
+func marshalNoGen(node cluster.NodeStatus) []byte {
+	node.Gen = nil
+	b, _ := json.Marshal(node)
+	return b
+}
+
+func (t *T) publish(e event.Event) {
+	for c := range t.subscribers {
+		select {
+		case c <- e:
+		default:
+			log.Warn().Uint64("id", e.ID).Msg("daemondata: slow subscriber, event dropped")
+		}
+	}
+}
+
+// SetCluster sets the cluster id, name and nodes.
+func (t *T) SetCluster(info cluster.Info) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		s.Cluster = info
+	})
+}
+
+// SetNodeFrozen sets the local node frozen timestamp. A zero timestamp
+// means thawed.
+func (t *T) SetNodeFrozen(tm timestamp.T) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		node.Frozen = tm
+		s.Monitor.Frozen = node.IsFrozen()
+	})
+}
+
+// SetNodeLabels sets the local node labels.
+func (t *T) SetNodeLabels(labels map[string]string) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		node.Labels = labels
+	})
+}
+
+// SetNodeMonitor sets the local node monitor states.
+func (t *T) SetNodeMonitor(m cluster.NodeMonitor) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		node.Monitor = m
+	})
+}
+
+// GetNodeMonitor returns the local node monitor states.
+func (t *T) GetNodeMonitor() cluster.NodeMonitor {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status.Monitor.Nodes[t.nodename].Monitor
+}
+
+// GetNodeStatus returns the local node entry of the dataset.
+func (t *T) GetNodeStatus() cluster.NodeStatus {
+	return t.Get().Monitor.Nodes[t.nodename]
+}
+
+// SetPeerStatus sets the entry of a peer node, as received from its
+// heartbeats, and records the peer generation applied by the local node.
+func (t *T) SetPeerStatus(node string, st cluster.NodeStatus) {
+	if node == t.nodename {
+		return
+	}
+	t.update(func(s *cluster.Status, local *cluster.NodeStatus) {
+		s.Monitor.Nodes[node] = st
+		local.Gen[node] = st.Gen[node]
+	})
+}
+
+// SetHeartbeat sets the status of the heartbeat named name, like hb#1.
+func (t *T) SetHeartbeat(name string, st cluster.HeartbeatThreadStatus) {
+	t.update(func(s *cluster.Status, _ *cluster.NodeStatus) {
+		if s.Heartbeats == nil {
+			s.Heartbeats = make(map[string]cluster.HeartbeatThreadStatus)
+		}
+		s.Heartbeats[name] = st
+	})
+}
+
+//
+// Lock records the action lock of the object p held by the session of l,
+// and returns the local node generation publishing it. An error wrapping
+// cluster.ErrLocked is returned if another session of a live process
+// holds the lock, or if a peer holds a lock or runs an action on the
+// object.
+//
+func (t *T) Lock(p path.T, l cluster.Lock) (uint64, error) {
+	var err error
+	k := p.String()
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		if cur, ok := node.Locks[k]; ok && cur.SessionID != l.SessionID && processExists(cur.PID) {
+			err = fmt.Errorf("%w: %s held by pid %d session %s", cluster.ErrLocked, cur.Intent, cur.PID, cur.SessionID)
+			return
+		}
+		if err = s.LockConflict(t.nodename, k, nil); err != nil {
+			return
+		}
+		if node.Locks == nil {
+			node.Locks = make(map[string]cluster.Lock)
+		}
+		node.Locks[k] = l
+	})
+	if err != nil {
+		return 0, err
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status.Monitor.Nodes[t.nodename].Gen[t.nodename], nil
+}
+
+// Unlock removes the action lock of the object p, if held by the session.
+func (t *T) Unlock(p path.T, sessionID string) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		k := p.String()
+		if cur, ok := node.Locks[k]; ok && cur.SessionID == sessionID {
+			delete(node.Locks, k)
+		}
+	})
+}
+
+// SetResumed sets the orchestrations resumed at the daemon startup.
+func (t *T) SetResumed(l []orchestjournal.Intent) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		s.Monitor.Resumed = l
+	})
+}
+
+// SetInstanceConfig sets the local instance configuration digest of the
+// object.
+func (t *T) SetInstanceConfig(p path.T, cfg instance.Config) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		node.Services.Config[p.String()] = cfg
+	})
+}
+
+//
+// SetInstanceStatus sets the local instance status of the object, and
+// the object aggregated status. The instance monitor states, owned by the
+// daemon, are preserved.
+//
+func (t *T) SetInstanceStatus(p path.T, st instance.Status) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		k := p.String()
+		st.Monitor = node.Services.Status[k].Monitor
+		node.Services.Status[k] = st
+		s.Monitor.Services[k] = aggregate(st)
+	})
+}
+
+// GetInstanceMonitor returns the local instance monitor states of the
+// object.
+func (t *T) GetInstanceMonitor(p path.T) instance.Monitor {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status.Monitor.Nodes[t.nodename].Services.Status[p.String()].Monitor
+}
+
+// SetInstanceMonitor sets the local instance monitor states of the
+// object.
+func (t *T) SetInstanceMonitor(p path.T, m instance.Monitor) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		k := p.String()
+		st := node.Services.Status[k]
+		st.Monitor = m
+		node.Services.Status[k] = st
+	})
+}
+
+// DelInstance removes the local instance of the object from the dataset.
+func (t *T) DelInstance(p path.T) {
+	t.update(func(s *cluster.Status, node *cluster.NodeStatus) {
+		k := p.String()
+		delete(node.Services.Config, k)
+		delete(node.Services.Status, k)
+		delete(s.Monitor.Services, k)
+	})
+}
+
+// Paths returns the paths of the objects having a local instance in the
+// dataset.
+func (t *T) Paths() path.L {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	l := make(path.L, 0)
+	for k := range t.status.Monitor.Nodes[t.nodename].Services.Config {
+		if p, err := path.Parse(k); err == nil {
+			l = append(l, p)
+		}
+	}
+	return l
+}
+
+//
+// aggregate returns the object aggregated status computed from the local
+// instance status. The peer instances are not known to this dataset, so
+// the aggregation is the local instance view.
+//
+func aggregate(st instance.Status) object.AggregatedStatus {
+	data := object.AggregatedStatus{
+		Avail:       st.Avail,
+		Overall:     st.Overall,
+		Provisioned: st.Provisioned,
+		Frozen:      "thawed",
+	}
+	if !st.Frozen.IsZero() && !st.Frozen.Time().IsZero() {
+		data.Frozen = "frozen"
+	}
+	return data
+}
@@ -0,0 +1,122 @@
+package daemondata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/jsondelta"
+)
+
+func TestData(t *testing.T) {
+	data := New(WithNodename("n1"))
+	full, events, cancel := data.Subscribe()
+	defer cancel()
+	assert.Equal(t, KindFull, full.Kind)
+	seq := jsondelta.NewSequence(*full.Data, full.ID, true)
+
+	p, _ := path.Parse("svc1")
+	data.SetInstanceConfig(p, instance.Config{Checksum: "abc", Scope: []string{"n1"}})
+	data.SetInstanceMonitor(p, instance.Monitor{GlobalExpect: "started"})
+	data.SetInstanceStatus(p, instance.Status{Avail: status.Up, Overall: status.Up})
+	data.SetInstanceStatus(p, instance.Status{Avail: status.Up, Overall: status.Up})
+
+	for i := 0; i < 3; i++ {
+		e := <-events
+		assert.Equal(t, KindPatch, e.Kind)
+		require.NoError(t, seq.Apply(e.ID, jsondelta.NewPatch(*e.Data)))
+	}
+	select {
+	case e := <-events:
+		assert.Fail(t, "unchanged dataset published", "%v", e)
+	default:
+	}
+	assert.JSONEq(t, string(data.GetJSON()), string(seq.Bytes()), "the patches rebuild the dataset")
+
+	st := data.Get()
+	assert.Equal(t, status.Up, st.Monitor.Services["svc1"].Avail)
+	assert.Equal(t, "started", st.Monitor.Nodes["n1"].Services.Status["svc1"].Monitor.GlobalExpect, "the instance monitor is preserved")
+	assert.Equal(t, uint64(3), st.Monitor.Nodes["n1"].Gen["n1"])
+	assert.Equal(t, path.L{p}, data.Paths())
+
+	data.DelInstance(p)
+	e := <-events
+	require.NoError(t, seq.Apply(e.ID, jsondelta.NewPatch(*e.Data)))
+	assert.Empty(t, data.Get().Monitor.Services)
+	assert.Empty(t, data.Paths())
+
+	cancel()
+	_, ok := <-events
+	assert.False(t, ok, "the channel is closed on unsubscribe")
+}
+
+func TestGetters(t *testing.T) {
+	data := New(WithNodename("n1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	q, err := EventsGetter{Data: data, Context: ctx}.GetRaw()
+	require.NoError(t, err)
+
+	p, _ := path.Parse("svc1")
+	data.SetInstanceStatus(p, instance.Status{Avail: status.Up})
+
+	full, err := event.DecodeFromJSON(<-q)
+	require.NoError(t, err)
+	assert.Equal(t, KindFull, full.Kind)
+	seq := jsondelta.NewSequence(*full.Data, full.ID, true)
+	e, err := event.DecodeFromJSON(<-q)
+	require.NoError(t, err)
+	require.NoError(t, seq.Apply(e.ID, jsondelta.NewPatch(*e.Data)))
+
+	b, err := StatusGetter{Data: data}.Get()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(seq.Bytes()))
+
+	cancel()
+	for range q {
+	}
+}
+
+func TestLock(t *testing.T) {
+	data := New(WithNodename("n1"))
+	svc1, _ := path.Parse("svc1")
+	svc2, _ := path.Parse("svc2")
+	alive := map[int]bool{10: true}
+	defer func(f func(int) bool) { processExists = f }(processExists)
+	processExists = func(pid int) bool { return alive[pid] }
+
+	gen, err := data.Lock(svc1, cluster.Lock{Intent: "start", SessionID: "s1", PID: 10})
+	require.NoError(t, err)
+	assert.Equal(t, data.Get().Monitor.Nodes["n1"].Gen["n1"], gen, "the lock generation is published")
+	_, err = data.Lock(svc1, cluster.Lock{Intent: "stop", SessionID: "s2", PID: 11})
+	assert.True(t, errors.Is(err, cluster.ErrLocked))
+	assert.Equal(t, "locked: start held by pid 10 session s1", err.Error())
+	_, err = data.Lock(svc1, cluster.Lock{Intent: "start", SessionID: "s1", PID: 10})
+	assert.NoError(t, err, "the session relocks")
+
+	alive[10] = false
+	_, err = data.Lock(svc1, cluster.Lock{Intent: "stop", SessionID: "s2", PID: 11})
+	assert.NoError(t, err, "the lock of a dead process is replaced")
+	data.Unlock(svc1, "s1")
+	assert.Equal(t, "s2", data.Get().Monitor.Nodes["n1"].Locks["svc1"].SessionID, "the session releases its own lock only")
+	data.Unlock(svc1, "s2")
+	assert.Empty(t, data.Get().Monitor.Nodes["n1"].Locks)
+
+	gen = data.Get().Monitor.Nodes["n1"].Gen["n1"]
+	data.SetPeerStatus("n2", cluster.NodeStatus{
+		Gen:   map[string]uint64{"n1": gen, "n2": 7},
+		Locks: map[string]cluster.Lock{"svc2": {Intent: "start", SessionID: "s3"}},
+	})
+	st := data.Get()
+	assert.Equal(t, uint64(7), st.Monitor.Nodes["n1"].Gen["n2"], "the applied peer generation is recorded")
+	assert.Equal(t, gen, st.Monitor.Nodes["n1"].Gen["n1"], "applying a peer generation is not a local change")
+	_, err = data.Lock(svc2, cluster.Lock{Intent: "stop", SessionID: "s2", PID: 11})
+	assert.Equal(t, "locked: start held by node n2 session s3", err.Error())
+}
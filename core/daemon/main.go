@@ -0,0 +1,141 @@
+//
+// Package daemon manages the opensvc daemon process lifecycle.
+//
+// The foreground main loop runs the daemon threads until a SIGTERM or
+// SIGINT signal, then cancels their context and waits for their clean
+// exit. The Start, Stop and IsRunning controls back the om daemon
+// commands, and delegate to systemd when the agent unit is installed.
+//
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// ThreadFunc is the main function of a daemon thread. It must return
+	// when the context is done.
+	ThreadFunc func(ctx context.Context) error
+
+	// T is the daemon main loop.
+	T struct {
+		threads         map[string]ThreadFunc
+		shutdownTimeout time.Duration
+	}
+)
+
+var (
+	// DefaultShutdownTimeout is the maximum duration of the threads
+	// shutdown, before the daemon exits anyway.
+	DefaultShutdownTimeout = 30 * time.Second
+
+	// ErrRunning is returned by Run when another daemon is running.
+	ErrRunning = errors.New("daemon is already running")
+)
+
+// New allocates and returns a daemon main loop.
+func New(opts ...funcopt.O) *T {
+	t := &T{
+		threads:         make(map[string]ThreadFunc),
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithThread adds a thread to run in the daemon main loop.
+func WithThread(name string, fn ThreadFunc) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.threads[name] = fn
+		return nil
+	})
+}
+
+// WithShutdownTimeout sets the maximum duration of the threads shutdown.
+func WithShutdownTimeout(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.shutdownTimeout = d
+		return nil
+	})
+}
+
+//
+// Run writes the daemon pid file, starts the threads, and blocks until a
+// SIGTERM or SIGINT signal is received or the context is done. The
+// threads context is then cancelled, and Run returns when all threads
+// are stopped or the shutdown timeout is reached.
+//
+func (t *T) Run(ctx context.Context) error {
+	if IsRunning() {
+		return ErrRunning
+	}
+	if err := writePidFile(); err != nil {
+		return err
+	}
+	defer removePidFile()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(sigs)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, name := range t.threadNames() {
+		wg.Add(1)
+		go func(name string, fn ThreadFunc) {
+			defer wg.Done()
+			log.Info().Str("thread", name).Msg("started")
+			if err := fn(ctx); err != nil {
+				log.Error().Err(err).Str("thread", name).Msg("")
+			}
+			log.Info().Str("thread", name).Msg("stopped")
+		}(name, t.threads[name])
+	}
+	log.Info().Int("pid", os.Getpid()).Msg("daemon started")
+
+	select {
+	case sig := <-sigs:
+		log.Info().Stringer("signal", sig).Msg("daemon shutdown")
+	case <-ctx.Done():
+		log.Info().Msg("daemon shutdown")
+	}
+	cancel()
+
+	done := make(chan interface{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Info().Msg("daemon stopped")
+		return nil
+	case <-time.After(t.shutdownTimeout):
+		return fmt.Errorf("daemon threads still running after %s", t.shutdownTimeout)
+	}
+}
+
+func (t *T) threadNames() []string {
+	l := make([]string, 0, len(t.threads))
+	for name := range t.threads {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
+}
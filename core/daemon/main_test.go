@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func setupRoot(t *testing.T) func() {
+	root, err := ioutil.TempDir("", "daemon")
+	require.NoError(t, err)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	return func() {
+		rawconfig.Load(map[string]string{})
+		_ = os.RemoveAll(root)
+	}
+}
+
+func TestRun(t *testing.T) {
+	defer setupRoot(t)()
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan interface{})
+	stopped := false
+	d := New(WithThread("t1", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		stopped = true
+		return nil
+	}))
+	errC := make(chan error)
+	go func() {
+		errC <- d.Run(ctx)
+	}()
+	<-started
+	assert.Equal(t, os.Getpid(), Pid(), "the pid file records the daemon pid")
+	assert.True(t, IsRunning())
+	assert.Equal(t, ErrRunning, New().Run(context.Background()), "a second daemon is refused")
+
+	cancel()
+	require.NoError(t, <-errC)
+	assert.True(t, stopped, "the threads are stopped before Run returns")
+	assert.Equal(t, 0, Pid(), "the pid file is removed")
+	assert.False(t, IsRunning())
+}
+
+func TestRunShutdownTimeout(t *testing.T) {
+	defer setupRoot(t)()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d := New(
+		WithShutdownTimeout(10*time.Millisecond),
+		WithThread("stuck", func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}),
+	)
+	assert.Error(t, d.Run(ctx))
+}
+
+func TestPidDeadProcess(t *testing.T) {
+	defer setupRoot(t)()
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Var, 0755))
+	require.NoError(t, ioutil.WriteFile(PidFile(), []byte("999999999\n"), 0644))
+	assert.Equal(t, 0, Pid())
+}
+
+func TestPidUnlocked(t *testing.T) {
+	defer setupRoot(t)()
+	require.NoError(t, os.MkdirAll(rawconfig.Node.Paths.Var, 0755))
+	require.NoError(t, ioutil.WriteFile(PidFile(), []byte(fmt.Sprintln(os.Getpid())), 0644))
+	assert.Equal(t, 0, Pid(), "a live process pid recorded in a pid file not locked is not trusted")
+}
@@ -0,0 +1,7 @@
+// Package devicetree aggregates the block devices exposed, used and
+// reserved by the resources of an object.
+//
+// The tree is the data source of the scsi reservation, zoning and pool
+// sizing features, and of the "om <path> print devs" command.
+
+package devicetree
@@ -0,0 +1,269 @@
+package devicetree
+
+import (
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/device"
+	"opensvc.com/opensvc/util/render/tree"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	// Role is the relation between a resource and a device.
+	Role string
+
+	// ResourceDevices is the list of devices of a resource, by role.
+	ResourceDevices struct {
+		Exposed    []string `json:"exposed,omitempty"`
+		Sub        []string `json:"sub,omitempty"`
+		Reservable []string `json:"reservable,omitempty"`
+		Claimed    []string `json:"claimed,omitempty"`
+	}
+
+	// T is the device tree of an object, indexed by resource id.
+	T struct {
+		Path      string                     `json:"path"`
+		Resources map[string]ResourceDevices `json:"resources"`
+	}
+)
+
+const (
+	// Exposed is the role of the devices a resource exposes, so other
+	// resources can be stacked on.
+	Exposed Role = "exposed"
+
+	// Sub is the role of the devices a resource is stacked on.
+	Sub Role = "sub"
+
+	// Reservable is the role of the devices a resource can protect with a
+	// scsi persistent reservation.
+	Reservable Role = "reservable"
+
+	// Claimed is the role of the devices a resource uses exclusively.
+	Claimed Role = "claimed"
+)
+
+// New returns the device tree of the resources.
+func New(path string, l []resource.Driver) *T {
+	t := &T{
+		Path:      path,
+		Resources: make(map[string]ResourceDevices),
+	}
+	for _, r := range l {
+		if r.IsDisabled() {
+			continue
+		}
+		devs := ResourceDevices{
+			Exposed:    exposedDevices(r),
+			Sub:        subDevices(r),
+			Reservable: reservableDevices(r),
+			Claimed:    claimedDevices(r),
+		}
+		if devs.isEmpty() {
+			continue
+		}
+		t.Resources[r.RID()] = devs
+	}
+	return t
+}
+
+func paths(l []*device.T) []string {
+	s := make([]string, 0, len(l))
+	for _, dev := range l {
+		if dev == nil {
+			continue
+		}
+		s = append(s, dev.Path())
+	}
+	return s
+}
+
+func exposedDevices(r resource.Driver) []string {
+	if i, ok := r.(resource.ExposedDeviceser); ok {
+		return paths(i.ExposedDevices())
+	}
+	return nil
+}
+
+func subDevices(r resource.Driver) []string {
+	if i, ok := r.(resource.SubDeviceser); ok {
+		return paths(i.SubDevices())
+	}
+	if i, ok := r.(resource.BaseDeviceser); ok {
+		return paths(i.BaseDevices())
+	}
+	return nil
+}
+
+func reservableDevices(r resource.Driver) []string {
+	if i, ok := r.(resource.ReservableDeviceser); ok {
+		return paths(i.ReservableDevices())
+	}
+	return nil
+}
+
+func claimedDevices(r resource.Driver) []string {
+	if i, ok := r.(resource.ClaimedDeviceser); ok {
+		return paths(i.ClaimedDevices())
+	}
+	return nil
+}
+
+func (t ResourceDevices) isEmpty() bool {
+	return len(t.Exposed)+len(t.Sub)+len(t.Reservable)+len(t.Claimed) == 0
+}
+
+// Get returns the devices of the resource having the role.
+func (t ResourceDevices) Get(role Role) []string {
+	switch role {
+	case Exposed:
+		return t.Exposed
+	case Sub:
+		return t.Sub
+	case Reservable:
+		return t.Reservable
+	case Claimed:
+		return t.Claimed
+	default:
+		return nil
+	}
+}
+
+// Devices returns the sorted list of unique devices having one of the
+// roles in the object. All roles are considered if none is specified.
+func (t T) Devices(roles ...Role) []string {
+	if len(roles) == 0 {
+		roles = []Role{Exposed, Sub, Reservable, Claimed}
+	}
+	m := make(map[string]interface{})
+	for _, devs := range t.Resources {
+		for _, role := range roles {
+			for _, p := range devs.Get(role) {
+				m[p] = nil
+			}
+		}
+	}
+	l := make([]string, 0, len(m))
+	for p := range m {
+		l = append(l, p)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// rids returns the sorted resource ids of the tree.
+func (t T) rids() []string {
+	l := make([]string, 0, len(t.Resources))
+	for rid := range t.Resources {
+		l = append(l, rid)
+	}
+	sort.Strings(l)
+	return l
+}
+
+//
+// users returns the "<rid> <role>" descriptions of the resources using
+// the device.
+//
+func (t T) users(p string) string {
+	l := make([]string, 0)
+	for _, rid := range t.rids() {
+		devs := t.Resources[rid]
+		for _, role := range []Role{Exposed, Sub, Reservable, Claimed} {
+			for _, e := range devs.Get(role) {
+				if e == p {
+					l = append(l, rid+" "+string(role))
+				}
+			}
+		}
+	}
+	return strings.Join(l, ", ")
+}
+
+//
+// children returns the devices the device is built on, which are the sub
+// devices of the resources exposing the device.
+//
+func (t T) children(p string) []string {
+	l := make([]string, 0)
+	for _, rid := range t.rids() {
+		devs := t.Resources[rid]
+		if !has(devs.Exposed, p) {
+			continue
+		}
+		for _, e := range devs.Sub {
+			if e != p {
+				l = append(l, e)
+			}
+		}
+	}
+	return l
+}
+
+// deviceSize returns the exact compact size of the device, or "-" if the
+// size is not available, like for a device not present on this node.
+func deviceSize(p string) string {
+	n, err := device.New(p).Size()
+	if err != nil {
+		return "-"
+	}
+	return sizeconv.Compact(n, sizeconv.IEC)
+}
+
+func has(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isChild returns true if the device is a sub device of a resource
+// exposing other devices.
+func (t T) isChild(p string) bool {
+	for _, devs := range t.Resources {
+		if len(devs.Exposed) == 0 || has(devs.Exposed, p) {
+			continue
+		}
+		if has(devs.Sub, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns the human readable tree of the object devices, each
+// device having the devices it is built on as children.
+func (t T) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText(t.Path).SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Resources").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Size").SetColor(rawconfig.Node.Color.Bold)
+	var add func(n *tree.Node, p string, seen map[string]bool)
+	add = func(n *tree.Node, p string, seen map[string]bool) {
+		child := n.AddNode()
+		child.AddColumn().AddText(p).SetColor(rawconfig.Node.Color.Primary)
+		child.AddColumn().AddText(t.users(p))
+		child.AddColumn().AddText(deviceSize(p))
+		if seen[p] {
+			// stacking loop
+			return
+		}
+		seen[p] = true
+		defer delete(seen, p)
+		for _, sub := range t.children(p) {
+			add(child, sub, seen)
+		}
+	}
+	for _, p := range t.Devices() {
+		if t.isChild(p) {
+			continue
+		}
+		add(tr.Head(), p, make(map[string]bool))
+	}
+	return tr.Render()
+}
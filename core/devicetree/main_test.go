@@ -0,0 +1,87 @@
+package devicetree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/device"
+)
+
+type (
+	driver struct {
+		resource.T
+	}
+	lvDriver struct {
+		driver
+	}
+	rawDriver struct {
+		driver
+	}
+	fsDriver struct {
+		driver
+	}
+)
+
+func newDriver(rid string) driver {
+	r := driver{}
+	r.SetRID(rid)
+	return r
+}
+
+func devices(l ...string) []*device.T {
+	devs := make([]*device.T, len(l))
+	for i, p := range l {
+		devs[i] = device.New(p)
+	}
+	return devs
+}
+
+func (t lvDriver) ExposedDevices() []*device.T    { return devices("/dev/vg1/lv1") }
+func (t lvDriver) SubDevices() []*device.T        { return devices("/dev/sda", "/dev/sdb") }
+func (t lvDriver) ReservableDevices() []*device.T { return t.SubDevices() }
+func (t rawDriver) ExposedDevices() []*device.T   { return devices("/dev/raw/raw1") }
+func (t rawDriver) BaseDevices() []*device.T      { return devices("/dev/vg1/lv1") }
+func (t fsDriver) BaseDevices() []*device.T       { return devices("/dev/raw/raw1") }
+
+func newTree() *T {
+	return New("svc1", []resource.Driver{
+		&lvDriver{newDriver("disk#1")},
+		&rawDriver{newDriver("disk#2")},
+		&fsDriver{newDriver("fs#1")},
+	})
+}
+
+func TestNew(t *testing.T) {
+	tree := newTree()
+	assert.Equal(t, ResourceDevices{
+		Exposed:    []string{"/dev/vg1/lv1"},
+		Sub:        []string{"/dev/sda", "/dev/sdb"},
+		Reservable: []string{"/dev/sda", "/dev/sdb"},
+	}, tree.Resources["disk#1"])
+	assert.Equal(t, ResourceDevices{
+		Sub: []string{"/dev/raw/raw1"},
+	}, tree.Resources["fs#1"], "base devices are used as sub devices")
+}
+
+func TestDevices(t *testing.T) {
+	tree := newTree()
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, tree.Devices(Reservable))
+	assert.Equal(t, []string{"/dev/raw/raw1", "/dev/vg1/lv1"}, tree.Devices(Exposed))
+	assert.Len(t, tree.Devices(), 4)
+}
+
+func TestStacking(t *testing.T) {
+	tree := newTree()
+	assert.Equal(t, []string{"/dev/vg1/lv1"}, tree.children("/dev/raw/raw1"))
+	assert.Equal(t, []string{"/dev/sda", "/dev/sdb"}, tree.children("/dev/vg1/lv1"))
+	assert.False(t, tree.isChild("/dev/raw/raw1"))
+	assert.True(t, tree.isChild("/dev/vg1/lv1"))
+}
+
+func TestRender(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	s := newTree().Render()
+	assert.Regexp(t, "(?s)/dev/raw/raw1.*/dev/vg1/lv1.*/dev/sda.*/dev/sdb", s)
+}
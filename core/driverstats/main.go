@@ -0,0 +1,242 @@
+// Package driverstats records the duration of the resource driver actions
+// (start, stop, status) in histograms.
+//
+// Observations are accumulated in memory by the process running the
+// actions, and merged into the node stats store on Flush(), so the daemon
+// can expose them via the daemon stats handler and the metrics exporter.
+package driverstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opensvc/fcntllock"
+	"github.com/opensvc/flock"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/xsession"
+)
+
+type (
+	// Histogram counts the observed durations in cumulative buckets.
+	// Buckets[i] is the number of observations lower or equal to
+	// Bounds[i]. Sum is expressed in seconds.
+	Histogram struct {
+		Buckets []uint64 `json:"buckets"`
+		Count   uint64   `json:"count"`
+		Sum     float64  `json:"sum"`
+	}
+
+	// ActionStats maps action names to their duration histogram.
+	ActionStats map[string]*Histogram
+
+	// T maps driver names (<group>.<name>) to their action histograms.
+	T map[string]ActionStats
+)
+
+var (
+	// Bounds are the upper bounds of the histogram buckets.
+	Bounds = []time.Duration{
+		10 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		5 * time.Second,
+		10 * time.Second,
+		30 * time.Second,
+		time.Minute,
+		5 * time.Minute,
+	}
+
+	pending = make(T)
+	mu      sync.Mutex
+
+	lockTimeout = 5 * time.Second
+)
+
+// NewHistogram allocates a histogram with one bucket per bound.
+func NewHistogram() *Histogram {
+	return &Histogram{
+		Buckets: make([]uint64, len(Bounds)),
+	}
+}
+
+// Observe adds a duration to the histogram.
+func (t *Histogram) Observe(d time.Duration) {
+	if len(t.Buckets) != len(Bounds) {
+		t.Buckets = make([]uint64, len(Bounds))
+	}
+	for i, bound := range Bounds {
+		if d <= bound {
+			t.Buckets[i]++
+		}
+	}
+	t.Count++
+	t.Sum += d.Seconds()
+}
+
+// Merge adds the other histogram counters to the histogram.
+func (t *Histogram) Merge(other *Histogram) {
+	if len(t.Buckets) != len(Bounds) {
+		t.Buckets = make([]uint64, len(Bounds))
+	}
+	for i := range t.Buckets {
+		if i < len(other.Buckets) {
+			t.Buckets[i] += other.Buckets[i]
+		}
+	}
+	t.Count += other.Count
+	t.Sum += other.Sum
+}
+
+// Mean returns the average observed duration.
+func (t Histogram) Mean() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return time.Duration(t.Sum / float64(t.Count) * float64(time.Second))
+}
+
+// Observe records the duration of an action of the driver.
+func (t T) Observe(driver, action string, d time.Duration) {
+	if _, ok := t[driver]; !ok {
+		t[driver] = make(ActionStats)
+	}
+	if _, ok := t[driver][action]; !ok {
+		t[driver][action] = NewHistogram()
+	}
+	t[driver][action].Observe(d)
+}
+
+// Merge adds the other stats counters to the stats.
+func (t T) Merge(other T) {
+	for driver, actions := range other {
+		if _, ok := t[driver]; !ok {
+			t[driver] = make(ActionStats)
+		}
+		for action, h := range actions {
+			if _, ok := t[driver][action]; !ok {
+				t[driver][action] = NewHistogram()
+			}
+			t[driver][action].Merge(h)
+		}
+	}
+}
+
+// Drivers returns the sorted list of driver names having observations.
+func (t T) Drivers() []string {
+	l := make([]string, 0, len(t))
+	for driver := range t {
+		l = append(l, driver)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// WritePrometheus writes the stats in the prometheus text exposition format.
+func (t T) WritePrometheus(w io.Writer) error {
+	const name = "opensvc_driver_action_duration_seconds"
+	if _, err := fmt.Fprintf(w, "# HELP %s Duration of the resource driver actions.\n# TYPE %s histogram\n", name, name); err != nil {
+		return err
+	}
+	for _, driver := range t.Drivers() {
+		actions := make([]string, 0, len(t[driver]))
+		for action := range t[driver] {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			h := t[driver][action]
+			labels := fmt.Sprintf("driver=%q,action=%q", driver, action)
+			for i, bound := range Bounds {
+				var v uint64
+				if i < len(h.Buckets) {
+					v = h.Buckets[i]
+				}
+				if _, err := fmt.Fprintf(w, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, bound.Seconds(), v); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.Count); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "%s_sum{%s} %g\n%s_count{%s} %d\n", name, labels, h.Sum, name, labels, h.Count); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Observe records the duration of a driver action in the process pending
+// stats.
+func Observe(driver, action string, d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	pending.Observe(driver, action, d)
+}
+
+// File returns the path of the node driver stats store.
+func File() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "driver_stats.json")
+}
+
+// Load returns the stats stored in the node driver stats store.
+func Load() (T, error) {
+	data := make(T)
+	b, err := ioutil.ReadFile(File())
+	switch {
+	case os.IsNotExist(err):
+		return data, nil
+	case err != nil:
+		return data, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// Flush merges the process pending stats into the node driver stats store.
+func Flush() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+	p := File()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	lock := flock.New(p+".lock", xsession.ID, fcntllock.New)
+	if err := lock.Lock(lockTimeout, "driver stats flush"); err != nil {
+		return err
+	}
+	defer func() { _ = lock.UnLock() }()
+	data, err := Load()
+	if err != nil {
+		// a corrupted store must not prevent recording new stats
+		data = make(T)
+	}
+	data.Merge(pending)
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return err
+	}
+	pending = make(T)
+	return nil
+}
@@ -0,0 +1,45 @@
+package driverstats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserve(t *testing.T) {
+	data := make(T)
+	data.Observe("disk.raw", "status", 20*time.Millisecond)
+	data.Observe("disk.raw", "status", 2*time.Second)
+	h := data["disk.raw"]["status"]
+	assert.Equal(t, uint64(2), h.Count)
+	assert.Equal(t, uint64(0), h.Buckets[0], "10ms bucket")
+	assert.Equal(t, uint64(1), h.Buckets[1], "50ms bucket")
+	assert.Equal(t, uint64(2), h.Buckets[len(Bounds)-1], "last bucket")
+	assert.Equal(t, 1010*time.Millisecond, h.Mean())
+}
+
+func TestMerge(t *testing.T) {
+	a := make(T)
+	a.Observe("disk.raw", "start", time.Second)
+	b := make(T)
+	b.Observe("disk.raw", "start", time.Second)
+	b.Observe("fs.flag", "stop", time.Second)
+	a.Merge(b)
+	assert.Equal(t, uint64(2), a["disk.raw"]["start"].Count)
+	assert.Equal(t, uint64(1), a["fs.flag"]["stop"].Count)
+	assert.Equal(t, []string{"disk.raw", "fs.flag"}, a.Drivers())
+}
+
+func TestWritePrometheus(t *testing.T) {
+	data := make(T)
+	data.Observe("disk.raw", "start", time.Second)
+	var b bytes.Buffer
+	assert.Nil(t, data.WritePrometheus(&b))
+	s := b.String()
+	assert.True(t, strings.Contains(s, `opensvc_driver_action_duration_seconds_bucket{driver="disk.raw",action="start",le="1"} 1`))
+	assert.True(t, strings.Contains(s, `opensvc_driver_action_duration_seconds_bucket{driver="disk.raw",action="start",le="0.5"} 0`))
+	assert.True(t, strings.Contains(s, `opensvc_driver_action_duration_seconds_count{driver="disk.raw",action="start"} 1`))
+}
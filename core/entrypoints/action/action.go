@@ -97,7 +97,7 @@ type (
 
 	// Actioner is the interface implemented by nodeaction.T and objectaction.T
 	Actioner interface {
-		DoRemote()
+		DoRemote() error
 		DoLocal() error
 		DoAsync()
 		Options() T
@@ -111,7 +111,7 @@ func Do(t Actioner) error {
 	o := t.Options()
 	switch {
 	case o.NodeSelector != "":
-		t.DoRemote()
+		err = t.DoRemote()
 	case o.Local || o.DefaultIsLocal:
 		err = t.DoLocal()
 	case o.Target != "":
@@ -120,7 +120,7 @@ func Do(t Actioner) error {
 		err = t.DoLocal()
 	default:
 		// post action on context endpoint
-		t.DoRemote()
+		err = t.DoRemote()
 	}
 	if o.Watch {
 		m := monitor.New()
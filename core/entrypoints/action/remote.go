@@ -0,0 +1,154 @@
+package action
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/nodeselector"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// RemoteResult is the result of an action executed by a peer node
+	// agent api.
+	RemoteResult struct {
+		Node   string `json:"node"`
+		Status int    `json:"status"`
+		Out    string `json:"out,omitempty"`
+		Err    string `json:"err,omitempty"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	// RemoteResults is the list of per-node results of a remote action,
+	// sorted by node name.
+	RemoteResults []RemoteResult
+
+	// RemotePoster posts the action request to the agent api of <node>.
+	RemotePoster func(ctx context.Context, node string) ([]byte, error)
+)
+
+// ErrRemote is returned by the remote actions failed on at least one node.
+type ErrRemote struct {
+	Failed int
+	Total  int
+
+	// Status is the exit code shared by the failed nodes, or zero if
+	// their exit codes differ or a node was not reached.
+	Status int
+}
+
+func (t ErrRemote) Error() string {
+	return fmt.Sprintf("action failed on %d/%d nodes", t.Failed, t.Total)
+}
+
+// ExitCode implements the exitcode.Coder interface.
+func (t ErrRemote) ExitCode() int {
+	switch {
+	case t.Failed < t.Total:
+		return exitcode.Partial
+	case t.Status > 0:
+		return t.Status
+	default:
+		return exitcode.Error
+	}
+}
+
+// RemoteNodes expands the node selector expression into a list of nodes,
+// using the nodes information of the agent api.
+func RemoteNodes(c *client.T, selector string) ([]string, error) {
+	nodes := nodeselector.New(selector, nodeselector.WithClient(c)).Expand()
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no node selected by '%s'", selector)
+	}
+	return nodes, nil
+}
+
+//
+// FanOut posts the action to each node concurrently, and returns the
+// per-node results, sorted by node name. The transport errors and the
+// undecodable responses are reported in the result Error field.
+//
+func FanOut(ctx context.Context, nodes []string, post RemotePoster) RemoteResults {
+	responses := client.FanOut(ctx, nodes, client.NodeRequestFunc(post))
+	l := make(RemoteResults, len(responses))
+	for i, resp := range responses {
+		l[i] = remoteResult(resp)
+	}
+	return l
+}
+
+func remoteResult(resp client.NodeResponse) RemoteResult {
+	r := RemoteResult{Node: resp.Node}
+	if resp.Err != nil {
+		r.Error = resp.Err.Error()
+		return r
+	}
+	if err := json.Unmarshal(resp.Data, &r); err != nil {
+		r.Error = fmt.Sprintf("decode response: %s", err)
+	}
+	r.Node = resp.Node
+	return r
+}
+
+// IsSuccess returns true if the action succeeded on the node.
+func (t RemoteResult) IsSuccess() bool {
+	return t.Error == "" && t.Status == 0
+}
+
+// Err returns an ErrRemote if the action failed on at least one node.
+func (t RemoteResults) Err() error {
+	failed := 0
+	status := -1
+	for _, r := range t {
+		if r.IsSuccess() {
+			continue
+		}
+		failed++
+		switch {
+		case r.Error != "":
+			status = 0
+		case status == -1:
+			status = r.Status
+		case status != r.Status:
+			status = 0
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+	return ErrRemote{Failed: failed, Total: len(t), Status: status}
+}
+
+// Render is a human renderer of the remote action results.
+func (t RemoteResults) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText("Node").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Status").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Output").SetColor(rawconfig.Node.Color.Bold)
+	for _, r := range t {
+		n := tr.AddNode()
+		n.AddColumn().AddText(r.Node).SetColor(rawconfig.Node.Color.Primary)
+		if r.IsSuccess() {
+			n.AddColumn().AddText("ok").SetColor(rawconfig.Node.Color.Optimal)
+		} else {
+			n.AddColumn().AddText("failed").SetColor(rawconfig.Node.Color.Error)
+		}
+		out := n.AddColumn()
+		for _, s := range []string{r.Out, r.Err, r.Error} {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			if r.IsSuccess() {
+				out.AddText(s)
+			} else {
+				out.AddText(s).SetColor(rawconfig.Node.Color.Error)
+			}
+		}
+	}
+	return tr.Render()
+}
@@ -0,0 +1,77 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestFanOut(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	post := func(_ context.Context, node string) ([]byte, error) {
+		switch node {
+		case "n1":
+			return []byte(`{"status": 0, "out": "started"}`), nil
+		case "n2":
+			return []byte(`{"status": 1, "err": "start failed"}`), nil
+		case "n3":
+			return nil, errors.New("connection refused")
+		default:
+			return []byte(`not json`), nil
+		}
+	}
+	rs := FanOut(context.Background(), []string{"n4", "n3", "n2", "n1"}, post)
+	require.Len(t, rs, 4)
+	assert.Equal(t, RemoteResult{Node: "n1", Out: "started"}, rs[0])
+	assert.Equal(t, RemoteResult{Node: "n2", Status: 1, Err: "start failed"}, rs[1])
+	assert.Equal(t, RemoteResult{Node: "n3", Error: "connection refused"}, rs[2])
+	assert.Equal(t, "n4", rs[3].Node)
+	assert.NotEmpty(t, rs[3].Error)
+
+	var errRemote ErrRemote
+	require.True(t, errors.As(rs.Err(), &errRemote))
+	assert.Equal(t, ErrRemote{Failed: 3, Total: 4}, errRemote)
+	assert.Nil(t, rs[:1].Err())
+
+	s := rs.Render()
+	assert.Contains(t, s, "started")
+	assert.Contains(t, s, "connection refused")
+}
+
+func TestRemoteResultsExitCode(t *testing.T) {
+	cases := map[string]struct {
+		results  RemoteResults
+		expected int
+	}{
+		"all succeeded": {
+			results:  RemoteResults{{Node: "n1"}, {Node: "n2"}},
+			expected: exitcode.OK,
+		},
+		"some failed": {
+			results:  RemoteResults{{Node: "n1"}, {Node: "n2", Status: exitcode.Aborted}},
+			expected: exitcode.Partial,
+		},
+		"all aborted": {
+			results:  RemoteResults{{Node: "n1", Status: exitcode.Aborted}, {Node: "n2", Status: exitcode.Aborted}},
+			expected: exitcode.Aborted,
+		},
+		"all failed differently": {
+			results:  RemoteResults{{Node: "n1", Status: exitcode.Aborted}, {Node: "n2", Status: exitcode.NotFound}},
+			expected: exitcode.Error,
+		},
+		"all failed, one unreachable": {
+			results:  RemoteResults{{Node: "n1", Status: exitcode.Aborted}, {Node: "n2", Error: "connection refused"}},
+			expected: exitcode.Error,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, exitcode.Of(c.results.Err()))
+		})
+	}
+}
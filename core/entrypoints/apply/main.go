@@ -0,0 +1,470 @@
+// Package apply reconciles the cluster objects with a directory of object
+// configuration manifests.
+//
+// The manifests are ini or json formatted configuration files. The object
+// path is read from the json "metadata" section if present, or derived
+// from the file path relative to the manifests directory:
+//
+//	<dir>/<namespace>/<kind>/<name>.conf
+//	<dir>/<kind>/<name>.conf
+//	<dir>/<name>.conf
+//
+// The objects with no manifest are deleted only if the prune option is
+// set, and only in the namespaces hosting at least one manifest.
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/entrypoints/create"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	T struct {
+		client    *client.T
+		dir       string
+		namespace string
+		prune     bool
+		dryRun    bool
+		local     bool
+		out       io.Writer
+	}
+
+	// Op is the kind of change planned for an object.
+	Op string
+
+	// Change is a planned change of an object configuration.
+	Change struct {
+		Op     Op
+		Path   path.T
+		Config rawconfig.T
+	}
+
+	// Plan is the list of changes to apply, sorted by object path.
+	Plan []Change
+)
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+var (
+	manifestExtensions = []string{".conf", ".ini", ".json"}
+)
+
+// WithDir sets the path of the directory hosting the manifests.
+func WithDir(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.dir = s
+		return nil
+	})
+}
+
+// WithNamespace overrides the namespace of all the manifests.
+func WithNamespace(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.namespace = s
+		return nil
+	})
+}
+
+// WithPrune enables the deletion of the objects having no manifest.
+func WithPrune(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.prune = v
+		return nil
+	})
+}
+
+// WithDryRun only prints the plan.
+func WithDryRun(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.dryRun = v
+		return nil
+	})
+}
+
+// WithLocal applies the changes to the local configuration files instead
+// of submitting them to the daemon api.
+func WithLocal(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.local = v
+		return nil
+	})
+}
+
+func WithClient(c *client.T) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.client = c
+		return nil
+	})
+}
+
+func New(opts ...funcopt.O) (*T, error) {
+	t := &T{
+		out: os.Stdout,
+	}
+	if err := funcopt.Apply(t, opts...); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Do computes the plan, prints it and applies it unless dry-run is set.
+func (t T) Do() error {
+	plan, err := t.Plan()
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(t.out, plan.Render())
+	if t.dryRun || len(plan) == 0 {
+		return nil
+	}
+	return t.Apply(plan)
+}
+
+// Plan returns the changes needed to reconcile the objects with the
+// manifests.
+func (t T) Plan() (Plan, error) {
+	manifests, err := t.Manifests()
+	if err != nil {
+		return nil, err
+	}
+	plan := make(Plan, 0)
+	namespaces := make(map[string]interface{})
+	for s, c := range manifests {
+		p, _ := path.Parse(s)
+		namespaces[p.Namespace] = nil
+		current, err := t.current(p)
+		switch {
+		case err != nil:
+			return nil, err
+		case current.Data == nil:
+			plan = append(plan, Change{Op: OpCreate, Path: p, Config: c})
+		default:
+			c = keepID(c, current)
+			if equal(c, current) {
+				continue
+			}
+			plan = append(plan, Change{Op: OpUpdate, Path: p, Config: c})
+		}
+	}
+	if t.prune {
+		for ns := range namespaces {
+			for _, p := range t.installed(ns) {
+				if _, ok := manifests[p.String()]; ok {
+					continue
+				}
+				plan = append(plan, Change{Op: OpDelete, Path: p})
+			}
+		}
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].Path.String() < plan[j].Path.String()
+	})
+	return plan, nil
+}
+
+// Apply submits the planned changes.
+func (t T) Apply(plan Plan) error {
+	for _, c := range plan {
+		var err error
+		if t.isLocal() {
+			err = applyLocal(c)
+		} else {
+			err = t.applyDaemon(c)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", c.Op, c.Path, err)
+		}
+		fmt.Fprintf(t.out, "%s %sd\n", c.Path, c.Op)
+	}
+	return nil
+}
+
+// Render returns the human readable representation of the plan.
+func (t Plan) Render() string {
+	if len(t) == 0 {
+		return "no changes\n"
+	}
+	var b strings.Builder
+	counts := make(map[Op]int)
+	for _, c := range t {
+		counts[c.Op]++
+		switch c.Op {
+		case OpCreate:
+			fmt.Fprintf(&b, "+ %s\n", c.Path)
+		case OpUpdate:
+			fmt.Fprintf(&b, "~ %s\n", c.Path)
+		case OpDelete:
+			fmt.Fprintf(&b, "- %s\n", c.Path)
+		}
+	}
+	fmt.Fprintf(&b, "plan: %d to create, %d to update, %d to delete\n", counts[OpCreate], counts[OpUpdate], counts[OpDelete])
+	return b.String()
+}
+
+func (t T) isLocal() bool {
+	return t.local || (t.client == nil && !clientcontext.IsSet())
+}
+
+// Manifests returns the raw configurations found in the manifests
+// directory, indexed by object path.
+func (t T) Manifests() (map[string]rawconfig.T, error) {
+	m := make(map[string]rawconfig.T)
+	if t.dir == "" {
+		return m, fmt.Errorf("no manifests directory")
+	}
+	err := filepath.Walk(t.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isManifest(p) {
+			return nil
+		}
+		op, c, err := t.parseManifest(p)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+		if _, ok := m[op.String()]; ok {
+			return fmt.Errorf("%s: duplicate manifest for %s", p, op)
+		}
+		m[op.String()] = c
+		return nil
+	})
+	return m, err
+}
+
+func isManifest(p string) bool {
+	ext := filepath.Ext(p)
+	for _, e := range manifestExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (t T) parseManifest(p string) (path.T, rawconfig.T, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return path.T{}, rawconfig.T{}, err
+	}
+	c, err := rawconfig.Parse(b)
+	if err != nil {
+		return path.T{}, rawconfig.T{}, err
+	}
+	op, err := t.manifestPath(p, c)
+	if err != nil {
+		return op, c, err
+	}
+	if c.Data != nil {
+		c.Data.Delete("metadata")
+	}
+	return op, c, nil
+}
+
+func (t T) manifestPath(p string, c rawconfig.T) (path.T, error) {
+	var (
+		op  path.T
+		err error
+	)
+	if md, ok := metadata(c); ok {
+		op, err = create.PathFromMetadata(md)
+	} else {
+		var rel string
+		if rel, err = filepath.Rel(t.dir, p); err != nil {
+			return op, err
+		}
+		rel = strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+		op, err = path.Parse(rel)
+	}
+	if err != nil {
+		return op, err
+	}
+	if t.namespace != "" {
+		op.Namespace = t.namespace
+	}
+	return op, nil
+}
+
+func metadata(c rawconfig.T) (*orderedmap.OrderedMap, bool) {
+	if c.Data == nil {
+		return nil, false
+	}
+	v, ok := c.Data.Get("metadata")
+	if !ok {
+		return nil, false
+	}
+	md, ok := v.(orderedmap.OrderedMap)
+	if !ok {
+		return nil, false
+	}
+	return &md, true
+}
+
+// current returns the current configuration of the object, with a nil
+// Data if the object does not exist.
+func (t T) current(p path.T) (rawconfig.T, error) {
+	if t.isLocal() {
+		o := object.NewConfigurerFromPath(p)
+		if !o.Exists() {
+			return rawconfig.T{}, nil
+		}
+		return o.Config().Raw(), nil
+	}
+	if !t.exists(p) {
+		return rawconfig.T{}, nil
+	}
+	req := t.client.NewGetObjectConfig()
+	req.ObjectSelector = p.String()
+	b, err := req.Do()
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	return parseConfigResponse(b)
+}
+
+func (t T) exists(p path.T) bool {
+	return len(t.selection(p.String(), "").Expand()) > 0
+}
+
+func (t T) installed(namespace string) []path.T {
+	return t.selection("**", namespace).Expand()
+}
+
+func (t T) selection(selector, namespace string) *object.Selection {
+	opts := []funcopt.O{object.SelectionWithNamespace(namespace)}
+	if t.isLocal() {
+		opts = append(opts, object.SelectionWithLocal(true))
+	} else {
+		opts = append(opts, object.SelectionWithClient(t.client))
+	}
+	return object.NewSelection(selector, opts...)
+}
+
+func (t T) applyDaemon(c Change) error {
+	switch c.Op {
+	case OpDelete:
+		req := t.client.NewPostObjectAction()
+		req.ObjectSelector = c.Path.String()
+		req.Action = "delete"
+		_, err := req.Do()
+		return err
+	default:
+		req := t.client.NewPostObjectCreate()
+		req.Restore = c.Op == OpUpdate
+		req.Data[c.Path.String()] = c.Config
+		_, err := req.Do()
+		return err
+	}
+}
+
+func applyLocal(c Change) error {
+	o := object.NewConfigurerFromPath(c.Path)
+	switch c.Op {
+	case OpDelete:
+		return o.Delete(object.OptsDelete{})
+	default:
+		return o.Config().CommitData(c.Config)
+	}
+}
+
+// keepID sets the current object id in the manifest configuration if the
+// manifest does not define one, so an update does not change the id.
+func keepID(c, current rawconfig.T) rawconfig.T {
+	id, ok := sectionValue(current, "DEFAULT", "id")
+	if !ok {
+		return c
+	}
+	if _, ok := sectionValue(c, "DEFAULT", "id"); ok {
+		return c
+	}
+	data := orderedmap.New()
+	section := orderedmap.New()
+	if c.Data == nil {
+		c.Data = orderedmap.New()
+	}
+	section.Set("id", id)
+	if v, ok := c.Data.Get("DEFAULT"); ok {
+		if omap, ok := v.(orderedmap.OrderedMap); ok {
+			for _, k := range omap.Keys() {
+				v, _ := omap.Get(k)
+				section.Set(k, v)
+			}
+		}
+	}
+	data.Set("DEFAULT", *section)
+	for _, k := range c.Data.Keys() {
+		if k == "DEFAULT" {
+			continue
+		}
+		v, _ := c.Data.Get(k)
+		data.Set(k, v)
+	}
+	return rawconfig.T{Data: data}
+}
+
+func sectionValue(c rawconfig.T, section, option string) (interface{}, bool) {
+	if c.Data == nil {
+		return nil, false
+	}
+	v, ok := c.Data.Get(section)
+	if !ok {
+		return nil, false
+	}
+	omap, ok := v.(orderedmap.OrderedMap)
+	if !ok {
+		return nil, false
+	}
+	return omap.Get(option)
+}
+
+// equal returns true if both configurations have the same ini
+// representation.
+func equal(a, b rawconfig.T) bool {
+	sa, err := a.Ini()
+	if err != nil {
+		return false
+	}
+	sb, err := b.Ini()
+	if err != nil {
+		return false
+	}
+	return sa == sb
+}
+
+func parseConfigResponse(b []byte) (rawconfig.T, error) {
+	type routedResponse struct {
+		Nodes map[string]rawconfig.T
+	}
+	d := routedResponse{}
+	if err := json.Unmarshal(b, &d); err == nil {
+		for _, c := range d.Nodes {
+			return c, nil
+		}
+	}
+	return rawconfig.FromJSON(b)
+}
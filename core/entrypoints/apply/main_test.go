@@ -0,0 +1,85 @@
+package apply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func writeManifest(t *testing.T, dir, rel, content string) {
+	p := filepath.Join(dir, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	require.NoError(t, ioutil.WriteFile(p, []byte(content), 0644))
+}
+
+func TestManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apply")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeManifest(t, dir, "svc1.conf", "[DEFAULT]\nnodes = *\n")
+	writeManifest(t, dir, "ns1/cfg/c1.conf", "[DEFAULT]\n")
+	writeManifest(t, dir, "any.json", `{"metadata": {"name": "s2", "namespace": "ns2", "kind": "svc"}, "DEFAULT": {"nodes": "*"}}`)
+	writeManifest(t, dir, "README.md", "not a manifest")
+
+	a, err := New(WithDir(dir))
+	require.NoError(t, err)
+	m, err := a.Manifests()
+	require.NoError(t, err)
+	assert.Len(t, m, 3)
+	assert.Contains(t, m, "svc1")
+	assert.Contains(t, m, "ns1/cfg/c1")
+	assert.Contains(t, m, "ns2/svc/s2")
+	_, ok := m["ns2/svc/s2"].Data.Get("metadata")
+	assert.False(t, ok, "metadata section is dropped")
+
+	a, err = New(WithDir(dir), WithNamespace("test"))
+	require.NoError(t, err)
+	m, err = a.Manifests()
+	require.NoError(t, err)
+	assert.Contains(t, m, "test/svc/svc1")
+}
+
+func TestManifestsDuplicate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "apply")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeManifest(t, dir, "svc1.conf", "[DEFAULT]\n")
+	writeManifest(t, dir, "root/svc/svc1.conf", "[DEFAULT]\n")
+
+	a, err := New(WithDir(dir))
+	require.NoError(t, err)
+	_, err = a.Manifests()
+	assert.Error(t, err)
+}
+
+func TestKeepID(t *testing.T) {
+	current, err := rawconfig.FromIni([]byte("[DEFAULT]\nid = abc\nnodes = *\n"))
+	require.NoError(t, err)
+	manifest, err := rawconfig.FromIni([]byte("[DEFAULT]\nnodes = *\n"))
+	require.NoError(t, err)
+	assert.False(t, equal(manifest, current))
+	assert.True(t, equal(keepID(manifest, current), current))
+
+	manifest, err = rawconfig.FromIni([]byte("[DEFAULT]\nnodes = n1\n"))
+	require.NoError(t, err)
+	assert.False(t, equal(keepID(manifest, current), current))
+}
+
+func TestPlanRender(t *testing.T) {
+	p1, _ := path.Parse("svc1")
+	p2, _ := path.Parse("ns1/cfg/c1")
+	plan := Plan{
+		{Op: OpCreate, Path: p1},
+		{Op: OpDelete, Path: p2},
+	}
+	assert.Equal(t, "+ svc1\n- ns1/cfg/c1\nplan: 1 to create, 0 to update, 1 to delete\n", plan.Render())
+	assert.Equal(t, "no changes\n", Plan{}.Render())
+}
@@ -0,0 +1,101 @@
+package entrypoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/key"
+)
+
+// ClusterArbitrate resolves a cluster split in favor of the segment of a
+// node, for the clusters configuring no arbitrator.
+type ClusterArbitrate struct {
+	Server string
+
+	// Winner is the name of a node of the winning segment.
+	Winner string
+}
+
+//
+// Do verifies the cluster has no arbitrator, the winner is a cluster
+// node and the daemon detects a split, then asks the daemon to vote for
+// the winner segment.
+//
+func (t ClusterArbitrate) Do() error {
+	winner, err := t.check()
+	if err != nil {
+		return err
+	}
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	b, err := c.NewGetDaemonStatus().Do()
+	if err != nil {
+		return err
+	}
+	split, err := splitStatus(b)
+	if err != nil {
+		return err
+	}
+	req := c.NewPostArbitrate()
+	req.Winner = winner
+	if _, err := req.Do(); err != nil {
+		return err
+	}
+	log.Info().
+		Str("winner", winner).
+		Strs("segment", split.Segment).
+		Strs("lost", split.Lost).
+		Msg("split arbitrated")
+	return nil
+}
+
+// check returns the lowercased winner node name, or an error if the
+// arbitration is not applicable.
+func (t ClusterArbitrate) check() (string, error) {
+	winner := strings.ToLower(t.Winner)
+	if winner == "" {
+		return "", fmt.Errorf("the winner node is required")
+	}
+	ccfg := object.NewCcfg(clusterPath)
+	nodes := strings.Fields(ccfg.Config().Get(key.New("cluster", "nodes")))
+	if !hasNode(nodes, winner) {
+		return "", fmt.Errorf("%s is not a cluster node: %s", winner, strings.Join(nodes, " "))
+	}
+	for _, section := range object.NewNode().MergedConfig().SectionStrings() {
+		if strings.HasPrefix(section, "arbitrator#") {
+			return "", fmt.Errorf("the %s arbitrator is configured: the splits are resolved by the arbitrators vote", strings.TrimPrefix(section, "arbitrator#"))
+		}
+	}
+	return winner, nil
+}
+
+func hasNode(nodes []string, node string) bool {
+	for _, s := range nodes {
+		if strings.ToLower(s) == node {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStatus returns the split status of the daemon status document, or
+// an error if the daemon detects no split.
+func splitStatus(b []byte) (cluster.SplitStatus, error) {
+	var data cluster.Status
+	if err := json.Unmarshal(b, &data); err != nil {
+		return cluster.SplitStatus{}, err
+	}
+	split := data.Cluster.Split
+	if !split.Split {
+		return split, fmt.Errorf("no cluster split detected")
+	}
+	return split, nil
+}
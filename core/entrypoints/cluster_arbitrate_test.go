@@ -0,0 +1,58 @@
+package entrypoints
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+func TestClusterArbitrateCheck(t *testing.T) {
+	root, err := ioutil.TempDir("", "clusterarbitrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	defer hostname.Impersonate("node1")()
+
+	ccfg := object.NewCcfg(clusterPath)
+	require.NoError(t, ccfg.SetKeywords([]string{"cluster.nodes=node1 node2"}))
+
+	_, err = ClusterArbitrate{}.check()
+	assert.Error(t, err, "the winner is required")
+	_, err = ClusterArbitrate{Winner: "node3"}.check()
+	assert.Error(t, err, "the winner must be a cluster node")
+	winner, err := ClusterArbitrate{Winner: "Node2"}.check()
+	assert.NoError(t, err)
+	assert.Equal(t, "node2", winner)
+
+	require.NoError(t, ccfg.SetKeywords([]string{"arbitrator#1.name=arb1", "arbitrator#1.secret=s1"}))
+	_, err = ClusterArbitrate{Winner: "node2"}.check()
+	assert.Error(t, err, "the arbitrators resolve the splits")
+}
+
+func TestSplitStatus(t *testing.T) {
+	_, err := splitStatus([]byte(`{
+		"cluster": {"nodes": ["node1", "node2", "node3"]},
+		"hb#1.rx": {"peers": {"node2": {"beating": true}, "node3": {"beating": false}}}
+	}`))
+	assert.Error(t, err, "the segment holds the majority")
+
+	split, err := splitStatus([]byte(`{
+		"cluster": {"nodes": ["node1", "node2", "node3", "node4"]},
+		"hb#1.rx": {"peers": {"node2": {"beating": true}, "node3": {"beating": false}, "node4": {"beating": false}}},
+		"hb#2.rx": {"peers": {"node2": {"beating": false}, "node3": {"beating": false}, "node4": {"beating": false}}}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"node1", "node2"}, split.Segment)
+	assert.Equal(t, []string{"node3", "node4"}, split.Lost)
+	assert.Equal(t, []string{"node2"}, split.Disagree)
+}
@@ -0,0 +1,105 @@
+package entrypoints
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// ClusterInit bootstraps a one-node cluster on the local node.
+type ClusterInit struct {
+	// Name is the cluster name.
+	Name string
+
+	// Secret is the cluster secret. A random secret is generated if
+	// empty.
+	Secret string
+
+	// Force allows the reinitialization of a cluster, discarding its
+	// identity, secret and certificates.
+	Force bool
+}
+
+//
+// Do writes the cluster identity, name, secret and the local node as
+// the only cluster node in cluster.conf, then generates the listener ca
+// and the node certificate secrets. The other nodes can then join the
+// cluster with the secret.
+//
+func (t ClusterInit) Do() error {
+	name := strings.ToLower(t.Name)
+	if name == "" {
+		return fmt.Errorf("the cluster name is required")
+	}
+	if err := hostname.Validate(name); err != nil {
+		return fmt.Errorf("invalid cluster name: %w", err)
+	}
+	secret := t.Secret
+	if secret == "" {
+		secret = strings.ReplaceAll(uuid.New().String(), "-", "")
+	}
+	nodename := hostname.Hostname()
+
+	ccfg := object.NewCcfg(clusterPath)
+	if id := ccfg.Config().Get(key.New("cluster", "id")); id != "" && !t.Force {
+		return fmt.Errorf("the cluster is already initialized with id %s, set --force to reinitialize", id)
+	}
+	err := ccfg.SetKeywords([]string{
+		"cluster.id=" + uuid.New().String(),
+		"cluster.name=" + name,
+		"cluster.secret=" + secret,
+		"cluster.nodes=" + nodename,
+	})
+	if err != nil {
+		return fmt.Errorf("install the cluster configuration: %w", err)
+	}
+	if err := unsetNodeMembership(); err != nil {
+		return err
+	}
+
+	// The secrets keys are encrypted with the new cluster secret.
+	rawconfig.Node.Cluster.Name = name
+	rawconfig.Node.Cluster.Secret = secret
+
+	paths := clusterSecPaths(ccfg, name)
+	caPath, certPath := paths[0], paths[len(paths)-1]
+	if err := genCert(caPath, []string{
+		"cn=ca-" + name,
+		"o=" + name,
+		"validity=10y",
+	}); err != nil {
+		return err
+	}
+	if err := genCert(certPath, []string{
+		"ca=" + caPath.String(),
+		"cn=" + nodename,
+		"o=" + name,
+		"alt_names=" + nodename,
+	}); err != nil {
+		return err
+	}
+	log.Info().Str("cluster", name).Str("node", nodename).Msg("initialized")
+	return nil
+}
+
+// genCert sets the certificate keywords of the secret and generates its
+// private key and certificate.
+func genCert(p path.T, kws []string) error {
+	sec := object.NewSec(p)
+	if err := sec.SetKeywords(kws); err != nil {
+		return fmt.Errorf("configure %s: %w", p, err)
+	}
+	if err := sec.GenCert(object.OptsGenCert{}); err != nil {
+		return fmt.Errorf("generate the %s certificate: %w", p, err)
+	}
+	log.Info().Stringer("path", p).Msg("certificate generated")
+	return nil
+}
@@ -0,0 +1,69 @@
+package entrypoints
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+func decodeCert(t *testing.T, s string) *x509.Certificate {
+	p, err := path.Parse(s)
+	require.NoError(t, err)
+	sec := object.NewSec(p, object.WithVolatile(true))
+	require.True(t, sec.Exists(), "%s is created", s)
+	b, err := sec.CustomDecode(sec.Config().Get(key.New(object.DataSectionName, "certificate")))
+	require.NoError(t, err)
+	block, _ := pem.Decode(b)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestClusterInit(t *testing.T) {
+	root, err := ioutil.TempDir("", "clusterinit")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	defer hostname.Impersonate("node1")()
+
+	assert.Error(t, ClusterInit{}.Do(), "the name is required")
+	assert.Error(t, ClusterInit{Name: "c_1"}.Do(), "the name must be a valid dns label")
+
+	require.NoError(t, ClusterInit{Name: "C1", Secret: "s1"}.Do())
+	cf := object.NewCcfg(clusterPath).Config()
+	id := cf.Get(key.New("cluster", "id"))
+	assert.NotEmpty(t, id)
+	assert.Equal(t, "c1", cf.Get(key.New("cluster", "name")))
+	assert.Equal(t, "s1", cf.Get(key.New("cluster", "secret")))
+	assert.Equal(t, "node1", cf.Get(key.New("cluster", "nodes")))
+
+	ca := decodeCert(t, "system/sec/ca-c1")
+	assert.True(t, ca.IsCA)
+	cert := decodeCert(t, "system/sec/cert-c1")
+	assert.Equal(t, "node1", cert.Subject.CommonName)
+	assert.NoError(t, cert.CheckSignatureFrom(ca), "the node certificate is signed by the ca")
+	assert.NotEqual(t, ca.SerialNumber, cert.SerialNumber)
+
+	assert.Error(t, ClusterInit{Name: "c1"}.Do(), "an initialized cluster is not reinitialized")
+	assert.Equal(t, id, object.NewCcfg(clusterPath).Config().Get(key.New("cluster", "id")))
+
+	require.NoError(t, ClusterInit{Name: "c1", Force: true}.Do())
+	cf = object.NewCcfg(clusterPath).Config()
+	assert.NotEqual(t, id, cf.Get(key.New("cluster", "id")))
+	assert.NotEqual(t, "s1", cf.Get(key.New("cluster", "secret")), "a random secret is generated")
+}
@@ -0,0 +1,206 @@
+package create
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/file"
+)
+
+type (
+	// backup is the configuration file content of an object before its
+	// installation, restored on rollback.
+	backup struct {
+		file    string
+		data    []byte
+		mode    os.FileMode
+		existed bool
+	}
+)
+
+//
+// Handle is the daemon side of the object create api. It renders the
+// objects definitions of the request, validates them all against the
+// drivers keywords, then installs them all or none: a failed
+// installation restores the configurations installed before the
+// failure.
+//
+func Handle(req api.PostObjectCreate) (api.ObjectCreateResult, error) {
+	pivot, err := pivotFromRequest(req)
+	if err != nil {
+		return api.ObjectCreateResult{}, err
+	}
+	t := T{
+		namespace: req.Namespace,
+		restore:   req.Restore,
+		env:       envOverrides(req.Env),
+	}
+	if pivot, err = pivot.relocate(t.namespace); err != nil {
+		return api.ObjectCreateResult{}, err
+	}
+	if pivot, err = t.prepare(pivot); err != nil {
+		return api.ObjectCreateResult{}, err
+	}
+	return pivot.install()
+}
+
+// pivotFromRequest returns the objects definitions of the request, from
+// its template, configuration or data.
+func pivotFromRequest(req api.PostObjectCreate) (Pivot, error) {
+	pivot := make(Pivot)
+	sources := 0
+	for _, set := range []bool{req.Template != "", req.Config != "", len(req.Data) > 0} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources == 0:
+		return pivot, fmt.Errorf("no object definition: set template, config or data")
+	case sources > 1:
+		return pivot, fmt.Errorf("template, config and data are conflicting")
+	case len(req.Data) > 0:
+		b, err := json.Marshal(req.Data)
+		if err != nil {
+			return pivot, err
+		}
+		if err := json.Unmarshal(b, &pivot); err != nil {
+			return pivot, fmt.Errorf("invalid data: %w", err)
+		}
+		return pivot, nil
+	}
+	p, err := path.Parse(req.ObjectSelector)
+	if err != nil {
+		return pivot, fmt.Errorf("the path is required with a template or a config: %w", err)
+	}
+	s := req.Config
+	if req.Template != "" {
+		if s, err = object.NewNode().ProvisioningTemplate(req.Template); err != nil {
+			return pivot, err
+		}
+	}
+	c, err := rawconfig.Parse([]byte(s))
+	if err != nil {
+		return pivot, fmt.Errorf("invalid config: %w", err)
+	}
+	pivot[p.String()] = c
+	return pivot, nil
+}
+
+// envOverrides returns the <key>=<value> env overrides of the env map,
+// sorted by key.
+func envOverrides(m map[string]string) []string {
+	l := make([]string, 0, len(m))
+	for k, v := range m {
+		l = append(l, k+"="+v)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// paths returns the sorted object paths of the pivot.
+func (t Pivot) paths() []string {
+	l := make([]string, 0, len(t))
+	for s := range t {
+		l = append(l, s)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// withIDs returns the pivot with a new id set in the configurations not
+// having one, so the objects installed on multiple nodes share their id.
+func (t Pivot) withIDs() (Pivot, error) {
+	pivot := make(Pivot)
+	for s, c := range t {
+		f, err := c.IniFile()
+		if err != nil {
+			return t, fmt.Errorf("%s: %w", s, err)
+		}
+		if section := f.Section("DEFAULT"); !section.HasKey("id") {
+			section.Key("id").SetValue(uuid.New().String())
+		}
+		pivot[s] = rawconfig.FromIniFile(f)
+	}
+	return pivot, nil
+}
+
+//
+// install commits the configurations of the pivot objects, all or none:
+// on failure, the configurations installed before are restored and the
+// new objects configuration files are removed.
+//
+func (t Pivot) install() (api.ObjectCreateResult, error) {
+	result := api.ObjectCreateResult{
+		Created: make([]string, 0),
+		Updated: make([]string, 0),
+	}
+	done := make([]backup, 0, len(t))
+	for _, s := range t.paths() {
+		p, err := path.Parse(s)
+		if err != nil {
+			rollback(done)
+			return result, err
+		}
+		oc := object.NewConfigurerFromPath(p)
+		b, err := newBackup(oc.ConfigFile())
+		if err != nil {
+			rollback(done)
+			return result, fmt.Errorf("%s: %w", s, err)
+		}
+		if err := oc.Config().CommitData(t[s]); err != nil {
+			rollback(append(done, b))
+			return result, fmt.Errorf("%s: %w", s, err)
+		}
+		done = append(done, b)
+		if b.existed {
+			result.Updated = append(result.Updated, s)
+		} else {
+			result.Created = append(result.Created, s)
+		}
+	}
+	return result, nil
+}
+
+func newBackup(p string) (backup, error) {
+	b := backup{file: p}
+	info, err := os.Stat(p)
+	switch {
+	case os.IsNotExist(err):
+		return b, nil
+	case err != nil:
+		return b, err
+	}
+	if b.data, err = ioutil.ReadFile(p); err != nil {
+		return b, err
+	}
+	b.mode = info.Mode().Perm()
+	b.existed = true
+	return b, nil
+}
+
+// rollback restores the configuration files saved before their
+// installation, most recent first.
+func rollback(l []backup) {
+	for i := len(l) - 1; i >= 0; i-- {
+		b := l[i]
+		var err error
+		if b.existed {
+			err = file.AtomicWrite(b.file, b.data, b.mode)
+		} else {
+			err = os.Remove(b.file)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Str("file", b.file).Msg("create rollback")
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package create
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/client/api"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestHandle(t *testing.T) {
+	_, cleanup := setup(t)
+	defer cleanup()
+	etc := rawconfig.Node.Paths.Etc
+
+	t.Run("install a config with env overrides", func(t *testing.T) {
+		result, err := Handle(api.PostObjectCreate{
+			ObjectSelector: "svc1",
+			Config:         testConfig,
+			Env:            map[string]string{"greet": "hello"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"svc1"}, result.Created)
+		b, err := ioutil.ReadFile(filepath.Join(etc, "svc1.conf"))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "greet = hello")
+	})
+
+	t.Run("refuse conflicting definitions", func(t *testing.T) {
+		_, err := Handle(api.PostObjectCreate{
+			ObjectSelector: "svc1",
+			Config:         testConfig,
+			Template:       "t1",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("validate all objects before installing any", func(t *testing.T) {
+		_, err := Handle(api.PostObjectCreate{
+			Data: map[string]interface{}{
+				"svc2": map[string]interface{}{"DEFAULT": map[string]interface{}{"nodes": "n1"}},
+				"svc3": map[string]interface{}{"DEFAULT": map[string]interface{}{"foo": "bar"}},
+			},
+		})
+		assert.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(etc, "svc2.conf"))
+		assert.NoFileExists(t, filepath.Join(etc, "svc3.conf"))
+	})
+}
+
+func TestInstallRollback(t *testing.T) {
+	pivot, cleanup := setup(t)
+	defer cleanup()
+	etc := rawconfig.Node.Paths.Etc
+	require.NoError(t, os.MkdirAll(etc, 0755))
+	previous := []byte("[DEFAULT]\nid = 22222222-2222-2222-2222-222222222222\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(etc, "svc1.conf"), previous, 0644))
+
+	// a directory in place of the svc3 configuration file fails its install
+	require.NoError(t, os.MkdirAll(filepath.Join(etc, "svc3.conf"), 0755))
+	pivot["svc2"] = pivot["svc1"]
+	pivot["svc3"] = pivot["svc1"]
+
+	_, err := pivot.install()
+	assert.Error(t, err)
+	b, err := ioutil.ReadFile(filepath.Join(etc, "svc1.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, previous, b, "the updated configuration is restored")
+	assert.NoFileExists(t, filepath.Join(etc, "svc2.conf"), "the new configuration is removed")
+}
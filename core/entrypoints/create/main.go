@@ -1,32 +1,42 @@
 package create
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/iancoleman/orderedmap"
+	"github.com/rs/zerolog/log"
 	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/client/api"
 	"opensvc.com/opensvc/core/clientcontext"
+	"opensvc.com/opensvc/core/entrypoints/action"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/key"
 	"opensvc.com/opensvc/util/uri"
 )
 
 type (
 	T struct {
-		client    *client.T
-		path      path.T
-		namespace string
-		config    string
-		template  string
-		keywords  []string
-		restore   bool
+		client      *client.T
+		path        path.T
+		namespace   string
+		config      string
+		template    string
+		keywords    []string
+		env         []string
+		interactive bool
+		restore     bool
+		nodes       string
 	}
 	Pivot map[string]rawconfig.T
 )
@@ -86,6 +96,30 @@ func WithKeywords(s []string) funcopt.O {
 	})
 }
 
+//
+// WithEnv sets the <key>=<value> overrides of the env section keys of the
+// new objects.
+//
+func WithEnv(s []string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.env = s
+		return nil
+	})
+}
+
+//
+// WithInteractive sets the prompt of the user for the env section keys
+// values of the new objects.
+//
+func WithInteractive(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.interactive = v
+		return nil
+	})
+}
+
 func WithClient(c *client.T) funcopt.O {
 	return funcopt.F(func(i interface{}) error {
 		t := i.(*T)
@@ -102,6 +136,19 @@ func WithRestore(v bool) funcopt.O {
 	})
 }
 
+//
+// WithNodes sets the selector of the nodes where to install the new
+// objects through the api. The objects are created on all the selected
+// nodes or none.
+//
+func WithNodes(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.nodes = s
+		return nil
+	})
+}
+
 func New(opts ...funcopt.O) (*T, error) {
 	t := &T{}
 	if err := funcopt.Apply(t, opts...); err != nil {
@@ -114,11 +161,13 @@ func (t T) Do() error {
 	switch {
 	case t.template != "" && t.config != "":
 		return fmt.Errorf("--config and --template are conflicting")
+	case t.interactive && t.isStdinConfig():
+		return fmt.Errorf("--interactive and --config=%s are conflicting", t.config)
 	case t.template != "":
 		return t.fromTemplate()
 	case t.config == "":
 		return t.fromScratch()
-	case t.config == "-" || t.config == "/dev/stdin" || t.config == "stdin":
+	case t.isStdinConfig():
 		return t.fromStdin()
 	case t.config != "":
 		return t.fromConfig()
@@ -127,18 +176,80 @@ func (t T) Do() error {
 	}
 }
 
+func (t T) isStdinConfig() bool {
+	return t.config == "-" || t.config == "/dev/stdin" || t.config == "stdin"
+}
+
 func (t *T) submit(pivot Pivot) error {
+	if t.nodes == "" {
+		_, err := t.submitTo(pivot, "", t.restore)
+		return err
+	}
+	nodes, err := action.RemoteNodes(t.client, t.nodes)
+	if err != nil {
+		return err
+	}
+	// the objects share their id on all nodes
+	if pivot, err = pivot.withIDs(); err != nil {
+		return err
+	}
+	done := make(map[string]api.ObjectCreateResult)
+	for _, node := range nodes {
+		result, err := t.submitTo(pivot, node, true)
+		if err != nil {
+			t.rollback(done)
+			return fmt.Errorf("%s: %w", node, err)
+		}
+		done[node] = result
+	}
+	return nil
+}
+
+// submitTo posts the objects definitions to the node api, or to the
+// default api endpoint if node is empty.
+func (t *T) submitTo(pivot Pivot, node string, restore bool) (api.ObjectCreateResult, error) {
+	var result api.ObjectCreateResult
 	data := make(map[string]interface{})
 	for opath, c := range pivot {
 		data[opath] = c
 	}
 	req := t.client.NewPostObjectCreate()
-	req.Restore = t.restore
+	req.Restore = restore
 	req.Data = data
-	if _, err := req.Do(); err != nil {
-		return err
+	req.SetNode(node)
+	b, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+	if node != "" {
+		if err := json.Unmarshal(b, &result); err != nil {
+			return result, fmt.Errorf("decode the create response: %w", err)
+		}
+	}
+	return result, nil
+}
+
+//
+// rollback deletes the objects created on the nodes before a failure.
+// The configurations updated on these nodes can not be restored through
+// the api, so they are only reported.
+//
+func (t *T) rollback(done map[string]api.ObjectCreateResult) {
+	for node, result := range done {
+		for _, s := range result.Created {
+			req := t.client.NewPostObjectAction()
+			req.ObjectSelector = s
+			req.NodeSelector = node
+			req.Action = "delete"
+			req.SetNode(node)
+			if _, err := req.Do(); err != nil {
+				log.Warn().Err(err).Str("node", node).Str("path", s).Msg("create rollback")
+			}
+		}
+		for _, s := range result.Updated {
+			log.Warn().Str("node", node).Str("path", s).Msg("create rollback: the updated configuration is not restored")
+		}
 	}
-	return nil
 }
 
 func (t T) fromTemplate() error {
@@ -183,15 +294,148 @@ func (t T) fromStdin() error {
 }
 
 func (t T) fromData(pivot Pivot) error {
-	// TODO: kws
-	if clientcontext.IsSet() {
+	pivot, err := pivot.relocate(t.namespace)
+	if err != nil {
+		return err
+	}
+	if pivot, err = t.prepare(pivot); err != nil {
+		return err
+	}
+	if clientcontext.IsSet() || t.nodes != "" {
 		return t.submit(pivot)
 	}
 	return localFromData(pivot)
 }
 
+// relocate returns the pivot with all the object paths moved to the
+// namespace set by the WithNamespace option, so the objects can not be
+// created outside this namespace. The relocations are logged.
+func (t Pivot) relocate(namespace string) (Pivot, error) {
+	if namespace == "" {
+		return t, nil
+	}
+	pivot := make(Pivot)
+	for s, c := range t {
+		p, err := path.Parse(s)
+		if err != nil {
+			return t, err
+		}
+		if p.Namespace != namespace {
+			from := p.String()
+			p.Namespace = namespace
+			log.Info().Str("path", from).Msgf("relocate %s to %s", from, p)
+		}
+		pivot[p.String()] = c
+	}
+	return pivot, nil
+}
+
+// prepare returns the pivot with the configurations amended by the
+// create options and validated.
+func (t T) prepare(pivot Pivot) (Pivot, error) {
+	prepared := make(Pivot)
+	for s, c := range pivot {
+		p, err := path.Parse(s)
+		if err != nil {
+			return pivot, err
+		}
+		if c, err = t.prepareConfig(p, c); err != nil {
+			return pivot, fmt.Errorf("%s: %w", s, err)
+		}
+		prepared[s] = c
+	}
+	return prepared, nil
+}
+
+// prepareConfig loads the configuration in a volatile object to drop the
+// origin id unless restoring, set the env overrides, prompt for the env
+// values, apply the keyword operations and validate the keywords.
+func (t T) prepareConfig(p path.T, c rawconfig.T) (rawconfig.T, error) {
+	oc := object.NewFromPath(p, object.WithVolatile(true)).(object.Configurer)
+	config := oc.Config()
+	if c.IsZero() {
+		c = rawconfig.T{Data: orderedmap.New()}
+	}
+	if err := config.CommitDataToInvalid(c, ""); err != nil {
+		return c, err
+	}
+	if !t.restore {
+		// the commit adds a new id
+		config.Unset(key.New("DEFAULT", "id"))
+	}
+	kws := make([]string, 0)
+	for _, s := range t.env {
+		l := strings.SplitN(s, "=", 2)
+		if len(l) != 2 {
+			return c, fmt.Errorf("invalid env override: %s. expected <key>=<value>", s)
+		}
+		kws = append(kws, "env."+s)
+	}
+	if t.interactive {
+		l, err := promptEnv(config, os.Stdin, os.Stdout)
+		if err != nil {
+			return c, err
+		}
+		kws = append(kws, l...)
+	}
+	kws = append(kws, t.keywords...)
+	if err := oc.Set(object.OptsSet{KeywordOps: kws}); err != nil {
+		return c, err
+	}
+	if err := config.CommitInvalid(); err != nil {
+		return c, err
+	}
+	if err := config.Validate(); err != nil {
+		return c, err
+	}
+	return config.Raw(), nil
+}
+
+// promptEnv prompts the user for the value of each env section key,
+// proposing the current value as default, and returns the keyword
+// operations setting the new values.
+func promptEnv(config *xconfig.T, r io.Reader, w io.Writer) ([]string, error) {
+	kws := make([]string, 0)
+	reader := bufio.NewReader(r)
+	for _, option := range config.Keys("env") {
+		k := key.New("env", option)
+		def := config.Get(k)
+		if def == "" {
+			fmt.Fprintf(w, "%s: ", option)
+		} else {
+			fmt.Fprintf(w, "%s [%s]: ", option, def)
+		}
+		s, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return kws, err
+		}
+		s = strings.TrimSpace(s)
+		switch {
+		case s != "":
+			kws = append(kws, fmt.Sprintf("%s=%s", k, s))
+		case def == "":
+			return kws, fmt.Errorf("no value for %s and no default", k)
+		}
+	}
+	return kws, nil
+}
+
 func (t T) rawFromTemplate() (Pivot, error) {
-	return nil, fmt.Errorf("TODO: collector requester")
+	s, err := object.NewNode().ProvisioningTemplate(t.template)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile("", "template.*.conf")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		return nil, err
+	}
+	fmt.Print("fetched... ")
+	return rawFromConfigFile(t.path, f.Name())
 }
 
 func (t T) rawFromConfig() (Pivot, error) {
@@ -218,6 +462,16 @@ func rawFromConfigURI(p path.T, u uri.T) (Pivot, error) {
 
 func rawFromConfigFile(p path.T, fpath string) (Pivot, error) {
 	pivot := make(Pivot)
+	if b, err := ioutil.ReadFile(fpath); err == nil && rawconfig.IsJSON(b) {
+		// json formatted configuration, as produced by "print config --format json"
+		c, err := rawconfig.FromJSON(b)
+		if err != nil {
+			return pivot, err
+		}
+		pivot[p.String()] = c
+		fmt.Print("parsed... ")
+		return pivot, nil
+	}
 	c, err := xconfig.NewObject(fpath)
 	if err != nil {
 		return pivot, err
@@ -243,7 +497,7 @@ func rawFromStdinNested(namespace string) (Pivot, error) {
 		return pivot, err
 	}
 	if md, ok := pivot["metadata"]; ok {
-		p, err := pathFromMetadata(md.Data)
+		p, err := PathFromMetadata(md.Data)
 		if err != nil {
 			return pivot, err
 		}
@@ -255,7 +509,8 @@ func rawFromStdinNested(namespace string) (Pivot, error) {
 	return pivot, nil
 }
 
-func pathFromMetadata(data *orderedmap.OrderedMap) (path.T, error) {
+// PathFromMetadata returns the object path described by a "metadata" section.
+func PathFromMetadata(data *orderedmap.OrderedMap) (path.T, error) {
 	var name, namespace, kind string
 	if s, ok := data.Get("name"); ok {
 		if name, ok = s.(string); !ok {
@@ -299,25 +554,16 @@ func rawFromBytesFlat(p path.T, b []byte) (Pivot, error) {
 }
 
 func localFromData(pivot Pivot) error {
-	for opath, c := range pivot {
-		p, err := path.Parse(opath)
-		if err != nil {
-			return err
-		}
-		if err = localFromRaw(p, c); err != nil {
-			return err
-		}
+	result, err := pivot.install()
+	if err != nil {
+		return err
+	}
+	for _, opath := range append(result.Created, result.Updated...) {
 		fmt.Println(opath, "commited")
 	}
 	return nil
 }
 
-func localFromRaw(p path.T, c rawconfig.T) error {
-	o := object.NewFromPath(p)
-	oc := o.(object.Configurer)
-	return oc.Config().CommitData(c)
-}
-
 func LocalEmpty(p path.T) error {
 	o := object.NewFromPath(p)
 	oc := o.(object.Configurer)
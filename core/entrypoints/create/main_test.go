@@ -0,0 +1,147 @@
+package create
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+const testConfig = `[DEFAULT]
+id = 11111111-1111-1111-1111-111111111111
+nodes = {env.nodes}
+
+[env]
+nodes = n1 n2
+greet =
+`
+
+func setup(t *testing.T) (Pivot, func()) {
+	root, err := ioutil.TempDir("", "create")
+	require.NoError(t, err)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	cf := filepath.Join(root, "template.conf")
+	require.NoError(t, ioutil.WriteFile(cf, []byte(testConfig), 0644))
+	p, _ := path.Parse("svc1")
+	pivot, err := rawFromConfigFile(p, cf)
+	require.NoError(t, err)
+	return pivot, func() {
+		rawconfig.Load(map[string]string{})
+		os.RemoveAll(root)
+	}
+}
+
+func getOption(t *testing.T, c rawconfig.T, section, option string) string {
+	file, err := c.IniFile()
+	require.NoError(t, err)
+	return file.Section(section).Key(option).Value()
+}
+
+func newTestConfig(t *testing.T, p path.T, c rawconfig.T) *xconfig.T {
+	config := object.NewFromPath(p, object.WithVolatile(true)).(object.Configurer).Config()
+	require.NoError(t, config.CommitData(c))
+	return config
+}
+
+func TestPrepare(t *testing.T) {
+	t.Run("generate a new id", func(t *testing.T) {
+		pivot, cleanup := setup(t)
+		defer cleanup()
+		pivot, err := T{}.prepare(pivot)
+		require.NoError(t, err)
+		id := getOption(t, pivot["svc1"], "DEFAULT", "id")
+		assert.NotEqual(t, "11111111-1111-1111-1111-111111111111", id)
+		assert.NotEmpty(t, id)
+	})
+
+	t.Run("keep the id on restore", func(t *testing.T) {
+		pivot, cleanup := setup(t)
+		defer cleanup()
+		pivot, err := T{restore: true}.prepare(pivot)
+		require.NoError(t, err)
+		assert.Equal(t, "11111111-1111-1111-1111-111111111111", getOption(t, pivot["svc1"], "DEFAULT", "id"))
+	})
+
+	t.Run("set the env overrides and keywords", func(t *testing.T) {
+		pivot, cleanup := setup(t)
+		defer cleanup()
+		pivot, err := T{
+			env:      []string{"greet=hello"},
+			keywords: []string{"orchestrate=ha"},
+		}.prepare(pivot)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", getOption(t, pivot["svc1"], "env", "greet"))
+		assert.Equal(t, "ha", getOption(t, pivot["svc1"], "DEFAULT", "orchestrate"))
+	})
+
+	t.Run("refuse an invalid env override", func(t *testing.T) {
+		pivot, cleanup := setup(t)
+		defer cleanup()
+		_, err := T{env: []string{"greet"}}.prepare(pivot)
+		assert.Error(t, err)
+	})
+
+	t.Run("refuse an unknown keyword", func(t *testing.T) {
+		pivot, cleanup := setup(t)
+		defer cleanup()
+		_, err := T{keywords: []string{"foo=bar"}}.prepare(pivot)
+		assert.Error(t, err)
+	})
+}
+
+func TestPromptEnv(t *testing.T) {
+	pivot, cleanup := setup(t)
+	defer cleanup()
+	p, _ := path.Parse("svc1")
+	c := pivot["svc1"]
+
+	t.Run("keep the default on empty input", func(t *testing.T) {
+		config := newTestConfig(t, p, c)
+		var w bytes.Buffer
+		_, err := promptEnv(config, strings.NewReader("\nhello\n"), &w)
+		require.NoError(t, err)
+		assert.Contains(t, w.String(), "nodes [n1 n2]: ")
+	})
+
+	t.Run("fail if no value and no default", func(t *testing.T) {
+		config := newTestConfig(t, p, c)
+		_, err := promptEnv(config, strings.NewReader("n3\n\n"), &bytes.Buffer{})
+		assert.Error(t, err)
+	})
+
+	t.Run("set the input values", func(t *testing.T) {
+		config := newTestConfig(t, p, c)
+		kws, err := promptEnv(config, strings.NewReader("n3\nhello\n"), &bytes.Buffer{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			key.New("env", "nodes").String() + "=n3",
+			key.New("env", "greet").String() + "=hello",
+		}, kws)
+	})
+}
+
+func TestRelocate(t *testing.T) {
+	pivot, cleanup := setup(t)
+	defer cleanup()
+	pivot["prod/svc/svc2"] = pivot["svc1"]
+
+	relocated, err := pivot.relocate("")
+	require.NoError(t, err)
+	assert.Equal(t, pivot, relocated)
+
+	relocated, err = pivot.relocate("prod")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"prod/svc/svc1", "prod/svc/svc2"}, relocated.paths())
+}
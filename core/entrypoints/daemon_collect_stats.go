@@ -0,0 +1,54 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/nodestats"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/key"
+)
+
+// DaemonCollectStats samples the node resources usage at the interval set
+// by the stats.interval node keyword, and stores the samples in the node
+// stats ring database.
+type DaemonCollectStats struct{}
+
+// Do samples the node resources usage until the process is interrupted.
+func (t DaemonCollectStats) Do() error {
+	return t.Run(context.Background())
+}
+
+// Run samples the node resources usage until the context is done.
+func (t DaemonCollectStats) Run(ctx context.Context) error {
+	node := object.NewNode()
+	interval, err := node.MergedConfig().GetDurationStrict(key.New("stats", "interval"))
+	if err != nil {
+		return fmt.Errorf("stats.interval: %w", err)
+	}
+	if interval == nil || *interval < time.Second {
+		return fmt.Errorf("stats.interval: must be at least 1s")
+	}
+	db := node.StatsDB()
+	disable := node.StatsDisabled()
+	for {
+		// the sample rates are averaged over the whole interval
+		s, err := nodestats.Collect(ctx, *interval, disable)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			node.Log().Error().Err(err).Msg("collect stats")
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(*interval):
+			}
+			continue
+		}
+		if err := db.Append(s); err != nil {
+			node.Log().Error().Err(err).Msg("store stats")
+		}
+	}
+}
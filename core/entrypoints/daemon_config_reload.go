@@ -0,0 +1,52 @@
+package entrypoints
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/monstate"
+	"opensvc.com/opensvc/core/orchestjournal"
+)
+
+// daemonConfigReload is the daemon thread applying the configuration
+// changes detected by the configuration watcher.
+type daemonConfigReload struct {
+	events <-chan cfgwatch.Event
+}
+
+// Run consumes the configuration change events until the context is
+// done or the watcher stops.
+func (t daemonConfigReload) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-t.events:
+			if !ok {
+				return nil
+			}
+			t.apply(ev)
+		}
+	}
+}
+
+//
+// apply logs the change. The persisted monitor state and orchestration
+// intent of a removed object are dropped, so a reinstalled object with
+// the same path does not inherit them.
+//
+func (t daemonConfigReload) apply(ev cfgwatch.Event) {
+	if !ev.Deleted {
+		log.Info().Stringer("path", ev.Path).Str("csum", ev.Checksum).Msg("configuration changed")
+		return
+	}
+	log.Info().Stringer("path", ev.Path).Msg("configuration removed")
+	if err := monstate.DelInstance(ev.Path.String()); err != nil {
+		log.Warn().Err(err).Stringer("path", ev.Path).Msg("drop the monitor state")
+	}
+	if err := orchestjournal.End(ev.Path.String()); err != nil {
+		log.Warn().Err(err).Stringer("path", ev.Path).Msg("drop the orchestration intent")
+	}
+}
@@ -0,0 +1,76 @@
+package entrypoints
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+// daemonEvents is the daemon thread persisting the local node event
+// stream in the node events store, so the events emitted while no client
+// follows the stream can be replayed by "node events --since".
+type daemonEvents struct {
+	store *event.Store
+
+	// stream returns the event stream, closed when the context is done
+	// or the api connection is lost.
+	stream func(ctx context.Context) (chan event.Event, error)
+
+	// retry is the delay before reconnecting a lost event stream.
+	retry time.Duration
+}
+
+var daemonEventsRetry = 5 * time.Second
+
+// Run persists the event stream until the context is done, reconnecting
+// the stream when lost.
+func (t daemonEvents) Run(ctx context.Context) error {
+	if t.store == nil {
+		t.store = event.NewStore(hostname.Hostname())
+	}
+	if t.stream == nil {
+		t.stream = streamEvents
+	}
+	if t.retry == 0 {
+		t.retry = daemonEventsRetry
+	}
+	for {
+		if err := t.persist(ctx); err != nil {
+			log.Debug().Err(err).Msg("events: stream not available")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(t.retry):
+		}
+	}
+}
+
+// persist appends the stream events to the store until the stream is
+// closed.
+func (t daemonEvents) persist(ctx context.Context) error {
+	events, err := t.stream(ctx)
+	if err != nil {
+		return err
+	}
+	for e := range events {
+		if err := t.store.Append(e); err != nil {
+			log.Warn().Err(err).Msg("events: persist")
+		}
+	}
+	return nil
+}
+
+// streamEvents returns the local node event stream, from the daemon api.
+func streamEvents(ctx context.Context) (chan event.Event, error) {
+	c, err := client.New()
+	if err != nil {
+		return nil, err
+	}
+	return c.NewGetEvents().SetRelatives(false).SetContext(ctx).Do()
+}
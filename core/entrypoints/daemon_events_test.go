@@ -0,0 +1,53 @@
+package entrypoints
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestDaemonEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	connections := 0
+	stream := func(ctx context.Context) (chan event.Event, error) {
+		connections++
+		c := make(chan event.Event, 2)
+		if connections == 2 {
+			// the second connection replays an already stored event
+			c <- event.Event{ID: 2, Kind: "event", Timestamp: timestamp.New(time.Unix(2, 0))}
+			c <- event.Event{ID: 3, Kind: "patch", Timestamp: timestamp.New(time.Unix(3, 0))}
+			cancel()
+		} else {
+			c <- event.Event{ID: 1, Kind: "event", Timestamp: timestamp.New(time.Unix(1, 0))}
+			c <- event.Event{ID: 2, Kind: "event", Timestamp: timestamp.New(time.Unix(2, 0))}
+		}
+		close(c)
+		return c, nil
+	}
+	store := event.NewStore("n1")
+	require.NoError(t, daemonEvents{store: store, stream: stream, retry: time.Millisecond}.Run(ctx))
+
+	assert.Equal(t, 2, connections, "the lost stream is reconnected")
+	l, err := store.Read(event.Filter{})
+	require.NoError(t, err)
+	ids := make([]uint64, len(l))
+	for i, e := range l {
+		ids[i] = e.ID
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+}
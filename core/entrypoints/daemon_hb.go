@@ -0,0 +1,266 @@
+package entrypoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/hbmsg"
+	"opensvc.com/opensvc/core/hbrelay"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	//
+	// daemonHeartbeat is the daemon thread running the heartbeats
+	// configured in the cluster configuration hb#<n> sections. Only the
+	// relay heartbeats are supported by this daemon.
+	//
+	daemonHeartbeat struct {
+		data *daemondata.T
+	}
+
+	//
+	// relayHeartbeat sends the local node entry of the dataset to the
+	// relay slot of the node, and reads the peers entries from their
+	// slots, every interval.
+	//
+	// The relay only keeps the last message of each node, so a reader
+	// polling slower than the writer would miss patches: the messages
+	// hold the full document, compressed with the codec supported by
+	// all the peers.
+	//
+	relayHeartbeat struct {
+		name     string
+		nodename string
+		peers    []string
+		interval time.Duration
+		timeout  time.Duration
+		client   hbrelay.Client
+		data     *daemondata.T
+
+		created  timestamp.T
+		encoder  *hbmsg.Encoder
+		decoders map[string]*hbmsg.Decoder
+		updated  map[string]time.Time
+		last     map[string]time.Time
+	}
+)
+
+// defaultRelayPort is the port of the relay heartbeat servers, when the
+// hb relay keyword does not specify one.
+const defaultRelayPort = "1217"
+
+// Run runs the configured heartbeats until the context is done.
+func (t daemonHeartbeat) Run(ctx context.Context) error {
+	config := object.NewNode().MergedConfig()
+	var wg sync.WaitGroup
+	for _, s := range config.SectionStrings() {
+		if !strings.HasPrefix(s, "hb#") {
+			continue
+		}
+		hbType := config.GetString(key.New(s, "type"))
+		if hbType != "relay" {
+			log.Warn().Str("hb", s).Str("type", hbType).Msg("hb: type not supported by this daemon, ignored")
+			continue
+		}
+		hb, err := newRelayHeartbeat(s, config, t.data)
+		if err != nil {
+			log.Error().Err(err).Str("hb", s).Msg("hb: ignored")
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hb.run(ctx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// newRelayHeartbeat returns the relay heartbeat configured by the
+// section s of the cluster configuration.
+func newRelayHeartbeat(s string, config *xconfig.T, data *daemondata.T) (*relayHeartbeat, error) {
+	relay := config.GetString(key.New(s, "relay"))
+	if relay == "" {
+		return nil, errors.New("relay is required")
+	}
+	if _, _, err := net.SplitHostPort(relay); err != nil {
+		relay = net.JoinHostPort(relay, defaultRelayPort)
+	}
+	nodename := data.Nodename()
+	nodes := config.GetSlice(key.New(s, "nodes"))
+	if len(nodes) == 0 {
+		nodes = config.GetSlice(key.New("cluster", "nodes"))
+	}
+	peers := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if node != nodename {
+			peers = append(peers, node)
+		}
+	}
+	t := &relayHeartbeat{
+		name:     s,
+		nodename: nodename,
+		peers:    peers,
+		interval: 5 * time.Second,
+		timeout:  15 * time.Second,
+		client: hbrelay.Client{
+			URL:     "https://" + relay,
+			Cluster: config.GetString(key.New("cluster", "id")),
+			Secret:  config.GetString(key.New(s, "secret")),
+		},
+		data: data,
+	}
+	if d := config.GetDuration(key.New(s, "interval")); d != nil {
+		t.interval = *d
+	}
+	if d := config.GetDuration(key.New(s, "timeout")); d != nil {
+		t.timeout = *d
+	}
+	return t, nil
+}
+
+func (t *relayHeartbeat) run(ctx context.Context) {
+	t.init()
+	log.Info().Str("hb", t.name).Str("relay", t.client.URL).Strs("peers", t.peers).Msg("hb: started")
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		t.beat(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *relayHeartbeat) init() {
+	t.created = timestamp.Now()
+	t.encoder = hbmsg.NewEncoder(hbmsg.WithFullEvery(1))
+	t.decoders = make(map[string]*hbmsg.Decoder)
+	t.updated = make(map[string]time.Time)
+	t.last = make(map[string]time.Time)
+	for _, peer := range t.peers {
+		t.decoders[peer] = hbmsg.NewDecoder()
+	}
+}
+
+// beat sends the local node entry, reads the peers entries, and updates
+// the heartbeat status in the dataset.
+func (t *relayHeartbeat) beat(ctx context.Context, now time.Time) {
+	if err := t.tx(ctx); err != nil {
+		log.Warn().Err(err).Str("hb", t.name).Msg("hb: tx")
+	}
+	for _, peer := range t.peers {
+		if err := t.rx(ctx, peer, now); err != nil {
+			log.Debug().Err(err).Str("hb", t.name).Str("peer", peer).Msg("hb: rx")
+		}
+	}
+	t.encoder.SetPeerCodecs(t.peerCodecs())
+	t.data.SetHeartbeat(t.name, t.status(now))
+}
+
+func (t *relayHeartbeat) tx(ctx context.Context) error {
+	doc, err := json.Marshal(t.data.GetNodeStatus())
+	if err != nil {
+		return err
+	}
+	frame, err := t.encoder.Encode(doc)
+	if err != nil {
+		return err
+	}
+	return t.client.Tx(ctx, t.nodename, frame)
+}
+
+// rx reads the peer slot, and sets the peer entry of the dataset if the
+// slot was updated since the previous read. The peer is beating while
+// its slot is updated, whatever the relay clock.
+func (t *relayHeartbeat) rx(ctx context.Context, peer string, now time.Time) error {
+	slot, err := t.client.Rx(ctx, peer)
+	if err != nil {
+		return err
+	}
+	if !slot.Updated.After(t.updated[peer]) {
+		return nil
+	}
+	t.updated[peer] = slot.Updated
+	doc, err := t.decoders[peer].Decode(slot.Msg)
+	if err != nil {
+		return err
+	}
+	var st cluster.NodeStatus
+	if err := json.Unmarshal(doc, &st); err != nil {
+		return err
+	}
+	t.last[peer] = now
+	t.data.SetPeerStatus(peer, st)
+	return nil
+}
+
+// peerCodecs returns the codecs advertised by all the peers, so the
+// broadcasted messages are decodable by every peer.
+func (t *relayHeartbeat) peerCodecs() []string {
+	var l []string
+	for i, peer := range t.peers {
+		codecs := t.decoders[peer].PeerCodecs()
+		if i == 0 {
+			l = codecs
+			continue
+		}
+		common := make([]string, 0, len(l))
+		for _, c := range l {
+			for _, e := range codecs {
+				if c == e {
+					common = append(common, c)
+					break
+				}
+			}
+		}
+		l = common
+	}
+	return l
+}
+
+// status returns the heartbeat status. The tx counters are the same for
+// all the peers, as each message is sent to all of them.
+func (t *relayHeartbeat) status(now time.Time) cluster.HeartbeatThreadStatus {
+	var tx cluster.HeartbeatPeerStatus
+	t.encoder.Counters().Fill(&tx, now)
+	st := cluster.HeartbeatThreadStatus{
+		ThreadStatus: cluster.ThreadStatus{
+			Created: t.created,
+			State:   "running",
+		},
+		Peers: make(map[string]cluster.HeartbeatPeerStatus),
+	}
+	for _, peer := range t.peers {
+		var ps cluster.HeartbeatPeerStatus
+		t.decoders[peer].Counters().Fill(&ps, now)
+		ps.Codec = tx.Codec
+		ps.TxBytes = tx.TxBytes
+		ps.TxRawBytes = tx.TxRawBytes
+		ps.TxMsgs = tx.TxMsgs
+		ps.TxFull = tx.TxFull
+		ps.TxMsgRate = tx.TxMsgRate
+		if last, ok := t.last[peer]; ok {
+			ps.Last = timestamp.New(last)
+			ps.Beating = now.Sub(last) < t.timeout
+		}
+		st.Peers[peer] = ps
+	}
+	return st
+}
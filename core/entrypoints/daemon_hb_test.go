@@ -0,0 +1,84 @@
+package entrypoints
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/hbmsg"
+	"opensvc.com/opensvc/core/hbrelay"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestNewRelayHeartbeat(t *testing.T) {
+	root, err := ioutil.TempDir("", "hb")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[cluster]\nid = c1\nnodes = n1 n2 n3\n\n[hb#1]\ntype = relay\nrelay = relay1\nsecret = s1\ninterval = 2s\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "cluster.conf"), b, 0644))
+
+	hb, err := newRelayHeartbeat("hb#1", object.NewNode().MergedConfig(), daemondata.New(daemondata.WithNodename("n1")))
+	require.NoError(t, err)
+	assert.Equal(t, "https://relay1:1217", hb.client.URL)
+	assert.Equal(t, "c1", hb.client.Cluster)
+	assert.Equal(t, "s1", hb.client.Secret)
+	assert.Equal(t, []string{"n2", "n3"}, hb.peers)
+	assert.Equal(t, 2*time.Second, hb.interval)
+	assert.Equal(t, 15*time.Second, hb.timeout)
+}
+
+func TestRelayHeartbeat(t *testing.T) {
+	srv := &hbrelay.Server{Retention: time.Minute, Secrets: map[string]string{"c1": "s1"}}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	newHB := func(node, peer string) *relayHeartbeat {
+		hb := &relayHeartbeat{
+			name:     "hb#1",
+			nodename: node,
+			peers:    []string{peer},
+			timeout:  15 * time.Second,
+			client:   hbrelay.Client{URL: ts.URL, Cluster: "c1", Secret: "s1"},
+			data:     daemondata.New(daemondata.WithNodename(node)),
+		}
+		hb.init()
+		return hb
+	}
+	hb1 := newHB("n1", "n2")
+	hb2 := newHB("n2", "n1")
+	hb2.data.SetNodeLabels(map[string]string{"az": "eu1"})
+
+	ctx := context.Background()
+	now := time.Now()
+	hb1.beat(ctx, now)
+	hb2.beat(ctx, now)
+	hb1.beat(ctx, now.Add(time.Second))
+
+	data := hb1.data.Get()
+	require.Contains(t, data.Monitor.Nodes, "n2", "the peer entry is received")
+	assert.Equal(t, "eu1", data.Monitor.Nodes["n2"].Labels["az"])
+	require.Contains(t, data.Heartbeats, "hb#1")
+	peer := data.Heartbeats["hb#1"].Peers["n2"]
+	assert.True(t, peer.Beating)
+	assert.Equal(t, uint64(2), peer.TxMsgs)
+	assert.Equal(t, uint64(1), peer.RxMsgs)
+	assert.Equal(t, hbmsg.CodecGzip, peer.Codec, "the codec is negotiated from the peer advertised codecs")
+	assert.False(t, data.Cluster.Split.Split)
+
+	t.Run("a peer not updating its slot is lost", func(t *testing.T) {
+		hb1.beat(ctx, now.Add(time.Minute))
+		peer := hb1.data.Get().Heartbeats["hb#1"].Peers["n2"]
+		assert.False(t, peer.Beating)
+	})
+}
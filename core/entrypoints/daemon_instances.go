@@ -0,0 +1,187 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	// daemonInstances is the daemon thread maintaining the local node
+	// and object instances configuration and status in the daemon
+	// dataset.
+	daemonInstances struct {
+		data   *daemondata.T
+		events <-chan cfgwatch.Event
+
+		// interval is the delay between two reloads of the instances status.
+		interval time.Duration
+
+		// list returns the paths of the local objects.
+		list func() []path.T
+
+		// load returns the configuration digest and status of the local
+		// instance of the object.
+		load func(p path.T) (instance.Config, instance.Status, error)
+
+		// node returns the cluster information, the node frozen
+		// timestamp and the node labels.
+		node func() nodeConfig
+	}
+
+	// nodeConfig is the node configuration published in the dataset.
+	nodeConfig struct {
+		Info   cluster.Info
+		Frozen timestamp.T
+		Labels map[string]string
+	}
+)
+
+var daemonInstancesInterval = 10 * time.Second
+
+//
+// Run loads the local instances in the dataset, then reloads an instance
+// when its configuration changes, and all instances at interval, until
+// the context is done.
+//
+func (t daemonInstances) Run(ctx context.Context) error {
+	if t.interval == 0 {
+		t.interval = daemonInstancesInterval
+	}
+	if t.list == nil {
+		t.list = object.NewSelection("**", object.SelectionWithLocal(true)).Expand
+	}
+	if t.load == nil {
+		t.load = loadInstance
+	}
+	if t.node == nil {
+		t.node = loadNode
+	}
+	t.refresh()
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.refresh()
+		case ev, ok := <-t.events:
+			if !ok {
+				t.events = nil
+				continue
+			}
+			switch {
+			case ev.Deleted:
+				t.data.DelInstance(ev.Path)
+			case ev.Path.Kind == kind.Ccfg:
+				t.refreshNode()
+				t.refreshInstance(ev.Path)
+			default:
+				t.refreshInstance(ev.Path)
+			}
+		}
+	}
+}
+
+// refresh reloads the node and all the local instances, and drops the
+// instances of the objects no longer installed.
+func (t daemonInstances) refresh() {
+	t.refreshNode()
+	installed := make(map[string]interface{})
+	for _, p := range t.list() {
+		installed[p.String()] = nil
+		t.refreshInstance(p)
+	}
+	for _, p := range t.data.Paths() {
+		if _, ok := installed[p.String()]; !ok {
+			t.data.DelInstance(p)
+		}
+	}
+}
+
+func (t daemonInstances) refreshNode() {
+	cfg := t.node()
+	t.data.SetCluster(cfg.Info)
+	t.data.SetNodeFrozen(cfg.Frozen)
+	t.data.SetNodeLabels(cfg.Labels)
+}
+
+func (t daemonInstances) refreshInstance(p path.T) {
+	cfg, st, err := t.load(p)
+	if err != nil {
+		log.Warn().Err(err).Stringer("path", p).Msg("instances: load")
+		return
+	}
+	t.data.SetInstanceConfig(p, cfg)
+	t.data.SetInstanceStatus(p, st)
+}
+
+// loadInstance returns the configuration digest and the status of the
+// local instance of the object. The status is read from the instance
+// status cache, evaluated if outdated.
+func loadInstance(p path.T) (instance.Config, instance.Status, error) {
+	var (
+		cfg instance.Config
+		st  instance.Status
+	)
+	o := object.NewFromPath(p)
+	configured, ok := o.(interface {
+		ConfigFile() string
+		Nodes() []string
+	})
+	if !ok {
+		return cfg, st, fmt.Errorf("unsupported object kind")
+	}
+	sum, err := cfgwatch.Checksum(configured.ConfigFile())
+	if err != nil {
+		return cfg, st, err
+	}
+	cfg = instance.Config{
+		Checksum: sum,
+		Scope:    configured.Nodes(),
+		Updated:  timestamp.New(file.ModTime(configured.ConfigFile())),
+	}
+	if st, err = o.(object.Baser).Status(object.OptsStatus{}); err != nil {
+		return cfg, st, err
+	}
+	return cfg, st, nil
+}
+
+// loadNode returns the cluster information, the frozen timestamp and the
+// labels of the local node.
+func loadNode() nodeConfig {
+	node := object.NewNode()
+	config := node.MergedConfig()
+	cfg := nodeConfig{
+		Info: cluster.Info{
+			ID:    config.GetString(key.New("cluster", "id")),
+			Name:  config.GetString(key.New("cluster", "name")),
+			Nodes: strings.Fields(config.GetString(key.New("cluster", "nodes"))),
+		},
+		Frozen: node.Frozen(),
+		Labels: make(map[string]string),
+	}
+	for _, k := range config.Keys("labels") {
+		if strings.Contains(k, "@") {
+			// a scoped value, evaluated with its unscoped key
+			continue
+		}
+		cfg.Labels[k] = config.GetString(key.New("labels", k))
+	}
+	return cfg
+}
\ No newline at end of file
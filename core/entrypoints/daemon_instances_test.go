@@ -0,0 +1,71 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestDaemonInstances(t *testing.T) {
+	svc1, _ := path.Parse("svc1")
+	svc2, _ := path.Parse("svc2")
+	broken, _ := path.Parse("broken")
+	stale, _ := path.Parse("stale")
+
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetInstanceConfig(stale, instance.Config{Checksum: "old"})
+
+	events := make(chan cfgwatch.Event)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- daemonInstances{
+			data:     data,
+			events:   events,
+			interval: time.Hour,
+			list:     func() []path.T { return []path.T{svc1, svc2, broken} },
+			load: func(p path.T) (instance.Config, instance.Status, error) {
+				if p == broken {
+					return instance.Config{}, instance.Status{}, fmt.Errorf("broken")
+				}
+				return instance.Config{Checksum: "abc"}, instance.Status{Avail: status.Up}, nil
+			},
+			node: func() nodeConfig {
+				return nodeConfig{
+					Info:   cluster.Info{Name: "c1"},
+					Frozen: timestamp.Now(),
+					Labels: map[string]string{"az": "eu1"},
+				}
+			},
+		}.Run(ctx)
+	}()
+
+	events <- cfgwatch.Event{Path: svc2, Deleted: true}
+	// the second event is received after the first one is handled
+	events <- cfgwatch.Event{Path: svc1}
+	st := data.Get()
+	assert.Equal(t, "c1", st.Cluster.Name)
+	assert.True(t, st.Monitor.Frozen)
+	assert.Equal(t, map[string]string{"az": "eu1"}, st.Monitor.Nodes["n1"].Labels)
+	assert.Contains(t, st.Monitor.Services, "svc1")
+	assert.NotContains(t, st.Monitor.Services, "svc2", "the deleted instance is dropped")
+	assert.NotContains(t, st.Monitor.Nodes["n1"].Services.Config, "broken", "the instance failing to load is skipped")
+	assert.NotContains(t, st.Monitor.Nodes["n1"].Services.Config, "stale", "the uninstalled instance is dropped")
+	assert.Equal(t, status.Up, st.Monitor.Nodes["n1"].Services.Status["svc1"].Avail)
+
+	cancel()
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,177 @@
+package entrypoints
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// DaemonJoin adds the local node to the cluster of a seed node.
+type DaemonJoin struct {
+	// Secret is the cluster secret, used to encrypt the join request
+	// sent to the seed node.
+	Secret string
+
+	// Node is the seed node name, or its raw api url.
+	Node string
+}
+
+var (
+	// clusterPath is the path of the cluster configuration object.
+	clusterPath, _ = path.Parse("cluster")
+
+	// clusterMembershipKeys are the node.conf keys overriding the cluster
+	// membership set in cluster.conf.
+	clusterMembershipKeys = []key.T{
+		key.New("cluster", "id"),
+		key.New("cluster", "name"),
+		key.New("cluster", "nodes"),
+		key.New("cluster", "secret"),
+	}
+)
+
+//
+// Do sends the join request to the seed node, installs the returned
+// cluster configuration, including the heartbeat sections, as the local
+// cluster.conf, and installs the cluster ca and certificate secrets
+// fetched from the seed node.
+//
+func (t DaemonJoin) Do() error {
+	if t.Node == "" {
+		return fmt.Errorf("the seed node is required")
+	}
+	if t.Secret == "" {
+		return fmt.Errorf("the cluster secret is required")
+	}
+
+	// The local node does not know the cluster name yet. The join
+	// request is encrypted with the cluster secret, and the sender
+	// identifies itself with the "join" cluster name.
+	rawconfig.Node.Cluster.Secret = t.Secret
+	rawconfig.Node.Cluster.Name = "join"
+
+	c, err := client.New(client.WithURL(seedURL(t.Node)))
+	if err != nil {
+		return err
+	}
+	req := c.NewPostJoin()
+	req.NodeName = hostname.Hostname()
+	b, err := req.Do()
+	if err != nil {
+		return fmt.Errorf("join %s: %w", t.Node, err)
+	}
+	data, err := rawconfig.Parse(b)
+	if err != nil {
+		return fmt.Errorf("join %s: %w", t.Node, err)
+	}
+	if data.IsZero() {
+		return fmt.Errorf("join %s: no cluster configuration in the response", t.Node)
+	}
+
+	ccfg := object.NewCcfg(clusterPath)
+	if err := ccfg.Config().CommitData(data); err != nil {
+		return fmt.Errorf("install the cluster configuration: %w", err)
+	}
+	err = ccfg.SetKeywords([]string{
+		"cluster.secret=" + t.Secret,
+		"cluster.nodes|=" + hostname.Hostname(),
+	})
+	if err != nil {
+		return fmt.Errorf("install the cluster configuration: %w", err)
+	}
+	if err := unsetNodeMembership(); err != nil {
+		return err
+	}
+	clusterName := ccfg.Config().Get(key.New("cluster", "name"))
+	if clusterName == "" {
+		clusterName = "default"
+	}
+	rawconfig.Node.Cluster.Name = clusterName
+	for _, section := range ccfg.Config().SectionStrings() {
+		if strings.HasPrefix(section, "hb#") {
+			log.Info().Str("hb", section).Msg("heartbeat registered")
+		}
+	}
+
+	// Now authenticated as a cluster member, fetch the listener tls
+	// secrets.
+	for _, p := range clusterSecPaths(ccfg, clusterName) {
+		if err := fetchObjectConfig(c, p); err != nil {
+			return fmt.Errorf("install %s: %w", p, err)
+		}
+	}
+	log.Info().Str("cluster", clusterName).Str("seed", t.Node).Msg("joined")
+	return nil
+}
+
+// seedURL returns the raw api url of the seed node, unless s is already
+// an url.
+func seedURL(s string) string {
+	if strings.Contains(s, "://") {
+		return s
+	}
+	return fmt.Sprintf("raw://%s:1214", s)
+}
+
+// unsetNodeMembership removes from node.conf the cluster membership
+// keys, which would override the cluster.conf values.
+func unsetNodeMembership() error {
+	cf := object.NewNode().Config()
+	if cf.Unset(clusterMembershipKeys...) == 0 {
+		return nil
+	}
+	return cf.Commit()
+}
+
+// clusterSecPaths returns the paths of the secrets hosting the listener
+// ca certificates and certificate, as set by the cluster.ca and
+// cluster.cert keywords or their defaults. The ccfg has no keyword
+// store, so the raw values are used.
+func clusterSecPaths(ccfg *object.Ccfg, clusterName string) []path.T {
+	cf := ccfg.Config()
+	l := strings.Fields(cf.Get(key.New("cluster", "ca")))
+	if len(l) == 0 {
+		l = []string{"system/sec/ca-" + clusterName}
+	}
+	if s := cf.Get(key.New("cluster", "cert")); s != "" {
+		l = append(l, s)
+	} else {
+		l = append(l, "system/sec/cert-"+clusterName)
+	}
+	paths := make([]path.T, 0, len(l))
+	for _, s := range l {
+		p, err := path.Parse(s)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// fetchObjectConfig installs the configuration of the object as served
+// by the api.
+func fetchObjectConfig(c *client.T, p path.T) error {
+	req := c.NewGetObjectConfig()
+	req.ObjectSelector = p.String()
+	b, err := req.Do()
+	if err != nil {
+		return err
+	}
+	data, err := rawconfig.Parse(b)
+	if err != nil {
+		return err
+	}
+	if data.IsZero() {
+		return fmt.Errorf("empty configuration")
+	}
+	return object.NewConfigurerFromPath(p).Config().CommitData(data)
+}
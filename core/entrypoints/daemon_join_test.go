@@ -0,0 +1,107 @@
+package entrypoints
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/client/request"
+	reqjsonrpc "opensvc.com/opensvc/core/client/requester/jsonrpc"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// fakeSeed serves the raw api requests with the responses indexed by
+// action, encrypted with the secret. It records the cluster name of the
+// senders, indexed by action.
+func fakeSeed(t *testing.T, secret string, responses map[string]interface{}) (string, map[string]string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	senders := make(map[string]string)
+	done := make(chan interface{})
+	go func() {
+		defer close(done)
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			b, err := bufio.NewReader(conn).ReadBytes('\x00')
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			var sender struct {
+				ClusterName string `json:"clustername"`
+			}
+			_ = json.Unmarshal(b[:len(b)-1], &sender)
+			m := &reqjsonrpc.Message{Key: secret, Data: b[:len(b)-1]}
+			b, err = m.Decrypt()
+			require.NoError(t, err)
+			var req request.T
+			require.NoError(t, json.Unmarshal(b, &req))
+			senders[req.Action] = sender.ClusterName
+			b, _ = json.Marshal(responses[req.Action])
+			m = &reqjsonrpc.Message{Key: secret, ClusterName: "c1", NodeName: "seed", Data: b}
+			b, err = m.Encrypt()
+			require.NoError(t, err)
+			_, _ = conn.Write(append(b, '\x00'))
+			conn.Close()
+		}
+	}()
+	cleanup := func() {
+		l.Close()
+		<-done
+	}
+	return "raw://" + l.Addr().String(), senders, cleanup
+}
+
+func TestDaemonJoin(t *testing.T) {
+	root, err := ioutil.TempDir("", "join")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+
+	secret := "0123456789abcdef0123456789abcdef"
+	url, senders, cleanup := fakeSeed(t, secret, map[string]interface{}{
+		"join": map[string]interface{}{
+			"status": 0,
+			"data": map[string]interface{}{
+				"cluster": map[string]string{"name": "c1", "nodes": "seed"},
+				"hb#1":    map[string]string{"type": "unicast"},
+			},
+		},
+		"object_config": map[string]interface{}{
+			"DEFAULT": map[string]string{"id": "b5e4b07d-3f3a-4f4d-8d43-2b9b1f0c4a51"},
+			"data":    map[string]string{"certificate_chain": "foo"},
+		},
+	})
+	defer cleanup()
+
+	require.NoError(t, DaemonJoin{Secret: secret, Node: url}.Do())
+	assert.Equal(t, "join", senders["join"], "the join request is sent with the join cluster name")
+	assert.Equal(t, "c1", senders["object_config"], "the secrets are fetched as a cluster member")
+
+	cf := object.NewCcfg(clusterPath).Config()
+	assert.Equal(t, "c1", cf.Get(key.New("cluster", "name")))
+	assert.Equal(t, secret, cf.Get(key.New("cluster", "secret")))
+	assert.Equal(t, []string{"seed", hostname.Hostname()}, strings.Fields(cf.Get(key.New("cluster", "nodes"))))
+	assert.True(t, cf.HasSectionString("hb#1"))
+	for _, s := range []string{"system/sec/ca-c1", "system/sec/cert-c1"} {
+		p, _ := path.Parse(s)
+		assert.True(t, object.NewConfigurerFromPath(p).Exists(), "%s is installed", s)
+	}
+}
@@ -0,0 +1,69 @@
+package entrypoints
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+// daemonJournal is the daemon thread persisting the daemon events in the
+// node events store, for audit and post-mortem analysis.
+type daemonJournal struct {
+	events <-chan cfgwatch.Event
+	store  *event.Store
+}
+
+// Run journals the daemon start, the configuration change events until
+// the context is done or the watcher stops, and the daemon stop.
+func (t daemonJournal) Run(ctx context.Context) error {
+	if t.store == nil {
+		t.store = event.NewStore(hostname.Hostname())
+	}
+	t.append("daemon_start", nil)
+	defer t.append("daemon_stop", nil)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-t.events:
+			if !ok {
+				return nil
+			}
+			name := "config_changed"
+			if ev.Deleted {
+				name = "config_removed"
+			}
+			t.append(name, map[string]interface{}{
+				"path": ev.Path.String(),
+				"csum": ev.Checksum,
+			})
+		}
+	}
+}
+
+func (t daemonJournal) append(name string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["name"] = name
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Warn().Err(err).Str("event", name).Msg("journal")
+		return
+	}
+	raw := json.RawMessage(b)
+	e := event.Event{
+		Kind:      "event",
+		Timestamp: timestamp.Now(),
+		Data:      &raw,
+	}
+	if err := t.store.Append(e); err != nil {
+		log.Warn().Err(err).Str("event", name).Msg("journal")
+	}
+}
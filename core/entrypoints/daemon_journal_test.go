@@ -0,0 +1,45 @@
+package entrypoints
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestDaemonJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	events := make(chan cfgwatch.Event, 2)
+	p, _ := path.Parse("s1")
+	events <- cfgwatch.Event{Path: p, Checksum: "abc"}
+	events <- cfgwatch.Event{Path: p, Deleted: true}
+	close(events)
+	rawconfig.Node.Paths.Var = dir
+	store := event.NewStore("n1")
+	require.NoError(t, daemonJournal{events: events, store: store}.Run(context.Background()))
+
+	l, err := store.Read(event.Filter{})
+	require.NoError(t, err)
+	names := make([]string, 0)
+	for _, e := range l {
+		assert.Equal(t, "event", e.Kind)
+		assert.Contains(t, string(*e.Data), `"name":`)
+		names = append(names, event.Render(e))
+	}
+	require.Len(t, names, 4)
+	assert.Contains(t, names[0], "daemon_start")
+	assert.Contains(t, names[1], "config_changed")
+	assert.Contains(t, names[2], "config_removed")
+	assert.Contains(t, names[3], "daemon_stop")
+}
@@ -0,0 +1,90 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// DaemonLeave removes the local node from its cluster.
+type DaemonLeave struct {
+	Server string
+}
+
+// clusterPeerSectionPrefixes are the prefixes of the cluster.conf sections
+// describing the relations with the peer nodes, removed on leave.
+var clusterPeerSectionPrefixes = []string{"hb#", "stonith#", "arbitrator#"}
+
+//
+// Do asks each peer node to remove the local node from its cluster nodes,
+// then resets the local cluster.conf to a single-node cluster: the peers,
+// heartbeats, stonith and arbitrators are removed, and a new cluster
+// secret will be generated on first use.
+//
+// The local reset is done even if some peers did not acknowledge the
+// leave, and an error lists those peers.
+//
+func (t DaemonLeave) Do() error {
+	nodename := hostname.Hostname()
+	ccfg := object.NewCcfg(clusterPath)
+	peers := make([]string, 0)
+	for _, node := range strings.Fields(ccfg.Config().Get(key.New("cluster", "nodes"))) {
+		if node != nodename {
+			peers = append(peers, node)
+		}
+	}
+	failed := make([]string, 0)
+	if len(peers) > 0 {
+		c, err := client.New(client.WithURL(t.Server))
+		if err != nil {
+			return err
+		}
+		responses := client.FanOut(context.Background(), peers, func(ctx context.Context, node string) ([]byte, error) {
+			req := c.NewPostLeave()
+			req.SetContext(ctx)
+			req.NodeName = nodename
+			req.SetNode(node)
+			return req.Do()
+		})
+		for _, r := range responses {
+			if r.Err != nil {
+				log.Error().Err(r.Err).Str("peer", r.Node).Msg("leave")
+				failed = append(failed, r.Node)
+				continue
+			}
+			log.Info().Str("peer", r.Node).Msg("left")
+		}
+	}
+
+	sections := make([]string, 0)
+	for _, section := range ccfg.Config().SectionStrings() {
+		for _, prefix := range clusterPeerSectionPrefixes {
+			if strings.HasPrefix(section, prefix) {
+				sections = append(sections, section)
+			}
+		}
+	}
+	if err := ccfg.Config().DeleteSections(sections); err != nil {
+		return err
+	}
+	if err := ccfg.Unset(object.OptsUnset{Keywords: []string{"cluster.secret", "cluster.id"}}); err != nil {
+		return err
+	}
+	if err := ccfg.SetKeywords([]string{"cluster.nodes=" + nodename}); err != nil {
+		return err
+	}
+	if err := unsetNodeMembership(); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("leave not acknowledged by %s: remove %s from their cluster.nodes", strings.Join(failed, ", "), nodename)
+	}
+	return nil
+}
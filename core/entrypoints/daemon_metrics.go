@@ -0,0 +1,101 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/metrics"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+// daemonMetrics is the daemon thread serving the daemon dataset as
+// prometheus metrics, on the listener configured by the node.conf metrics
+// section. The exporter is disabled if the section is not set.
+type daemonMetrics struct {
+	data *daemondata.T
+}
+
+// Run serves the metrics until the context is done.
+func (t daemonMetrics) Run(ctx context.Context) error {
+	config := object.NewNode().MergedConfig()
+	if !hasSection(config, "metrics") {
+		log.Info().Msg("metrics: no metrics section in the node configuration, exporter disabled")
+		return nil
+	}
+	srv, err := newMetricsServer(config)
+	if err != nil {
+		return err
+	}
+	srv.Getter = daemondata.StatusGetter{Data: t.data}
+	return srv.Run(ctx)
+}
+
+//
+// DaemonMetrics fetches the cluster status from an opensvc agent api and
+// exposes it as prometheus metrics. The local daemon serves its own
+// metrics, so the listener is only useful to export the metrics of a
+// remote cluster api.
+//
+type DaemonMetrics struct {
+	Server string
+
+	// Listen runs the exporter listener configured by the node.conf
+	// metrics section, instead of printing the metrics once.
+	Listen bool
+}
+
+// Do prints the metrics, or serves them if Listen is set.
+func (t DaemonMetrics) Do() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	getter := c.NewGetDaemonStatus().SetSelector("**")
+	if !t.Listen {
+		b, err := metrics.Get(getter)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	srv, err := newMetricsServer(object.NewNode().MergedConfig())
+	if err != nil {
+		return err
+	}
+	srv.Getter = getter
+	return srv.ListenAndServe()
+}
+
+// newMetricsServer returns a metrics exporter configured from the node.conf
+// metrics section.
+func newMetricsServer(config *xconfig.T) (metrics.Server, error) {
+	port, err := config.GetIntStrict(key.New("metrics", "port"))
+	if err != nil {
+		return metrics.Server{}, fmt.Errorf("metrics.port: %w", err)
+	}
+	return metrics.Server{
+		Addr:     net.JoinHostPort(config.GetString(key.New("metrics", "addr")), strconv.Itoa(port)),
+		CertFile: config.GetString(key.New("metrics", "cert")),
+		KeyFile:  config.GetString(key.New("metrics", "key")),
+	}, nil
+}
+
+// hasSection returns true if the configuration has the section s.
+func hasSection(config *xconfig.T, s string) bool {
+	for _, section := range config.SectionStrings() {
+		if section == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package entrypoints
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/notify"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/snmptrap"
+)
+
+// daemonNotify is the daemon thread posting the notable changes of the
+// daemon dataset to the webhooks and snmp trap receivers configured by
+// the node.conf notify sections. The notifier is disabled if no notify
+// section is set.
+type daemonNotify struct {
+	data *daemondata.T
+
+	// notifiers, if set, replaces the notifiers configured by the node.
+	notifiers []notify.Notifier
+}
+
+// Run posts the notable changes until the context is done.
+func (t daemonNotify) Run(ctx context.Context) error {
+	if t.notifiers == nil {
+		notifiers, err := newNotifiers()
+		if err != nil {
+			return err
+		}
+		t.notifiers = notifiers
+	}
+	if len(t.notifiers) == 0 {
+		log.Info().Msg("notify: no notify section in the node configuration, notifier disabled")
+		return nil
+	}
+	w := notify.Watcher{
+		Events:    daemondata.EventsGetter{Data: t.data, Context: ctx},
+		Status:    daemondata.StatusGetter{Data: t.data},
+		Notifiers: t.notifiers,
+	}
+	return w.Watch(ctx)
+}
+
+//
+// DaemonNotify follows the event stream of an opensvc agent api, and posts
+// the notable cluster status changes to the webhooks and snmp trap
+// receivers configured by the node.conf notify sections. The local daemon
+// runs its own notifier, so this follower is only useful to notify the
+// changes of a remote cluster api.
+//
+type DaemonNotify struct {
+	Server string
+}
+
+// Do follows the event stream until the process is interrupted.
+func (t DaemonNotify) Do() error {
+	notifiers, err := newNotifiers()
+	if err != nil {
+		return err
+	}
+	if len(notifiers) == 0 {
+		return fmt.Errorf("no notify section in the node configuration")
+	}
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	w := notify.Watcher{
+		Events:    c.NewGetEvents().SetSelector("**"),
+		Status:    c.NewGetDaemonStatus().SetSelector("**"),
+		Notifiers: notifiers,
+	}
+	return w.Watch(context.Background())
+}
+
+// newNotifiers returns the notifiers configured by the node.conf
+// notify#<name> sections.
+func newNotifiers() ([]notify.Notifier, error) {
+	config := object.NewNode().MergedConfig()
+	l := make([]notify.Notifier, 0)
+	for _, s := range config.SectionStrings() {
+		if !strings.HasPrefix(s, "notify#") {
+			continue
+		}
+		name := s[7:]
+		limit, err := config.GetIntStrict(key.New(s, "rate_limit"))
+		if err != nil {
+			return nil, fmt.Errorf("%s.rate_limit: %w", s, err)
+		}
+		interval, err := config.GetDurationStrict(key.New(s, "rate_interval"))
+		if err != nil {
+			return nil, fmt.Errorf("%s.rate_interval: %w", s, err)
+		}
+		if interval == nil {
+			d := time.Minute
+			interval = &d
+		}
+		u := config.GetString(key.New(s, "url"))
+		if u == "" {
+			return nil, fmt.Errorf("%s.url is required", s)
+		}
+		var (
+			n      notify.Notifier
+			filter *notify.Filter
+		)
+		switch config.GetString(key.New(s, "type")) {
+		case "snmp":
+			sender, err := newTrapSender(config, s, u)
+			if err != nil {
+				return nil, err
+			}
+			trap := notify.NewTrap(name, sender, limit, *interval)
+			n, filter = trap, &trap.Filter
+		default:
+			hook := notify.NewWebhook(name, u, limit, *interval)
+			hook.Slack = config.GetString(key.New(s, "type")) == "slack"
+			n, filter = hook, &hook.Filter
+		}
+		filter.Kinds = config.GetSlice(key.New(s, "events"))
+		if tmpl := config.GetString(key.New(s, "template")); tmpl != "" {
+			if err := filter.ParseTemplate(tmpl); err != nil {
+				return nil, err
+			}
+		}
+		l = append(l, n)
+	}
+	return l, nil
+}
+
+// newTrapSender returns the snmp trap sender configured by the section s,
+// sending to the snmp://<host>[:<port>] url u.
+func newTrapSender(config *xconfig.T, s, u string) (*snmptrap.T, error) {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Scheme != "snmp" || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("%s.url: snmp://<host>[:<port>] expected, got %s", s, u)
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = strconv.Itoa(snmptrap.DefaultPort)
+	}
+	addr := net.JoinHostPort(parsed.Hostname(), port)
+	opts := []funcopt.O{snmptrap.WithCommunity(config.GetString(key.New(s, "community")))}
+	if config.GetString(key.New(s, "version")) == "3" {
+		user := config.GetString(key.New(s, "user"))
+		if user == "" {
+			return nil, fmt.Errorf("%s.user is required for the snmp version 3", s)
+		}
+		engineID := snmptrap.EngineID(hostname.Hostname())
+		if v := config.GetString(key.New(s, "engine_id")); v != "" {
+			if engineID, err = hex.DecodeString(v); err != nil {
+				return nil, fmt.Errorf("%s.engine_id: %w", s, err)
+			}
+		}
+		opts = append(opts,
+			snmptrap.WithUser(user, engineID),
+			snmptrap.WithAuth(config.GetString(key.New(s, "auth_protocol")), config.GetString(key.New(s, "auth_password"))),
+			snmptrap.WithPriv(config.GetString(key.New(s, "priv_protocol")), config.GetString(key.New(s, "priv_password"))),
+		)
+	}
+	sender, err := snmptrap.New(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s, err)
+	}
+	return sender, nil
+}
@@ -0,0 +1,59 @@
+package entrypoints
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/notify"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+type notifierFunc func(ctx context.Context, e notify.Event) error
+
+func (t notifierFunc) Post(ctx context.Context, e notify.Event) error {
+	return t(ctx, e)
+}
+
+func TestDaemonNotify(t *testing.T) {
+	p, _ := path.Parse("svc1")
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	data.SetInstanceStatus(p, instance.Status{Avail: status.Up, Overall: status.Up})
+
+	posted := make(chan notify.Event, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- daemonNotify{
+			data: data,
+			notifiers: []notify.Notifier{notifierFunc(func(_ context.Context, e notify.Event) error {
+				posted <- e
+				return nil
+			})},
+		}.Run(ctx)
+	}()
+
+	// the watcher subscribes asynchronously: repeat the change until seen
+	var e notify.Event
+	require.Eventually(t, func() bool {
+		data.SetInstanceStatus(p, instance.Status{Avail: status.Down, Overall: status.Down})
+		select {
+		case e = <-posted:
+			return true
+		case <-time.After(10 * time.Millisecond):
+			data.SetInstanceStatus(p, instance.Status{Avail: status.Up, Overall: status.Up})
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "svc1", e.Path)
+
+	cancel()
+	require.NoError(t, <-done)
+}
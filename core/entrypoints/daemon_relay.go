@@ -0,0 +1,65 @@
+package entrypoints
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/hbrelay"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+// daemonRelay is the daemon thread serving the relay heartbeat slots of
+// the clusters configured in the node.conf relay#<cluster id> sections.
+type daemonRelay struct{}
+
+// Run serves the relay heartbeat until the context is done. It returns
+// immediately if no cluster is configured to use the relay.
+func (t daemonRelay) Run(ctx context.Context) error {
+	srv, err := newRelayServer(object.NewNode().MergedConfig())
+	if err != nil {
+		return err
+	}
+	if len(srv.Secrets) == 0 {
+		log.Debug().Msg("relay: no relay#<cluster id> section, server not started")
+		return nil
+	}
+	return srv.Run(ctx)
+}
+
+// newRelayServer returns a relay heartbeat server configured from the
+// node.conf relay section, and the relay#<cluster id> sections secrets.
+func newRelayServer(config *xconfig.T) (*hbrelay.Server, error) {
+	port, err := config.GetIntStrict(key.New("relay", "port"))
+	if err != nil {
+		return nil, fmt.Errorf("relay.port: %w", err)
+	}
+	srv := &hbrelay.Server{
+		Addr:     net.JoinHostPort(config.GetString(key.New("relay", "addr")), strconv.Itoa(port)),
+		CertFile: config.GetString(key.New("relay", "cert")),
+		KeyFile:  config.GetString(key.New("relay", "key")),
+		Secrets:  make(map[string]string),
+	}
+	if d := config.GetDuration(key.New("relay", "retention")); d != nil {
+		srv.Retention = *d
+	}
+	for _, s := range config.SectionStrings() {
+		if !strings.HasPrefix(s, "relay#") {
+			continue
+		}
+		cluster := s[len("relay#"):]
+		secret := config.GetString(key.New(s, "secret"))
+		if secret == "" {
+			log.Warn().Str("section", s).Msg("relay: no secret, cluster ignored")
+			continue
+		}
+		srv.Secrets[cluster] = secret
+	}
+	return srv, nil
+}
@@ -0,0 +1,32 @@
+package entrypoints
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestNewRelayServer(t *testing.T) {
+	root, err := ioutil.TempDir("", "relay")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[relay]\nport = 1300\nretention = 30s\n\n[relay#c1]\nsecret = s1\n\n[relay#c2]\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "node.conf"), b, 0644))
+
+	srv, err := newRelayServer(object.NewNode().MergedConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "[::]:1300", srv.Addr)
+	assert.Equal(t, 30*time.Second, srv.Retention)
+	assert.Equal(t, map[string]string{"c1": "s1"}, srv.Secrets, "the clusters without secret are ignored")
+}
@@ -0,0 +1,93 @@
+package entrypoints
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/monstate"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/orchestjournal"
+	"opensvc.com/opensvc/core/path"
+)
+
+// daemonResumeMaxAge is the age over which an orchestration found in
+// progress at daemon startup is cancelled instead of resumed.
+var daemonResumeMaxAge = 24 * time.Hour
+
+//
+// resumeOrchestrations recovers the orchestration journal at daemon
+// startup. The global expects of the resumed orchestrations are restored
+// in the daemon dataset, and the resumed list exposed in the monitor
+// thread status. The cancelled orchestrations are logged.
+//
+func resumeOrchestrations(data *daemondata.T, validate orchestjournal.Validator) error {
+	r, err := orchestjournal.Recover(daemonResumeMaxAge, validate)
+	if err != nil {
+		return err
+	}
+	for _, i := range r.Cancelled {
+		log.Warn().Stringer("intent", i).Str("reason", i.Reason).Msg("orchestration cancelled")
+	}
+	for _, i := range r.Resumed {
+		log.Info().Stringer("intent", i).Msg("orchestration resumed")
+		if i.Path == "" {
+			m := data.GetNodeMonitor()
+			m.GlobalExpect = i.GlobalExpect
+			m.GlobalExpectUpdated = i.Created
+			data.SetNodeMonitor(m)
+			continue
+		}
+		p, err := path.Parse(i.Path)
+		if err != nil {
+			continue
+		}
+		m := data.GetInstanceMonitor(p)
+		m.GlobalExpect = i.GlobalExpect
+		m.GlobalExpectUpdated = i.Created
+		data.SetInstanceMonitor(p, m)
+	}
+	data.SetResumed(r.Resumed)
+	return nil
+}
+
+//
+// restoreMonitorStates restores in the daemon dataset the local expects
+// and restart counters of the instance monitors saved before the daemon
+// restart.
+//
+func restoreMonitorStates(data *daemondata.T) error {
+	states, err := monstate.Load()
+	if err != nil {
+		return err
+	}
+	for k, st := range states.Instances {
+		p, err := path.Parse(k)
+		if err != nil {
+			continue
+		}
+		m := data.GetInstanceMonitor(p)
+		st.Apply(&m)
+		data.SetInstanceMonitor(p, m)
+	}
+	return nil
+}
+
+// validateIntent refuses to resume the orchestration of an object no
+// longer installed on the node.
+func validateIntent(i orchestjournal.Intent) error {
+	if i.Path == "" {
+		return nil
+	}
+	p, err := path.Parse(i.Path)
+	if err != nil {
+		return err
+	}
+	o, ok := object.NewFromPath(p).(interface{ Exists() bool })
+	if !ok || !o.Exists() {
+		return fmt.Errorf("object not found")
+	}
+	return nil
+}
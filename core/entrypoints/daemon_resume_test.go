@@ -0,0 +1,67 @@
+package entrypoints
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/daemon/daemondata"
+	"opensvc.com/opensvc/core/monstate"
+	"opensvc.com/opensvc/core/orchestjournal"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestResumeOrchestrations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resume")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	require.NoError(t, orchestjournal.Begin(orchestjournal.Intent{Path: "svc1", Node: "n1", GlobalExpect: "started"}))
+	require.NoError(t, orchestjournal.Begin(orchestjournal.Intent{Path: "svc2", Node: "n1", GlobalExpect: "stopped"}))
+	require.NoError(t, orchestjournal.Begin(orchestjournal.Intent{Node: "n1", GlobalExpect: "frozen"}))
+
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	require.NoError(t, resumeOrchestrations(data, func(i orchestjournal.Intent) error {
+		if i.Path == "svc2" {
+			return fmt.Errorf("object not found")
+		}
+		return nil
+	}))
+
+	st := data.Get()
+	require.Len(t, st.Monitor.Resumed, 2)
+	assert.Equal(t, "node:n1", st.Monitor.Resumed[0].Key())
+	assert.Equal(t, "svc1", st.Monitor.Resumed[1].Key())
+	assert.Equal(t, "frozen", st.Monitor.Nodes["n1"].Monitor.GlobalExpect)
+	svc1, _ := path.Parse("svc1")
+	assert.Equal(t, "started", data.GetInstanceMonitor(svc1).GlobalExpect)
+
+	journal, err := orchestjournal.Load()
+	require.NoError(t, err)
+	assert.NotContains(t, journal, "svc2", "the cancelled orchestration is removed from the journal")
+}
+
+func TestRestoreMonitorStates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resume")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	require.NoError(t, monstate.SetInstance("svc1", monstate.InstanceState{
+		LocalExpect: "started",
+		Restart:     map[string]int{"app#1": 1},
+	}))
+	data := daemondata.New(daemondata.WithNodename("n1"))
+	require.NoError(t, restoreMonitorStates(data))
+
+	svc1, _ := path.Parse("svc1")
+	m := data.GetInstanceMonitor(svc1)
+	assert.Equal(t, "started", m.LocalExpect)
+	assert.Equal(t, 1, m.Restart["app#1"])
+}
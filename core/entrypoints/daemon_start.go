@@ -0,0 +1,47 @@
+package entrypoints
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/daemon"
+	"opensvc.com/opensvc/core/daemon/cfgwatch"
+	"opensvc.com/opensvc/core/daemon/daemonapi"
+	"opensvc.com/opensvc/core/daemon/daemondata"
+)
+
+// DaemonStart starts the opensvc daemon, in the background or in the
+// current process.
+type DaemonStart struct {
+	Foreground bool
+}
+
+// Do starts the daemon. In foreground mode, Do returns when the daemon
+// threads are stopped by a SIGTERM or SIGINT signal.
+func (t DaemonStart) Do() error {
+	if !t.Foreground {
+		return daemon.Start()
+	}
+	watcher := cfgwatch.New()
+	data := daemondata.New()
+	if err := resumeOrchestrations(data, validateIntent); err != nil {
+		log.Warn().Err(err).Msg("resume orchestrations")
+	}
+	if err := restoreMonitorStates(data); err != nil {
+		log.Warn().Err(err).Msg("restore monitor states")
+	}
+	return daemon.New(
+		daemon.WithThread("stats", DaemonCollectStats{}.Run),
+		daemon.WithThread("cfgwatch", watcher.Run),
+		daemon.WithThread("cfgreload", daemonConfigReload{events: watcher.Subscribe()}.Run),
+		daemon.WithThread("journal", daemonJournal{events: watcher.Subscribe()}.Run),
+		daemon.WithThread("instances", daemonInstances{data: data, events: watcher.Subscribe()}.Run),
+		daemon.WithThread("listener", (&daemonapi.Server{Data: data}).Run),
+		daemon.WithThread("metrics", daemonMetrics{data: data}.Run),
+		daemon.WithThread("notify", daemonNotify{data: data}.Run),
+		daemon.WithThread("events", daemonEvents{}.Run),
+		daemon.WithThread("relay", daemonRelay{}.Run),
+		daemon.WithThread("hb", daemonHeartbeat{data: data}.Run),
+	).Run(context.Background())
+}
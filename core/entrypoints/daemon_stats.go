@@ -5,8 +5,10 @@ import (
 
 	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/driverstats"
 	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
 )
 
 // DaemonStats fetches and renders the statistic metrics from an opensvc
@@ -15,39 +17,48 @@ type DaemonStats struct {
 	Color  string
 	Format string
 	Server string
+
+	// NodeSelector selects the nodes the daemon aggregates the metrics
+	// of. Empty means all nodes.
+	NodeSelector string
 }
 
-// Do prints the formatted object selection
+// Do prints the formatted daemon statistics of the selected nodes.
 func (t DaemonStats) Do() error {
-	var (
-		err  error
-		b    []byte
-		data cluster.Stats
-		c    *client.T
-	)
-	c, err = client.New(client.WithURL(t.Server))
+	c, err := client.New(client.WithURL(t.Server))
 	if err != nil {
 		return err
 	}
 	handle := c.NewGetDaemonStats()
-	b, err = handle.Do()
+	if t.NodeSelector != "" {
+		handle.NodeSelector = t.NodeSelector
+		handle.SetNode(t.NodeSelector)
+	}
+	b, err := handle.Do()
 	if err != nil {
 		return err
 	}
-	data, err = parseDaemonStats(b)
+	data, err := parseDaemonStats(b)
 	if err != nil {
 		return err
 	}
-	renderer := output.Renderer{
-		Format:   t.Format,
-		Color:    t.Color,
-		Data:     data,
-		Colorize: rawconfig.Node.Colorize,
-	}
-	renderer.Print()
+	addLocalDriverStats(data, driverstats.Load)
+	output.Renderer{
+		Format:        t.Format,
+		Color:         t.Color,
+		Data:          data,
+		HumanRenderer: data.Render,
+		Colorize:      rawconfig.Node.Colorize,
+	}.Print()
 	return nil
 }
 
+//
+// parseDaemonStats returns the per-node statistics of a daemon_stats api
+// response, routed from each selected node in a
+// nodes: {<node>: {status: <int>, data: <stats>}} dataset. The nodes
+// failing to report their statistics are skipped.
+//
 func parseDaemonStats(b []byte) (cluster.Stats, error) {
 	type (
 		nodeData struct {
@@ -61,12 +72,33 @@ func parseDaemonStats(b []byte) (cluster.Stats, error) {
 	)
 	var t responseType
 	ds := make(cluster.Stats)
-	err := json.Unmarshal(b, &t)
-	if err != nil {
+	if err := json.Unmarshal(b, &t); err != nil {
 		return ds, err
 	}
 	for k, v := range t.Nodes {
+		if v.Status != 0 {
+			continue
+		}
 		ds[k] = v.Data
 	}
 	return ds, nil
 }
+
+//
+// addLocalDriverStats sets the local node driver action stats, read from
+// the node driver stats store, if the daemon did not report them. The
+// stats of the other nodes are only available from their daemon.
+//
+func addLocalDriverStats(data cluster.Stats, load func() (driverstats.T, error)) {
+	nodename := hostname.Hostname()
+	ns, ok := data[nodename]
+	if !ok || len(ns.Drivers) > 0 {
+		return
+	}
+	drivers, err := load()
+	if err != nil {
+		return
+	}
+	ns.Drivers = drivers
+	data[nodename] = ns
+}
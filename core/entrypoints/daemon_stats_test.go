@@ -0,0 +1,47 @@
+package entrypoints
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/driverstats"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+func TestParseDaemonStats(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	b := []byte(`{
+		"status": 0,
+		"nodes": {
+			"n1": {"status": 0, "data": {"monitor": {"procs": 1, "threads": 4}, "hb#1.rx": {"threads": 1}}},
+			"n2": {"status": 1, "data": {}}
+		}
+	}`)
+	data, err := parseDaemonStats(b)
+	require.NoError(t, err)
+	require.Len(t, data, 1, "the failed nodes are skipped")
+	assert.Equal(t, uint64(4), data["n1"].Monitor.Threads)
+	assert.Contains(t, data["n1"].Heartbeats, "hb#1.rx")
+	assert.Contains(t, data.Render(), "hb#1.rx")
+}
+
+func TestAddLocalDriverStats(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	local := hostname.Hostname()
+	load := func() (driverstats.T, error) {
+		data := make(driverstats.T)
+		data.Observe("ip.host", "start", 2*time.Second)
+		return data, nil
+	}
+	data := cluster.Stats{local: {}, "peer": {}}
+	addLocalDriverStats(data, load)
+	require.Contains(t, data[local].Drivers, "ip.host")
+	assert.Equal(t, uint64(1), data[local].Drivers["ip.host"]["start"].Count)
+	assert.Empty(t, data["peer"].Drivers, "the peer stats are not in the local store")
+	assert.Contains(t, data.Render(), "ip.host start")
+	assert.Contains(t, data.Render(), "1 x 2.00s")
+}
@@ -2,12 +2,16 @@ package entrypoints
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/event"
 	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
 )
 
 // Events hosts the options of the events fetcher/renderer entrypoint.
@@ -15,14 +19,49 @@ type Events struct {
 	Color  string
 	Format string
 	Server string
+
+	// Since and Until, if set, render the events of this time range
+	// persisted in the node events store, including the daemon events
+	// and the actions audit records, before following the stream. Their
+	// value is a duration relative to now, or a RFC3339 date. If Until
+	// is set, the stream is not followed.
+	Since string
+	Until string
+
+	// Kinds, if set, renders only the events of these kinds.
+	Kinds []string
 }
 
 // Do renders the event stream
 func (t Events) Do() {
 	var (
-		err error
-		c   *client.T
+		err    error
+		c      *client.T
+		filter event.Filter
 	)
+	now := time.Now()
+	filter.Kinds = t.Kinds
+	if filter.Since, err = event.ParseTime(t.Since, now); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if filter.Until, err = event.ParseTime(t.Until, now); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if t.Since != "" || t.Until != "" {
+		events, err := event.NewStore(t.nodename()).Read(filter)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, e := range events {
+			t.doOne(e)
+		}
+		if t.Until != "" {
+			return
+		}
+	}
 	c, err = client.New(client.WithURL(t.Server))
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -34,9 +73,25 @@ func (t Events) Do() {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+	filter = event.Filter{Kinds: t.Kinds}
 	for m := range events {
-		t.doOne(m)
+		if filter.Match(m) {
+			t.doOne(m)
+		}
+	}
+}
+
+// nodename returns the name of the node emitting the events, used to
+// select the events store.
+func (t Events) nodename() string {
+	if t.Server == "" {
+		return hostname.Hostname()
+	}
+	u, err := url.Parse(t.Server)
+	if err != nil || u.Hostname() == "" {
+		return strings.NewReplacer("/", "_", ":", "_").Replace(t.Server)
 	}
+	return u.Hostname()
 }
 
 func (t Events) doOne(e event.Event) {
@@ -0,0 +1,25 @@
+package monitor
+
+import (
+	"opensvc.com/opensvc/core/cluster"
+)
+
+type (
+	// objectFilter limits the rendered objects to those matching a
+	// selector expression and a namespace.
+	objectFilter struct {
+		// selector is a comma-separated list of path patterns.
+		// Empty means all objects.
+		selector string
+
+		// namespace is the namespace of the objects to render.
+		// Empty means all namespaces.
+		namespace string
+	}
+)
+
+// apply returns a copy of the cluster status data limited to the objects
+// and instances passing the filter. The source data is not modified.
+func (t objectFilter) apply(data cluster.Status) cluster.Status {
+	return cluster.Filter{Selector: t.selector, Namespace: t.namespace}.Apply(data)
+}
@@ -0,0 +1,45 @@
+package monitor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+func TestObjectFilter(t *testing.T) {
+	data := cluster.Status{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"monitor": {
+			"services": {"db1": {}, "web1": {}, "test/svc/db2": {}, "test/cfg/db3": {}},
+			"nodes": {"n1": {"services": {
+				"config": {"db1": {}, "web1": {}},
+				"status": {"db1": {}, "web1": {}, "1.db1": {}}
+			}}}
+		}
+	}`), &data))
+
+	filtered := objectFilter{}.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 4)
+
+	filtered = objectFilter{selector: "**"}.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 4)
+
+	filtered = objectFilter{selector: "db1"}.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 1)
+	assert.Contains(t, filtered.Monitor.Services, "db1")
+	assert.Len(t, filtered.Monitor.Nodes["n1"].Services.Config, 1)
+	assert.Len(t, filtered.Monitor.Nodes["n1"].Services.Status, 2, "the scaler slave instance passes the filter")
+	assert.Len(t, data.Monitor.Nodes["n1"].Services.Status, 3, "the source data is not modified")
+
+	filtered = objectFilter{selector: "**", namespace: "test"}.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 2)
+	assert.Len(t, filtered.Monitor.Nodes["n1"].Services.Status, 0)
+
+	filtered = objectFilter{selector: "*/svc/*", namespace: "test"}.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 1)
+	assert.Contains(t, filtered.Monitor.Services, "test/svc/db2")
+}
@@ -0,0 +1,238 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+type (
+	// view is the interactive watch state, driven by the keystrokes.
+	view struct {
+		objectFilter
+		paused bool
+		help   bool
+		hidden map[string]bool
+
+		// prompt is the name of the filter being typed, if any
+		prompt string
+		input  string
+
+		// disconnected is the cause of the event stream drop, if any
+		disconnected error
+		reconnect    time.Time
+	}
+
+	// crlfWriter translates the line feeds to carriage return plus line
+	// feed, as needed by a terminal in raw mode.
+	crlfWriter struct {
+		w io.Writer
+	}
+)
+
+const (
+	keyCtrlC     = 0x03
+	keyBackspace = 0x08
+	keyEnter     = '\r'
+	keyLineFeed  = '\n'
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+
+	promptSelector  = "selector"
+	promptNamespace = "namespace"
+)
+
+var (
+	// errQuit is returned by the key handler when the user asks to
+	// quit the watch.
+	errQuit = errors.New("quit")
+
+	// allSections is the ordered list of the sections the keys can toggle.
+	allSections = []string{"threads", "arbitrators", "nodes", "objects"}
+
+	// sectionKeys maps the section toggle keys to the section names.
+	sectionKeys = map[byte]string{
+		't': "threads",
+		'a': "arbitrators",
+		'n': "nodes",
+		'o': "objects",
+	}
+
+	helpText = `Keys:
+  ?, h      toggle this help
+  p, space  pause/resume the refresh
+  t         toggle the threads section
+  a         toggle the arbitrators section
+  n         toggle the nodes section
+  o         toggle the objects section
+  /         filter the objects by selector (ex: */svc/db*,web)
+  N         filter the objects by namespace
+  c         clear the filters
+  q, ^C     quit
+
+`
+)
+
+func (w crlfWriter) Write(p []byte) (int, error) {
+	if _, err := w.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func newView() *view {
+	return &view{
+		hidden: make(map[string]bool),
+	}
+}
+
+// SetInput sets the reader of the keystrokes driving the watch mode. If the
+// reader is a terminal, it is put in raw mode during the watch, so the
+// keystrokes are handled without waiting for a line feed.
+func (m *T) SetInput(r io.Reader) {
+	m.input = r
+}
+
+// keys starts reading the input keystrokes, and returns the channel they are
+// sent to, and the function restoring the terminal state, or nil if the
+// input is not a terminal put in raw mode. The returned channel is nil if
+// no input is set.
+func (m T) keys() (chan byte, func(), error) {
+	var restore func()
+	if m.input == nil {
+		return nil, restore, nil
+	}
+	if f, ok := m.input.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return nil, restore, err
+		}
+		restore = func() {
+			_ = term.Restore(int(f.Fd()), state)
+		}
+	}
+	c := make(chan byte)
+	go func() {
+		b := make([]byte, 1)
+		for {
+			if _, err := m.input.Read(b); err != nil {
+				close(c)
+				return
+			}
+			c <- b[0]
+		}
+	}()
+	return c, restore, nil
+}
+
+// handleKey updates the view state from a keystroke. It returns errQuit if
+// the user asks to quit.
+func (v *view) handleKey(k byte) error {
+	if v.prompt != "" {
+		v.handlePromptKey(k)
+		return nil
+	}
+	switch k {
+	case 'q', keyCtrlC:
+		return errQuit
+	case '?', 'h':
+		v.help = !v.help
+	case 'p', ' ':
+		v.paused = !v.paused
+	case '/':
+		v.prompt = promptSelector
+		v.input = v.selector
+	case 'N':
+		v.prompt = promptNamespace
+		v.input = v.namespace
+	case 'c':
+		v.selector = ""
+		v.namespace = ""
+	default:
+		if section, ok := sectionKeys[k]; ok {
+			v.hidden[section] = !v.hidden[section]
+		}
+	}
+	return nil
+}
+
+func (v *view) handlePromptKey(k byte) {
+	switch k {
+	case keyEnter, keyLineFeed:
+		switch v.prompt {
+		case promptSelector:
+			v.selector = v.input
+		case promptNamespace:
+			v.namespace = v.input
+		}
+		v.prompt = ""
+	case keyEscape, keyCtrlC:
+		v.prompt = ""
+	case keyBackspace, keyDelete:
+		if len(v.input) > 0 {
+			v.input = v.input[:len(v.input)-1]
+		}
+	default:
+		if k >= 0x20 && k < 0x7f {
+			v.input += string(k)
+		}
+	}
+}
+
+// sections returns the sections to render, given the sections set by
+// SetSections and the ones toggled off. The returned list is empty if all
+// sections are toggled off.
+func (v view) sections(base []string) []string {
+	if len(base) == 0 {
+		base = allSections
+	}
+	l := make([]string, 0)
+	for _, s := range base {
+		if !v.hidden[s] {
+			l = append(l, s)
+		}
+	}
+	return l
+}
+
+// banner returns the disconnected banner, if the event stream dropped.
+func (v view) banner() string {
+	if v.disconnected == nil {
+		return ""
+	}
+	return fmt.Sprintf("disconnected: %s, next reconnect attempt at %s\n\n", v.disconnected, v.reconnect.Format("15:04:05"))
+}
+
+// header returns the help overlay, if enabled.
+func (v view) header() string {
+	if !v.help {
+		return ""
+	}
+	return helpText
+}
+
+// footer returns the view state line and the filter prompt, if any.
+func (v view) footer() string {
+	l := make([]string, 0)
+	if v.paused {
+		l = append(l, "paused")
+	}
+	if v.selector != "" {
+		l = append(l, "selector: "+v.selector)
+	}
+	if v.namespace != "" {
+		l = append(l, "namespace: "+v.namespace)
+	}
+	l = append(l, "press ? for help")
+	s := "\n" + strings.Join(l, " | ") + "\n"
+	if v.prompt != "" {
+		s += fmt.Sprintf("%s: %s_", v.prompt, v.input)
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+type mockEvents struct {
+	events chan []byte
+}
+
+func (t mockEvents) GetRaw() (chan []byte, error) {
+	return t.events, nil
+}
+
+func TestViewHandleKey(t *testing.T) {
+	v := newView()
+	for _, k := range []byte("t/*/svc/db*\rNtest\r") {
+		require.NoError(t, v.handleKey(k))
+	}
+	assert.Equal(t, []string{"arbitrators", "nodes", "objects"}, v.sections(nil))
+	assert.Equal(t, []string{"objects"}, v.sections([]string{"objects"}))
+	assert.Equal(t, "*/svc/db*", v.selector)
+	assert.Equal(t, "test", v.namespace)
+
+	require.NoError(t, v.handleKey('/'))
+	for _, k := range []byte("web\x7f\x1b") {
+		require.NoError(t, v.handleKey(k))
+	}
+	assert.Equal(t, "*/svc/db*", v.selector, "escape cancels the prompt")
+
+	require.NoError(t, v.handleKey('c'))
+	assert.Equal(t, "", v.selector)
+	assert.Equal(t, "", v.namespace)
+
+	require.NoError(t, v.handleKey(' '))
+	assert.True(t, v.paused)
+	assert.Equal(t, errQuit, v.handleKey('q'))
+}
+
+func TestViewFilter(t *testing.T) {
+	data := cluster.Status{}
+	require.NoError(t, json.Unmarshal([]byte(`{"monitor": {"services": {"db1": {}, "web1": {}, "test/svc/db1": {}}}}`), &data))
+	v := newView()
+	v.selector = "db*"
+	filtered := v.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 1)
+	assert.Contains(t, filtered.Monitor.Services, "db1")
+	assert.Len(t, data.Monitor.Services, 3, "the source data is not modified")
+
+	v.selector = ""
+	v.namespace = "test"
+	filtered = v.apply(data)
+	assert.Len(t, filtered.Monitor.Services, 1)
+	assert.Contains(t, filtered.Monitor.Services, "test/svc/db1")
+}
+
+func TestDoWatchQuit(t *testing.T) {
+	events := make(chan []byte, 1)
+	events <- []byte(`{"kind": "full", "data": ` + daemonResultString + `}`)
+	r, w := io.Pipe()
+	m := New()
+	m.SetColor("no")
+	m.SetInput(r)
+	spy := recorder{}
+	go func() {
+		_, _ = w.Write([]byte("?q"))
+	}()
+	err := m.DoWatch(mockEvents{events: events}, &spy)
+	assert.NoError(t, err)
+	assert.Contains(t, string(spy.data), "press ? for help")
+	assert.Contains(t, string(spy.data), "toggle the threads section")
+}
+
+type flakyEvents struct {
+	calls int
+	input io.Writer
+}
+
+func (t *flakyEvents) GetRaw() (chan []byte, error) {
+	t.calls++
+	if t.calls == 1 {
+		return nil, errors.New("connection refused")
+	}
+	events := make(chan []byte, 1)
+	events <- []byte(`{"kind": "patch", "data": []}`)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = t.input.Write([]byte("q"))
+	}()
+	return events, nil
+}
+
+func TestDoWatchReconnect(t *testing.T) {
+	defer func(d time.Duration) { reconnectMinDelay = d }(reconnectMinDelay)
+	reconnectMinDelay = 10 * time.Millisecond
+	r, w := io.Pipe()
+	m := New()
+	m.SetColor("no")
+	m.SetInput(r)
+	m.SetStatusGetter(&mockDaemonStatus{daemonResultString})
+	spy := recorder{}
+	getter := &flakyEvents{input: w}
+	err := m.DoWatch(getter, &spy)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, getter.calls)
+	assert.Contains(t, string(spy.data), "disconnected: connection refused")
+	assert.Contains(t, string(spy.data), "Objects")
+}
@@ -6,10 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"time"
 
-	"github.com/inancgumus/screen"
 	"github.com/pkg/errors"
+	"golang.org/x/term"
 
 	"opensvc.com/opensvc/core/cluster"
 	"opensvc.com/opensvc/core/event"
@@ -21,19 +22,40 @@ import (
 type (
 	// T is a monitor renderer instance. It stores the rendering options.
 	T struct {
-		color    string
-		format   string
-		selector string
-		sections []string
-		nodes    []string
+		color     string
+		format    string
+		selector  string
+		namespace string
+		sections  []string
+		nodes     []string
+		input     io.Reader
+
+		// statusGetter, if set, fetches the full status on watch
+		// reconnect.
+		statusGetter Getter
 	}
 )
 
+var (
+	// reconnectMinDelay is the delay before the first event stream
+	// reconnection attempt. It doubles on each failed attempt.
+	reconnectMinDelay = time.Second
+
+	// reconnectMaxDelay is the maximum delay between two event stream
+	// reconnection attempts.
+	reconnectMaxDelay = 30 * time.Second
+)
+
 // CmdLong factorizes the long desc text defined by commands invoking a Monitor.
 const CmdLong = `Color convention:
   red     issue
   orange  warning
 
+Node Flags:
+  *       Frozen
+  @       Speaker
+  ?       No data received
+
 Object Flags:
   !       Warning
   ^       Placement non-optimal
@@ -48,12 +70,16 @@ Instance Flags:
   *       Frozen
   ^       Placement leader
   #       DRP instance
+
+Watch mode:
+  Press ? for the keys toggling the sections, filtering the objects
+  and pausing the refresh.
 `
 
 // New allocates a monitor.
 func New() T {
 	return T{
-		selector: "*",
+		selector: "**",
 		color:    "auto",
 		format:   "auto",
 	}
@@ -71,6 +97,18 @@ func (m *T) SetFormat(v string) {
 	m.format = v
 }
 
+// SetSelector sets the object selector expression, limiting the rendered
+// objects to those matching. Default is "**", interpreted as all objects.
+func (m *T) SetSelector(v string) {
+	m.selector = v
+}
+
+// SetNamespace sets the namespace of the objects to render. Defaults to an
+// empty string, interpreted as all namespaces.
+func (m *T) SetNamespace(v string) {
+	m.namespace = v
+}
+
 // SetSections sets the sections option, controlling which sections to render
 // (threads, nodes, arbitrators, objects). Defaults to an empty list, interpreted
 // as all sections.
@@ -84,6 +122,12 @@ func (m *T) SetNodes(v []string) {
 	m.nodes = v
 }
 
+// SetStatusGetter sets the getter used to resync the watch with a full
+// status fetch when the event stream is reopened.
+func (m *T) SetStatusGetter(g Getter) {
+	m.statusGetter = g
+}
+
 type Getter interface {
 	Get() ([]byte, error)
 }
@@ -103,24 +147,89 @@ func (m T) Do(getter Getter, out io.Writer) error {
 	if err := json.Unmarshal(b, &data); err != nil {
 		return err
 	}
-	m.doOneShot(data, false, out)
+	_, _ = fmt.Fprint(out, m.sprint(data, nil))
 	return nil
 }
 
+// DoWatch renders the cluster status on each change received from the
+// event getter. If an input is set, its keystrokes control the rendering,
+// until the user quits.
+//
+// When the event stream drops, the last known status is rendered with a
+// disconnected banner, and the stream is reopened with an exponential
+// backoff. If a status getter is set, the full status is fetched on
+// reconnect to resync the rendering.
 func (m T) DoWatch(eventGetter EventGetter, out io.Writer) error {
+	keys, restore, err := m.keys()
+	if err != nil {
+		return err
+	}
+	if restore != nil {
+		defer restore()
+		out = crlfWriter{w: out}
+	}
+	v := newView()
+	p := newPainter(terminalHeight(out))
+	data := &cluster.Status{}
+	delay := reconnectMinDelay
 	for {
-		if err := m.watch(eventGetter, out); err != nil {
+		connected, err := m.watch(eventGetter, out, v, p, keys, data)
+		switch {
+		case errors.Is(err, errQuit):
+			return nil
+		case err == nil:
+			err = errors.New("event stream closed")
+		}
+		if connected {
+			delay = reconnectMinDelay
+		}
+		v.disconnected = err
+		v.reconnect = time.Now().Add(delay)
+		if err := m.waitReconnect(out, v, p, keys, data, delay); err != nil {
+			if errors.Is(err, errQuit) {
+				return nil
+			}
 			return err
 		}
-		// unexpected: avoid fast looping
-		time.Sleep(100 * time.Millisecond)
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+// waitReconnect renders the last known status with the disconnected banner
+// until the reconnect delay expires, still handling the keystrokes.
+func (m T) waitReconnect(out io.Writer, v *view, p *painter, keys chan byte, data *cluster.Status, delay time.Duration) error {
+	if err := p.paint(out, m.sprint(*data, v)); err != nil {
+		return err
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			return nil
+		case k, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			if err := v.handleKey(k); err != nil {
+				return err
+			}
+			if err := p.paint(out, m.sprint(*data, v)); err != nil {
+				return err
+			}
+		}
 	}
-	return nil
 }
 
-func (m T) watch(eventGetter EventGetter, out io.Writer) error {
+// watch renders the cluster status changes until the event stream drops or
+// the user quits. It returns true if the event stream was opened.
+func (m T) watch(eventGetter EventGetter, out io.Writer, v *view, p *painter, keys chan byte, data *cluster.Status) (bool, error) {
 	var (
-		data   cluster.Status
+		b      []byte
 		ok     bool
 		err    error
 		evt    event.Event
@@ -128,61 +237,117 @@ func (m T) watch(eventGetter EventGetter, out io.Writer) error {
 	)
 	events, err = eventGetter.GetRaw()
 	if err != nil {
-		return err
+		return false, err
+	}
+	if m.statusGetter != nil {
+		// resync from a full status
+		if b, err = m.statusGetter.Get(); err != nil {
+			return true, errors.Wrap(err, "get status")
+		}
 	}
-	b, ok := <-events
+	e, ok := <-events
 	if !ok {
-		return errors.New("event channel unexpectedly closed")
+		return true, errors.New("event channel unexpectedly closed")
 	}
-	evt, err = event.DecodeFromJSON(b)
+	evt, err = event.DecodeFromJSON(e)
 	if err != nil {
-		return err
-	}
-	b = *evt.Data
-	if err := json.Unmarshal(*evt.Data, &data); err != nil {
-		return err
+		return true, err
 	}
-	m.doOneShot(data, true, out)
-	for e := range events {
-		evt, err := event.DecodeFromJSON(e)
-		if err != nil {
-			//log.Debug().Err(err).Msgf("decode event %v", e)
-			continue
+	var seq *jsondelta.Sequence
+	if b == nil || evt.Kind == "full" {
+		seq = jsondelta.NewSequence(*evt.Data, evt.ID, true)
+	} else {
+		seq = jsondelta.NewSequence(b, 0, true)
+		if err := handleEvent(seq, evt); err != nil {
+			return true, err
 		}
+	}
+	*data = cluster.Status{}
+	if err := json.Unmarshal(seq.Bytes(), data); err != nil {
+		return true, err
+	}
+	v.disconnected = nil
+	if err := p.paint(out, m.sprint(*data, v)); err != nil {
+		return true, err
+	}
+	for {
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				// input closed: keep watching without keys
+				keys = nil
+				continue
+			}
+			if err := v.handleKey(k); err != nil {
+				return true, err
+			}
+			if err := p.paint(out, m.sprint(*data, v)); err != nil {
+				return true, err
+			}
+		case e, ok := <-events:
+			if !ok {
+				return true, nil
+			}
+			evt, err := event.DecodeFromJSON(e)
+			if err != nil {
+				//log.Debug().Err(err).Msgf("decode event %v", e)
+				continue
+			}
 
-		switch evt.Kind {
-		case "event":
-			continue
-		case "patch", "full":
-			// pass
-		default:
-			// unexpected: avoid fast looping
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
+			switch evt.Kind {
+			case "event":
+				continue
+			case "patch", "full":
+				// pass
+			default:
+				// unexpected: avoid fast looping
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
 
-		if err := handleEvent(&b, evt); err != nil {
-			return errors.Wrap(err, "handle event")
-		}
-		if err := json.Unmarshal(b, &data); err != nil {
-			return errors.Wrap(err, "unmarshal event data")
+			if err := handleEvent(seq, evt); err != nil {
+				// a missed patch makes the data diverge: resync
+				return true, err
+			}
+			*data = cluster.Status{}
+			if err := json.Unmarshal(seq.Bytes(), data); err != nil {
+				return true, errors.Wrap(err, "unmarshal event data")
+			}
+			if v.paused {
+				continue
+			}
+			if err := p.paint(out, m.sprint(*data, v)); err != nil {
+				return true, err
+			}
 		}
-		m.doOneShot(data, true, out)
 	}
-	return nil
 }
 
-func handleEvent(b *[]byte, e event.Event) (err error) {
+func handleEvent(seq *jsondelta.Sequence, e event.Event) error {
 	patch := jsondelta.NewPatch(*e.Data)
-	*b, err = patch.Apply(*b)
-	return
+	if err := seq.Apply(e.ID, patch); err != nil {
+		return errors.Wrap(err, "handle event")
+	}
+	return nil
 }
 
-func (m T) doOneShot(data cluster.Status, clear bool, out io.Writer) {
+// sprint returns the cluster status rendering. The view, if not nil, filters
+// the data and the sections, and adds the interactive help and state lines.
+func (m T) sprint(data cluster.Status, v *view) string {
+	data = objectFilter{selector: m.selector, namespace: m.namespace}.apply(data)
+	sections := m.sections
+	if v != nil {
+		data = v.apply(data)
+		sections = v.sections(m.sections)
+	}
 	human := func() string {
+		if v != nil && len(sections) == 0 {
+			// all sections toggled off
+			return ""
+		}
 		f := cluster.Frame{
 			Current:  data,
-			Sections: m.sections,
+			Sections: sections,
 		}
 		return f.Render()
 	}
@@ -195,9 +360,30 @@ func (m T) doOneShot(data cluster.Status, clear bool, out io.Writer) {
 		Colorize:      rawconfig.Node.Colorize,
 	}.Sprint()
 
-	if clear {
-		screen.Clear()
-		screen.MoveTopLeft()
+	if v != nil && output.New(m.format) == output.Human {
+		s = v.banner() + s
+		if m.input != nil {
+			s = v.header() + s + v.footer()
+		}
+	}
+	return s
+}
+
+// terminalHeight returns a function returning the height of the terminal
+// out is writing to, or zero if out is not a terminal.
+func terminalHeight(out io.Writer) func() int {
+	if w, ok := out.(crlfWriter); ok {
+		out = w.w
+	}
+	f, ok := out.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+	return func() int {
+		_, h, err := term.GetSize(int(f.Fd()))
+		if err != nil {
+			return 0
+		}
+		return h
 	}
-	_, _ = fmt.Fprint(out, s)
 }
@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// painter repaints a text frame on a terminal, only rewriting the
+	// lines changed since the previous frame, to avoid the flicker and the
+	// cost of a full screen clear on each update.
+	painter struct {
+		// height returns the terminal height. A zero height means unknown,
+		// in which case the frames are not truncated.
+		height func() int
+
+		lines      []string
+		lastHeight int
+	}
+)
+
+const (
+	ansiClearScreen   = "\x1b[2J"
+	ansiClearLineEnd  = "\x1b[K"
+	ansiClearDown     = "\x1b[J"
+	ansiMoveLineStart = "\x1b[%d;1H"
+)
+
+func newPainter(height func() int) *painter {
+	return &painter{
+		height: height,
+	}
+}
+
+// paint writes to out the escape sequences and text needed to turn the
+// previously painted frame into s.
+func (p *painter) paint(out io.Writer, s string) error {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	height := 0
+	if p.height != nil {
+		height = p.height()
+	}
+	if height > 0 && len(lines) > height {
+		// the cursor can not be positioned on the scrolled out lines
+		lines = lines[:height]
+	}
+	var b strings.Builder
+	if p.lines == nil || height != p.lastHeight {
+		b.WriteString(ansiClearScreen)
+		p.lines = []string{}
+	}
+	p.lastHeight = height
+	for i, line := range lines {
+		if i < len(p.lines) && p.lines[i] == line {
+			continue
+		}
+		fmt.Fprintf(&b, ansiMoveLineStart, i+1)
+		b.WriteString(line)
+		b.WriteString(ansiClearLineEnd)
+	}
+	if len(lines) < len(p.lines) {
+		fmt.Fprintf(&b, ansiMoveLineStart, len(lines)+1)
+		b.WriteString(ansiClearDown)
+	}
+	// park the cursor after the frame
+	if height == 0 || len(lines) < height {
+		fmt.Fprintf(&b, ansiMoveLineStart, len(lines)+1)
+	}
+	p.lines = lines
+	_, err := io.WriteString(out, b.String())
+	return err
+}
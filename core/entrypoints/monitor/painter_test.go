@@ -0,0 +1,32 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPainter(t *testing.T) {
+	p := newPainter(nil)
+	var b strings.Builder
+
+	require.NoError(t, p.paint(&b, "a\nb\nc\n"))
+	assert.Equal(t, "\x1b[2J\x1b[1;1Ha\x1b[K\x1b[2;1Hb\x1b[K\x1b[3;1Hc\x1b[K\x1b[4;1H", b.String())
+
+	b.Reset()
+	require.NoError(t, p.paint(&b, "a\nB\nc\n"))
+	assert.Equal(t, "\x1b[2;1HB\x1b[K\x1b[4;1H", b.String(), "only the changed line is repainted")
+
+	b.Reset()
+	require.NoError(t, p.paint(&b, "a\n"))
+	assert.Equal(t, "\x1b[2;1H\x1b[J\x1b[2;1H", b.String(), "the lines below the shorter frame are cleared")
+}
+
+func TestPainterTruncate(t *testing.T) {
+	p := newPainter(func() int { return 2 })
+	var b strings.Builder
+	require.NoError(t, p.paint(&b, "a\nb\nc\n"))
+	assert.Equal(t, "\x1b[2J\x1b[1;1Ha\x1b[K\x1b[2;1Hb\x1b[K", b.String())
+}
@@ -1,8 +1,7 @@
 package nodeaction
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -203,12 +202,9 @@ func (t T) DoLocal() error {
 		Colorize:      rawconfig.Node.Colorize,
 	}.Print()
 	if r.Panic != nil {
-		return errors.New("")
+		return fmt.Errorf("%s", r.Panic)
 	}
-	if r.Error != nil {
-		return errors.New("")
-	}
-	return nil
+	return r.Error
 }
 
 // DoAsync uses the agent API to submit a target state to reach via an
@@ -235,32 +231,47 @@ func (t T) DoAsync() {
 	}.Print()
 }
 
-// DoRemote posts the action to a peer node agent API, for synchronous
-// execution.
-func (t T) DoRemote() {
+//
+// DoRemote posts the action to the agent api of each node selected by
+// NodeSelector, for synchronous execution. The posts are concurrent, and
+// the per-node results are rendered as a merged table.
+//
+func (t T) DoRemote() error {
 	c, err := client.New(client.WithURL(t.Server))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, err)
+		return err
 	}
-	req := c.NewPostNodeAction()
-	req.NodeSelector = t.NodeSelector
-	req.Action = t.Action
-	req.Options = t.PostFlags
-	b, err := req.Do()
-	data := &struct {
-		Err    string `json:"err"`
-		Out    string `json:"out"`
-		Status int    `json:"status"`
-	}{}
-	if err := json.Unmarshal(b, data); err != nil {
-		log.Error().Err(err).Msg("")
-		return
+	nodes, err := action.RemoteNodes(c, t.NodeSelector)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
 	}
-	fmt.Fprintf(os.Stdout, data.Out)
-	fmt.Fprintf(os.Stderr, data.Err)
+	rs := action.FanOut(context.Background(), nodes, func(ctx context.Context, node string) ([]byte, error) {
+		req := c.NewPostNodeAction()
+		req.SetContext(ctx)
+		req.NodeSelector = node
+		req.Action = t.Action
+		req.Options = t.PostFlags
+		req.SetNode(node)
+		return req.Do()
+	})
+	output.Renderer{
+		Format:        t.Format,
+		Color:         t.Color,
+		Data:          rs,
+		HumanRenderer: rs.Render,
+		Colorize:      rawconfig.Node.Colorize,
+	}.Print()
+	if err := rs.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	return nil
 }
 
+// Do executes the action. The returned error exit code is defined by
+// the exitcode package.
 func (t T) Do() error {
 	return action.Do(t)
 }
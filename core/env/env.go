@@ -26,3 +26,15 @@ func Kind() string {
 func Context() string {
 	return os.Getenv("OSVC_CONTEXT")
 }
+
+// Requester returns the name of the user who submitted the action to the
+// daemon api, forwarded via the OSVC_REQUESTER variable.
+func Requester() string {
+	return os.Getenv("OSVC_REQUESTER")
+}
+
+// RequesterAddr returns the address of the api client who submitted the
+// action, forwarded via the OSVC_REQUESTER_ADDR variable.
+func RequesterAddr() string {
+	return os.Getenv("OSVC_REQUESTER_ADDR")
+}
@@ -0,0 +1,82 @@
+package event
+
+import (
+	"encoding/json"
+	"os/user"
+	"time"
+
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	// Audit is the events store record of an action: who requested it,
+	// from where, the command executed and its result.
+	Audit struct {
+		Who      string        `json:"who"`
+		From     string        `json:"from"`
+		Command  []string      `json:"command"`
+		Result   string        `json:"result"`
+		ExitCode int           `json:"exit_code"`
+		Begin    time.Time     `json:"begin"`
+		Duration time.Duration `json:"duration"`
+	}
+)
+
+const (
+	// KindAudit is the kind of the actions audit records events.
+	KindAudit = "audit"
+
+	// AuditFromLocal is the origin of the actions not submitted through
+	// the daemon api.
+	AuditFromLocal = "local"
+)
+
+//
+// NewAudit returns the audit record of the command starting now. The
+// requester and its address are read from the OSVC_REQUESTER and
+// OSVC_REQUESTER_ADDR variables, defaulting to the process user and to a
+// local origin.
+//
+// The daemon api sets OSVC_ACTION_ORIGIN and OSVC_REQUESTER in the
+// environment of the object and node actions it executes on behalf of
+// its clients. It does not set OSVC_REQUESTER_ADDR, as its clients are
+// local processes.
+//
+func NewAudit(command []string) *Audit {
+	t := &Audit{
+		Who:     env.Requester(),
+		From:    env.RequesterAddr(),
+		Command: command,
+		Begin:   time.Now(),
+	}
+	if t.Who == "" {
+		if u, err := user.Current(); err == nil {
+			t.Who = u.Username
+		}
+	}
+	if t.From == "" {
+		t.From = AuditFromLocal
+	}
+	return t
+}
+
+// Event returns the event of the audit record of the command exited with
+// code.
+func (t Audit) Event(code int) Event {
+	now := time.Now()
+	t.ExitCode = code
+	t.Duration = now.Sub(t.Begin)
+	if code == 0 {
+		t.Result = "ok"
+	} else {
+		t.Result = "error"
+	}
+	b, _ := json.Marshal(t)
+	data := json.RawMessage(b)
+	return Event{
+		Kind:      KindAudit,
+		Timestamp: timestamp.New(now),
+		Data:      &data,
+	}
+}
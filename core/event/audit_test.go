@@ -0,0 +1,66 @@
+package event
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	os.Setenv("OSVC_REQUESTER", "alice")
+	os.Setenv("OSVC_REQUESTER_ADDR", "10.0.0.1:51234")
+	defer os.Unsetenv("OSVC_REQUESTER")
+	defer os.Unsetenv("OSVC_REQUESTER_ADDR")
+
+	data := json.RawMessage(`{"name":"daemon_start"}`)
+	store := NewStore("n1")
+	stream := Event{ID: 3, Kind: "event", Timestamp: timestamp.New(time.Now().Add(-time.Hour)), Data: &data}
+	require.NoError(t, store.Append(stream))
+	audit := NewAudit([]string{"om", "s1", "stop"})
+	require.NoError(t, store.Append(audit.Event(1)))
+	// the audit records do not hide the stream events replays
+	require.NoError(t, store.Append(stream))
+
+	events, err := store.Read(Filter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	events, err = store.Read(Filter{Since: time.Now().Add(-time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, KindAudit, events[0].Kind)
+	var record Audit
+	require.NoError(t, json.Unmarshal(*events[0].Data, &record))
+	assert.Equal(t, "alice", record.Who)
+	assert.Equal(t, "10.0.0.1:51234", record.From)
+	assert.Equal(t, []string{"om", "s1", "stop"}, record.Command)
+	assert.Equal(t, "error", record.Result)
+	assert.Equal(t, 1, record.ExitCode)
+	assert.Contains(t, Render(events[0]), "alice")
+
+	events, err = store.Read(Filter{Kinds: []string{"event"}})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestNewAuditDefaults(t *testing.T) {
+	audit := NewAudit([]string{"om", "node", "freeze"})
+	assert.Equal(t, AuditFromLocal, audit.From)
+	assert.NotEmpty(t, audit.Who)
+	var record Audit
+	e := audit.Event(0)
+	assert.NoError(t, json.Unmarshal(*e.Data, &record))
+	assert.Equal(t, "ok", record.Result)
+}
@@ -10,7 +10,7 @@ import (
 // Render formats a opensvc agent event
 func Render(e Event) string {
 	s := fmt.Sprintf("%s %s\n", e.Timestamp, e.Kind)
-	if e.Kind == "event" {
+	if (e.Kind == "event" || e.Kind == KindAudit) && e.Data != nil {
 		s += output.SprintFlat(*e.Data)
 	} else if e.Data != nil {
 		patch := jsondelta.NewPatch(*e.Data)
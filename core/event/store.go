@@ -0,0 +1,280 @@
+package event
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opensvc/fcntllock"
+	"github.com/opensvc/flock"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/xsession"
+)
+
+type (
+	// Store is a ring of the recent events of a node, persisted as two
+	// json-lines files: the current file and the previous file. When the
+	// current file grows over MaxSize, it replaces the previous file, so
+	// the store holds between MaxSize and 2*MaxSize bytes of events.
+	//
+	// The store holds the daemon event stream, and the unidentified
+	// records of the local node, like the daemon start and stop events
+	// and the actions audit records.
+	Store struct {
+		Nodename string
+		MaxSize  int64
+
+		// last is the most recent stored stream event, and tail the current
+		// file info after it was stored, so Append only scans the
+		// events appended since by other writers. tail is nil if the
+		// current file was rotated.
+		cached bool
+		last   *Event
+		tail   os.FileInfo
+	}
+
+	// Filter selects the events returned by Store.Read.
+	Filter struct {
+		// Since, if not zero, drops the events older than this time.
+		Since time.Time
+
+		// Until, if not zero, drops the events more recent than this time.
+		Until time.Time
+
+		// Kinds, if not empty, drops the events not of one of these kinds.
+		Kinds []string
+	}
+)
+
+var (
+	// DefaultStoreMaxSize is the default maximum size of a store file.
+	DefaultStoreMaxSize int64 = 4 * 1024 * 1024
+
+	storeLockTimeout = 5 * time.Second
+)
+
+// NewStore returns the events store of the node.
+func NewStore(nodename string) *Store {
+	return &Store{
+		Nodename: nodename,
+		MaxSize:  DefaultStoreMaxSize,
+	}
+}
+
+// File returns the path of the store current file.
+func (t Store) File() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "events", t.Nodename+".jsonl")
+}
+
+func (t Store) previousFile() string {
+	return t.File() + ".1"
+}
+
+// Match returns true if the event is selected by the filter.
+func (t Filter) Match(e Event) bool {
+	tm := e.Timestamp.Time()
+	if !t.Since.IsZero() && tm.Before(t.Since) {
+		return false
+	}
+	if !t.Until.IsZero() && tm.After(t.Until) {
+		return false
+	}
+	if len(t.Kinds) == 0 {
+		return true
+	}
+	for _, kind := range t.Kinds {
+		if kind == e.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// Append persists the events in the store. The stream events already
+// stored, as seen by multiple consumers of the same event stream, are
+// skipped. The events with a zero id are not stream events, and are
+// always stored.
+//
+func (t *Store) Append(events ...Event) error {
+	p := t.File()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	lock := flock.New(p+".lock", xsession.ID, fcntllock.New)
+	if err := lock.Lock(storeLockTimeout, "events store append"); err != nil {
+		return err
+	}
+	defer func() { _ = lock.UnLock() }()
+	last, err := t.lastEvent()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	t.cached = false
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if e.ID != 0 && last != nil && e.ID <= last.ID && !e.Timestamp.Time().After(last.Timestamp.Time()) {
+			continue
+		}
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+		if e.ID != 0 {
+			stored := e
+			last = &stored
+		}
+	}
+	tail, err := t.rotate(f)
+	if err != nil {
+		return err
+	}
+	t.cached, t.last, t.tail = true, last, tail
+	return nil
+}
+
+// rotate replaces the previous file with the current file when the
+// current file is larger than MaxSize. It returns the current file info,
+// nil if rotated.
+func (t Store) rotate(f *os.File) (os.FileInfo, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if t.MaxSize <= 0 || info.Size() < t.MaxSize {
+		return info, nil
+	}
+	return nil, os.Rename(t.File(), t.previousFile())
+}
+
+//
+// lastEvent returns the most recent stored stream event, nil if none is
+// empty. The cached last event is updated with the events appended to
+// the current file by other writers since, and the files are scanned
+// only if another writer rotated the current file.
+//
+func (t *Store) lastEvent() (*Event, error) {
+	info, err := os.Stat(t.File())
+	switch {
+	case os.IsNotExist(err):
+		info = nil
+	case err != nil:
+		return nil, err
+	}
+	var offset int64
+	switch {
+	case !t.cached:
+		return t.scanLast()
+	case t.tail == nil:
+		// rotated by us: the current file only holds newer events
+	case info != nil && os.SameFile(t.tail, info) && info.Size() >= t.tail.Size():
+		offset = t.tail.Size()
+	default:
+		return t.scanLast()
+	}
+	if info == nil {
+		return t.last, nil
+	}
+	last := t.last
+	err = scanFileFrom(t.File(), offset, func(e Event) {
+		if e.ID != 0 {
+			last = &e
+		}
+	})
+	return last, err
+}
+
+// scanLast returns the most recent stored stream event, scanning the
+// files.
+func (t Store) scanLast() (*Event, error) {
+	var last *Event
+	for _, p := range []string{t.previousFile(), t.File()} {
+		err := scanFile(p, func(e Event) {
+			if e.ID != 0 {
+				last = &e
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return last, nil
+}
+
+// Read returns the stored events selected by the filter, oldest first.
+func (t Store) Read(filter Filter) ([]Event, error) {
+	l := make([]Event, 0)
+	for _, p := range []string{t.previousFile(), t.File()} {
+		err := scanFile(p, func(e Event) {
+			if filter.Match(e) {
+				l = append(l, e)
+			}
+		})
+		if err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+// scanFile calls fn for each valid event of the file. The corrupted
+// lines, like a partial write on a full filesystem, are ignored.
+func scanFile(p string, fn func(Event)) error {
+	return scanFileFrom(p, 0, fn)
+}
+
+// scanFileFrom calls fn for each valid event of the file, starting at
+// offset.
+func scanFileFrom(p string, offset int64, fn func(Event)) error {
+	f, err := os.Open(p)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	defer f.Close()
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	r := bufio.NewReader(f)
+	for {
+		b, err := r.ReadBytes('\n')
+		if len(b) > 0 {
+			if e, err := DecodeFromJSON(b); err == nil {
+				fn(e)
+			}
+		}
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+}
+
+// ParseTime returns the time represented by s, either a duration relative
+// to now, like 1h or 30m, or a RFC3339 date, like 2021-03-01T10:00:00Z.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if tm, err := time.Parse(time.RFC3339, s); err == nil {
+		return tm, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %s: expected a duration like 1h or a RFC3339 date", s)
+}
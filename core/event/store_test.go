@@ -0,0 +1,104 @@
+package event
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	base := time.Unix(1600000000, 0)
+	data := json.RawMessage(`{}`)
+	newEvent := func(id uint64, kind string) Event {
+		return Event{
+			ID:        id,
+			Kind:      kind,
+			Timestamp: timestamp.New(base.Add(time.Duration(id) * time.Minute)),
+			Data:      &data,
+		}
+	}
+	store := NewStore("n1")
+	store.MaxSize = 150
+	for i := uint64(1); i <= 6; i++ {
+		kind := "patch"
+		if i%2 == 0 {
+			kind = "event"
+		}
+		assert.NoError(t, store.Append(newEvent(i, kind)))
+	}
+	// already stored by another consumer of the stream
+	assert.NoError(t, store.Append(newEvent(6, "event")))
+
+	events, err := store.Read(Filter{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, events)
+	assert.Less(t, len(events), 6, "the oldest events are dropped from the ring")
+	assert.Equal(t, uint64(6), events[len(events)-1].ID)
+
+	events, err = store.Read(Filter{
+		Since: base.Add(4 * time.Minute),
+		Kinds: []string{"event"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	events, err = store.Read(Filter{Until: base.Add(5 * time.Minute), Since: base.Add(5 * time.Minute)})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, uint64(5), events[0].ID)
+}
+
+func TestStoreConcurrentWriters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	base := time.Unix(1600000000, 0)
+	newEvent := func(id uint64) Event {
+		return Event{ID: id, Kind: "event", Timestamp: timestamp.New(base.Add(time.Duration(id) * time.Minute))}
+	}
+	s1 := NewStore("n1")
+	s2 := NewStore("n1")
+	assert.NoError(t, s1.Append(newEvent(1), newEvent(2)))
+	assert.NoError(t, s2.Append(newEvent(2), newEvent(3)))
+	// the cached last event of s1 is updated with the s2 appends
+	assert.NoError(t, s1.Append(newEvent(3), newEvent(4)))
+	assert.NoError(t, os.Rename(s1.File(), s1.previousFile()))
+	// the rotation by another writer is detected
+	assert.NoError(t, s1.Append(newEvent(4), newEvent(5)))
+
+	events, err := s1.Read(Filter{})
+	assert.NoError(t, err)
+	ids := make([]uint64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, ids)
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tm, err := ParseTime("1h", now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-time.Hour), tm)
+	tm, err = ParseTime("2021-03-01T10:00:00Z", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2021, tm.Year())
+	tm, err = ParseTime("", now)
+	assert.NoError(t, err)
+	assert.True(t, tm.IsZero())
+	_, err = ParseTime("yesterday", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+//
+// Package exitcode defines the exit codes of the om commands:
+//
+//   0  ok: the action succeeded on all selected objects and nodes
+//   1  error: the action failed
+//   2  partial: the action failed on some of the selected objects or nodes
+//   3  not found: the selector matched no object
+//   4  aborted: a resource driver vetoed the action
+//   5  timeout: the --wait timeout was reached before the orchestration
+//      reported the target state
+//
+// Errors implementing Coder carry their own exit code. Otherwise the
+// ErrNotFound and ErrTimeout sentinel errors, as tested by errors.Is,
+// select their code, and any other error exits with the Error code.
+//
+package exitcode
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+const (
+	OK       = 0
+	Error    = 1
+	Partial  = 2
+	NotFound = 3
+	Aborted  = 4
+	Timeout  = 5
+)
+
+type (
+	// Coder is the interface implemented by the errors knowing the
+	// exit code the command should return.
+	Coder interface {
+		ExitCode() int
+	}
+)
+
+var (
+	// ErrNotFound is the error of the actions on a selector matching
+	// no object.
+	ErrNotFound = errors.New("object not found")
+
+	// ErrTimeout is the error of the actions whose --wait timeout was
+	// reached before the orchestration reported the target state.
+	ErrTimeout = errors.New("wait timeout")
+
+	atExitMu sync.Mutex
+	atExit   []func(code int)
+)
+
+// Of returns the exit code of the command failed with err.
+func Of(err error) int {
+	var coder Coder
+	switch {
+	case err == nil:
+		return OK
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.Is(err, ErrNotFound):
+		return NotFound
+	case errors.Is(err, ErrTimeout):
+		return Timeout
+	default:
+		return Error
+	}
+}
+
+// AtExit registers a function called with the exit code of the command
+// by Exit or Done, like the actions audit journaling.
+func AtExit(fn func(code int)) {
+	atExitMu.Lock()
+	defer atExitMu.Unlock()
+	atExit = append(atExit, fn)
+}
+
+// Done calls the functions registered by AtExit, once, for a command
+// terminating with code.
+func Done(code int) {
+	atExitMu.Lock()
+	l := atExit
+	atExit = nil
+	atExitMu.Unlock()
+	for _, fn := range l {
+		fn(code)
+	}
+}
+
+// Exit terminates the program with the exit code of err, after calling
+// the functions registered by AtExit.
+func Exit(err error) {
+	code := Of(err)
+	Done(code)
+	os.Exit(code)
+}
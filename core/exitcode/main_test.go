@@ -0,0 +1,41 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type coder int
+
+func (t coder) Error() string { return "coder" }
+func (t coder) ExitCode() int { return int(t) }
+
+func TestOf(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		expected int
+	}{
+		"nil":             {nil, OK},
+		"error":           {errors.New("foo"), Error},
+		"not found":       {ErrNotFound, NotFound},
+		"wrapped timeout": {fmt.Errorf("freeze: %w", ErrTimeout), Timeout},
+		"coder":           {coder(Aborted), Aborted},
+		"wrapped coder":   {fmt.Errorf("start: %w", coder(Partial)), Partial},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Of(c.err))
+		})
+	}
+}
+
+func TestDone(t *testing.T) {
+	codes := make([]int, 0)
+	AtExit(func(code int) { codes = append(codes, code) })
+	Done(Partial)
+	Done(OK)
+	assert.Equal(t, []int{Partial}, codes, "the functions are called once")
+}
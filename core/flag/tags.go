@@ -1,6 +1,26 @@
 package flag
 
 var Tags = map[string]Opt{
+	"applynamespace": Opt{
+		Long: "to-namespace",
+		Desc: "apply all the manifests to this namespace, overriding their namespace",
+	},
+	"array": Opt{
+		Long: "array",
+		Desc: "the name of the array, as declared by a array#<name> section of the node or cluster configuration",
+	},
+	"changed": Opt{
+		Long: "changed",
+		Desc: "report only the keywords with a value different from the default",
+	},
+	"clustername": Opt{
+		Long: "name",
+		Desc: "the cluster name, used as the cluster dns zone name",
+	},
+	"clustersecret": Opt{
+		Long: "secret",
+		Desc: "the cluster secret. a random secret is generated if not set",
+	},
 	"color": Opt{
 		Long:    "color",
 		Default: "auto",
@@ -10,6 +30,10 @@ var Tags = map[string]Opt{
 		Long: "config",
 		Desc: "the configuration to use as template when creating or installing a service. the value can be `-` or `/dev/stdin` to read the json-formatted configuration from stdin, or a file path, or uri pointing to a ini-formatted configuration, or a service selector expression (ATTENTION with cloning existing live services that include more than containers, volumes and backend ip addresses ... this could cause disruption on the cloned service)",
 	},
+	"confirm": Opt{
+		Long: "confirm",
+		Desc: "confirm the run of the tasks configured with confirmation=true",
+	},
 	"disable-rollback": Opt{
 		Long: "disable-rollback",
 		Desc: "on action error, do not return activated resources to their previous state",
@@ -18,6 +42,22 @@ var Tags = map[string]Opt{
 		Long: "discard",
 		Desc: "discard the stashed, invalid, configuration file leftover of a previous execution",
 	},
+	"diskgroup": Opt{
+		Long: "diskgroup",
+		Desc: "the name of the array disk group to allocate the disk from",
+	},
+	"diskid": Opt{
+		Long: "id",
+		Desc: "the array-side identifier of the disk",
+	},
+	"diskname": Opt{
+		Long: "name",
+		Desc: "the array-side name of the disk",
+	},
+	"disksize": Opt{
+		Long: "size",
+		Desc: "the disk size, ex: 10g. on resize, a +/- prefix grows or shrinks the disk by the specified amount",
+	},
 	"downto": Opt{
 		Long:       "downto",
 		Desc:       "stop the service down to the specified rid or driver group",
@@ -38,7 +78,7 @@ var Tags = map[string]Opt{
 	"format": Opt{
 		Long:    "format",
 		Default: "auto",
-		Desc:    "output format json|flat|auto",
+		Desc:    "output format json|flat|csv|yaml|auto",
 	},
 	"force": Opt{
 		Long: "force",
@@ -62,12 +102,16 @@ var Tags = map[string]Opt{
 	},
 	"kwops": Opt{
 		Long: "kw",
-		Desc: "keyword operations, <k><op><v> with op in = |= += -= ^=",
+		Desc: "keyword operations, <k><op><v> with op in = |= += -= ^=, or <k>[<i>]=<v> to insert and <k>[<i>]-= to remove the list element at index i",
 	},
 	"kws": Opt{
 		Long: "kw",
 		Desc: "keyword list",
 	},
+	"last": Opt{
+		Long: "last",
+		Desc: "only print the last run log of each selected task",
+	},
 	"leader": Opt{
 		Long: "leader",
 		Desc: "provision all resources, including shared resources that are not provisioned by default",
@@ -76,18 +120,47 @@ var Tags = map[string]Opt{
 		Long: "local",
 		Desc: "inline action on local instance",
 	},
-	"createnamespace": Opt{
-		Long: "namespace",
-		Desc: "where to create the new objects",
+	"lockqueue": Opt{
+		Long: "queue",
+		Desc: "wait in queue for the action lock, held locally or by a peer node action, instead of failing after the waitlock timeout",
+	},
+	"manifests": Opt{
+		Long:  "file",
+		Short: "f",
+		Desc:  "the directory hosting the object configuration manifests to apply",
+	},
+	"mapping": Opt{
+		Long: "mapping",
+		Desc: "an array target to map the disk to. multiple --mapping <target> can be specified",
 	},
 	"match": Opt{
 		Long:    "match",
 		Desc:    "a fnmatch key name filter",
 		Default: "**",
 	},
+	"modules": Opt{
+		Long: "modules",
+		Desc: "a comma separated list of compliance modules to run",
+	},
+	"modulesets": Opt{
+		Long: "modulesets",
+		Desc: "a comma separated list of compliance modulesets to run. all the modulesets attached to the node are run if neither --modulesets nor --modules is set",
+	},
+	"namespace": Opt{
+		Long: "namespace",
+		Desc: "restrict the selection to the objects of this namespace, and create the new objects in this namespace. defaults to the OSVC_NAMESPACE environment variable value",
+	},
+	"networkstatusname": Opt{
+		Long: "name",
+		Desc: "filter on a network name",
+	},
+	"networkstatusverbose": Opt{
+		Long: "verbose",
+		Desc: "include the network leases",
+	},
 	"node": Opt{
 		Long: "node",
-		Desc: "execute on a list of nodes",
+		Desc: "execute on a selection of nodes. a whitespace-separated list of node names, fnmatch patterns, <label>=<value> and frozen:true|false or state:<monitor state> filters",
 	},
 	"nolock": Opt{
 		Long: "nolock",
@@ -104,6 +177,14 @@ var Tags = map[string]Opt{
 		Default: "",
 		Desc:    "an object selector expression, '**/s[12]+!*/vol/*'",
 	},
+	"objecttimeout": Opt{
+		Long: "object-timeout",
+		Desc: "the maximum duration of the action on each object. the action is cancelled when exceeded, and the object reported failed if the action does not complete. 0 means no timeout",
+	},
+	"oldsecret": Opt{
+		Long: "oldsecret",
+		Desc: "the cluster secret the keys were encrypted with before the secret rotation",
+	},
 	"poolstatusname": Opt{
 		Long: "name",
 		Desc: "filter on a pool name",
@@ -112,6 +193,15 @@ var Tags = map[string]Opt{
 		Long: "verbose",
 		Desc: "include pool volumes",
 	},
+	"parallel": Opt{
+		Long:  "parallel",
+		Short: "p",
+		Desc:  "the maximum number of objects actioned concurrently. 0 means no limit",
+	},
+	"prune": Opt{
+		Long: "prune",
+		Desc: "delete the objects of the manifests namespaces having no manifest",
+	},
 	"provision": Opt{
 		Long: "provision",
 		Desc: "provision the object after create",
@@ -129,6 +219,10 @@ var Tags = map[string]Opt{
 		Long: "restore",
 		Desc: "keep the same object id as the origin template or config file. the default is to generate a new id",
 	},
+	"rev": Opt{
+		Long: "rev",
+		Desc: "the previous configuration generation to print. 0 is the installed configuration, 1 the configuration it replaced",
+	},
 	"rid": Opt{
 		Long: "rid",
 		Desc: "resource selector expression (ip#1,app,disk.type=zvol)",
@@ -137,15 +231,44 @@ var Tags = map[string]Opt{
 		Long: "server",
 		Desc: "uri of the opensvc api server. scheme raw|https",
 	},
+	"sid": Opt{
+		Long: "sid",
+		Desc: "only report the log entries of the action with this session id",
+	},
 	"time": Opt{
 		Long:    "time",
 		Default: "5m",
 		Desc:    "stop waiting for the object to reach the target state after a duration",
 	},
+	"snapkeep": Opt{
+		Long:    "keep",
+		Default: "1",
+		Desc:    "the number of most recent snapshots to keep per resource",
+	},
+	"snapname": Opt{
+		Long: "name",
+		Desc: "the snapshot name",
+	},
+	"stats-from": Opt{
+		Long: "from",
+		Desc: "report the stats samples since this date, expressed as a duration relative to now, like 1h, or a RFC3339 date. default is 24h",
+	},
+	"stats-to": Opt{
+		Long: "to",
+		Desc: "report the stats samples until this date, expressed as a duration relative to now, like 1h, or a RFC3339 date",
+	},
 	"subsets": Opt{
 		Long: "subsets",
 		Desc: "subset selector expression (g1,g2)",
 	},
+	"switch-to": Opt{
+		Long: "to",
+		Desc: "the node to switch the object instances to",
+	},
+	"target": Opt{
+		Long: "target",
+		Desc: "the sync targets: nodes, drpnodes. all the targets if not set. multiple --target can be specified",
+	},
 	"template": Opt{
 		Long: "template",
 		Desc: "the configuration file template name or id, served by the collector",
@@ -165,7 +288,7 @@ var Tags = map[string]Opt{
 	},
 	"from": Opt{
 		Long: "from",
-		Desc: "the key value source (uri, file, /dev/stdin)",
+		Desc: "the key value source (uri, file, /dev/stdin), or a secret backend reference stored as-is in sec objects (vault://<path>#<field>)",
 	},
 	"value": Opt{
 		Long: "value",
@@ -185,4 +308,8 @@ var Tags = map[string]Opt{
 		Short: "w",
 		Desc:  "watch the monitor changes",
 	},
+	"winner": Opt{
+		Long: "winner",
+		Desc: "the name of a node of the segment winning the split arbitration",
+	},
 }
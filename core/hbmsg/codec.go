@@ -0,0 +1,138 @@
+package hbmsg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+type (
+	//
+	// Codec compresses the heartbeat frames. The codec id is written in
+	// the first byte of each frame, so the receiver decodes the frames
+	// whatever the codec negotiated by the sender.
+	//
+	Codec struct {
+		Name       string
+		ID         byte
+		Compress   func([]byte) ([]byte, error)
+		Decompress func([]byte) ([]byte, error)
+	}
+)
+
+const (
+	// CodecNone sends the frames uncompressed.
+	CodecNone = "none"
+
+	// CodecGzip compresses the frames with gzip.
+	CodecGzip = "gzip"
+
+	// CodecZstd compresses the frames with zstd. Its id is reserved, but
+	// the codec is only available to the builds registering it.
+	CodecZstd = "zstd"
+)
+
+var (
+	codecsMu   sync.RWMutex
+	codecsName = make(map[string]Codec)
+	codecsID   = make(map[byte]Codec)
+
+	// Preference is the list of codecs in the order they are proposed
+	// to the peers. The codecs not registered are ignored.
+	Preference = []string{CodecZstd, CodecGzip, CodecNone}
+)
+
+func init() {
+	Register(Codec{
+		Name:       CodecNone,
+		ID:         0,
+		Compress:   func(b []byte) ([]byte, error) { return b, nil },
+		Decompress: func(b []byte) ([]byte, error) { return b, nil },
+	})
+	Register(Codec{
+		Name:       CodecGzip,
+		ID:         1,
+		Compress:   gzipCompress,
+		Decompress: gzipDecompress,
+	})
+}
+
+// Register adds a codec to the codecs available for negotiation and
+// decoding.
+func Register(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecsName[c.Name] = c
+	codecsID[c.ID] = c
+}
+
+// Supported returns the names of the registered codecs, in preference
+// order.
+func Supported() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	l := make([]string, 0, len(codecsName))
+	for _, name := range Preference {
+		if _, ok := codecsName[name]; ok {
+			l = append(l, name)
+		}
+	}
+	return l
+}
+
+//
+// Negotiate returns the first codec of the local preference supported
+// by the peer. The none codec is returned if the peer did not advertise
+// its codecs or shares none with the local node.
+//
+func Negotiate(peer []string) Codec {
+	m := make(map[string]interface{}, len(peer))
+	for _, name := range peer {
+		m[name] = nil
+	}
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	for _, name := range Preference {
+		c, ok := codecsName[name]
+		if !ok {
+			continue
+		}
+		if _, ok := m[name]; ok {
+			return c
+		}
+	}
+	return codecsName[CodecNone]
+}
+
+func codecByID(id byte) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecsID[id]
+	if !ok {
+		return c, fmt.Errorf("unsupported codec id %d", id)
+	}
+	return c, nil
+}
+
+func gzipCompress(b []byte) ([]byte, error) {
+	var buff bytes.Buffer
+	w := gzip.NewWriter(&buff)
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+func gzipDecompress(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,99 @@
+package hbmsg
+
+import (
+	"sync"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+type (
+	//
+	// Counters are the traffic counters of a heartbeat peer. The message
+	// rates are computed between two consecutive Fill calls.
+	//
+	Counters struct {
+		mu sync.Mutex
+
+		codec      string
+		txBytes    uint64
+		txRawBytes uint64
+		txMsgs     uint64
+		txFull     uint64
+		rxBytes    uint64
+		rxRawBytes uint64
+		rxMsgs     uint64
+		rxFull     uint64
+		resyncs    uint64
+
+		last       time.Time
+		lastTxMsgs uint64
+		lastRxMsgs uint64
+		txRate     float64
+		rxRate     float64
+	}
+)
+
+func (t *Counters) setCodec(s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.codec = s
+}
+
+func (t *Counters) tx(kind string, raw, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.txMsgs++
+	t.txRawBytes += uint64(raw)
+	t.txBytes += uint64(n)
+	if kind == KindFull {
+		t.txFull++
+	}
+}
+
+func (t *Counters) rx(kind string, raw, n int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rxMsgs++
+	t.rxRawBytes += uint64(raw)
+	t.rxBytes += uint64(n)
+	if kind == KindFull {
+		t.rxFull++
+	}
+}
+
+func (t *Counters) resync() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resyncs++
+}
+
+//
+// Fill sets the counters in the peer status, and updates the message
+// rates with the messages counted since the previous call.
+//
+func (t *Counters) Fill(s *cluster.HeartbeatPeerStatus, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.last.IsZero() {
+		if elapsed := now.Sub(t.last).Seconds(); elapsed > 0 {
+			t.txRate = float64(t.txMsgs-t.lastTxMsgs) / elapsed
+			t.rxRate = float64(t.rxMsgs-t.lastRxMsgs) / elapsed
+		}
+	}
+	t.last = now
+	t.lastTxMsgs = t.txMsgs
+	t.lastRxMsgs = t.rxMsgs
+	s.Codec = t.codec
+	s.TxBytes = t.txBytes
+	s.TxRawBytes = t.txRawBytes
+	s.TxMsgs = t.txMsgs
+	s.TxFull = t.txFull
+	s.TxMsgRate = t.txRate
+	s.RxBytes = t.rxBytes
+	s.RxRawBytes = t.rxRawBytes
+	s.RxMsgs = t.rxMsgs
+	s.RxFull = t.rxFull
+	s.RxMsgRate = t.rxRate
+	s.Resyncs = t.resyncs
+}
@@ -0,0 +1,269 @@
+//
+// Package hbmsg encodes the node data sent to the heartbeat peers.
+//
+// To reduce the heartbeat bandwidth, the encoder of a peer sends the
+// full node data document once, then only the json-delta patches from
+// the previously sent document. A full document is sent again every
+// FullEvery messages, and whenever the receiver asks for a resync, so
+// a receiver missing a patch recovers quickly.
+//
+// The messages are compressed with the codec negotiated with the peer.
+// The codecs supported by the sender are advertised in the full
+// messages.
+//
+package hbmsg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/jsondelta"
+)
+
+type (
+	// Message is the heartbeat message, before compression.
+	Message struct {
+		// Kind is KindFull or KindPatch.
+		Kind string `json:"kind"`
+
+		//
+		// Gen is the generation of the document. A full message resets
+		// the receiver document generation, and each patch increments
+		// it.
+		//
+		Gen uint64 `json:"gen"`
+
+		// Codecs are the codecs supported by the sender, advertised in
+		// the full messages.
+		Codecs []string `json:"codecs,omitempty"`
+
+		// Data is the full document, or the rfc6902 patch.
+		Data json.RawMessage `json:"data"`
+	}
+
+	// Encoder encodes the messages sent to a peer.
+	Encoder struct {
+		fullEvery int
+		codec     Codec
+		last      []byte
+		gen       uint64
+		sinceFull int
+		resync    bool
+		counters  *Counters
+	}
+
+	// Decoder decodes the messages received from a peer.
+	Decoder struct {
+		seq        *jsondelta.Sequence
+		peerCodecs []string
+		counters   *Counters
+	}
+)
+
+const (
+	// KindFull is the kind of the messages holding the full document.
+	KindFull = "full"
+
+	// KindPatch is the kind of the messages holding a patch.
+	KindPatch = "patch"
+)
+
+var (
+	//
+	// ErrResync is returned by Decode when a patch can not be applied,
+	// because no full document was received yet or because a patch was
+	// missed. The caller should ask the peer for a full document.
+	//
+	ErrResync = errors.New("resync needed")
+
+	// DefaultFullEvery is the default number of messages between two
+	// full documents.
+	DefaultFullEvery = 60
+)
+
+// NewEncoder allocates and returns a peer messages encoder.
+func NewEncoder(opts ...funcopt.O) *Encoder {
+	t := &Encoder{
+		fullEvery: DefaultFullEvery,
+		codec:     Negotiate(nil),
+		counters:  &Counters{},
+	}
+	_ = funcopt.Apply(t, opts...)
+	t.counters.setCodec(t.codec.Name)
+	return t
+}
+
+// WithFullEvery sets the number of messages between two full documents.
+// A value lower than 1 disables the patches.
+func WithFullEvery(n int) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*Encoder)
+		t.fullEvery = n
+		return nil
+	})
+}
+
+// WithCounters sets the counters updated by the encoder or decoder, so
+// a peer encoder and decoder can share their counters.
+func WithCounters(c *Counters) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		switch t := i.(type) {
+		case *Encoder:
+			t.counters = c
+		case *Decoder:
+			t.counters = c
+		}
+		return nil
+	})
+}
+
+// SetPeerCodecs negotiates the codec of the next messages with the
+// codecs advertised by the peer.
+func (t *Encoder) SetPeerCodecs(l []string) {
+	t.codec = Negotiate(l)
+	t.counters.setCodec(t.codec.Name)
+}
+
+// Resync forces the next message to hold the full document.
+func (t *Encoder) Resync() {
+	t.resync = true
+}
+
+// Counters returns the counters updated by the encoder.
+func (t *Encoder) Counters() *Counters {
+	return t.counters
+}
+
+// Encode returns the compressed message sending the doc json object to
+// the peer.
+func (t *Encoder) Encode(doc []byte) ([]byte, error) {
+	msg, err := t.message(doc)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	frame, err := t.codec.Compress(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s compress", t.codec.Name)
+	}
+	frame = append([]byte{t.codec.ID}, frame...)
+	t.last = doc
+	t.gen = msg.Gen
+	if msg.Kind == KindFull {
+		t.sinceFull = 0
+		t.resync = false
+	} else {
+		t.sinceFull++
+	}
+	t.counters.tx(msg.Kind, len(b), len(frame))
+	return frame, nil
+}
+
+func (t *Encoder) message(doc []byte) (Message, error) {
+	if t.last == nil || t.resync || t.fullEvery < 1 || t.sinceFull+1 >= t.fullEvery {
+		return t.full(doc), nil
+	}
+	patch, err := jsondelta.Diff(t.last, doc)
+	if err != nil {
+		return Message{}, err
+	}
+	b, err := patch.MarshalRFC6902()
+	if err != nil {
+		return Message{}, err
+	}
+	if len(b) >= len(doc) {
+		// the patch is no lighter than the document
+		return t.full(doc), nil
+	}
+	return Message{
+		Kind: KindPatch,
+		Gen:  t.gen + 1,
+		Data: b,
+	}, nil
+}
+
+func (t *Encoder) full(doc []byte) Message {
+	return Message{
+		Kind:   KindFull,
+		Gen:    t.gen + 1,
+		Codecs: Supported(),
+		Data:   doc,
+	}
+}
+
+// NewDecoder allocates and returns a peer messages decoder.
+func NewDecoder(opts ...funcopt.O) *Decoder {
+	t := &Decoder{
+		counters: &Counters{},
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// PeerCodecs returns the codecs advertised by the peer in its last full
+// message.
+func (t *Decoder) PeerCodecs() []string {
+	return t.peerCodecs
+}
+
+// Counters returns the counters updated by the decoder.
+func (t *Decoder) Counters() *Counters {
+	return t.counters
+}
+
+//
+// Decode decodes the message frame and returns the up to date peer
+// document. ErrResync is returned if the message is a patch not
+// applicable to the current document, in which case the document is
+// unusable until the next full message.
+//
+func (t *Decoder) Decode(frame []byte) ([]byte, error) {
+	if len(frame) == 0 {
+		return nil, fmt.Errorf("empty message")
+	}
+	codec, err := codecByID(frame[0])
+	if err != nil {
+		return nil, err
+	}
+	b, err := codec.Decompress(frame[1:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s decompress", codec.Name)
+	}
+	var msg Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return nil, err
+	}
+	t.counters.rx(msg.Kind, len(b), len(frame))
+	switch msg.Kind {
+	case KindFull:
+		t.seq = jsondelta.NewSequence(msg.Data, msg.Gen, true)
+		t.peerCodecs = msg.Codecs
+		return msg.Data, nil
+	case KindPatch:
+		return t.apply(msg)
+	default:
+		return nil, fmt.Errorf("unknown message kind %s", msg.Kind)
+	}
+}
+
+func (t *Decoder) apply(msg Message) ([]byte, error) {
+	if t.seq == nil {
+		t.counters.resync()
+		return nil, errors.Wrap(ErrResync, "patch received before the full document")
+	}
+	patch, err := jsondelta.DecodeRFC6902(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.seq.Apply(msg.Gen, patch); err != nil {
+		t.seq = nil
+		t.counters.resync()
+		return nil, errors.Wrap(ErrResync, err.Error())
+	}
+	return t.seq.Bytes(), nil
+}
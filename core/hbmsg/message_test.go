@@ -0,0 +1,111 @@
+package hbmsg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+func doc(i int) []byte {
+	return []byte(fmt.Sprintf(`{"monitor":{"status":"idle","updated":%d},"services":{"svc1":{"avail":"up"},"svc2":{"avail":"down"}},"labels":{"az":"fr1","rack":"r12"}}`, i))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	enc := NewEncoder(WithFullEvery(3))
+	dec := NewDecoder()
+	kinds := make([]string, 0)
+	for i := 0; i < 5; i++ {
+		frame, err := enc.Encode(doc(i))
+		require.NoError(t, err)
+		b, err := dec.Decode(frame)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(doc(i)), string(b))
+		if enc.sinceFull == 0 {
+			kinds = append(kinds, KindFull)
+		} else {
+			kinds = append(kinds, KindPatch)
+		}
+	}
+	assert.Equal(t, []string{KindFull, KindPatch, KindPatch, KindFull, KindPatch}, kinds)
+	assert.Equal(t, Supported(), dec.PeerCodecs())
+}
+
+func TestDecodeResync(t *testing.T) {
+	enc := NewEncoder()
+	dec := NewDecoder()
+
+	// the full document is lost
+	_, err := enc.Encode(doc(0))
+	require.NoError(t, err)
+	frame, err := enc.Encode(doc(1))
+	require.NoError(t, err)
+	_, err = dec.Decode(frame)
+	assert.True(t, errors.Is(err, ErrResync))
+
+	enc.Resync()
+	frame, err = enc.Encode(doc(2))
+	require.NoError(t, err)
+	_, err = dec.Decode(frame)
+	require.NoError(t, err)
+
+	// a patch is lost
+	_, err = enc.Encode(doc(3))
+	require.NoError(t, err)
+	frame, err = enc.Encode(doc(4))
+	require.NoError(t, err)
+	_, err = dec.Decode(frame)
+	assert.True(t, errors.Is(err, ErrResync))
+
+	// further patches are refused until the next full document
+	frame, err = enc.Encode(doc(5))
+	require.NoError(t, err)
+	_, err = dec.Decode(frame)
+	assert.True(t, errors.Is(err, ErrResync))
+
+	var s cluster.HeartbeatPeerStatus
+	dec.Counters().Fill(&s, time.Now())
+	assert.Equal(t, uint64(3), s.Resyncs)
+	assert.Equal(t, uint64(4), s.RxMsgs)
+	assert.Equal(t, uint64(1), s.RxFull)
+}
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, CodecNone, Negotiate(nil).Name)
+	assert.Equal(t, CodecNone, Negotiate([]string{"foo"}).Name)
+	assert.Equal(t, CodecGzip, Negotiate([]string{CodecNone, CodecGzip}).Name)
+	assert.Equal(t, CodecGzip, Negotiate([]string{CodecZstd, CodecGzip}).Name)
+	assert.NotContains(t, Supported(), CodecZstd)
+}
+
+func TestCompression(t *testing.T) {
+	big := []byte(fmt.Sprintf(`{"data":"%s"}`, strings.Repeat("a", 4096)))
+	enc := NewEncoder()
+	enc.SetPeerCodecs([]string{CodecGzip})
+	dec := NewDecoder()
+	frame, err := enc.Encode(big)
+	require.NoError(t, err)
+	assert.Less(t, len(frame), len(big))
+	b, err := dec.Decode(frame)
+	require.NoError(t, err)
+	assert.Equal(t, big, b)
+
+	now := time.Now()
+	var s cluster.HeartbeatPeerStatus
+	enc.Counters().Fill(&s, now)
+	_, err = enc.Encode(big)
+	require.NoError(t, err)
+	_, err = enc.Encode(big)
+	require.NoError(t, err)
+	enc.Counters().Fill(&s, now.Add(time.Second))
+	assert.Equal(t, CodecGzip, s.Codec)
+	assert.Equal(t, uint64(3), s.TxMsgs)
+	assert.Equal(t, 2.0, s.TxMsgRate)
+	assert.Less(t, s.TxBytes, s.TxRawBytes)
+}
@@ -0,0 +1,92 @@
+package hbrelay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+type (
+	//
+	// Client writes the local node payload in its relay slot, and reads
+	// the payloads of the peer nodes from their slots. It authenticates
+	// with the cluster id and the cluster relay secret.
+	//
+	Client struct {
+		// URL is the relay base url, like https://relay1:1217.
+		URL string
+
+		// Cluster is the cluster id.
+		Cluster string
+
+		// Secret is the cluster relay secret.
+		Secret string
+
+		// HTTPClient sends the requests. The default is
+		// http.DefaultClient.
+		HTTPClient *http.Client
+	}
+)
+
+var (
+	// ErrNoSlot is returned by Rx if the relay holds no valid slot for
+	// the node.
+	ErrNoSlot = errors.New("relay: no valid slot")
+)
+
+// Tx writes the payload msg in the slot of the node.
+func (t Client) Tx(ctx context.Context, node string, msg []byte) error {
+	b, err := json.Marshal(txRequest{Node: node, Msg: msg})
+	if err != nil {
+		return err
+	}
+	_, code, err := t.do(ctx, http.MethodPost, "/relay_tx", bytes.NewReader(b))
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("relay: tx %s: %s", node, http.StatusText(code))
+	}
+	return err
+}
+
+// Rx returns the slot of the node. ErrNoSlot is returned if the node
+// slot does not exist or is expired.
+func (t Client) Rx(ctx context.Context, node string) (Slot, error) {
+	var slot Slot
+	b, code, err := t.do(ctx, http.MethodGet, "/relay_rx?nodename="+url.QueryEscape(node), nil)
+	switch {
+	case err != nil:
+		return slot, err
+	case code == http.StatusNotFound:
+		return slot, ErrNoSlot
+	case code != http.StatusOK:
+		return slot, fmt.Errorf("relay: rx %s: %s", node, http.StatusText(code))
+	}
+	if err := json.Unmarshal(b, &slot); err != nil {
+		return slot, fmt.Errorf("relay: rx %s: %w", node, err)
+	}
+	return slot, nil
+}
+
+func (t Client) do(ctx context.Context, method, uri string, body io.Reader) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.URL+uri, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.SetBasicAuth(t.Cluster, t.Secret)
+	c := t.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	return b, resp.StatusCode, err
+}
@@ -0,0 +1,203 @@
+package hbrelay
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/apiv2"
+)
+
+type (
+	//
+	// Server serves the heartbeat slots to the relay clients:
+	//
+	//   POST /relay_tx      write the node payload in its slot
+	//   GET  /relay_rx      read the slot of ?nodename=<peer>
+	//   GET  /relay_status  list the nodes having a valid slot
+	//
+	// The clients authenticate with the http basic scheme, the cluster
+	// id as user and the cluster relay secret as password, and can only
+	// access the slots of their cluster.
+	//
+	// The requests of the v2 agents clients, posted to the root path, are
+	// translated by the apiv2 layer. Their slot option is the nodename.
+	//
+	Server struct {
+		// Addr is the listener address, like ":1217".
+		Addr string
+
+		// CertFile and KeyFile are the tls listener certificate and
+		// private key. Both are required, as the clients send their
+		// cluster secret in the basic auth header.
+		CertFile string
+		KeyFile  string
+
+		// Retention is the age of the slots expired and purged.
+		Retention time.Duration
+
+		// Secrets are the secrets of the clusters allowed to use the
+		// relay, indexed by cluster id.
+		Secrets map[string]string
+
+		store *Store
+	}
+
+	// txRequest is the body of the POST /relay_tx requests.
+	txRequest struct {
+		Node string `json:"nodename"`
+		Msg  []byte `json:"msg"`
+	}
+
+	// statusResponse is the body of the GET /relay_status responses.
+	statusResponse struct {
+		Nodes []string `json:"nodes"`
+	}
+)
+
+var (
+	// ErrNoTLS is returned by Run if the tls certificate or private key
+	// is not set.
+	ErrNoTLS = errors.New("relay: the cert and key are required, the clients secrets are not sent over a clear-text listener")
+
+	// MaxMsgSize is the maximum size of a relay_tx request body.
+	MaxMsgSize int64 = 1024 * 1024
+
+	// purgeInterval is the interval between two purges of the expired
+	// slots.
+	purgeInterval = 10 * time.Second
+
+	// v2Actions are the translations of the v2 relay actions.
+	v2Actions = apiv2.Actions{
+		"relay_tx": {
+			Method:  http.MethodPost,
+			Options: map[string]string{"slot": "nodename"},
+		},
+		"relay_rx": {
+			Method:  http.MethodGet,
+			Options: map[string]string{"slot": "nodename"},
+			Query:   []string{"nodename"},
+			Epoch:   []string{"updated"},
+		},
+		"relay_status": {Method: http.MethodGet},
+	}
+)
+
+// Store returns the slots store of the server, allocated on first use.
+func (t *Server) Store() *Store {
+	if t.store == nil {
+		t.store = NewStore(t.Retention)
+	}
+	return t.store
+}
+
+// Handler returns the http handler serving the relay requests.
+func (t *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/relay_tx", t.auth(http.MethodPost, t.handleTx))
+	mux.HandleFunc("/relay_rx", t.auth(http.MethodGet, t.handleRx))
+	mux.HandleFunc("/relay_status", t.auth(http.MethodGet, t.handleStatus))
+	return v2Actions.Handler(mux)
+}
+
+// auth wraps the handler with the method check and the cluster
+// authentication. The handler receives the authenticated cluster id.
+func (t *Server) auth(method string, fn func(http.ResponseWriter, *http.Request, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		cluster, secret, ok := r.BasicAuth()
+		expected, known := t.Secrets[cluster]
+		if !ok || !known || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+			log.Warn().Str("cluster", cluster).Str("from", r.RemoteAddr).Msg("relay: authentication failed")
+			w.Header().Set("WWW-Authenticate", `Basic realm="relay"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fn(w, r, cluster)
+	}
+}
+
+func (t *Server) handleTx(w http.ResponseWriter, r *http.Request, cluster string) {
+	var req txRequest
+	r.Body = http.MaxBytesReader(w, r.Body, MaxMsgSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Node == "" {
+		http.Error(w, "nodename is required", http.StatusBadRequest)
+		return
+	}
+	t.Store().Put(Slot{
+		Cluster: cluster,
+		Node:    req.Node,
+		Msg:     req.Msg,
+		Updated: time.Now(),
+	})
+	writeJSON(w, struct{}{})
+}
+
+func (t *Server) handleRx(w http.ResponseWriter, r *http.Request, cluster string) {
+	node := r.URL.Query().Get("nodename")
+	slot, ok := t.Store().Get(cluster, node, time.Now())
+	if !ok {
+		http.Error(w, "no valid slot for node "+node, http.StatusNotFound)
+		return
+	}
+	writeJSON(w, slot)
+}
+
+func (t *Server) handleStatus(w http.ResponseWriter, r *http.Request, cluster string) {
+	writeJSON(w, statusResponse{Nodes: t.Store().Nodes(cluster, time.Now())})
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Debug().Err(err).Msg("relay: write response")
+	}
+}
+
+//
+// Run serves the relay requests over tls and purges the expired slots
+// until the context is done or the listener fails. It returns ErrNoTLS
+// if CertFile or KeyFile is not set.
+//
+func (t *Server) Run(ctx context.Context) error {
+	if t.CertFile == "" || t.KeyFile == "" {
+		return ErrNoTLS
+	}
+	srv := &http.Server{
+		Addr:    t.Addr,
+		Handler: t.Handler(),
+	}
+	errC := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("relay tls listener on %s", t.Addr)
+		errC <- srv.ListenAndServeTLS(t.CertFile, t.KeyFile)
+	}()
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		case err := <-errC:
+			return err
+		case now := <-ticker.C:
+			if n := t.Store().Purge(now); n > 0 {
+				log.Info().Int("slots", n).Msg("relay: purge expired slots")
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package hbrelay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	srv := &Server{
+		Retention: time.Minute,
+		Secrets: map[string]string{
+			"c1": "s1",
+			"c2": "s2",
+		},
+	}
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	do := func(method, uri, cluster, secret string, body interface{}) *http.Response {
+		var buff bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buff).Encode(body))
+		}
+		req, err := http.NewRequest(method, ts.URL+uri, &buff)
+		require.NoError(t, err)
+		req.SetBasicAuth(cluster, secret)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("refuse a bad secret", func(t *testing.T) {
+		resp := do("POST", "/relay_tx", "c1", "s2", txRequest{Node: "n1", Msg: []byte("x")})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("refuse an unknown cluster", func(t *testing.T) {
+		resp := do("GET", "/relay_rx?nodename=n1", "c3", "s3", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("write and read a slot", func(t *testing.T) {
+		resp := do("POST", "/relay_tx", "c1", "s1", txRequest{Node: "n1", Msg: []byte{0, 1, 2}})
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = do("GET", "/relay_rx?nodename=n1", "c1", "s1", nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var slot Slot
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&slot))
+		assert.Equal(t, "c1", slot.Cluster)
+		assert.Equal(t, []byte{0, 1, 2}, slot.Msg)
+
+		resp = do("GET", "/relay_status", "c1", "s1", nil)
+		defer resp.Body.Close()
+		var status statusResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		assert.Equal(t, []string{"n1"}, status.Nodes)
+	})
+
+	t.Run("translate the v2 requests", func(t *testing.T) {
+		resp := do("POST", "/", "c1", "s1", map[string]interface{}{
+			"action":  "relay_tx",
+			"options": map[string]interface{}{"slot": "n2", "msg": []byte("v2")},
+		})
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = do("POST", "/", "c1", "s1", map[string]interface{}{
+			"action":  "relay_rx",
+			"options": map[string]interface{}{"slot": "n2"},
+		})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var v2 struct {
+			Status int `json:"status"`
+			Data   struct {
+				Msg     []byte  `json:"msg"`
+				Updated float64 `json:"updated"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&v2))
+		assert.Equal(t, 0, v2.Status)
+		assert.Equal(t, []byte("v2"), v2.Data.Msg)
+		assert.InDelta(t, float64(time.Now().Unix()), v2.Data.Updated, 60, "the v2 dates are epoch floats")
+	})
+
+	t.Run("isolate the clusters slots", func(t *testing.T) {
+		resp := do("GET", "/relay_rx?nodename=n1", "c2", "s2", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("refuse an oversized payload", func(t *testing.T) {
+		saved := MaxMsgSize
+		MaxMsgSize = 16
+		defer func() { MaxMsgSize = saved }()
+		resp := do("POST", "/relay_tx", "c1", "s1", txRequest{Node: "n1", Msg: make([]byte, 64)})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestServerRequiresTLS(t *testing.T) {
+	srv := &Server{
+		Addr:    "127.0.0.1:0",
+		Secrets: map[string]string{"c1": "s1"},
+	}
+	assert.Equal(t, ErrNoTLS, srv.Run(context.Background()))
+}
+
+func TestStoreExpiry(t *testing.T) {
+	now := time.Now()
+	store := NewStore(time.Minute)
+	store.Put(Slot{Cluster: "c1", Node: "n1", Updated: now.Add(-2 * time.Minute)})
+	store.Put(Slot{Cluster: "c1", Node: "n2", Updated: now})
+	store.Put(Slot{Cluster: "c2", Node: "n1", Updated: now.Add(-2 * time.Minute)})
+
+	_, ok := store.Get("c1", "n1", now)
+	assert.False(t, ok, "stale slot")
+	_, ok = store.Get("c1", "n2", now)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"n2"}, store.Nodes("c1", now))
+
+	assert.Equal(t, 2, store.Purge(now))
+	assert.Len(t, store.slots, 1, "the clusters without slots are dropped")
+}
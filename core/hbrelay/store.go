@@ -0,0 +1,109 @@
+//
+// Package hbrelay implements the server side of the relay heartbeat.
+//
+// The relay is a node hosting the heartbeat payloads of the nodes of
+// third-party clusters unable to reach each other directly, like the
+// nodes of a stretched cluster without a common network. Each node
+// writes its payload in its slot, and reads the payloads of its peers
+// from their slots. The slots are indexed by cluster id and node name,
+// and each cluster authenticates with its own secret.
+//
+// The slots not updated during the retention period are expired, so
+// a peer stopped sending is detected as stale by the readers.
+//
+package hbrelay
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Slot is the last heartbeat payload sent by a node.
+	Slot struct {
+		Cluster string    `json:"cluster_id"`
+		Node    string    `json:"nodename"`
+		Msg     []byte    `json:"msg"`
+		Updated time.Time `json:"updated"`
+	}
+
+	// Store is the in-memory table of the heartbeat slots.
+	Store struct {
+		mu        sync.RWMutex
+		slots     map[string]map[string]Slot
+		retention time.Duration
+	}
+)
+
+// NewStore allocates and returns a slots store expiring the slots not
+// updated since retention.
+func NewStore(retention time.Duration) *Store {
+	return &Store{
+		slots:     make(map[string]map[string]Slot),
+		retention: retention,
+	}
+}
+
+// Put stores the slot, replacing the previous payload of the node.
+func (t *Store) Put(slot Slot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m, ok := t.slots[slot.Cluster]
+	if !ok {
+		m = make(map[string]Slot)
+		t.slots[slot.Cluster] = m
+	}
+	m[slot.Node] = slot
+}
+
+// Get returns the slot of the cluster node, and false if the node never
+// sent a payload or if its slot is expired.
+func (t *Store) Get(cluster, node string, now time.Time) (Slot, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	slot, ok := t.slots[cluster][node]
+	if !ok || t.isExpired(slot, now) {
+		return Slot{}, false
+	}
+	return slot, true
+}
+
+// Nodes returns the sorted names of the nodes of the cluster having a
+// valid slot.
+func (t *Store) Nodes(cluster string, now time.Time) []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	l := make([]string, 0)
+	for node, slot := range t.slots[cluster] {
+		if !t.isExpired(slot, now) {
+			l = append(l, node)
+		}
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Purge drops the expired slots, and the clusters left without slots.
+// It returns the number of dropped slots.
+func (t *Store) Purge(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for cluster, m := range t.slots {
+		for node, slot := range m {
+			if t.isExpired(slot, now) {
+				delete(m, node)
+				n++
+			}
+		}
+		if len(m) == 0 {
+			delete(t.slots, cluster)
+		}
+	}
+	return n
+}
+
+func (t *Store) isExpired(slot Slot, now time.Time) bool {
+	return t.retention > 0 && now.Sub(slot.Updated) > t.retention
+}
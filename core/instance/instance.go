@@ -68,6 +68,7 @@ type (
 		FlexMax     int                               `json:"flex_max,omitempty"`
 		Subsets     map[string]SubsetStatus           `json:"subsets,omitempty"`
 		Resources   map[string]resource.ExposedStatus `json:"resources,omitempty"`
+		Encap       map[string]Status                 `json:"encap,omitempty"`
 		Running     ResourceRunningSet                `json:"running,omitempty"`
 		Parents     []path.Relation                   `json:"parents,omitempty"`
 		Children    []path.Relation                   `json:"children,omitempty"`
@@ -155,7 +156,7 @@ func (a ResourceOrder) Less(i, j int) bool {
 //   D  Disabled
 //   O  Optional
 //   E  Encap
-//   P  Provisioned
+//   P  Not provisioned, or p if partially provisioned
 //   S  Standby
 //
 func (t Status) ResourceFlagsString(rid resourceid.T, r resource.ExposedStatus) string {
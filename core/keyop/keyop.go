@@ -40,6 +40,8 @@ const (
 	Toggle
 	// Insert adds an element at the position specified by Index
 	Insert
+	// Pop removes the element at the position specified by Index
+	Pop
 )
 
 var (
@@ -50,6 +52,7 @@ var (
 		Remove: "-=",
 		Merge:  "|=",
 		Toggle: "^=",
+		Pop:    "-=",
 	}
 
 	toID = map[string]Op{
@@ -127,6 +130,8 @@ func Parse(s string) *T {
 		switch t.Op {
 		case Set:
 			t.Op = Insert
+		case Remove:
+			t.Op = Pop
 		default:
 			// invalid
 			return &T{}
@@ -138,8 +143,8 @@ func Parse(s string) *T {
 
 func (t T) String() string {
 	switch t.Op {
-	case Insert:
-		return fmt.Sprintf("%s[%d]=%s", t.Key, t.Index, t.Value)
+	case Insert, Pop:
+		return fmt.Sprintf("%s[%d]%s%s", t.Key, t.Index, t.Op, t.Value)
 	default:
 		return fmt.Sprintf("%s%s%s", t.Key, t.Op, t.Value)
 	}
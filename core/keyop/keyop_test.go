@@ -59,6 +59,19 @@ func TestKeyopParse(t *testing.T) {
 			val:   "b",
 			index: 2,
 		},
+		{
+			expr:  "fs#1.devs[1]-=",
+			key:   key.T{Section: "fs#1", Option: "devs"},
+			op:    Pop,
+			val:   "",
+			index: 1,
+		},
+		{
+			expr: "fs#1.devs[1]|=a",
+			key:  key.T{},
+			op:   Invalid,
+			val:  "",
+		},
 		{
 			expr:  "fs.optional=false",
 			key:   key.T{Section: "fs", Option: "optional"},
@@ -79,7 +92,7 @@ func TestKeyopParse(t *testing.T) {
 			t.Run("test value is correct", func(t *testing.T) {
 				assert.Equal(t, test.val, op.Value)
 			})
-			if op.Op == Insert {
+			if op.Op == Insert || op.Op == Pop {
 				t.Run("test index is correct", func(t *testing.T) {
 					assert.Equal(t, test.index, op.Index)
 				})
@@ -87,3 +100,9 @@ func TestKeyopParse(t *testing.T) {
 		})
 	}
 }
+
+func TestKeyopString(t *testing.T) {
+	for _, s := range []string{"a.b=c", "a.b+=c", "a.b-=c", "a.b|=c", "a.b^=c", "a.b[2]=c", "a.b[0]-="} {
+		assert.Equal(t, s, Parse(s).String())
+	}
+}
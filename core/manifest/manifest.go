@@ -67,13 +67,24 @@ type (
 	runner interface {
 		Run(context.Context) error
 	}
-	syncer interface {
-		Sync(context.Context) error
+	syncFuller interface {
+		SyncFull(context.Context) error
+	}
+	syncUpdater interface {
+		SyncUpdate(context.Context) error
 	}
 )
 
 func (t *T) AddInterfacesKeywords(r interface{}) *T {
 	if _, ok := r.(starter); ok {
+		t.AddKeyword(keywords.Keyword{
+			Option:    "start_timeout",
+			Attr:      "StartTimeout",
+			Converter: converters.Duration,
+			Scopable:  true,
+			Example:   "1m30s",
+			Text:      "Cancel the resource 'start' action after <duration>. The action failure is attributed to the resource, and does not break the object action if the resource is optional. If not set, the resource action is only limited by the object action timeout.",
+		})
 		t.AddKeyword(keywords.Keyword{
 			Option:  "start_requires",
 			Attr:    "StartRequires",
@@ -82,6 +93,14 @@ func (t *T) AddInterfacesKeywords(r interface{}) *T {
 		})
 	}
 	if _, ok := r.(stopper); ok {
+		t.AddKeyword(keywords.Keyword{
+			Option:    "stop_timeout",
+			Attr:      "StopTimeout",
+			Converter: converters.Duration,
+			Scopable:  true,
+			Example:   "1m30s",
+			Text:      "Cancel the resource 'stop' action after <duration>. The action failure is attributed to the resource, and does not break the object action if the resource is optional. If not set, the resource action is only limited by the object action timeout.",
+		})
 		t.AddKeyword(keywords.Keyword{
 			Option:  "stop_requires",
 			Attr:    "StopRequires",
@@ -105,7 +124,9 @@ func (t *T) AddInterfacesKeywords(r interface{}) *T {
 			Text:    "A whitespace-separated list of conditions to meet to accept doing a 'unprovision' action. A condition is expressed as ``<rid>(<state>,...)``. If states are omitted, ``up,stdby up`` is used as the default expected states.",
 		})
 	}
-	if _, ok := r.(syncer); ok {
+	_, isSyncFuller := r.(syncFuller)
+	_, isSyncUpdater := r.(syncUpdater)
+	if isSyncFuller || isSyncUpdater {
 		t.AddKeyword(keywords.Keyword{
 			Option:  "sync_requires",
 			Attr:    "SyncRequires",
@@ -150,6 +171,19 @@ var genericKeywords = []keywords.Keyword{
 		Converter: converters.Bool,
 		Text:      "Set to ``true`` to skip the resource on provision and unprovision actions if the action has already been done by a peer. Shared resources, like vg built on SAN disks must be provisioned once. All resources depending on a shared resource must also be flagged as shared.",
 	},
+	{
+		Option:    "encap",
+		Attr:      "Encap",
+		Converter: converters.Bool,
+		Text:      "Set to ``true`` to ignore this resource in the nodes context and consider it in the encapnodes context. The resource is thus handled by the agents deployed in the service containers.",
+	},
+	{
+		Option:    "provision",
+		Attr:      "EnableProvision",
+		Default:   "true",
+		Converter: converters.Bool,
+		Text:      "Set to ``false`` to skip the resource on provision and unprovision actions. Warning: Provision implies destructive operations like formating. Unprovision destroys service data.",
+	},
 	{
 		Option:    "standby",
 		Attr:      "Standby",
@@ -230,11 +264,31 @@ func New(group drivergroup.T, name string, r interface{}) *T {
 	return t
 }
 
+//
+// AddKeyword adds keywords to the manifest. A keyword already defined,
+// like a generic keyword, is replaced by the new definition, so the
+// drivers can override the generic keywords.
+//
 func (t *T) AddKeyword(kws ...keywords.Keyword) *T {
-	t.Keywords = append(t.Keywords, kws...)
+	for _, kw := range kws {
+		if i := t.keywordIndex(kw); i >= 0 {
+			t.Keywords[i] = kw
+			continue
+		}
+		t.Keywords = append(t.Keywords, kw)
+	}
 	return t
 }
 
+func (t *T) keywordIndex(kw keywords.Keyword) int {
+	for i, e := range t.Keywords {
+		if e.Section == kw.Section && e.Option == kw.Option {
+			return i
+		}
+	}
+	return -1
+}
+
 func (t *T) AddContext(ctx ...Context) *T {
 	t.Context = append(t.Context, ctx...)
 	return t
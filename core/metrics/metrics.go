@@ -0,0 +1,255 @@
+// Package metrics exposes the cluster status as Prometheus metrics, in
+// the text exposition format.
+//
+// The exposed metrics are:
+//
+//	opensvc_object_avail{path,status}               1 for the object current availability status
+//	opensvc_object_overall{path,status}             1 for the object current overall status
+//	opensvc_instance_avail{path,node,status}        1 for the instance current availability status
+//	opensvc_resource_status{path,node,rid,status}   1 for the resource current status
+//	opensvc_node_score{node}                        the node placement score
+//	opensvc_node_load15m{node}                      the node 15 minutes load average
+//	opensvc_node_mem_avail_percent{node}            the node available memory percent
+//	opensvc_node_mem_total_bytes{node}              the node total memory
+//	opensvc_node_swap_avail_percent{node}           the node available swap percent
+//	opensvc_node_swap_total_bytes{node}             the node total swap
+//	opensvc_node_frozen{node}                       1 if the node is frozen
+//	opensvc_scheduler_delayed_tasks                 the number of tasks queued by the scheduler
+//	opensvc_scheduler_lag_seconds                   the age of the oldest queued task
+//	opensvc_heartbeat_peer_beating{hb,peer}         1 if the peer is beating on the heartbeat
+//	opensvc_heartbeat_peer_stale_seconds{hb,peer}   the time since the peer last beat on the heartbeat
+//	opensvc_driver_action_duration_seconds{driver,action}
+//	                                                the local node resource driver actions duration histogram
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+)
+
+type (
+	// Label is a metric sample dimension.
+	Label struct {
+		Name  string
+		Value string
+	}
+
+	// Sample is a metric value and its labels.
+	Sample struct {
+		Labels []Label
+		Value  float64
+	}
+
+	// Family is a set of samples sharing a metric name.
+	Family struct {
+		Name    string
+		Help    string
+		Type    string
+		Samples []Sample
+	}
+)
+
+const (
+	// Gauge is the type of the metrics whose value can go up and down.
+	Gauge = "gauge"
+
+	// Counter is the type of the metrics whose value only goes up.
+	Counter = "counter"
+
+	mb = 1024 * 1024
+)
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func newGauge(name, help string) *Family {
+	return &Family{Name: name, Help: help, Type: Gauge}
+}
+
+// Add appends a sample to the family. The labels are passed as name, value
+// pairs.
+func (t *Family) Add(value float64, labels ...string) {
+	l := make([]Label, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		l = append(l, Label{Name: labels[i], Value: labels[i+1]})
+	}
+	t.Samples = append(t.Samples, Sample{Labels: l, Value: value})
+}
+
+// WriteTo writes the family in the Prometheus text exposition format.
+func (t Family) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", t.Name, helpEscaper.Replace(t.Help))
+	fmt.Fprintf(&b, "# TYPE %s %s\n", t.Name, t.Type)
+	for _, s := range t.Samples {
+		b.WriteString(t.Name)
+		if len(s.Labels) > 0 {
+			l := make([]string, len(s.Labels))
+			for i, label := range s.Labels {
+				l[i] = fmt.Sprintf(`%s="%s"`, label.Name, labelValueEscaper.Replace(label.Value))
+			}
+			b.WriteString("{" + strings.Join(l, ",") + "}")
+		}
+		b.WriteString(" " + strconv.FormatFloat(s.Value, 'g', -1, 64) + "\n")
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Write writes the families in the Prometheus text exposition format.
+func Write(w io.Writer, l []Family) error {
+	for _, f := range l {
+		if _, err := f.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect returns the metric families extracted from the cluster status.
+// The durations are computed relative to now.
+func Collect(data cluster.Status, now time.Time) []Family {
+	l := make([]Family, 0)
+	l = append(l, collectObjects(data)...)
+	l = append(l, collectNodes(data)...)
+	l = append(l, collectScheduler(data, now)...)
+	l = append(l, collectHeartbeats(data, now)...)
+	return l
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func boolValue(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+func collectObjects(data cluster.Status) []Family {
+	objectAvail := newGauge("opensvc_object_avail", "The object availability status.")
+	objectOverall := newGauge("opensvc_object_overall", "The object overall status.")
+	instanceAvail := newGauge("opensvc_instance_avail", "The instance availability status.")
+	resourceStatus := newGauge("opensvc_resource_status", "The resource status.")
+
+	paths := make(map[string]interface{})
+	for p := range data.Monitor.Services {
+		paths[p] = nil
+	}
+	for _, p := range sortedKeys(paths) {
+		d := data.Monitor.Services[p]
+		objectAvail.Add(1, "path", p, "status", d.Avail.String())
+		objectOverall.Add(1, "path", p, "status", d.Overall.String())
+	}
+
+	nodes := make(map[string]interface{})
+	for n := range data.Monitor.Nodes {
+		nodes[n] = nil
+	}
+	for _, n := range sortedKeys(nodes) {
+		instances := data.Monitor.Nodes[n].Services.Status
+		paths := make(map[string]interface{})
+		for p := range instances {
+			paths[p] = nil
+		}
+		for _, p := range sortedKeys(paths) {
+			d := instances[p]
+			instanceAvail.Add(1, "path", p, "node", n, "status", d.Avail.String())
+			rids := make(map[string]interface{})
+			for rid := range d.Resources {
+				rids[rid] = nil
+			}
+			for _, rid := range sortedKeys(rids) {
+				resourceStatus.Add(1, "path", p, "node", n, "rid", rid, "status", d.Resources[rid].Status.String())
+			}
+		}
+	}
+	return []Family{*objectAvail, *objectOverall, *instanceAvail, *resourceStatus}
+}
+
+func collectNodes(data cluster.Status) []Family {
+	score := newGauge("opensvc_node_score", "The node placement score.")
+	load := newGauge("opensvc_node_load15m", "The node 15 minutes load average.")
+	memAvail := newGauge("opensvc_node_mem_avail_percent", "The node available memory percent.")
+	memTotal := newGauge("opensvc_node_mem_total_bytes", "The node total memory.")
+	swapAvail := newGauge("opensvc_node_swap_avail_percent", "The node available swap percent.")
+	swapTotal := newGauge("opensvc_node_swap_total_bytes", "The node total swap.")
+	frozen := newGauge("opensvc_node_frozen", "1 if the node is frozen.")
+
+	nodes := make(map[string]interface{})
+	for n := range data.Monitor.Nodes {
+		nodes[n] = nil
+	}
+	for _, n := range sortedKeys(nodes) {
+		d := data.Monitor.Nodes[n]
+		score.Add(float64(d.Stats.Score), "node", n)
+		load.Add(d.Stats.Load15M, "node", n)
+		memAvail.Add(float64(d.Stats.MemAvailPct), "node", n)
+		memTotal.Add(float64(d.Stats.MemTotalMB*mb), "node", n)
+		swapAvail.Add(float64(d.Stats.SwapAvailPct), "node", n)
+		swapTotal.Add(float64(d.Stats.SwapTotalMB*mb), "node", n)
+		frozen.Add(boolValue(!d.Frozen.IsZero() && !d.Frozen.Time().IsZero()), "node", n)
+	}
+	return []Family{*score, *load, *memAvail, *memTotal, *swapAvail, *swapTotal, *frozen}
+}
+
+func collectScheduler(data cluster.Status, now time.Time) []Family {
+	delayed := newGauge("opensvc_scheduler_delayed_tasks", "The number of tasks queued by the scheduler.")
+	lag := newGauge("opensvc_scheduler_lag_seconds", "The age of the oldest task queued by the scheduler.")
+	var oldest time.Time
+	for _, e := range data.Scheduler.Delayed {
+		queued := e.Queued.Time()
+		if queued.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || queued.Before(oldest) {
+			oldest = queued
+		}
+	}
+	delayed.Add(float64(len(data.Scheduler.Delayed)))
+	if oldest.IsZero() {
+		lag.Add(0)
+	} else {
+		lag.Add(now.Sub(oldest).Seconds())
+	}
+	return []Family{*delayed, *lag}
+}
+
+func collectHeartbeats(data cluster.Status, now time.Time) []Family {
+	beating := newGauge("opensvc_heartbeat_peer_beating", "1 if the peer is beating on the heartbeat.")
+	stale := newGauge("opensvc_heartbeat_peer_stale_seconds", "The time since the peer last beat on the heartbeat.")
+	hbs := make(map[string]interface{})
+	for name := range data.Heartbeats {
+		hbs[name] = nil
+	}
+	for _, name := range sortedKeys(hbs) {
+		peers := data.Heartbeats[name].Peers
+		names := make(map[string]interface{})
+		for peer := range peers {
+			names[peer] = nil
+		}
+		for _, peer := range sortedKeys(names) {
+			d := peers[peer]
+			beating.Add(boolValue(d.Beating), "hb", name, "peer", peer)
+			if last := d.Last.Time(); !last.IsZero() && !d.Last.IsZero() {
+				stale.Add(now.Sub(last).Seconds(), "hb", name, "peer", peer)
+			}
+		}
+	}
+	return []Family{*beating, *stale}
+}
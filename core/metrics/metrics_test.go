@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/driverstats"
+)
+
+var statusJSON = `{
+	"monitor": {
+		"services": {"svc1": {"avail": "up", "overall": "warn"}},
+		"nodes": {
+			"n1": {
+				"frozen": 1600000000,
+				"stats": {"score": 90, "load_15m": 0.5, "mem_avail": 60, "mem_total": 2, "swap_avail": 100, "swap_total": 0},
+				"services": {"status": {"svc1": {"avail": "up", "resources": {"fs#1": {"status": "up"}}}}}
+			}
+		}
+	},
+	"scheduler": {"delayed": [{"action": "status", "queued": 1600000090}, {"action": "push", "queued": 1600000060}]},
+	"hb#1.rx": {"peers": {"n2": {"beating": true, "last": 1600000095}}}
+}`
+
+type mockGetter struct {
+	b []byte
+}
+
+func (t mockGetter) Get() ([]byte, error) {
+	return t.b, nil
+}
+
+func TestCollect(t *testing.T) {
+	var data cluster.Status
+	require.NoError(t, json.Unmarshal([]byte(statusJSON), &data))
+	var b strings.Builder
+	require.NoError(t, Write(&b, Collect(data, time.Unix(1600000100, 0))))
+	s := b.String()
+	for _, line := range []string{
+		"# TYPE opensvc_object_avail gauge",
+		`opensvc_object_avail{path="svc1",status="up"} 1`,
+		`opensvc_object_overall{path="svc1",status="warn"} 1`,
+		`opensvc_instance_avail{path="svc1",node="n1",status="up"} 1`,
+		`opensvc_resource_status{path="svc1",node="n1",rid="fs#1",status="up"} 1`,
+		`opensvc_node_score{node="n1"} 90`,
+		`opensvc_node_load15m{node="n1"} 0.5`,
+		`opensvc_node_mem_total_bytes{node="n1"} 2.097152e+06`,
+		`opensvc_node_frozen{node="n1"} 1`,
+		`opensvc_scheduler_delayed_tasks 2`,
+		`opensvc_scheduler_lag_seconds 40`,
+		`opensvc_heartbeat_peer_beating{hb="hb#1.rx",peer="n2"} 1`,
+		`opensvc_heartbeat_peer_stale_seconds{hb="hb#1.rx",peer="n2"} 5`,
+	} {
+		assert.Contains(t, s, line+"\n")
+	}
+}
+
+func TestFamilyEscape(t *testing.T) {
+	f := newGauge("m", "a\nb")
+	f.Add(1, "l", `x"y\z`)
+	var b strings.Builder
+	_, err := f.WriteTo(&b)
+	require.NoError(t, err)
+	assert.Equal(t, "# HELP m a\\nb\n# TYPE m gauge\nm{l=\"x\\\"y\\\\z\"} 1\n", b.String())
+}
+
+func TestHandler(t *testing.T) {
+	defer func(f func() (driverstats.T, error)) { loadDriverStats = f }(loadDriverStats)
+	loadDriverStats = func() (driverstats.T, error) {
+		data := make(driverstats.T)
+		data.Observe("ip.host", "start", 20*time.Millisecond)
+		return data, nil
+	}
+	w := httptest.NewRecorder()
+	Handler(mockGetter{b: []byte(statusJSON)}).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `opensvc_object_avail{path="svc1",status="up"} 1`)
+	assert.Contains(t, w.Body.String(), `opensvc_driver_action_duration_seconds_count{driver="ip.host",action="start"} 1`)
+
+	w = httptest.NewRecorder()
+	Handler(mockGetter{b: []byte("{")}).ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 503, w.Code)
+}
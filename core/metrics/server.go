@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/apiv2"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/driverstats"
+)
+
+type (
+	// Getter fetches the cluster status json document.
+	Getter interface {
+		Get() ([]byte, error)
+	}
+
+	// Server is a Prometheus exporter serving the metrics collected from
+	// the cluster status on /metrics, and to the v2 agents clients
+	// "metrics" action, translated by the apiv2 layer.
+	Server struct {
+		// Addr is the listener address, like ":9100".
+		Addr string
+
+		// CertFile and KeyFile, if both set, enable the tls listener.
+		CertFile string
+		KeyFile  string
+
+		Getter Getter
+	}
+)
+
+// ContentType is the Prometheus text exposition format content type.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// v2Actions are the translations of the v2 actions served.
+var v2Actions = apiv2.Actions{
+	"metrics": {Method: http.MethodGet, Raw: true},
+}
+
+// loadDriverStats returns the local node resource driver action stats.
+var loadDriverStats = driverstats.Load
+
+// Get fetches the cluster status and returns the metrics in the text
+// exposition format, followed by the local node resource driver action
+// duration histograms.
+func Get(getter Getter) ([]byte, error) {
+	b, err := getter.Get()
+	if err != nil {
+		return nil, err
+	}
+	var data cluster.Status
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	var buff bytes.Buffer
+	if err := Write(&buff, Collect(data, time.Now())); err != nil {
+		return nil, err
+	}
+	drivers, err := loadDriverStats()
+	if err != nil {
+		// the cluster status metrics are still worth exposing
+		log.Warn().Err(err).Msg("load driver stats")
+		return buff.Bytes(), nil
+	}
+	if err := drivers.WritePrometheus(&buff); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// Handler returns the http handler serving the metrics, fetched from the
+// getter on each scrape.
+func Handler(getter Getter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := Get(getter)
+		if err != nil {
+			log.Error().Err(err).Msg("collect metrics")
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = w.Write(b)
+	})
+}
+
+// ListenAndServe serves the metrics until the listener fails.
+func (t Server) ListenAndServe() error {
+	return t.serve(t.newServer())
+}
+
+// Run serves the metrics until the context is done or the listener
+// fails.
+func (t Server) Run(ctx context.Context) error {
+	srv := t.newServer()
+	errC := make(chan error, 1)
+	go func() {
+		errC <- t.serve(srv)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errC:
+		return err
+	}
+}
+
+func (t Server) newServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(t.Getter))
+	return &http.Server{
+		Addr:    t.Addr,
+		Handler: v2Actions.Handler(mux),
+	}
+}
+
+func (t Server) serve(srv *http.Server) error {
+	if t.CertFile != "" && t.KeyFile != "" {
+		log.Info().Msgf("metrics tls listener on %s", t.Addr)
+		return srv.ListenAndServeTLS(t.CertFile, t.KeyFile)
+	}
+	log.Info().Msgf("metrics listener on %s", t.Addr)
+	return srv.ListenAndServe()
+}
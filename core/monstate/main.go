@@ -0,0 +1,204 @@
+// Package monstate persists the instance monitor states a daemon restart
+// must not lose: the local expects and the resource restart counters.
+//
+// The daemon api saves a state when it changes, and the daemon reloads
+// the states on startup. The global expects are not persisted here: they
+// are the orchestration intents recorded by the orchestjournal package,
+// which decides on startup which ones are resumed.
+//
+// The states are stored in a versioned document. Load migrates the
+// documents written with an older schema, and refuses the documents
+// written with a newer schema, so an agent downgrade does not silently
+// drop the states.
+package monstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opensvc/fcntllock"
+	"github.com/opensvc/flock"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/xsession"
+)
+
+type (
+	// InstanceState is the persisted part of an instance monitor.
+	InstanceState struct {
+		LocalExpect string         `json:"local_expect,omitempty"`
+		Restart     map[string]int `json:"restart,omitempty"`
+	}
+
+	// T is the monitor states document.
+	T struct {
+		Version   int                      `json:"version"`
+		Instances map[string]InstanceState `json:"instances"`
+	}
+
+	// migration converts a document from a schema version to the next.
+	migration func(map[string]json.RawMessage) (map[string]json.RawMessage, error)
+)
+
+// Version is the schema version of the documents written by this agent.
+const Version = 1
+
+var (
+	// ErrNewerVersion is returned when loading a document written with a
+	// schema newer than Version.
+	ErrNewerVersion = errors.New("monitor states schema is newer than supported")
+
+	// migrations are indexed by the schema version they convert from.
+	migrations = map[int]migration{
+		0: migrateV0,
+	}
+
+	lockTimeout = 5 * time.Second
+)
+
+// New allocates an empty document with the current schema version.
+func New() T {
+	return T{
+		Version:   Version,
+		Instances: make(map[string]InstanceState),
+	}
+}
+
+// NewInstanceState returns the persisted part of an instance monitor.
+func NewInstanceState(m instance.Monitor) InstanceState {
+	return InstanceState{
+		LocalExpect: m.LocalExpect,
+		Restart:     m.Restart,
+	}
+}
+
+// Apply restores the persisted state in the instance monitor.
+func (t InstanceState) Apply(m *instance.Monitor) {
+	m.LocalExpect = t.LocalExpect
+	m.Restart = t.Restart
+}
+
+// IsZero returns true if the state holds no local expect and no restart
+// counter, so it needs not be persisted.
+func (t InstanceState) IsZero() bool {
+	return t.LocalExpect == "" && len(t.Restart) == 0
+}
+
+// File returns the path of the node monitor states document.
+func File() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "monitor_states.json")
+}
+
+// Load returns the monitor states, migrated to the current schema.
+func Load() (T, error) {
+	b, err := ioutil.ReadFile(File())
+	switch {
+	case os.IsNotExist(err):
+		return New(), nil
+	case err != nil:
+		return New(), err
+	}
+	return decode(b)
+}
+
+// SetInstance saves the state of the instance monitor of the object
+// path. A zero state removes the instance from the document.
+func SetInstance(p string, s InstanceState) error {
+	return update(func(data *T) {
+		if s.IsZero() {
+			delete(data.Instances, p)
+			return
+		}
+		data.Instances[p] = s
+	})
+}
+
+// DelInstance removes the state of the instance monitor of the object
+// path, for example when the object is deleted.
+func DelInstance(p string) error {
+	return update(func(data *T) {
+		delete(data.Instances, p)
+	})
+}
+
+// decode parses a document, migrating it to the current schema version.
+func decode(b []byte) (T, error) {
+	data := New()
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return data, err
+	}
+	version := 0
+	if v, ok := raw["version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return data, fmt.Errorf("version: %w", err)
+		}
+	}
+	if version > Version {
+		return data, fmt.Errorf("%w: %d > %d", ErrNewerVersion, version, Version)
+	}
+	for ; version < Version; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return data, fmt.Errorf("no migration from schema version %d", version)
+		}
+		var err error
+		if raw, err = migrate(raw); err != nil {
+			return data, fmt.Errorf("migrate from schema version %d: %w", version, err)
+		}
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, err
+	}
+	if data.Instances == nil {
+		data.Instances = make(map[string]InstanceState)
+	}
+	data.Version = Version
+	return data, nil
+}
+
+// migrateV0 converts the unversioned documents, where the instance states
+// were stored under the "services" key.
+func migrateV0(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	if v, ok := raw["services"]; ok {
+		raw["instances"] = v
+		delete(raw, "services")
+	}
+	return raw, nil
+}
+
+// update applies fn to the document under the document lock.
+func update(fn func(*T)) error {
+	p := File()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	lock := flock.New(p+".lock", xsession.ID, fcntllock.New)
+	if err := lock.Lock(lockTimeout, "monitor states update"); err != nil {
+		return err
+	}
+	defer func() { _ = lock.UnLock() }()
+	data, err := Load()
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	fn(&data)
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
@@ -0,0 +1,66 @@
+package monstate
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monstate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	m := instance.Monitor{
+		GlobalExpect: "started",
+		LocalExpect:  "started",
+		Restart:      map[string]int{"app#1": 2},
+	}
+	require.NoError(t, SetInstance("ns1/svc/s1", NewInstanceState(m)))
+	require.NoError(t, SetInstance("ns1/svc/s2", InstanceState{LocalExpect: "started"}))
+	require.NoError(t, SetInstance("ns1/svc/s2", InstanceState{}))
+
+	data, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, Version, data.Version)
+	assert.Len(t, data.Instances, 1, "zero states are not persisted")
+
+	var restored instance.Monitor
+	data.Instances["ns1/svc/s1"].Apply(&restored)
+	assert.Equal(t, "", restored.GlobalExpect, "the global expects are journaled by orchestjournal")
+	assert.Equal(t, "started", restored.LocalExpect)
+	assert.Equal(t, 2, restored.Restart["app#1"])
+
+	require.NoError(t, DelInstance("ns1/svc/s1"))
+	data, err = Load()
+	require.NoError(t, err)
+	assert.Len(t, data.Instances, 0)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("migrates the unversioned documents", func(t *testing.T) {
+		data, err := decode([]byte(`{"services": {"ns1/svc/s1": {"local_expect": "started", "restart": {"app#1": 1}}}}`))
+		require.NoError(t, err)
+		assert.Equal(t, Version, data.Version)
+		assert.Equal(t, "started", data.Instances["ns1/svc/s1"].LocalExpect)
+		assert.Equal(t, 1, data.Instances["ns1/svc/s1"].Restart["app#1"])
+	})
+
+	t.Run("refuses the newer schema versions", func(t *testing.T) {
+		_, err := decode([]byte(`{"version": 99, "instances": {}}`))
+		assert.True(t, errors.Is(err, ErrNewerVersion))
+	})
+
+	t.Run("allocates the instances map", func(t *testing.T) {
+		data, err := decode([]byte(`{"version": 1}`))
+		require.NoError(t, err)
+		assert.NotNil(t, data.Instances)
+	})
+}
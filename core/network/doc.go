@@ -0,0 +1,10 @@
+// Package network provides the cluster backend networks definitions and
+// their address allocator.
+//
+// The networks are declared in network#<name> sections of the node or
+// cluster configuration. The ip resources configured with
+// provisioner=ipam allocate their address from the network named by
+// their network keyword, and the allocations are recorded as leases in
+// the node var directory.
+
+package network
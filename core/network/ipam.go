@@ -0,0 +1,123 @@
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type (
+	// Lease is an address allocated to an owner, usually a
+	// "<object path> <rid>" string.
+	Lease struct {
+		Network string `json:"network"`
+		IP      net.IP `json:"ip"`
+		Owner   string `json:"owner"`
+	}
+)
+
+//
+// leasesDir returns the directory hosting the network leases, one file
+// per allocated address, named after the address and containing the
+// owner.
+//
+func (t T) leasesDir() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "network", t.Name, "leases")
+}
+
+// Leases returns the addresses allocated from the network.
+func (t T) Leases() ([]Lease, error) {
+	l := make([]Lease, 0)
+	entries, err := ioutil.ReadDir(t.leasesDir())
+	switch {
+	case os.IsNotExist(err):
+		return l, nil
+	case err != nil:
+		return l, err
+	}
+	for _, e := range entries {
+		ip := net.ParseIP(e.Name())
+		if ip == nil {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(t.leasesDir(), e.Name()))
+		if err != nil {
+			continue
+		}
+		l = append(l, Lease{Network: t.Name, IP: ip, Owner: strings.TrimSpace(string(b))})
+	}
+	return l, nil
+}
+
+//
+// Allocate returns a free address of the allocation ranges, and records
+// its lease to the owner. The address already leased to the owner is
+// returned if any, so the allocation is idempotent. The gateway address
+// is never allocated.
+//
+// The lease file is created exclusively, so concurrent allocations can
+// not return the same address.
+//
+func (t T) Allocate(owner string) (net.IP, error) {
+	leases, err := t.Leases()
+	if err != nil {
+		return nil, err
+	}
+	for _, lease := range leases {
+		if lease.Owner == owner {
+			return lease.IP, nil
+		}
+	}
+	if err := os.MkdirAll(t.leasesDir(), 0755); err != nil {
+		return nil, err
+	}
+	for _, r := range t.AllocationRanges() {
+		for ip := r.First; compareIP(ip, r.Last) <= 0; ip = nextIP(ip) {
+			if t.Gateway != nil && ip.Equal(t.Gateway) {
+				continue
+			}
+			f, err := os.OpenFile(filepath.Join(t.leasesDir(), ip.String()), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			switch {
+			case os.IsExist(err):
+				continue
+			case err != nil:
+				return nil, err
+			}
+			_, err = f.WriteString(owner + "\n")
+			if errClose := f.Close(); err == nil {
+				err = errClose
+			}
+			if err != nil {
+				_ = os.Remove(f.Name())
+				return nil, err
+			}
+			return ip, nil
+		}
+	}
+	return nil, fmt.Errorf("network %s: no free address", t.Name)
+}
+
+// Release removes the leases of the owner, and returns the released
+// addresses.
+func (t T) Release(owner string) ([]net.IP, error) {
+	l := make([]net.IP, 0)
+	leases, err := t.Leases()
+	if err != nil {
+		return l, err
+	}
+	for _, lease := range leases {
+		if lease.Owner != owner {
+			continue
+		}
+		if err := os.Remove(filepath.Join(t.leasesDir(), lease.IP.String())); err != nil && !os.IsNotExist(err) {
+			return l, err
+		}
+		l = append(l, lease.IP)
+	}
+	return l, nil
+}
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestAllocate(t *testing.T) {
+	root, err := ioutil.TempDir("", "ipam")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/30")
+	n := T{Name: "n1", Network: ipnet, Gateway: net.ParseIP("10.0.0.1")}
+
+	ip, err := n.Allocate("s1 ip#1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", ip.String(), "the network and gateway addresses are skipped")
+
+	ip, err = n.Allocate("s1 ip#1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", ip.String(), "the allocation is idempotent")
+
+	_, err = n.Allocate("s2 ip#1")
+	assert.Error(t, err, "the broadcast address is not allocatable")
+
+	released, err := n.Release("s1 ip#1")
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	assert.Equal(t, "10.0.0.2", released[0].String())
+
+	ip, err = n.Allocate("s2 ip#1")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.2", ip.String())
+
+	leases, err := n.Leases()
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+	assert.Equal(t, "s2 ip#1", leases[0].Owner)
+}
+
+func TestAllocationRanges(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/24")
+	n := T{Name: "n1", Network: ipnet}
+	assert.Equal(t, "10.0.0.1-10.0.0.254", n.AllocationRanges()[0].String())
+
+	r, err := ParseRange("10.0.0.10-10.0.0.20")
+	require.NoError(t, err)
+	n.Ranges = []Range{r}
+	assert.Equal(t, []Range{r}, n.AllocationRanges())
+	assert.NoError(t, n.validate())
+
+	r, err = ParseRange("10.0.1.10-10.0.1.20")
+	require.NoError(t, err)
+	n.Ranges = []Range{r}
+	assert.Error(t, n.validate())
+
+	_, err = ParseRange("10.0.0.20-10.0.0.10")
+	assert.Error(t, err)
+	_, err = ParseRange("10.0.0.20")
+	assert.Error(t, err)
+}
+
+func TestUsage(t *testing.T) {
+	root, err := ioutil.TempDir("", "ipam")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+
+	_, ipnet, _ := net.ParseCIDR("10.0.0.0/24")
+	n := T{Name: "n1", Network: ipnet, Gateway: net.ParseIP("10.0.0.1")}
+	_, err = n.Allocate("s1 ip#1")
+	require.NoError(t, err)
+
+	usage, err := n.Usage()
+	require.NoError(t, err)
+	assert.Equal(t, StatusUsage{Size: 253, Used: 1, Free: 252}, usage, "the gateway is not allocatable")
+
+	_, ipnet, _ = net.ParseCIDR("fd00::/32")
+	n = T{Name: "n2", Network: ipnet}
+	usage, err = n.Usage()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), usage.Size)
+}
@@ -0,0 +1,210 @@
+package network
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// T is a network definition.
+	T struct {
+		Name    string     `json:"name"`
+		Type    string     `json:"type"`
+		Network *net.IPNet `json:"network"`
+		Gateway net.IP     `json:"gateway,omitempty"`
+		Ranges  []Range    `json:"ranges,omitempty"`
+
+		// routed_bridge
+		IPsPerNode int             `json:"ips_per_node,omitempty"`
+		Tables     []string        `json:"tables,omitempty"`
+		Tunnel     string          `json:"tunnel,omitempty"`
+		TunnelMode string          `json:"tunnel_mode,omitempty"`
+		Nodes      map[string]Node `json:"nodes,omitempty"`
+	}
+
+	// Range is an inclusive range of allocatable addresses.
+	Range struct {
+		First net.IP `json:"first"`
+		Last  net.IP `json:"last"`
+	}
+)
+
+func sectionName(name string) string {
+	return "network#" + name
+}
+
+// Names returns the names of the networks declared in the configuration.
+func Names(config *xconfig.T) []string {
+	l := make([]string, 0)
+	for _, s := range config.SectionStrings() {
+		if !strings.HasPrefix(s, "network#") {
+			continue
+		}
+		l = append(l, s[8:])
+	}
+	return l
+}
+
+//
+// New returns the definition of the network <name> declared in the
+// configuration. The keywords are evaluated in the local node scope, so
+// the configuration referrer must be the node.
+//
+func New(name string, config *xconfig.T) (*T, error) {
+	section := sectionName(name)
+	if !config.HasSectionString(section) {
+		return nil, fmt.Errorf("network %s is not declared in the configuration", name)
+	}
+	evalString := func(option string) (string, error) {
+		v, err := config.Eval(key.New(section, option))
+		if err != nil {
+			return "", err
+		}
+		s, _ := v.(string)
+		return s, nil
+	}
+	t := &T{Name: name}
+	var (
+		s   string
+		err error
+	)
+	if t.Type, err = evalString("type"); err != nil {
+		return nil, err
+	}
+	if s, err = evalString("network"); err != nil {
+		return nil, err
+	}
+	if _, t.Network, err = net.ParseCIDR(s); err != nil {
+		return nil, fmt.Errorf("network %s: %w", name, err)
+	}
+	if s, err = evalString("gateway"); err == nil && s != "" {
+		if t.Gateway = net.ParseIP(s); t.Gateway == nil {
+			return nil, fmt.Errorf("network %s: invalid gateway %s", name, s)
+		}
+	}
+	if v, err := config.Eval(key.New(section, "ranges")); err == nil {
+		l, _ := v.([]string)
+		for _, s := range l {
+			r, err := ParseRange(s)
+			if err != nil {
+				return nil, fmt.Errorf("network %s: %w", name, err)
+			}
+			t.Ranges = append(t.Ranges, r)
+		}
+	}
+	if t.Type == "routed_bridge" {
+		t.loadNodes(config)
+	}
+	return t, t.validate()
+}
+
+// ParseRange returns the range of a <first>-<last> addresses string.
+func ParseRange(s string) (Range, error) {
+	l := strings.SplitN(s, "-", 2)
+	if len(l) != 2 {
+		return Range{}, fmt.Errorf("invalid range %s: expected <first>-<last>", s)
+	}
+	r := Range{First: net.ParseIP(l[0]), Last: net.ParseIP(l[1])}
+	if r.First == nil || r.Last == nil {
+		return Range{}, fmt.Errorf("invalid range %s: invalid address", s)
+	}
+	if compareIP(r.First, r.Last) > 0 {
+		return Range{}, fmt.Errorf("invalid range %s: first address after last address", s)
+	}
+	return r, nil
+}
+
+func (t T) validate() error {
+	for _, r := range t.Ranges {
+		if !t.Network.Contains(r.First) || !t.Network.Contains(r.Last) {
+			return fmt.Errorf("network %s: range %s-%s is not in %s", t.Name, r.First, r.Last, t.Network)
+		}
+	}
+	return nil
+}
+
+// String returns the <first>-<last> representation of the range.
+func (t Range) String() string {
+	return t.First.String() + "-" + t.Last.String()
+}
+
+//
+// AllocationRanges returns the ranges the addresses are allocated from:
+// the configured ranges, or the whole network except its first and last
+// addresses.
+//
+func (t T) AllocationRanges() []Range {
+	if len(t.Ranges) > 0 {
+		return t.Ranges
+	}
+	first := nextIP(t.Network.IP.Mask(t.Network.Mask))
+	last := prevIP(lastIP(t.Network))
+	if compareIP(first, last) > 0 {
+		return []Range{}
+	}
+	return []Range{{First: first, Last: last}}
+}
+
+// lastIP returns the last address of the network, the broadcast address
+// for ipv4.
+func lastIP(n *net.IPNet) net.IP {
+	ip := n.IP.Mask(n.Mask)
+	last := make(net.IP, len(ip))
+	for i := range ip {
+		last[i] = ip[i] | ^n.Mask[i]
+	}
+	return last
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func prevIP(ip net.IP) net.IP {
+	prev := make(net.IP, len(ip))
+	copy(prev, ip)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			break
+		}
+	}
+	return prev
+}
+
+func compareIP(a, b net.IP) int {
+	if a4, b4 := a.To4(), b.To4(); a4 != nil && b4 != nil {
+		a, b = a4, b4
+	} else {
+		a, b = a.To16(), b.To16()
+	}
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func ipInt(ip net.IP) *big.Int {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return new(big.Int).SetBytes(ip)
+}
@@ -0,0 +1,235 @@
+package network
+
+import (
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net"
+	"sort"
+
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// Node is the network setup of a cluster node: its subnet of the
+	// network and its underlay address, used as the routes gateway or the
+	// tunnels endpoint.
+	Node struct {
+		Addr   net.IP     `json:"addr,omitempty"`
+		Subnet *net.IPNet `json:"subnet,omitempty"`
+		Error  string     `json:"error,omitempty"`
+	}
+
+	// Tunnel is a tunnel interface to a peer node.
+	Tunnel struct {
+		Name   string `json:"name"`
+		Mode   string `json:"mode"`
+		Local  net.IP `json:"local"`
+		Remote net.IP `json:"remote"`
+		VNI    int    `json:"vni,omitempty"`
+	}
+
+	//
+	// Route is a route to the subnet of a peer node, either via the peer
+	// underlay address, or via a tunnel interface. The vxlan routes are
+	// via the peer subnet gateway, through the vxlan interface.
+	//
+	Route struct {
+		Nodename string     `json:"nodename"`
+		Dst      *net.IPNet `json:"dst"`
+		Gateway  net.IP     `json:"gateway,omitempty"`
+		Dev      string     `json:"dev,omitempty"`
+		Table    string     `json:"table"`
+		Tunnel   *Tunnel    `json:"tunnel,omitempty"`
+	}
+)
+
+const (
+	tunnelAuto   = "auto"
+	tunnelAlways = "always"
+	tunnelNever  = "never"
+
+	tunnelModeIPIP  = "ipip"
+	tunnelModeVXLAN = "vxlan"
+)
+
+var (
+	// lookupIP resolves the node names without addr keyword.
+	lookupIP = net.LookupIP
+
+	// localNetworks returns the networks of the local interfaces, to
+	// decide if a peer is reachable without tunnel.
+	localNetworks = func() []*net.IPNet {
+		l := make([]*net.IPNet, 0)
+		addrs, err := net.InterfaceAddrs()
+		if err != nil {
+			return l
+		}
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+				l = append(l, ipnet)
+			}
+		}
+		return l
+	}
+)
+
+//
+// loadNodes sets the network setup of each cluster node. The subnet is
+// the node-scoped subnet keyword value, or the block of ips_per_node
+// addresses at the node index in the cluster nodes list. The address is
+// the node-scoped addr keyword value, or the node name resolution.
+//
+func (t *T) loadNodes(config *xconfig.T) {
+	section := sectionName(t.Name)
+	evalAs := func(option, nodename string) (interface{}, error) {
+		return config.EvalAs(key.New(section, option), nodename)
+	}
+	if v, err := config.Eval(key.New(section, "tunnel")); err == nil {
+		t.Tunnel, _ = v.(string)
+	}
+	if v, err := config.Eval(key.New(section, "tunnel_mode")); err == nil {
+		t.TunnelMode, _ = v.(string)
+	}
+	if v, err := config.Eval(key.New(section, "tables")); err == nil {
+		t.Tables, _ = v.([]string)
+	}
+	if v, err := config.Eval(key.New(section, "ips_per_node")); err == nil {
+		t.IPsPerNode, _ = v.(int)
+	}
+	var nodes []string
+	if v, err := config.Eval(key.New("cluster", "nodes")); err == nil {
+		nodes, _ = v.([]string)
+	}
+	t.Nodes = make(map[string]Node)
+	for i, nodename := range nodes {
+		node := Node{}
+		if v, err := evalAs("subnet", nodename); err == nil && v.(string) != "" {
+			if _, node.Subnet, err = net.ParseCIDR(v.(string)); err != nil {
+				node.Error = err.Error()
+			}
+		} else if node.Subnet, err = t.NodeSubnet(i); err != nil {
+			node.Error = err.Error()
+		}
+		if v, err := evalAs("addr", nodename); err == nil && v.(string) != "" {
+			node.Addr = net.ParseIP(v.(string))
+		} else if l, err := lookupIP(nodename); err == nil && len(l) > 0 {
+			node.Addr = l[0]
+		}
+		if node.Addr == nil && node.Error == "" {
+			node.Error = fmt.Sprintf("can not resolve the %s address", nodename)
+		}
+		t.Nodes[nodename] = node
+	}
+}
+
+//
+// NodeSubnet returns the subnet of the node at index in the cluster
+// nodes list: the network is fragmented in blocks of ips_per_node
+// addresses, rounded to the next power of two.
+//
+func (t T) NodeSubnet(index int) (*net.IPNet, error) {
+	ones, bits := t.Network.Mask.Size()
+	hostBits := 0
+	for 1<<uint(hostBits) < t.IPsPerNode {
+		hostBits++
+	}
+	subOnes := bits - hostBits
+	if subOnes < ones {
+		return nil, fmt.Errorf("network %s: ips_per_node %d exceeds the network size", t.Name, t.IPsPerNode)
+	}
+	if subOnes-ones < 63 && index >= 1<<uint(subOnes-ones) {
+		return nil, fmt.Errorf("network %s: too small for %d nodes", t.Name, index+1)
+	}
+	base := t.Network.IP.Mask(t.Network.Mask)
+	i := new(big.Int).SetBytes(base)
+	i.Add(i, new(big.Int).Lsh(big.NewInt(int64(index)), uint(hostBits)))
+	b := i.Bytes()
+	ip := make(net.IP, len(base))
+	copy(ip[len(ip)-len(b):], b)
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(subOnes, bits)}, nil
+}
+
+// tunnelName returns the name of the interface of a tunnel to the peer
+// address, short enough for the interface names length limit.
+func tunnelName(remote net.IP) string {
+	return fmt.Sprintf("otun%08x", crc32.ChecksumIEEE([]byte(remote.String())))
+}
+
+// vxlanName returns the name of the vxlan interface of the network.
+func (t T) vxlanName() string {
+	return fmt.Sprintf("ovx%08x", crc32.ChecksumIEEE([]byte(t.Name)))
+}
+
+// vni returns the vxlan network identifier of the network, the same on
+// all nodes.
+func (t T) vni() int {
+	return int(crc32.ChecksumIEEE([]byte(t.Name)) & 0xffffff)
+}
+
+func (t T) isDirect(addr net.IP) bool {
+	for _, n := range localNetworks() {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t T) needTunnel(addr net.IP) bool {
+	switch t.Tunnel {
+	case tunnelAlways:
+		return true
+	case tunnelNever:
+		return false
+	default:
+		return !t.isDirect(addr)
+	}
+}
+
+//
+// Routes returns the routes to install on the local node to reach the
+// subnets of its peers, in each routing table. The peers with an
+// incomplete setup are ignored.
+//
+func (t T) Routes(local string) []Route {
+	l := make([]Route, 0)
+	self, ok := t.Nodes[local]
+	if !ok {
+		return l
+	}
+	tables := t.Tables
+	if len(tables) == 0 {
+		tables = []string{"main"}
+	}
+	nodenames := make([]string, 0, len(t.Nodes))
+	for nodename := range t.Nodes {
+		nodenames = append(nodenames, nodename)
+	}
+	sort.Strings(nodenames)
+	for _, nodename := range nodenames {
+		peer := t.Nodes[nodename]
+		if nodename == local || peer.Error != "" || peer.Subnet == nil || peer.Addr == nil {
+			continue
+		}
+		route := Route{Nodename: nodename, Dst: peer.Subnet}
+		switch {
+		case !t.needTunnel(peer.Addr):
+			route.Gateway = peer.Addr
+		case t.TunnelMode == tunnelModeVXLAN:
+			route.Dev = t.vxlanName()
+			route.Gateway = nextIP(peer.Subnet.IP)
+			route.Tunnel = &Tunnel{Name: route.Dev, Mode: tunnelModeVXLAN, Local: self.Addr, Remote: peer.Addr, VNI: t.vni()}
+		default:
+			route.Dev = tunnelName(peer.Addr)
+			route.Tunnel = &Tunnel{Name: route.Dev, Mode: tunnelModeIPIP, Local: self.Addr, Remote: peer.Addr}
+		}
+		for _, table := range tables {
+			r := route
+			r.Table = table
+			l = append(l, r)
+		}
+	}
+	return l
+}
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeSubnet(t *testing.T) {
+	_, ipnet, _ := net.ParseCIDR("10.22.0.0/16")
+	n := T{Name: "n1", Network: ipnet, IPsPerNode: 1000}
+
+	subnet, err := n.NodeSubnet(0)
+	require.NoError(t, err)
+	assert.Equal(t, "10.22.0.0/22", subnet.String())
+
+	subnet, err = n.NodeSubnet(2)
+	require.NoError(t, err)
+	assert.Equal(t, "10.22.8.0/22", subnet.String())
+
+	_, err = n.NodeSubnet(64)
+	assert.Error(t, err, "a /16 network holds 64 /22 subnets")
+
+	n.IPsPerNode = 1 << 17
+	_, err = n.NodeSubnet(0)
+	assert.Error(t, err)
+}
+
+func TestRoutes(t *testing.T) {
+	localNetworksSave := localNetworks
+	defer func() { localNetworks = localNetworksSave }()
+	_, lan, _ := net.ParseCIDR("192.168.1.0/24")
+	localNetworks = func() []*net.IPNet { return []*net.IPNet{lan} }
+
+	_, ipnet, _ := net.ParseCIDR("10.22.0.0/16")
+	_, s1, _ := net.ParseCIDR("10.22.0.0/22")
+	_, s2, _ := net.ParseCIDR("10.22.4.0/22")
+	_, s3, _ := net.ParseCIDR("10.22.8.0/22")
+	n := T{
+		Name:    "n1",
+		Network: ipnet,
+		Tunnel:  tunnelAuto,
+		Tables:  []string{"main", "custom"},
+		Nodes: map[string]Node{
+			"node1": {Addr: net.ParseIP("192.168.1.1"), Subnet: s1},
+			"node2": {Addr: net.ParseIP("192.168.1.2"), Subnet: s2},
+			"node3": {Addr: net.ParseIP("172.16.0.3"), Subnet: s3},
+			"node4": {Error: "can not resolve the node4 address"},
+		},
+	}
+
+	t.Run("the peers on the same lan are routed via their address", func(t *testing.T) {
+		l := n.Routes("node1")
+		require.Len(t, l, 4)
+		assert.Equal(t, "node2", l[0].Nodename)
+		assert.Equal(t, "main", l[0].Table)
+		assert.Equal(t, "custom", l[1].Table)
+		assert.Equal(t, s2, l[0].Dst)
+		assert.Equal(t, "192.168.1.2", l[0].Gateway.String())
+		assert.Nil(t, l[0].Tunnel)
+
+		assert.Equal(t, "node3", l[2].Nodename)
+		require.NotNil(t, l[2].Tunnel)
+		assert.Equal(t, tunnelModeIPIP, l[2].Tunnel.Mode)
+		assert.Equal(t, "192.168.1.1", l[2].Tunnel.Local.String())
+		assert.Equal(t, "172.16.0.3", l[2].Tunnel.Remote.String())
+		assert.Equal(t, l[2].Tunnel.Name, l[2].Dev)
+		assert.Nil(t, l[2].Gateway)
+	})
+
+	t.Run("tunnel=always tunnels to all peers", func(t *testing.T) {
+		n := n
+		n.Tunnel = tunnelAlways
+		n.TunnelMode = tunnelModeVXLAN
+		for _, r := range n.Routes("node1") {
+			require.NotNil(t, r.Tunnel, r.Nodename)
+			assert.Equal(t, n.vxlanName(), r.Dev)
+			assert.Equal(t, nextIP(r.Dst.IP), r.Gateway, "vxlan routes are via the peer subnet gateway")
+		}
+	})
+
+	t.Run("tunnel=never routes all peers via their address", func(t *testing.T) {
+		n := n
+		n.Tunnel = tunnelNever
+		for _, r := range n.Routes("node1") {
+			assert.Nil(t, r.Tunnel, r.Nodename)
+		}
+	})
+
+	t.Run("no route from a node out of the network", func(t *testing.T) {
+		assert.Len(t, n.Routes("node9"), 0)
+	})
+}
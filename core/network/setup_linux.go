@@ -0,0 +1,174 @@
+// +build linux
+
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	vxlanPort = 4789
+	rtTables  = "/etc/iproute2/rt_tables"
+)
+
+//
+// Setup installs the tunnels and the routes to the peer nodes subnets.
+// The setup is idempotent: the existing tunnels are kept and the routes
+// are replaced.
+//
+func (t T) Setup(local string) error {
+	for _, r := range t.Routes(local) {
+		if err := r.install(); err != nil {
+			return fmt.Errorf("network %s: route to %s: %w", t.Name, r.Nodename, err)
+		}
+	}
+	return nil
+}
+
+// Status returns the installation state of the routes on the local node.
+func (t T) Status(local string) Status {
+	data := t.status(local)
+	for _, r := range t.Routes(local) {
+		rs := RouteStatus{Route: r}
+		rs.Installed, rs.Error = r.installed()
+		data.Routes = append(data.Routes, rs)
+	}
+	return data
+}
+
+func (t Route) install() error {
+	var (
+		link netlink.Link
+		err  error
+	)
+	if t.Tunnel != nil {
+		if link, err = t.Tunnel.install(); err != nil {
+			return err
+		}
+	}
+	table, err := tableID(t.Table)
+	if err != nil {
+		return err
+	}
+	route := &netlink.Route{Dst: t.Dst, Gw: t.Gateway, Table: table}
+	if link != nil {
+		route.LinkIndex = link.Attrs().Index
+		if t.Gateway != nil {
+			route.Flags = int(netlink.FLAG_ONLINK)
+		}
+	}
+	return netlink.RouteReplace(route)
+}
+
+func (t Route) installed() (bool, string) {
+	table, err := tableID(t.Table)
+	if err != nil {
+		return false, err.Error()
+	}
+	filter := &netlink.Route{Dst: t.Dst, Table: table}
+	l, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, filter, netlink.RT_FILTER_DST|netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return false, err.Error()
+	}
+	for _, r := range l {
+		if t.Gateway != nil && !r.Gw.Equal(t.Gateway) {
+			continue
+		}
+		if t.Dev != "" {
+			link, err := netlink.LinkByName(t.Dev)
+			if err != nil || link.Attrs().Index != r.LinkIndex {
+				continue
+			}
+		}
+		return true, ""
+	}
+	return false, ""
+}
+
+//
+// install creates the tunnel interface if missing, and sets it up. The
+// vxlan interface is shared by all the peers of the network, each peer
+// being added as a flooding destination.
+//
+func (t Tunnel) install() (netlink.Link, error) {
+	link, err := netlink.LinkByName(t.Name)
+	if err != nil {
+		switch t.Mode {
+		case tunnelModeVXLAN:
+			link = &netlink.Vxlan{
+				LinkAttrs: netlink.LinkAttrs{Name: t.Name},
+				VxlanId:   t.VNI,
+				SrcAddr:   t.Local,
+				Port:      vxlanPort,
+				Learning:  true,
+			}
+		default:
+			link = &netlink.Iptun{
+				LinkAttrs: netlink.LinkAttrs{Name: t.Name},
+				Local:     t.Local,
+				Remote:    t.Remote,
+			}
+		}
+		if err := netlink.LinkAdd(link); err != nil {
+			return nil, fmt.Errorf("add %s tunnel %s: %w", t.Mode, t.Name, err)
+		}
+		if link, err = netlink.LinkByName(t.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return nil, err
+	}
+	if t.Mode == tunnelModeVXLAN {
+		neigh := &netlink.Neigh{
+			LinkIndex:    link.Attrs().Index,
+			Family:       syscall.AF_BRIDGE,
+			Flags:        netlink.NTF_SELF,
+			State:        netlink.NUD_PERMANENT,
+			IP:           t.Remote,
+			HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0, 0},
+		}
+		if err := netlink.NeighAppend(neigh); err != nil && !os.IsExist(err) {
+			return nil, fmt.Errorf("add %s flooding destination to %s: %w", t.Remote, t.Name, err)
+		}
+	}
+	return link, nil
+}
+
+// tableID returns the id of a routing table name, resolved from the
+// iproute2 rt_tables file if not numeric.
+func tableID(name string) (int, error) {
+	switch name {
+	case "", "main":
+		return syscall.RT_TABLE_MAIN, nil
+	case "local":
+		return syscall.RT_TABLE_LOCAL, nil
+	case "default":
+		return syscall.RT_TABLE_DEFAULT, nil
+	}
+	if i, err := strconv.Atoi(name); err == nil {
+		return i, nil
+	}
+	f, err := os.Open(rtTables)
+	if err != nil {
+		return 0, fmt.Errorf("routing table %s: %w", name, err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		l := strings.Fields(s.Text())
+		if len(l) < 2 || strings.HasPrefix(l[0], "#") || l[1] != name {
+			continue
+		}
+		return strconv.Atoi(l[0])
+	}
+	return 0, fmt.Errorf("routing table %s not found in %s", name, rtTables)
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package network
+
+import "errors"
+
+// ErrNotSupported is returned by Setup on the operating systems without
+// network setup support.
+var ErrNotSupported = errors.New("network setup is not supported on this operating system")
+
+// Setup is not supported on this operating system.
+func (t T) Setup(local string) error {
+	return ErrNotSupported
+}
+
+// Status returns the network status, without routes.
+func (t T) Status(local string) Status {
+	data := t.status(local)
+	data.Errors = append(data.Errors, ErrNotSupported.Error())
+	return data
+}
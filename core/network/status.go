@@ -0,0 +1,164 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// RouteStatus is the installation state of a route on the local node.
+	RouteStatus struct {
+		Route
+		Installed bool   `json:"installed"`
+		Error     string `json:"error,omitempty"`
+	}
+
+	// Status is the setup state of a network on the local node.
+	Status struct {
+		Name    string        `json:"name"`
+		Type    string        `json:"type"`
+		Network string        `json:"network"`
+		Subnet  string        `json:"subnet,omitempty"`
+		Routes  []RouteStatus `json:"routes,omitempty"`
+		Errors  []string      `json:"errors,omitempty"`
+		Leases  []Lease       `json:"leases,omitempty"`
+		StatusUsage
+	}
+
+	// StatusUsage is the addresses usage of a network.
+	StatusUsage struct {
+		// Size is the number of allocatable addresses.
+		Size uint64 `json:"size"`
+		// Used is the number of leased addresses.
+		Used uint64 `json:"used"`
+		// Free is the number of addresses available for allocation.
+		Free uint64 `json:"free"`
+	}
+
+	// StatusList is a renderable list of networks status.
+	StatusList []Status
+)
+
+// Render is a human readable format of the networks status.
+func (t StatusList) Render() string {
+	tr := tree.New()
+	tr.AddColumn().AddText("Name").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Type").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Network").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Subnet").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Size").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Used").SetColor(rawconfig.Node.Color.Bold)
+	tr.AddColumn().AddText("Free").SetColor(rawconfig.Node.Color.Bold)
+	for _, s := range t {
+		n := tr.AddNode()
+		n.AddColumn().AddText(s.Name).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(s.Type)
+		n.AddColumn().AddText(s.Network)
+		n.AddColumn().AddText(s.Subnet)
+		n.AddColumn().AddText(fmt.Sprint(s.Size))
+		n.AddColumn().AddText(fmt.Sprint(s.Used))
+		n.AddColumn().AddText(fmt.Sprint(s.Free))
+		for _, e := range s.Errors {
+			n.AddNode().AddColumn().AddText(e).SetColor(rawconfig.Node.Color.Error)
+		}
+		for _, lease := range s.Leases {
+			ln := n.AddNode()
+			ln.AddColumn().AddText(lease.IP.String())
+			ln.AddColumn().AddText(lease.Owner)
+		}
+		for _, r := range s.Routes {
+			rn := n.AddNode()
+			rn.AddColumn().AddText(r.Nodename)
+			rn.AddColumn().AddText(r.via())
+			rn.AddColumn().AddText(r.Dst.String())
+			if r.Installed {
+				rn.AddColumn().AddText("installed").SetColor(rawconfig.Node.Color.Optimal)
+			} else {
+				rn.AddColumn().AddText(strings.TrimSpace("not installed " + r.Error)).SetColor(rawconfig.Node.Color.Warning)
+			}
+		}
+	}
+	return tr.Render()
+}
+
+// via returns a human readable description of the route next hop.
+func (t Route) via() string {
+	var s string
+	switch {
+	case t.Tunnel != nil:
+		s = fmt.Sprintf("%s tunnel %s", t.Tunnel.Mode, t.Dev)
+	default:
+		s = fmt.Sprintf("via %s", t.Gateway)
+	}
+	if t.Table != "main" {
+		s += " table " + t.Table
+	}
+	return s
+}
+
+//
+// Usage returns the number of allocatable, leased and free addresses of
+// the network. The size is capped to the uint64 capacity, which only the
+// large ipv6 networks exceed.
+//
+func (t T) Usage() (StatusUsage, error) {
+	var data StatusUsage
+	leases, err := t.Leases()
+	if err != nil {
+		return data, err
+	}
+	size := big.NewInt(0)
+	for _, r := range t.AllocationRanges() {
+		n := new(big.Int).Sub(ipInt(r.Last), ipInt(r.First))
+		size.Add(size, n.Add(n, big.NewInt(1)))
+		if t.Gateway != nil && compareIP(r.First, t.Gateway) <= 0 && compareIP(t.Gateway, r.Last) <= 0 {
+			size.Sub(size, big.NewInt(1))
+		}
+	}
+	if size.IsUint64() {
+		data.Size = size.Uint64()
+	} else {
+		data.Size = math.MaxUint64
+	}
+	data.Used = uint64(len(leases))
+	if data.Used < data.Size {
+		data.Free = data.Size - data.Used
+	}
+	return data, nil
+}
+
+// status returns the network status, without the routes state.
+func (t T) status(local string) Status {
+	data := Status{
+		Name:    t.Name,
+		Type:    t.Type,
+		Network: t.Network.String(),
+		Routes:  make([]RouteStatus, 0),
+		Errors:  make([]string, 0),
+	}
+	if node, ok := t.Nodes[local]; ok && node.Subnet != nil {
+		data.Subnet = node.Subnet.String()
+	}
+	nodenames := make([]string, 0, len(t.Nodes))
+	for nodename := range t.Nodes {
+		nodenames = append(nodenames, nodename)
+	}
+	sort.Strings(nodenames)
+	for _, nodename := range nodenames {
+		if e := t.Nodes[nodename].Error; e != "" {
+			data.Errors = append(data.Errors, nodename+": "+e)
+		}
+	}
+	if usage, err := t.Usage(); err != nil {
+		data.Errors = append(data.Errors, err.Error())
+	} else {
+		data.StatusUsage = usage
+	}
+	return data
+}
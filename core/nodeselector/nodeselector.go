@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/danwakefield/fnmatch"
@@ -17,6 +18,7 @@ import (
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/util/funcopt"
 	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/timestamp"
 	"opensvc.com/opensvc/util/xmap"
 )
 
@@ -28,6 +30,19 @@ type (
 		Targets interface{}
 	}
 
+	// NodesStatus is the subset of the daemon status used by the status
+	// filters, indexed by node name.
+	NodesStatus map[string]NodeStatus
+
+	// NodeStatus is the subset of a node daemon status used by the status
+	// filters.
+	NodeStatus struct {
+		Frozen  timestamp.T `json:"frozen"`
+		Monitor struct {
+			Status string `json:"status"`
+		} `json:"monitor"`
+	}
+
 	T struct {
 		SelectorExpression string
 		hasClient          bool
@@ -38,6 +53,7 @@ type (
 		knownNodes         []string
 		knownNodesSet      *set.Set
 		info               NodesInfo
+		status             NodesStatus
 	}
 )
 
@@ -184,10 +200,22 @@ func (t *T) expand() error {
 	return nil
 }
 
+//
+// expandOne resolves a selector expression element:
+//
+//   <label>=<value>  nodes with the label set to the value
+//   <filter>:<value> nodes whose daemon status matches the filter, with
+//                    filter in frozen (true|false) and state (the node
+//                    monitor state, ex: idle)
+//   <glob>           known nodes matching the fnmatch pattern
+//   <node>           the node, if known
+//
 func (t *T) expandOne(s string) (*set.Set, error) {
 	switch {
 	case strings.Contains(s, "="):
 		return t.labelExpand(s)
+	case strings.Contains(s, ":"):
+		return t.statusExpand(s)
 	case fnmatchExpressionRegex.MatchString(s):
 		return t.fnmatchExpand(s)
 	default:
@@ -275,6 +303,70 @@ func (t *T) labelExpand(s string) (*set.Set, error) {
 	return matching, nil
 }
 
+func (t *T) statusExpand(s string) (*set.Set, error) {
+	l := strings.SplitN(s, ":", 2)
+	var match func(NodeStatus) bool
+	switch l[0] {
+	case "frozen":
+		v, err := strconv.ParseBool(l[1])
+		if err != nil {
+			return nil, errors.Newf("invalid frozen filter value %s", l[1])
+		}
+		match = func(data NodeStatus) bool {
+			return data.IsFrozen() == v
+		}
+	case "state":
+		match = func(data NodeStatus) bool {
+			return data.Monitor.Status == l[1]
+		}
+	default:
+		return nil, errors.Newf("invalid status filter %s: supported filters are frozen and state", l[0])
+	}
+	matching := set.New()
+	nodesStatus, err := t.getNodesStatus()
+	if err != nil {
+		return nil, err
+	}
+	for node, data := range nodesStatus {
+		if match(data) {
+			matching.Insert(node)
+		}
+	}
+	return matching, nil
+}
+
+// IsFrozen returns true if the node is frozen.
+func (t NodeStatus) IsFrozen() bool {
+	return !t.Frozen.IsZero() && !t.Frozen.Time().IsZero()
+}
+
+//
+// getNodesStatus returns the nodes status from the daemon status. The
+// status filters are not resolvable without a daemon.
+//
+func (t *T) getNodesStatus() (NodesStatus, error) {
+	if t.status != nil {
+		return t.status, nil
+	}
+	if err := t.mustHaveClient(); err != nil {
+		return nil, err
+	}
+	b, err := t.client.NewGetDaemonStatus().Do()
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		Monitor struct {
+			Nodes NodesStatus `json:"nodes"`
+		} `json:"monitor"`
+	}{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	t.status = data.Monitor.Nodes
+	return t.status, nil
+}
+
 func (t T) KnownNodes() ([]string, error) {
 	if t.local {
 		return t.localKnownNodes()
@@ -284,7 +376,7 @@ func (t T) KnownNodes() ([]string, error) {
 
 func (t T) localKnownNodes() ([]string, error) {
 	l := strings.Fields(rawconfig.Node.Cluster.Nodes)
-	for i := 0; i > len(l); i++ {
+	for i := 0; i < len(l); i++ {
 		l[i] = strings.ToLower(l[i])
 	}
 	return l, nil
@@ -0,0 +1,64 @@
+package nodeselector
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func newTestSelector(s string) *T {
+	t := New(s, WithLocal(true))
+	t.info = NodesInfo{
+		"n1":   {Labels: map[string]string{"az": "eu1"}},
+		"n2":   {Labels: map[string]string{"az": "eu2"}},
+		"dev1": {Labels: map[string]string{"az": "eu1"}},
+	}
+	frozen := NodeStatus{Frozen: timestamp.New(time.Now())}
+	frozen.Monitor.Status = "idle"
+	thawed := NodeStatus{}
+	thawed.Monitor.Status = "idle"
+	draining := NodeStatus{}
+	draining.Monitor.Status = "draining"
+	t.status = NodesStatus{
+		"n1":   frozen,
+		"n2":   thawed,
+		"dev1": draining,
+	}
+	return t
+}
+
+func TestExpand(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	rawconfig.Node.Cluster.Nodes = "n1 N2 dev1"
+	cases := map[string][]string{
+		"n1":                 {"n1"},
+		"N1":                 {"n1"},
+		"n3":                 {},
+		"n*":                 {"n1", "n2"},
+		"az=eu1":             {"dev1", "n1"},
+		"az=eu3":             {},
+		"frozen:true":        {"n1"},
+		"frozen:false":       {"dev1", "n2"},
+		"state:draining":     {"dev1"},
+		"n1 az=eu2":          {"n1", "n2"},
+		"state:idle dev*":    {"dev1", "n1", "n2"},
+		"frozen:true az=eu1": {"dev1", "n1"},
+	}
+	for s, expected := range cases {
+		nodes := newTestSelector(s).Expand()
+		sort.Strings(nodes)
+		assert.ElementsMatch(t, expected, nodes, s)
+	}
+}
+
+func TestExpandInvalidStatusFilter(t *testing.T) {
+	rawconfig.Load(map[string]string{})
+	for _, s := range []string{"frozen:maybe", "color:blue"} {
+		_, err := newTestSelector(s).statusExpand(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,36 @@
+package nodestats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"opensvc.com/opensvc/util/xmlrpc"
+)
+
+// Push posts the samples not yet pushed to the collector xmlrpc server at
+// url, authenticated by the node name and uuid, and records the time of
+// the most recent pushed sample. It returns the number of samples pushed.
+func (t DB) Push(ctx context.Context, url, nodename, uuid string) (int, error) {
+	from := t.LastPush()
+	if !from.IsZero() {
+		// LastPush is the time of the last sample already pushed
+		from = from.Add(1)
+	}
+	samples, err := t.Query(from, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	if len(samples) == 0 {
+		return 0, nil
+	}
+	b, err := json.Marshal(samples)
+	if err != nil {
+		return 0, err
+	}
+	auth := []string{uuid, nodename}
+	if err := xmlrpc.New(url).Call(ctx, "insert_stats", string(b), auth); err != nil {
+		return 0, err
+	}
+	return len(samples), t.SetLastPush(samples[len(samples)-1].Time)
+}
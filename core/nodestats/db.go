@@ -0,0 +1,140 @@
+package nodestats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// DB is a ring database of samples, stored as one json lines file per
+	// day of month, named sa<DD>.jsonl like the sysstat files. A day file
+	// is reset when first written on a new day, so the database holds
+	// about one month of samples.
+	DB struct {
+		Dir string
+	}
+
+	// Samples is a list of samples, in time order.
+	Samples []Sample
+)
+
+const lastPushFile = "last_push"
+
+// NewDB returns the ring database stored in dir.
+func NewDB(dir string) *DB {
+	return &DB{Dir: dir}
+}
+
+func (t DB) dayFile(tm time.Time) string {
+	return filepath.Join(t.Dir, fmt.Sprintf("sa%02d.jsonl", tm.Day()))
+}
+
+// Append stores the sample in the file of its day of month, resetting
+// the file if it holds the samples of a previous month.
+func (t DB) Append(s Sample) error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	p := t.dayFile(s.Time)
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if fi, err := os.Stat(p); err == nil && !sameDay(fi.ModTime(), s.Time) {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(p, flag, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Local().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// Query returns the stored samples timestamped between from and to
+// included, in time order. A zero to means no upper bound.
+func (t DB) Query(from, to time.Time) (Samples, error) {
+	data := make(Samples, 0)
+	matches, err := filepath.Glob(filepath.Join(t.Dir, "sa[0-9][0-9].jsonl"))
+	if err != nil {
+		return data, err
+	}
+	for _, p := range matches {
+		if fi, err := os.Stat(p); err != nil || fi.ModTime().Before(from) {
+			// the file was last written before the range begins
+			continue
+		}
+		l, err := readSamples(p)
+		if err != nil {
+			return data, err
+		}
+		for _, s := range l {
+			if s.Time.Before(from) || (!to.IsZero() && s.Time.After(to)) {
+				continue
+			}
+			data = append(data, s)
+		}
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].Time.Before(data[j].Time)
+	})
+	return data, nil
+}
+
+func readSamples(p string) (Samples, error) {
+	data := make(Samples, 0)
+	f, err := os.Open(p)
+	if err != nil {
+		return data, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			// skip the line truncated by a crash
+			continue
+		}
+		data = append(data, s)
+	}
+	return data, scanner.Err()
+}
+
+// LastPush returns the time of the most recent sample pushed to the
+// collector, or the zero time if none was pushed yet.
+func (t DB) LastPush() time.Time {
+	b, err := ioutil.ReadFile(filepath.Join(t.Dir, lastPushFile))
+	if err != nil {
+		return time.Time{}
+	}
+	tm, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}
+	}
+	return tm
+}
+
+// SetLastPush stores the time of the most recent sample pushed to the
+// collector.
+func (t DB) SetLastPush(tm time.Time) error {
+	if err := os.MkdirAll(t.Dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(t.Dir, lastPushFile), []byte(tm.Format(time.RFC3339Nano)), 0644)
+}
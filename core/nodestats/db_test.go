@@ -0,0 +1,89 @@
+package nodestats
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db := NewDB(dir)
+
+	now := time.Now()
+	t0 := now.Add(-2 * time.Hour)
+	t1 := now.Add(-time.Hour)
+	require.NoError(t, db.Append(Sample{Time: t0, CPU: &CPU{Idle: 90}}))
+	require.NoError(t, db.Append(Sample{Time: t1, CPU: &CPU{Idle: 80}}))
+
+	samples, err := db.Query(now.Add(-90*time.Minute), time.Time{})
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 80.0, samples[0].CPU.Idle)
+
+	samples, err = db.Query(now.Add(-3*time.Hour), t0)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, 90.0, samples[0].CPU.Idle)
+}
+
+func TestDBRingReset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db := NewDB(dir)
+
+	now := time.Now()
+	lastMonth := now.AddDate(0, -1, 0)
+	p := db.dayFile(now)
+	require.NoError(t, db.Append(Sample{Time: now}))
+	// the day file was last written on the same day of the previous month
+	require.NoError(t, os.Chtimes(p, lastMonth, lastMonth))
+	require.NoError(t, db.Append(Sample{Time: now}))
+
+	b, err := ioutil.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"))
+}
+
+func TestPush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db := NewDB(dir)
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		b, _ := ioutil.ReadAll(r.Body)
+		assert.Contains(t, string(b), "<methodName>insert_stats</methodName>")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><nil/></value></param></params></methodResponse>`))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	require.NoError(t, db.Append(Sample{Time: now.Add(-time.Minute)}))
+	require.NoError(t, db.Append(Sample{Time: now}))
+	n, err := db.Push(context.Background(), srv.URL, "node1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, db.LastPush().Equal(now))
+	_, err = os.Stat(filepath.Join(dir, lastPushFile))
+	assert.NoError(t, err)
+
+	n, err = db.Push(context.Background(), srv.URL, "node1", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "already pushed")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,225 @@
+package nodestats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// counters are the monotonic kernel counters sampled twice to compute
+	// the cpu, block device and network device rates.
+	counters struct {
+		cpu     cpuCounters
+		blkdevs map[string]blkdevCounters
+		netdevs map[string]netdevCounters
+	}
+
+	cpuCounters struct {
+		user, nice, system, idle, iowait, irq, softirq, steal uint64
+	}
+
+	blkdevCounters struct {
+		reads, readSectors, writes, writeSectors uint64
+	}
+
+	netdevCounters struct {
+		rxBytes, rxPackets, rxErrs, rxDrop uint64
+		txBytes, txPackets, txErrs, txDrop uint64
+	}
+)
+
+// procPath is the mount point of the proc filesystem, variable for tests.
+var procPath = "/proc"
+
+// sectorSize is the unit of the /proc/diskstats sector counters, which is
+// always 512 bytes, whatever the device logical block size.
+const sectorSize = 512
+
+func readCounters() (counters, error) {
+	var c counters
+	err := readProcFile("stat", func(r io.Reader) (err error) {
+		c.cpu, err = parseCPU(r)
+		return
+	})
+	if err != nil {
+		return c, err
+	}
+	err = readProcFile("diskstats", func(r io.Reader) (err error) {
+		c.blkdevs, err = parseDiskstats(r)
+		return
+	})
+	if err != nil {
+		return c, err
+	}
+	err = readProcFile("net/dev", func(r io.Reader) (err error) {
+		c.netdevs, err = parseNetDev(r)
+		return
+	})
+	return c, err
+}
+
+func readProcFile(name string, parser func(io.Reader) error) error {
+	f, err := os.Open(procPath + "/" + name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return parser(f)
+}
+
+// parseCPU parses the aggregated cpu line of /proc/stat.
+func parseCPU(r io.Reader) (cpuCounters, error) {
+	var c cpuCounters
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		l := strings.Fields(scanner.Text())
+		if len(l) < 9 || l[0] != "cpu" {
+			continue
+		}
+		v, err := parseUints(l[1:9])
+		if err != nil {
+			return c, fmt.Errorf("parse cpu line: %w", err)
+		}
+		c = cpuCounters{
+			user:    v[0],
+			nice:    v[1],
+			system:  v[2],
+			idle:    v[3],
+			iowait:  v[4],
+			irq:     v[5],
+			softirq: v[6],
+			steal:   v[7],
+		}
+		return c, nil
+	}
+	return c, fmt.Errorf("no cpu line")
+}
+
+// parseDiskstats parses /proc/diskstats, skipping the loop and ram
+// devices.
+func parseDiskstats(r io.Reader) (map[string]blkdevCounters, error) {
+	m := make(map[string]blkdevCounters)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		l := strings.Fields(scanner.Text())
+		if len(l) < 14 {
+			continue
+		}
+		name := l[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+			continue
+		}
+		v, err := parseUints([]string{l[3], l[5], l[7], l[9]})
+		if err != nil {
+			return m, fmt.Errorf("parse %s diskstats: %w", name, err)
+		}
+		m[name] = blkdevCounters{
+			reads:        v[0],
+			readSectors:  v[1],
+			writes:       v[2],
+			writeSectors: v[3],
+		}
+	}
+	return m, scanner.Err()
+}
+
+// parseNetDev parses /proc/net/dev, skipping the loopback device.
+func parseNetDev(r io.Reader) (map[string]netdevCounters, error) {
+	m := make(map[string]netdevCounters)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		name := strings.TrimSpace(line[:i])
+		l := strings.Fields(line[i+1:])
+		if name == "lo" || len(l) < 12 {
+			continue
+		}
+		v, err := parseUints([]string{l[0], l[1], l[2], l[3], l[8], l[9], l[10], l[11]})
+		if err != nil {
+			return m, fmt.Errorf("parse %s net dev: %w", name, err)
+		}
+		m[name] = netdevCounters{
+			rxBytes:   v[0],
+			rxPackets: v[1],
+			rxErrs:    v[2],
+			rxDrop:    v[3],
+			txBytes:   v[4],
+			txPackets: v[5],
+			txErrs:    v[6],
+			txDrop:    v[7],
+		}
+	}
+	return m, scanner.Err()
+}
+
+// parseMeminfo parses /proc/meminfo, and returns the memory and swap
+// usage in kilobytes.
+func parseMeminfo(r io.Reader) (Mem, Swap, error) {
+	var (
+		mem  Mem
+		swap Swap
+	)
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		l := strings.Fields(scanner.Text())
+		if len(l) < 2 {
+			continue
+		}
+		i, err := strconv.ParseUint(l[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSuffix(l[0], ":")] = i
+	}
+	if err := scanner.Err(); err != nil {
+		return mem, swap, err
+	}
+	total, ok := values["MemTotal"]
+	if !ok {
+		return mem, swap, fmt.Errorf("no MemTotal in meminfo")
+	}
+	avail, ok := values["MemAvailable"]
+	if !ok {
+		// kernels older than 3.14
+		avail = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	mem = Mem{
+		Total: total,
+		Avail: avail,
+		Used:  total - avail,
+	}
+	swap = Swap{
+		Total: values["SwapTotal"],
+		Used:  values["SwapTotal"] - values["SwapFree"],
+	}
+	mem.UsedPct = percent(mem.Used, mem.Total)
+	swap.UsedPct = percent(swap.Used, swap.Total)
+	return mem, swap, nil
+}
+
+func parseUints(l []string) ([]uint64, error) {
+	v := make([]uint64, len(l))
+	for i, s := range l {
+		var err error
+		if v[i], err = strconv.ParseUint(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
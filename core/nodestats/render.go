@@ -0,0 +1,57 @@
+package nodestats
+
+import (
+	"fmt"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+// Render returns a human friendly string representation of the samples,
+// one line per sample with the cpu, memory and swap usage percentages,
+// and the network and block devices throughputs summed over all devices.
+func (t Samples) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText(hostname.Hostname()).SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("cpu%")
+	tree.AddColumn().AddText("mem%")
+	tree.AddColumn().AddText("swap%")
+	tree.AddColumn().AddText("rxkB/s")
+	tree.AddColumn().AddText("txkB/s")
+	tree.AddColumn().AddText("rdkB/s")
+	tree.AddColumn().AddText("wrkB/s")
+	for _, s := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(s.Time.Local().Format("2006-01-02 15:04:05")).SetColor(rawconfig.Node.Color.Primary)
+		if s.CPU != nil {
+			n.AddColumn().AddText(fmt.Sprintf("%.1f", 100-s.CPU.Idle))
+		} else {
+			n.AddColumn().AddText("-")
+		}
+		if s.Mem != nil {
+			n.AddColumn().AddText(fmt.Sprintf("%.1f", s.Mem.UsedPct))
+		} else {
+			n.AddColumn().AddText("-")
+		}
+		if s.Swap != nil {
+			n.AddColumn().AddText(fmt.Sprintf("%.1f", s.Swap.UsedPct))
+		} else {
+			n.AddColumn().AddText("-")
+		}
+		var rx, tx, rd, wr float64
+		for _, d := range s.NetDevs {
+			rx += d.RxBps
+			tx += d.TxBps
+		}
+		for _, d := range s.BlockDevs {
+			rd += d.ReadBps
+			wr += d.WriteBps
+		}
+		n.AddColumn().AddText(fmt.Sprintf("%.1f", rx/1024))
+		n.AddColumn().AddText(fmt.Sprintf("%.1f", tx/1024))
+		n.AddColumn().AddText(fmt.Sprintf("%.1f", rd/1024))
+		n.AddColumn().AddText(fmt.Sprintf("%.1f", wr/1024))
+	}
+	return tree.Render()
+}
@@ -0,0 +1,265 @@
+// Package nodestats samples the node cpu, memory, swap, block device,
+// network device and filesystem usage, stores the samples in a local
+// ring database, and pushes them to the collector.
+package nodestats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"opensvc.com/opensvc/util/df"
+)
+
+type (
+	// Sample is the node resources usage at a point in time. The rates are
+	// averaged over the sampling interval.
+	Sample struct {
+		Time      time.Time `json:"time"`
+		CPU       *CPU      `json:"cpu,omitempty"`
+		Mem       *Mem      `json:"mem,omitempty"`
+		Swap      *Swap     `json:"swap,omitempty"`
+		BlockDevs []BlkDev  `json:"blockdev,omitempty"`
+		NetDevs   []NetDev  `json:"netdev,omitempty"`
+		FS        []FS      `json:"fs,omitempty"`
+	}
+
+	// CPU is the percentage of the cpu time spent in each state.
+	CPU struct {
+		User   float64 `json:"user"`
+		Nice   float64 `json:"nice"`
+		System float64 `json:"system"`
+		IOWait float64 `json:"iowait"`
+		Steal  float64 `json:"steal"`
+		Idle   float64 `json:"idle"`
+	}
+
+	// Mem is the memory usage, in kilobytes.
+	Mem struct {
+		Total   uint64  `json:"total"`
+		Used    uint64  `json:"used"`
+		Avail   uint64  `json:"avail"`
+		UsedPct float64 `json:"used_pct"`
+	}
+
+	// Swap is the swap usage, in kilobytes.
+	Swap struct {
+		Total   uint64  `json:"total"`
+		Used    uint64  `json:"used"`
+		UsedPct float64 `json:"used_pct"`
+	}
+
+	// BlkDev is a block device io rates, in operations and bytes per
+	// second.
+	BlkDev struct {
+		Name     string  `json:"name"`
+		Reads    float64 `json:"rps"`
+		Writes   float64 `json:"wps"`
+		ReadBps  float64 `json:"rbps"`
+		WriteBps float64 `json:"wbps"`
+	}
+
+	// NetDev is a network device rates, in packets, bytes and errors per
+	// second.
+	NetDev struct {
+		Name      string  `json:"name"`
+		RxBps     float64 `json:"rxbps"`
+		TxBps     float64 `json:"txbps"`
+		RxPackets float64 `json:"rxpps"`
+		TxPackets float64 `json:"txpps"`
+		RxErrs    float64 `json:"rxerrps"`
+		TxErrs    float64 `json:"txerrps"`
+		RxDrop    float64 `json:"rxdropps"`
+		TxDrop    float64 `json:"txdropps"`
+	}
+
+	// FS is a filesystem usage, in kilobytes.
+	FS struct {
+		MountPoint string `json:"mnt"`
+		Device     string `json:"dev"`
+		Total      int64  `json:"total"`
+		Used       int64  `json:"used"`
+		UsedPct    int64  `json:"used_pct"`
+	}
+)
+
+const (
+	// GroupCPU is the stats group name of the cpu usage.
+	GroupCPU = "cpu"
+
+	// GroupMem is the stats group name of the memory usage.
+	GroupMem = "mem_u"
+
+	// GroupSwap is the stats group name of the swap usage.
+	GroupSwap = "swap"
+
+	// GroupBlockDev is the stats group name of the block devices rates.
+	GroupBlockDev = "blockdev"
+
+	// GroupNetDev is the stats group name of the network devices rates.
+	GroupNetDev = "netdev"
+
+	// GroupFS is the stats group name of the filesystems usage.
+	GroupFS = "fs_u"
+)
+
+// Collect samples the kernel counters twice, interval apart, and returns
+// the node resources usage, without the groups listed in disable. The
+// context cancellation interrupts the wait between the two samples.
+func Collect(ctx context.Context, interval time.Duration, disable []string) (Sample, error) {
+	prev, err := readCounters()
+	if err != nil {
+		return Sample{}, err
+	}
+	begin := time.Now()
+	select {
+	case <-ctx.Done():
+		return Sample{}, ctx.Err()
+	case <-time.After(interval):
+	}
+	cur, err := readCounters()
+	if err != nil {
+		return Sample{}, err
+	}
+	now := time.Now()
+	s := newSample(prev, cur, now.Sub(begin))
+	s.Time = now
+	err = readProcFile("meminfo", func(r io.Reader) error {
+		mem, swap, err := parseMeminfo(r)
+		s.Mem = &mem
+		s.Swap = &swap
+		return err
+	})
+	if err != nil {
+		return s, err
+	}
+	entries, err := df.Usage()
+	if err != nil {
+		return s, fmt.Errorf("df: %w", err)
+	}
+	for _, e := range entries {
+		s.FS = append(s.FS, FS{
+			MountPoint: e.MountPoint,
+			Device:     e.Device,
+			Total:      e.Total,
+			Used:       e.Used,
+			UsedPct:    e.UsedPercent,
+		})
+	}
+	s.Disable(disable)
+	return s, nil
+}
+
+// Disable removes the groups listed in l from the sample.
+func (t *Sample) Disable(l []string) {
+	for _, group := range l {
+		switch group {
+		case GroupCPU:
+			t.CPU = nil
+		case GroupMem:
+			t.Mem = nil
+		case GroupSwap:
+			t.Swap = nil
+		case GroupBlockDev:
+			t.BlockDevs = nil
+		case GroupNetDev:
+			t.NetDevs = nil
+		case GroupFS:
+			t.FS = nil
+		}
+	}
+}
+
+// newSample returns the sample of the rates computed from the counters
+// read d apart.
+func newSample(prev, cur counters, d time.Duration) Sample {
+	s := Sample{}
+	seconds := d.Seconds()
+	if seconds <= 0 {
+		return s
+	}
+	s.CPU = cpuUsage(prev.cpu, cur.cpu)
+	for _, name := range sortedKeysBlk(cur.blkdevs) {
+		p, ok := prev.blkdevs[name]
+		if !ok {
+			continue
+		}
+		c := cur.blkdevs[name]
+		s.BlockDevs = append(s.BlockDevs, BlkDev{
+			Name:     name,
+			Reads:    rate(p.reads, c.reads, seconds),
+			Writes:   rate(p.writes, c.writes, seconds),
+			ReadBps:  rate(p.readSectors, c.readSectors, seconds) * sectorSize,
+			WriteBps: rate(p.writeSectors, c.writeSectors, seconds) * sectorSize,
+		})
+	}
+	for _, name := range sortedKeysNet(cur.netdevs) {
+		p, ok := prev.netdevs[name]
+		if !ok {
+			continue
+		}
+		c := cur.netdevs[name]
+		s.NetDevs = append(s.NetDevs, NetDev{
+			Name:      name,
+			RxBps:     rate(p.rxBytes, c.rxBytes, seconds),
+			TxBps:     rate(p.txBytes, c.txBytes, seconds),
+			RxPackets: rate(p.rxPackets, c.rxPackets, seconds),
+			TxPackets: rate(p.txPackets, c.txPackets, seconds),
+			RxErrs:    rate(p.rxErrs, c.rxErrs, seconds),
+			TxErrs:    rate(p.txErrs, c.txErrs, seconds),
+			RxDrop:    rate(p.rxDrop, c.rxDrop, seconds),
+			TxDrop:    rate(p.txDrop, c.txDrop, seconds),
+		})
+	}
+	return s
+}
+
+func cpuUsage(prev, cur cpuCounters) *CPU {
+	user := delta(prev.user, cur.user)
+	nice := delta(prev.nice, cur.nice)
+	system := delta(prev.system, cur.system) + delta(prev.irq, cur.irq) + delta(prev.softirq, cur.softirq)
+	iowait := delta(prev.iowait, cur.iowait)
+	steal := delta(prev.steal, cur.steal)
+	idle := delta(prev.idle, cur.idle)
+	total := user + nice + system + iowait + steal + idle
+	return &CPU{
+		User:   percent(user, total),
+		Nice:   percent(nice, total),
+		System: percent(system, total),
+		IOWait: percent(iowait, total),
+		Steal:  percent(steal, total),
+		Idle:   percent(idle, total),
+	}
+}
+
+// delta returns the counter increment, or zero if the counter was reset.
+func delta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+func rate(prev, cur uint64, seconds float64) float64 {
+	return float64(delta(prev, cur)) / seconds
+}
+
+func sortedKeysBlk(m map[string]blkdevCounters) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func sortedKeysNet(m map[string]netdevCounters) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
@@ -0,0 +1,77 @@
+package nodestats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	procStat0 = "cpu  100 0 50 800 10 0 0 0 0 0\ncpu0 100 0 50 800 10 0 0 0 0 0\n"
+	procStat1 = "cpu  160 0 70 910 20 0 0 0 0 0\ncpu0 160 0 70 910 20 0 0 0 0 0\n"
+
+	diskstats0 = "   7       0 loop0 1 0 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+		"   8       0 sda 100 0 2000 0 50 0 1000 0 0 0 0 0 0 0 0 0 0\n"
+	diskstats1 = "   7       0 loop0 9 0 80 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+		"   8       0 sda 110 0 2400 0 70 0 1800 0 0 0 0 0 0 0 0 0 0\n"
+
+	netdev0 = "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"    lo: 1000 10 0 0 0 0 0 0 1000 10 0 0 0 0 0 0\n" +
+		"  eth0: 10000 100 0 0 0 0 0 0 20000 200 1 0 0 0 0 0\n"
+	netdev1 = "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"    lo: 9000 90 0 0 0 0 0 0 9000 90 0 0 0 0 0 0\n" +
+		"  eth0: 30000 120 0 2 0 0 0 0 60000 240 1 0 0 0 0 0\n"
+
+	meminfo = "MemTotal:        1000 kB\nMemFree:          100 kB\nMemAvailable:     250 kB\nSwapTotal:        400 kB\nSwapFree:         300 kB\n"
+)
+
+func parseCounters(t *testing.T, stat, diskstats, netdev string) counters {
+	var (
+		c   counters
+		err error
+	)
+	c.cpu, err = parseCPU(strings.NewReader(stat))
+	require.NoError(t, err)
+	c.blkdevs, err = parseDiskstats(strings.NewReader(diskstats))
+	require.NoError(t, err)
+	c.netdevs, err = parseNetDev(strings.NewReader(netdev))
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewSample(t *testing.T) {
+	prev := parseCounters(t, procStat0, diskstats0, netdev0)
+	cur := parseCounters(t, procStat1, diskstats1, netdev1)
+	s := newSample(prev, cur, 2*time.Second)
+
+	assert.Equal(t, &CPU{User: 30, System: 10, IOWait: 5, Idle: 55}, s.CPU)
+	assert.Equal(t, []BlkDev{{Name: "sda", Reads: 5, Writes: 10, ReadBps: 200 * 512, WriteBps: 400 * 512}}, s.BlockDevs)
+	assert.Equal(t, []NetDev{{Name: "eth0", RxBps: 10000, TxBps: 20000, RxPackets: 10, TxPackets: 20, RxDrop: 1}}, s.NetDevs)
+}
+
+func TestNewSampleCounterReset(t *testing.T) {
+	prev := parseCounters(t, procStat1, diskstats1, netdev1)
+	cur := parseCounters(t, procStat0, diskstats0, netdev0)
+	s := newSample(prev, cur, time.Second)
+	assert.Equal(t, 0.0, s.NetDevs[0].RxBps)
+}
+
+func TestParseMeminfo(t *testing.T) {
+	mem, swap, err := parseMeminfo(strings.NewReader(meminfo))
+	require.NoError(t, err)
+	assert.Equal(t, Mem{Total: 1000, Used: 750, Avail: 250, UsedPct: 75}, mem)
+	assert.Equal(t, Swap{Total: 400, Used: 100, UsedPct: 25}, swap)
+}
+
+func TestDisable(t *testing.T) {
+	s := Sample{CPU: &CPU{}, Mem: &Mem{}, FS: []FS{{}}}
+	s.Disable([]string{GroupCPU, GroupFS})
+	assert.Nil(t, s.CPU)
+	assert.NotNil(t, s.Mem)
+	assert.Nil(t, s.FS)
+}
@@ -0,0 +1,305 @@
+// Package notify detects the notable cluster status changes and posts them
+// to http webhooks, slack-compatible endpoints or snmp trap receivers.
+//
+// The detected events are:
+//
+//	object_warn   an object overall status turned warn
+//	object_down   an object availability status turned down
+//	object_avail  an object availability status changed
+//	failover      a failover object instance started on another node
+//	frozen        a node or an object got frozen
+//	hb_stale      a heartbeat stopped receiving data from a peer
+//	node_status   a node monitor status changed
+//	split         the cluster split, and the local segment lost the majority
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+type (
+	// Event is a notable cluster status change.
+	Event struct {
+		Kind      string    `json:"kind"`
+		Cluster   string    `json:"cluster,omitempty"`
+		Path      string    `json:"path,omitempty"`
+		Node      string    `json:"node,omitempty"`
+		Heartbeat string    `json:"hb,omitempty"`
+		From      string    `json:"from,omitempty"`
+		To        string    `json:"to,omitempty"`
+		Time      time.Time `json:"time"`
+	}
+)
+
+const (
+	// ObjectWarn is the kind of event emitted when an object overall
+	// status turns warn.
+	ObjectWarn = "object_warn"
+
+	// ObjectDown is the kind of event emitted when an object availability
+	// status turns down.
+	ObjectDown = "object_down"
+
+	// ObjectAvail is the kind of event emitted when an object availability
+	// status changes.
+	ObjectAvail = "object_avail"
+
+	// Failover is the kind of event emitted when a failover object
+	// instance starts on another node.
+	Failover = "failover"
+
+	// Frozen is the kind of event emitted when a node or an object gets
+	// frozen.
+	Frozen = "frozen"
+
+	// HeartbeatStale is the kind of event emitted when a heartbeat stops
+	// receiving data from a peer.
+	HeartbeatStale = "hb_stale"
+
+	// NodeStatus is the kind of event emitted when a node monitor status
+	// changes.
+	NodeStatus = "node_status"
+
+	// Split is the kind of event emitted when the cluster splits and the
+	// segment of the reporting node loses the majority.
+	Split = "split"
+)
+
+var (
+	// Kinds is the list of all event kinds.
+	Kinds = []string{ObjectWarn, ObjectDown, ObjectAvail, Failover, Frozen, HeartbeatStale, NodeStatus, Split}
+
+	// WebhookKinds is the list of event kinds posted by the webhooks
+	// selecting no kinds.
+	WebhookKinds = []string{ObjectWarn, ObjectDown, Failover, Frozen, HeartbeatStale, Split}
+
+	// TrapKinds is the list of event kinds sent by the snmp trap
+	// notifiers selecting no kinds.
+	TrapKinds = []string{ObjectAvail, Failover, Frozen, HeartbeatStale, NodeStatus, Split}
+)
+
+// Text returns the default human readable description of the event.
+func (t Event) Text() string {
+	switch t.Kind {
+	case ObjectWarn:
+		return fmt.Sprintf("%s: object %s overall status turned warn", t.Cluster, t.Path)
+	case ObjectDown:
+		return fmt.Sprintf("%s: object %s turned down", t.Cluster, t.Path)
+	case ObjectAvail:
+		return fmt.Sprintf("%s: object %s availability changed from %s to %s", t.Cluster, t.Path, t.From, t.To)
+	case Failover:
+		return fmt.Sprintf("%s: object %s failed over from %s to %s", t.Cluster, t.Path, t.From, t.To)
+	case Frozen:
+		if t.Path != "" {
+			return fmt.Sprintf("%s: object %s frozen", t.Cluster, t.Path)
+		}
+		return fmt.Sprintf("%s: node %s frozen", t.Cluster, t.Node)
+	case HeartbeatStale:
+		return fmt.Sprintf("%s: %s stale for peer %s", t.Cluster, t.Heartbeat, t.Node)
+	case NodeStatus:
+		return fmt.Sprintf("%s: node %s monitor status changed from %s to %s", t.Cluster, t.Node, t.From, t.To)
+	case Split:
+		return fmt.Sprintf("%s: cluster split, nodes %s lost, arbitration required", t.Cluster, t.Node)
+	default:
+		return fmt.Sprintf("%s: %s", t.Cluster, t.Kind)
+	}
+}
+
+// Detect returns the events explaining the change from the prev to the cur
+// cluster status. The objects, nodes and heartbeat peers absent from prev
+// emit no event, so a daemon restart or a new object does not trigger a
+// notification storm.
+func Detect(prev, cur cluster.Status, now time.Time) []Event {
+	l := make([]Event, 0)
+	l = append(l, detectObjects(prev, cur)...)
+	l = append(l, detectFailovers(prev, cur)...)
+	l = append(l, detectFrozen(prev, cur)...)
+	l = append(l, detectHeartbeats(prev, cur)...)
+	l = append(l, detectNodeStatus(prev, cur)...)
+	l = append(l, detectSplit(prev, cur)...)
+	for i := range l {
+		l[i].Cluster = cur.Cluster.Name
+		l[i].Time = now
+	}
+	return l
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func objectPaths(data cluster.Status) []string {
+	m := make(map[string]interface{})
+	for p := range data.Monitor.Services {
+		m[p] = nil
+	}
+	return sortedKeys(m)
+}
+
+func detectObjects(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	for _, p := range objectPaths(cur) {
+		before, ok := prev.Monitor.Services[p]
+		if !ok {
+			continue
+		}
+		after := cur.Monitor.Services[p]
+		if after.Overall == status.Warn && before.Overall != status.Warn {
+			l = append(l, Event{Kind: ObjectWarn, Path: p, From: before.Overall.String(), To: after.Overall.String()})
+		}
+		if after.Avail == status.Down && before.Avail != status.Down {
+			l = append(l, Event{Kind: ObjectDown, Path: p, From: before.Avail.String(), To: after.Avail.String()})
+		}
+		if after.Avail != before.Avail {
+			l = append(l, Event{Kind: ObjectAvail, Path: p, From: before.Avail.String(), To: after.Avail.String()})
+		}
+	}
+	return l
+}
+
+// upNodes returns the sorted list of nodes hosting an up instance of the
+// failover object p.
+func upNodes(data cluster.Status, p string) []string {
+	m := make(map[string]interface{})
+	for n, node := range data.Monitor.Nodes {
+		instance, ok := node.Services.Status[p]
+		if !ok || instance.Topology != topology.Failover {
+			continue
+		}
+		if instance.Avail == status.Up {
+			m[n] = nil
+		}
+	}
+	return sortedKeys(m)
+}
+
+func detectFailovers(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	for _, p := range objectPaths(cur) {
+		before := upNodes(prev, p)
+		after := upNodes(cur, p)
+		if len(before) == 0 || len(after) == 0 {
+			continue
+		}
+		if strings.Join(before, ",") == strings.Join(after, ",") {
+			continue
+		}
+		if hasAny(before, after) {
+			// still up on a previous node: not a failover yet
+			continue
+		}
+		l = append(l, Event{Kind: Failover, Path: p, From: strings.Join(before, ","), To: strings.Join(after, ",")})
+	}
+	return l
+}
+
+func hasAny(l, candidates []string) bool {
+	for _, s := range l {
+		for _, c := range candidates {
+			if s == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeNames(data cluster.Status) []string {
+	m := make(map[string]interface{})
+	for n := range data.Monitor.Nodes {
+		m[n] = nil
+	}
+	return sortedKeys(m)
+}
+
+func isFrozen(data cluster.Status, n string) bool {
+	node, ok := data.Monitor.Nodes[n]
+	if !ok {
+		return false
+	}
+	return !node.Frozen.IsZero() && !node.Frozen.Time().IsZero()
+}
+
+func detectFrozen(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	for _, n := range nodeNames(cur) {
+		if _, ok := prev.Monitor.Nodes[n]; !ok {
+			continue
+		}
+		if isFrozen(cur, n) && !isFrozen(prev, n) {
+			l = append(l, Event{Kind: Frozen, Node: n})
+		}
+	}
+	for _, p := range objectPaths(cur) {
+		before, ok := prev.Monitor.Services[p]
+		if !ok {
+			continue
+		}
+		if cur.Monitor.Services[p].Frozen == "frozen" && before.Frozen != "frozen" {
+			l = append(l, Event{Kind: Frozen, Path: p})
+		}
+	}
+	return l
+}
+
+func detectHeartbeats(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	hbs := make(map[string]interface{})
+	for name := range cur.Heartbeats {
+		hbs[name] = nil
+	}
+	for _, name := range sortedKeys(hbs) {
+		peers := cur.Heartbeats[name].Peers
+		names := make(map[string]interface{})
+		for peer := range peers {
+			names[peer] = nil
+		}
+		for _, peer := range sortedKeys(names) {
+			before, ok := prev.Heartbeats[name].Peers[peer]
+			if !ok {
+				continue
+			}
+			if before.Beating && !peers[peer].Beating {
+				l = append(l, Event{Kind: HeartbeatStale, Heartbeat: name, Node: peer})
+			}
+		}
+	}
+	return l
+}
+
+func detectNodeStatus(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	for _, n := range nodeNames(cur) {
+		before, ok := prev.Monitor.Nodes[n]
+		if !ok {
+			continue
+		}
+		after := cur.Monitor.Nodes[n]
+		if after.Monitor.Status != before.Monitor.Status {
+			l = append(l, Event{Kind: NodeStatus, Node: n, From: before.Monitor.Status, To: after.Monitor.Status})
+		}
+	}
+	return l
+}
+
+// detectSplit emits a split event when the cluster splits, with the lost
+// nodes as the comma-separated event node.
+func detectSplit(prev, cur cluster.Status) []Event {
+	l := make([]Event, 0)
+	if cur.Cluster.Split.Split && !prev.Cluster.Split.Split {
+		l = append(l, Event{Kind: Split, Node: strings.Join(cur.Cluster.Split.Lost, ","), From: strings.Join(cur.Cluster.Split.Segment, ",")})
+	}
+	return l
+}
@@ -0,0 +1,140 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// Notifier posts an event to a notification endpoint.
+	Notifier interface {
+		Post(ctx context.Context, e Event) error
+	}
+
+	// Filter selects, formats and rate limits the events posted by a
+	// notifier.
+	Filter struct {
+		// Name identifies the notifier in the logs.
+		Name string
+
+		// Kinds is the list of event kinds to post. Empty means the
+		// notifier default kinds.
+		Kinds []string
+
+		// Template formats the notification text from an Event. Nil means
+		// the Event.Text() description.
+		Template *template.Template
+
+		defaultKinds []string
+		limiter      limiter
+	}
+
+	// limiter allows at most limit notifications per interval, counting
+	// the suppressed ones so the next allowed notification can report
+	// them.
+	limiter struct {
+		limit      int
+		interval   time.Duration
+		start      time.Time
+		count      int
+		suppressed int
+	}
+)
+
+func newFilter(name string, defaultKinds []string, limit int, interval time.Duration) Filter {
+	return Filter{
+		Name:         name,
+		defaultKinds: defaultKinds,
+		limiter: limiter{
+			limit:    limit,
+			interval: interval,
+		},
+	}
+}
+
+// ParseTemplate sets the notification text template. The template data is
+// the Event, so {{.Path}} or {{.Text}} are valid template actions.
+func (t *Filter) ParseTemplate(s string) error {
+	tmpl, err := template.New(t.Name).Parse(s)
+	if err != nil {
+		return fmt.Errorf("notifier %s template: %w", t.Name, err)
+	}
+	t.Template = tmpl
+	return nil
+}
+
+// Selects returns true if the notifier posts the events of kind k.
+func (t Filter) Selects(k string) bool {
+	l := t.Kinds
+	if len(l) == 0 {
+		l = t.defaultKinds
+	}
+	for _, s := range l {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Render returns the notification text of the event.
+func (t Filter) Render(e Event) (string, error) {
+	if t.Template == nil {
+		return e.Text(), nil
+	}
+	var b strings.Builder
+	if err := t.Template.Execute(&b, e); err != nil {
+		return "", fmt.Errorf("notifier %s template: %w", t.Name, err)
+	}
+	return b.String(), nil
+}
+
+// admit returns true if the event is selected and the rate limit is not
+// exceeded, and the number of notifications suppressed since the last
+// admitted one.
+func (t *Filter) admit(e Event) (bool, int) {
+	if !t.Selects(e.Kind) {
+		return false, 0
+	}
+	ok, suppressed := t.limiter.allow(e.Time)
+	if !ok {
+		log.Debug().Msgf("notifier %s: rate limit exceeded, suppress %s event", t.Name, e.Kind)
+	}
+	return ok, suppressed
+}
+
+// allow returns true if a notification can be sent at time now, and the
+// number of notifications suppressed since the last allowed one.
+func (t *limiter) allow(now time.Time) (bool, int) {
+	if t.limit <= 0 {
+		return true, 0
+	}
+	if t.start.IsZero() || now.Sub(t.start) >= t.interval {
+		t.start = now
+		t.count = 0
+	}
+	if t.count >= t.limit {
+		t.suppressed++
+		return false, 0
+	}
+	t.count++
+	suppressed := t.suppressed
+	t.suppressed = 0
+	return true, suppressed
+}
+
+// Notify posts the events to the notifiers, logging the delivery errors.
+func Notify(ctx context.Context, notifiers []Notifier, events []Event) {
+	for _, e := range events {
+		for _, n := range notifiers {
+			if err := n.Post(ctx, e); err != nil {
+				log.Error().Err(err).Msgf("notify %s event", e.Kind)
+			}
+		}
+	}
+}
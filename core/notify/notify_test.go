@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/util/snmptrap"
+)
+
+const prevJSON = `{
+	"cluster": {"name": "c1", "nodes": ["n1", "n2"]},
+	"monitor": {
+		"services": {
+			"svc1": {"avail": "up", "overall": "up"},
+			"svc2": {"avail": "up", "overall": "up"}
+		},
+		"nodes": {
+			"n1": {"monitor": {"status": "idle"}, "services": {"status": {"svc1": {"avail": "up", "topology": "failover"}}}},
+			"n2": {"monitor": {"status": "idle"}, "services": {"status": {"svc1": {"avail": "down", "topology": "failover"}}}}
+		}
+	},
+	"hb#1.rx": {"peers": {"n2": {"beating": true}}}
+}`
+
+const curJSON = `{
+	"cluster": {"name": "c1", "nodes": ["n1", "n2"]},
+	"monitor": {
+		"services": {
+			"svc1": {"avail": "up", "overall": "warn"},
+			"svc2": {"avail": "down", "overall": "down", "frozen": "frozen"},
+			"svc3": {"avail": "down", "overall": "down"}
+		},
+		"nodes": {
+			"n1": {"frozen": 1600000000, "monitor": {"status": "draining"}, "services": {"status": {"svc1": {"avail": "down", "topology": "failover"}}}},
+			"n2": {"monitor": {"status": "idle"}, "services": {"status": {"svc1": {"avail": "up", "topology": "failover"}}}}
+		}
+	},
+	"hb#1.rx": {"peers": {"n2": {"beating": false}}}
+}`
+
+func unmarshalStatus(t *testing.T, s string) cluster.Status {
+	var data cluster.Status
+	require.NoError(t, json.Unmarshal([]byte(s), &data))
+	return data
+}
+
+func TestDetect(t *testing.T) {
+	now := time.Unix(1600000100, 0)
+	events := Detect(unmarshalStatus(t, prevJSON), unmarshalStatus(t, curJSON), now)
+	expected := []Event{
+		{Kind: ObjectWarn, Path: "svc1", From: "up", To: "warn"},
+		{Kind: ObjectDown, Path: "svc2", From: "up", To: "down"},
+		{Kind: ObjectAvail, Path: "svc2", From: "up", To: "down"},
+		{Kind: Failover, Path: "svc1", From: "n1", To: "n2"},
+		{Kind: Frozen, Node: "n1"},
+		{Kind: Frozen, Path: "svc2"},
+		{Kind: HeartbeatStale, Heartbeat: "hb#1.rx", Node: "n2"},
+		{Kind: NodeStatus, Node: "n1", From: "idle", To: "draining"},
+		{Kind: Split, Node: "n2", From: "n1"},
+	}
+	for i := range expected {
+		expected[i].Cluster = "c1"
+		expected[i].Time = now
+	}
+	assert.Equal(t, expected, events)
+}
+
+func TestDetectNoChange(t *testing.T) {
+	data := unmarshalStatus(t, curJSON)
+	assert.Empty(t, Detect(data, data, time.Now()))
+}
+
+func TestEventText(t *testing.T) {
+	e := Event{Kind: Failover, Cluster: "c1", Path: "svc1", From: "n1", To: "n2"}
+	assert.Equal(t, "c1: object svc1 failed over from n1 to n2", e.Text())
+}
+
+func TestWebhookPost(t *testing.T) {
+	bodies := make([]map[string]interface{}, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var m map[string]interface{}
+		_ = json.Unmarshal(b, &m)
+		bodies = append(bodies, m)
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1600000000, 0)
+	hook := NewWebhook("test", srv.URL, 1, time.Minute)
+	hook.Slack = true
+	hook.Kinds = []string{ObjectDown}
+	require.NoError(t, hook.ParseTemplate("{{.Path}} is {{.To}}"))
+	ctx := context.Background()
+
+	require.NoError(t, hook.Post(ctx, Event{Kind: ObjectWarn, Path: "svc1", Time: now}))
+	assert.Len(t, bodies, 0, "unselected kind")
+
+	require.NoError(t, hook.Post(ctx, Event{Kind: ObjectDown, Path: "svc1", To: "down", Time: now}))
+	require.NoError(t, hook.Post(ctx, Event{Kind: ObjectDown, Path: "svc2", To: "down", Time: now.Add(time.Second)}))
+	require.NoError(t, hook.Post(ctx, Event{Kind: ObjectDown, Path: "svc3", To: "down", Time: now.Add(time.Minute)}))
+	require.Len(t, bodies, 2, "rate limited")
+	assert.Equal(t, map[string]interface{}{"text": "svc1 is down"}, bodies[0])
+	assert.Equal(t, map[string]interface{}{"text": "svc3 is down (1 notifications suppressed by the rate limit)"}, bodies[1])
+}
+
+func TestWebhookPostPayload(t *testing.T) {
+	var body payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	e := Event{Kind: Frozen, Cluster: "c1", Node: "n1", Time: time.Unix(1600000000, 0).UTC()}
+	hook := NewWebhook("test", srv.URL, 0, 0)
+	require.NoError(t, hook.Post(context.Background(), Event{Kind: NodeStatus}))
+	require.NoError(t, hook.Post(context.Background(), e))
+	assert.Equal(t, payload{Event: e, Text: "c1: node n1 frozen"}, body, "node_status is not a default webhook kind")
+}
+
+func TestWebhookPostError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	hook := NewWebhook("test", srv.URL, 0, 0)
+	err := hook.Post(context.Background(), Event{Kind: Frozen})
+	assert.EqualError(t, err, "webhook test: unexpected response status 500 Internal Server Error")
+}
+
+func TestTrapPost(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	sender, err := snmptrap.New(conn.LocalAddr().String())
+	require.NoError(t, err)
+
+	trap := NewTrap("test", sender, 0, 0)
+	require.NoError(t, trap.Post(context.Background(), Event{Kind: ObjectWarn, Path: "svc1"}))
+	require.NoError(t, trap.Post(context.Background(), Event{Kind: ObjectAvail, Cluster: "c1", Path: "svc1", From: "up", To: "down"}))
+
+	buf := make([]byte, 1500)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	// the object_warn event is not a default trap kind, so the first
+	// received trap is the object_avail one.
+	assert.True(t, bytes.Contains(buf[:n], []byte("c1: object svc1 availability changed from up to down")))
+}
+
+func TestTrapUndefinedKind(t *testing.T) {
+	trap := NewTrap("test", nil, 0, 0)
+	trap.Kinds = []string{"unknown"}
+	err := trap.Post(context.Background(), Event{Kind: "unknown"})
+	assert.EqualError(t, err, "trap test: no notification defined for the unknown event kind")
+}
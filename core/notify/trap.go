@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/util/snmptrap"
+)
+
+type (
+	// Trap sends the events of the selected kinds as snmp traps, defined
+	// by the OPENSVC-MIB.txt module.
+	Trap struct {
+		Filter
+		Sender *snmptrap.T
+	}
+)
+
+// MIBRoot is the OPENSVC-MIB module identity oid, registered under the
+// experimental arc.
+const MIBRoot = "1.3.6.1.3.9114"
+
+var (
+	// trapOIDs maps the event kinds to their OPENSVC-MIB notification
+	// oids.
+	trapOIDs = map[string]string{
+		ObjectWarn:     MIBRoot + ".0.1",
+		ObjectDown:     MIBRoot + ".0.2",
+		Failover:       MIBRoot + ".0.3",
+		Frozen:         MIBRoot + ".0.4",
+		HeartbeatStale: MIBRoot + ".0.5",
+		ObjectAvail:    MIBRoot + ".0.6",
+		NodeStatus:     MIBRoot + ".0.7",
+		Split:          MIBRoot + ".0.8",
+	}
+)
+
+// OPENSVC-MIB notification objects oids
+const (
+	oidEventKind  = MIBRoot + ".1.1.0"
+	oidCluster    = MIBRoot + ".1.2.0"
+	oidPath       = MIBRoot + ".1.3.0"
+	oidNode       = MIBRoot + ".1.4.0"
+	oidHeartbeat  = MIBRoot + ".1.5.0"
+	oidFrom       = MIBRoot + ".1.6.0"
+	oidTo         = MIBRoot + ".1.7.0"
+	oidText       = MIBRoot + ".1.8.0"
+	oidSuppressed = MIBRoot + ".1.9.0"
+)
+
+// NewTrap allocates a snmp trap notifier sending at most limit traps per
+// interval. A zero limit disables the rate limiting.
+func NewTrap(name string, sender *snmptrap.T, limit int, interval time.Duration) *Trap {
+	return &Trap{
+		Filter: newFilter(name, TrapKinds, limit, interval),
+		Sender: sender,
+	}
+}
+
+// Post sends the event trap, unless the event kind is not selected or the
+// rate limit is exceeded.
+func (t *Trap) Post(_ context.Context, e Event) error {
+	ok, suppressed := t.admit(e)
+	if !ok {
+		return nil
+	}
+	trap, err := t.trap(e, suppressed)
+	if err != nil {
+		return err
+	}
+	if err := t.Sender.Send(trap); err != nil {
+		return fmt.Errorf("trap %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+func (t Trap) trap(e Event, suppressed int) (snmptrap.Trap, error) {
+	oid, ok := trapOIDs[e.Kind]
+	if !ok {
+		return snmptrap.Trap{}, fmt.Errorf("trap %s: no notification defined for the %s event kind", t.Name, e.Kind)
+	}
+	text, err := t.Render(e)
+	if err != nil {
+		return snmptrap.Trap{}, err
+	}
+	return snmptrap.Trap{
+		OID: oid,
+		Varbinds: []snmptrap.Varbind{
+			{OID: oidEventKind, Value: e.Kind},
+			{OID: oidCluster, Value: e.Cluster},
+			{OID: oidPath, Value: e.Path},
+			{OID: oidNode, Value: e.Node},
+			{OID: oidHeartbeat, Value: e.Heartbeat},
+			{OID: oidFrom, Value: e.From},
+			{OID: oidTo, Value: e.To},
+			{OID: oidText, Value: text},
+			{OID: oidSuppressed, Value: snmptrap.Gauge32(suppressed)},
+		},
+	}, nil
+}
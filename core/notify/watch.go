@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/util/jsondelta"
+)
+
+type (
+	// Getter fetches the cluster status json document.
+	Getter interface {
+		Get() ([]byte, error)
+	}
+
+	// EventGetter opens the daemon event stream.
+	EventGetter interface {
+		GetRaw() (chan []byte, error)
+	}
+
+	// Watcher follows the daemon event stream, and posts the events
+	// detected on each cluster status change to the notifiers.
+	Watcher struct {
+		Events    EventGetter
+		Status    Getter
+		Notifiers []Notifier
+	}
+)
+
+var (
+	// reconnectMinDelay is the delay before the first event stream
+	// reconnection attempt. It doubles on each failed attempt.
+	reconnectMinDelay = time.Second
+
+	// reconnectMaxDelay is the maximum delay between two event stream
+	// reconnection attempts.
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// Watch follows the event stream until the context is done. The stream is
+// reopened with an exponential backoff when it drops, and the cluster
+// status is resynced from a full status fetch.
+func (t Watcher) Watch(ctx context.Context) error {
+	var data *cluster.Status
+	delay := reconnectMinDelay
+	for {
+		connected, err := t.watch(ctx, &data)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			err = errors.New("event stream closed")
+		}
+		if connected {
+			delay = reconnectMinDelay
+		}
+		log.Warn().Err(err).Msgf("notify: reconnect in %s", delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+// watch detects the events until the event stream drops. It returns true
+// if the event stream was opened. data is the last known cluster status,
+// kept across reconnects so the changes happening while disconnected are
+// notified.
+func (t Watcher) watch(ctx context.Context, data **cluster.Status) (bool, error) {
+	events, err := t.Events.GetRaw()
+	if err != nil {
+		return false, err
+	}
+	b, err := t.Status.Get()
+	if err != nil {
+		return true, errors.Wrap(err, "get status")
+	}
+	seq := jsondelta.NewSequence(b, 0, true)
+	if err := t.update(ctx, seq, data); err != nil {
+		return true, err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case e, ok := <-events:
+			if !ok {
+				return true, nil
+			}
+			evt, err := event.DecodeFromJSON(e)
+			if err != nil {
+				continue
+			}
+			switch evt.Kind {
+			case "full":
+				seq = jsondelta.NewSequence(*evt.Data, evt.ID, true)
+			case "patch":
+				if err := seq.Apply(evt.ID, jsondelta.NewPatch(*evt.Data)); err != nil {
+					// a missed patch makes the data diverge: resync
+					return true, errors.Wrap(err, "handle event")
+				}
+			default:
+				continue
+			}
+			if err := t.update(ctx, seq, data); err != nil {
+				return true, err
+			}
+		}
+	}
+}
+
+// update decodes the sequence document, and notifies the events explaining
+// the change from the previous cluster status.
+func (t Watcher) update(ctx context.Context, seq *jsondelta.Sequence, data **cluster.Status) error {
+	cur := &cluster.Status{}
+	if err := json.Unmarshal(seq.Bytes(), cur); err != nil {
+		return errors.Wrap(err, "unmarshal status")
+	}
+	if *data != nil {
+		Notify(ctx, t.Notifiers, Detect(**data, *cur, time.Now()))
+	}
+	*data = cur
+	return nil
+}
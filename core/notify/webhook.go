@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// Webhook posts the events of the selected kinds to an http endpoint.
+	Webhook struct {
+		Filter
+
+		// URL is the endpoint the events are posted to.
+		URL string
+
+		// Slack, if set, posts a slack-compatible {"text": ...} payload
+		// instead of the event json document.
+		Slack bool
+
+		// Client is the http client posting the events. Nil means a
+		// client with a 10 seconds timeout.
+		Client *http.Client
+	}
+
+	// payload is the json document posted to a non-slack webhook.
+	payload struct {
+		Event      Event  `json:"event"`
+		Text       string `json:"text"`
+		Suppressed int    `json:"suppressed,omitempty"`
+	}
+)
+
+var (
+	defaultClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// NewWebhook allocates a webhook posting to url, at most limit
+// notifications per interval. A zero limit disables the rate limiting.
+func NewWebhook(name, url string, limit int, interval time.Duration) *Webhook {
+	return &Webhook{
+		Filter: newFilter(name, WebhookKinds, limit, interval),
+		URL:    url,
+	}
+}
+
+// Post sends the event to the webhook endpoint, unless the event kind is not
+// selected or the rate limit is exceeded.
+func (t *Webhook) Post(ctx context.Context, e Event) error {
+	ok, suppressed := t.admit(e)
+	if !ok {
+		return nil
+	}
+	text, err := t.Render(e)
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	if t.Slack {
+		if suppressed > 0 {
+			text += fmt.Sprintf(" (%d notifications suppressed by the rate limit)", suppressed)
+		}
+		v = map[string]string{"text": text}
+	} else {
+		v = payload{Event: e, Text: text, Suppressed: suppressed}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := t.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", t.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s: unexpected response status %s", t.Name, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package object
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/objectactionprops"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+	"opensvc.com/opensvc/util/bootid"
+)
+
+// OptsBoot is the options of the Boot object method.
+type OptsBoot struct {
+	OptsGlobal
+	OptsLocking
+	resourceselector.Options
+}
+
+// OptsStartStandby is the options of the StartStandby object method.
+type OptsStartStandby struct {
+	OptsGlobal
+	OptsLocking
+	resourceselector.Options
+	OptForce
+}
+
+//
+// lastBootIDFile is the path of the file storing the identifier of the
+// operating system boot the instance was last booted on.
+//
+func (t *Base) lastBootIDFile() string {
+	return filepath.Join(t.varDir(), "last_boot_id")
+}
+
+func (t *Base) lastBootID() string {
+	b, err := ioutil.ReadFile(t.lastBootIDFile())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+func (t *Base) writeLastBootID(s string) error {
+	return ioutil.WriteFile(t.lastBootIDFile(), []byte(s+"\n"), 0644)
+}
+
+//
+// Boot cleans up the leftovers of the previous node boot and starts the
+// standby resources of the local instance.
+//
+// The daemon calls this method for each instance on startup. In this
+// case the action is skipped if the instance was already booted since
+// the last node reboot, so a daemon restart does not disrupt the
+// running resources.
+//
+func (t *Base) Boot(ctx context.Context, options OptsBoot) error {
+	id, err := bootid.Get()
+	if err != nil {
+		t.log.Warn().Err(err).Msg("get boot id")
+	}
+	if env.HasDaemonOrigin() && id != "" && id == t.lastBootID() {
+		t.log.Debug().Msg("boot: already done since the last node reboot")
+		return nil
+	}
+	ctx = actioncontext.New(ctx, options, objectactionprops.Boot)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv("boot", false)
+	defer t.postActionStatusEval(ctx)
+	err = t.lockedAction("", options.OptsLocking, "boot", func() error {
+		if err := t.masterBoot(ctx); err != nil {
+			return err
+		}
+		ctx := actioncontext.New(ctx, OptsStartStandby{
+			OptsGlobal:  options.OptsGlobal,
+			OptsLocking: options.OptsLocking,
+			Options:     options.Options,
+		}, objectactionprops.StartStandby)
+		return t.masterStartStandby(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return nil
+	}
+	return t.writeLastBootID(id)
+}
+
+func (t *Base) masterBoot(ctx context.Context) error {
+	return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+		if resource.SkipUnimplemented(r, resource.FeatureBoot, "boot") {
+			return nil
+		}
+		t.log.Debug().Str("rid", r.RID()).Msg("boot resource")
+		return r.(resource.Booter).Boot(ctx)
+	})
+}
+
+// StartStandby starts the standby resources of the local instance.
+func (t *Base) StartStandby(ctx context.Context, options OptsStartStandby) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.StartStandby)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv("startstandby", false)
+	defer t.postActionStatusEval(ctx)
+	return t.lockedAction("", options.OptsLocking, "startstandby", func() error {
+		return t.masterStartStandby(ctx)
+	})
+}
+
+func (t *Base) masterStartStandby(ctx context.Context) error {
+	return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+		if !r.IsStandby() {
+			return nil
+		}
+		t.log.Debug().Str("rid", r.RID()).Msg("start standby resource")
+		return resource.Start(ctx, r)
+	})
+}
@@ -0,0 +1,56 @@
+package object
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/bootid"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+func TestBoot(t *testing.T) {
+	id, err := bootid.Get()
+	if err != nil || id == "" {
+		t.Skip("no boot id on this system")
+	}
+	root, err := ioutil.TempDir("", "boot")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	p, _ := path.Parse("svc1")
+	cf := filepath.Join(root, "etc", "svc1.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cf), os.ModePerm))
+	b := []byte("[DEFAULT]\nnodes = " + hostname.Hostname() + "\n")
+	require.NoError(t, ioutil.WriteFile(cf, b, 0644))
+
+	t.Run("operator boot records the boot id", func(t *testing.T) {
+		o := NewSvc(p)
+		require.NoError(t, o.Boot(context.Background(), OptsBoot{}))
+		assert.Equal(t, id, o.lastBootID())
+	})
+
+	t.Run("daemon boot is skipped until the next node reboot", func(t *testing.T) {
+		os.Setenv("OSVC_ACTION_ORIGIN", "daemon")
+		defer os.Unsetenv("OSVC_ACTION_ORIGIN")
+		o := NewSvc(p)
+		require.NoError(t, o.writeLastBootID("stale"))
+		require.NoError(t, o.Boot(context.Background(), OptsBoot{}))
+		assert.Equal(t, id, o.lastBootID())
+		fi, err := os.Stat(o.lastBootIDFile())
+		require.NoError(t, err)
+		require.NoError(t, o.Boot(context.Background(), OptsBoot{}))
+		fi2, err := os.Stat(o.lastBootIDFile())
+		require.NoError(t, err)
+		assert.Equal(t, fi.ModTime(), fi2.ModTime())
+	})
+}
@@ -22,8 +22,8 @@ type OptsProvision struct {
 }
 
 // Provision allocates and starts the local instance of the object
-func (t *Base) Provision(options OptsProvision) error {
-	ctx := actioncontext.New(options, objectactionprops.Provision)
+func (t *Base) Provision(ctx context.Context, options OptsProvision) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.Provision)
 	if err := t.validateAction(); err != nil {
 		return err
 	}
@@ -0,0 +1,40 @@
+package object
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/objectactionprops"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+)
+
+// OptsRun is the options of the Run object method.
+type OptsRun struct {
+	OptsGlobal
+	OptsLocking
+	resourceselector.Options
+	OptConfirm
+}
+
+//
+// Run executes the selected task resources of the local instance. The
+// tasks configured with confirmation=true are refused unless the confirm
+// option is set.
+//
+// The run lock group is used, so a long running task does not block the
+// other actions.
+//
+func (t *Base) Run(ctx context.Context, options OptsRun) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.Run)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv("run", false)
+	return t.lockedAction("run", options.OptsLocking, "run", func() error {
+		return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+			t.log.Debug().Str("rid", r.RID()).Msg("run resource")
+			return resource.Run(ctx, r)
+		})
+	})
+}
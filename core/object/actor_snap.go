@@ -0,0 +1,108 @@
+package object
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/objectactionprops"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+)
+
+type (
+	// OptsSnapCreate is the options of the SnapCreate object method.
+	OptsSnapCreate struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		Name string `flag:"snapname"`
+	}
+
+	// OptsSnapRollback is the options of the SnapRollback object method.
+	OptsSnapRollback struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		Name string `flag:"snapname"`
+	}
+
+	// OptsSnapPrune is the options of the SnapPrune object method.
+	OptsSnapPrune struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		Keep int `flag:"snapkeep"`
+	}
+)
+
+// SnapCreate creates the named snapshot of the selected snapshot-capable
+// resources.
+func (t *Base) SnapCreate(ctx context.Context, options OptsSnapCreate) error {
+	if err := resource.ValidateSnapName(options.Name); err != nil {
+		return err
+	}
+	ctx = actioncontext.New(ctx, options, objectactionprops.SnapCreate)
+	return t.snapAction(ctx, options.OptsLocking, func(ctx context.Context, r resource.Snapshotter) error {
+		return r.SnapCreate(ctx, options.Name)
+	})
+}
+
+// SnapRollback restores the data of the selected snapshot-capable
+// resources from the named snapshot.
+func (t *Base) SnapRollback(ctx context.Context, options OptsSnapRollback) error {
+	if err := resource.ValidateSnapName(options.Name); err != nil {
+		return err
+	}
+	ctx = actioncontext.New(ctx, options, objectactionprops.SnapRollback)
+	return t.snapAction(ctx, options.OptsLocking, func(ctx context.Context, r resource.Snapshotter) error {
+		return r.SnapRollback(ctx, options.Name)
+	})
+}
+
+// SnapPrune removes the snapshots of the selected snapshot-capable
+// resources, except the options.Keep most recent ones.
+func (t *Base) SnapPrune(ctx context.Context, options OptsSnapPrune) error {
+	if options.Keep < 0 {
+		return fmt.Errorf("invalid number of snapshots to keep: %d", options.Keep)
+	}
+	ctx = actioncontext.New(ctx, options, objectactionprops.SnapPrune)
+	return t.snapAction(ctx, options.OptsLocking, func(ctx context.Context, r resource.Snapshotter) error {
+		l, err := r.Snaps(ctx)
+		if err != nil {
+			return err
+		}
+		if len(l) <= options.Keep {
+			return nil
+		}
+		for _, snap := range l[:len(l)-options.Keep] {
+			if err := r.SnapRemove(ctx, snap.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *Base) snapAction(ctx context.Context, options OptsLocking, fn func(context.Context, resource.Snapshotter) error) error {
+	props := actioncontext.Props(ctx)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv(props.Name, false)
+	return t.lockedAction("", options, props.Name, func() error {
+		return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+			if resource.SkipUnimplemented(r, resource.FeatureSnap, props.Name) {
+				return nil
+			}
+			t.log.Debug().Str("rid", r.RID()).Msgf("%s resource", props.Name)
+			err := fn(ctx, r.(resource.Snapshotter))
+			if errors.Is(err, resource.ErrSnapNotSupported) {
+				r.Log().Info().Msgf("skip: %s", err)
+				return nil
+			}
+			return err
+		})
+	})
+}
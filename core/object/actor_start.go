@@ -2,6 +2,7 @@ package object
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -23,8 +24,8 @@ type OptsStart struct {
 }
 
 // Start starts the local instance of the object
-func (t *Base) Start(options OptsStart) error {
-	ctx := actioncontext.New(options, objectactionprops.Start)
+func (t *Base) Start(ctx context.Context, options OptsStart) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.Start)
 	if err := t.validateAction(); err != nil {
 		return err
 	}
@@ -48,24 +49,29 @@ func (t *Base) lockedStart(ctx context.Context) error {
 	return nil
 }
 
-func (t Base) abortWorker(ctx context.Context, r resource.Driver, q chan bool, wg *sync.WaitGroup) {
+func (t Base) abortWorker(ctx context.Context, r resource.Driver, q chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
-	a, ok := r.(resource.Aborter)
-	if !ok {
-		q <- false
+	if resource.SkipUnimplemented(r, resource.FeatureAbort, "abort") {
 		return
 	}
-	if a.Abort(ctx) {
-		t.log.Error().Str("rid", r.RID()).Msg("abort start")
-		q <- true
-		return
+	if err := r.(resource.Aborter).Abort(ctx); err != nil {
+		var e resource.ErrAbortStart
+		if !errors.As(err, &e) {
+			e = resource.ErrAbortStart{Reason: err.Error()}
+		}
+		e.RID = r.RID()
+		q <- e
 	}
-	q <- false
 }
 
+//
+// abortStart asks the Aborter drivers if the start must be vetoed.
+// The vetoes of a forceable class are bypassed with --force, the others
+// are returned as an ErrsAbortStart, listing the reasons.
+//
 func (t *Base) abortStart(ctx context.Context) (err error) {
 	t.log.Debug().Msg("abort start check")
-	q := make(chan bool, len(t.Resources()))
+	q := make(chan error, len(t.Resources()))
 	var wg sync.WaitGroup
 	for _, r := range t.Resources() {
 		if r.IsDisabled() {
@@ -75,12 +81,20 @@ func (t *Base) abortStart(ctx context.Context) (err error) {
 		go t.abortWorker(ctx, r, q, &wg)
 	}
 	wg.Wait()
-	var ret bool
-	for range t.Resources() {
-		ret = ret || <-q
+	close(q)
+	errs := make(resource.ErrsAbortStart, 0)
+	for err := range q {
+		e := err.(resource.ErrAbortStart)
+		if e.Class.IsForceable() && actioncontext.IsForce(ctx) {
+			t.log.Warn().Str("rid", e.RID).Str("class", e.Class.String()).Msgf("abort start bypassed by --force: %s", e.Reason)
+			continue
+		}
+		t.log.Error().Str("rid", e.RID).Str("class", e.Class.String()).Msgf("abort start: %s", e.Reason)
+		errs = append(errs, e)
 	}
-	if ret {
-		return errors.New("abort start")
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].RID < errs[j].RID })
+		return errs
 	}
 	return nil
 }
@@ -20,8 +20,8 @@ type OptsStop struct {
 }
 
 // Stop stops the local instance of the object
-func (t *Base) Stop(options OptsStop) error {
-	ctx := actioncontext.New(options, objectactionprops.Stop)
+func (t *Base) Stop(ctx context.Context, options OptsStop) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.Stop)
 	if err := t.validateAction(); err != nil {
 		return err
 	}
@@ -0,0 +1,117 @@
+package object
+
+import (
+	"context"
+	"fmt"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/objectactionprops"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+	"opensvc.com/opensvc/core/status"
+)
+
+type (
+	// OptsSyncFull is the options of the SyncFull object method.
+	OptsSyncFull struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		OptForce
+		OptTarget
+	}
+
+	// OptsSyncUpdate is the options of the SyncUpdate object method.
+	OptsSyncUpdate struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		OptForce
+		OptTarget
+	}
+
+	// OptsSyncResync is the options of the SyncResync object method.
+	OptsSyncResync struct {
+		OptsGlobal
+		OptsLocking
+		resourceselector.Options
+		OptForce
+		OptTarget
+	}
+)
+
+// syncTargets are the valid values of the sync target option.
+var syncTargets = map[string]interface{}{
+	"nodes":    nil,
+	"drpnodes": nil,
+}
+
+// SyncFull copies all the data of the selected sync resources to their targets.
+func (t *Base) SyncFull(ctx context.Context, options OptsSyncFull) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.SyncFull)
+	return t.sync(ctx, options.OptsLocking, resource.SyncFull)
+}
+
+// SyncUpdate copies the data changed since the last sync of the selected
+// sync resources to their targets.
+func (t *Base) SyncUpdate(ctx context.Context, options OptsSyncUpdate) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.SyncUpdate)
+	return t.sync(ctx, options.OptsLocking, resource.SyncUpdate)
+}
+
+// SyncResync reestablishes the replication of the selected sync resources.
+func (t *Base) SyncResync(ctx context.Context, options OptsSyncResync) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.SyncResync)
+	return t.sync(ctx, options.OptsLocking, resource.SyncResync)
+}
+
+//
+// sync executes the sync action on the selected sync resources. The
+// data source is the instance the object is up on, so the action is
+// skipped on the other instances, unless forced.
+//
+// The sync lock group is used, so a long running sync does not block
+// the other actions.
+//
+func (t *Base) sync(ctx context.Context, options OptsLocking, fn func(context.Context, resource.Driver) error) error {
+	props := actioncontext.Props(ctx)
+	for _, target := range actioncontext.Targets(ctx) {
+		if _, ok := syncTargets[target]; !ok {
+			return fmt.Errorf("invalid sync target '%s': valid targets are nodes, drpnodes", target)
+		}
+	}
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	if !t.isSyncLeader() {
+		if !actioncontext.IsForce(ctx) {
+			t.log.Info().Msgf("skip %s: the local instance is not leader", props.Name)
+			return nil
+		}
+		t.log.Warn().Msgf("%s forced on a non-leader instance", props.Name)
+	}
+	t.setenv(props.Name, false)
+	return t.lockedAction("sync", options, props.Name, func() error {
+		return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+			t.log.Debug().Str("rid", r.RID()).Msgf("%s resource", props.Name)
+			return fn(ctx, r)
+		})
+	})
+}
+
+// isSyncLeader returns true if the local instance is the sync data
+// source: the instance is up, or has no resource contributing to the
+// availability status.
+func (t *Base) isSyncLeader() bool {
+	data, err := t.Status(OptsStatus{})
+	if err != nil {
+		t.log.Debug().Err(err).Msg("sync leader")
+		return false
+	}
+	switch data.Avail {
+	case status.Up, status.NotApplicable:
+		return true
+	default:
+		return false
+	}
+}
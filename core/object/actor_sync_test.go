@@ -0,0 +1,36 @@
+package object
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestSyncTargets(t *testing.T) {
+	root, err := ioutil.TempDir("", "sync")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[DEFAULT]\nid = 5f0a1a8e-1d8a-4a3e-9d5b-2d1cba0a1d3e\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "s1.conf"), b, 0644))
+	p, _ := path.Parse("s1")
+	o := NewSvc(p)
+
+	options := OptsSyncUpdate{}
+	options.Target = []string{"drpnodes", "peers"}
+	err = o.SyncUpdate(context.Background(), options)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid sync target 'peers'")
+
+	options.Target = []string{"nodes", "drpnodes"}
+	assert.NoError(t, o.SyncUpdate(context.Background(), options))
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/key"
+)
+
+// OptsTOC is the options of the TOC object method.
+type OptsTOC struct {
+	OptsGlobal
+	OptsLocking
+}
+
+// MonitorAction returns the action to take when a monitored resource fails hard.
+func (t Base) MonitorAction() string {
+	k := key.Parse("monitor_action")
+	return t.config.GetString(k)
+}
+
+// PreMonitorAction returns the command to execute before the monitor action.
+func (t Base) PreMonitorAction() string {
+	k := key.Parse("pre_monitor_action")
+	return t.config.GetString(k)
+}
+
+//
+// TOC takes over control of the local instance when a monitored resource
+// fails hard, executing the configured monitor_action.
+//
+// The pre_monitor_action command is executed first. Its failure is
+// logged but does not prevent the monitor action.
+//
+func (t *Base) TOC(ctx context.Context, options OptsTOC) error {
+	t.setenv("toc", false)
+	t.preMonitorAction()
+	switch s := t.MonitorAction(); s {
+	case "":
+		t.log.Info().Msg("toc: no monitor_action configured")
+		return nil
+	case "freezestop":
+		t.log.Info().Msg("toc: freeze and stop the instance")
+		if err := t.Freeze(); err != nil {
+			return err
+		}
+		return t.Stop(ctx, OptsStop{OptsGlobal: options.OptsGlobal, OptsLocking: options.OptsLocking})
+	case "switch":
+		t.log.Info().Msg("toc: stop the instance for the daemon to orchestrate the failover")
+		return t.Stop(ctx, OptsStop{OptsGlobal: options.OptsGlobal, OptsLocking: options.OptsLocking})
+	case "reboot":
+		t.log.Info().Msg("toc: reboot the node")
+		return NewNode().Reboot(OptsNodeReboot{OptForce: OptForce{Force: true}})
+	case "crash":
+		t.log.Info().Msg("toc: crash the node")
+		return NewNode().Crash()
+	default:
+		return errors.Errorf("toc: invalid monitor_action %s", s)
+	}
+}
+
+func (t *Base) preMonitorAction() {
+	s := t.PreMonitorAction()
+	if s == "" {
+		return
+	}
+	args, err := command.CmdArgsFromString(s)
+	if err != nil {
+		t.log.Error().Err(err).Msg("pre_monitor_action")
+		return
+	}
+	t.log.Info().Msgf("pre_monitor_action: %s", s)
+	cmd := command.New(
+		command.WithName(args[0]),
+		command.WithVarArgs(args[1:]...),
+		command.WithLogger(&t.log),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	if err := cmd.Run(); err != nil {
+		t.log.Error().Err(err).Msg("pre_monitor_action")
+	}
+}
@@ -0,0 +1,55 @@
+package object
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+func TestTOC(t *testing.T) {
+	root, err := ioutil.TempDir("", "toc")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	p, _ := path.Parse("svc1")
+	cf := filepath.Join(root, "etc", "svc1.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cf), os.ModePerm))
+	flagFile := filepath.Join(root, "pre_monitor_action")
+	write := func(monitorAction string) {
+		b := []byte("[DEFAULT]\nnodes = " + hostname.Hostname() + "\n" +
+			"monitor_action = " + monitorAction + "\n" +
+			"pre_monitor_action = touch " + flagFile + "\n")
+		require.NoError(t, ioutil.WriteFile(cf, b, 0644))
+	}
+
+	t.Run("no monitor_action only executes the pre_monitor_action", func(t *testing.T) {
+		write("")
+		o := NewSvc(p)
+		require.NoError(t, o.TOC(context.Background(), OptsTOC{}))
+		assert.FileExists(t, flagFile)
+		assert.True(t, o.Frozen().IsZero())
+	})
+
+	t.Run("freezestop freezes the instance", func(t *testing.T) {
+		write("freezestop")
+		o := NewSvc(p)
+		require.NoError(t, o.TOC(context.Background(), OptsTOC{}))
+		assert.False(t, o.Frozen().IsZero())
+	})
+
+	t.Run("invalid monitor_action", func(t *testing.T) {
+		write("foo")
+		assert.Error(t, NewSvc(p).TOC(context.Background(), OptsTOC{}))
+	})
+}
@@ -21,8 +21,8 @@ type OptsUnprovision struct {
 }
 
 // Unprovision stops and frees the local instance of the object
-func (t *Base) Unprovision(options OptsUnprovision) error {
-	ctx := actioncontext.New(options, objectactionprops.Unprovision)
+func (t *Base) Unprovision(ctx context.Context, options OptsUnprovision) error {
+	ctx = actioncontext.New(ctx, options, objectactionprops.Unprovision)
 	if err := t.validateAction(); err != nil {
 		return err
 	}
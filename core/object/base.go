@@ -66,16 +66,7 @@ func (t *Base) init(p path.T, opts ...funcopt.O) error {
 		t.log.Debug().Msgf("%s init error: %s", t, err)
 		return err
 	}
-	t.log = logging.Configure(logging.Config{
-		ConsoleLoggingEnabled: true,
-		EncodeLogsAsJSON:      true,
-		FileLoggingEnabled:    true,
-		Directory:             t.logDir(),
-		Filename:              t.Path.String() + ".log",
-		MaxSize:               5,
-		MaxBackups:            1,
-		MaxAge:                30,
-	}).
+	t.log = logging.Configure(newLogConfig(t.logDir(), t.Path.Name+".log")).
 		With().
 		Stringer("o", t.Path).
 		Str("n", hostname.Hostname()).
@@ -383,21 +374,30 @@ func (t Base) TmpDir() string {
 
 //
 // LogDir returns the directory on the local filesystem where the object
-// stores its temporary files.
+// stores its log files: <log>/<namespace> for services, and
+// <log>/<namespace>/<kind> for the other kinds, the root namespace
+// included.
 //
 func (t Base) LogDir() string {
-	p := t.Path.String()
-	switch {
-	case t.Path.Namespace != "", t.Path.Namespace != "root":
-		p = fmt.Sprintf("%s/namespaces/%s/%s", rawconfig.Node.Paths.Log, t.Path.Namespace, t.Path.Kind)
-	case t.Path.Kind == kind.Svc, t.Path.Kind == kind.Ccfg:
-		p = fmt.Sprintf("%s", rawconfig.Node.Paths.Log)
-	default:
-		p = fmt.Sprintf("%s/%s", rawconfig.Node.Paths.Log, t.Path.Kind)
+	namespace := t.Path.Namespace
+	if namespace == "" {
+		namespace = "root"
+	}
+	p := fmt.Sprintf("%s/%s", rawconfig.Node.Paths.Log, namespace)
+	if t.Path.Kind != kind.Svc {
+		p = fmt.Sprintf("%s/%s", p, t.Path.Kind)
 	}
 	return filepath.FromSlash(p)
 }
 
+//
+// LogFile returns the path of the object log file. The rotated log files
+// are in the same directory.
+//
+func (t Base) LogFile() string {
+	return filepath.Join(t.LogDir(), t.Path.Name+".log")
+}
+
 //
 // Node returns a cache Node struct pointer. If none is already cached,
 // allocate a new Node{} and cache it.
@@ -32,9 +32,14 @@ type (
 var (
 	ErrInvalidNode = errors.New("invalid node")
 	ErrLogged      = errors.New("already logged")
+	ErrNodeFrozen  = errors.New("node is frozen")
 )
 
 func (t *Base) validateAction() error {
+	if env.HasDaemonOrigin() && !NewNode().Frozen().IsZero() {
+		// the operator actions are still allowed on a frozen node
+		return errors.Wrap(ErrNodeFrozen, "refuse the daemon orchestrated action")
+	}
 	if t.Env() != "PRD" && rawconfig.Node.Node.Env == "PRD" {
 		return errors.Wrapf(ErrInvalidNode, "not allowed to run on this node (svc env=%s node env=%s)", t.Env(), rawconfig.Node.Node.Env)
 	}
@@ -44,7 +49,10 @@ func (t *Base) validateAction() error {
 	if t.config.IsInDRPNodes(hostname.Hostname()) {
 		return nil
 	}
-	return errors.Wrapf(ErrInvalidNode, "hostname '%s' is not a member of DEFAULT.nodes, DEFAULT.drpnode nor DEFAULT.drpnodes", hostname.Hostname())
+	if t.config.IsInEncapNodes(hostname.Hostname()) {
+		return nil
+	}
+	return errors.Wrapf(ErrInvalidNode, "hostname '%s' is not a member of DEFAULT.nodes, DEFAULT.drpnode, DEFAULT.drpnodes nor DEFAULT.encapnodes", hostname.Hostname())
 }
 
 func (t *Base) setenv(action string, leader bool) {
@@ -134,7 +142,7 @@ func (t *Base) action(ctx context.Context, fn resourceset.DoFunc) error {
 		sb.Post(r.RID(), resource.Status(ctx, r), false)
 		return nil
 	})
-	if err := t.ResourceSets().Do(ctx, l, b, fn); err != nil {
+	if err := t.doResources(ctx, l, b, fn); err != nil {
 		if !errors.Is(err, ErrLogged) {
 			// avoid logging multiple times the same error.
 			// worst case is an error in a volume object started by
@@ -154,6 +162,33 @@ func (t *Base) action(ctx context.Context, fn resourceset.DoFunc) error {
 	return nil
 }
 
+//
+// doResources applies fn to the resources in scope. The action is also
+// forwarded to the encap agents: before the hypervisor resources for the
+// descending actions like stop, after for the ascending actions like
+// start, so the containers are up when the encap agents are called.
+//
+func (t *Base) doResources(ctx context.Context, l resourceset.ResourceLister, b string, fn resourceset.DoFunc) error {
+	desc := l.IsDesc()
+	if desc {
+		if err := t.encapAction(ctx); err != nil {
+			return err
+		}
+	}
+	if err := t.ResourceSets().Do(ctx, l, b, func(ctx context.Context, r resource.Driver) error {
+		if !t.isResourceInScope(r) {
+			return nil
+		}
+		return fn(ctx, r)
+	}); err != nil {
+		return err
+	}
+	if !desc {
+		return t.encapAction(ctx)
+	}
+	return nil
+}
+
 func (t *Base) notifyAction(ctx context.Context) error {
 	if env.HasDaemonOrigin() {
 		return nil
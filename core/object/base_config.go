@@ -14,9 +14,9 @@ import (
 	"opensvc.com/opensvc/core/placement"
 	"opensvc.com/opensvc/core/priority"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/topology"
 	"opensvc.com/opensvc/core/xconfig"
-	"opensvc.com/opensvc/util/device"
 	"opensvc.com/opensvc/util/hostname"
 	"opensvc.com/opensvc/util/key"
 )
@@ -34,6 +34,7 @@ func (t *Base) loadConfig() error {
 	t.config.Path = t.Path
 	t.config.Referrer = t
 	t.config.NodeReferrer = t.Node()
+	t.config.HistoryDir = filepath.Join(t.VarDir(), "config_history")
 	return err
 }
 
@@ -210,9 +211,6 @@ func (t Base) FlexTarget() int {
 
 func (t Base) dereferenceExposedDevices(ref string) (string, error) {
 	l := strings.SplitN(ref, ".", 2)
-	type ExposedDeviceser interface {
-		ExposedDevices() []*device.T
-	}
 	if len(l) != 2 {
 		return ref, fmt.Errorf("misformatted exposed_devs ref: %s", ref)
 	}
@@ -225,7 +223,7 @@ func (t Base) dereferenceExposedDevices(ref string) (string, error) {
 			return ref, fmt.Errorf("resource referenced by %s not found", ref)
 		}
 	}
-	o, ok := r.(ExposedDeviceser)
+	o, ok := r.(resource.ExposedDeviceser)
 	if !ok {
 		return ref, fmt.Errorf("resource referenced by %s has no exposed devices", ref)
 	}
@@ -331,6 +329,15 @@ func (t Base) EncapNodes() []string {
 	return l.([]string)
 }
 
+//
+// isResourceInScope returns true if the resource is handled in the local
+// node context: encap resources on encap nodes, the other resources on
+// the hypervisor nodes.
+//
+func (t Base) isResourceInScope(r resource.Driver) bool {
+	return r.IsEncap() == t.config.IsInEncapNodes(hostname.Hostname())
+}
+
 func (t Base) PostCommit() error {
 	return nil
 }
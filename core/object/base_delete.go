@@ -1,10 +1,15 @@
 package object
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/resourceselector"
 	"opensvc.com/opensvc/util/file"
 )
 
@@ -18,19 +23,42 @@ type OptsDelete struct {
 //
 // Delete is the 'delete' object action entrypoint.
 //
+// If the unprovision option is set, unprovision the selected resources
+// first, or all resources if no resource selector is set.
+//
 // If no resource selector is set, remove all etc, var and log
-// file belonging to the object.
+// file belonging to the object, and deregister the instance from
+// the daemon.
 //
 // If a resource selector is set, only delete the corresponding
 // sections in the configuration file.
 //
 func (t Base) Delete(opts OptsDelete) error {
+	if opts.Unprovision {
+		if err := t.deleteUnprovision(opts); err != nil {
+			return err
+		}
+	}
 	if opts.ResourceSelector != "" {
 		return t.deleteSections(opts.ResourceSelector)
 	}
 	return t.deleteInstance()
 }
 
+func (t Base) deleteUnprovision(opts OptsDelete) error {
+	if !kind.Or(kind.Svc, kind.Vol).Has(t.Path.Kind) {
+		// no resources to unprovision
+		return nil
+	}
+	return t.Unprovision(context.Background(), OptsUnprovision{
+		OptsGlobal:  opts.Global,
+		OptsLocking: opts.Lock,
+		Options: resourceselector.Options{
+			RID: opts.ResourceSelector,
+		},
+	})
+}
+
 func (t Base) deleteInstance() error {
 	if err := t.deleteInstanceFiles(); err != nil {
 		return err
@@ -42,9 +70,28 @@ func (t Base) deleteInstance() error {
 		t.log.Warn().Err(err).Msg("")
 		return nil
 	}
+	if err := t.deregister(); err != nil {
+		t.log.Debug().Err(err).Msg("deregister from the daemon")
+	}
 	return nil
 }
 
+// deregister asks the daemon to drop the deleted instance from its
+// cluster data, instead of waiting for its next configuration scan.
+func (t Base) deregister() error {
+	if env.HasDaemonOrigin() {
+		return nil
+	}
+	c, err := client.New()
+	if err != nil {
+		return err
+	}
+	req := c.NewPostObjectDeregister()
+	req.Path = t.Path.String()
+	_, err = req.Do()
+	return err
+}
+
 func (t Base) deleteInstanceFiles() error {
 	patterns := []string{
 		filepath.Join(t.logDir(), t.Path.Name+".log*"),
@@ -0,0 +1,50 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestDelete(t *testing.T) {
+	root, err := ioutil.TempDir("", "delete")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	p, _ := path.Parse("svc1")
+	cf := filepath.Join(root, "etc", "svc1.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(cf), os.ModePerm))
+	b := []byte(`[DEFAULT]
+nodes = node1
+
+[fs#1]
+type = flag
+
+[fs#2]
+type = flag
+`)
+	require.NoError(t, ioutil.WriteFile(cf, b, 0644))
+
+	t.Run("delete the selected sections", func(t *testing.T) {
+		o := NewSvc(p)
+		require.NoError(t, o.Delete(OptsDelete{ResourceSelector: "fs#1"}))
+		o = NewSvc(p)
+		assert.False(t, o.Config().HasSectionString("fs#1"))
+		assert.True(t, o.Config().HasSectionString("fs#2"))
+	})
+
+	t.Run("delete the config file", func(t *testing.T) {
+		require.NoError(t, NewSvc(p).Delete(OptsDelete{}))
+		_, err := os.Stat(cf)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,179 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+// encapAgent is the agent command executed in the containers.
+var encapAgent = "om"
+
+func (t *Base) hasEncapResources() bool {
+	for _, r := range t.Resources() {
+		if r.IsEncap() {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// encapContainers returns the container resources hosting an agent
+// driving the encap resources. The list is empty on the encap nodes, and
+// if the object has no encap resource.
+//
+func (t *Base) encapContainers() resource.Drivers {
+	l := make(resource.Drivers, 0)
+	if t.config.IsInEncapNodes(hostname.Hostname()) || !t.hasEncapResources() {
+		return l
+	}
+	for _, r := range t.Resources() {
+		if r.IsEncap() {
+			continue
+		}
+		if _, ok := r.(resource.Encaper); ok {
+			l = append(l, r)
+		}
+	}
+	return l
+}
+
+//
+// encapRawConfig returns the configuration installed in the containers:
+// the sections of the resources handled by the hypervisor nodes are
+// removed.
+//
+func (t *Base) encapRawConfig() rawconfig.T {
+	raw := t.config.Raw()
+	for _, r := range t.Resources() {
+		if !r.IsEncap() {
+			raw.Data.Delete(r.RID())
+		}
+	}
+	return raw
+}
+
+// encapPushConfig installs the encap configuration in the container, so
+// the encap agent acts on the current configuration.
+func (t *Base) encapPushConfig(ctx context.Context, r resource.Driver) error {
+	b, err := json.Marshal(t.encapRawConfig())
+	if err != nil {
+		return err
+	}
+	args := []string{encapAgent, t.Path.String(), "create", "--config", "-", "--restore"}
+	if _, err := r.(resource.Encaper).EncapCmd(ctx, args, bytes.NewReader(b)); err != nil {
+		return fmt.Errorf("%s: push the encap configuration: %w", r.RID(), err)
+	}
+	return nil
+}
+
+// encapSelectedRIDs returns the encap resources ids selected by the
+// action context.
+func (t *Base) encapSelectedRIDs(ctx context.Context) []string {
+	l := make([]string, 0)
+	for _, r := range resourceselector.FromContext(ctx, t).Resources() {
+		if r.IsEncap() {
+			l = append(l, r.RID())
+		}
+	}
+	return l
+}
+
+//
+// encapAction forwards the action to the agents of the up containers,
+// after the installation of the current encap configuration. The action
+// resource selection is forwarded too, limited to the encap resources.
+//
+func (t *Base) encapAction(ctx context.Context) error {
+	props := actioncontext.Props(ctx)
+	if !props.Encap {
+		return nil
+	}
+	containers := t.encapContainers()
+	if len(containers) == 0 {
+		return nil
+	}
+	args := []string{encapAgent, t.Path.String(), props.Name, "--local"}
+	if !resourceselector.OptionsFromContext(ctx).IsZero() {
+		rids := t.encapSelectedRIDs(ctx)
+		if len(rids) == 0 {
+			return nil
+		}
+		args = append(args, "--rid", strings.Join(rids, ","))
+	}
+	for _, r := range containers {
+		if s := resource.Status(ctx, r); s != status.Up {
+			t.log.Debug().Str("rid", r.RID()).Msgf("skip encap %s: container is %s", props.Name, s)
+			continue
+		}
+		if actioncontext.IsDryRun(ctx) {
+			t.log.Info().Str("rid", r.RID()).Msgf("dry run: encap %s", strings.Join(args, " "))
+			continue
+		}
+		if err := t.encapPushConfig(ctx, r); err != nil {
+			return err
+		}
+		t.log.Info().Str("rid", r.RID()).Msgf("encap %s", strings.Join(args, " "))
+		b, err := r.(resource.Encaper).EncapCmd(ctx, args, nil)
+		if err != nil {
+			return fmt.Errorf("%s: encap %s: %w", r.RID(), props.Name, err)
+		}
+		t.log.Debug().Str("rid", r.RID()).Msgf("encap %s output: %s", props.Name, b)
+	}
+	return nil
+}
+
+// encapStatus returns the instance status evaluated by the agent of the
+// container.
+func (t *Base) encapStatus(ctx context.Context, r resource.Driver) (instance.Status, error) {
+	var l []Status
+	args := []string{encapAgent, t.Path.String(), "print", "status", "--local", "--refresh", "--format", "json"}
+	b, err := r.(resource.Encaper).EncapCmd(ctx, args, nil)
+	if err != nil {
+		return instance.Status{}, err
+	}
+	if err := json.Unmarshal(b, &l); err != nil {
+		return instance.Status{}, err
+	}
+	for _, d := range l {
+		for _, i := range d.Instances {
+			return i.Status, nil
+		}
+	}
+	return instance.Status{}, fmt.Errorf("%s: no encap instance status", r.RID())
+}
+
+//
+// encapStatusEval merges the encap instances status of the up containers
+// into the instance status. The encap instances availability is
+// aggregated in the instance availability.
+//
+func (t *Base) encapStatusEval(ctx context.Context, data *instance.Status) {
+	for _, r := range t.encapContainers() {
+		if xd, ok := data.Resources[r.RID()]; !ok || xd.Status != status.Up {
+			continue
+		}
+		s, err := t.encapStatus(ctx, r)
+		if err != nil {
+			t.log.Debug().Err(err).Str("rid", r.RID()).Msg("encap status")
+			continue
+		}
+		if data.Encap == nil {
+			data.Encap = make(map[string]instance.Status)
+		}
+		data.Encap[r.RID()] = s
+		data.Overall.Add(s.Overall)
+		data.Avail.Add(s.Avail)
+	}
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"fmt"
+
+	"opensvc.com/opensvc/core/resource"
+)
+
+type (
+	// OptsEnter is the options of the Enter object method.
+	OptsEnter struct {
+		Global OptsGlobal
+		RID    string `flag:"rid"`
+	}
+
+	// OptsConsole is the options of the Console object method.
+	OptsConsole struct {
+		Global OptsGlobal
+		RID    string `flag:"rid"`
+	}
+)
+
+//
+// Enter executes an interactive shell in the container resource
+// identified by rid, using the container runtime. The rid can be omitted
+// if the object has a single container supporting this action.
+//
+func (t *Base) Enter(options OptsEnter) error {
+	t.setenv("enter", false)
+	r, err := t.interactiveResource("enter", options.RID, func(r resource.Driver) bool {
+		return resource.Implements(r, resource.FeatureEnter)
+	})
+	if err != nil {
+		return err
+	}
+	return r.(resource.Enterer).Enter()
+}
+
+//
+// Console attaches the serial console of the container resource
+// identified by rid. The rid can be omitted if the object has a single
+// container supporting this action.
+//
+func (t *Base) Console(options OptsConsole) error {
+	t.setenv("console", false)
+	r, err := t.interactiveResource("console", options.RID, func(r resource.Driver) bool {
+		return resource.Implements(r, resource.FeatureConsole)
+	})
+	if err != nil {
+		return err
+	}
+	return r.(resource.Consoler).Console()
+}
+
+// interactiveResource returns the resource supporting the interactive
+// action, identified by rid or guessed when the object has only one
+// candidate.
+func (t *Base) interactiveResource(action, rid string, supports func(resource.Driver) bool) (resource.Driver, error) {
+	candidates := make(resource.Drivers, 0)
+	for _, r := range t.Resources() {
+		if rid != "" && r.RID() != rid {
+			continue
+		}
+		if rid != "" && !supports(r) {
+			return nil, fmt.Errorf("%s: resource %s does not support %s", t.Path, rid, action)
+		}
+		if supports(r) {
+			candidates = append(candidates, r)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		if rid != "" {
+			return nil, fmt.Errorf("%s: resource %s not found", t.Path, rid)
+		}
+		return nil, fmt.Errorf("%s: no resource supports %s", t.Path, action)
+	case 1:
+		r := candidates[0]
+		resource.Setenv(r)
+		return r, nil
+	default:
+		return nil, fmt.Errorf("%s: %d resources support %s, select one with --rid", t.Path, len(candidates), action)
+	}
+}
@@ -1,6 +1,8 @@
 package object
 
 import (
+	"sort"
+
 	"opensvc.com/opensvc/core/envs"
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/kind"
@@ -10,6 +12,7 @@ import (
 	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/xmap"
 )
 
 var keywordStore = keywords.Store{
@@ -44,6 +47,13 @@ var keywordStore = keywords.Store{
 		Text:    "Allow service process to bind only the specified cpus. Cpus are specified as list or range : 0,1,2 or 0-2",
 		Example: "0-2",
 	},
+	{
+		Generic:  true,
+		Option:   "profile",
+		Scopable: true,
+		Example:  "oracle-fs",
+		Text:     "The name of a resource profile defined as a ``[profile#<name>]`` section in the node or cluster configuration. The keywords not set in the section take the profile value, which has precedence over the keyword default.",
+	},
 	{
 		Section:     "DEFAULT",
 		Option:      "nodes",
@@ -151,6 +161,17 @@ var keywordStore = keywords.Store{
 		Candidates: []string{"no", "ha", "start"},
 		Text:       "If set to ``no``, disable service orchestration by the OpenSVC daemon monitor, including service start on boot. If set to ``start`` failover services won't failover automatically, though the service instance on the natural placement leader is started if another instance is not already up. Flex services won't restart the :kw:`flex_target` number of up instances. Resource restart is still active whatever the :kw:`orchestrate` value.",
 	},
+	{
+		Section:    "DEFAULT",
+		Option:     "monitor_action",
+		Candidates: []string{"", "freezestop", "switch", "reboot", "crash"},
+		Text:       "The action to take when a monitored resource is not up nor standby up, and all the resource restart tries failed. The ``freezestop`` action freezes and stops the local instance. The ``switch`` action stops the local instance, leaving the daemon orchestrate the failover. The ``reboot`` and ``crash`` actions reboot or crash the node immediately, so the peer nodes take over the object instances.",
+	},
+	{
+		Section: "DEFAULT",
+		Option:  "pre_monitor_action",
+		Text:    "A script to execute before the :kw:`monitor_action`. For example, if the :kw:`monitor_action` is set to ``freezestop``, the script can decide to crash the node if it detects a situation were the freezestop can not succeed (ex. fs can not be umounted with a dead storage array).",
+	},
 	{
 		Section:   "DEFAULT",
 		Option:    "priority",
@@ -166,6 +187,16 @@ var keywordStore = keywords.Store{
 		Text:      "If set to ``true``, actions are executed in parallel amongst the subset member resources.",
 	},
 
+	// Users
+	{
+		Section:   "DEFAULT",
+		Option:    "grant",
+		Converter: converters.List,
+		Text:      "Grant roles on namespaces to the user. A whitespace separated list of ``<role>[:<namespace pattern>]``, where role is one of ``root``, ``squatter``, ``blacklistadmin``, ``heartbeat``, ``prioritizer`` (cluster roles, no namespace), or ``admin``, ``operator``, ``guest`` (namespace roles). The namespace pattern is a fnmatch expression.",
+		Example:   "admin:test* guest:*",
+		Kind:      kind.Or(kind.Usr),
+	},
+
 	// Secrets
 	{
 		Section:  "DEFAULT",
@@ -173,7 +204,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Common Name.",
 		Example:  "test.opensvc.com",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -181,7 +212,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Country.",
 		Example:  "FR",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -189,7 +220,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request State.",
 		Example:  "Oise",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -197,7 +228,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Location.",
 		Example:  "Gouvieux",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -205,7 +236,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Organization.",
 		Example:  "OpenSVC",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -213,7 +244,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Organizational Unit.",
 		Example:  "Lab",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -221,7 +252,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "Certificate Signing Request Email.",
 		Example:  "test@opensvc.com",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:   "DEFAULT",
@@ -230,7 +261,7 @@ var keywordStore = keywords.Store{
 		Scopable:  true,
 		Text:      "Certificate Signing Request Alternative Domain Names.",
 		Example:   "www.opensvc.com opensvc.com",
-		Kind:      kind.Or(kind.Sec),
+		Kind:      kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:   "DEFAULT",
@@ -240,7 +271,7 @@ var keywordStore = keywords.Store{
 		Text:      "Certificate Private Key Length.",
 		Default:   "4kib",
 		Example:   "8192",
-		Kind:      kind.Or(kind.Sec),
+		Kind:      kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:   "DEFAULT",
@@ -258,7 +289,7 @@ var keywordStore = keywords.Store{
 		Text:      "Certificate Validity duration.",
 		Default:   "1y",
 		Example:   "10y",
-		Kind:      kind.Or(kind.Sec),
+		Kind:      kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -266,7 +297,7 @@ var keywordStore = keywords.Store{
 		Scopable: true,
 		Text:     "The name of secret containing a certificate to use as a Certificate Authority. This secret must be in the same namespace.",
 		Example:  "ca",
-		Kind:     kind.Or(kind.Sec),
+		Kind:     kind.Or(kind.Sec, kind.Usr),
 	},
 	{
 		Section:  "DEFAULT",
@@ -397,3 +428,46 @@ func (t Base) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
 	}
 	return keywords.Keyword{}
 }
+
+//
+// KeywordCandidates returns the sorted names of the keywords supported by
+// an object of kind k, whose resource sections are the keys of the
+// sections map, valued by their driver type (empty for the driver group
+// default). The DEFAULT section keywords are named without section
+// prefix.
+//
+func KeywordCandidates(k kind.T, sections map[string]string) []string {
+	m := make(map[string]interface{})
+	add := func(section, option string) {
+		m[key.New(section, option).String()] = nil
+	}
+	for _, kw := range keywordStore {
+		if !kw.Kind.Has(k) || kw.Section == "subset" {
+			continue
+		}
+		add("DEFAULT", kw.Option)
+	}
+	for section, sectionType := range sections {
+		switch section {
+		case "DEFAULT", "env", "data":
+			continue
+		}
+		driverGroup := resourceid.Parse(section).DriverGroup()
+		if sectionType == "" {
+			sectionType = DefaultDriver[driverGroup.String()]
+		}
+		newDRV := resource.NewDriverID(driverGroup, sectionType).NewResourceFunc()
+		if newDRV == nil {
+			continue
+		}
+		for _, kw := range newDRV().Manifest().Keywords {
+			if !kw.Kind.Has(k) {
+				continue
+			}
+			add(section, kw.Option)
+		}
+	}
+	l := xmap.Keys(m)
+	sort.Strings(l)
+	return l
+}
@@ -0,0 +1,18 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opensvc.com/opensvc/core/kind"
+)
+
+func TestKeywordCandidates(t *testing.T) {
+	l := KeywordCandidates(kind.Svc, map[string]string{"DEFAULT": ""})
+	assert.Contains(t, l, "nodes")
+	assert.Contains(t, l, "orchestrate")
+	for _, s := range l {
+		assert.NotContains(t, s, "DEFAULT.", "DEFAULT keywords are not prefixed")
+	}
+}
@@ -1,14 +1,44 @@
 package object
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/opensvc/fcntllock"
 	"github.com/opensvc/flock"
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/util/waitfor"
 	"opensvc.com/opensvc/util/xsession"
 )
 
+type (
+	// lockMeta is the json content written by flock in the lock file,
+	// hinting about what holds the lock.
+	lockMeta struct {
+		PID       int    `json:"pid"`
+		Intent    string `json:"intent"`
+		SessionID string `json:"session_id"`
+	}
+)
+
+var (
+	// lockQueueTimeout is the lock acquire timeout when queueing is
+	// requested via --queue.
+	lockQueueTimeout = 24 * time.Hour
+
+	// lockPollInterval is the delay between two cluster lock requests
+	// while the daemon refuses the lock.
+	lockPollInterval = time.Second
+)
+
 func (t *Base) lockPath(group string) (path string) {
 	if group == "" {
 		group = "generic"
@@ -29,7 +59,7 @@ func (t *Base) Lock(group string, timeout time.Duration, intent string) (*flock.
 	lock := flock.New(p, xsession.ID, fcntllock.New)
 	err := lock.Lock(timeout, intent)
 	if err != nil {
-		return nil, err
+		return nil, lockError(p, err)
 	}
 	t.log.Debug().Msgf("locked %s", p)
 	return lock, nil
@@ -40,12 +70,117 @@ func (t *Base) lockedAction(group string, options OptsLocking, intent string, f
 		// --nolock handling
 		return nil
 	}
+	timeout := options.Timeout
+	if options.Queue {
+		timeout = lockQueueTimeout
+	}
+	deadline := time.Now().Add(timeout)
 	p := t.lockPath(group)
 	lock := flock.New(p, xsession.ID, fcntllock.New)
-	err := lock.Lock(options.Timeout, intent)
+	err := lock.Lock(time.Until(deadline), intent)
 	if err != nil {
-		return err
+		return lockError(p, err)
 	}
 	defer func() { _ = lock.UnLock() }()
+	if group == "" && intent != "" {
+		// mutating action: also lock the peer instances
+		release, err := t.lockCluster(intent, deadline)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
 	return f()
 }
+
+// lockError adds to err the lock holder information found in the lock file.
+func lockError(p string, err error) error {
+	b, readErr := ioutil.ReadFile(p)
+	if readErr != nil {
+		return err
+	}
+	m := lockMeta{}
+	if json.Unmarshal(b, &m) != nil {
+		return err
+	}
+	return fmt.Errorf("%w: held by pid %d session %s for %s", err, m.PID, m.SessionID, m.Intent)
+}
+
+//
+// lockCluster acquires the cluster-wide action lock of the object from
+// the daemon, retrying while the lock is refused until the deadline, and
+// returns the function releasing it. The daemon refuses the lock while a
+// peer holds it or runs an action on the object.
+//
+// The lock is not acquired when the action is executed by the daemon
+// itself, which already serializes its orchestrated actions, nor when no
+// daemon is running, as no peer action can then be coordinated.
+//
+func (t *Base) lockCluster(intent string, deadline time.Time) (func(), error) {
+	release := func() {}
+	if env.HasDaemonOrigin() {
+		return release, nil
+	}
+	c, err := client.New()
+	if err != nil {
+		t.log.Debug().Err(err).Msg("cluster lock skipped: no daemon client")
+		return release, nil
+	}
+	req := c.NewPostObjectLock()
+	req.Path = t.Path.String()
+	req.Intent = intent
+	req.SessionID = xsession.ID
+	req.PID = os.Getpid()
+	var refused error
+	cond := func() (bool, error) {
+		_, err := req.Do()
+		switch {
+		case err == nil:
+			return true, nil
+		case isDaemonDown(err):
+			return false, err
+		default:
+			refused = err
+			return false, nil
+		}
+	}
+	onRetry := func(attempt int, _ time.Duration) {
+		if attempt == 1 {
+			t.log.Info().Msgf("%s waiting for the cluster lock: %s", intent, refused)
+		}
+	}
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		// already expired: try once
+		timeout = time.Nanosecond
+	}
+	err = waitfor.WaitFor(context.Background(), lockPollInterval, timeout, cond, waitfor.WithOnRetry(onRetry))
+	switch {
+	case err == nil:
+	case isDaemonDown(err):
+		t.log.Debug().Err(err).Msg("cluster lock skipped: no daemon")
+		return release, nil
+	case errors.Is(err, waitfor.ErrTimeout):
+		return nil, fmt.Errorf("lock timeout exceeded: %s", refused)
+	default:
+		return nil, err
+	}
+	release = func() {
+		req := c.NewDeleteObjectLock()
+		req.Path = t.Path.String()
+		req.SessionID = xsession.ID
+		if _, err := req.Do(); err != nil {
+			t.log.Warn().Err(err).Msg("cluster lock release")
+		}
+	}
+	return release, nil
+}
+
+// isDaemonDown returns true if the error of a daemon request is a
+// transport error, like a missing daemon socket. The request timeouts
+// are not, as the daemon may be waiting for the peers to agree on the
+// lock.
+func isDaemonDown(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && !netErr.Timeout()
+}
@@ -0,0 +1,57 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestIsDaemonDown(t *testing.T) {
+	assert.True(t, isDaemonDown(&net.OpError{Op: "dial", Net: "unix", Err: syscall.ENOENT}))
+	assert.True(t, isDaemonDown(fmt.Errorf("post: %w", &net.OpError{Op: "dial", Net: "unix", Err: syscall.ECONNREFUSED})))
+	assert.False(t, isDaemonDown(&url.Error{Op: "Post", URL: "http://localhost", Err: context.DeadlineExceeded}))
+	assert.False(t, isDaemonDown(errors.New("locked: start held by node n2 session abc")))
+}
+
+func TestLockClusterWithoutDaemon(t *testing.T) {
+	root, err := ioutil.TempDir("", "lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	p, _ := path.Parse("svc1")
+	o := NewSvc(p)
+	release, err := o.lockCluster("start", time.Now().Add(time.Second))
+	require.NoError(t, err, "no daemon to coordinate the peer actions: the lock is skipped")
+	release()
+}
+
+func TestLockError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "generic")
+	errTimeout := errors.New("lock timeout exceeded")
+
+	assert.Equal(t, errTimeout, lockError(p, errTimeout), "no lock file")
+
+	assert.NoError(t, ioutil.WriteFile(p, []byte(`{"pid": 1234, "intent": "start", "session_id": "abc"}`), 0644))
+	err = lockError(p, errTimeout)
+	assert.True(t, errors.Is(err, errTimeout))
+	assert.Equal(t, "lock timeout exceeded: held by pid 1234 session abc for start", err.Error())
+}
@@ -0,0 +1,113 @@
+package object
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rs/zerolog"
+)
+
+type (
+	// OptsLogs is the options of the Logs object method.
+	OptsLogs struct {
+		Global OptsGlobal
+		SID    string `flag:"sid"`
+	}
+
+	// LogEntries is the list of the entries of the object log files,
+	// oldest first.
+	LogEntries []LogEntry
+
+	// LogEntry is a decoded log file line.
+	LogEntry map[string]interface{}
+)
+
+// logBackupTimeFormat is the timestamp format embedded in the rotated log
+// file names by the lumberjack writer.
+const logBackupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Logs returns the entries of the object log file and of its rotated
+// backups, oldest first. If options.SID is set, only the entries logged by
+// this session are returned, so the trace of a single action can be
+// reconstructed.
+func (t *Base) Logs(options OptsLogs) (LogEntries, error) {
+	data := make(LogEntries, 0)
+	for _, p := range t.logFiles() {
+		entries, err := readLogFile(p, options.SID)
+		if err != nil {
+			return data, err
+		}
+		data = append(data, entries...)
+	}
+	return data, nil
+}
+
+// logFiles returns the rotated log files paths, oldest first, followed by
+// the current log file path.
+func (t Base) logFiles() []string {
+	l := make([]string, 0)
+	prefix := t.Path.Name + "-"
+	matches, _ := filepath.Glob(filepath.Join(t.LogDir(), prefix+"*.log"))
+	for _, p := range matches {
+		ts := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(p), prefix), ".log")
+		if _, err := time.Parse(logBackupTimeFormat, ts); err != nil {
+			// not a backup, but the log file of another object
+			// whose name starts with the same prefix.
+			continue
+		}
+		l = append(l, p)
+	}
+	sort.Strings(l)
+	if _, err := os.Stat(t.LogFile()); err == nil {
+		l = append(l, t.LogFile())
+	}
+	return l
+}
+
+func readLogFile(p, sid string) (LogEntries, error) {
+	data := make(LogEntries, 0)
+	f, err := os.Open(p)
+	if err != nil {
+		return data, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e := make(LogEntry)
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// skip the lines not written by the zerolog json encoder
+			continue
+		}
+		if sid != "" && e["sid"] != sid {
+			continue
+		}
+		data = append(data, e)
+	}
+	return data, scanner.Err()
+}
+
+// Render returns a human friendly string representation of the entries,
+// formatted like the console log.
+func (t LogEntries) Render() string {
+	var b bytes.Buffer
+	w := zerolog.ConsoleWriter{
+		Out:     &b,
+		NoColor: color.NoColor,
+	}
+	for _, e := range t {
+		buf, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		_, _ = w.Write(buf)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestLogs(t *testing.T) {
+	root, err := ioutil.TempDir("", "logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	p, _ := path.New("svc1", "", "svc")
+	b := Base{Path: p}
+	dir := b.LogDir()
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	files := map[string]string{
+		"svc1-2020-01-01T00-00-00.000.log": `{"sid":"a","m":"first"}` + "\n",
+		"svc1-2020-01-02T00-00-00.000.log": `{"sid":"b","m":"second"}` + "\n" + "not json\n",
+		"svc1-foo.log":                     `{"sid":"a","m":"other object"}` + "\n",
+		"svc1.log":                         `{"sid":"a","m":"third"}` + "\n",
+	}
+	for name, s := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(s), 0644))
+	}
+
+	entries, err := b.Logs(OptsLogs{})
+	require.NoError(t, err)
+	assert.Equal(t, LogEntries{
+		{"sid": "a", "m": "first"},
+		{"sid": "b", "m": "second"},
+		{"sid": "a", "m": "third"},
+	}, entries)
+
+	entries, err = b.Logs(OptsLogs{SID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, LogEntries{
+		{"sid": "a", "m": "first"},
+		{"sid": "a", "m": "third"},
+	}, entries)
+}
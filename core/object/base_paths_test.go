@@ -84,3 +84,39 @@ func TestConfigFile(t *testing.T) {
 	}
 
 }
+
+func TestLogFile(t *testing.T) {
+	tests := map[string]struct {
+		name      string
+		namespace string
+		kind      string
+		lf        string
+	}{
+		"rooted svc": {
+			name: "svc1",
+			kind: "svc",
+			lf:   "/opt/opensvc/log/root/svc1.log",
+		},
+		"namespaced svc": {
+			name:      "svc1",
+			namespace: "ns1",
+			kind:      "svc",
+			lf:        "/opt/opensvc/log/ns1/svc1.log",
+		},
+		"namespaced vol": {
+			name:      "vol1",
+			namespace: "ns1",
+			kind:      "vol",
+			lf:        "/opt/opensvc/log/ns1/vol/vol1.log",
+		},
+	}
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": "/opt/opensvc",
+	})
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		p, _ := path.New(test.name, test.namespace, test.kind)
+		b := Base{Path: p}
+		assert.Equal(t, test.lf, b.LogFile())
+	}
+}
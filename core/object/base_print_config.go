@@ -10,13 +10,21 @@ type OptsPrintConfig struct {
 	Lock        OptsLocking
 	Eval        bool   `flag:"eval"`
 	Impersonate string `flag:"impersonate"`
+	Revision    int    `flag:"rev"`
 }
 
-// PrintConfig gets a keyword value
+// PrintConfig returns the object configuration. With options.Eval, the
+// scoped keywords are replaced by their value on the options.Impersonate
+// node, or the local node, and the references are dereferenced.
+// With options.Revision, the configuration is read from the history of
+// the previous configuration generations.
 func (t *Base) PrintConfig(options OptsPrintConfig) (rawconfig.T, error) {
-	if options.Eval {
-		// TODO
-		return rawconfig.T{}, nil
+	cf, err := t.config.Revision(options.Revision)
+	if err != nil {
+		return rawconfig.T{}, err
 	}
-	return t.config.Raw(), nil
+	if options.Eval || options.Impersonate != "" {
+		return cf.RawEvaluatedAs(options.Impersonate), nil
+	}
+	return cf.Raw(), nil
 }
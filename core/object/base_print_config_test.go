@@ -0,0 +1,104 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/opensvc/testhelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestPrintConfigEval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "printconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cf := filepath.Join(dir, "s1.conf")
+	b := []byte(`[DEFAULT]
+nodes = node1 node2
+orchestrate = no
+orchestrate@node2 = ha
+
+[env]
+greet = hello {name}
+greet@node2 = bye {name}
+`)
+	require.NoError(t, ioutil.WriteFile(cf, b, 0644))
+	p, _ := path.Parse("s1")
+	o := NewFromPath(p, WithConfigFile(cf), WithVolatile(true)).(*Svc)
+
+	get := func(options OptsPrintConfig, section, option string) interface{} {
+		data, err := o.PrintConfig(options)
+		require.NoError(t, err)
+		i, ok := data.Data.Get(section)
+		require.True(t, ok, section)
+		m := i.(orderedmap.OrderedMap)
+		v, _ := m.Get(option)
+		return v
+	}
+
+	raw := OptsPrintConfig{}
+	assert.Equal(t, "hello {name}", get(raw, "env", "greet"))
+	assert.Equal(t, "bye {name}", get(raw, "env", "greet@node2"))
+
+	eval := OptsPrintConfig{Eval: true, Impersonate: "node1"}
+	assert.Equal(t, "no", get(eval, "DEFAULT", "orchestrate"))
+	assert.Equal(t, "hello s1", get(eval, "env", "greet"))
+	assert.Nil(t, get(eval, "env", "greet@node2"))
+
+	eval.Impersonate = "node2"
+	assert.Equal(t, "no", get(eval, "DEFAULT", "orchestrate"), "orchestrate is not scopable")
+	assert.Equal(t, "bye s1", get(eval, "env", "greet"))
+}
+
+func TestResourceProfile(t *testing.T) {
+	td, tdCleanup := testhelper.Tempdir(t)
+	defer tdCleanup()
+	rawconfig.Load(map[string]string{"osvc_root_path": td})
+	defer rawconfig.Load(map[string]string{})
+
+	etc := rawconfig.Node.Paths.Etc
+	require.NoError(t, os.MkdirAll(etc, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(etc, "node.conf"), []byte(`[profile#oracle-fs]
+mnt_opt = rw,noatime
+user = oracle
+perm = 0750
+`), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(etc, "cluster.conf"), []byte(`[profile#small]
+app = accounting
+`), 0644))
+
+	cf := filepath.Join(td, "s1.conf")
+	require.NoError(t, ioutil.WriteFile(cf, []byte(`[DEFAULT]
+profile = small
+
+[fs#1]
+profile = oracle-fs
+user = root
+`), 0644))
+	p, _ := path.Parse("s1")
+	o := NewFromPath(p, WithConfigFile(cf), WithVolatile(true)).(*Svc)
+
+	v, err := o.Config().Eval(key.Parse("app"))
+	assert.NoError(t, err)
+	assert.Equal(t, "accounting", v, "the cluster profile value has precedence over the default")
+
+	data, err := o.PrintConfig(OptsPrintConfig{Eval: true})
+	require.NoError(t, err)
+	i, ok := data.Data.Get("fs#1")
+	require.True(t, ok)
+	m := i.(orderedmap.OrderedMap)
+	get := func(option string) interface{} {
+		v, _ := m.Get(option)
+		return v
+	}
+	assert.Equal(t, "root", get("user"), "the object value has precedence over the profile value")
+	assert.Equal(t, "rw,noatime", get("mnt_opt"))
+	assert.Equal(t, "0750", get("perm"))
+}
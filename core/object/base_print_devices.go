@@ -0,0 +1,18 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/devicetree"
+)
+
+type (
+	// OptsPrintDevices is the options of the PrintDevices object method.
+	OptsPrintDevices struct {
+		Global OptsGlobal
+	}
+)
+
+// PrintDevices returns the tree of the devices exposed, used, reserved
+// and claimed by the object resources.
+func (t *Base) PrintDevices(options OptsPrintDevices) *devicetree.T {
+	return devicetree.New(t.Path.String(), t.Resources())
+}
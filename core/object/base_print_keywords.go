@@ -0,0 +1,139 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// OptsPrintKeywords is the options of the PrintKeywords object method.
+	OptsPrintKeywords struct {
+		Global  OptsGlobal
+		Changed bool `flag:"changed"`
+	}
+
+	// KeywordsReport is the list of the keywords set in an object
+	// configuration, with their default value.
+	KeywordsReport []KeywordsReportEntry
+
+	// KeywordsReportEntry describes a keyword set in an object
+	// configuration.
+	KeywordsReportEntry struct {
+		// Scope is "object" for the DEFAULT section keywords, "resource"
+		// for the resource sections keywords.
+		Scope   string `json:"scope"`
+		Section string `json:"section"`
+		Option  string `json:"option"`
+
+		// Node is the node scope of the value, like nodes, drpnodes or
+		// a node name, or empty if the value is not scoped.
+		Node    string `json:"node,omitempty"`
+		Value   string `json:"value"`
+		Default string `json:"default"`
+
+		// Known is false if the keyword is not declared by the object or
+		// its resource drivers.
+		Known   bool `json:"known"`
+		Changed bool `json:"changed"`
+	}
+)
+
+const (
+	keywordsReportScopeObject   = "object"
+	keywordsReportScopeResource = "resource"
+)
+
+// PrintKeywords returns the keywords set in the object configuration. If
+// options.Changed is set, only the keywords whose value differs from the
+// keyword default are returned.
+func (t *Base) PrintKeywords(options OptsPrintKeywords) (KeywordsReport, error) {
+	data := make(KeywordsReport, 0)
+	if t.config == nil {
+		return data, fmt.Errorf("%s: no configuration", t.Path)
+	}
+	sections := t.config.SectionStrings()
+	sort.Strings(sections)
+	for _, section := range sections {
+		scope := keywordsReportScopeResource
+		if section == "DEFAULT" {
+			scope = keywordsReportScopeObject
+		}
+		sectionType := t.config.GetString(key.New(section, "type"))
+		for _, name := range t.config.Keys(section) {
+			l := strings.SplitN(name, "@", 2)
+			e := KeywordsReportEntry{
+				Scope:   scope,
+				Section: section,
+				Option:  l[0],
+				Value:   t.config.Get(key.New(section, name)),
+			}
+			if len(l) == 2 {
+				e.Node = l[1]
+			}
+			kwSectionType := sectionType
+			if e.Option == "type" {
+				kwSectionType = ""
+			}
+			kw := t.KeywordLookup(key.New(section, e.Option), kwSectionType)
+			e.Known = !kw.IsZero()
+			e.Default = kw.Default
+			e.Changed = !e.Known || !isDefaultValue(kw, e.Value)
+			if options.Changed && !e.Changed {
+				continue
+			}
+			data = append(data, e)
+		}
+	}
+	return data, nil
+}
+
+// isDefaultValue returns true if the value is the keyword default. The
+// values are compared after conversion if the keyword has a converter, so
+// "60s" is considered equal to a "1m" default.
+func isDefaultValue(kw keywords.Keyword, v string) bool {
+	if v == kw.Default {
+		return true
+	}
+	if kw.Converter == nil || kw.Default == "" {
+		return false
+	}
+	a, err := kw.Converter.Convert(v)
+	if err != nil {
+		return false
+	}
+	b, err := kw.Converter.Convert(kw.Default)
+	if err != nil {
+		return false
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// Render returns a human friendly string representation of the report.
+func (t KeywordsReport) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText("Section").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Keyword").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Value").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Default").SetColor(rawconfig.Node.Color.Bold)
+	for _, e := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(e.Section).SetColor(rawconfig.Node.Color.Primary)
+		option := e.Option
+		if e.Node != "" {
+			option += "@" + e.Node
+		}
+		c := n.AddColumn().AddText(option)
+		if !e.Known {
+			c.SetColor(rawconfig.Node.Color.Warning)
+		}
+		n.AddColumn().AddText(e.Value)
+		n.AddColumn().AddText(e.Default).SetColor(rawconfig.Node.Color.Secondary)
+	}
+	return tree.Render()
+}
@@ -0,0 +1,53 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestPrintKeywords(t *testing.T) {
+	dir, err := ioutil.TempDir("", "printkeywords")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	cf := filepath.Join(dir, "s1.conf")
+	b := []byte(`[DEFAULT]
+topology = failover
+orchestrate = ha
+priority = 050
+priority@node1 = 10
+
+[foo#1]
+bar = baz
+`)
+	require.NoError(t, ioutil.WriteFile(cf, b, 0644))
+	p, _ := path.Parse("s1")
+	o := NewFromPath(p, WithConfigFile(cf), WithVolatile(true)).(*Svc)
+
+	report, err := o.PrintKeywords(OptsPrintKeywords{})
+	require.NoError(t, err)
+	assert.Len(t, report, 5)
+
+	report, err = o.PrintKeywords(OptsPrintKeywords{Changed: true})
+	require.NoError(t, err)
+	changed := make(map[string]KeywordsReportEntry)
+	for _, e := range report {
+		k := e.Section + "." + e.Option
+		if e.Node != "" {
+			k += "@" + e.Node
+		}
+		changed[k] = e
+	}
+	assert.Len(t, changed, 3)
+	assert.Contains(t, changed, "DEFAULT.orchestrate")
+	assert.Contains(t, changed, "DEFAULT.priority@node1")
+	assert.Equal(t, "object", changed["DEFAULT.orchestrate"].Scope)
+	assert.Equal(t, "no", changed["DEFAULT.orchestrate"].Default)
+	assert.False(t, changed["foo#1.bar"].Known)
+	assert.Equal(t, "resource", changed["foo#1.bar"].Scope)
+}
@@ -0,0 +1,63 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+)
+
+type (
+	// OptsPrintRun is the options of the PrintRun object method.
+	OptsPrintRun struct {
+		Global OptsGlobal
+		resourceselector.Options
+		Last bool `flag:"last"`
+	}
+
+	// RunLogs is the list of the task resources run logs, oldest first.
+	RunLogs []resource.RunLog
+)
+
+//
+// PrintRun returns the run logs of the selected task resources. With the
+// Last option, only the last run log of each task is returned, with its
+// content.
+//
+func (t *Base) PrintRun(options OptsPrintRun) (RunLogs, error) {
+	data := make(RunLogs, 0)
+	sel := resourceselector.New(t, resourceselector.WithOptions(options.Options))
+	for _, r := range sel.Resources() {
+		if !resource.Implements(r, resource.FeatureRun) {
+			continue
+		}
+		l, err := resource.RunLogs(r)
+		if err != nil {
+			return data, err
+		}
+		if options.Last && len(l) > 0 {
+			e := l[len(l)-1]
+			if err := e.Load(); err != nil {
+				return data, err
+			}
+			l = []resource.RunLog{e}
+		}
+		data = append(data, l...)
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Time.Before(data[j].Time) })
+	return data, nil
+}
+
+// Render is a human readable format of the run logs.
+func (t RunLogs) Render() string {
+	var b strings.Builder
+	for _, e := range t {
+		fmt.Fprintf(&b, "%s %s %s\n", e.Time.Format("2006-01-02 15:04:05"), e.RID, e.File)
+		if e.Content != "" {
+			b.WriteString(e.Content)
+		}
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/schedule"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/hostname"
@@ -75,10 +76,14 @@ func (t *Base) Schedules() schedule.Table {
 		t.newScheduleEntry("compliance_auto", "comp_schedule", "comp_check"),
 	)
 	needResMon := false
+	needSync := false
 	for _, r := range t.Resources() {
 		if !needResMon && r.IsMonitored() {
 			needResMon = true
 		}
+		if _, ok := r.(resource.SyncUpdater); ok {
+			needSync = true
+		}
 		if i, ok := r.(scheduler); ok {
 			table = table.Add(i.Schedules())
 		}
@@ -87,9 +92,13 @@ func (t *Base) Schedules() schedule.Table {
 		e := t.newScheduleEntry("resource_monitor", "monitor_schedule", "resource_monitor")
 		table = table.Add(e)
 	}
+	if needSync {
+		e := t.newScheduleEntry("sync_update", "sync_schedule", "sync_update")
+		table = table.Add(e)
+	}
 	if len(t.Resources()) > 0 {
 		e := t.newScheduleEntry("push_resinfo", "resinfo_schedule", "push_resinfo")
 		table = table.Add(e)
 	}
-	return table
+	return table.SetNext(time.Now())
 }
@@ -18,8 +18,13 @@ func (t *Base) Set(options OptsSet) error {
 	return t.SetKeywords(options.KeywordOps)
 }
 
+//
+// SetKeywords applies the keyword operations and commits the
+// configuration. The operations are applied all or none: if an operation
+// or the commit validation fails, the configuration is left unchanged.
+//
 func (t *Base) SetKeywords(kws []string) error {
-	changes := 0
+	ops := make([]keyop.T, 0, len(kws))
 	for _, kw := range kws {
 		op := keyop.Parse(kw)
 		if op.IsZero() {
@@ -30,13 +35,18 @@ func (t *Base) SetKeywords(kws []string) error {
 			Stringer("op", op.Op).
 			Str("val", op.Value).
 			Msg("set")
-		if err := t.config.Set(*op); err != nil {
-			return err
-		}
-		changes++
+		ops = append(ops, *op)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+	if err := t.config.SetKeys(ops...); err != nil {
+		return err
 	}
-	if changes > 0 {
-		return t.config.Commit()
+	if err := t.config.Commit(); err != nil {
+		// discard the applied operations
+		_ = t.config.Reload()
+		return err
 	}
 	return nil
 }
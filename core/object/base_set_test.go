@@ -0,0 +1,66 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestSetKeywordsOperators(t *testing.T) {
+	root, err := ioutil.TempDir("", "keyop")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[DEFAULT]\nid = 5f0a1a8e-1d8a-4a3e-9d5b-2d1cba0a1d3e\nparents = S1 s2\nflex_min = 1\n\n[env]\nfoo = a b\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "s1.conf"), b, 0644))
+	p, _ := path.Parse("s1")
+	o := NewSvc(p)
+
+	cases := []struct {
+		ops      []string
+		key      string
+		expected string
+		failed   bool
+	}{
+		{ops: []string{"env.foo+=c"}, key: "env.foo", expected: "a b c"},
+		{ops: []string{"env.foo|=c"}, key: "env.foo", expected: "a b c"},
+		{ops: []string{"env.foo-=b"}, key: "env.foo", expected: "a c"},
+		{ops: []string{"env.foo^=b"}, key: "env.foo", expected: "a c b"},
+		{ops: []string{"env.foo^=b"}, key: "env.foo", expected: "a c"},
+		{ops: []string{"env.foo[1]=x"}, key: "env.foo", expected: "a x c"},
+		{ops: []string{"env.foo[3]=y"}, key: "env.foo", expected: "a x c y"},
+		{ops: []string{"env.foo[0]-="}, key: "env.foo", expected: "x c y"},
+		{ops: []string{"env.foo[0]-=c"}, key: "env.foo", expected: "x c y", failed: true},
+		{ops: []string{"env.foo[9]=z"}, key: "env.foo", expected: "x c y", failed: true},
+		{ops: []string{"DEFAULT.parents-=s1"}, key: "DEFAULT.parents", expected: "s2"},
+		{ops: []string{"DEFAULT.parents|=S2"}, key: "DEFAULT.parents", expected: "s2"},
+		{ops: []string{"DEFAULT.flex_min+=1"}, key: "DEFAULT.flex_min", expected: "1", failed: true},
+		{ops: []string{"DEFAULT.flex_min=2"}, key: "DEFAULT.flex_min", expected: "2"},
+		{ops: []string{"DEFAULT.flex_min=two"}, key: "DEFAULT.flex_min", expected: "2", failed: true},
+	}
+	for _, c := range cases {
+		err := o.SetKeywords(c.ops)
+		if c.failed {
+			assert.Error(t, err, "%s", c.ops)
+		} else {
+			assert.NoError(t, err, "%s", c.ops)
+		}
+		assert.Equal(t, c.expected, o.Config().Get(key.Parse(c.key)), "%s", c.ops)
+	}
+
+	t.Run("the conversion error names the keyword", func(t *testing.T) {
+		err := o.SetKeywords([]string{"DEFAULT.flex_min=two"})
+		assert.ErrorIs(t, err, converters.ErrInvalid)
+		assert.Contains(t, err.Error(), "flex_min: invalid value")
+	})
+}
@@ -44,7 +44,7 @@ func (t *Base) Status(options OptsStatus) (instance.Status, error) {
 		data instance.Status
 		err  error
 	)
-	ctx := actioncontext.New(options, objectactionprops.Status)
+	ctx := actioncontext.New(context.Background(), options, objectactionprops.Status)
 	if options.Refresh || t.statusDumpOutdated() {
 		return t.statusEval(ctx, options)
 	}
@@ -56,7 +56,9 @@ func (t *Base) Status(options OptsStatus) (instance.Status, error) {
 }
 
 func (t *Base) postActionStatusEval(ctx context.Context) {
-	if _, err := t.statusEval(ctx, OptsStatus{}); err != nil {
+	// the action context may be cancelled by its timeout, but the
+	// status must be refreshed anyway
+	if _, err := t.statusEval(actioncontext.Detach(ctx), OptsStatus{}); err != nil {
 		t.log.Debug().Err(err).Msg("a status refresh is already in progress")
 	}
 }
@@ -89,6 +91,7 @@ func (t *Base) lockedStatusEval(ctx context.Context) (data instance.Status, err
 	if err = t.resourceStatusEval(ctx, &data); err != nil {
 		return
 	}
+	t.encapStatusEval(ctx, &data)
 	if len(data.Resources) == 0 {
 		data.Avail = status.NotApplicable
 		data.Overall = status.NotApplicable
@@ -186,6 +189,12 @@ func (t *Base) resourceStatusEval(ctx context.Context, data *instance.Status) er
 		xd := resource.GetExposedStatus(ctx, r)
 		mu.Lock()
 		data.Resources[r.RID()] = xd
+		if !t.isResourceInScope(r) {
+			// encap resources are not aggregated on the hypervisor nodes,
+			// and the other resources not aggregated on the encap nodes.
+			mu.Unlock()
+			return nil
+		}
 		data.Overall.Add(xd.Status)
 		if !xd.Optional {
 			data.Avail.Add(xd.Status)
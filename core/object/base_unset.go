@@ -25,8 +25,13 @@ func (t *Base) unset(kws ...key.T) error {
 	for _, k := range kws {
 		changes += t.config.Unset(k)
 	}
-	if changes > 0 {
-		return t.config.Commit()
+	if changes == 0 {
+		return nil
+	}
+	if err := t.config.Commit(); err != nil {
+		// restore the unset keys
+		_ = t.config.Reload()
+		return err
 	}
 	return nil
 }
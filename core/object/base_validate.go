@@ -0,0 +1,117 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceid"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/key"
+)
+
+//
+// ValidateConfig is the configuration commit validation hook. It returns
+// an error if a resource section sets a keyword unknown to its driver
+// manifest, if a literal value is not convertible to its keyword type,
+// or if a value references a key of an undefined resource section.
+//
+// The sections of drivers not available on this node are not checked,
+// as they are skipped by the resources configuration too.
+//
+func (t Base) ValidateConfig(cf *xconfig.T) error {
+	for _, section := range cf.SectionStrings() {
+		if err := validateSectionKeywords(cf, section); err != nil {
+			return err
+		}
+		// GetStrict does not create the missing key, unlike Get
+		sectionType, _ := cf.GetStrict(key.New(section, "type"))
+		for _, option := range cf.Keys(section) {
+			k := key.New(section, option)
+			v := cf.Get(k)
+			if err := validateReferences(cf, v); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+			if err := validateValue(cf, k, sectionType, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateSectionKeywords(cf *xconfig.T, section string) error {
+	rid := resourceid.Parse(section)
+	driverGroup := rid.DriverGroup()
+	if driverGroup == drivergroup.Unknown {
+		return nil
+	}
+	sectionType, _ := cf.GetStrict(key.New(section, "type"))
+	driverName := sectionType
+	if driverName == "" {
+		driverName = DefaultDriver[driverGroup.String()]
+	}
+	if resource.NewDriverID(driverGroup, driverName).NewResourceFunc() == nil {
+		return nil
+	}
+	for _, option := range cf.Keys(section) {
+		option = strings.SplitN(option, "@", 2)[0]
+		switch option {
+		case "comment", "type":
+			continue
+		}
+		k := key.New(section, option)
+		if kw := cf.Referrer.KeywordLookup(k, sectionType); kw.IsZero() {
+			return fmt.Errorf("%s: %w for the %s driver", k, xconfig.ErrNoKeyword, driverGroup)
+		}
+	}
+	return nil
+}
+
+//
+// validateValue returns an error if the value is not convertible to the
+// keyword type. The values containing references are only convertible
+// once evaluated, and the user and group lookups depend on the node, so
+// these are not checked.
+//
+func validateValue(cf *xconfig.T, k key.T, sectionType, v string) error {
+	if v == "" || strings.Contains(v, "{") {
+		return nil
+	}
+	lk := key.New(k.Section, strings.SplitN(k.Option, "@", 2)[0])
+	if lk.Option == "type" {
+		return nil
+	}
+	kw := cf.Referrer.KeywordLookup(lk, sectionType)
+	switch kw.Converter.(type) {
+	case nil, converters.TUser, converters.TGroup:
+		return nil
+	}
+	if _, err := kw.Converter.Convert(v); err != nil {
+		return fmt.Errorf("%s: %w", k, err)
+	}
+	return nil
+}
+
+// validateReferences returns an error if the value references a key of
+// an undefined resource section, like {fs#9.mnt}.
+func validateReferences(cf *xconfig.T, v string) error {
+	for _, ref := range rawconfig.RegexpReference.FindAllString(v, -1) {
+		ref = ref[1 : len(ref)-1]
+		if l := strings.SplitN(ref, ":", 2); len(l) == 2 {
+			// strip the {upper:...} like modifiers
+			ref = l[1]
+		}
+		k := key.Parse(ref)
+		if !strings.Contains(k.Section, "#") {
+			continue
+		}
+		if !cf.HasSectionString(k.Section) {
+			return fmt.Errorf("reference {%s} to the undefined section %s", ref, k.Section)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestTransactionalSet(t *testing.T) {
+	root, err := ioutil.TempDir("", "commit")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[DEFAULT]\nid = 5f0a1a8e-1d8a-4a3e-9d5b-2d1cba0a1d3e\n\n[env]\nfoo = 1\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "s1.conf"), b, 0644))
+	p, _ := path.Parse("s1")
+	o := NewSvc(p)
+
+	t.Run("refuses a reference to an undefined resource", func(t *testing.T) {
+		err := o.SetKeywords([]string{"env.foo=2", "env.bar={fs#9.mnt}"})
+		assert.Error(t, err)
+		assert.Equal(t, "1", o.Config().Get(key.New("env", "foo")), "the in-memory configuration is unchanged")
+		assert.Equal(t, "1", NewSvc(p).Config().Get(key.New("env", "foo")), "the configuration file is unchanged")
+	})
+
+	t.Run("keeps the previous generations", func(t *testing.T) {
+		require.NoError(t, o.SetKeywords([]string{"env.foo=2"}))
+		require.NoError(t, o.SetKeywords([]string{"env.foo=3"}))
+		get := func(rev int) interface{} {
+			data, err := o.PrintConfig(OptsPrintConfig{Revision: rev})
+			require.NoError(t, err)
+			i, _ := data.Data.Get("env")
+			m := i.(orderedmap.OrderedMap)
+			v, _ := m.Get("foo")
+			return v
+		}
+		assert.Equal(t, "3", get(0))
+		assert.Equal(t, "2", get(1))
+		assert.Equal(t, "1", get(2))
+		_, err := o.PrintConfig(OptsPrintConfig{Revision: 3})
+		assert.ErrorIs(t, err, xconfig.ErrNoRevision)
+	})
+
+	t.Run("bounds the history", func(t *testing.T) {
+		o.Config().HistoryMax = 2
+		require.NoError(t, o.SetKeywords([]string{"env.foo=4"}))
+		l, err := o.Config().Revisions()
+		require.NoError(t, err)
+		assert.Len(t, l, 2)
+	})
+}
@@ -1,8 +1,11 @@
 package object
 
 import (
+	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/key"
 )
 
 type (
@@ -17,9 +20,36 @@ type (
 	}
 )
 
+// ccfgKeywordStore holds the node.conf keywords allowed in cluster.conf.
+var ccfgKeywordStore = keywords.Store(commonKeywords)
+
 // NewCcfg allocates a ccfg kind object.
 func NewCcfg(p path.T, opts ...funcopt.O) *Ccfg {
 	s := &Ccfg{}
 	s.Base.init(p, opts...)
+	if s.config != nil {
+		// evaluate the keywords and scopes as cluster keywords and
+		// nodes instead of the generic object ones.
+		s.config.Referrer = s
+	}
 	return s
 }
+
+// KeywordLookup returns the cluster.conf keyword definition.
+func (t Ccfg) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
+	return lookupNodeKeyword(ccfgKeywordStore, k, sectionType)
+}
+
+// Nodes returns the cluster nodes, used to evaluate the @nodes scopes.
+func (t Ccfg) Nodes() []string {
+	v := t.config.Get(key.New("cluster", "nodes"))
+	l, _ := xconfig.NodesConverter.Convert(v)
+	return l.([]string)
+}
+
+// DRPNodes returns the cluster disaster recovery nodes.
+func (t Ccfg) DRPNodes() []string {
+	v := t.config.Get(key.New("cluster", "drpnodes"))
+	l, _ := xconfig.OtherNodesConverter.Convert(v)
+	return l.([]string)
+}
@@ -0,0 +1,53 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestCcfg(t *testing.T) {
+	root, err := ioutil.TempDir("", "ccfg")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[cluster]\nname = c1\nnodes = " + hostname.Hostname() + " n2\n\n[hb#1]\ntype = unicast\ntimeout = 20s\ntimeout@n2 = 30s\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "cluster.conf"), b, 0644))
+	p, _ := path.Parse("cluster")
+
+	t.Run("evaluates the cluster keywords", func(t *testing.T) {
+		o := NewCcfg(p)
+		v, err := o.Eval(OptsEval{Keyword: "cluster.nodes"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{hostname.Hostname(), "n2"}, v)
+		assert.Equal(t, "c1", o.Config().GetString(key.New("cluster", "name")))
+	})
+
+	t.Run("scopes on the cluster nodes", func(t *testing.T) {
+		o := NewCcfg(p)
+		v, err := o.Eval(OptsEval{Keyword: "hb#1.timeout", Impersonate: "n2"})
+		require.NoError(t, err)
+		assert.Equal(t, "30s", v.(interface{ String() string }).String())
+	})
+
+	t.Run("rejects the node private keywords", func(t *testing.T) {
+		o := NewCcfg(p)
+		_, err := o.Eval(OptsEval{Keyword: "node.prkey"})
+		assert.Error(t, err)
+	})
+
+	t.Run("sets the cluster keywords", func(t *testing.T) {
+		require.NoError(t, NewCcfg(p).Set(OptsSet{KeywordOps: []string{"cluster.name=c2"}}))
+		assert.Equal(t, "c2", NewCcfg(p).Config().GetString(key.New("cluster", "name")))
+	})
+}
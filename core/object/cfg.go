@@ -49,7 +49,8 @@ func cfgEncode(b []byte) (string, error) {
 func cfgDecode(s string) ([]byte, error) {
 	switch {
 	case strings.HasPrefix(s, "base64:"):
-		return base64.URLEncoding.DecodeString(s[7:])
+		// cfgEncode does not pad, but accept the padded values too
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(s[7:], "="))
 	case strings.HasPrefix(s, "literal:"):
 		return []byte(s[8:]), nil
 	default:
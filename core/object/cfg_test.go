@@ -0,0 +1,21 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCfgEncodeDecode(t *testing.T) {
+	for _, b := range [][]byte{[]byte("v1"), {0}, {0, 1}, {0, 1, 2}, {0, 1, 2, 255}} {
+		s, err := cfgEncode(b)
+		require.NoError(t, err)
+		decoded, err := cfgDecode(s)
+		require.NoError(t, err)
+		assert.Equal(t, b, decoded, s)
+	}
+	decoded, err := cfgDecode("base64:AAECAw==")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3}, decoded, "padded values are accepted")
+}
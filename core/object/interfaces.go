@@ -1,6 +1,7 @@
 package object
 
 import (
+	"context"
 	"opensvc.com/opensvc/core/instance"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/resourceset"
@@ -18,6 +19,7 @@ type (
 	// SecureKeystorer is implemented by encrypting Keystore object kinds (usr, sec).
 	SecureKeystorer interface {
 		GenCert(OptsGenCert) error
+		Rekey(OptsRekey) error
 	}
 
 	// Keystorer is implemented by Keystore object kinds (usr, sec, cfg).
@@ -28,6 +30,7 @@ type (
 		Keys(OptsKeys) ([]string, error)
 		Remove(OptsRemove) error
 		EditKey(OptsEditKey) error
+		InstallKey(OptsInstallKey) ([]string, error)
 	}
 
 	// Baser is implemented by all object kinds.
@@ -41,10 +44,22 @@ type (
 	// Actor is implemented by object kinds supporting start, stop, ...
 	Actor interface {
 		Freezer
-		Start(OptsStart) error
-		Stop(OptsStop) error
-		Provision(OptsProvision) error
-		Unprovision(OptsUnprovision) error
+		Boot(context.Context, OptsBoot) error
+		Console(OptsConsole) error
+		Enter(OptsEnter) error
+		Start(context.Context, OptsStart) error
+		StartStandby(context.Context, OptsStartStandby) error
+		Stop(context.Context, OptsStop) error
+		TOC(context.Context, OptsTOC) error
+		Provision(context.Context, OptsProvision) error
+		Run(context.Context, OptsRun) error
+		SnapCreate(context.Context, OptsSnapCreate) error
+		SnapPrune(context.Context, OptsSnapPrune) error
+		SnapRollback(context.Context, OptsSnapRollback) error
+		SyncFull(context.Context, OptsSyncFull) error
+		SyncResync(context.Context, OptsSyncResync) error
+		SyncUpdate(context.Context, OptsSyncUpdate) error
+		Unprovision(context.Context, OptsUnprovision) error
 	}
 
 	// Freezer is implemented by object kinds supporting freeze and thaw.
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/secbackend"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/uri"
 )
@@ -40,6 +42,8 @@ func (t *Keystore) alter(name string, from string, value string) error {
 		err error
 	)
 	switch {
+	case from != "" && secbackend.IsRef(from):
+		err = t.fromRef(name, from)
 	case from != "":
 		u := uri.New(from)
 		switch {
@@ -66,6 +70,27 @@ func (t *Keystore) fromValue(name string, value string) error {
 	return t.addKey(name, b)
 }
 
+// fromRef stores an external secret backend reference, resolved at decode
+// time, instead of the encoded value.
+func (t *Keystore) fromRef(name string, ref string) error {
+	if t.Path.Kind != kind.Sec {
+		return fmt.Errorf("secret backend references are only supported by sec objects")
+	}
+	if _, err := secbackend.Resolve(ref); err != nil {
+		return err
+	}
+	op := keyop.T{
+		Key:   keyFromName(name),
+		Op:    keyop.Set,
+		Value: ref,
+	}
+	if err := t.config.Set(op); err != nil {
+		return err
+	}
+	t.log.Info().Str("key", name).Str("ref", ref).Msg("key reference set")
+	return nil
+}
+
 func (t *Keystore) fromRegular(name string, p string) error {
 	b, err := file.ReadAll(p)
 	if err != nil {
@@ -0,0 +1,138 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/util/file"
+)
+
+// OptsInstallKey is the options of the InstallKey function of all keystore objects.
+type OptsInstallKey struct {
+	// Key is the key name, or a key name glob pattern.
+	Key string
+
+	// Path is the file path to install the key to. If Key is a glob pattern
+	// or Path ends with a /, Path is a directory and the keys are installed
+	// as files named after the key base name.
+	Path string
+
+	// Perm is the permissions to apply to the installed files.
+	Perm os.FileMode
+
+	// DirPerm is the permissions to apply to the created directories.
+	DirPerm os.FileMode
+
+	// UID is the uid of the installed files and created directories owner. -1 to leave unchanged.
+	UID int
+
+	// GID is the gid of the installed files and created directories owner. -1 to leave unchanged.
+	GID int
+}
+
+func isKeyGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// InstallKey writes the decoded values of the keys matching options.Key
+// to files, and returns the list of files whose content changed.
+func (t *Keystore) InstallKey(options OptsInstallKey) ([]string, error) {
+	changed := make([]string, 0)
+	if options.Key == "" {
+		return changed, fmt.Errorf("key name can not be empty")
+	}
+	if options.Path == "" {
+		return changed, fmt.Errorf("install path can not be empty")
+	}
+	if options.Perm == 0 {
+		options.Perm = DefaultInstalledFileMode
+	}
+	if options.DirPerm == 0 {
+		options.DirPerm = os.ModePerm
+	}
+	if !isKeyGlob(options.Key) && !strings.HasSuffix(options.Path, "/") {
+		if v, err := t.installKeyTo(options.Key, options.Path, options); err != nil {
+			return changed, err
+		} else if v {
+			changed = append(changed, options.Path)
+		}
+		return changed, nil
+	}
+	keys, err := t.Keys(OptsKeys{Match: options.Key})
+	if err != nil {
+		return changed, err
+	}
+	for _, keyname := range keys {
+		p := filepath.Join(options.Path, filepath.Base(keyname))
+		if v, err := t.installKeyTo(keyname, p, options); err != nil {
+			return changed, err
+		} else if v {
+			changed = append(changed, p)
+		}
+	}
+	return changed, nil
+}
+
+// installKeyTo writes the decoded key value to the file p if the file
+// content differs. It returns true if the file was written.
+func (t *Keystore) installKeyTo(keyname string, p string, options OptsInstallKey) (bool, error) {
+	b, err := t.decode(keyname)
+	if err != nil {
+		return false, err
+	}
+	if err := t.installDir(filepath.Dir(p), options); err != nil {
+		return false, err
+	}
+	changed := true
+	if file.ExistsAndRegular(p) {
+		if current, err := ioutil.ReadFile(p); err == nil && bytes.Equal(current, b) {
+			changed = false
+		}
+	}
+	if changed {
+		t.log.Info().Msgf("install %s/%s in %s", t.Path, keyname, p)
+		if err := file.AtomicWrite(p, b, options.Perm); err != nil {
+			return false, err
+		}
+	}
+	if err := installPermOwnership(p, options.Perm, options.UID, options.GID); err != nil {
+		return changed, err
+	}
+	return changed, nil
+}
+
+func (t *Keystore) installDir(p string, options OptsInstallKey) error {
+	if file.ExistsAndDir(p) {
+		return nil
+	}
+	t.log.Info().Msgf("create directory %s", p)
+	if err := os.MkdirAll(p, options.DirPerm); err != nil {
+		return err
+	}
+	return installPermOwnership(p, options.DirPerm, options.UID, options.GID)
+}
+
+func installPermOwnership(p string, perm os.FileMode, uid, gid int) error {
+	if mode, err := file.Mode(p); err != nil {
+		return err
+	} else if mode.Perm() != perm {
+		if err := os.Chmod(p, (mode&os.ModeType)|perm); err != nil {
+			return err
+		}
+	}
+	if uid == -1 && gid == -1 {
+		return nil
+	}
+	curUID, curGID, err := file.Ownership(p)
+	if err != nil {
+		return err
+	}
+	if (uid == -1 || uid == curUID) && (gid == -1 || gid == curGID) {
+		return nil
+	}
+	return os.Chown(p, uid, gid)
+}
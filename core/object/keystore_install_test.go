@@ -0,0 +1,94 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestKeystoreInstallKey(t *testing.T) {
+	root, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+
+	p, _ := path.Parse("test/cfg/c1")
+	o := NewCfg(p)
+	require.NoError(t, o.Add(OptsAdd{Key: "app.conf", Value: "a=1"}))
+	require.NoError(t, o.Add(OptsAdd{Key: "certs/ca.pem", Value: "ca"}))
+	require.NoError(t, o.Add(OptsAdd{Key: "certs/cert.pem", Value: "cert"}))
+	dir := filepath.Join(root, "vol")
+
+	t.Run("install a key to a file", func(t *testing.T) {
+		dst := filepath.Join(dir, "etc", "app.conf")
+		changed, err := o.InstallKey(OptsInstallKey{Key: "app.conf", Path: dst, Perm: 0600, DirPerm: 0750, UID: -1, GID: -1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{dst}, changed)
+		b, err := ioutil.ReadFile(dst)
+		require.NoError(t, err)
+		assert.Equal(t, "a=1", string(b))
+		info, err := os.Stat(dst)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		info, err = os.Stat(filepath.Dir(dst))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+	})
+
+	t.Run("unchanged content is not reported", func(t *testing.T) {
+		dst := filepath.Join(dir, "etc", "app.conf")
+		require.NoError(t, os.Chmod(dst, 0644))
+		changed, err := o.InstallKey(OptsInstallKey{Key: "app.conf", Path: dst, Perm: 0600, UID: -1, GID: -1})
+		require.NoError(t, err)
+		assert.Empty(t, changed)
+		info, err := os.Stat(dst)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "the mode is fixed even if the content is unchanged")
+	})
+
+	t.Run("changed content is reported", func(t *testing.T) {
+		dst := filepath.Join(dir, "etc", "app.conf")
+		require.NoError(t, o.Change(OptsAdd{Key: "app.conf", Value: "a=2"}))
+		changed, err := o.InstallKey(OptsInstallKey{Key: "app.conf", Path: dst, UID: -1, GID: -1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{dst}, changed)
+		b, err := ioutil.ReadFile(dst)
+		require.NoError(t, err)
+		assert.Equal(t, "a=2", string(b))
+		info, err := os.Stat(dst)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultInstalledFileMode, info.Mode().Perm())
+	})
+
+	t.Run("install a key glob to a directory", func(t *testing.T) {
+		certs := filepath.Join(dir, "certs")
+		changed, err := o.InstallKey(OptsInstallKey{Key: "certs/*", Path: certs, UID: -1, GID: -1})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{filepath.Join(certs, "ca.pem"), filepath.Join(certs, "cert.pem")}, changed)
+		b, err := ioutil.ReadFile(filepath.Join(certs, "cert.pem"))
+		require.NoError(t, err)
+		assert.Equal(t, "cert", string(b))
+	})
+
+	t.Run("install a key to a directory", func(t *testing.T) {
+		changed, err := o.InstallKey(OptsInstallKey{Key: "app.conf", Path: dir + "/", UID: -1, GID: -1})
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "app.conf")}, changed)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := o.InstallKey(OptsInstallKey{Path: dir})
+		assert.Error(t, err)
+		_, err = o.InstallKey(OptsInstallKey{Key: "app.conf"})
+		assert.Error(t, err)
+		_, err = o.InstallKey(OptsInstallKey{Key: "notexist", Path: filepath.Join(dir, "x"), UID: -1, GID: -1})
+		assert.Error(t, err)
+	})
+}
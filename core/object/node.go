@@ -41,16 +41,7 @@ func (t *Node) init(opts ...funcopt.O) error {
 		return err
 	}
 
-	t.log = logging.Configure(logging.Config{
-		ConsoleLoggingEnabled: true,
-		EncodeLogsAsJSON:      true,
-		FileLoggingEnabled:    true,
-		Directory:             t.LogDir(),
-		Filename:              "node.log",
-		MaxSize:               5,
-		MaxBackups:            1,
-		MaxAge:                30,
-	}).
+	t.log = logging.Configure(newLogConfig(t.LogDir(), "node.log")).
 		With().
 		Str("n", hostname.Hostname()).
 		Str("sid", xsession.ID).
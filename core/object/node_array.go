@@ -0,0 +1,57 @@
+package object
+
+import (
+	"fmt"
+
+	"opensvc.com/opensvc/core/array"
+)
+
+// ListArrays returns the names of the arrays declared in the node and
+// cluster configuration.
+func (t *Node) ListArrays() []string {
+	return array.Names(t.MergedConfig())
+}
+
+// Array returns the driver of the array <name>.
+func (t *Node) Array(name string) (array.Driver, error) {
+	return array.New(name, t.MergedConfig())
+}
+
+func (t *Node) arrayDisker(name string) (array.Disker, error) {
+	a, err := t.Array(name)
+	if err != nil {
+		return nil, err
+	}
+	i, ok := a.(array.Disker)
+	if !ok {
+		return nil, fmt.Errorf("array %s: the %s driver does not support disk management", name, a.Type())
+	}
+	return i, nil
+}
+
+// ArrayAddDisk allocates a disk from the array <name>.
+func (t *Node) ArrayAddDisk(name string, options array.OptsAddDisk) (array.Disk, error) {
+	a, err := t.arrayDisker(name)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	return a.AddDisk(options)
+}
+
+// ArrayDelDisk frees a disk allocated from the array <name>.
+func (t *Node) ArrayDelDisk(name string, options array.OptsDelDisk) (array.Disk, error) {
+	a, err := t.arrayDisker(name)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	return a.DelDisk(options)
+}
+
+// ArrayResizeDisk resizes a disk allocated from the array <name>.
+func (t *Node) ArrayResizeDisk(name string, options array.OptsResizeDisk) (array.Disk, error) {
+	a, err := t.arrayDisker(name)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	return a.ResizeDisk(options)
+}
@@ -1,6 +1,8 @@
 package object
 
 import (
+	"errors"
+
 	"opensvc.com/opensvc/util/capabilities"
 )
 
@@ -27,10 +29,14 @@ func (t Node) NodeScanCapabilities() (interface{}, error) {
 	return NodeCapabilities(capabilities.Data()), nil
 }
 
-// PrintCapabilities load and return node capabilities
+// PrintCapabilities load and return node capabilities, scanning them
+// first if the capabilities cache is missing or corrupt.
 func (t Node) PrintCapabilities() (interface{}, error) {
 	caps, err := capabilities.Load()
-	if err != nil {
+	switch {
+	case errors.Is(err, capabilities.ErrorNeedScan):
+		return t.NodeScanCapabilities()
+	case err != nil:
 		return nil, err
 	}
 	return NodeCapabilities(caps), nil
@@ -0,0 +1,38 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestPrintCapabilities(t *testing.T) {
+	root, err := ioutil.TempDir("", "capabilities")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{
+		"osvc_root_path": root,
+	})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "var"), os.ModePerm))
+	capFile := filepath.Join(root, "var", "capabilities.json")
+
+	t.Run("scan when not yet scanned", func(t *testing.T) {
+		_, err := Node{}.PrintCapabilities()
+		require.NoError(t, err)
+		_, err = os.Stat(capFile)
+		assert.NoError(t, err, "the scan result must be persisted")
+	})
+
+	t.Run("load the persisted scan result", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(capFile, []byte(`["c1","c2"]`), 0600))
+		caps, err := Node{}.PrintCapabilities()
+		require.NoError(t, err)
+		assert.Equal(t, NodeCapabilities{"c1", "c2"}, caps)
+	})
+}
@@ -0,0 +1,90 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// collectorURL returns the url of a collector xmlrpc server: the url set
+// by the node.<option> keyword, or if not set, the url with the same
+// scheme, host and port as node.dbopensvc and the path p. The path of
+// node.dbopensvc itself is kept if set, as it can be left unspecified. An
+// empty option always derives the url from node.dbopensvc. It returns an
+// empty string if no collector is configured.
+func (t Node) collectorURL(option, p string) string {
+	config := t.MergedConfig()
+	var s string
+	if option != "" {
+		s = config.GetString(key.New("node", option))
+	}
+	if s != "" && option != "dbopensvc" {
+		return s
+	}
+	if s == "" {
+		s = config.GetString(key.New("node", "dbopensvc"))
+	}
+	if s == "" {
+		return ""
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Log().Warn().Err(err).Msg("parse node.dbopensvc")
+		return ""
+	}
+	if option != "dbopensvc" || u.Path == "" || u.Path == "/" {
+		u.Path = p
+	}
+	return u.String()
+}
+
+// ProvisioningTemplate returns the definition of the provisioning template
+// named or identified by s, served by the collector rest api.
+func (t Node) ProvisioningTemplate(s string) (string, error) {
+	var data struct {
+		Data []struct {
+			Definition string `json:"tpl_definition"`
+		} `json:"data"`
+	}
+	p := "/provisioning_templates/" + url.PathEscape(s) + "?props=tpl_definition&meta=0"
+	if err := t.collectorRestAPIGet(p, &data); err != nil {
+		return "", err
+	}
+	if len(data.Data) == 0 {
+		return "", fmt.Errorf("provisioning template %s not found", s)
+	}
+	return data.Data[0].Definition, nil
+}
+
+// collectorRestAPIGet decodes in v the json response of the collector
+// rest api handler p, authenticated by the node name and uuid.
+func (t Node) collectorRestAPIGet(p string, v interface{}) error {
+	u := t.collectorURL("", "/init/rest/api")
+	if u == "" {
+		return fmt.Errorf("node.dbopensvc is not set")
+	}
+	req, err := http.NewRequest(http.MethodGet, u+p, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(hostname.Hostname(), t.MergedConfig().GetString(key.New("node", "uuid")))
+	c := &http.Client{Timeout: 30 * time.Second}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("collector rest api %s: %s", p, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,98 @@
+package object
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/core/compliance"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+// OptsNodeCompliance is the options of the compliance node methods.
+type OptsNodeCompliance struct {
+	Global     OptsGlobal
+	Modulesets string `flag:"modulesets"`
+	Modules    string `flag:"modules"`
+	Force      bool   `flag:"force"`
+}
+
+// ComplianceCheck runs the check action of the selected compliance
+// modules.
+func (t Node) ComplianceCheck(options OptsNodeCompliance) (compliance.Results, error) {
+	c, err := t.newCompliance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Check(context.Background(), options.selection())
+}
+
+// ComplianceFix runs the fix action of the selected non compliant
+// compliance modules, or of all the selected modules with options.Force.
+func (t Node) ComplianceFix(options OptsNodeCompliance) (compliance.Results, error) {
+	c, err := t.newCompliance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Fix(context.Background(), options.selection())
+}
+
+// ComplianceAuto runs the check action of the selected compliance modules,
+// and the fix action of the non compliant modules flagged autofix.
+func (t Node) ComplianceAuto(options OptsNodeCompliance) (compliance.Results, error) {
+	c, err := t.newCompliance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Auto(context.Background(), options.selection())
+}
+
+func (t OptsNodeCompliance) selection() compliance.Selection {
+	return compliance.Selection{
+		Modulesets: splitCommaList(t.Modulesets),
+		Modules:    splitCommaList(t.Modules),
+		Force:      t.Force,
+	}
+}
+
+func splitCommaList(s string) []string {
+	l := make([]string, 0)
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+// ComplianceDir returns the directory where the compliance modules are
+// installed.
+func (t Node) ComplianceDir() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "compliance")
+}
+
+// ComplianceDataFile returns the path of the json file storing the
+// compliance modulesets and rulesets attached to the node.
+func (t Node) ComplianceDataFile() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "compliance.json")
+}
+
+func (t Node) newCompliance() (*compliance.T, error) {
+	data, err := compliance.LoadData(t.ComplianceDataFile())
+	if err != nil {
+		return nil, err
+	}
+	opts := []funcopt.O{
+		compliance.WithDir(t.ComplianceDir()),
+		compliance.WithData(data),
+		compliance.WithLogger(t.Log()),
+	}
+	if s := t.collectorURL("dbcompliance", "/init/compliance/call/xmlrpc"); s != "" {
+		uuid := t.MergedConfig().GetString(key.New("node", "uuid"))
+		opts = append(opts, compliance.WithReporter(compliance.NewCollector(s, hostname.Hostname(), uuid, data)))
+	}
+	return compliance.New(opts...), nil
+}
@@ -0,0 +1,60 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	// NodeDrivers is the list of the resource drivers registered in the
+	// agent.
+	NodeDrivers []NodeDriver
+
+	// NodeDriver describes a registered resource driver, and its usability
+	// on the node.
+	NodeDriver struct {
+		Group      string `json:"group"`
+		Name       string `json:"name"`
+		Capability string `json:"capability"`
+		Usable     bool   `json:"usable"`
+	}
+)
+
+// Render is a human rendered for the node drivers
+func (t NodeDrivers) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText("Group").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Name").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Capability").SetColor(rawconfig.Node.Color.Bold)
+	tree.AddColumn().AddText("Usable").SetColor(rawconfig.Node.Color.Bold)
+	for _, d := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(d.Group).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(d.Name).SetColor(rawconfig.Node.Color.Primary)
+		n.AddColumn().AddText(d.Capability)
+		if d.Usable {
+			n.AddColumn().AddText("yes")
+		} else {
+			n.AddColumn().AddText("no").SetColor(rawconfig.Node.Color.Error)
+		}
+	}
+	return tree.Render()
+}
+
+// PrintDrivers returns the registered resource drivers, with the node
+// capability they require and their usability on this node.
+func (t Node) PrintDrivers() (interface{}, error) {
+	l := resource.RegisteredDrivers()
+	data := make(NodeDrivers, len(l))
+	for i, d := range l {
+		data[i] = NodeDriver{
+			Group:      d.ID.Group.String(),
+			Name:       d.ID.Name,
+			Capability: d.Capability,
+			Usable:     d.Capability == "" || capabilities.Has(d.Capability),
+		}
+	}
+	return data, nil
+}
@@ -1,6 +1,8 @@
 package object
 
 import (
+	"strings"
+
 	"opensvc.com/opensvc/core/envs"
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/kind"
@@ -340,6 +342,11 @@ var commonKeywords = []keywords.Keyword{
 		Candidates: envs.List,
 		Text:       "A non-PRD service can not be brought up on a PRD node, but a PRD service can be startup on a non-PRD node (in a DRP situation).",
 	},
+	{
+		Section: "node",
+		Option:  "nodename",
+		Text:    "Override the operating system hostname as the node name, for example in containers. The :envvar:`OSVC_HOSTNAME` environment variable has precedence over this keyword. The value must be a lowercase RFC 1123 hostname.",
+	},
 	{
 		Section:   "node",
 		Option:    "max_parallel",
@@ -547,6 +554,13 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Example:   "blockdev, mem_u",
 		Text:      "Disable push for a stats group (mem_u, cpu, proc, swap, netdev, netdev_err, block, blockdev, fs_u).",
 	},
+	{
+		Section:   "stats",
+		Option:    "interval",
+		Converter: converters.Duration,
+		Default:   "1m",
+		Text:      "The interval between two node resources usage samples of the :cmd:`om daemon collect-stats` collector. The sample rates are averaged over this interval.",
+	},
 	{
 		Section: "checks",
 		Option:  "schedule",
@@ -653,6 +667,131 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Example: "https://keycloak.opensvc.com/auth/realms/clusters/.well-known/openid-configuration",
 		Text:    "The url serving the well-known configuration of an openid provider. If set, the h2 listener will try to validate the Bearer token provided in the requests. If valid the user name is fetched from the 'preferred_username' claim (fallback on 'name'), and the user grants are fetched from the 'grant' claim. Grant can be a list, in which case a proper grant value is formatted via concatenation of the list elements.",
 	},
+	{
+		Section: "metrics",
+		Option:  "addr",
+		Default: "::",
+		Example: "1.2.3.4",
+		Text:    "The ip addr the prometheus metrics exporter must listen on.",
+	},
+	{
+		Section:   "metrics",
+		Option:    "port",
+		Converter: converters.Int,
+		Default:   "9114",
+		Text:      "The port the prometheus metrics exporter must listen on. The metrics are served on the /metrics path.",
+	},
+	{
+		Section: "metrics",
+		Option:  "cert",
+		Example: "/etc/opensvc/metrics.crt",
+		Text:    "The path of the certificate file of the prometheus metrics exporter tls listener. The tls listener is enabled if both :kw:`metrics.cert` and :kw:`metrics.key` are set.",
+	},
+	{
+		Section: "metrics",
+		Option:  "key",
+		Example: "/etc/opensvc/metrics.key",
+		Text:    "The path of the private key file of the prometheus metrics exporter tls listener.",
+	},
+	{
+		Section:  "notify",
+		Option:   "url",
+		Required: true,
+		Example:  "https://hooks.slack.com/services/T000/B000/XXXX",
+		Text:     "The url the notifications are posted to. The section rindex is the notifier name used in the logs. For the ``snmp`` type, the url is ``snmp://<host>[:<port>]``, the port defaulting to 162.",
+	},
+	{
+		Section:    "notify",
+		Option:     "type",
+		Candidates: []string{"webhook", "slack", "snmp"},
+		Default:    "webhook",
+		Text:       "The notification format. ``webhook`` posts a json document with the ``event``, ``text`` and ``suppressed`` keys. ``slack`` posts a slack-compatible json document with only the ``text`` key. ``snmp`` sends a trap defined by the OPENSVC-MIB.",
+	},
+	{
+		Section:     "notify",
+		Option:      "events",
+		Converter:   converters.List,
+		Candidates:  []string{"object_warn", "object_down", "object_avail", "failover", "frozen", "hb_stale", "node_status", "split"},
+		DefaultText: "object_warn object_down failover frozen hb_stale split for the webhook and slack types, object_avail failover frozen hb_stale node_status split for the snmp type.",
+		Example:     "object_down failover",
+		Text:        "The kinds of events to notify.",
+	},
+	{
+		Section:    "notify",
+		Option:     "version",
+		Types:      []string{"snmp"},
+		Candidates: []string{"2c", "3"},
+		Default:    "2c",
+		Text:       "The snmp version of the traps.",
+	},
+	{
+		Section: "notify",
+		Option:  "community",
+		Types:   []string{"snmp"},
+		Default: "public",
+		Text:    "The snmp v2c community of the traps.",
+	},
+	{
+		Section: "notify",
+		Option:  "user",
+		Types:   []string{"snmp"},
+		Example: "opensvc",
+		Text:    "The snmp v3 user name of the traps. Required for the snmp version 3.",
+	},
+	{
+		Section:     "notify",
+		Option:      "engine_id",
+		Types:       []string{"snmp"},
+		Example:     "800000000406e6f646531",
+		DefaultText: "A text format engine id built from the nodename.",
+		Text:        "The hex encoded snmp v3 engine id of the traps sender. The trap receivers must declare the user with this engine id.",
+	},
+	{
+		Section:    "notify",
+		Option:     "auth_protocol",
+		Types:      []string{"snmp"},
+		Candidates: []string{"md5", "sha"},
+		Text:       "The snmp v3 authentication protocol. If not set, the traps are not authenticated.",
+	},
+	{
+		Section: "notify",
+		Option:  "auth_password",
+		Types:   []string{"snmp"},
+		Text:    "The snmp v3 authentication password.",
+	},
+	{
+		Section:    "notify",
+		Option:     "priv_protocol",
+		Types:      []string{"snmp"},
+		Candidates: []string{"aes"},
+		Text:       "The snmp v3 privacy protocol. If not set, the traps are not encrypted. Requires :kw:`notify.auth_protocol`.",
+	},
+	{
+		Section: "notify",
+		Option:  "priv_password",
+		Types:   []string{"snmp"},
+		Text:    "The snmp v3 privacy password.",
+	},
+	{
+		Section: "notify",
+		Option:  "template",
+		Example: "{{.Cluster}}: {{.Kind}} {{.Path}} on {{.Node}}",
+		Text:    "The go text/template formatting the notification text. The template data is the event, with the ``Kind``, ``Cluster``, ``Path``, ``Node``, ``Heartbeat``, ``From``, ``To`` and ``Time`` fields, and the ``Text`` method returning the default description.",
+	},
+	{
+		Section:   "notify",
+		Option:    "rate_limit",
+		Converter: converters.Int,
+		Default:   "10",
+		Text:      "The maximum number of notifications posted per :kw:`notify.rate_interval`. The notifications exceeding the limit are suppressed, and their count is reported by the next posted notification. ``0`` disables the rate limiting.",
+	},
+	{
+		Section:   "notify",
+		Option:    "rate_interval",
+		Converter: converters.Duration,
+		Default:   "1m",
+		Text:      "The rate limiting window of :kw:`notify.rate_limit`.",
+	},
 	{
 		Section: "syslog",
 		Option:  "facility",
@@ -678,6 +817,58 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Default: "514",
 		Text:    "The syslog host to send logs to. If neither host nor port are specified and if /dev/log exists, the messages are posted to /dev/log.",
 	},
+	{
+		Section:    "syslog",
+		Option:     "protocol",
+		Candidates: []string{"udp", "tcp"},
+		Default:    "udp",
+		Text:       "The transport protocol of the messages sent to :kw:`syslog.host`. The tcp messages are framed by octet counting.",
+	},
+	{
+		Section: "syslog",
+		Option:  "sd_id",
+		Default: "opensvc@32473",
+		Text:    "The rfc5424 structured data element id the log entry fields are sent as. The default enterprise number is the one reserved for documentation, so set the site registered enterprise number if the log collector requires one.",
+	},
+	{
+		Section:    "journald",
+		Option:     "level",
+		Default:    "info",
+		Candidates: []string{"critical", "error", "warning", "info", "debug"},
+		Text:       "The minimum message criticity to feed to journald.",
+	},
+	{
+		Section:    "log",
+		Option:     "sinks",
+		Converter:  converters.List,
+		Candidates: []string{"syslog", "journald"},
+		Example:    "syslog journald",
+		Text:       "The destinations the log entries are duplicated to, in addition to the console and the log files. ``syslog`` sends rfc5424 messages with the entry fields as structured data, as configured by the syslog section. ``journald`` sends the entries to the journald native socket, with the fields prefixed by ``OPENSVC_``.",
+	},
+	{
+		Section:   "log",
+		Option:    "max_size",
+		Converter: converters.Size,
+		Default:   "5m",
+		Example:   "20m",
+		Text:      "The size above which the node and object log files are rotated. The value is rounded down to the megabyte.",
+	},
+	{
+		Section:   "log",
+		Option:    "max_backups",
+		Converter: converters.Int,
+		Default:   "1",
+		Example:   "3",
+		Text:      "The number of rotated node and object log files to keep. ``0`` keeps all the rotated files, within the ``max_age`` limit.",
+	},
+	{
+		Section:   "log",
+		Option:    "max_age",
+		Converter: converters.Int,
+		Default:   "30",
+		Example:   "7",
+		Text:      "The number of days to keep the rotated node and object log files. ``0`` disables the age-based removal.",
+	},
 	{
 		Section:  "cluster",
 		Option:   "vip",
@@ -887,7 +1078,7 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Types:    []string{"relay"},
 		Required: true,
 		Example:  "relaynode1",
-		Text:     "The relay resolvable node name.",
+		Text:     "The relay resolvable node name, optionally followed by :<port>. The default port is 1217.",
 	},
 	{
 		Section:  "hb",
@@ -895,7 +1086,46 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Types:    []string{"relay"},
 		Required: true,
 		Example:  "123123123124325543565",
-		Text:     "The secret to use to encrypt/decrypt data exchanged with the relay (AES256).",
+		Text:     "The secret the node authenticates with to the relay server, set in the relay node ``relay#<cluster id>`` section. The relay is reached over https.",
+	},
+	{
+		Section: "relay",
+		Option:  "addr",
+		Default: "::",
+		Example: "1.2.3.4",
+		Text:    "The ip addr the relay heartbeat server must listen on. The relay server is started by the daemon if at least one ``relay#<cluster id>`` section is defined.",
+	},
+	{
+		Section:   "relay",
+		Option:    "port",
+		Converter: converters.Int,
+		Default:   "1217",
+		Text:      "The port the relay heartbeat server must listen on.",
+	},
+	{
+		Section: "relay",
+		Option:  "cert",
+		Example: "/etc/opensvc/relay.crt",
+		Text:    "The path of the certificate file of the relay heartbeat server tls listener. Both :kw:`relay.cert` and :kw:`relay.key` are required: the relay server does not start without tls, as the clients send their cluster secret in the http basic auth header.",
+	},
+	{
+		Section: "relay",
+		Option:  "key",
+		Example: "/etc/opensvc/relay.key",
+		Text:    "The path of the private key file of the relay heartbeat server tls listener. Required, see :kw:`relay.cert`.",
+	},
+	{
+		Section:   "relay",
+		Option:    "retention",
+		Converter: converters.Duration,
+		Default:   "1m",
+		Text:      "The age of the heartbeat slots expired by the relay server. The readers of an expired slot see the peer as stale.",
+	},
+	{
+		Section: "relay",
+		Option:  "secret",
+		Example: "123123123124325543565",
+		Text:    "Set in a ``relay#<cluster id>`` section, the secret the nodes of this cluster authenticate with to the relay server.",
 	},
 	{
 		Section: "cni",
@@ -1160,10 +1390,18 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 	},
 	{
 		Section:  "network",
-		Types:    []string{"routed_bridge"},
+		Types:    []string{"bridge", "routed_bridge"},
 		Option:   "gateway",
 		Scopable: true,
-		Text:     "The gateway to use to reach the network segment of the node specified as scope.",
+		Text:     "The gateway to use to reach the network segment of the node specified as scope. This address is never allocated by the ipam.",
+	},
+	{
+		Section:   "network",
+		Types:     []string{"bridge", "routed_bridge"},
+		Option:    "ranges",
+		Converter: converters.List,
+		Example:   "10.22.0.10-10.22.0.99 10.22.1.10-10.22.1.99",
+		Text:      "The list of ``<first>-<last>`` address ranges the ipam allocates the ip resources addresses from. The default is the whole :kw:`network`, except its first and last addresses.",
 	},
 	{
 		Section:   "network",
@@ -1198,6 +1436,14 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Candidates: []string{"auto", "always", "never"},
 		Text:       "Create and route trafic through tunnels to peer nodes policy. ``auto`` tunnel if the peer is not in the same subnet, ``always`` tunnel even if the peer seems to be in the same subnet (some hosting providers require this as traffic goes through router even between adjacent nodes.",
 	},
+	{
+		Section:    "network",
+		Types:      []string{"routed_bridge"},
+		Option:     "tunnel_mode",
+		Default:    "ipip",
+		Candidates: []string{"ipip", "vxlan"},
+		Text:       "The type of tunnels to the peer nodes. ``ipip`` creates an ipip tunnel per peer node. ``vxlan`` creates a single vxlan interface per network, the peer nodes being its flooding destinations, and routes the peer subnets via their first address.",
+	},
 	{
 		Section: "network",
 		Types:   []string{"bridge", "routed_bridge"},
@@ -1575,11 +1821,40 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Example:   "2000",
 		Text:      "The nexenta administration listener port.",
 	},
+	{
+		Section: "vault",
+		Option:  "addr",
+		Example: "https://vault.mycorp:8200",
+		Text:    "The address of the vault server resolving the vault://<path>#<field> sec key values. The VAULT_ADDR environment variable has precedence.",
+	},
+	{
+		Section: "vault",
+		Option:  "token",
+		Text:    "The token used to authenticate to the vault server. The VAULT_TOKEN environment variable has precedence. Prefer :kw:`token_file` to avoid storing the token in the node configuration.",
+	},
+	{
+		Section: "vault",
+		Option:  "token_file",
+		Example: "/etc/opensvc/vault.token",
+		Text:    "The path of a file containing the token used to authenticate to the vault server.",
+	},
+	{
+		Section: "vault",
+		Option:  "namespace",
+		Example: "ns1",
+		Text:    "The vault enterprise namespace to send in the requests.",
+	},
 }
 
 var nodeKeywordStore = keywords.Store(append(privateKeywords, commonKeywords...))
 
 func (t Node) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
+	return lookupNodeKeyword(nodeKeywordStore, k, sectionType)
+}
+
+// lookupNodeKeyword returns the keyword of a node.conf-like
+// configuration, like node.conf or cluster.conf, from the store.
+func lookupNodeKeyword(store keywords.Store, k key.T, sectionType string) keywords.Keyword {
 	switch k.Section {
 	case "data", "env", "labels":
 		return keywords.Keyword{
@@ -1588,5 +1863,24 @@ func (t Node) KeywordLookup(k key.T, sectionType string) keywords.Keyword {
 			Required: false,
 		}
 	}
-	return nodeKeywordStore.Lookup(k, kind.Invalid, sectionType)
+	if strings.HasPrefix(k.Section, "profile#") {
+		// resource profiles accept any resource keyword
+		return keywords.Keyword{
+			Option:   "*", // trick IsZero()
+			Scopable: true,
+			Required: false,
+		}
+	}
+	if kw := store.Lookup(k, kind.Invalid, sectionType); !kw.IsZero() {
+		return kw
+	}
+	if sectionType == "" {
+		return keywords.Keyword{}
+	}
+	// keywords common to all the section types, like hb.timeout or
+	// notify.url, declare no Types.
+	if kw := store.Lookup(k, kind.Invalid, ""); len(kw.Types) == 0 {
+		return kw
+	}
+	return keywords.Keyword{}
 }
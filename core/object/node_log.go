@@ -3,18 +3,30 @@ package object
 import (
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/util/logging"
+	"opensvc.com/opensvc/util/sizeconv"
 )
 
 // ConfigureLog configures the zerolog logger with console writer and lumberjack rotating file writer.
 func ConfigureLog() *logging.Logger {
-	return logging.Configure(logging.Config{
+	return logging.Configure(newLogConfig(rawconfig.Node.Paths.Log, "node"))
+}
+
+// newLogConfig returns the logging configuration of a log file, with the
+// rotation thresholds set by the log.max_size, log.max_backups and
+// log.max_age node keywords.
+func newLogConfig(dir, filename string) logging.Config {
+	maxSize := 5
+	if i, err := sizeconv.FromSize(rawconfig.Node.Log.MaxSize); err == nil && i >= sizeconv.MiB {
+		maxSize = int(i / sizeconv.MiB)
+	}
+	return logging.Config{
 		ConsoleLoggingEnabled: true,
 		EncodeLogsAsJSON:      true,
 		FileLoggingEnabled:    true,
-		Directory:             rawconfig.Node.Paths.Log,
-		Filename:              "node",
-		MaxSize:               5,
-		MaxBackups:            1,
-		MaxAge:                30,
-	})
+		Directory:             dir,
+		Filename:              filename,
+		MaxSize:               maxSize,
+		MaxBackups:            rawconfig.Node.Log.MaxBackups,
+		MaxAge:                rawconfig.Node.Log.MaxAge,
+	}
 }
@@ -0,0 +1,66 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+// ListNetworks returns the names of the networks declared in the node and
+// cluster configuration.
+func (t *Node) ListNetworks() []string {
+	return network.Names(t.MergedConfig())
+}
+
+// Network returns the definition of the network <name>.
+func (t *Node) Network(name string) (*network.T, error) {
+	return network.New(name, t.MergedConfig())
+}
+
+//
+// NetworkSetup installs the tunnels and routes to the peer nodes subnets
+// of the routed_bridge networks, so the containers addresses are
+// reachable cluster-wide.
+//
+func (t *Node) NetworkSetup() error {
+	for _, name := range t.ListNetworks() {
+		n, err := t.Network(name)
+		if err != nil {
+			return err
+		}
+		if n.Type != "routed_bridge" {
+			continue
+		}
+		t.Log().Info().Msgf("setup network %s", name)
+		if err := n.Setup(hostname.Hostname()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//
+// NetworkStatus returns the usage and setup state of the networks on the
+// local node, with their leases if verbose is set. The networks with a
+// configuration error are reported with this error.
+//
+func (t *Node) NetworkStatus(name string, verbose bool) network.StatusList {
+	l := make(network.StatusList, 0)
+	for _, s := range t.ListNetworks() {
+		if name != "" && name != s {
+			continue
+		}
+		n, err := t.Network(s)
+		if err != nil {
+			l = append(l, network.Status{Name: s, Errors: []string{err.Error()}})
+			continue
+		}
+		data := n.Status(hostname.Hostname())
+		if verbose {
+			if data.Leases, err = n.Leases(); err != nil {
+				data.Errors = append(data.Errors, err.Error())
+			}
+		}
+		l = append(l, data)
+	}
+	return l
+}
@@ -3,28 +3,81 @@ package object
 import (
 	"strings"
 
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/pool"
 	"opensvc.com/opensvc/drivers/pooldirectory"
 	"opensvc.com/opensvc/drivers/poolshm"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/sizeconv"
 )
 
-func (t *Node) ShowPoolsByName(name string) pool.StatusList {
+//
+// ShowPoolsByName returns the usage of the pool <name>, or of all the
+// pools if name is empty. The volumes allocated from the pools are
+// included if verbose is set.
+//
+func (t *Node) ShowPoolsByName(name string, verbose bool) pool.StatusList {
+	var volumes map[string]pool.VolumeStatusList
+	if verbose {
+		volumes = t.PoolVolumes()
+	}
 	l := pool.NewStatusList()
 	for _, p := range t.Pools() {
 		if name != "" && name != p.Name() {
 			continue
 		}
 		l = l.Add(p, true)
+		if vols, ok := volumes[p.Name()]; ok {
+			l[len(l)-1].Volumes = vols
+		}
 	}
 	return l
 }
 
 func (t *Node) ShowPools() pool.StatusList {
-	l := pool.NewStatusList()
-	for _, p := range t.Pools() {
-		l = l.Add(p, true)
+	return t.ShowPoolsByName("", false)
+}
+
+//
+// PoolVolumes returns the locally installed volumes, indexed by the name
+// of the pool they were allocated from. A volume without children is
+// reported as orphan.
+//
+func (t *Node) PoolVolumes() map[string]pool.VolumeStatusList {
+	m := make(map[string]pool.VolumeStatusList)
+	paths, err := Installed()
+	if err != nil {
+		t.Log().Warn().Err(err).Msg("list installed volumes")
+		return m
 	}
-	return l
+	for _, p := range paths {
+		if p.Kind != kind.Vol {
+			continue
+		}
+		o := NewVol(p, WithVolatile(true))
+		// the pool keywords set by the volume allocation are not
+		// declared in the vol kind keywords, so read their raw value.
+		poolName := o.Config().Get(key.Parse("pool"))
+		if poolName == "" {
+			continue
+		}
+		data := pool.VolumeStatus{
+			Path:     p,
+			Children: make([]path.T, 0),
+		}
+		if size, err := sizeconv.FromSize(o.Config().Get(key.Parse("size"))); err == nil {
+			data.Size = float64(size)
+		}
+		for _, rel := range o.Children() {
+			if child, err := rel.Path(); err == nil {
+				data.Children = append(data.Children, child)
+			}
+		}
+		data.Orphan = len(data.Children) == 0
+		m[poolName] = append(m[poolName], data)
+	}
+	return m
 }
 
 func (t *Node) Pools() []pool.Pooler {
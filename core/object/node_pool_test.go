@@ -0,0 +1,49 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestNodePoolVolumes(t *testing.T) {
+	root, err := ioutil.TempDir("", "poolvol")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	volDir := filepath.Join(root, "etc", "namespaces", "ns1", "vol")
+	require.NoError(t, os.MkdirAll(volDir, 0755))
+	files := map[string]string{
+		"v1.conf": "[DEFAULT]\npool = default\nsize = 1m\nchildren = ns1/svc/s1\n",
+		"v2.conf": "[DEFAULT]\npool = default\nsize = 2m\n",
+		"v3.conf": "[DEFAULT]\nnodes = *\n",
+	}
+	for name, s := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(volDir, name), []byte(s), 0644))
+	}
+
+	m := NewNode().PoolVolumes()
+	require.Len(t, m, 1, "the volumes without pool are ignored")
+	l := m["default"]
+	require.Len(t, l, 2)
+	assert.Equal(t, "ns1/vol/v1", l[0].Path.String())
+	assert.Equal(t, float64(1024*1024), l[0].Size)
+	assert.Equal(t, "ns1/svc/s1", l[0].Children[0].String())
+	assert.False(t, l[0].Orphan)
+	assert.True(t, l[1].Orphan)
+
+	t.Run("the volumes are reported in verbose mode only", func(t *testing.T) {
+		for _, s := range NewNode().ShowPoolsByName("default", true) {
+			assert.Len(t, s.Volumes, 2)
+		}
+		for _, s := range NewNode().ShowPoolsByName("default", false) {
+			assert.Len(t, s.Volumes, 0)
+		}
+	})
+}
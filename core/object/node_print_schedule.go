@@ -0,0 +1,80 @@
+package object
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/schedule"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+type (
+	// nodeScheduleDef associates a node scheduled action to its schedule
+	// keyword and last run file basename.
+	nodeScheduleDef struct {
+		Action string
+		Key    string
+		Base   string
+	}
+)
+
+var nodeScheduleDefs = []nodeScheduleDef{
+	{Action: "auto_reboot", Key: "reboot.schedule", Base: "auto_reboot"},
+	{Action: "collect_stats", Key: "stats_collection.schedule", Base: "collect_stats"},
+	{Action: "compliance_auto", Key: "compliance.schedule", Base: "comp_check"},
+	{Action: "dequeue_actions", Key: "dequeue_actions.schedule", Base: "dequeue_actions"},
+	{Action: "pushasset", Key: "asset.schedule", Base: "pushasset"},
+	{Action: "pushchecks", Key: "checks.schedule", Base: "pushchecks"},
+	{Action: "pushdisks", Key: "disks.schedule", Base: "pushdisks"},
+	{Action: "pushpatch", Key: "patches.schedule", Base: "pushpatch"},
+	{Action: "pushpkg", Key: "packages.schedule", Base: "pushpkg"},
+	{Action: "pushstats", Key: "stats.schedule", Base: "pushstats"},
+	{Action: "rotate_root_pw", Key: "rotate_root_pw.schedule", Base: "rotate_root_pw"},
+	{Action: "sysreport", Key: "sysreport.schedule", Base: "sysreport"},
+}
+
+// PrintSchedule display the node scheduling table
+func (t *Node) PrintSchedule() schedule.Table {
+	return t.Schedules()
+}
+
+// Schedules returns the node scheduled actions, with their last and
+// next run times.
+func (t *Node) Schedules() schedule.Table {
+	table := schedule.NewTable()
+	for _, def := range nodeScheduleDefs {
+		k := key.Parse(def.Key)
+		s, err := t.config.GetStringStrict(k)
+		if err != nil {
+			t.Log().Debug().Err(err).Msgf("schedule %s", k)
+			continue
+		}
+		table = table.AddEntry(schedule.Entry{
+			Node:       hostname.Hostname(),
+			Action:     def.Action,
+			Last:       timestamp.New(t.loadLast(def.Base)),
+			Key:        k.String(),
+			Definition: s,
+		})
+	}
+	return table.SetNext(time.Now())
+}
+
+func (t *Node) lastFilepath(base string) string {
+	return filepath.Join(t.VarDir(), "scheduler", "last_"+base)
+}
+
+func (t *Node) loadLast(base string) time.Time {
+	b, err := file.ReadAll(t.lastFilepath(base))
+	if err != nil {
+		return time.Unix(0, 0)
+	}
+	if ti, err := timestamp.Parse(strings.TrimSpace(string(b))); err == nil {
+		return ti
+	}
+	return time.Unix(0, 0)
+}
@@ -0,0 +1,41 @@
+package object
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/util/key"
+)
+
+//
+// PRKey returns the scsi3 persistent reservation key of the node, as set
+// by the node.prkey keyword. If not set, a key is derived from the node
+// id and stored in the node configuration, so the key is stable across
+// the node lifetime.
+//
+func (t Node) PRKey() (string, error) {
+	k := key.Parse("node.prkey")
+	if s := t.config.GetString(k); s != "" {
+		return s, nil
+	}
+	id := t.ID()
+	s := fmt.Sprintf("0x%016x", binary.BigEndian.Uint64(id[:8]))
+	op := keyop.T{
+		Key:   k,
+		Op:    keyop.Set,
+		Value: s,
+	}
+	if err := t.config.Set(op); err != nil {
+		return "", err
+	}
+	if err := t.config.Commit(); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// PrintPRKey returns the scsi3 persistent reservation key of the node.
+func (t Node) PrintPRKey() (interface{}, error) {
+	return t.PRKey()
+}
@@ -0,0 +1,39 @@
+package object
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func TestNodePRKey(t *testing.T) {
+	root, err := ioutil.TempDir("", "prkey")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+
+	t.Run("generated and stored if not set", func(t *testing.T) {
+		k, err := NewNode().PRKey()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(k, "0x"))
+		assert.Len(t, k, 18)
+		k2, err := NewNode().PRKey()
+		require.NoError(t, err)
+		assert.Equal(t, k, k2, "the generated key is persisted")
+	})
+
+	t.Run("read from the node configuration", func(t *testing.T) {
+		b := []byte("[node]\nprkey = 0x7d3f\n")
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "node.conf"), b, 0644))
+		k, err := NewNode().PRKey()
+		require.NoError(t, err)
+		assert.Equal(t, "0x7d3f", k)
+	})
+}
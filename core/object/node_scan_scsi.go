@@ -0,0 +1,45 @@
+package object
+
+import (
+	"opensvc.com/opensvc/util/scsi"
+)
+
+type (
+	// NodeScanSCSI contains the block devices appeared during a scsi scan
+	NodeScanSCSI struct {
+		New scsi.Inventory `json:"new"`
+	}
+)
+
+// Render is a human renderer for the scsi scan result
+func (t NodeScanSCSI) Render() string {
+	if len(t.New) == 0 {
+		return "no new device\n"
+	}
+	s := ""
+	for _, d := range t.New {
+		s = s + d.String() + "\n"
+	}
+	return s
+}
+
+// ScanSCSI rescans the scsi hosts, reloads the multipath maps, waits for
+// the udev events to settle, and returns the block devices that appeared.
+func (t Node) ScanSCSI() (interface{}, error) {
+	previous, err := scsi.Scan()
+	if err != nil {
+		return nil, err
+	}
+	if err := scsi.Rescan(t.Log()); err != nil {
+		return nil, err
+	}
+	if err := scsi.ReloadMultipath(t.Log()); err != nil {
+		return nil, err
+	}
+	scsi.Settle()
+	current, err := scsi.Scan()
+	if err != nil {
+		return nil, err
+	}
+	return NodeScanSCSI{New: current.Diff(previous)}, nil
+}
@@ -0,0 +1,89 @@
+package object
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/syspower"
+)
+
+// OptsNodeReboot is the options of the Reboot node method.
+type OptsNodeReboot struct {
+	OptForce
+}
+
+//
+// stopInstances stops all the local svc instances, then all the local vol
+// instances, so the volumes are not stopped while still in use.
+// The errors are logged, and the first one is returned after all
+// instances were tried.
+//
+func (t *Node) stopInstances() error {
+	paths, err := Installed()
+	if err != nil {
+		return err
+	}
+	var errs error
+	for _, k := range []kind.T{kind.Svc, kind.Vol} {
+		for _, p := range paths {
+			if p.Kind != k {
+				continue
+			}
+			if err := t.stopInstance(p); err != nil && errs == nil {
+				errs = err
+			}
+		}
+	}
+	return errs
+}
+
+func (t *Node) stopInstance(p path.T) error {
+	t.log.Info().Stringer("path", p).Msg("stop instance")
+	options := OptsStop{}
+	options.Force = true
+	if err := NewActorFromPath(p).Stop(context.Background(), options); err != nil {
+		t.log.Error().Err(err).Stringer("path", p).Msg("stop instance")
+		return errors.Wrapf(err, "%s", p)
+	}
+	return nil
+}
+
+// Shutdown stops all the local object instances, then halts and powers
+// off the node.
+func (t *Node) Shutdown() error {
+	if err := t.stopInstances(); err != nil {
+		return err
+	}
+	t.log.Info().Msg("power off")
+	return syspower.Poweroff()
+}
+
+//
+// Reboot stops all the local object instances, then reboots the node.
+//
+// With the force option, the node is rebooted immediately, without
+// stopping the instances. This is the method used by the object
+// monitor_action=reboot, when a monitored resource fails hard.
+//
+func (t *Node) Reboot(options OptsNodeReboot) error {
+	if options.Force {
+		t.log.Info().Msg("force reboot")
+		return syspower.ForceReboot()
+	}
+	if err := t.stopInstances(); err != nil {
+		return err
+	}
+	t.log.Info().Msg("reboot")
+	return syspower.Reboot()
+}
+
+//
+// Crash crashes the node kernel, so the peer nodes take over the object
+// instances as soon as possible. This is the method used by the object
+// monitor_action=crash.
+//
+func (t *Node) Crash() error {
+	t.log.Info().Msg("crash")
+	return syspower.Crash()
+}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"opensvc.com/opensvc/core/event"
+	"opensvc.com/opensvc/core/nodestats"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// OptsNodePrintStats is the options of the PrintStats node method.
+	OptsNodePrintStats struct {
+		Global OptsGlobal
+		From   string `flag:"stats-from"`
+		To     string `flag:"stats-to"`
+	}
+
+	// OptsNodePushStats is the options of the PushStats node method.
+	OptsNodePushStats struct {
+		Global OptsGlobal
+	}
+)
+
+// statsSampleInterval is the interval between the two counters readings
+// of the sample collected by PushStats.
+const statsSampleInterval = time.Second
+
+// StatsDB returns the local ring database of the node stats samples.
+func (t Node) StatsDB() *nodestats.DB {
+	return nodestats.NewDB(filepath.Join(rawconfig.Node.Paths.Var, "stats"))
+}
+
+// StatsDisabled returns the stats groups disabled by the stats.disable
+// keyword.
+func (t Node) StatsDisabled() []string {
+	return t.MergedConfig().GetSlice(key.New("stats", "disable"))
+}
+
+// PrintStats returns the stored stats samples between options.From and
+// options.To, which can be durations relative to now or RFC3339 dates.
+// The default range is the last 24 hours.
+func (t Node) PrintStats(options OptsNodePrintStats) (nodestats.Samples, error) {
+	now := time.Now()
+	from, err := event.ParseTime(options.From, now)
+	if err != nil {
+		return nil, err
+	}
+	if from.IsZero() {
+		from = now.Add(-24 * time.Hour)
+	}
+	to, err := event.ParseTime(options.To, now)
+	if err != nil {
+		return nil, err
+	}
+	return t.StatsDB().Query(from, to)
+}
+
+// PushStats collects a stats sample, stores it in the local ring
+// database, and pushes the samples not yet pushed to the collector if
+// one is configured.
+func (t Node) PushStats(options OptsNodePushStats) (nodestats.Samples, error) {
+	s, err := nodestats.Collect(context.Background(), statsSampleInterval, t.StatsDisabled())
+	if err != nil {
+		return nil, err
+	}
+	db := t.StatsDB()
+	if err := db.Append(s); err != nil {
+		return nil, err
+	}
+	if u := t.collectorURL("dbopensvc", "/feed/default/call/xmlrpc"); u != "" {
+		uuid := t.MergedConfig().GetString(key.New("node", "uuid"))
+		n, err := db.Push(context.Background(), u, hostname.Hostname(), uuid)
+		if err != nil {
+			return nil, err
+		}
+		t.Log().Info().Int("samples", n).Msg("stats pushed to the collector")
+	}
+	return nodestats.Samples{s}, nil
+}
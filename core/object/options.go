@@ -9,19 +9,23 @@ import (
 type (
 	// OptsGlobal contains options accepted by all actions
 	OptsGlobal struct {
-		Color          string `flag:"color"`
-		Format         string `flag:"format"`
-		Server         string `flag:"server"`
-		Local          bool   `flag:"local"`
-		NodeSelector   string `flag:"node"`
-		ObjectSelector string `flag:"object"`
-		DryRun         bool   `flag:"dry-run"`
+		Color          string        `flag:"color"`
+		Format         string        `flag:"format"`
+		Server         string        `flag:"server"`
+		Local          bool          `flag:"local"`
+		NodeSelector   string        `flag:"node"`
+		ObjectSelector string        `flag:"object"`
+		Namespace      string        `flag:"namespace"`
+		DryRun         bool          `flag:"dry-run"`
+		Parallel       int           `flag:"parallel"`
+		ObjectTimeout  time.Duration `flag:"objecttimeout"`
 	}
 
 	// OptsLocking contains options accepted by all actions using an action lock
 	OptsLocking struct {
 		Disable bool          `flag:"nolock"`
 		Timeout time.Duration `flag:"waitlock"`
+		Queue   bool          `flag:"lockqueue"`
 	}
 
 	// OptsAsync contains options accepted by all actions having an orchestration
@@ -46,6 +50,11 @@ type (
 		Confirm bool `flag:"confirm"`
 	}
 
+	// OptTarget contains the sync targets selection option
+	OptTarget struct {
+		Target []string `flag:"target"`
+	}
+
 	// OpTo sets a barrier when iterating over a resource lister
 	OptTo struct {
 		To     string `flag:"to"`
@@ -74,11 +83,10 @@ type (
 		Template    string   `flag:"template"`
 		Config      string   `flag:"config"`
 		Keywords    []string `flag:"kwops"`
-		Env         string   `flag:"env"`
+		Env         []string `flag:"env"`
 		Interactive bool     `flag:"interactive"`
 		Provision   bool     `flag:"provision"`
 		Restore     bool     `flag:"restore"`
-		Namespace   string   `flag:"createnamespace"`
 	}
 )
 
@@ -94,6 +102,9 @@ func (t OptForce) IsForce() bool {
 func (t OptTo) ToStr() string {
 	return t.To
 }
+func (t OptTarget) Targets() []string {
+	return t.Target
+}
 func (t OptLeader) IsLeader() bool {
 	return t.Leader
 }
@@ -8,6 +8,8 @@ import (
 
 	reqjsonrpc "opensvc.com/opensvc/core/client/requester/jsonrpc"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/secbackend"
 	"opensvc.com/opensvc/util/funcopt"
 )
 
@@ -25,6 +27,9 @@ type (
 	// A Signal can be sent to consumer processes upon exposed key value
 	// changes.
 	//
+	// A Key value can be a reference to an external secret backend
+	// (ex: vault://secret/data/db#password), resolved at decode time.
+	//
 	Sec struct {
 		Keystore
 	}
@@ -40,26 +45,41 @@ func NewSec(p path.T, opts ...funcopt.O) *Sec {
 }
 
 func secEncode(b []byte) (string, error) {
-	m := reqjsonrpc.NewMessage(b)
-	b, err := m.Encrypt()
-	if err != nil {
-		return "", err
-	}
-	return "crypt:" + base64.URLEncoding.Strict().EncodeToString(b), nil
+	return secEncodeWithSecret(b, rawconfig.Node.Cluster.Secret)
 }
 
 func secDecode(s string) ([]byte, error) {
-	if !strings.HasPrefix(s, "crypt:") {
-		return []byte{}, fmt.Errorf("unsupported value (no crypt prefix)")
+	if secbackend.IsRef(s) {
+		return secbackend.Resolve(s)
 	}
+	return secDecodeWithSecret(s, rawconfig.Node.Cluster.Secret)
+}
 
+func secDecodeWithSecret(s string, secret string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(s, secCryptPrefix):
+		return secOpen(s[len(secCryptPrefix):], secret)
+	case strings.HasPrefix(s, secLegacyCryptPrefix):
+		return secDecodeLegacy(s[len(secLegacyCryptPrefix):], secret)
+	default:
+		return []byte{}, fmt.Errorf("unsupported value (no %s or %s prefix)", secCryptPrefix, secLegacyCryptPrefix)
+	}
+}
+
+// secDecodeLegacy decodes the values encrypted by the AES-CBC jsonrpc
+// message format used before the crypt2 format. These values are
+// re-encrypted in the crypt2 format by the rekey action.
+func secDecodeLegacy(s string, secret string) ([]byte, error) {
 	// decode base64
-	b, err := base64.URLEncoding.DecodeString(s[6:])
+	b, err := base64.URLEncoding.DecodeString(s)
 	if err != nil {
 		return []byte{}, err
 	}
+	if len(b) == 0 {
+		return []byte{}, fmt.Errorf("empty encrypted value")
+	}
 
-	// remove the trailing \r
+	// remove the trailing \x00
 	last := len(b) - 1
 	if b[last] == '\x00' {
 		b = b[:last]
@@ -67,6 +87,7 @@ func secDecode(s string) ([]byte, error) {
 
 	// decrypt AES
 	m := reqjsonrpc.NewMessage(b)
+	m.Key = secret
 	b, err = m.Decrypt()
 	if err != nil {
 		return []byte{}, err
@@ -0,0 +1,97 @@
+package object
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+const (
+	// secCryptPrefix is the prefix of the sec values encrypted in the
+	// versioned authenticated format.
+	secCryptPrefix = "crypt2:"
+
+	// secLegacyCryptPrefix is the prefix of the sec values encrypted in
+	// the unauthenticated AES-CBC jsonrpc message format.
+	secLegacyCryptPrefix = "crypt:"
+
+	// secCryptVersion is the first byte of the decoded crypt2 payload.
+	secCryptVersion byte = 1
+)
+
+var (
+	ErrSecVersion = fmt.Errorf("unsupported sec value format version")
+	ErrSecOpen    = fmt.Errorf("sec value authentication failed")
+)
+
+// secKey derives the 256 bits AES key from the cluster secret.
+func secKey(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+func secAEAD(secret string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("empty cluster secret")
+	}
+	block, err := aes.NewCipher(secKey(secret))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// secSeal encrypts b with AES-GCM, and returns the base64 encoding of
+// <version><nonce><ciphertext+tag>. The version byte is authenticated as
+// additional data.
+func secSeal(b []byte, secret string) (string, error) {
+	aead, err := secAEAD(secret)
+	if err != nil {
+		return "", err
+	}
+	header := []byte{secCryptVersion}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	buff := append(header, nonce...)
+	buff = aead.Seal(buff, nonce, b, header)
+	return base64.RawURLEncoding.EncodeToString(buff), nil
+}
+
+// secOpen decrypts and authenticates a value encrypted by secSeal.
+func secOpen(s string, secret string) ([]byte, error) {
+	buff, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(buff) == 0 || buff[0] != secCryptVersion {
+		return nil, ErrSecVersion
+	}
+	aead, err := secAEAD(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(buff) < 1+aead.NonceSize()+aead.Overhead() {
+		return nil, ErrSecOpen
+	}
+	header := buff[:1]
+	nonce := buff[1 : 1+aead.NonceSize()]
+	b, err := aead.Open(nil, nonce, buff[1+aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrSecOpen
+	}
+	return b, nil
+}
+
+func secEncodeWithSecret(b []byte, secret string) (string, error) {
+	s, err := secSeal(b, secret)
+	if err != nil {
+		return "", err
+	}
+	return secCryptPrefix + s, nil
+}
@@ -0,0 +1,29 @@
+package object
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecEncodeDecode(t *testing.T) {
+	s, err := secEncodeWithSecret([]byte("fooBarValue"), "secret1")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(s, secCryptPrefix))
+
+	b, err := secDecodeWithSecret(s, "secret1")
+	assert.Nil(t, err)
+	assert.Equal(t, "fooBarValue", string(b))
+
+	_, err = secDecodeWithSecret(s, "secret2")
+	assert.Equal(t, ErrSecOpen, err, "decode with another secret")
+
+	tampered := []byte(s)
+	tampered[len(tampered)-2] ^= 1
+	_, err = secDecodeWithSecret(string(tampered), "secret1")
+	assert.NotNil(t, err, "decode tampered value")
+
+	_, err = secDecodeWithSecret("plain", "secret1")
+	assert.NotNil(t, err, "decode value without prefix")
+}
@@ -65,7 +65,7 @@ func (t *Sec) genSelfSigned(priv *rsa.PrivateKey) error {
 	if err != nil {
 		return err
 	}
-	_, certBytes, err := genCert(&tmpl, &tmpl, priv)
+	_, certBytes, err := genCert(&tmpl, &tmpl, &priv.PublicKey, priv)
 	if err != nil {
 		return err
 	}
@@ -78,11 +78,15 @@ func (t *Sec) genCASigned(priv *rsa.PrivateKey, ca string) error {
 	if err != nil {
 		return err
 	}
+	caPriv, err := t.getCAPriv()
+	if err != nil {
+		return err
+	}
 	tmpl, err := t.template(false, priv)
 	if err != nil {
 		return err
 	}
-	_, certBytes, err := genCert(&tmpl, caCert, priv)
+	_, certBytes, err := genCert(&tmpl, caCert, &priv.PublicKey, caPriv)
 	if err != nil {
 		return err
 	}
@@ -164,20 +168,25 @@ func (t *Sec) template(isCA bool, priv interface{}) (x509.Certificate, error) {
 	if err != nil {
 		return x509.Certificate{}, err
 	}
+	// the certificates signed by the same ca must have unique serials
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return x509.Certificate{}, err
+	}
 	template := x509.Certificate{
-		SerialNumber:          big.NewInt(1),
+		SerialNumber:          serial,
 		Subject:               t.subject(),
 		NotBefore:             time.Now().Add(-10 * time.Second),
 		NotAfter:              notAfter,
 		KeyUsage:              keyUsage,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
-		MaxPathLen:            2,
 		IPAddresses:           t.IPAddressesFromAltNames(),
 		DNSNames:              t.DNSNamesFromAltNames(),
 	}
 	if isCA {
 		template.IsCA = true
+		template.MaxPathLen = 2
 		template.KeyUsage |= x509.KeyUsageCertSign
 		template.KeyUsage |= x509.KeyUsageCRLSign
 	}
@@ -294,8 +303,10 @@ func (t *Sec) genPriv() (*rsa.PrivateKey, error) {
 	return priv, nil
 }
 
-func genCert(template, parent *x509.Certificate, priv *rsa.PrivateKey) (*x509.Certificate, []byte, error) {
-	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &priv.PublicKey, priv)
+// genCert returns the certificate of the pub key, signed by the parent
+// certificate signer private key.
+func genCert(template, parent *x509.Certificate, pub *rsa.PublicKey, signer *rsa.PrivateKey) (*x509.Certificate, []byte, error) {
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, pub, signer)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create certificate: " + err.Error())
 	}
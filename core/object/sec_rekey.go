@@ -0,0 +1,63 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/secbackend"
+)
+
+// OptsRekey is the options of the Rekey function of sec objects.
+type OptsRekey struct {
+	Global    OptsGlobal
+	Lock      OptsLocking
+	OldSecret string `flag:"oldsecret"`
+}
+
+// Rekey re-encrypts all keys with the current cluster secret, in the
+// current encryption format.
+//
+// After a cluster secret rotation, the old secret must be passed to
+// decrypt the existing values. Values already encrypted with the current
+// secret are accepted, so the action can be safely replayed.
+func (t *Sec) Rekey(options OptsRekey) error {
+	secret := rawconfig.Node.Cluster.Secret
+	n := 0
+	for _, name := range t.config.Keys(DataSectionName) {
+		k := keyFromName(name)
+		s, err := t.config.GetStringStrict(k)
+		if err != nil {
+			return err
+		}
+		if secbackend.IsRef(s) {
+			continue
+		}
+		var b []byte
+		if options.OldSecret != "" {
+			b, err = secDecodeWithSecret(s, options.OldSecret)
+		}
+		if options.OldSecret == "" || err != nil {
+			b, err = secDecodeWithSecret(s, secret)
+		}
+		if err != nil {
+			t.log.Error().Err(err).Str("key", name).Msg("rekey: decode")
+			return err
+		}
+		if s, err = secEncodeWithSecret(b, secret); err != nil {
+			return err
+		}
+		op := keyop.T{
+			Key:   k,
+			Op:    keyop.Set,
+			Value: s,
+		}
+		if err := t.config.Set(op); err != nil {
+			return err
+		}
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	t.log.Info().Int("count", n).Msg("keys re-encrypted")
+	return t.config.Commit()
+}
@@ -1,11 +1,13 @@
 package object
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
@@ -35,6 +37,7 @@ type (
 		installed          []path.T
 		installedSet       *set.Set
 		server             string
+		namespace          string
 	}
 
 	// BaseAction describes common options of actions to execute on the selected objects or node.
@@ -48,7 +51,20 @@ type (
 	// Action describes an action to execute on the selected objects.
 	Action struct {
 		BaseAction
-		Run func(path.T) (interface{}, error)
+
+		// Run is the action function. Its context is cancelled when
+		// the action times out, so the resource drivers can abort.
+		Run func(context.Context, path.T) (interface{}, error)
+
+		// Parallel is the maximum number of objects actioned
+		// concurrently. Zero means no limit.
+		Parallel int
+
+		// Timeout is the maximum duration of the action on one object.
+		// The Run context is cancelled when exceeded, and the object
+		// result is an ErrActionTimeout error if Run fails after the
+		// deadline. Zero means no timeout.
+		Timeout time.Duration
 	}
 
 	// ActionResult is a predictible type of actions return value, for reflect.
@@ -68,7 +84,7 @@ const (
 
 var (
 	fnmatchExpressionRegex = regexp.MustCompile(`[?*\[\]]`)
-	configExpressionRegex  = regexp.MustCompile(`[=:><]`)
+	configExpressionRegex  = regexp.MustCompile(`[=:><~]`)
 )
 
 // NewSelection allocates a new object selection
@@ -101,6 +117,16 @@ func SelectionWithLocal(v bool) funcopt.O {
 	})
 }
 
+// SelectionWithNamespace restricts the selection to the objects of the
+// namespace.
+func SelectionWithNamespace(namespace string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*Selection)
+		t.namespace = namespace
+		return nil
+	})
+}
+
 // SelectionWithServer sets the server struct key
 func SelectionWithServer(server string) funcopt.O {
 	return funcopt.F(func(i interface{}) error {
@@ -127,10 +153,41 @@ func (t *Selection) Expand() []path.T {
 		return t.paths
 	}
 	t.expand()
+	t.filterNamespace(t.namespace)
+	sort.Sort(path.L(t.paths))
 	log.Debug().Msgf("%d objects selected", len(t.paths))
 	return t.paths
 }
 
+// filterNamespace drops the selected paths not in the namespace set by
+// the SelectionWithNamespace option.
+func (t *Selection) filterNamespace(namespace string) {
+	if namespace == "" {
+		return
+	}
+	l := make([]path.T, 0, len(t.paths))
+	for _, p := range t.paths {
+		if p.Namespace == namespace {
+			l = append(l, p)
+		}
+	}
+	t.paths = l
+}
+
+// ConstrainNamespace returns the selector expression restricted to the
+// objects of the namespace, for the daemons to expand. Each comma
+// separated union member is intersected with the namespace.
+func ConstrainNamespace(selector string, namespace string) string {
+	if selector == "" || namespace == "" {
+		return selector
+	}
+	l := strings.Split(selector, ",")
+	for i, s := range l {
+		l[i] = s + "+" + namespace + "/**"
+	}
+	return strings.Join(l, ",")
+}
+
 //
 // ExpandSet returns a set of the paths returned by Expand. Usually to
 // benefit from the .Has() function.
@@ -281,10 +338,11 @@ func (t *Selection) localExpandOneNegative(s string) (*set.Set, error) {
 
 func (t *Selection) localExpandOnePositive(s string) (*set.Set, error) {
 	switch {
-	case fnmatchExpressionRegex.MatchString(s):
-		return t.localFnmatchExpand(s)
 	case configExpressionRegex.MatchString(s):
+		// before fnmatch, as filter values can contain fnmatch or regexp patterns
 		return t.localConfigExpand(s)
+	case fnmatchExpressionRegex.MatchString(s):
+		return t.localFnmatchExpand(s)
 	default:
 		return t.localExactExpand(s)
 	}
@@ -324,7 +382,21 @@ func (t *Selection) getInstalledSet() (*set.Set, error) {
 
 func (t *Selection) localConfigExpand(s string) (*set.Set, error) {
 	matching := set.New()
-	log.Warn().Msg("TODO: localConfigExpand")
+	f, err := parseSelectorFilter(s)
+	if err != nil {
+		return matching, err
+	}
+	paths, err := t.getInstalled()
+	if err != nil {
+		return matching, err
+	}
+	for _, p := range paths {
+		if ok, err := f.match(p); err != nil {
+			return matching, err
+		} else if ok {
+			matching.Insert(p.String())
+		}
+	}
 	return matching, nil
 }
 
@@ -377,60 +449,83 @@ func (t *Selection) daemonExpand() error {
 }
 
 // Do executes in parallel the action on all selected objects supporting
-// the action.
+// the action. At most action.Parallel objects are actioned concurrently,
+// and the results are returned in the selection order.
 func (t *Selection) Do(action Action) []ActionResult {
 	t.Expand()
-	q := make(chan ActionResult, len(t.paths))
-	results := make([]ActionResult, 0)
-	started := 0
+	results := make([]ActionResult, len(t.paths))
+	parallel := action.Parallel
+	if parallel <= 0 || parallel > len(t.paths) {
+		parallel = len(t.paths)
+	}
+	sem := make(chan interface{}, parallel)
+	done := make(chan interface{}, len(t.paths))
 
-	for _, p := range t.paths {
-		go func(p path.T) {
-			result := ActionResult{
-				Path:     p,
-				Nodename: hostname.Hostname(),
-			}
+	for i, p := range t.paths {
+		sem <- nil
+		go func(i int, p path.T) {
 			defer func() {
-				if r := recover(); r != nil {
-					result.Panic = r
-					fmt.Println(string(debug.Stack()))
-					q <- result
-				}
+				<-sem
+				done <- nil
 			}()
-			data, err := action.Run(p)
-			result.Data = data
-			result.Error = err
-			result.HumanRenderer = func() string {
-				if data == nil {
-					return ""
-				}
-				switch v := data.(type) {
-				case Renderer:
-					return v.Render()
-				case fmt.Stringer:
-					return v.String()
-				case string:
-					return v + "\n"
-				case []string:
-					s := ""
-					for _, e := range v {
-						s += e + "\n"
-					}
-					return s
-				case []byte:
-					return string(v)
-				default:
-					return ""
-				}
-			}
-			q <- result
-		}(p)
-		started++
+			results[i] = action.runOne(p)
+		}(i, p)
 	}
-
-	for i := 0; i < started; i++ {
-		r := <-q
-		results = append(results, r)
+	for range t.paths {
+		<-done
 	}
 	return results
 }
+
+//
+// runOne executes the action on the object, recovering panics and
+// enforcing the action per-object timeout: the action context is
+// cancelled on timeout, and runOne waits for the action to return.
+//
+func (t Action) runOne(p path.T) (result ActionResult) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if t.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+	}
+	defer cancel()
+	result = ActionResult{
+		Path:     p,
+		Nodename: hostname.Hostname(),
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			result.Panic = r
+			fmt.Println(string(debug.Stack()))
+		}
+	}()
+	data, err := t.Run(ctx, p)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = errors.Wrapf(ErrActionTimeout, "%s: %s", t.Timeout, err)
+	}
+	result.Data = data
+	result.Error = err
+	result.HumanRenderer = func() string {
+		if data == nil {
+			return ""
+		}
+		switch v := data.(type) {
+		case Renderer:
+			return v.Render()
+		case fmt.Stringer:
+			return v.String()
+		case string:
+			return v + "\n"
+		case []string:
+			s := ""
+			for _, e := range v {
+				s += e + "\n"
+			}
+			return s
+		case []byte:
+			return string(v)
+		default:
+			return ""
+		}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/exitcode"
+)
+
+type (
+	// ErrSelection is the error summary of an action executed on a
+	// selection of objects.
+	ErrSelection struct {
+		// Failed are the results of the objects the action failed on.
+		Failed []ActionResult
+
+		// Total is the number of objects actioned.
+		Total int
+	}
+)
+
+var (
+	// ErrActionTimeout is the error of an object action result when the
+	// action per-object timeout is exceeded.
+	ErrActionTimeout = errors.New("action timeout")
+)
+
+// NewErrSelection returns an ErrSelection if at least one of the results
+// is a failure, nil otherwise.
+func NewErrSelection(results []ActionResult) error {
+	failed := make([]ActionResult, 0)
+	for _, r := range results {
+		if r.Error != nil || r.Panic != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return ErrSelection{
+		Failed: failed,
+		Total:  len(results),
+	}
+}
+
+func (t ErrSelection) Error() string {
+	l := make([]string, len(t.Failed))
+	for i, r := range t.Failed {
+		l[i] = r.Path.String()
+	}
+	return fmt.Sprintf("%d/%d objects failed: %s", len(t.Failed), t.Total, strings.Join(l, ", "))
+}
+
+//
+// ExitCode implements the exitcode.Coder interface. The action failed on
+// some of the objects is a partial failure. Otherwise the exit code is
+// the code shared by all objects errors, or the generic error code if
+// they differ.
+//
+func (t ErrSelection) ExitCode() int {
+	if len(t.Failed) < t.Total {
+		return exitcode.Partial
+	}
+	code := exitcode.Error
+	for i, r := range t.Failed {
+		c := exitcode.Error
+		if r.Panic == nil {
+			c = exitcode.Of(r.Error)
+		}
+		if i > 0 && c != code {
+			return exitcode.Error
+		}
+		code = c
+	}
+	return code
+}
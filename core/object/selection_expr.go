@@ -0,0 +1,193 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danwakefield/fnmatch"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// selectorFilter is a parsed <key><op><value> selector expression
+	// element, filtering the objects on a configuration keyword or a
+	// status field value.
+	//
+	// Examples:
+	//   env=prod            DEFAULT.env value matches the prod fnmatch pattern
+	//   fs.type=xfs         one of the fs#<n> sections type is xfs
+	//   priority<10         the DEFAULT.priority value is lower than 10
+	//   app:                the DEFAULT.app keyword is set
+	//   fs#1:               the fs#1 section exists
+	//   nodes~^n[12]        the DEFAULT.nodes value matches the regexp
+	//   status.avail=up     the cached instance status avail is up
+	//   status.overall!=up  the cached instance status overall is not up
+	selectorFilter struct {
+		Key   string
+		Op    string
+		Value string
+	}
+)
+
+const (
+	statusFilterPrefix = "status."
+)
+
+var (
+	// selectorOps is ordered so the two-char operators are tried first.
+	selectorOps = []string{">=", "<=", "!=", "=", ">", "<", "~", ":"}
+)
+
+func parseSelectorFilter(s string) (selectorFilter, error) {
+	f := selectorFilter{}
+	idx := -1
+	for _, op := range selectorOps {
+		i := strings.Index(s, op)
+		if i < 0 {
+			continue
+		}
+		if idx < 0 || i < idx || (i == idx && len(op) > len(f.Op)) {
+			idx = i
+			f.Op = op
+		}
+	}
+	if idx <= 0 {
+		return f, fmt.Errorf("invalid selector filter %s: expected <key><op><value>", s)
+	}
+	f.Key = s[:idx]
+	f.Value = s[idx+len(f.Op):]
+	return f, nil
+}
+
+// match returns true if the object matches the filter.
+func (t selectorFilter) match(p path.T) (bool, error) {
+	if strings.HasPrefix(t.Key, statusFilterPrefix) {
+		return t.matchStatus(p)
+	}
+	return t.matchConfig(p)
+}
+
+func (t selectorFilter) matchConfig(p path.T) (bool, error) {
+	o, ok := NewFromPath(p, WithVolatile(true)).(Configurer)
+	if !ok {
+		return false, nil
+	}
+	c := o.Config()
+	if c == nil {
+		return false, nil
+	}
+	if t.Op == ":" && !strings.Contains(t.Key, ".") && strings.Contains(t.Key, "#") {
+		// section existence: fs#1:
+		for _, section := range c.SectionStrings() {
+			if section == t.Key {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	k := key.Parse(t.Key)
+	for _, section := range c.SectionStrings() {
+		if !sectionMatch(section, k.Section) {
+			continue
+		}
+		sk := key.New(section, k.Option)
+		if !c.HasKey(sk) {
+			continue
+		}
+		if t.Op == ":" {
+			return true, nil
+		}
+		v := c.Get(sk)
+		if i, err := c.Eval(sk); err == nil {
+			v = fmt.Sprint(i)
+		}
+		if ok, err := t.compare(v); err != nil {
+			return false, err
+		} else if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sectionMatch returns true if the section name is the selector section
+// or, if the selector section is a driver group, a section of this group.
+func sectionMatch(section, s string) bool {
+	if section == s {
+		return true
+	}
+	if strings.Contains(s, "#") || s == "DEFAULT" {
+		return false
+	}
+	return strings.HasPrefix(section, s+"#")
+}
+
+func (t selectorFilter) matchStatus(p path.T) (bool, error) {
+	o, ok := NewFromPath(p, WithVolatile(true)).(interface {
+		statusLoad() (instance.Status, error)
+	})
+	if !ok {
+		return false, nil
+	}
+	data, err := o.statusLoad()
+	if err != nil {
+		// no cached status: can not match
+		return false, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		return false, err
+	}
+	v, ok := m[strings.TrimPrefix(t.Key, statusFilterPrefix)]
+	if t.Op == ":" {
+		return ok, nil
+	}
+	if !ok {
+		v = ""
+	}
+	return t.compare(fmt.Sprint(v))
+}
+
+func (t selectorFilter) compare(v string) (bool, error) {
+	switch t.Op {
+	case "=":
+		return fnmatch.Match(t.Value, v, fnmatch.FNM_IGNORECASE), nil
+	case "!=":
+		return !fnmatch.Match(t.Value, v, fnmatch.FNM_IGNORECASE), nil
+	case "~":
+		r, err := regexp.Compile(t.Value)
+		if err != nil {
+			return false, err
+		}
+		return r.MatchString(v), nil
+	case ">", ">=", "<", "<=":
+		a, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return false, nil
+		}
+		b, err := strconv.ParseFloat(t.Value, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector filter %s%s%s: %s is not a number", t.Key, t.Op, t.Value, t.Value)
+		}
+		switch t.Op {
+		case ">":
+			return a > b, nil
+		case ">=":
+			return a >= b, nil
+		case "<":
+			return a < b, nil
+		default:
+			return a <= b, nil
+		}
+	}
+	return false, fmt.Errorf("unsupported selector operator %s", t.Op)
+}
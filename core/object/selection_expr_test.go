@@ -0,0 +1,57 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelectorFilter(t *testing.T) {
+	for s, expected := range map[string]selectorFilter{
+		"env=prod":        {Key: "env", Op: "=", Value: "prod"},
+		"env!=prod":       {Key: "env", Op: "!=", Value: "prod"},
+		"priority>=10":    {Key: "priority", Op: ">=", Value: "10"},
+		"priority<10":     {Key: "priority", Op: "<", Value: "10"},
+		"app:":            {Key: "app", Op: ":", Value: ""},
+		"nodes~^n[12]=":   {Key: "nodes", Op: "~", Value: "^n[12]="},
+		"status.avail=up": {Key: "status.avail", Op: "=", Value: "up"},
+	} {
+		f, err := parseSelectorFilter(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, f, s)
+	}
+	_, err := parseSelectorFilter("=prod")
+	assert.Error(t, err)
+}
+
+func TestSelectorFilterCompare(t *testing.T) {
+	for s, expected := range map[string]bool{
+		"env=prod":  true,
+		"env=PR*":   true,
+		"env!=prod": false,
+		"env~^p.o":  true,
+		"env~^x":    false,
+	} {
+		f, err := parseSelectorFilter(s)
+		assert.NoError(t, err, s)
+		ok, err := f.compare("prod")
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, ok, s)
+	}
+	f, _ := parseSelectorFilter("priority>=10")
+	ok, err := f.compare("10")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	f, _ = parseSelectorFilter("priority>=ten")
+	_, err = f.compare("10")
+	assert.Error(t, err)
+}
+
+func TestSectionMatch(t *testing.T) {
+	assert.True(t, sectionMatch("fs#1", "fs"))
+	assert.True(t, sectionMatch("fs#1", "fs#1"))
+	assert.False(t, sectionMatch("fs#2", "fs#1"))
+	assert.False(t, sectionMatch("fsx#1", "fs"))
+	assert.True(t, sectionMatch("DEFAULT", "DEFAULT"))
+	assert.False(t, sectionMatch("fs#1", "DEFAULT"))
+}
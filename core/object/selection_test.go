@@ -0,0 +1,131 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/exitcode"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestConstrainNamespace(t *testing.T) {
+	assert.Equal(t, "*", ConstrainNamespace("*", ""))
+	assert.Equal(t, "", ConstrainNamespace("", "test"))
+	assert.Equal(t, "*+test/**", ConstrainNamespace("*", "test"))
+	assert.Equal(t, "a*+b*+test/**,c+test/**", ConstrainNamespace("a*+b*,c", "test"))
+}
+
+func TestSelectionFilterNamespace(t *testing.T) {
+	p1, _ := path.Parse("test/svc/s1")
+	p2, _ := path.Parse("s2")
+	sel := NewSelection("*")
+	sel.paths = []path.T{p1, p2}
+	sel.filterNamespace(sel.namespace)
+	assert.Len(t, sel.paths, 2)
+	sel = NewSelection("*", SelectionWithNamespace("test"))
+	sel.paths = []path.T{p1, p2}
+	sel.filterNamespace(sel.namespace)
+	assert.Equal(t, []path.T{p1}, sel.paths)
+}
+
+func TestSelectionDo(t *testing.T) {
+	paths := make([]path.T, 0)
+	for _, s := range []string{"s1", "s2", "s3", "s4", "s5"} {
+		p, _ := path.Parse(s)
+		paths = append(paths, p)
+	}
+	sel := NewSelection("*")
+	sel.paths = paths
+
+	var (
+		mu              sync.Mutex
+		running, maxRun int
+	)
+	results := sel.Do(Action{
+		Parallel: 2,
+		Timeout:  100 * time.Millisecond,
+		Run: func(ctx context.Context, p path.T) (interface{}, error) {
+			mu.Lock()
+			running++
+			if running > maxRun {
+				maxRun = running
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				mu.Unlock()
+			}()
+			switch p.Name {
+			case "s2":
+				return nil, errors.New("failed")
+			case "s4":
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(time.Second):
+					return nil, errors.New("context not cancelled")
+				}
+			default:
+				time.Sleep(10 * time.Millisecond)
+			}
+			return p.Name, nil
+		},
+	})
+	assert.LessOrEqual(t, maxRun, 2)
+	assert.Len(t, results, 5)
+	for i, r := range results {
+		assert.Equal(t, paths[i], r.Path, "results are in selection order")
+	}
+	assert.True(t, errors.Is(results[3].Error, ErrActionTimeout))
+	assert.Contains(t, results[3].Error.Error(), context.DeadlineExceeded.Error())
+	assert.Equal(t, 0, running, "the timed out action returned before Do")
+
+	err := NewErrSelection(results)
+	var errSelection ErrSelection
+	assert.True(t, errors.As(err, &errSelection))
+	assert.Equal(t, 5, errSelection.Total)
+	assert.Len(t, errSelection.Failed, 2)
+	assert.Equal(t, "2/5 objects failed: s2, s4", err.Error())
+
+	assert.NoError(t, NewErrSelection(results[:1]))
+}
+
+func TestErrSelectionExitCode(t *testing.T) {
+	aborted := exitcodeError(exitcode.Aborted)
+	cases := map[string]struct {
+		err      ErrSelection
+		expected int
+	}{
+		"partial": {
+			err:      ErrSelection{Failed: []ActionResult{{Error: aborted}}, Total: 2},
+			expected: exitcode.Partial,
+		},
+		"all aborted": {
+			err:      ErrSelection{Failed: []ActionResult{{Error: aborted}, {Error: aborted}}, Total: 2},
+			expected: exitcode.Aborted,
+		},
+		"mixed errors": {
+			err:      ErrSelection{Failed: []ActionResult{{Error: aborted}, {Error: errors.New("foo")}}, Total: 2},
+			expected: exitcode.Error,
+		},
+		"panic": {
+			err:      ErrSelection{Failed: []ActionResult{{Panic: "foo"}}, Total: 1},
+			expected: exitcode.Error,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, exitcode.Of(c.err))
+		})
+	}
+}
+
+type exitcodeError int
+
+func (t exitcodeError) Error() string { return "exitcode error" }
+func (t exitcodeError) ExitCode() int { return int(t) }
@@ -0,0 +1,107 @@
+package object
+
+import (
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/placement"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+// reference returns the status of one of the object instances, used to
+// read the object-level properties (placement, topology, flex target,
+// scope) all instances share.
+func (t Status) reference() (instance.Config, instance.Status, bool) {
+	for _, nodename := range t.sortedInstanceNames() {
+		i := t.Instances[nodename]
+		return i.Config, i.Status, true
+	}
+	return instance.Config{}, instance.Status{}, false
+}
+
+// isCandidate returns true if the instance on nodename can be elected
+// placement leader: the node and the instance are not frozen, and the
+// instance status is sane.
+func (t Status) isCandidate(nodename string) bool {
+	i, ok := t.Instances[nodename]
+	if !ok {
+		return false
+	}
+	if !i.Node.Frozen.IsZero() && !i.Node.Frozen.Time().IsZero() {
+		return false
+	}
+	if !i.Status.Frozen.IsZero() && !i.Status.Frozen.Time().IsZero() {
+		return false
+	}
+	switch i.Status.Avail {
+	case status.Undef, status.Warn:
+		return false
+	}
+	return true
+}
+
+// PlacementCandidates returns the nodes eligible to the placement
+// leadership, ordered by decreasing priority according to the object
+// placement policy.
+func (t Status) PlacementCandidates(stats placement.Stats) []string {
+	cfg, ref, ok := t.reference()
+	if !ok {
+		return []string{}
+	}
+	l := make([]string, 0)
+	for _, nodename := range cfg.Scope {
+		if t.isCandidate(nodename) {
+			l = append(l, nodename)
+		}
+	}
+	return ref.Placement.Rank(t.Path.Name, l, stats)
+}
+
+// PlacementLeaders returns the candidate nodes the daemon should run the
+// object instances on: the first candidate for a failover object, the
+// flex_target first candidates for a flex object.
+func (t Status) PlacementLeaders(stats placement.Stats) []string {
+	_, ref, ok := t.reference()
+	if !ok || ref.Placement == placement.None {
+		return []string{}
+	}
+	l := t.PlacementCandidates(stats)
+	n := 1
+	if ref.Topology == topology.Flex {
+		n = ref.FlexTarget
+	}
+	if n < len(l) {
+		l = l[:n]
+	}
+	return l
+}
+
+//
+// PlacementState returns "optimal" if the up instances run on the
+// placement leaders, "non-optimal" if not, and "n/a" if the object has
+// no placement policy or no up instance.
+//
+func (t Status) PlacementState(stats placement.Stats) string {
+	_, ref, ok := t.reference()
+	if !ok || ref.Placement == placement.None {
+		return "n/a"
+	}
+	up := make(map[string]interface{})
+	for nodename, i := range t.Instances {
+		if i.Status.Avail == status.Up {
+			up[nodename] = nil
+		}
+	}
+	if len(up) == 0 {
+		return "n/a"
+	}
+	leaders := t.PlacementLeaders(stats)
+	for _, nodename := range leaders {
+		if _, ok := up[nodename]; !ok {
+			return "non-optimal"
+		}
+	}
+	if ref.Topology != topology.Flex && len(up) > len(leaders) {
+		return "non-optimal"
+	}
+	return "optimal"
+}
@@ -2,6 +2,7 @@ package object
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"opensvc.com/opensvc/core/colorstatus"
@@ -72,9 +73,9 @@ func (t Status) LoadTreeNode(head *tree.Node) {
 	head.AddColumn().AddText(t.descString())
 	instances := head.AddNode()
 	instances.AddColumn().AddText("instances")
-	for _, data := range t.Instances {
+	for _, nodename := range t.sortedInstanceNames() {
 		n := instances.AddNode()
-		data.LoadTreeNode(n)
+		t.Instances[nodename].LoadTreeNode(n)
 	}
 	t.loadTreeNodeParents(head)
 	t.loadTreeNodeChildren(head)
@@ -87,7 +88,8 @@ func (t Status) loadTreeNodeParents(head *tree.Node) {
 	}
 	n := head.AddNode()
 	n.AddColumn().AddText("parents")
-	for p, data := range t.Parents {
+	for _, p := range sortedRelations(t.Parents) {
+		data := t.Parents[p]
 		pNode := n.AddNode()
 		pNode.AddColumn().AddText(p).SetColor(rawconfig.Node.Color.Bold)
 		pNode.AddColumn()
@@ -101,7 +103,8 @@ func (t Status) loadTreeNodeChildren(head *tree.Node) {
 	}
 	n := head.AddNode()
 	n.AddColumn().AddText("children")
-	for p, data := range t.Children {
+	for _, p := range sortedRelations(t.Children) {
+		data := t.Children[p]
 		pNode := n.AddNode()
 		pNode.AddColumn().AddText(p).SetColor(rawconfig.Node.Color.Bold)
 		pNode.AddColumn()
@@ -115,7 +118,8 @@ func (t Status) loadTreeNodeSlaves(head *tree.Node) {
 	}
 	n := head.AddNode()
 	n.AddColumn().AddText("slaves")
-	for p, data := range t.Slaves {
+	for _, p := range sortedRelations(t.Slaves) {
+		data := t.Slaves[p]
 		pNode := n.AddNode()
 		pNode.AddColumn().AddText(p).SetColor(rawconfig.Node.Color.Bold)
 		pNode.AddColumn()
@@ -150,6 +154,26 @@ func (t Status) descString() string {
 	return strings.Join(l, " ")
 }
 
+// sortedInstanceNames returns the instances node names, sorted so the
+// rendering is stable.
+func (t Status) sortedInstanceNames() []string {
+	l := make([]string, 0, len(t.Instances))
+	for nodename := range t.Instances {
+		l = append(l, nodename)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func sortedRelations(m map[string]AggregatedStatus) []string {
+	l := make([]string, 0, len(m))
+	for p := range m {
+		l = append(l, p)
+	}
+	sort.Strings(l)
+	return l
+}
+
 // NewObjectStatus allocates and return a struct to host an objet full state dataset.
 func NewObjectStatus() *Status {
 	t := &Status{}
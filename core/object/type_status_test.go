@@ -0,0 +1,27 @@
+package object
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+func TestStatusRenderOrder(t *testing.T) {
+	p, _ := path.Parse("ns1/svc/s1")
+	data := NewObjectStatus()
+	data.Path = p
+	data.Compat = true
+	for _, nodename := range []string{"node3", "node1", "node2"} {
+		data.Instances[nodename] = InstanceStates{Node: InstanceNode{Name: nodename}}
+	}
+	data.Parents["ns1/svc/p2"] = AggregatedStatus{Avail: status.Up}
+	data.Parents["ns1/svc/p1"] = AggregatedStatus{Avail: status.Down}
+	s := data.Render()
+	assert.Less(t, strings.Index(s, "node1"), strings.Index(s, "node2"))
+	assert.Less(t, strings.Index(s, "node2"), strings.Index(s, "node3"))
+	assert.Less(t, strings.Index(s, "ns1/svc/p1"), strings.Index(s, "ns1/svc/p2"))
+	assert.Equal(t, s, data.Render(), "render is stable")
+}
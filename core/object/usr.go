@@ -2,7 +2,9 @@ package object
 
 import (
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rbac"
 	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/key"
 )
 
 type (
@@ -13,14 +15,26 @@ type (
 	// They are required for basic, session and x509 api access, but not
 	// for OpenID access (where grants are embedded in the trusted token)
 	//
+	// The credentials are stored as encrypted keys, like in a Sec: the
+	// "password" key for basic authentication, and the "certificate" and
+	// "private_key" keys generated by gencert for x509 authentication.
+	//
 	Usr struct {
-		Base
+		Sec
 	}
 )
 
 // NewUsr allocates a usr kind object.
 func NewUsr(p path.T, opts ...funcopt.O) *Usr {
 	s := &Usr{}
+	s.CustomEncode = secEncode
+	s.CustomDecode = secDecode
 	s.Base.init(p, opts...)
 	return s
 }
+
+// Grants returns the parsed grant keyword value, used by the api
+// listener to authorize the user requests.
+func (t *Usr) Grants() (rbac.Grants, error) {
+	return rbac.ParseGrants(t.config.GetSlice(key.Parse("grant")))
+}
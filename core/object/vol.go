@@ -32,7 +32,20 @@ func NewVol(p path.T, opts ...funcopt.O) *Vol {
 	return s
 }
 
+// MountPoint returns the head directory of the first fs resource of the
+// volume, where the keys and directories are installed.
 func (t *Vol) MountPoint() string {
+	type header interface {
+		Head() string
+	}
+	for _, r := range t.Resources() {
+		if r.ID().DriverGroup() != drivergroup.FS {
+			continue
+		}
+		if o, ok := r.(header); ok {
+			return o.Head()
+		}
+	}
 	return ""
 }
 
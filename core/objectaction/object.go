@@ -1,15 +1,19 @@
 package objectaction
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/driverstats"
 	"opensvc.com/opensvc/core/entrypoints/action"
+	"opensvc.com/opensvc/core/exitcode"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/path"
@@ -172,8 +176,29 @@ func WithServer(s string) funcopt.O {
 	})
 }
 
-// WithLocalRun sets a function to run if the the action is local
-func WithLocalRun(f func(path.T) (interface{}, error)) funcopt.O {
+// WithParallel sets the maximum number of objects actioned concurrently
+// by a local action. Zero means no limit.
+func WithParallel(n int) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.Object.Parallel = n
+		return nil
+	})
+}
+
+// WithObjectTimeout sets the maximum duration of a local action on each
+// selected object. Zero means no timeout.
+func WithObjectTimeout(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.Object.Timeout = d
+		return nil
+	})
+}
+
+// WithLocalRun sets a function to run if the the action is local. The
+// function context is cancelled when the object timeout is exceeded.
+func WithLocalRun(f func(context.Context, path.T) (interface{}, error)) funcopt.O {
 	return funcopt.F(func(i interface{}) error {
 		t := i.(*T)
 		t.Object.Run = f
@@ -196,6 +221,12 @@ func (t T) DoLocal() error {
 		object.SelectionWithLocal(true),
 	)
 	rs := sel.Do(t.Object)
+	if len(rs) == 0 {
+		return fmt.Errorf("%s: %w", t.ObjectSelector, exitcode.ErrNotFound)
+	}
+	if err := driverstats.Flush(); err != nil {
+		log.Debug().Err(err).Msg("flush driver stats")
+	}
 	human := func() string {
 		s := ""
 		for _, r := range rs {
@@ -235,12 +266,7 @@ func (t T) DoLocal() error {
 		HumanRenderer: human,
 		Colorize:      rawconfig.Node.Colorize,
 	}.Print()
-	for _, ar := range rs {
-		if ar.Panic != nil || ar.Error != nil {
-			return errors.New("")
-		}
-	}
-	return nil
+	return object.NewErrSelection(rs)
 }
 
 // DoAsync uses the agent API to submit a target state to reach via an
@@ -278,36 +304,61 @@ func (t T) DoAsync() {
 	}
 }
 
-// DoRemote posts the action to a peer node agent API, for synchronous
-// execution.
-func (t T) DoRemote() {
+//
+// DoRemote posts the action to the agent api of each node selected by
+// NodeSelector, for synchronous execution. The posts are concurrent, and
+// the per-node results are rendered as a merged table.
+//
+func (t T) DoRemote() error {
 	c, err := client.New(client.WithURL(t.Server))
 	if err != nil {
 		log.Error().Err(err).Msg("")
-		os.Exit(1)
+		return err
 	}
-	req := c.NewPostObjectAction()
-	req.ObjectSelector = t.ObjectSelector
-	req.NodeSelector = t.NodeSelector
-	req.Action = t.Action
-	req.Options = t.PostFlags
-	b, err := req.Do()
-	human := func() string {
-		s := fmt.Sprintln(string(b))
-		return s
+	nodes, err := action.RemoteNodes(c, t.NodeSelector)
+	if err != nil {
+		log.Error().Err(err).Msg("")
+		return err
 	}
+	rs := action.FanOut(context.Background(), nodes, func(ctx context.Context, node string) ([]byte, error) {
+		req := c.NewPostObjectAction()
+		req.SetContext(ctx)
+		req.ObjectSelector = t.ObjectSelector
+		req.NodeSelector = node
+		req.Action = t.Action
+		req.Options = t.PostFlags
+		req.SetNode(node)
+		return req.Do()
+	})
 	output.Renderer{
 		Format:        t.Format,
 		Color:         t.Color,
-		Data:          b,
-		HumanRenderer: human,
+		Data:          rs,
+		HumanRenderer: rs.Render,
+		Colorize:      rawconfig.Node.Colorize,
 	}.Print()
+	return rs.Err()
 }
 
+// Do executes the action and terminates the program with the exit code
+// of the action result, as defined by the exitcode package.
 func (t T) Do() {
 	err := action.Do(t)
 	if err != nil {
-		os.Exit(1)
+		var (
+			errSelection object.ErrSelection
+			errRemote    action.ErrRemote
+		)
+		switch {
+		case errors.As(err, &errSelection) && errSelection.Total > 1:
+			// the objects errors are already logged: only summarize
+			fmt.Fprintln(os.Stderr, errSelection)
+		case errors.As(err, &errRemote):
+			// the nodes errors are already rendered: only summarize
+			fmt.Fprintln(os.Stderr, errRemote)
+		case errors.Is(err, exitcode.ErrNotFound):
+			fmt.Fprintln(os.Stderr, err)
+		}
 	}
-	os.Exit(0)
+	exitcode.Exit(err)
 }
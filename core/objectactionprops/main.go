@@ -1,6 +1,8 @@
 package objectactionprops
 
 import (
+	"strings"
+
 	"opensvc.com/opensvc/core/kind"
 	"opensvc.com/opensvc/core/ordering"
 )
@@ -18,6 +20,7 @@ type (
 		DisableNodeValidation bool
 		RelayToAny            bool
 		Rollback              bool
+		Encap                 bool
 		TimeoutKeywords       []string
 	}
 )
@@ -29,6 +32,13 @@ var (
 		Progress:    "aborting",
 		LocalExpect: "unset",
 	}
+	Boot = T{
+		Name:     "boot",
+		Progress: "booting",
+		Local:    true,
+		Order:    ordering.Desc,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+	}
 	Decode = T{
 		Name:       "decode",
 		RelayToAny: true,
@@ -106,6 +116,7 @@ var (
 		LocalExpect:     "unset",
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
 		Rollback:        true,
+		Encap:           true,
 		TimeoutKeywords: []string{"unprovision_timeout", "timeout"},
 	}
 	Purge = T{
@@ -126,6 +137,13 @@ var (
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
 		TimeoutKeywords: []string{"start_timeout", "timeout"},
 	}
+	Run = T{
+		Name:            "run",
+		Progress:        "running",
+		Local:           true,
+		Kinds:           []kind.T{kind.Svc},
+		TimeoutKeywords: []string{"timeout"},
+	}
 	Shutdown = T{
 		Name:            "shutdown",
 		Target:          "shutdown",
@@ -133,8 +151,27 @@ var (
 		Local:           true,
 		Order:           ordering.Desc,
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
+		Encap:           true,
 		TimeoutKeywords: []string{"stop_timeout", "timeout"},
 	}
+	SnapCreate = T{
+		Name:     "snap_create",
+		Progress: "snapshotting",
+		Local:    true,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+	}
+	SnapPrune = T{
+		Name:     "snap_prune",
+		Progress: "pruning",
+		Local:    true,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+	}
+	SnapRollback = T{
+		Name:     "snap_rollback",
+		Progress: "rolling back",
+		Local:    true,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+	}
 	Start = T{
 		Name:            "start",
 		Target:          "started",
@@ -143,6 +180,14 @@ var (
 		LocalExpect:     "unset",
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
 		Rollback:        true,
+		Encap:           true,
+		TimeoutKeywords: []string{"start_timeout", "timeout"},
+	}
+	StartStandby = T{
+		Name:            "startstandby",
+		Progress:        "starting",
+		Local:           true,
+		Kinds:           []kind.T{kind.Svc, kind.Vol},
 		TimeoutKeywords: []string{"start_timeout", "timeout"},
 	}
 	Stop = T{
@@ -154,8 +199,30 @@ var (
 		LocalExpect:     "",
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
 		Freeze:          true,
+		Encap:           true,
 		TimeoutKeywords: []string{"stop_timeout", "timeout"},
 	}
+	SyncFull = T{
+		Name:            "sync_full",
+		Progress:        "syncing",
+		Local:           true,
+		Kinds:           []kind.T{kind.Svc, kind.Vol},
+		TimeoutKeywords: []string{"sync_timeout", "timeout"},
+	}
+	SyncResync = T{
+		Name:            "sync_resync",
+		Progress:        "syncing",
+		Local:           true,
+		Kinds:           []kind.T{kind.Svc, kind.Vol},
+		TimeoutKeywords: []string{"sync_timeout", "timeout"},
+	}
+	SyncUpdate = T{
+		Name:            "sync_update",
+		Progress:        "syncing",
+		Local:           true,
+		Kinds:           []kind.T{kind.Svc, kind.Vol},
+		TimeoutKeywords: []string{"sync_timeout", "timeout"},
+	}
 	Switch = T{
 		Name:            "switch",
 		Target:          "placed@",
@@ -193,6 +260,37 @@ var (
 		Local:           true,
 		Order:           ordering.Desc,
 		Kinds:           []kind.T{kind.Svc, kind.Vol},
+		Encap:           true,
 		TimeoutKeywords: []string{"unprovision_timeout", "timeout"},
 	}
 )
+
+var (
+	// progressActions are the actions setting their progress state in
+	// the instance monitor status while running.
+	progressActions = []T{
+		Abort, Boot, Delete, Freeze, Giveback, Move, Provision, Purge,
+		Restart, Run, Shutdown, SnapCreate, SnapPrune, SnapRollback,
+		Start, StartStandby, Stop, SyncFull, SyncResync, SyncUpdate,
+		Switch, Takeover, Thaw, TOC, Unprovision,
+	}
+)
+
+//
+// IsProgress returns true if the instance monitor status s is the
+// progress state of an action, like "starting". The "placing@" progress
+// state matches the states naming the destination node, like
+// "placing@n1".
+//
+func IsProgress(s string) bool {
+	for _, a := range progressActions {
+		switch {
+		case a.Progress == "":
+		case s == a.Progress:
+			return true
+		case strings.HasSuffix(a.Progress, "@") && strings.HasPrefix(s, a.Progress) && !strings.Contains(s, " "):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package objectactionprops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsProgress(t *testing.T) {
+	for _, s := range []string{"starting", "provisioning", "rolling back", "placing@", "placing@n1"} {
+		assert.True(t, IsProgress(s), s)
+	}
+	for _, s := range []string{"", "idle", "start failed", "placing@n1 failed", "booting failed"} {
+		assert.False(t, IsProgress(s), s)
+	}
+}
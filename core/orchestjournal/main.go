@@ -0,0 +1,184 @@
+// Package orchestjournal persists the in-flight orchestration intents, so
+// a daemon restart can resume or safely cancel the global expects that
+// were in progress when it stopped.
+//
+// The daemon api records an intent when a global expect is set on an
+// object or on the node, and removes it when the global expect is unset
+// or the object configuration removed. On startup, the daemon calls
+// Recover to sort the leftover intents in resumed and cancelled lists.
+// The resumed intents are exposed in the daemon status.
+//
+// The journal is the only persistent store of the global expects. The
+// instance local expects and restart counters are persisted by the
+// monstate package.
+package orchestjournal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/opensvc/fcntllock"
+	"github.com/opensvc/flock"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+	"opensvc.com/opensvc/util/xsession"
+)
+
+type (
+	// Intent is an orchestration in progress: the target global expect of
+	// an object, or of the node if Path is empty.
+	Intent struct {
+		Path         string      `json:"path,omitempty"`
+		Node         string      `json:"node,omitempty"`
+		GlobalExpect string      `json:"global_expect"`
+		Created      timestamp.T `json:"created"`
+		Resumed      timestamp.T `json:"resumed,omitempty"`
+		Reason       string      `json:"reason,omitempty"`
+	}
+
+	// T maps intent keys to intents.
+	T map[string]Intent
+
+	// Recovery is the result of the journal recovery at daemon startup.
+	Recovery struct {
+		Resumed   []Intent `json:"resumed"`
+		Cancelled []Intent `json:"cancelled"`
+	}
+
+	// Validator returns a non-nil error if the intent can not be resumed
+	// safely. The error message is recorded as the cancel reason.
+	Validator func(Intent) error
+)
+
+var (
+	lockTimeout = 5 * time.Second
+)
+
+// Key returns the journal index of the intent.
+func (t Intent) Key() string {
+	if t.Path == "" {
+		return "node:" + t.Node
+	}
+	return t.Path
+}
+
+func (t Intent) String() string {
+	return fmt.Sprintf("%s => %s", t.Key(), t.GlobalExpect)
+}
+
+// List returns the intents sorted by key.
+func (t T) List() []Intent {
+	l := make([]Intent, 0, len(t))
+	for _, i := range t {
+		l = append(l, i)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Key() < l[j].Key()
+	})
+	return l
+}
+
+// File returns the path of the node orchestration journal.
+func File() string {
+	return filepath.Join(rawconfig.Node.Paths.Var, "orchestrations.json")
+}
+
+// Load returns the intents stored in the node orchestration journal.
+func Load() (T, error) {
+	data := make(T)
+	b, err := ioutil.ReadFile(File())
+	switch {
+	case os.IsNotExist(err):
+		return data, nil
+	case err != nil:
+		return data, err
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// Begin records the intent in the journal, replacing the previous intent
+// of the same object or node.
+func Begin(i Intent) error {
+	if i.Created.Time().IsZero() {
+		i.Created = timestamp.Now()
+	}
+	return update(func(data T) {
+		data[i.Key()] = i
+	})
+}
+
+// End removes the intent with the key from the journal.
+func End(key string) error {
+	return update(func(data T) {
+		delete(data, key)
+	})
+}
+
+// Recover sorts the journaled intents in resumed and cancelled lists.
+// The intents older than maxAge, or refused by the validator, are
+// cancelled and removed from the journal. The others are marked resumed
+// and kept, until End is called when their orchestration completes.
+// A zero maxAge disables the age check.
+func Recover(maxAge time.Duration, validate Validator) (Recovery, error) {
+	r := Recovery{
+		Resumed:   make([]Intent, 0),
+		Cancelled: make([]Intent, 0),
+	}
+	now := time.Now()
+	err := update(func(data T) {
+		for _, i := range data.List() {
+			var err error
+			switch {
+			case maxAge > 0 && now.Sub(i.Created.Time()) > maxAge:
+				err = fmt.Errorf("expired after %s", maxAge)
+			case validate != nil:
+				err = validate(i)
+			}
+			if err != nil {
+				i.Reason = err.Error()
+				r.Cancelled = append(r.Cancelled, i)
+				delete(data, i.Key())
+				continue
+			}
+			i.Resumed = timestamp.New(now)
+			data[i.Key()] = i
+			r.Resumed = append(r.Resumed, i)
+		}
+	})
+	return r, err
+}
+
+// update applies fn to the journal content under the journal lock.
+func update(fn func(T)) error {
+	p := File()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	lock := flock.New(p+".lock", xsession.ID, fcntllock.New)
+	if err := lock.Lock(lockTimeout, "orchestration journal update"); err != nil {
+		return err
+	}
+	defer func() { _ = lock.UnLock() }()
+	data, err := Load()
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	fn(data)
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
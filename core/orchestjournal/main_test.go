@@ -0,0 +1,46 @@
+package orchestjournal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/timestamp"
+)
+
+func TestRecover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orchestjournal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	rawconfig.Node.Paths.Var = dir
+
+	assert.NoError(t, Begin(Intent{Path: "ns1/svc/s1", GlobalExpect: "started"}))
+	assert.NoError(t, Begin(Intent{Path: "ns1/svc/s2", GlobalExpect: "stopped"}))
+	assert.NoError(t, Begin(Intent{Node: "n1", GlobalExpect: "frozen"}))
+	assert.NoError(t, Begin(Intent{Path: "ns1/svc/s3", GlobalExpect: "purged", Created: timestamp.New(time.Now().Add(-time.Hour))}))
+	assert.NoError(t, Begin(Intent{Path: "ns1/svc/s4", GlobalExpect: "started"}))
+	assert.NoError(t, End("ns1/svc/s4"))
+
+	r, err := Recover(time.Minute, func(i Intent) error {
+		if i.Path == "ns1/svc/s2" {
+			return fmt.Errorf("object deleted")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, r.Resumed, 2)
+	assert.Equal(t, "node:n1", r.Resumed[0].Key())
+	assert.Equal(t, "ns1/svc/s1", r.Resumed[1].Key())
+	assert.False(t, r.Resumed[1].Resumed.IsZero())
+	assert.Len(t, r.Cancelled, 2)
+	assert.Equal(t, "object deleted", r.Cancelled[0].Reason)
+	assert.Equal(t, "expired after 1m0s", r.Cancelled[1].Reason)
+
+	data, err := Load()
+	assert.NoError(t, err)
+	assert.Len(t, data, 2, "cancelled intents are removed from the journal")
+}
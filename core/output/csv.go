@@ -0,0 +1,79 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+//
+// SprintCSV returns the csv representation of the data. A json array is
+// rendered as a row per element, any other value as a single row. The
+// columns are the sorted flattened keys of all the rows, and the scalar
+// rows values are in the "value" column.
+//
+func SprintCSV(data interface{}) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "", err
+	}
+	elements, ok := v.([]interface{})
+	if !ok {
+		elements = []interface{}{v}
+	}
+	rows := make([]map[string]interface{}, len(elements))
+	columns := make(map[string]interface{})
+	for i, e := range elements {
+		row := Flatten(e)
+		if value, ok := row[""]; ok {
+			delete(row, "")
+			row["value"] = value
+		}
+		for k := range row {
+			columns[k] = nil
+		}
+		rows[i] = row
+	}
+	header := make([]string, 0, len(columns))
+	for k := range columns {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, k := range header {
+			if value, ok := row[k]; ok {
+				record[i] = csvCell(value)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+// csvCell returns the cell text of a flattened value, the json encoded
+// strings being unquoted.
+func csvCell(value interface{}) string {
+	s, _ := value.(string)
+	if strings.HasPrefix(s, `"`) {
+		var unquoted string
+		if err := json.Unmarshal([]byte(s), &unquoted); err == nil {
+			return unquoted
+		}
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprintCSV(t *testing.T) {
+	type res struct {
+		RID    string            `json:"rid"`
+		Status string            `json:"status"`
+		Tags   []string          `json:"tags,omitempty"`
+		Info   map[string]string `json:"info,omitempty"`
+	}
+	t.Run("a row per array element", func(t *testing.T) {
+		data := []res{
+			{RID: "fs#1", Status: "up", Tags: []string{"a", "b"}},
+			{RID: "ip#1", Status: "down", Info: map[string]string{"ipaddr": "10.0.0.1"}},
+		}
+		s, err := SprintCSV(data)
+		require.Nil(t, err)
+		assert.Equal(t, "info.ipaddr,rid,status,tags[0],tags[1]\n,fs#1,up,a,b\n10.0.0.1,ip#1,down,,\n", s)
+	})
+	t.Run("a single row for an object", func(t *testing.T) {
+		s, err := SprintCSV(res{RID: "app#1", Status: "n/a, unknown"})
+		require.Nil(t, err)
+		assert.Equal(t, "rid,status\napp#1,\"n/a, unknown\"\n", s)
+	})
+	t.Run("scalars in the value column", func(t *testing.T) {
+		s, err := SprintCSV([]string{"n1", "n2"})
+		require.Nil(t, err)
+		assert.Equal(t, "value\nn1\nn2\n", s)
+	})
+}
+
+func TestSprintFlat(t *testing.T) {
+	s := SprintFlat([]byte(`{"a": {"b/c": [1, "x"]}, "d": true}`))
+	assert.Equal(t, "a.'b/c'[0] = 1\na.'b/c'[1] = \"x\"\nd = true\n", s)
+}
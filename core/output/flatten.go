@@ -94,7 +94,10 @@ func flatten(value interface{}, lkey string, flattened *map[string]interface{})
 			if strings.ContainsAny(rkey, ".#$/") {
 				rkey = fmt.Sprintf("'%s'", rkey)
 			}
-			k := fmt.Sprintf("%s.%s", lkey, rkey)
+			k := rkey
+			if lkey != "" {
+				k = lkey + "." + rkey
+			}
 			flatten(rval, k, flattened)
 		}
 	default:
@@ -3,10 +3,13 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 // T encodes as an integer one of the supported output formats
-// (json, flat, human, table, csv)
+// (json, flat, human, table, csv, yaml)
 type T int
 
 const (
@@ -22,6 +25,8 @@ const (
 	Table
 	// CSV is the csv tabular output format
 	CSV
+	// YAML is the yaml output format
+	YAML
 )
 
 var toString = map[T]string{
@@ -31,6 +36,7 @@ var toString = map[T]string{
 	Flat:     "flat",
 	Table:    "table",
 	CSV:      "csv",
+	YAML:     "yaml",
 }
 
 var toID = map[string]T{
@@ -41,6 +47,16 @@ var toID = map[string]T{
 	"flat_json": Flat, // compat
 	"table":     Table,
 	"csv":       CSV,
+	"yaml":      YAML,
+}
+
+// extraFormats are the format values accepted by the --format flag in
+// addition to the rendered formats: "auto" selects the command default,
+// and "ini" is the raw configuration format of the config printers.
+var extraFormats = map[string]interface{}{
+	"":     nil,
+	"auto": nil,
+	"ini":  nil,
 }
 
 func (t T) String() string {
@@ -52,6 +68,26 @@ func New(s string) T {
 	return toID[s]
 }
 
+// Validate returns an error if s is not a supported output format.
+func Validate(s string) error {
+	if _, ok := toID[s]; ok {
+		return nil
+	}
+	if _, ok := extraFormats[s]; ok {
+		return nil
+	}
+	return fmt.Errorf("invalid output format %s: supported formats are %s", s, strings.Join(formats(), ", "))
+}
+
+func formats() []string {
+	l := make([]string, 0, len(toString)+1)
+	for _, s := range toString {
+		l = append(l, s)
+	}
+	sort.Strings(l)
+	return append(l, "auto")
+}
+
 // MarshalJSON marshals the enum as a quoted json string
 func (t T) MarshalJSON() ([]byte, error) {
 	buffer := bytes.NewBufferString(`"`)
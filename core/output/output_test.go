@@ -0,0 +1,14 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	for _, s := range []string{"", "auto", "ini", "json", "flat", "yaml"} {
+		assert.NoError(t, Validate(s), s)
+	}
+	assert.Error(t, Validate("yml"))
+}
@@ -56,6 +56,12 @@ func (t Renderer) Sprint() string {
 		}
 	}
 	switch format {
+	case CSV:
+		s, err := SprintCSV(t.Data)
+		if err != nil {
+			return err.Error() + "\n"
+		}
+		return s
 	case Flat:
 		b, _ := json.Marshal(t.Data)
 		if color.NoColor {
@@ -77,6 +83,12 @@ func (t Renderer) Sprint() string {
 	case JSONLine:
 		b, _ := json.Marshal(t.Data)
 		return string(b) + "\n"
+	case YAML:
+		s, err := SprintYAML(t.Data)
+		if err != nil {
+			return err.Error() + "\n"
+		}
+		return s
 	default:
 		if t.HumanRenderer != nil {
 			return t.HumanRenderer()
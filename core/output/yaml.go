@@ -0,0 +1,55 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yaml11Keywords are the strings yaml 1.1 parsers resolve to booleans or
+// null.
+var yaml11Keywords = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true,
+	"on": true, "off": true, "true": true, "false": true,
+	"null": true, "~": true,
+}
+
+// SprintYAML returns the yaml representation of the data.
+//
+// The data is first marshaled to json, so the json struct tags and custom
+// marshalers apply, and the keys order is preserved.
+func SprintYAML(data interface{}) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return "", err
+	}
+	resetStyle(&node)
+	var buff bytes.Buffer
+	enc := yaml.NewEncoder(&buff)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+// resetStyle drops the json flow and quoting styles inherited from the
+// json document, so the encoder uses the yaml block style. The strings
+// a yaml 1.1 parser would read as booleans or null stay quoted.
+func resetStyle(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" || !yaml11Keywords[strings.ToLower(node.Value)] {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,38 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSprintYAML(t *testing.T) {
+	data := struct {
+		Name    string            `json:"name"`
+		Count   int               `json:"count"`
+		Version string            `json:"version"`
+		Empty   []string          `json:"empty"`
+		Labels  map[string]string `json:"labels"`
+		Nodes   []string          `json:"nodes"`
+	}{
+		Name:    "svc1",
+		Count:   2,
+		Version: "1",
+		Empty:   []string{},
+		Labels:  map[string]string{"b": "yes", "a": "x y"},
+		Nodes:   []string{"n1", "n2"},
+	}
+	s, err := SprintYAML(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `name: svc1
+count: 2
+version: "1"
+empty: []
+labels:
+  a: x y
+  b: "yes"
+nodes:
+- n1
+- n2
+`, s)
+}
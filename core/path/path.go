@@ -106,6 +106,32 @@ func (t T) String() string {
 	return s + t.Name
 }
 
+// FQN returns the fully qualified path string, with the namespace and kind
+// parts even for the root namespace and the svc kind. Ex: root/svc/svc1
+func (t T) FQN() string {
+	if t.Kind == kind.Invalid {
+		return ""
+	}
+	namespace := t.Namespace
+	if namespace == "" {
+		namespace = "root"
+	}
+	return namespace + Separator + t.Kind.String() + Separator + t.Name
+}
+
+// Less returns true if the path sorts before the other path, ordering by
+// namespace, then kind, then name.
+func (t T) Less(other T) bool {
+	switch {
+	case t.Namespace != other.Namespace:
+		return t.Namespace < other.Namespace
+	case t.Kind != other.Kind:
+		return t.Kind.String() < other.Kind.String()
+	default:
+		return t.Name < other.Name
+	}
+}
+
 func (t T) IsZero() bool {
 	return t.Name == "" && t.Namespace == "" && t.Kind == kind.Invalid
 }
@@ -182,10 +208,13 @@ func (t *T) UnmarshalJSON(b []byte) error {
 // Trick:
 // The 'f*' pattern matches all svc objects in the root namespace.
 // The '*' pattern matches all svc objects in all namespaces.
+// The 'root/svc/f*' and 'root/**' patterns match the root namespace
+// objects, though their canonical path string has no namespace part.
 //
 func (t T) Match(pattern string) bool {
 	l := strings.Split(pattern, "/")
 	s := t.String()
+	fqn := t.FQN()
 	f := fnmatch.FNM_IGNORECASE | fnmatch.FNM_PATHNAME
 	switch len(l) {
 	case 1:
@@ -216,6 +245,9 @@ func (t T) Match(pattern string) bool {
 		if fnmatch.Match(pattern, s, f) {
 			return true
 		}
+		if fnmatch.Match(pattern, fqn, f) {
+			return true
+		}
 	case 3:
 		if l[1] == "svc" && l[0] == "*" {
 			// */svc/foo => foo ... for root namespace
@@ -226,33 +258,45 @@ func (t T) Match(pattern string) bool {
 		if fnmatch.Match(pattern, s, f) {
 			return true
 		}
+		if fnmatch.Match(pattern, fqn, f) {
+			return true
+		}
 	}
 	return false
 }
 
+// NewRelation returns the relation to the object path, or to its instance
+// on node if node is not empty.
+func NewRelation(p T, node string) Relation {
+	if node == "" {
+		return Relation(p.String())
+	}
+	return Relation(p.String() + "@" + node)
+}
+
 func (t Relation) String() string {
 	return string(t)
 }
 
+// Split returns the path and node parts of the relation.
 func (t Relation) Split() (T, string, error) {
 	p, err := t.Path()
 	return p, t.Node(), err
 }
 
+// Node returns the node part of the relation, or an empty string if the
+// relation is an object path.
 func (t Relation) Node() string {
-	var s string
-	if strings.Contains(string(t), "@") {
-		s = strings.SplitN(string(t), "@", 1)[1]
+	l := strings.SplitN(string(t), "@", 2)
+	if len(l) < 2 {
+		return ""
 	}
-	return s
+	return strings.ToLower(l[1])
 }
 
+// Path returns the object path part of the relation.
 func (t Relation) Path() (T, error) {
-	var s string
-	if strings.Contains(string(t), "@") {
-		s = strings.SplitN(string(t), "@", 1)[0]
-	}
-	s = string(t)
+	s := strings.SplitN(string(t), "@", 2)[0]
 	return Parse(s)
 }
 
@@ -263,3 +307,29 @@ func (t L) String() string {
 	}
 	return strings.Join(l, ",")
 }
+
+// Len implements sort.Interface
+func (t L) Len() int {
+	return len(t)
+}
+
+// Less implements sort.Interface, ordering by namespace, kind and name.
+func (t L) Less(i, j int) bool {
+	return t[i].Less(t[j])
+}
+
+// Swap implements sort.Interface
+func (t L) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+}
+
+// Filter returns the paths matching the pattern.
+func (t L) Filter(pattern string) L {
+	l := make(L, 0)
+	for _, p := range t {
+		if p.Match(pattern) {
+			l = append(l, p)
+		}
+	}
+	return l
+}
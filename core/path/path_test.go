@@ -2,6 +2,7 @@ package path
 
 import (
 	"encoding/json"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -298,3 +299,100 @@ func TestMatch(t *testing.T) {
 		assert.Equal(t, test.match, path.Match(test.pattern))
 	}
 }
+
+func TestMatchEdgeCases(t *testing.T) {
+	tests := []struct {
+		path    string
+		pattern string
+		match   bool
+	}{
+		{"svc1", "root/svc/svc1", true},
+		{"svc1", "root/svc/*", true},
+		{"svc1", "root/**", true},
+		{"svc1", "*/svc/svc1", true},
+		{"svc1", "svc/svc1", true},
+		{"svc1", "**", true},
+		{"svc1", "SVC1", true},
+		{"svc1", "Root/Svc/Svc*", true},
+		{"svc1", "ns1/**", false},
+		{"svc1", "root/vol/*", false},
+		{"vol/vol1", "root/vol/vol1", true},
+		{"vol/vol1", "root/**", true},
+		{"vol/vol1", "*", false},
+		{"vol/vol1", "**", true},
+		{"ns1/svc/svc1", "ns1/**", true},
+		{"ns1/svc/svc1", "ns1/*", false},
+		{"ns1/svc/svc1", "root/**", false},
+		{"ns1/svc/svc1", "root/svc/svc1", false},
+		{"ns1/svc/svc1", "svc1", false},
+		{"ns1/svc/svc1", "*/svc/svc1", true},
+		{"ns1/svc/svc1", "NS1/SVC/SVC1", true},
+		{"ns1/cfg/c1", "*/cfg/*", true},
+		{"ns1/cfg/c1", "*", false},
+		{"cluster", "root/ccfg/cluster", true},
+		{"ns1/", "ns1/nscfg/namespace", true},
+	}
+	for _, test := range tests {
+		p, err := Parse(test.path)
+		assert.NoError(t, err, test.path)
+		assert.Equal(t, test.match, p.Match(test.pattern), "%s matches %s", test.path, test.pattern)
+	}
+}
+
+func TestFQN(t *testing.T) {
+	for s, fqn := range map[string]string{
+		"svc1":         "root/svc/svc1",
+		"SVC1":         "root/svc/svc1",
+		"vol/vol1":     "root/vol/vol1",
+		"ns1/svc/svc1": "ns1/svc/svc1",
+		"cluster":      "root/ccfg/cluster",
+	} {
+		p, err := Parse(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, fqn, p.FQN())
+	}
+	assert.Equal(t, "", T{}.FQN())
+}
+
+func TestRelation(t *testing.T) {
+	tests := []struct {
+		relation string
+		path     string
+		node     string
+		ok       bool
+	}{
+		{"svc1", "svc1", "", true},
+		{"svc1@n1", "svc1", "n1", true},
+		{"ns1/svc/svc1@N1", "ns1/svc/svc1", "n1", true},
+		{"root/svc/svc1@n1", "svc1", "n1", true},
+		{"svc1@", "svc1", "", true},
+		{"@n1", "", "n1", false},
+		{"ns1/svc/svc#1@n1", "", "n1", false},
+	}
+	for _, test := range tests {
+		r := Relation(test.relation)
+		p, node, err := r.Split()
+		if test.ok {
+			assert.NoError(t, err, test.relation)
+		} else {
+			assert.Error(t, err, test.relation)
+		}
+		assert.Equal(t, test.path, p.String(), test.relation)
+		assert.Equal(t, test.node, node, test.relation)
+	}
+	p, _ := Parse("ns1/svc/svc1")
+	assert.Equal(t, Relation("ns1/svc/svc1@n1"), NewRelation(p, "n1"))
+	assert.Equal(t, Relation("ns1/svc/svc1"), NewRelation(p, ""))
+}
+
+func TestSort(t *testing.T) {
+	l := L{}
+	for _, s := range []string{"ns2/svc/s1", "s2", "ns1/vol/v1", "vol/v1", "s1", "ns1/svc/s2", "ns1/svc/s1", "cfg/c1"} {
+		p, err := Parse(s)
+		assert.NoError(t, err, s)
+		l = append(l, p)
+	}
+	sort.Sort(l)
+	assert.Equal(t, "ns1/svc/s1,ns1/svc/s2,ns1/vol/v1,ns2/svc/s1,cfg/c1,s1,s2,vol/v1", l.String())
+	assert.Equal(t, "ns1/svc/s1,ns1/svc/s2", l.Filter("ns1/svc/*").String())
+}
@@ -0,0 +1,80 @@
+package placement
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// NodeStats holds the node metrics the placement policies
+	// feed on to rank the candidate nodes.
+	NodeStats struct {
+		Load15M float64
+		Score   uint
+	}
+
+	// Stats is a map of node metrics indexed by nodename.
+	Stats map[string]NodeStats
+)
+
+//
+// Rank returns the candidate nodes ordered by decreasing priority
+// according to the placement policy.
+//
+// name is the object name. The shift policy uses its scaler slice
+// prefix (<n>.<name>) to rotate the nodes list, and the spread policy
+// hashes it with the nodenames.
+//
+// nodes is expected in the nodes keyword order. The returned slice is
+// a copy, so the caller's slice is never reordered.
+//
+func (t T) Rank(name string, nodes []string, stats Stats) []string {
+	l := make([]string, len(nodes))
+	copy(l, nodes)
+	switch t {
+	case LoadAvg:
+		sort.SliceStable(l, func(i, j int) bool {
+			return stats[l[i]].Load15M < stats[l[j]].Load15M
+		})
+	case Score:
+		sort.SliceStable(l, func(i, j int) bool {
+			return stats[l[i]].Score > stats[l[j]].Score
+		})
+	case Spread:
+		h := make(map[string]uint32)
+		for _, nodename := range l {
+			h[nodename] = spreadHash(name, nodename)
+		}
+		sort.SliceStable(l, func(i, j int) bool {
+			return h[l[i]] < h[l[j]]
+		})
+	case Shift:
+		if n := len(l); n > 0 {
+			i := sliceIndex(name) % n
+			l = append(l[i:], l[:i]...)
+		}
+	}
+	return l
+}
+
+func spreadHash(name, nodename string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name + nodename))
+	return h.Sum32()
+}
+
+// sliceIndex returns the scaler slice index found in a <n>.<name>
+// object name, or 0 if the object is not a scaler slice.
+func sliceIndex(name string) int {
+	l := strings.SplitN(name, ".", 2)
+	if len(l) != 2 {
+		return 0
+	}
+	i, err := strconv.Atoi(l[0])
+	if err != nil || i < 0 {
+		return 0
+	}
+	return i
+}
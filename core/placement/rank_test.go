@@ -0,0 +1,44 @@
+package placement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	nodes := []string{"n1", "n2", "n3"}
+	stats := Stats{
+		"n1": {Load15M: 2.1, Score: 10},
+		"n2": {Load15M: 0.3, Score: 90},
+		"n3": {Load15M: 1.2, Score: 50},
+	}
+	cases := map[string]struct {
+		policy   T
+		name     string
+		expected []string
+	}{
+		"none":          {None, "s1", []string{"n1", "n2", "n3"}},
+		"nodes order":   {NodesOrder, "s1", []string{"n1", "n2", "n3"}},
+		"load avg":      {LoadAvg, "s1", []string{"n2", "n3", "n1"}},
+		"score":         {Score, "s1", []string{"n2", "n3", "n1"}},
+		"shift slice 0": {Shift, "0.s1", []string{"n1", "n2", "n3"}},
+		"shift slice 1": {Shift, "1.s1", []string{"n2", "n3", "n1"}},
+		"shift slice 5": {Shift, "5.s1", []string{"n3", "n1", "n2"}},
+		"shift no scal": {Shift, "s1", []string{"n1", "n2", "n3"}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.policy.Rank(c.name, nodes, stats))
+		})
+	}
+	t.Run("input is not reordered", func(t *testing.T) {
+		LoadAvg.Rank("s1", nodes, stats)
+		assert.Equal(t, []string{"n1", "n2", "n3"}, nodes)
+	})
+	t.Run("spread is stable and complete", func(t *testing.T) {
+		l := Spread.Rank("s1", nodes, stats)
+		assert.ElementsMatch(t, nodes, l)
+		assert.Equal(t, l, Spread.Rank("s1", []string{"n3", "n1", "n2"}, nil))
+	})
+}
@@ -190,7 +190,7 @@ func MountPointFromName(name string) string {
 func baseKeywords(p Pooler, size float64, acs volaccess.T) []string {
 	return []string{
 		fmt.Sprintf("pool=%s", p.Name()),
-		fmt.Sprintf("size=%s", sizeconv.ExactBSizeCompact(size)),
+		fmt.Sprintf("size=%s", sizeconv.Compact(uint64(size), sizeconv.IEC)),
 		fmt.Sprintf("access=%s", acs),
 	}
 }
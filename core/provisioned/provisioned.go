@@ -54,6 +54,12 @@ func FromBool(v bool) T {
 	return bToID[v]
 }
 
+// FromString returns the provisioned state from its string
+// representation, Undef if the string is not a known state.
+func FromString(s string) T {
+	return sToID[s]
+}
+
 func (t T) String() string {
 	return toString[t]
 }
@@ -0,0 +1,177 @@
+package rawconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// CommentKey is the name of the section key hosting the section
+	// comment in the json representation of a configuration.
+	CommentKey = "comment"
+)
+
+// IniLoadOptions are the options used to parse the ini configuration files.
+var IniLoadOptions = ini.LoadOptions{
+	Loose:                      true,
+	AllowPythonMultilineValues: true,
+	SpaceBeforeInlineComment:   true,
+}
+
+// FromIniFile returns the raw configuration of a parsed ini file, with
+// the sections and keys order preserved and the section comments exposed
+// as a "comment" key.
+func FromIniFile(f *ini.File) T {
+	r := T{}
+	r.Data = orderedmap.New()
+	for _, s := range f.Sections() {
+		sectionMap := *orderedmap.New()
+		if s.Comment != "" {
+			sectionMap.Set(CommentKey, stripComment(s.Comment))
+		}
+		for _, k := range s.Keys() {
+			sectionMap.Set(k.Name(), k.Value())
+		}
+		r.Data.Set(s.Name(), sectionMap)
+	}
+	return r
+}
+
+// FromIni parses ini formatted bytes and returns the raw configuration.
+func FromIni(b []byte) (T, error) {
+	f, err := ini.LoadSources(IniLoadOptions, b)
+	if err != nil {
+		return T{}, err
+	}
+	return FromIniFile(f), nil
+}
+
+// FromJSON parses json formatted bytes and returns the raw configuration.
+func FromJSON(b []byte) (T, error) {
+	r := T{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+// Parse returns the raw configuration from ini or json formatted bytes.
+func Parse(b []byte) (T, error) {
+	if IsJSON(b) {
+		return FromJSON(b)
+	}
+	return FromIni(b)
+}
+
+// IsJSON returns true if the bytes look like a json document.
+func IsJSON(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{'
+}
+
+// IniFile returns the ini file representation of the raw configuration.
+// The "metadata" section is dropped, and the "comment" keys are
+// converted to section comments.
+func (t T) IniFile() (*ini.File, error) {
+	f := ini.Empty()
+	err := t.fillIniFile(func(name string) (*ini.Section, error) {
+		return f.Section(name), nil
+	})
+	return f, err
+}
+
+func (t T) fillIniFile(section func(string) (*ini.Section, error)) error {
+	if t.Data == nil {
+		return nil
+	}
+	for _, name := range t.Data.Keys() {
+		if name == "metadata" {
+			continue
+		}
+		m, _ := t.Data.Get(name)
+		omap, ok := m.(orderedmap.OrderedMap)
+		if !ok {
+			return fmt.Errorf("invalid section %s in raw config format: %+v", name, m)
+		}
+		s, err := section(name)
+		if err != nil {
+			return err
+		}
+		for _, option := range omap.Keys() {
+			value, _ := omap.Get(option)
+			if option == CommentKey {
+				if v, ok := value.(string); ok {
+					s.Comment = formatComment(v)
+				}
+				continue
+			}
+			s.Key(option).SetValue(ValueString(value))
+		}
+	}
+	return nil
+}
+
+//
+// Ini returns the ini formatted text of the raw configuration.
+//
+// The DEFAULT section header is written without setting the ini package
+// DefaultHeader global: the sections are appended to a file allowing non
+// unique sections, so the DEFAULT section is not the implicit headerless
+// first section.
+//
+func (t T) Ini() (string, error) {
+	f := ini.Empty(ini.LoadOptions{AllowNonUniqueSections: true})
+	if err := t.fillIniFile(f.NewSection); err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if _, err := f.WriteTo(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ValueString returns the ini string representation of a json value.
+func ValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		l := make([]string, len(v))
+		for i, e := range v {
+			l[i] = ValueString(e)
+		}
+		return strings.Join(l, " ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func stripComment(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		line = strings.TrimLeft(line, "#;")
+		lines[i] = strings.TrimPrefix(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatComment(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = "# " + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package rawconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/ini.v1"
+)
+
+const iniConfig = `[DEFAULT]
+id    = 2f1d2b4d-6a5c-4cd3-a3b1-bf5b19c6a6f2
+nodes = n1 n2
+
+# the main application
+# started first
+[app#1]
+start = /bin/true
+type  = forking
+
+`
+
+func TestIniJSONRoundTrip(t *testing.T) {
+	c, err := FromIni([]byte(iniConfig))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"DEFAULT", "app#1"}, c.Data.Keys())
+
+	b, err := json.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"DEFAULT":{"id":"2f1d2b4d-6a5c-4cd3-a3b1-bf5b19c6a6f2","nodes":"n1 n2"},"app#1":{"comment":"the main application\nstarted first","start":"/bin/true","type":"forking"}}`, string(b))
+
+	c, err = Parse(b)
+	assert.Nil(t, err)
+	s, err := c.Ini()
+	assert.Nil(t, err)
+	assert.Equal(t, iniConfig, s)
+}
+
+func TestValueString(t *testing.T) {
+	c, err := FromJSON([]byte(`{"DEFAULT": {"nodes": ["n1", "n2"], "orchestrate": "ha", "priority": 10, "disable": false}}`))
+	assert.Nil(t, err)
+	s, err := c.Ini()
+	assert.Nil(t, err)
+	assert.Equal(t, "[DEFAULT]\nnodes       = n1 n2\norchestrate = ha\npriority    = 10\ndisable     = false\n\n", s)
+	assert.False(t, ini.DefaultHeader, "the ini package global is not changed")
+}
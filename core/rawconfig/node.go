@@ -31,6 +31,9 @@ type (
 		Paths    AgentPaths            `mapstructure:"paths"`
 		Cluster  clusterSection        `mapstructure:"cluster"`
 		Node     nodeSection           `mapstructure:"node"`
+		Log      logSection            `mapstructure:"log"`
+		Syslog   syslogSection         `mapstructure:"syslog"`
+		Journald journaldSection       `mapstructure:"journald"`
 		Palette  palette.StringPalette `mapstructure:"palette"`
 		Colorize *palette.ColorPaletteFunc
 		Color    *palette.ColorPalette
@@ -44,9 +47,30 @@ type (
 	}
 
 	nodeSection struct {
+		Nodename  string `mapstructure:"nodename"`
 		Env       string `mapstructure:"env"`
 		Collector string `mapstructure:"dbopensvc"`
 	}
+
+	logSection struct {
+		Sinks      string `mapstructure:"sinks"`
+		MaxSize    string `mapstructure:"max_size"`
+		MaxBackups int    `mapstructure:"max_backups"`
+		MaxAge     int    `mapstructure:"max_age"`
+	}
+
+	syslogSection struct {
+		Facility string `mapstructure:"facility"`
+		Level    string `mapstructure:"level"`
+		Host     string `mapstructure:"host"`
+		Port     string `mapstructure:"port"`
+		Protocol string `mapstructure:"protocol"`
+		SDID     string `mapstructure:"sd_id"`
+	}
+
+	journaldSection struct {
+		Level string `mapstructure:"level"`
+	}
 )
 
 func setDefaults(root string) {
@@ -82,6 +106,13 @@ func setDefaults(root string) {
 		NodeViper.SetDefault("paths.html", filepath.Join(root, "share", "html"))
 		NodeViper.SetDefault("paths.drivers", filepath.Join(root, "drivers"))
 	}
+	NodeViper.SetDefault("log.max_size", "5m")
+	NodeViper.SetDefault("log.max_backups", 1)
+	NodeViper.SetDefault("log.max_age", 30)
+	NodeViper.SetDefault("syslog.facility", "daemon")
+	NodeViper.SetDefault("syslog.level", "info")
+	NodeViper.SetDefault("syslog.protocol", "udp")
+	NodeViper.SetDefault("journald.level", "info")
 	NodeViper.SetDefault("palette.primary", palette.DefaultPrimary)
 	NodeViper.SetDefault("palette.secondary", palette.DefaultSecondary)
 	NodeViper.SetDefault("palette.optimal", palette.DefaultOptimal)
@@ -125,6 +156,11 @@ func loadEpilogue() {
 		fmt.Fprintf(os.Stderr, "Failed to parse the configuration file: %s\n", err)
 		return
 	}
+	if err := hostname.SetOverride(Node.Node.Nodename); err != nil {
+		fmt.Fprintf(os.Stderr, "Ignore the node.nodename configuration: %s\n", err)
+	} else if Node.Node.Nodename != "" {
+		Node.Hostname = hostname.Hostname()
+	}
 	Node.Colorize = palette.NewFunc(Node.Palette)
 	Node.Color = palette.New(Node.Palette)
 }
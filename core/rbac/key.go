@@ -0,0 +1,41 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+)
+
+var (
+	// ErrForbidden is returned when the grants do not allow an operation.
+	ErrForbidden = errors.New("forbidden")
+)
+
+//
+// KeyRole returns the role required on the object namespace to read or
+// write one of its keys. The cfg keys are readable by the guests, while
+// the sec and usr keys are confidential and readable by the admins only.
+// Writing any key requires the admin role.
+//
+func KeyRole(k kind.T, write bool) Role {
+	if !write && k == kind.Cfg {
+		return RoleGuest
+	}
+	return RoleAdmin
+}
+
+// AllowKey returns an ErrForbidden error if the grants do not allow the
+// read or write of the keys of the object p.
+func (t Grants) AllowKey(p path.T, write bool) error {
+	role := KeyRole(p.Kind, write)
+	if t.Allow(role, p.Namespace) {
+		return nil
+	}
+	op := "read"
+	if write {
+		op = "write"
+	}
+	return fmt.Errorf("%w: %s %s keys requires the %s role on namespace %s", ErrForbidden, op, p, role, p.Namespace)
+}
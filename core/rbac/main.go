@@ -0,0 +1,189 @@
+// Package rbac parses the usr objects grants and decides if a grant set
+// allows a role on a namespace.
+//
+// A grant is formatted as <role>[:<namespace pattern>]. The namespace
+// pattern is a fnmatch expression. The cluster-wide roles (root,
+// squatter, blacklistadmin, heartbeat, prioritizer) do not accept a
+// namespace pattern.
+//
+// Examples:
+//
+//	root
+//	admin:test*
+//	operator:prod guest:*
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danwakefield/fnmatch"
+)
+
+type (
+	// Role is a named set of api privileges.
+	Role string
+
+	// Grant associates a role to a namespace pattern.
+	Grant struct {
+		Role      Role
+		Namespace string
+	}
+
+	// Grants is the list of grants of a user.
+	Grants []Grant
+)
+
+const (
+	RoleRoot           Role = "root"
+	RoleSquatter       Role = "squatter"
+	RoleBlacklistAdmin Role = "blacklistadmin"
+	RoleHeartbeat      Role = "heartbeat"
+	RolePrioritizer    Role = "prioritizer"
+	RoleAdmin          Role = "admin"
+	RoleOperator       Role = "operator"
+	RoleGuest          Role = "guest"
+)
+
+var (
+	clusterRoles = map[Role]interface{}{
+		RoleRoot:           nil,
+		RoleSquatter:       nil,
+		RoleBlacklistAdmin: nil,
+		RoleHeartbeat:      nil,
+		RolePrioritizer:    nil,
+	}
+
+	// namespaceRoles maps namespaced roles to the roles they imply.
+	namespaceRoles = map[Role][]Role{
+		RoleAdmin:    {RoleAdmin, RoleOperator, RoleGuest},
+		RoleOperator: {RoleOperator, RoleGuest},
+		RoleGuest:    {RoleGuest},
+	}
+)
+
+// IsCluster returns true if the role is not scoped to namespaces.
+func (t Role) IsCluster() bool {
+	_, ok := clusterRoles[t]
+	return ok
+}
+
+func (t Role) isValid() bool {
+	if t.IsCluster() {
+		return true
+	}
+	_, ok := namespaceRoles[t]
+	return ok
+}
+
+func (t Grant) String() string {
+	if t.Namespace == "" {
+		return string(t.Role)
+	}
+	return string(t.Role) + ":" + t.Namespace
+}
+
+// ParseGrant returns the Grant parsed from its <role>[:<namespace>] string
+// representation.
+func ParseGrant(s string) (Grant, error) {
+	l := strings.SplitN(s, ":", 2)
+	g := Grant{Role: Role(l[0])}
+	if len(l) == 2 {
+		g.Namespace = l[1]
+	}
+	switch {
+	case !g.Role.isValid():
+		return g, fmt.Errorf("grant %s: unknown role %s", s, g.Role)
+	case g.Role.IsCluster() && g.Namespace != "":
+		return g, fmt.Errorf("grant %s: role %s does not accept a namespace", s, g.Role)
+	case !g.Role.IsCluster() && g.Namespace == "":
+		return g, fmt.Errorf("grant %s: role %s requires a namespace", s, g.Role)
+	}
+	return g, nil
+}
+
+// ParseGrants returns the Grants parsed from a list of grant strings.
+func ParseGrants(l []string) (Grants, error) {
+	grants := make(Grants, 0, len(l))
+	for _, s := range l {
+		g, err := ParseGrant(s)
+		if err != nil {
+			return grants, err
+		}
+		grants = append(grants, g)
+	}
+	return grants, nil
+}
+
+// Strings returns the string representations of the grants.
+func (t Grants) Strings() []string {
+	l := make([]string, len(t))
+	for i, g := range t {
+		l[i] = g.String()
+	}
+	return l
+}
+
+// HasRoot returns true if the grants include the root role.
+func (t Grants) HasRoot() bool {
+	for _, g := range t {
+		if g.Role == RoleRoot {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow returns true if the grants allow the role on the namespace. The
+// root role allows every role on every namespace. The namespace is
+// ignored for cluster roles.
+func (t Grants) Allow(role Role, namespace string) bool {
+	if t.HasRoot() {
+		return true
+	}
+	for _, g := range t {
+		if role.IsCluster() {
+			if g.Role == role {
+				return true
+			}
+			continue
+		}
+		if !g.implies(role) {
+			continue
+		}
+		if fnmatch.Match(g.Namespace, namespace, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespaces returns the sorted list of namespace patterns where the role
+// is allowed.
+func (t Grants) Namespaces(role Role) []string {
+	m := make(map[string]interface{})
+	for _, g := range t {
+		if g.Role == RoleRoot {
+			return []string{"*"}
+		}
+		if g.implies(role) {
+			m[g.Namespace] = nil
+		}
+	}
+	l := make([]string, 0, len(m))
+	for ns := range m {
+		l = append(l, ns)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (t Grant) implies(role Role) bool {
+	for _, r := range namespaceRoles[t.Role] {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/path"
+)
+
+func TestParseGrant(t *testing.T) {
+	for _, s := range []string{"root", "admin:test*", "guest:*", "heartbeat"} {
+		g, err := ParseGrant(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, s, g.String())
+	}
+	for _, s := range []string{"foo", "admin", "root:ns1", ""} {
+		_, err := ParseGrant(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	grants, err := ParseGrants([]string{"admin:test*", "guest:prod", "prioritizer"})
+	assert.NoError(t, err)
+	assert.True(t, grants.Allow(RoleAdmin, "test1"))
+	assert.True(t, grants.Allow(RoleOperator, "test1"))
+	assert.True(t, grants.Allow(RoleGuest, "prod"))
+	assert.False(t, grants.Allow(RoleOperator, "prod"))
+	assert.False(t, grants.Allow(RoleGuest, "dev"))
+	assert.True(t, grants.Allow(RolePrioritizer, ""))
+	assert.False(t, grants.Allow(RoleSquatter, ""))
+	assert.Equal(t, []string{"prod", "test*"}, grants.Namespaces(RoleGuest))
+
+	grants, err = ParseGrants([]string{"root"})
+	assert.NoError(t, err)
+	assert.True(t, grants.Allow(RoleAdmin, "any"))
+	assert.True(t, grants.Allow(RoleSquatter, ""))
+	assert.Equal(t, []string{"*"}, grants.Namespaces(RoleAdmin))
+}
+
+func TestAllowKey(t *testing.T) {
+	grants, err := ParseGrants([]string{"admin:test", "guest:prod"})
+	assert.NoError(t, err)
+	parse := func(s string) path.T {
+		p, err := path.Parse(s)
+		assert.NoError(t, err)
+		return p
+	}
+	assert.NoError(t, grants.AllowKey(parse("test/sec/s1"), true))
+	assert.NoError(t, grants.AllowKey(parse("prod/cfg/c1"), false))
+	assert.ErrorIs(t, grants.AllowKey(parse("prod/cfg/c1"), true), ErrForbidden)
+	assert.ErrorIs(t, grants.AllowKey(parse("prod/sec/s1"), false), ErrForbidden)
+	assert.ErrorIs(t, grants.AllowKey(parse("dev/cfg/c1"), false), ErrForbidden)
+}
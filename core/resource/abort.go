@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/exitcode"
+)
+
+type (
+	// AbortClass qualifies the reason of a start veto, so the --force
+	// option can bypass the classes known to be safe to override.
+	AbortClass int
+
+	// ErrAbortStart is returned by the Aborter drivers vetoing a start.
+	ErrAbortStart struct {
+		RID    string
+		Class  AbortClass
+		Reason string
+	}
+
+	// ErrsAbortStart is the list of start vetoes of an object action.
+	ErrsAbortStart []ErrAbortStart
+)
+
+const (
+	// AbortUnknown is the class of vetoes not qualified by the driver,
+	// like a failure to evaluate the veto conditions.
+	AbortUnknown AbortClass = iota
+	// AbortConfig is the class of vetoes caused by an unsupported
+	// resource configuration.
+	AbortConfig
+	// AbortAddrInUse is the class of vetoes caused by an ip address
+	// already in use on the network.
+	AbortAddrInUse
+	// AbortNoCarrier is the class of vetoes caused by a network
+	// interface without carrier.
+	AbortNoCarrier
+	// AbortReservationConflict is the class of vetoes caused by a device
+	// reserved by another node.
+	AbortReservationConflict
+)
+
+var (
+	abortClassString = map[AbortClass]string{
+		AbortUnknown:             "unknown",
+		AbortConfig:              "config",
+		AbortAddrInUse:           "addr in use",
+		AbortNoCarrier:           "no carrier",
+		AbortReservationConflict: "reservation conflict",
+	}
+
+	// abortClassForceable lists the classes the --force option bypasses.
+	abortClassForceable = map[AbortClass]bool{
+		AbortNoCarrier:           true,
+		AbortReservationConflict: true,
+	}
+)
+
+func (t AbortClass) String() string {
+	return abortClassString[t]
+}
+
+// IsForceable returns true if the --force option bypasses the vetoes
+// of this class.
+func (t AbortClass) IsForceable() bool {
+	return abortClassForceable[t]
+}
+
+// NewErrAbortStart returns a start veto of the class, explained by the
+// formatted reason.
+func NewErrAbortStart(class AbortClass, format string, args ...interface{}) error {
+	return ErrAbortStart{
+		Class:  class,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+func (t ErrAbortStart) Error() string {
+	if t.RID == "" {
+		return t.Reason
+	}
+	return t.RID + ": " + t.Reason
+}
+
+// ExitCode implements the exitcode.Coder interface.
+func (t ErrAbortStart) ExitCode() int {
+	return exitcode.Aborted
+}
+
+func (t ErrsAbortStart) Error() string {
+	l := make([]string, len(t))
+	for i, e := range t {
+		l[i] = e.Error()
+	}
+	return "abort start: " + strings.Join(l, ", ")
+}
+
+// ExitCode implements the exitcode.Coder interface.
+func (t ErrsAbortStart) ExitCode() int {
+	return exitcode.Aborted
+}
@@ -0,0 +1,29 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortClass(t *testing.T) {
+	assert.True(t, AbortNoCarrier.IsForceable())
+	assert.True(t, AbortReservationConflict.IsForceable())
+	assert.False(t, AbortAddrInUse.IsForceable())
+	assert.False(t, AbortConfig.IsForceable())
+	assert.False(t, AbortUnknown.IsForceable())
+	assert.Equal(t, "addr in use", AbortAddrInUse.String())
+}
+
+func TestErrAbortStart(t *testing.T) {
+	err := NewErrAbortStart(AbortAddrInUse, "%s is alive", "10.0.0.1")
+	var e ErrAbortStart
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, AbortAddrInUse, e.Class)
+	assert.Equal(t, "10.0.0.1 is alive", e.Error())
+	e.RID = "ip#0"
+	assert.Equal(t, "ip#0: 10.0.0.1 is alive", e.Error())
+	errs := ErrsAbortStart{e, {RID: "disk#1", Class: AbortReservationConflict, Reason: "/dev/sdb is reserved by 0x1"}}
+	assert.Equal(t, "abort start: ip#0: 10.0.0.1 is alive, disk#1: /dev/sdb is reserved by 0x1", errs.Error())
+}
@@ -0,0 +1,128 @@
+package resource
+
+import (
+	"opensvc.com/opensvc/util/device"
+)
+
+type (
+	// ExposedDeviceser is implemented by drivers exposing block devices
+	// that other resources of the object can be stacked on.
+	ExposedDeviceser interface {
+		ExposedDevices() []*device.T
+	}
+
+	// BaseDeviceser is implemented by drivers stacked on block devices,
+	// as declared by their configuration. For example the device of a fs
+	// resource, or the source devices of a disk.raw resource.
+	BaseDeviceser interface {
+		BaseDevices() []*device.T
+	}
+
+	// SubDeviceser is implemented by drivers built on block devices
+	// discovered on the node, like the physical volumes of a disk.lv
+	// resource. Drivers not implementing this interface use their base
+	// devices as sub devices.
+	SubDeviceser interface {
+		SubDevices() []*device.T
+	}
+
+	// ReservableDeviceser is implemented by drivers whose devices can be
+	// protected by a scsi persistent reservation.
+	ReservableDeviceser interface {
+		ReservableDevices() []*device.T
+	}
+
+	// ClaimedDeviceser is implemented by drivers using block devices
+	// exclusively, without exposing nor stacking on them.
+	ClaimedDeviceser interface {
+		ClaimedDevices() []*device.T
+	}
+)
+
+//
+// sortByDeviceStack reorders the drivers of each resourceset, so a
+// driver exposing a device comes before the drivers stacked on this
+// device. The drivers order is otherwise preserved.
+//
+// The resourcesets are already ordered by driver group, so this only
+// matters for stacks within a driver group, like a disk.raw on a disk.lv.
+//
+func (t Drivers) sortByDeviceStack() {
+	begin := 0
+	for i := 1; i <= len(t); i++ {
+		if i < len(t) && sameResourceSet(t[begin], t[i]) {
+			continue
+		}
+		copy(t[begin:i], t[begin:i].stacked())
+		begin = i
+	}
+}
+
+func sameResourceSet(r1, r2 Driver) bool {
+	return r1.ID().DriverGroup() == r2.ID().DriverGroup() && r1.RSubset() == r2.RSubset()
+}
+
+// stacked returns the drivers ordered bottom-up along the device stack.
+func (t Drivers) stacked() Drivers {
+	bases := make(map[string][]string)
+	for _, r := range t {
+		i, ok := r.(BaseDeviceser)
+		if !ok {
+			continue
+		}
+		for _, dev := range i.BaseDevices() {
+			bases[r.RID()] = append(bases[r.RID()], dev.Path())
+		}
+	}
+	if len(bases) == 0 {
+		// avoid the exposed devices evaluation cost
+		return t
+	}
+	providers := make(map[string]string)
+	for _, r := range t {
+		i, ok := r.(ExposedDeviceser)
+		if !ok {
+			continue
+		}
+		for _, dev := range i.ExposedDevices() {
+			providers[dev.Path()] = r.RID()
+		}
+	}
+	deps := make(map[string][]string)
+	for rid, paths := range bases {
+		for _, p := range paths {
+			if provider, ok := providers[p]; ok && provider != rid {
+				deps[rid] = append(deps[rid], provider)
+			}
+		}
+	}
+	l := make(Drivers, 0, len(t))
+	placed := make(map[string]bool)
+	isReady := func(r Driver) bool {
+		for _, provider := range deps[r.RID()] {
+			if !placed[provider] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(l) < len(t) {
+		var next Driver
+		for _, r := range t {
+			if placed[r.RID()] {
+				continue
+			}
+			if next == nil {
+				// fallback to the configuration order on dependency loop
+				next = r
+			}
+			if isReady(r) {
+				next = r
+				break
+			}
+		}
+		placed[next.RID()] = true
+		l = append(l, next)
+	}
+	return l
+}
@@ -0,0 +1,68 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/util/device"
+)
+
+type stackDriver struct {
+	T
+	exposed []string
+	base    []string
+}
+
+func newStackDriver(rid string, exposed, base []string) *stackDriver {
+	r := &stackDriver{exposed: exposed, base: base}
+	r.SetRID(rid)
+	return r
+}
+
+func devices(l []string) []*device.T {
+	devs := make([]*device.T, len(l))
+	for i, p := range l {
+		devs[i] = device.New(p)
+	}
+	return devs
+}
+
+func (t stackDriver) ExposedDevices() []*device.T { return devices(t.exposed) }
+func (t stackDriver) BaseDevices() []*device.T    { return devices(t.base) }
+
+func rids(l Drivers) []string {
+	s := make([]string, len(l))
+	for i, r := range l {
+		s[i] = r.RID()
+	}
+	return s
+}
+
+func TestSortByDeviceStack(t *testing.T) {
+	newDrivers := func() Drivers {
+		return Drivers{
+			newStackDriver("disk#1", []string{"/dev/raw/raw1"}, []string{"/dev/vg1/lv1"}),
+			newStackDriver("disk#2", []string{"/dev/vg1/lv1"}, nil),
+			newStackDriver("disk#3", []string{"/dev/vg1/lv3"}, nil),
+			newStackDriver("fs#1", nil, []string{"/dev/raw/raw1"}),
+		}
+	}
+	t.Run("provider before consumer", func(t *testing.T) {
+		l := newDrivers()
+		l.Sort()
+		assert.Equal(t, []string{"disk#2", "disk#1", "disk#3", "fs#1"}, rids(l))
+	})
+	t.Run("reverse is the mirror of sort", func(t *testing.T) {
+		l := newDrivers()
+		l.Reverse()
+		assert.Equal(t, []string{"fs#1", "disk#3", "disk#1", "disk#2"}, rids(l))
+	})
+	t.Run("dependency loop keeps the configuration order", func(t *testing.T) {
+		l := Drivers{
+			newStackDriver("disk#1", []string{"/dev/a"}, []string{"/dev/b"}),
+			newStackDriver("disk#2", []string{"/dev/b"}, []string{"/dev/a"}),
+		}
+		l.Sort()
+		assert.Equal(t, []string{"disk#1", "disk#2"}, rids(l))
+	})
+}
@@ -90,17 +90,22 @@ func (t Drivers) Union(other Drivers) Drivers {
 }
 
 //
-// Sort sorts the driver list.
+// Sort sorts the driver list, ordering the drivers of a resourceset
+// bottom-up along their device stack.
 //
 func (t Drivers) Sort() {
 	sort.Sort(t)
+	t.sortByDeviceStack()
 }
 
 //
 // Reverse reverses the driver list sort.
 //
 func (t Drivers) Reverse() {
-	sort.Sort(sort.Reverse(t))
+	t.Sort()
+	for i, j := 0, len(t)-1; i < j; i, j = i+1, j-1 {
+		t[i], t[j] = t[j], t[i]
+	}
 }
 
 //
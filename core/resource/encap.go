@@ -0,0 +1,19 @@
+package resource
+
+import (
+	"context"
+	"io"
+)
+
+type (
+	//
+	// Encaper is implemented by the container drivers able to execute
+	// the commands of the agent installed in the container, to drive the
+	// encap resources.
+	//
+	Encaper interface {
+		// EncapCmd executes args in the container, with stdin fed from
+		// the reader if not nil, and returns the command stdout.
+		EncapCmd(ctx context.Context, args []string, stdin io.Reader) ([]byte, error)
+	}
+)
@@ -0,0 +1,15 @@
+package resource
+
+type (
+	// Enterer is implemented by the container drivers able to execute an
+	// interactive shell inside the container, zone or vm.
+	Enterer interface {
+		Enter() error
+	}
+
+	// Consoler is implemented by the container drivers able to attach the
+	// container serial console, like kvm and lxc.
+	Consoler interface {
+		Console() error
+	}
+)
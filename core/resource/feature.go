@@ -0,0 +1,92 @@
+package resource
+
+import (
+	"sort"
+	"sync"
+)
+
+type (
+	// Feature names an optional interface of the drivers, like the
+	// support of the run or snapshot actions.
+	Feature string
+
+	// Provisioner is implemented by the drivers having a provisioning
+	// step executed on the provisioning leader node, like an ipam
+	// allocation or a device creation.
+	Provisioner = ProvisionLeaderer
+)
+
+const (
+	FeatureProvision Feature = "provision"
+	FeatureAbort     Feature = "abort"
+	FeatureBoot      Feature = "boot"
+	FeatureSnap      Feature = "snap"
+	FeatureEnter     Feature = "enter"
+	FeatureConsole   Feature = "console"
+	FeatureRun       Feature = "run"
+	FeatureSchedule  Feature = "schedule"
+	FeatureSignal    Feature = "signal"
+)
+
+var (
+	featuresMu sync.RWMutex
+
+	// features maps the features to the function testing if a driver
+	// implements their optional interface.
+	features = map[Feature]func(Driver) bool{
+		FeatureProvision: func(r Driver) bool { _, ok := r.(Provisioner); return ok },
+		FeatureAbort:     func(r Driver) bool { _, ok := r.(Aborter); return ok },
+		FeatureBoot:      func(r Driver) bool { _, ok := r.(Booter); return ok },
+		FeatureSnap:      func(r Driver) bool { _, ok := r.(Snapshotter); return ok },
+		FeatureEnter:     func(r Driver) bool { _, ok := r.(Enterer); return ok },
+		FeatureConsole:   func(r Driver) bool { _, ok := r.(Consoler); return ok },
+		FeatureRun:       func(r Driver) bool { _, ok := r.(Runner); return ok },
+		FeatureSchedule:  func(r Driver) bool { _, ok := r.(Scheduler); return ok },
+		FeatureSignal:    func(r Driver) bool { _, ok := r.(Signaler); return ok },
+	}
+)
+
+// RegisterFeature declares a feature and the function testing if a
+// driver implements its optional interface.
+func RegisterFeature(f Feature, fn func(Driver) bool) {
+	featuresMu.Lock()
+	defer featuresMu.Unlock()
+	features[f] = fn
+}
+
+// Implements returns true if the driver implements the optional interface
+// of the feature. An unknown feature is implemented by no driver.
+func Implements(r Driver, f Feature) bool {
+	featuresMu.RLock()
+	fn, ok := features[f]
+	featuresMu.RUnlock()
+	return ok && fn(r)
+}
+
+//
+// SkipUnimplemented returns true if the driver does not implement the
+// optional interface of the feature, in which case the action is skipped
+// for this resource and the skip is logged at debug level.
+//
+func SkipUnimplemented(r Driver, f Feature, action string) bool {
+	if Implements(r, f) {
+		return false
+	}
+	r.Log().Debug().Msgf("skip %s: action not implemented by driver", action)
+	return true
+}
+
+// Features returns the sorted list of the features implemented by the
+// driver.
+func Features(r Driver) []Feature {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	l := make([]Feature, 0)
+	for f, fn := range features {
+		if fn(r) {
+			l = append(l, f)
+		}
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	return l
+}
@@ -0,0 +1,41 @@
+package resource
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatures(t *testing.T) {
+	snap := newSnapDriver("fs#1", true)
+	flag := newFlagDriver()
+
+	assert.True(t, Implements(snap, FeatureSnap))
+	assert.False(t, Implements(flag, FeatureSnap))
+	assert.False(t, Implements(snap, Feature("unknown")))
+	assert.Equal(t, []Feature{FeatureProvision, FeatureSnap}, Features(snap))
+	assert.Equal(t, []Feature{FeatureProvision}, Features(flag))
+
+	t.Run("the unimplemented actions are skipped with a debug log", func(t *testing.T) {
+		var buff bytes.Buffer
+		flag.SetLoggerForTest(zerolog.New(&buff).Level(zerolog.DebugLevel))
+		assert.True(t, SkipUnimplemented(flag, FeatureRun, "run"))
+		assert.Contains(t, buff.String(), "skip run: action not implemented by driver")
+		assert.Contains(t, buff.String(), `"level":"debug"`)
+		assert.False(t, SkipUnimplemented(snap, FeatureSnap, "snapshot"))
+	})
+
+	t.Run("the registered features are tested", func(t *testing.T) {
+		f := Feature("flagged")
+		RegisterFeature(f, func(r Driver) bool { _, ok := r.(*flagDriver); return ok })
+		defer func() {
+			featuresMu.Lock()
+			delete(features, f)
+			featuresMu.Unlock()
+		}()
+		assert.True(t, Implements(flag, f))
+		assert.Equal(t, []Feature{f, FeatureProvision}, Features(flag))
+	})
+}
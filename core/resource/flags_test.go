@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-collections/collections/set"
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/objectactionprops"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/status"
+)
+
+type flagDriver struct {
+	T
+	started     *bool
+	provisioned *bool
+	varDir      string
+}
+
+func newFlagDriver(tags ...string) *flagDriver {
+	r := &flagDriver{started: new(bool), provisioned: new(bool)}
+	r.SetRID("fs#1")
+	r.EnableProvision = true
+	r.Tags = set.New()
+	for _, tag := range tags {
+		r.Tags.Insert(tag)
+	}
+	return r
+}
+
+func (t flagDriver) Manifest() *manifest.T {
+	return manifest.New(drivergroup.FS, "test", t)
+}
+
+func (t flagDriver) Start(ctx context.Context) error {
+	*t.started = true
+	return nil
+}
+
+func (t flagDriver) Stop(ctx context.Context) error {
+	*t.started = false
+	return nil
+}
+
+func (t flagDriver) Status(ctx context.Context) status.T {
+	return status.Up
+}
+
+func (t flagDriver) ProvisionLeader(ctx context.Context) error {
+	*t.provisioned = true
+	return nil
+}
+
+func (t flagDriver) Provisioned() (provisioned.T, error) {
+	return provisioned.Undef, nil
+}
+
+func (t flagDriver) VarDir() string {
+	return t.varDir
+}
+
+type forceOptions struct{}
+
+func (t forceOptions) IsForce() bool { return true }
+
+func TestFlags(t *testing.T) {
+	ctx := context.Background()
+	varDir, err := ioutil.TempDir("", "flags")
+	assert.NoError(t, err)
+	defer os.RemoveAll(varDir)
+	newFlagDriver := func(tags ...string) *flagDriver {
+		r := newFlagDriver(tags...)
+		r.varDir = filepath.Join(varDir, fmt.Sprint(time.Now().UnixNano()))
+		return r
+	}
+
+	t.Run("provision starts the resource", func(t *testing.T) {
+		r := newFlagDriver()
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.True(t, *r.started)
+	})
+
+	t.Run("provision is skipped if already provisioned, unless forced", func(t *testing.T) {
+		r := newFlagDriver()
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.True(t, *r.provisioned)
+		assert.Equal(t, provisioned.True, getProvisionStatus(r).State, "the provisioned state is cached")
+		assert.False(t, getProvisionStatus(r).Mtime.IsZero())
+
+		*r.provisioned = false
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.False(t, *r.provisioned)
+
+		forceCtx := actioncontext.New(ctx, forceOptions{}, objectactionprops.Provision)
+		assert.NoError(t, Provision(forceCtx, r, true))
+		assert.True(t, *r.provisioned)
+
+		assert.NoError(t, Unprovision(ctx, r, true))
+		assert.Equal(t, provisioned.False, getProvisionStatus(r).State)
+	})
+
+	t.Run("provision=false skips the provision", func(t *testing.T) {
+		r := newFlagDriver()
+		r.EnableProvision = false
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.False(t, *r.started)
+	})
+
+	t.Run("disabled resources are not actioned nor evaluated", func(t *testing.T) {
+		r := newFlagDriver()
+		r.Disable = true
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.False(t, *r.started)
+		assert.Equal(t, status.NotApplicable, Status(ctx, r))
+	})
+
+	t.Run("noaction tag implies optional and skips the actions", func(t *testing.T) {
+		r := newFlagDriver(TagNoAction)
+		assert.True(t, r.IsOptional())
+		assert.NoError(t, Provision(ctx, r, true))
+		assert.False(t, *r.started)
+		assert.Equal(t, status.Up, Status(ctx, r))
+	})
+
+	t.Run("nostatus tag forces the status to n/a", func(t *testing.T) {
+		r := newFlagDriver(TagNoStatus)
+		assert.Equal(t, status.NotApplicable, Status(ctx, r))
+	})
+
+	t.Run("standby status", func(t *testing.T) {
+		r := newFlagDriver()
+		r.Standby = true
+		assert.Equal(t, status.StandbyUp, Status(ctx, r))
+	})
+}
@@ -2,9 +2,13 @@ package resource
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"opensvc.com/opensvc/core/actioncontext"
 	"opensvc.com/opensvc/core/provisioned"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/timestamp"
@@ -45,26 +49,79 @@ func provisionedTimestamp(t Driver) timestamp.T {
 	return timestamp.New(provisionedFileModTime(t))
 }
 
+// cachedProvisioned returns the provisioned state recorded by the last
+// provision or unprovision action, or the last status evaluation.
+func cachedProvisioned(t Driver) provisioned.T {
+	b, err := ioutil.ReadFile(provisionedFile(t))
+	if err != nil {
+		return provisioned.Undef
+	}
+	return provisioned.FromString(strings.TrimSpace(string(b)))
+}
+
 //
-// getProvisionStatus returns the resource provisioned state from the on-disk cache and its
+// setCachedProvisioned records the provisioned state. The cache file is
+// rewritten only on state change, so its modification time is the last
 // state change time.
 //
+func setCachedProvisioned(t Driver, state provisioned.T) {
+	switch state {
+	case provisioned.True, provisioned.False, provisioned.Mixed:
+	default:
+		return
+	}
+	if cachedProvisioned(t) == state {
+		return
+	}
+	p := provisionedFile(t)
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		t.Log().Warn().Err(err).Msg("cache provisioned state")
+		return
+	}
+	if err := ioutil.WriteFile(p, []byte(state.String()+"\n"), 0644); err != nil {
+		t.Log().Warn().Err(err).Msg("cache provisioned state")
+	}
+}
+
+//
+// getProvisionStatus returns the resource provisioned state and its last
+// state change time. The state is evaluated by the driver, and the cached
+// state is used if the driver can not tell.
+//
 func getProvisionStatus(t Driver) ProvisionStatus {
 	var (
 		data ProvisionStatus
 	)
-	if state, err := Provisioned(t); err != nil {
+	state, err := Provisioned(t)
+	if err != nil {
 		t.StatusLog().Error("provisioned: %s", err)
-	} else {
+	}
+	switch {
+	case err != nil, state == provisioned.Undef:
+		data.State = cachedProvisioned(t)
+	default:
+		setCachedProvisioned(t, state)
 		data.State = state
 	}
 	data.Mtime = provisionedTimestamp(t)
 	return data
 }
 
+//
+// Provision provisions and starts the resource. The provisioning is
+// skipped if the resource is already provisioned, unless forced.
+//
 func Provision(ctx context.Context, t Driver, leader bool) error {
-	if err := provisionLeaderSwitch(ctx, t, leader); err != nil {
-		return err
+	if skipProvision(t, "provision") {
+		return nil
+	}
+	if !actioncontext.IsForce(ctx) && getProvisionStatus(t).State == provisioned.True {
+		t.Log().Info().Msg("skip provision: already provisioned")
+	} else {
+		if err := provisionLeaderSwitch(ctx, t, leader); err != nil {
+			return err
+		}
+		setCachedProvisioned(t, provisioned.True)
 	}
 	if err := t.Start(ctx); err != nil {
 		return err
@@ -72,6 +129,22 @@ func Provision(ctx context.Context, t Driver, leader bool) error {
 	return nil
 }
 
+//
+// skipProvision returns true if the resource must not be provisioned nor
+// unprovisioned, because it is disabled, tagged noaction or defined with
+// provision=false.
+//
+func skipProvision(t Driver, action string) bool {
+	if skipAction(t, action) {
+		return true
+	}
+	if t.IsProvisionDisabled() {
+		t.Log().Debug().Msgf("skip %s: provision=false", action)
+		return true
+	}
+	return false
+}
+
 func provisionLeaderSwitch(ctx context.Context, t Driver, leader bool) error {
 	if !t.IsStandby() && !leader && t.IsShared() {
 		return provisionLeaded(ctx, t)
@@ -80,10 +153,10 @@ func provisionLeaderSwitch(ctx context.Context, t Driver, leader bool) error {
 }
 
 func provisionLeader(ctx context.Context, t Driver) error {
-	if i, ok := t.(ProvisionLeaderer); ok {
-		return i.ProvisionLeader(ctx)
+	if SkipUnimplemented(t, FeatureProvision, "provision leader") {
+		return nil
 	}
-	return nil
+	return t.(Provisioner).ProvisionLeader(ctx)
 }
 
 func provisionLeaded(ctx context.Context, t Driver) error {
@@ -94,12 +167,16 @@ func provisionLeaded(ctx context.Context, t Driver) error {
 }
 
 func Unprovision(ctx context.Context, t Driver, leader bool) error {
+	if skipProvision(t, "unprovision") {
+		return nil
+	}
 	if err := t.Stop(ctx); err != nil {
 		return err
 	}
 	if err := unprovisionLeaderSwitch(ctx, t, leader); err != nil {
 		return err
 	}
+	setCachedProvisioned(t, provisioned.False)
 	return nil
 }
 
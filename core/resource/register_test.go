@@ -0,0 +1,26 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/drivergroup"
+)
+
+func TestRegisteredDrivers(t *testing.T) {
+	saved := drivers
+	savedCapabilities := driverCapabilities
+	defer func() {
+		drivers = saved
+		driverCapabilities = savedCapabilities
+	}()
+	drivers = make(map[DriverID]func() Driver)
+	driverCapabilities = make(map[DriverID]string)
+
+	Register(drivergroup.FS, "flag", nil, WithCapability("drivers.resource.fs.flag"))
+	Register(drivergroup.App, "simple", nil)
+	assert.Equal(t, []RegisteredDriver{
+		{ID: DriverID{Group: drivergroup.App, Name: "simple"}},
+		{ID: DriverID{Group: drivergroup.FS, Name: "flag"}, Capability: "drivers.resource.fs.flag"},
+	}, RegisteredDrivers())
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/golang-collections/collections/set"
@@ -14,6 +16,7 @@ import (
 	"github.com/rs/zerolog"
 	"opensvc.com/opensvc/core/actioncontext"
 	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/driverstats"
 	"opensvc.com/opensvc/core/manifest"
 	"opensvc.com/opensvc/core/provisioned"
 	"opensvc.com/opensvc/core/resourceid"
@@ -23,6 +26,7 @@ import (
 	"opensvc.com/opensvc/core/statusbus"
 	"opensvc.com/opensvc/core/trigger"
 	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
 	"opensvc.com/opensvc/util/timestamp"
 )
 
@@ -62,6 +66,10 @@ type (
 		IsStandby() bool
 		IsShared() bool
 		IsMonitored() bool
+		IsEncap() bool
+		IsProvisionDisabled() bool
+		IsActionDisabled() bool
+		IsStatusDisabled() bool
 		MatchRID(string) bool
 		MatchSubset(string) bool
 		MatchTag(string) bool
@@ -76,8 +84,29 @@ type (
 		Requires(string) *resourcereqs.T
 	}
 
+	// Aborter is implemented by drivers able to veto a start, before
+	// any resource of the object is started. A veto is an ErrAbortStart
+	// error, whose class tells if the --force option bypasses it.
 	Aborter interface {
-		Abort(ctx context.Context) bool
+		Abort(ctx context.Context) error
+	}
+
+	// Booter is implemented by drivers needing to clean up the leftovers
+	// of a previous node boot, like stale flags or processes, before the
+	// standby resources are started.
+	Booter interface {
+		Boot(ctx context.Context) error
+	}
+
+	// Signaler is implemented by drivers whose processes can be sent a
+	// signal, for example to reload a configuration file installed in a
+	// volume.
+	Signaler interface {
+		Signal(sig syscall.Signal) error
+	}
+
+	actionTimeouter interface {
+		ActionTimeout(action string) *time.Duration
 	}
 
 	// T is the resource type, embedded in each drivers type
@@ -90,6 +119,8 @@ type (
 		Optional            bool          `json:"optional"`
 		Standby             bool          `json:"standby"`
 		Shared              bool          `json:"shared"`
+		Encap               bool          `json:"encap"`
+		EnableProvision     bool          `json:"enable_provision"`
 		Tags                *set.Set      `json:"tags"`
 		BlockingPreStart    string
 		BlockingPreStop     string
@@ -105,6 +136,8 @@ type (
 		UnprovisionRequires string
 		SyncRequires        string
 		RunRequires         string
+		StartTimeout        *time.Duration
+		StopTimeout         *time.Duration
 
 		statusLog StatusLog
 		log       zerolog.Logger
@@ -187,6 +220,14 @@ const (
 	Post
 )
 
+const (
+	// TagNoAction is the tag disabling the state changing actions of a resource.
+	TagNoAction = "noaction"
+
+	// TagNoStatus is the tag forcing the status of a resource to n/a.
+	TagNoStatus = "nostatus"
+)
+
 // FlagString returns a one character representation of the type instance.
 func (t MonitorFlag) FlagString() string {
 	if t {
@@ -264,7 +305,29 @@ func NewDriverID(group drivergroup.T, name string) *DriverID {
 	}
 }
 
-var drivers = make(map[DriverID]func() Driver)
+type (
+	// RegisteredDriver describes a driver registered by Register.
+	RegisteredDriver struct {
+		ID DriverID
+
+		// Capability is the node capability required to use the driver,
+		// or the empty string if the driver is usable on any node.
+		Capability string
+	}
+
+	registration struct {
+		capability string
+	}
+)
+
+var (
+	drivers            = make(map[DriverID]func() Driver)
+	driverCapabilities = make(map[DriverID]string)
+
+	// ErrActionTimeout is returned when a resource action exceeds the
+	// duration set by its <action>_timeout keyword.
+	ErrActionTimeout = errors.New("action timeout")
+)
 
 func RegisteredGroupDrivers(s string) map[DriverID]func() Driver {
 	m := make(map[DriverID]func() Driver)
@@ -277,9 +340,46 @@ func RegisteredGroupDrivers(s string) map[DriverID]func() Driver {
 	return m
 }
 
-func Register(group drivergroup.T, name string, f func() Driver) {
+// Register adds the driver allocator f to the drivers registry, under
+// the <group>.<name> driver id.
+func Register(group drivergroup.T, name string, f func() Driver, opts ...funcopt.O) {
+	var reg registration
+	_ = funcopt.Apply(&reg, opts...)
 	driverID := NewDriverID(group, name)
 	drivers[*driverID] = f
+	if reg.capability != "" {
+		driverCapabilities[*driverID] = reg.capability
+	}
+}
+
+//
+// WithCapability declares the node capability the registered driver
+// requires. This capability is expected to be reported by a scanner
+// registered in the capabilities package, so the drivers usually
+// declare it once, as their package capability constant, returned by
+// their scanner and passed to this option.
+//
+func WithCapability(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*registration)
+		t.capability = s
+		return nil
+	})
+}
+
+// RegisteredDrivers returns the registered drivers, sorted by driver id.
+func RegisteredDrivers() []RegisteredDriver {
+	l := make([]RegisteredDriver, 0, len(drivers))
+	for drvID := range drivers {
+		l = append(l, RegisteredDriver{
+			ID:         drvID,
+			Capability: driverCapabilities[drvID],
+		})
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].ID.String() < l[j].ID.String()
+	})
+	return l
 }
 
 func (t DriverID) NewResourceFunc() func() Driver {
@@ -297,11 +397,12 @@ func (t DriverID) NewResourceFunc() func() Driver {
 }
 
 //
-// IsOptional returns true if the resource definition contains optional=true.
+// IsOptional returns true if the resource definition contains optional=true,
+// or the noaction tag.
 // An optional resource does not break an object action on error.
 //
 func (t T) IsOptional() bool {
-	return t.Optional
+	return t.Optional || t.IsActionDisabled()
 }
 
 // IsDisabled returns true if the resource definition container disable=true.
@@ -324,6 +425,32 @@ func (t T) IsMonitored() bool {
 	return t.Monitor
 }
 
+// IsEncap returns true if the resource definition contains encap=true.
+// An encap resource is handled by the agent of the encapsulated nodes,
+// and ignored by the agent of the hypervisor nodes.
+func (t T) IsEncap() bool {
+	return t.Encap
+}
+
+// IsProvisionDisabled returns true if the resource definition contains
+// provision=false. Such a resource is skipped on provision and
+// unprovision actions.
+func (t T) IsProvisionDisabled() bool {
+	return !t.EnableProvision
+}
+
+// IsActionDisabled returns true if the resource is tagged noaction.
+// Such a resource is skipped on state changing actions.
+func (t T) IsActionDisabled() bool {
+	return t.MatchTag(TagNoAction)
+}
+
+// IsStatusDisabled returns true if the resource is tagged nostatus.
+// The status of such a resource is always n/a.
+func (t T) IsStatusDisabled() bool {
+	return t.MatchTag(TagNoStatus)
+}
+
 // RSubset returns the resource subset name
 func (t T) RSubset() string {
 	return t.Subset
@@ -490,7 +617,7 @@ func (t T) Requires(action string) *resourcereqs.T {
 		reqs = t.UnprovisionRequires
 	case "run":
 		reqs = t.RunRequires
-	case "sync":
+	case "sync", "sync_full", "sync_update", "sync_resync":
 		reqs = t.SyncRequires
 	}
 	return resourcereqs.New(reqs)
@@ -545,9 +672,75 @@ func updateStatusBus(ctx context.Context, r Driver) {
 	sb.Post(r.RID(), Status(ctx, r), false)
 }
 
+// observe records the duration of the driver action in the driver stats.
+func observe(r Driver, action string, fn func() error) error {
+	begin := time.Now()
+	err := fn()
+	driverstats.Observe(formatResourceType(r), action, time.Since(begin))
+	return err
+}
+
+//
+// withTimeout executes the driver action with a context cancelled at
+// the deadline set by the resource <action>_timeout keyword. The drivers
+// are expected to abort their commands when the context is done.
+//
+func withTimeout(ctx context.Context, r Driver, action string, fn func(context.Context) error) error {
+	i, ok := r.(actionTimeouter)
+	if !ok {
+		return fn(ctx)
+	}
+	timeout := i.ActionTimeout(action)
+	if timeout == nil || *timeout <= 0 {
+		return fn(ctx)
+	}
+	actionCtx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	err := fn(actionCtx)
+	if ctx.Err() == nil && errors.Is(actionCtx.Err(), context.DeadlineExceeded) {
+		if err == nil {
+			err = actionCtx.Err()
+		}
+		return errors.Wrapf(ErrActionTimeout, "%s %s: %s", action, *timeout, err)
+	}
+	return err
+}
+
+// ActionTimeout returns the duration set by the <action>_timeout
+// keyword, or nil if not set.
+func (t T) ActionTimeout(action string) *time.Duration {
+	switch action {
+	case "start":
+		return t.StartTimeout
+	case "stop":
+		return t.StopTimeout
+	default:
+		return nil
+	}
+}
+
+//
+// skipAction returns true if the resource must not be actioned, because it
+// is disabled or tagged noaction.
+//
+func skipAction(r Driver, action string) bool {
+	switch {
+	case r.IsDisabled():
+		r.Log().Debug().Msgf("skip %s: disabled", action)
+		return true
+	case r.IsActionDisabled():
+		r.Log().Debug().Msgf("skip %s: tagged %s", action, TagNoAction)
+		return true
+	}
+	return false
+}
+
 // Start activates a resource interfacer
 func Start(ctx context.Context, r Driver) error {
 	defer updateStatusBus(ctx, r)
+	if skipAction(r, "start") {
+		return nil
+	}
 	Setenv(r)
 	if err := checkRequires(ctx, r); err != nil {
 		return errors.Wrapf(err, "requires")
@@ -558,7 +751,7 @@ func Start(ctx context.Context, r Driver) error {
 	if err := r.Trigger(trigger.NoBlock, trigger.Pre, trigger.Start); err != nil {
 		r.Log().Warn().Int("exitcode", exitCode(err)).Msgf("trigger: %s", err)
 	}
-	if err := r.Start(ctx); err != nil {
+	if err := observe(r, "start", func() error { return withTimeout(ctx, r, "start", r.Start) }); err != nil {
 		return err
 	}
 	if err := r.Trigger(trigger.Block, trigger.Post, trigger.Start); err != nil {
@@ -573,6 +766,9 @@ func Start(ctx context.Context, r Driver) error {
 // Stop deactivates a resource interfacer
 func Stop(ctx context.Context, r Driver) error {
 	defer updateStatusBus(ctx, r)
+	if skipAction(r, "stop") {
+		return nil
+	}
 	Setenv(r)
 	if err := checkRequires(ctx, r); err != nil {
 		return errors.Wrapf(err, "requires")
@@ -583,7 +779,7 @@ func Stop(ctx context.Context, r Driver) error {
 	if err := r.Trigger(trigger.NoBlock, trigger.Pre, trigger.Stop); err != nil {
 		r.Log().Warn().Int("exitcode", exitCode(err)).Msgf("trigger: %s", err)
 	}
-	if err := r.Stop(ctx); err != nil {
+	if err := observe(r, "stop", func() error { return withTimeout(ctx, r, "stop", r.Stop) }); err != nil {
 		return err
 	}
 	if err := r.Trigger(trigger.Block, trigger.Post, trigger.Stop); err != nil {
@@ -597,8 +793,15 @@ func Stop(ctx context.Context, r Driver) error {
 
 // Status evaluates the status of a resource interfacer
 func Status(ctx context.Context, r Driver) status.T {
+	if r.IsDisabled() || r.IsStatusDisabled() {
+		return status.NotApplicable
+	}
 	Setenv(r)
-	s := r.Status(ctx)
+	var s status.T
+	_ = observe(r, "status", func() error {
+		s = r.Status(ctx)
+		return nil
+	})
 	if !r.IsStandby() {
 		return s
 	}
@@ -628,7 +831,8 @@ func GetExposedStatus(ctx context.Context, r Driver) ExposedStatus {
 		Optional:    OptionalFlag(r.IsOptional()),
 		Standby:     StandbyFlag(r.IsStandby()),
 		Disable:     DisableFlag(r.IsDisabled()),
-		//Encap:       EncapFlag(r.IsEncap()),
+		Encap:       EncapFlag(r.IsEncap()),
+		Monitor:     MonitorFlag(r.IsMonitored()),
 	}
 }
 
@@ -684,8 +888,8 @@ func exposedStatusInfo(t Driver) (data map[string]interface{}) {
 	} else {
 		data = make(map[string]interface{})
 	}
-	if i, ok := t.(Scheduler); ok {
-		data["sched"] = exposedStatusInfoSched(i)
+	if Implements(t, FeatureSchedule) {
+		data["sched"] = exposedStatusInfoSched(t.(Scheduler))
 	}
 	return data
 }
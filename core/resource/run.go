@@ -0,0 +1,140 @@
+package resource
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/trigger"
+)
+
+type (
+	// Runner is implemented by the drivers supporting the run action,
+	// like the tasks.
+	Runner interface {
+		Run(context.Context) error
+	}
+
+	// confirmationer is implemented by the drivers able to refuse a run
+	// not confirmed by the operator.
+	confirmationer interface {
+		NeedsConfirmation() bool
+	}
+
+	// RunLog is a run log file of a resource.
+	RunLog struct {
+		RID     string    `json:"rid"`
+		Time    time.Time `json:"time"`
+		File    string    `json:"file"`
+		Content string    `json:"content,omitempty"`
+	}
+)
+
+var (
+	// RunLogMax is the number of run log files kept per resource.
+	RunLogMax = 10
+
+	// ErrConfirmationRequired is returned by Run when the task is
+	// configured with confirmation=true and the --confirm flag is not set.
+	ErrConfirmationRequired = errors.New("confirmation required")
+)
+
+// runLogTimeFormat is the timestamp format of the run log file names.
+const runLogTimeFormat = "2006-01-02T15-04-05.000000"
+
+func runLogDir(r Driver) string {
+	return filepath.Join(r.VarDir(), "run")
+}
+
+//
+// OpenRunLog creates a new run log file for the resource, and removes
+// the oldest ones so at most RunLogMax files are kept. The caller is
+// responsible for closing the returned file.
+//
+func OpenRunLog(r Driver) (*os.File, error) {
+	dir := runLogDir(r)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	name := time.Now().Format(runLogTimeFormat) + ".log"
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if l, err := RunLogs(r); err == nil && len(l) > RunLogMax {
+		for _, e := range l[:len(l)-RunLogMax] {
+			_ = os.Remove(e.File)
+		}
+	}
+	return f, nil
+}
+
+// RunLogs returns the run logs of the resource, oldest first, without
+// their content.
+func RunLogs(r Driver) ([]RunLog, error) {
+	l := make([]RunLog, 0)
+	matches, err := filepath.Glob(filepath.Join(runLogDir(r), "*.log"))
+	if err != nil {
+		return l, err
+	}
+	sort.Strings(matches)
+	for _, p := range matches {
+		ts, err := time.ParseInLocation(runLogTimeFormat, strings.TrimSuffix(filepath.Base(p), ".log"), time.Local)
+		if err != nil {
+			continue
+		}
+		l = append(l, RunLog{RID: r.RID(), Time: ts, File: p})
+	}
+	return l, nil
+}
+
+// Load sets the run log content from its file.
+func (t *RunLog) Load() error {
+	b, err := ioutil.ReadFile(t.File)
+	if err != nil {
+		return err
+	}
+	t.Content = string(b)
+	return nil
+}
+
+// Run executes a task resource. The drivers not implementing Runner
+// are ignored.
+func Run(ctx context.Context, r Driver) error {
+	if SkipUnimplemented(r, FeatureRun, "run") {
+		return nil
+	}
+	i := r.(Runner)
+	if skipAction(r, "run") {
+		return nil
+	}
+	if c, ok := r.(confirmationer); ok && c.NeedsConfirmation() && !actioncontext.IsConfirm(ctx) {
+		return errors.Wrap(ErrConfirmationRequired, "the task is configured with confirmation=true, set --confirm to run it")
+	}
+	Setenv(r)
+	if err := checkRequires(ctx, r); err != nil {
+		return errors.Wrapf(err, "requires")
+	}
+	if err := r.Trigger(trigger.Block, trigger.Pre, trigger.Run); err != nil {
+		return errors.Wrapf(err, "trigger")
+	}
+	if err := r.Trigger(trigger.NoBlock, trigger.Pre, trigger.Run); err != nil {
+		r.Log().Warn().Int("exitcode", exitCode(err)).Msgf("trigger: %s", err)
+	}
+	if err := observe(r, "run", func() error { return withTimeout(ctx, r, "run", i.Run) }); err != nil {
+		return err
+	}
+	if err := r.Trigger(trigger.Block, trigger.Post, trigger.Run); err != nil {
+		return errors.Wrapf(err, "trigger")
+	}
+	if err := r.Trigger(trigger.NoBlock, trigger.Post, trigger.Run); err != nil {
+		r.Log().Warn().Int("exitcode", exitCode(err)).Msgf("trigger: %s", err)
+	}
+	return nil
+}
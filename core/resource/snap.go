@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	//
+	// Snapshotter is implemented by the drivers able to take named
+	// snapshots of their data, like the fs drivers on snapshot-capable
+	// filesystems or logical volumes. The sync drivers use the snapshots
+	// to replicate a crash-consistent image of the data.
+	//
+	Snapshotter interface {
+		SnapCreate(ctx context.Context, name string) error
+		SnapRollback(ctx context.Context, name string) error
+		SnapRemove(ctx context.Context, name string) error
+		Snaps(ctx context.Context) ([]Snap, error)
+	}
+
+	// Snap is a named snapshot of a resource data.
+	Snap struct {
+		RID     string    `json:"rid"`
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+)
+
+var (
+	// ErrSnapNotSupported is returned by the Snapshotter drivers whose
+	// configuration does not support snapshots, like a fs driver on a
+	// filesystem and a device without snapshot capability.
+	ErrSnapNotSupported = errors.New("snapshots not supported")
+
+	snapNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+)
+
+// ValidateSnapName returns an error if the snapshot name contains
+// characters not supported by all the snapshot backends.
+func ValidateSnapName(name string) error {
+	if !snapNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name '%s': allowed characters are a-z, A-Z, 0-9, '_', '.' and '-'", name)
+	}
+	return nil
+}
+
+//
+// WithSnaps creates the <name> snapshot of the Snapshotter resources of
+// the list, calls fn, then removes the snapshots. The snapshots created
+// before a failure are removed too. The resources not supporting
+// snapshots in their configuration are ignored.
+//
+func WithSnaps(ctx context.Context, l Drivers, name string, fn func() error) error {
+	if err := ValidateSnapName(name); err != nil {
+		return err
+	}
+	created := make([]Driver, 0)
+	defer func() {
+		for _, r := range created {
+			if err := r.(Snapshotter).SnapRemove(ctx, name); err != nil {
+				r.Log().Warn().Err(err).Msgf("remove snapshot %s", name)
+			}
+		}
+	}()
+	for _, r := range l {
+		if SkipUnimplemented(r, FeatureSnap, "snapshot") {
+			continue
+		}
+		err := r.(Snapshotter).SnapCreate(ctx, name)
+		switch {
+		case errors.Is(err, ErrSnapNotSupported):
+			r.Log().Debug().Err(err).Msg("skip snapshot")
+			continue
+		case err != nil:
+			return errors.Wrapf(err, "%s: create snapshot %s", r.RID(), name)
+		}
+		created = append(created, r)
+	}
+	return fn()
+}
@@ -0,0 +1,87 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapDriver struct {
+	*flagDriver
+	snaps     map[string]interface{}
+	supported bool
+	failure   error
+}
+
+func newSnapDriver(rid string, supported bool) *snapDriver {
+	r := &snapDriver{flagDriver: newFlagDriver(), snaps: make(map[string]interface{}), supported: supported}
+	r.SetRID(rid)
+	return r
+}
+
+func (t *snapDriver) SnapCreate(ctx context.Context, name string) error {
+	if !t.supported {
+		return ErrSnapNotSupported
+	}
+	if t.failure != nil {
+		return t.failure
+	}
+	t.snaps[name] = nil
+	return nil
+}
+
+func (t *snapDriver) SnapRollback(ctx context.Context, name string) error {
+	return nil
+}
+
+func (t *snapDriver) SnapRemove(ctx context.Context, name string) error {
+	delete(t.snaps, name)
+	return nil
+}
+
+func (t *snapDriver) Snaps(ctx context.Context) ([]Snap, error) {
+	l := make([]Snap, 0)
+	for name := range t.snaps {
+		l = append(l, Snap{RID: t.RID(), Name: name})
+	}
+	return l, nil
+}
+
+func TestWithSnaps(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("the snapshots exist during the replication and are removed after", func(t *testing.T) {
+		r1 := newSnapDriver("fs#1", true)
+		r2 := newSnapDriver("fs#2", false)
+		err := WithSnaps(ctx, Drivers{r1, r2, newFlagDriver()}, "sync", func() error {
+			assert.Contains(t, r1.snaps, "sync")
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, r1.snaps, 0)
+	})
+
+	t.Run("the created snapshots are removed on failure", func(t *testing.T) {
+		r1 := newSnapDriver("fs#1", true)
+		r2 := newSnapDriver("fs#2", true)
+		r2.failure = errors.New("no space left")
+		called := false
+		err := WithSnaps(ctx, Drivers{r1, r2}, "sync", func() error {
+			called = true
+			return nil
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "fs#2: create snapshot sync")
+		assert.False(t, called)
+		assert.Len(t, r1.snaps, 0)
+	})
+
+	t.Run("refuses invalid names", func(t *testing.T) {
+		assert.NoError(t, ValidateSnapName("daily.2021-01-01_1"))
+		assert.Error(t, ValidateSnapName("a/b"))
+		assert.Error(t, ValidateSnapName("-a"))
+		assert.Error(t, ValidateSnapName(""))
+	})
+}
@@ -0,0 +1,68 @@
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// SyncFuller is implemented by the sync drivers able to do a full
+	// copy of the data to their targets.
+	SyncFuller interface {
+		SyncFull(context.Context) error
+	}
+
+	// SyncUpdater is implemented by the sync drivers able to do an
+	// incremental copy of the data to their targets.
+	SyncUpdater interface {
+		SyncUpdate(context.Context) error
+	}
+
+	// SyncResyncer is implemented by the sync drivers able to
+	// reestablish a broken replication.
+	SyncResyncer interface {
+		SyncResync(context.Context) error
+	}
+)
+
+// SyncFull executes a full copy of the resource data to its targets.
+// The drivers not implementing SyncFuller are ignored.
+func SyncFull(ctx context.Context, r Driver) error {
+	i, ok := r.(SyncFuller)
+	if !ok {
+		return nil
+	}
+	return doSync(ctx, r, "sync_full", i.SyncFull)
+}
+
+// SyncUpdate executes an incremental copy of the resource data to its
+// targets. The drivers not implementing SyncUpdater are ignored.
+func SyncUpdate(ctx context.Context, r Driver) error {
+	i, ok := r.(SyncUpdater)
+	if !ok {
+		return nil
+	}
+	return doSync(ctx, r, "sync_update", i.SyncUpdate)
+}
+
+// SyncResync reestablishes the resource replication. The drivers not
+// implementing SyncResyncer are ignored.
+func SyncResync(ctx context.Context, r Driver) error {
+	i, ok := r.(SyncResyncer)
+	if !ok {
+		return nil
+	}
+	return doSync(ctx, r, "sync_resync", i.SyncResync)
+}
+
+func doSync(ctx context.Context, r Driver, action string, fn func(context.Context) error) error {
+	if skipAction(r, action) {
+		return nil
+	}
+	Setenv(r)
+	if err := checkRequires(ctx, r); err != nil {
+		return errors.Wrapf(err, "requires")
+	}
+	return observe(r, action, func() error { return withTimeout(ctx, r, action, fn) })
+}
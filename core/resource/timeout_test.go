@@ -0,0 +1,45 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	timeout := 50 * time.Millisecond
+	r := &T{StartTimeout: &timeout}
+	wait := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	}
+
+	t.Run("the action context is cancelled at the deadline", func(t *testing.T) {
+		err := withTimeout(context.Background(), r, "start", wait)
+		assert.True(t, errors.Is(err, ErrActionTimeout), "%s", err)
+	})
+
+	t.Run("no timeout for the actions without keyword", func(t *testing.T) {
+		err := withTimeout(context.Background(), r, "stop", func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("the parent cancellation is not a resource timeout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := withTimeout(ctx, r, "start", wait)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.False(t, errors.Is(err, ErrActionTimeout))
+	})
+}
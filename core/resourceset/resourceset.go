@@ -167,18 +167,8 @@ func (t T) doParallel(ctx context.Context, l ResourceLister, resources resource.
 	q := make(chan result, len(resources))
 	defer close(q)
 	do := func(q chan<- result, r resource.Driver) {
-		var err error
-		c := make(chan error, 1)
-		if err = l.ReconfigureResource(r); err == nil {
-			c <- fn(ctx, r)
-		}
-		select {
-		case <-ctx.Done():
-			err = fmt.Errorf("timeout")
-		case err = <-c:
-		}
 		q <- result{
-			Error:    err,
+			Error:    doOne(ctx, l, r, fn),
 			Resource: r,
 		}
 	}
@@ -187,37 +177,50 @@ func (t T) doParallel(ctx context.Context, l ResourceLister, resources resource.
 	}
 	for i := 0; i < len(resources); i++ {
 		res := <-q
-		if res.Resource.IsOptional() {
-			continue
+		if e := resourceError(res.Resource, res.Error); e != nil {
+			err = e
 		}
-		err = res.Error
 	}
 	return err
 }
 
 func (t T) doSerial(ctx context.Context, l ResourceLister, resources resource.Drivers, fn DoFunc) error {
 	for _, r := range resources {
-		var err error
-		c := make(chan error, 1)
-		if err = l.ReconfigureResource(r); err == nil {
-			c <- fn(ctx, r)
-		}
-		select {
-		case <-ctx.Done():
-			err = fmt.Errorf("timeout")
-		case err = <-c:
-		}
-		if err == nil {
-			continue
-		}
-		if r.IsOptional() {
-			continue
+		if err := resourceError(r, doOne(ctx, l, r, fn)); err != nil {
+			return err
 		}
-		return err
 	}
 	return nil
 }
 
+// doOne executes the action function on the resource, unless the object
+// action context is already done.
+func doOne(ctx context.Context, l ResourceLister, r resource.Driver, fn DoFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := l.ReconfigureResource(r); err != nil {
+		return err
+	}
+	return fn(ctx, r)
+}
+
+//
+// resourceError returns the action error attributed to the resource. The
+// optional resources action errors are logged as warnings and do not
+// break the action.
+//
+func resourceError(r resource.Driver, err error) error {
+	if err == nil {
+		return nil
+	}
+	if r.IsOptional() {
+		r.Log().Warn().Err(err).Msg("optional resource action failed")
+		return nil
+	}
+	return errors.Wrapf(err, "%s", r.RID())
+}
+
 func (t L) Reverse() {
 	sort.Sort(sort.Reverse(t))
 }
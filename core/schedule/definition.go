@@ -0,0 +1,332 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Expr is a parsed schedule definition.
+	//
+	// Definition syntax:
+	//
+	//	[~]<timeranges>[@<interval>] [<days> [<weeks> [<months>]]]
+	//
+	// Examples:
+	//
+	//	~00:00-06:00           once a night, at a random time in the window
+	//	@10                    every 10 minutes
+	//	08:00-20:00@1h mon-fri every hour in the business hours
+	//	02:00 sat,sun * jan-mar
+	//
+	// An empty definition or a @0 interval disables the task.
+	Expr struct {
+		Ranges   []TimeRange
+		Interval time.Duration
+		Random   bool
+		Disabled bool
+		days     map[time.Weekday]interface{}
+		weeks    map[int]interface{}
+		months   map[time.Month]interface{}
+	}
+
+	// TimeRange is a daily time window, expressed in durations since
+	// midnight. End can be greater than 24h for windows spanning midnight.
+	TimeRange struct {
+		Start time.Duration
+		End   time.Duration
+	}
+)
+
+var (
+	// ErrUnsupported is returned when parsing a definition using a
+	// syntax element this implementation can not evaluate, like the
+	// nth day of month qualifiers.
+	ErrUnsupported = errors.New("unsupported schedule definition")
+
+	dayNames = map[string]time.Weekday{
+		"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday,
+		"wed": time.Wednesday, "thu": time.Thursday, "fri": time.Friday,
+		"sat": time.Saturday,
+	}
+	monthNames = map[string]time.Month{
+		"jan": time.January, "feb": time.February, "mar": time.March,
+		"apr": time.April, "may": time.May, "jun": time.June,
+		"jul": time.July, "aug": time.August, "sep": time.September,
+		"oct": time.October, "nov": time.November, "dec": time.December,
+	}
+
+	// maxLookAheadDays bounds the next run search.
+	maxLookAheadDays = 366 * 2
+)
+
+// Parse returns the schedule expression parsed from its definition.
+func Parse(s string) (Expr, error) {
+	t := Expr{}
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		t.Disabled = true
+		return t, nil
+	}
+	if len(fields) > 4 {
+		return t, fmt.Errorf("schedule %s: too many fields", s)
+	}
+	if err := t.parseTimes(fields[0]); err != nil {
+		return t, errors.Wrapf(err, "schedule %s", s)
+	}
+	var err error
+	if len(fields) > 1 {
+		if t.days, err = parseDays(fields[1]); err != nil {
+			return t, errors.Wrapf(err, "schedule %s", s)
+		}
+	}
+	if len(fields) > 2 {
+		if t.weeks, err = parseWeeks(fields[2]); err != nil {
+			return t, errors.Wrapf(err, "schedule %s", s)
+		}
+	}
+	if len(fields) > 3 {
+		if t.months, err = parseMonths(fields[3]); err != nil {
+			return t, errors.Wrapf(err, "schedule %s", s)
+		}
+	}
+	return t, nil
+}
+
+func (t *Expr) parseTimes(s string) error {
+	if strings.HasPrefix(s, "~") {
+		t.Random = true
+		s = s[1:]
+	}
+	l := strings.SplitN(s, "@", 2)
+	if len(l) == 2 {
+		d, err := parseInterval(l[1])
+		if err != nil {
+			return err
+		}
+		if d == 0 {
+			t.Disabled = true
+			return nil
+		}
+		t.Interval = d
+	}
+	if l[0] == "" || l[0] == "*" {
+		t.Ranges = []TimeRange{{Start: 0, End: 24 * time.Hour}}
+		return nil
+	}
+	for _, e := range strings.Split(l[0], ",") {
+		r, err := parseTimeRange(e)
+		if err != nil {
+			return err
+		}
+		t.Ranges = append(t.Ranges, r)
+	}
+	return nil
+}
+
+func parseInterval(s string) (time.Duration, error) {
+	if i, err := strconv.Atoi(s); err == nil {
+		return time.Duration(i) * time.Minute, nil
+	}
+	if strings.HasSuffix(s, "d") {
+		i, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %s", s)
+		}
+		return time.Duration(i) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %s", s)
+	}
+	return d, nil
+}
+
+func parseTimeRange(s string) (TimeRange, error) {
+	l := strings.SplitN(s, "-", 2)
+	start, err := parseClock(l[0])
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if len(l) == 1 {
+		// a single time is a one minute window
+		return TimeRange{Start: start, End: start + time.Minute}, nil
+	}
+	end, err := parseClock(l[1])
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	return TimeRange{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	l := strings.Split(s, ":")
+	if len(l) != 2 {
+		return 0, fmt.Errorf("invalid time %s: expected hh:mm", s)
+	}
+	h, err := strconv.Atoi(l[0])
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid time %s: invalid hour", s)
+	}
+	m, err := strconv.Atoi(l[1])
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid time %s: invalid minute", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseList expands a comma-separated list of values and ranges, using
+// the conv function to convert each value to an integer.
+func parseList(s string, conv func(string) (int, error), min, max int) (map[int]interface{}, error) {
+	if s == "*" {
+		return nil, nil
+	}
+	m := make(map[int]interface{})
+	for _, e := range strings.Split(s, ",") {
+		if strings.ContainsAny(e, ":%") {
+			return nil, errors.Wrapf(ErrUnsupported, "%s", e)
+		}
+		l := strings.SplitN(e, "-", 2)
+		first, err := conv(l[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(l) == 2 {
+			if last, err = conv(l[1]); err != nil {
+				return nil, err
+			}
+		}
+		if first < min || last > max {
+			return nil, fmt.Errorf("invalid value %s: out of [%d-%d] range", e, min, max)
+		}
+		if last < first {
+			// wrapping range, like sat-mon
+			for i := first; i <= max; i++ {
+				m[i] = nil
+			}
+			first = min
+		}
+		for i := first; i <= last; i++ {
+			m[i] = nil
+		}
+	}
+	return m, nil
+}
+
+func parseDays(s string) (map[time.Weekday]interface{}, error) {
+	conv := func(s string) (int, error) {
+		if d, ok := dayNames[s]; ok {
+			return int(d), nil
+		}
+		return 0, fmt.Errorf("invalid day %s", s)
+	}
+	l, err := parseList(s, conv, 0, 6)
+	if l == nil || err != nil {
+		return nil, err
+	}
+	m := make(map[time.Weekday]interface{})
+	for i := range l {
+		m[time.Weekday(i)] = nil
+	}
+	return m, nil
+}
+
+func parseWeeks(s string) (map[int]interface{}, error) {
+	return parseList(s, strconv.Atoi, 1, 53)
+}
+
+func parseMonths(s string) (map[time.Month]interface{}, error) {
+	conv := func(s string) (int, error) {
+		if m, ok := monthNames[s]; ok {
+			return int(m), nil
+		}
+		if i, err := strconv.Atoi(s); err == nil {
+			return i, nil
+		}
+		return 0, fmt.Errorf("invalid month %s", s)
+	}
+	l, err := parseList(s, conv, 1, 12)
+	if l == nil || err != nil {
+		return nil, err
+	}
+	m := make(map[time.Month]interface{})
+	for i := range l {
+		m[time.Month(i)] = nil
+	}
+	return m, nil
+}
+
+// dayAllowed returns true if the day filters allow the date.
+func (t Expr) dayAllowed(tm time.Time) bool {
+	if t.days != nil {
+		if _, ok := t.days[tm.Weekday()]; !ok {
+			return false
+		}
+	}
+	if t.weeks != nil {
+		_, week := tm.ISOWeek()
+		if _, ok := t.weeks[week]; !ok {
+			return false
+		}
+	}
+	if t.months != nil {
+		if _, ok := t.months[tm.Month()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Next returns the earliest time after now the task is allowed to run,
+// given its last run time. Without interval, the task runs once per
+// window. For random windows, the earliest allowed time is returned. A
+// zero time is returned for a disabled schedule.
+func (t Expr) Next(last, now time.Time) time.Time {
+	if t.Disabled || len(t.Ranges) == 0 {
+		return time.Time{}
+	}
+	y, m, d := now.Date()
+	// start the day before to honor the windows spanning midnight
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	for i := 0; i < maxLookAheadDays; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if !t.dayAllowed(day) {
+			continue
+		}
+		var best time.Time
+		for _, r := range t.Ranges {
+			start := day.Add(r.Start)
+			end := day.Add(r.End)
+			candidate := start
+			if now.After(candidate) {
+				candidate = now
+			}
+			if t.Interval > 0 {
+				if next := last.Add(t.Interval); next.After(candidate) {
+					candidate = next
+				}
+			} else if !last.Before(start) {
+				// already run in this window
+				continue
+			}
+			if candidate.After(end) {
+				continue
+			}
+			if best.IsZero() || candidate.Before(best) {
+				best = candidate
+			}
+		}
+		if !best.IsZero() {
+			return best
+		}
+	}
+	return time.Time{}
+}
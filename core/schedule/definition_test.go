@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"", "@0", "@10", "~00:00-06:00", "08:00-20:00@1h mon-fri", "02:00 sat,sun * jan-mar", "22:00-02:00", "* * 1-10"} {
+		_, err := Parse(s)
+		assert.NoError(t, err, s)
+	}
+	for _, s := range []string{"25:00", "08:00-20:00 funday", "@x", "a b c d e"} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+	_, err := Parse("02:00 mon:last")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestNext(t *testing.T) {
+	loc := time.UTC
+	// 2021-03-03 is a wednesday
+	now := time.Date(2021, 3, 3, 10, 30, 0, 0, loc)
+	at := func(d, h, m int) time.Time {
+		return time.Date(2021, 3, d, h, m, 0, 0, loc)
+	}
+	tests := []struct {
+		def  string
+		last time.Time
+		next time.Time
+	}{
+		{"", time.Time{}, time.Time{}},
+		{"@0", time.Time{}, time.Time{}},
+		{"@10", time.Time{}, now},
+		{"@10", at(3, 10, 25), at(3, 10, 35)},
+		{"00:00-06:00", at(3, 1, 0), at(4, 0, 0)},
+		{"~00:00-06:00", at(2, 1, 0), at(4, 0, 0)},
+		{"10:00-12:00", at(2, 11, 0), now},
+		{"10:00-12:00", at(3, 10, 1), at(4, 10, 0)},
+		{"08:00-20:00@1h mon-fri", at(3, 10, 0), at(3, 11, 0)},
+		{"08:00-20:00@1h sat,sun", time.Time{}, at(6, 8, 0)},
+		{"22:00-02:00", at(2, 23, 0), at(3, 22, 0)},
+		{"02:00 * * apr", time.Time{}, time.Date(2021, 4, 1, 2, 0, 0, 0, loc)},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.def)
+		assert.NoError(t, err, test.def)
+		assert.Equal(t, test.next, expr.Next(test.last, now), test.def)
+	}
+}
+
+func TestTableSetNext(t *testing.T) {
+	now := time.Date(2021, 3, 3, 10, 30, 0, 0, time.UTC)
+	table := NewTable(
+		Entry{Action: "a", Definition: "@10"},
+		Entry{Action: "b", Definition: ""},
+		Entry{Action: "c", Definition: "02:00 mon:last"},
+	).SetNext(now)
+	assert.Equal(t, now, table[0].Next.Time())
+	assert.True(t, table[1].Next.Time().IsZero())
+	assert.True(t, table[2].Next.Time().IsZero())
+}
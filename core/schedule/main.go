@@ -1,6 +1,8 @@
 package schedule
 
 import (
+	"time"
+
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/util/timestamp"
 )
@@ -48,3 +50,19 @@ func (t Table) AddEntries(l []Entry) Table {
 func (t Table) AddEntry(e Entry) Table {
 	return append(t, e)
 }
+
+// SetNext sets the next run time of the entries, computed from their
+// schedule definition and last run time. The next run time of the entries
+// with a disabled or unsupported definition is left zero.
+func (t Table) SetNext(now time.Time) Table {
+	for i, e := range t {
+		expr, err := Parse(e.Definition)
+		if err != nil {
+			continue
+		}
+		if next := expr.Next(e.Last.Time(), now); !next.IsZero() {
+			t[i].Next = timestamp.New(next)
+		}
+	}
+	return t
+}
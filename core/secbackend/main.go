@@ -0,0 +1,101 @@
+// Package secbackend resolves sec key values referencing an external
+// secret backend, like vault://secret/data/db#password.
+//
+// Backend drivers register themselves for a reference scheme. The sec
+// objects store the reference instead of the encrypted value, and the
+// value is resolved at decode time, so the secret is never stored in the
+// cluster configuration.
+package secbackend
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+type (
+	// Ref is a parsed external secret reference <scheme>://<path>#<field>
+	Ref struct {
+		Scheme string
+		Path   string
+		Field  string
+	}
+
+	// Backend is the interface implemented by the secret backend drivers.
+	Backend interface {
+		// Get returns the secret value pointed by the reference.
+		Get(ref Ref) ([]byte, error)
+	}
+)
+
+var (
+	backends = make(map[string]Backend)
+
+	// ErrNotRef is returned by ParseRef when the string is not a
+	// reference to a registered backend.
+	ErrNotRef = fmt.Errorf("not a secret backend reference")
+)
+
+// Register makes a backend available to resolve references with the
+// scheme.
+func Register(scheme string, b Backend) {
+	backends[scheme] = b
+}
+
+// Schemes returns the sorted list of registered schemes.
+func Schemes() []string {
+	l := make([]string, 0, len(backends))
+	for scheme := range backends {
+		l = append(l, scheme)
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (t Ref) String() string {
+	s := t.Scheme + "://" + t.Path
+	if t.Field != "" {
+		s += "#" + t.Field
+	}
+	return s
+}
+
+// ParseRef returns the Ref parsed from a <scheme>://<path>#<field> string
+// if the scheme is registered.
+func ParseRef(s string) (Ref, error) {
+	ref := Ref{}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ref, ErrNotRef
+	}
+	if _, ok := backends[u.Scheme]; !ok {
+		return ref, ErrNotRef
+	}
+	ref.Scheme = u.Scheme
+	ref.Path = strings.TrimPrefix(u.Host+u.Path, "/")
+	ref.Field = u.Fragment
+	if ref.Path == "" {
+		return ref, fmt.Errorf("%s: empty secret path", s)
+	}
+	return ref, nil
+}
+
+// IsRef returns true if the string is a reference to a registered backend.
+func IsRef(s string) bool {
+	_, err := ParseRef(s)
+	return err == nil
+}
+
+// Resolve returns the secret value pointed by the reference string.
+func Resolve(s string) ([]byte, error) {
+	ref, err := ParseRef(s)
+	if err != nil {
+		return nil, err
+	}
+	b, err := backends[ref.Scheme].Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,58 @@
+package secbackend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fake map[string]string
+
+func (t fake) Get(ref Ref) ([]byte, error) {
+	return []byte(t[ref.Path+"#"+ref.Field]), nil
+}
+
+func TestParseRef(t *testing.T) {
+	Register("fake", fake{"secret/data/db#password": "s3cr3t"})
+	tests := map[string]struct {
+		s     string
+		isRef bool
+		ref   Ref
+	}{
+		"registered scheme": {
+			s:     "fake://secret/data/db#password",
+			isRef: true,
+			ref:   Ref{Scheme: "fake", Path: "secret/data/db", Field: "password"},
+		},
+		"registered scheme without field": {
+			s:     "fake://secret/data/db",
+			isRef: true,
+			ref:   Ref{Scheme: "fake", Path: "secret/data/db"},
+		},
+		"unregistered scheme": {
+			s: "foo://secret/data/db#password",
+		},
+		"crypted value": {
+			s: "crypt:Zm9v",
+		},
+	}
+	for name, test := range tests {
+		t.Logf("%s", name)
+		ref, err := ParseRef(test.s)
+		assert.Equal(t, test.isRef, IsRef(test.s))
+		if !test.isRef {
+			assert.Equal(t, ErrNotRef, err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.ref, ref)
+		assert.Equal(t, test.s, ref.String())
+	}
+}
+
+func TestResolve(t *testing.T) {
+	Register("fake", fake{"secret/data/db#password": "s3cr3t"})
+	b, err := Resolve("fake://secret/data/db#password")
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", string(b))
+}
@@ -8,6 +8,8 @@ import (
 )
 
 type (
+	// T maps signals to the list of resource ids to send them to.
+	// A nil list means all candidate resources.
 	T map[syscall.Signal][]string
 )
 
@@ -15,9 +17,6 @@ func Parse(s string) T {
 	t := make(map[syscall.Signal][]string)
 	for _, e := range strings.Fields(s) {
 		l := strings.SplitN(e, ":", 2)
-		if len(l) != 2 {
-			continue
-		}
 		sigName := strings.ToUpper(l[0])
 		if !strings.HasPrefix(sigName, "SIG") {
 			sigName = "SIG" + sigName
@@ -26,8 +25,29 @@ func Parse(s string) T {
 		if sigNum == 0 {
 			continue
 		}
+		if len(l) == 1 {
+			t[sigNum] = nil
+			continue
+		}
 		rids := strings.Split(l[1], ",")
 		t[sigNum] = rids
 	}
 	return T(t)
 }
+
+// Targets returns true if the resource id is a target of the signal.
+func (t T) Targets(sig syscall.Signal, rid string) bool {
+	rids, ok := t[sig]
+	if !ok {
+		return false
+	}
+	if rids == nil {
+		return true
+	}
+	for _, e := range rids {
+		if e == rid {
+			return true
+		}
+	}
+	return false
+}
@@ -15,3 +15,12 @@ func TestParse(t *testing.T) {
 	assert.Contains(t, m, syscall.SIGHUP, "contains SIGHUP")
 	assert.Contains(t, m, syscall.SIGKILL, "contains SIGKILL")
 }
+
+func TestParseAllTargets(t *testing.T) {
+	m := Parse("hup usr1:container#1")
+	assert.Equal(t, 2, len(m), "2 valid signals parsed")
+	assert.True(t, m.Targets(syscall.SIGHUP, "container#2"), "SIGHUP targets all resources")
+	assert.True(t, m.Targets(syscall.SIGUSR1, "container#1"), "SIGUSR1 targets container#1")
+	assert.False(t, m.Targets(syscall.SIGUSR1, "container#2"), "SIGUSR1 does not target container#2")
+	assert.False(t, m.Targets(syscall.SIGKILL, "container#1"), "SIGKILL is not configured")
+}
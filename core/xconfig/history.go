@@ -0,0 +1,125 @@
+package xconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultHistoryMax is the default number of previous configuration
+// generations kept in the history directory.
+const DefaultHistoryMax = 10
+
+// ErrNoRevision is returned when the requested configuration generation
+// is not in the history.
+var ErrNoRevision = errors.New("configuration revision not found")
+
+//
+// Revisions returns the generation numbers of the configurations saved
+// in the history directory, the most recent first.
+//
+func (t T) Revisions() ([]int, error) {
+	l := make([]int, 0)
+	if t.HistoryDir == "" {
+		return l, nil
+	}
+	entries, err := ioutil.ReadDir(t.HistoryDir)
+	switch {
+	case os.IsNotExist(err):
+		return l, nil
+	case err != nil:
+		return l, err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasSuffix(name, ".conf") {
+			continue
+		}
+		gen, err := strconv.Atoi(strings.TrimSuffix(name, ".conf"))
+		if err != nil {
+			continue
+		}
+		l = append(l, gen)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(l)))
+	return l, nil
+}
+
+//
+// Revision returns the n-th previous generation of the configuration,
+// with the same referrers. Revision 0 is the installed configuration,
+// revision 1 the configuration it replaced, and so on.
+//
+func (t *T) Revision(n int) (*T, error) {
+	if n == 0 {
+		return t, nil
+	}
+	l, err := t.Revisions()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > len(l) {
+		return nil, errors.Wrapf(ErrNoRevision, "%d (%d revisions in history)", n, len(l))
+	}
+	cf, err := NewObject(t.historyFile(l[n-1]))
+	if err != nil {
+		return nil, err
+	}
+	cf.Path = t.Path
+	cf.Referrer = t.Referrer
+	cf.NodeReferrer = t.NodeReferrer
+	return cf, nil
+}
+
+func (t T) historyFile(gen int) string {
+	return filepath.Join(t.HistoryDir, fmt.Sprintf("%d.conf", gen))
+}
+
+//
+// pushHistory saves the installed configuration file as the next
+// generation in the history directory, and removes the generations in
+// excess of HistoryMax.
+//
+func (t T) pushHistory() error {
+	if t.HistoryDir == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(t.ConfigFilePath)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	if err := os.MkdirAll(t.HistoryDir, 0700); err != nil {
+		return err
+	}
+	l, err := t.Revisions()
+	if err != nil {
+		return err
+	}
+	gen := 1
+	if len(l) > 0 {
+		gen = l[0] + 1
+	}
+	if err := ioutil.WriteFile(t.historyFile(gen), b, 0600); err != nil {
+		return err
+	}
+	max := t.HistoryMax
+	if max <= 0 {
+		max = DefaultHistoryMax
+	}
+	// l does not include the generation just saved
+	for i := max - 1; i < len(l); i++ {
+		if err := os.Remove(t.historyFile(l[i])); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
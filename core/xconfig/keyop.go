@@ -0,0 +1,158 @@
+package xconfig
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anmitsu/go-shlex"
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/util/converters"
+)
+
+type (
+	//
+	// listCodec splits a keyword value into its list elements and joins
+	// them back, the same way the keyword converter does, so the list
+	// operators are consistent with the value evaluation.
+	//
+	listCodec struct {
+		split func(string) ([]string, error)
+		join  func([]string) string
+		equal func(string, string) bool
+	}
+)
+
+var (
+	// scalarConverters are the converters of the keywords refusing the
+	// list operators.
+	scalarConverters = map[string]interface{}{
+		"int":       nil,
+		"int64":     nil,
+		"float64":   nil,
+		"bool":      nil,
+		"duration":  nil,
+		"umask":     nil,
+		"size":      nil,
+		"file-mode": nil,
+		"tristate":  nil,
+	}
+
+	fieldsCodec = listCodec{
+		split: func(s string) ([]string, error) { return strings.Fields(s), nil },
+		join:  func(l []string) string { return strings.Join(l, " ") },
+		equal: func(a, b string) bool { return a == b },
+	}
+
+	lowercaseFieldsCodec = listCodec{
+		split: fieldsCodec.split,
+		join:  fieldsCodec.join,
+		equal: strings.EqualFold,
+	}
+
+	shlexCodec = listCodec{
+		split: func(s string) ([]string, error) { return shlex.Split(s, true) },
+		join:  converters.ShlexJoin,
+		equal: func(a, b string) bool { return a == b },
+	}
+)
+
+//
+// codec returns the list codec of the key, or an error if the keyword
+// converter is a scalar type. The keys without keyword definition, like
+// the env section keys, are whitespace-separated lists.
+//
+func (t *T) codec(op keyop.T) (listCodec, error) {
+	if t.Referrer == nil {
+		return fieldsCodec, nil
+	}
+	kw, err := getKeyword(op.Key, t.sectionType(op.Key), t.Referrer)
+	if err != nil || kw.Converter == nil {
+		return fieldsCodec, nil
+	}
+	name := fmt.Sprint(kw.Converter)
+	switch name {
+	case "list-lowercase":
+		return lowercaseFieldsCodec, nil
+	case "shlex":
+		return shlexCodec, nil
+	}
+	if _, ok := scalarConverters[name]; ok {
+		return listCodec{}, fmt.Errorf("%s: the %s operator is not supported by the %s keyword type", op.Key, op.Op, name)
+	}
+	return fieldsCodec, nil
+}
+
+func (t *T) set(op keyop.T) error {
+	t.Referrer.Log().Debug().Stringer("op", op).Msg("set")
+	k := t.file.Section(op.Key.Section).Key(op.Key.Option)
+	if op.Op == keyop.Set {
+		k.SetValue(op.Value)
+		return nil
+	}
+	codec, err := t.codec(op)
+	if err != nil {
+		return err
+	}
+	current, err := codec.split(k.Value())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op.Key, err)
+	}
+	index := func(l []string) int {
+		for i, e := range l {
+			if codec.equal(e, op.Value) {
+				return i
+			}
+		}
+		return -1
+	}
+	remove := func(l []string) []string {
+		target := make([]string, 0, len(l))
+		for _, e := range l {
+			if !codec.equal(e, op.Value) {
+				target = append(target, e)
+			}
+		}
+		return target
+	}
+	var target []string
+	switch op.Op {
+	case keyop.Append:
+		target = append(current, op.Value)
+	case keyop.Remove:
+		if index(current) < 0 {
+			return nil
+		}
+		target = remove(current)
+	case keyop.Merge:
+		if index(current) >= 0 {
+			return nil
+		}
+		target = append(current, op.Value)
+	case keyop.Toggle:
+		if index(current) >= 0 {
+			target = remove(current)
+		} else {
+			target = append(current, op.Value)
+		}
+	case keyop.Insert:
+		if op.Index < 0 || op.Index > len(current) {
+			return fmt.Errorf("%s: index %d out of range [0-%d]", op.Key, op.Index, len(current))
+		}
+		target = append(target, current[:op.Index]...)
+		target = append(target, op.Value)
+		target = append(target, current[op.Index:]...)
+	case keyop.Pop:
+		if op.Index < 0 || op.Index >= len(current) {
+			return fmt.Errorf("%s: index %d out of range [0-%d]", op.Key, op.Index, len(current)-1)
+		}
+		if op.Value != "" && !codec.equal(current[op.Index], op.Value) {
+			return fmt.Errorf("%s: element %d is %s, not %s", op.Key, op.Index, current[op.Index], op.Value)
+		}
+		target = append(target, current[:op.Index]...)
+		target = append(target, current[op.Index+1:]...)
+	default:
+		return fmt.Errorf("unsupported operator: %d", op.Op)
+	}
+	k.SetValue(codec.join(target))
+	return nil
+}
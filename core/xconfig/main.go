@@ -1,8 +1,9 @@
 package xconfig
 
 import (
+	"bytes"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -10,8 +11,8 @@ import (
 	"time"
 
 	"github.com/golang-collections/collections/set"
-	"github.com/google/uuid"
 	"github.com/iancoleman/orderedmap"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"gopkg.in/ini.v1"
@@ -34,7 +35,17 @@ type (
 		Path           path.T
 		Referrer       Referrer
 		NodeReferrer   Referrer
-		file           *ini.File
+
+		// HistoryDir is the directory where the previous generations of
+		// the configuration file are saved on commit. An empty value
+		// disables the history.
+		HistoryDir string
+
+		// HistoryMax is the maximum number of previous generations kept
+		// in HistoryDir. Zero means DefaultHistoryMax.
+		HistoryMax int
+
+		file *ini.File
 	}
 
 	// Referer is the interface implemented by node and object to
@@ -55,6 +66,13 @@ type (
 		EncapNodes() []string
 	}
 
+	// ConfigValidator is implemented by the referrers validating the
+	// configuration before it is committed, for example checking the
+	// keywords against the resource driver manifests.
+	ConfigValidator interface {
+		ValidateConfig(*T) error
+	}
+
 	ErrPostponedRef struct {
 		Ref string
 		RID string
@@ -199,6 +217,47 @@ func (t *T) Unset(ks ...key.T) int {
 	return deleted
 }
 
+//
+// SetKeys applies the keyword operations to a copy of the configuration,
+// and installs the copy only if all the operations succeed. So a failing
+// operation leaves the configuration unchanged.
+//
+func (t *T) SetKeys(ops ...keyop.T) error {
+	file, err := t.copyFile()
+	if err != nil {
+		return err
+	}
+	current := t.file
+	t.file = file
+	for _, op := range ops {
+		if err := t.Set(op); err != nil {
+			t.file = current
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload replaces the in-memory configuration with the content of the
+// configuration file, discarding the uncommitted changes.
+func (t *T) Reload() error {
+	file, err := ini.LoadSources(rawconfig.IniLoadOptions, t.ConfigFilePath)
+	if err != nil {
+		return err
+	}
+	t.file = file
+	return nil
+}
+
+// copyFile returns a deep copy of the in-memory configuration.
+func (t T) copyFile() (*ini.File, error) {
+	var buf bytes.Buffer
+	if _, err := t.file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return ini.LoadSources(rawconfig.IniLoadOptions, buf.Bytes())
+}
+
 func (t *T) Set(op keyop.T) error {
 	if !DriverGroups.Has(op.Key.Section) {
 		return t.set(op)
@@ -220,115 +279,24 @@ func (t *T) DriverGroupSet(op keyop.T) error {
 	return nil
 }
 
-func (t *T) set(op keyop.T) error {
-	t.Referrer.Log().Debug().Stringer("op", op).Msg("set")
-	setSet := func(op keyop.T) error {
-		t.file.Section(op.Key.Section).Key(op.Key.Option).SetValue(op.Value)
-		return nil
-	}
-	setAppend := func(op keyop.T) error {
-		current := t.file.Section(op.Key.Section).Key(op.Key.Option).Value()
-		target := ""
-		if current == "" {
-			target = op.Value
-		} else {
-			target = fmt.Sprintf("%s %s", current, op.Value)
-		}
-		t.file.Section(op.Key.Section).Key(op.Key.Option).SetValue(target)
-		return nil
-	}
-	setMerge := func(op keyop.T) error {
-		current := strings.Fields(t.file.Section(op.Key.Section).Key(op.Key.Option).Value())
-		currentSet := set.New()
-		for _, e := range current {
-			currentSet.Insert(e)
-		}
-		if currentSet.Has(op.Value) {
-			return nil
-		}
-		return setAppend(op)
-	}
-
-	setRemove := func(op keyop.T) error {
-		current := strings.Fields(t.file.Section(op.Key.Section).Key(op.Key.Option).Value())
-		target := []string{}
-		removed := 0
-		for _, e := range current {
-			if e == op.Value {
-				removed += 1
-				continue
-			}
-			target = append(target, e)
-		}
-		if removed == 0 {
-			return nil
-		}
-		t.file.Section(op.Key.Section).Key(op.Key.Option).SetValue(strings.Join(target, " "))
-		return nil
-	}
-
-	setToggle := func(op keyop.T) error {
-		current := strings.Fields(t.file.Section(op.Key.Section).Key(op.Key.Option).Value())
-		hasValue := false
-		for _, e := range current {
-			if e == op.Value {
-				hasValue = true
-				break
-			}
-		}
-		if hasValue {
-			return setRemove(op)
-		}
-		return setMerge(op)
-	}
-
-	setInsert := func(op keyop.T) error {
-		current := strings.Fields(t.file.Section(op.Key.Section).Key(op.Key.Option).Value())
-		target := []string{}
-		target = append(target, current[:op.Index]...)
-		target = append(target, op.Value)
-		target = append(target, current[op.Index:]...)
-		t.file.Section(op.Key.Section).Key(op.Key.Option).SetValue(strings.Join(target, " "))
-		return nil
-	}
-
-	switch op.Op {
-	case keyop.Set:
-		return setSet(op)
-	case keyop.Append:
-		return setAppend(op)
-	case keyop.Remove:
-		return setRemove(op)
-	case keyop.Merge:
-		return setMerge(op)
-	case keyop.Toggle:
-		return setToggle(op)
-	case keyop.Insert:
-		return setInsert(op)
-	}
-	return fmt.Errorf("unsupported operator: %d", op.Op)
-}
-
-func (t *T) write() (err error) {
-	var f *os.File
+//
+// write installs the configuration file atomically. The replaced
+// configuration is saved in the history directory.
+//
+func (t *T) write(configPath string) error {
 	ini.DefaultHeader = true
-	dir := filepath.Dir(t.ConfigFilePath)
-	if err = os.MkdirAll(dir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return err
 	}
-	base := filepath.Base(t.ConfigFilePath)
-	if f, err = ioutil.TempFile(dir, "."+base+".*"); err != nil {
-		return err
-	}
-	fName := f.Name()
-	defer os.Remove(fName)
-	if err = t.file.SaveTo(fName); err != nil {
-		return err
+	if configPath == t.ConfigFilePath {
+		if err := t.pushHistory(); err != nil {
+			return fmt.Errorf("save the configuration history: %w", err)
+		}
 	}
-	if _, err = t.file.WriteTo(f); err != nil {
+	return file.AtomicWriteFunc(configPath, 0644, func(w io.Writer) error {
+		_, err := t.file.WriteTo(w)
 		return err
-	}
-	return os.Rename(fName, t.ConfigFilePath)
+	})
 }
 
 func (t *T) Eval(k key.T) (interface{}, error) {
@@ -363,7 +331,7 @@ func (t *T) EvalKeywordAs(k key.T, kw keywords.Keyword, impersonate string) (int
 	if err != nil {
 		return nil, err
 	}
-	return t.convert(v, kw)
+	return t.convert(k, v, kw)
 }
 
 func getKeyword(k key.T, sectionType string, referrer Referrer) (keywords.Keyword, error) {
@@ -386,11 +354,15 @@ func (t *T) evalStringAs(k key.T, kw keywords.Keyword, impersonate string) (stri
 	return t.replaceReferences(v, k.Section, impersonate)
 }
 
-func (t *T) convert(v string, kw keywords.Keyword) (interface{}, error) {
+func (t *T) convert(k key.T, v string, kw keywords.Keyword) (interface{}, error) {
 	if kw.Converter == nil {
 		return v, nil
 	}
-	return kw.Converter.Convert(v)
+	i, err := kw.Converter.Convert(v)
+	if err != nil {
+		return i, errors.Wrapf(err, "%s", k)
+	}
+	return i, nil
 }
 
 func (t *T) mayDescope(k key.T, kw keywords.Keyword, impersonate string) (string, error) {
@@ -405,6 +377,9 @@ func (t *T) mayDescope(k key.T, kw keywords.Keyword, impersonate string) (string
 	}
 	switch {
 	case errors.Is(err, ErrExist):
+		if v, err := t.profileValue(k, impersonate); err == nil {
+			return v, nil
+		}
 		switch kw.Required {
 		case true:
 			return "", err
@@ -417,6 +392,54 @@ func (t *T) mayDescope(k key.T, kw keywords.Keyword, impersonate string) (string
 	return v, nil
 }
 
+// ProfileSection returns the name of the node or cluster configuration
+// section hosting the named resource profile.
+func ProfileSection(name string) string {
+	return "profile#" + name
+}
+
+// profile returns the node and cluster merged configuration and the
+// profile section referenced by the section "profile" keyword.
+func (t *T) profile(section string, impersonate string) (*T, string, error) {
+	name, err := t.descope(key.New(section, "profile"), impersonate)
+	if err != nil {
+		return nil, "", err
+	}
+	if name == "" {
+		return nil, "", errors.Wrapf(ErrExist, "section '%s' has no profile", section)
+	}
+	i, ok := t.NodeReferrer.(interface {
+		MergedConfig() *T
+	})
+	if !ok {
+		return nil, "", errors.Wrapf(ErrExist, "profile %s: no node configuration", name)
+	}
+	cfg := i.MergedConfig()
+	if cfg == nil {
+		return nil, "", errors.Wrapf(ErrExist, "profile %s: no node configuration", name)
+	}
+	profileSection := ProfileSection(name)
+	if !cfg.HasSectionString(profileSection) {
+		return nil, "", errors.Wrapf(ErrExist, "profile %s: section '%s' not found in the node and cluster configurations", name, profileSection)
+	}
+	return cfg, profileSection, nil
+}
+
+// profileValue returns the value of the option in the resource profile
+// referenced by the section "profile" keyword. The profile values have
+// precedence over the keyword default, but not over the values set in the
+// object configuration.
+func (t *T) profileValue(k key.T, impersonate string) (string, error) {
+	if k.Option == "profile" || t.NodeReferrer == nil {
+		return "", errors.Wrapf(ErrExist, "%s", k)
+	}
+	cfg, profileSection, err := t.profile(k.Section, impersonate)
+	if err != nil {
+		return "", err
+	}
+	return cfg.descope(key.New(profileSection, k.Option), impersonate)
+}
+
 func (t *T) replaceReferences(v string, section string, impersonate string) (string, error) {
 	errs := make([]error, 0)
 	v = rawconfig.RegexpReference.ReplaceAllStringFunc(v, func(ref string) string {
@@ -475,14 +498,58 @@ func (t *T) descope(k key.T, impersonate string) (string, error) {
 }
 
 func (t T) Raw() rawconfig.T {
+	return rawconfig.FromIniFile(t.file)
+}
+
+// RawEvaluatedAs returns the configuration with the scoped keywords
+// replaced by their value on the impersonated node, the resource profiles
+// expanded, and the references dereferenced. The references that can only be resolved at action time,
+// like resource devices, are left as is.
+func (t *T) RawEvaluatedAs(impersonate string) rawconfig.T {
 	r := rawconfig.T{}
 	r.Data = orderedmap.New()
 	for _, s := range t.file.Sections() {
+		section := s.Name()
 		sectionMap := *orderedmap.New()
-		for k, v := range s.KeysHash() {
-			sectionMap.Set(k, v)
+		done := make(map[string]interface{})
+		for _, name := range t.Keys(section) {
+			option := strings.SplitN(name, "@", 2)[0]
+			if _, ok := done[option]; ok {
+				continue
+			}
+			done[option] = nil
+			k := key.New(section, option)
+			kw, err := getKeyword(k, t.sectionType(k), t.Referrer)
+			if err != nil {
+				// unknown keyword: still apply the scopes
+				kw = keywords.Keyword{Scopable: true}
+			}
+			v, err := t.evalStringAs(k, kw, impersonate)
+			switch err.(type) {
+			case nil, ErrPostponedRef:
+			default:
+				v = t.Get(k)
+			}
+			sectionMap.Set(option, v)
 		}
-		r.Data.Set(s.Name(), sectionMap)
+		if cfg, profileSection, err := t.profile(section, impersonate); err == nil {
+			for _, name := range cfg.Keys(profileSection) {
+				option := strings.SplitN(name, "@", 2)[0]
+				if _, ok := done[option]; ok {
+					continue
+				}
+				done[option] = nil
+				v, err := t.profileValue(key.New(section, option), impersonate)
+				if err != nil {
+					continue
+				}
+				if ev, err := t.replaceReferences(v, section, impersonate); err == nil {
+					v = ev
+				}
+				sectionMap.Set(option, v)
+			}
+		}
+		r.Data.Set(section, sectionMap)
 	}
 	return r
 }
@@ -648,23 +715,9 @@ func (t T) dereferenceWellKnown(ref string, section string, impersonate string)
 }
 
 func (t *T) replaceFile(configData rawconfig.T) error {
-	file := ini.Empty()
-	for _, section := range configData.Data.Keys() {
-		m, _ := configData.Data.Get(section)
-		omap, ok := m.(orderedmap.OrderedMap)
-		if !ok {
-			return fmt.Errorf("invalid section in raw config format: %+v", m)
-		}
-		for _, option := range omap.Keys() {
-			value, _ := omap.Get(option)
-			var v string
-			if value == nil {
-				v = ""
-			} else {
-				v = value.(string)
-			}
-			file.Section(section).Key(option).SetValue(v)
-		}
+	file, err := configData.IniFile()
+	if err != nil {
+		return err
 	}
 	t.file = file
 	return nil
@@ -694,12 +747,31 @@ func (t T) initDefaultSection() error {
 	return nil
 }
 
+//
+// rawCommit prepares the configuration to commit on a copy, validates
+// it and writes it. The in-memory configuration is replaced by the copy
+// only if all these steps succeed.
+//
 func (t *T) rawCommit(configData rawconfig.T, configPath string, validate bool) error {
+	current := t.file
 	if !configData.IsZero() {
 		if err := t.replaceFile(configData); err != nil {
 			return err
 		}
+	} else if file, err := t.copyFile(); err != nil {
+		return err
+	} else {
+		t.file = file
 	}
+	if err := t.commitFile(configPath, validate); err != nil {
+		t.file = current
+		return err
+	}
+	//t.clearRefCache()
+	return t.postCommit()
+}
+
+func (t *T) commitFile(configPath string, validate bool) error {
 	if configPath == "" {
 		configPath = t.ConfigFilePath
 	}
@@ -715,16 +787,44 @@ func (t *T) rawCommit(configData rawconfig.T, configPath string, validate bool)
 			return err
 		}
 	}
-	if !t.Referrer.IsVolatile() {
-		if err := t.write(); err != nil {
-			return err
-		}
+	if t.Referrer.IsVolatile() {
+		return nil
 	}
-	//t.clearRefCache()
-	return t.postCommit()
+	return t.write(configPath)
+}
+
+// validate runs the referrer validation hook, if implemented.
+func (t *T) validate() error {
+	if v, ok := t.Referrer.(ConfigValidator); ok {
+		return v.ValidateConfig(t)
+	}
+	return nil
 }
 
-func (t T) validate() error {
+// Validate returns an error wrapping ErrNoKeyword for the first key of
+// the configuration that is not a keyword of the referrer.
+func (t T) Validate() error {
+	for _, section := range t.file.Sections() {
+		name := section.Name()
+		if name == "metadata" {
+			continue
+		}
+		var sectionType string
+		if section.HasKey("type") {
+			sectionType = section.Key("type").Value()
+		}
+		for _, k := range section.Keys() {
+			option := strings.SplitN(k.Name(), "@", 2)[0]
+			switch option {
+			case "comment", "type":
+				// the section type is validated by the driver lookup
+				continue
+			}
+			if _, err := getKeyword(key.New(name, option), sectionType, t.Referrer); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"gopkg.in/ini.v1"
+	"opensvc.com/opensvc/core/rawconfig"
 )
 
 // NewObject configures and returns a Viper instance
@@ -14,11 +15,7 @@ func NewObject(p string, others ...interface{}) (t *T, err error) {
 	t = &T{
 		ConfigFilePath: cf,
 	}
-	t.file, err = ini.LoadSources(ini.LoadOptions{
-		Loose:                      true,
-		AllowPythonMultilineValues: true,
-		SpaceBeforeInlineComment:   true,
-	}, cf, others...)
+	t.file, err = ini.LoadSources(rawconfig.IniLoadOptions, cf, others...)
 	if err != nil {
 		return nil, errors.Wrap(err, "load config error")
 	}
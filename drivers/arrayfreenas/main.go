@@ -0,0 +1,285 @@
+package arrayfreenas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/array"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	// T is the freenas array driver, allocating zvols exported as iscsi
+	// extents through the freenas rest api v2.0.
+	T struct {
+		array.T
+
+		// password is a test hook bypassing the secret object lookup.
+		password string
+	}
+
+	freenasDataset struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		VolSize struct {
+			Parsed int64 `json:"parsed"`
+		} `json:"volsize"`
+	}
+
+	freenasExtent struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Disk string `json:"disk"`
+		NAA  string `json:"naa"`
+	}
+
+	freenasTarget struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+func init() {
+	array.Register("freenas", NewDriver)
+}
+
+// NewDriver allocates a freenas array driver.
+func NewDriver() array.Driver {
+	return &T{}
+}
+
+func (t *T) api() string {
+	return strings.TrimRight(t.GetString("api"), "/")
+}
+
+func (t *T) timeout() time.Duration {
+	if d := t.GetDuration("timeout"); d != nil {
+		return *d
+	}
+	return 120 * time.Second
+}
+
+//
+// getPassword returns the password stored in the password key of the
+// secret object referenced by the password keyword. The secret must be
+// in the system namespace.
+//
+func (t *T) getPassword() (string, error) {
+	if t.password != "" {
+		return t.password, nil
+	}
+	s := t.GetString("password")
+	if s == "" {
+		return "", fmt.Errorf("array %s: password keyword is not set", t.Name())
+	}
+	p, err := path.New(s, "system", "sec")
+	if err != nil {
+		return "", err
+	}
+	b, err := object.NewSec(p).Decode(object.OptsDecode{Key: "password"})
+	if err != nil {
+		return "", errors.Wrapf(err, "array %s: decode the password key of %s", t.Name(), p)
+	}
+	return string(b), nil
+}
+
+func (t *T) do(method, uri string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, t.api()+uri, &body)
+	if err != nil {
+		return err
+	}
+	password, err := t.getPassword()
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.GetString("username"), password)
+	req.Header.Set("Content-Type", "application/json")
+	c := &http.Client{Timeout: t.timeout()}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s: %s: %s", method, uri, resp.Status, strings.TrimSpace(string(b)))
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, out)
+}
+
+func datasetURI(id string) string {
+	return "/pool/dataset/id/" + url.PathEscape(id)
+}
+
+func (t *T) getExtent(name string) (freenasExtent, error) {
+	var l []freenasExtent
+	if err := t.do("GET", "/iscsi/extent?name="+url.QueryEscape(name), nil, &l); err != nil {
+		return freenasExtent{}, err
+	}
+	if len(l) == 0 {
+		return freenasExtent{}, fmt.Errorf("extent %s not found", name)
+	}
+	return l[0], nil
+}
+
+func (t *T) getExtentByID(id string) (freenasExtent, error) {
+	var e freenasExtent
+	err := t.do("GET", "/iscsi/extent/id/"+url.PathEscape(id), nil, &e)
+	return e, err
+}
+
+func (t *T) getTarget(name string) (freenasTarget, error) {
+	var l []freenasTarget
+	if err := t.do("GET", "/iscsi/target?name="+url.QueryEscape(name), nil, &l); err != nil {
+		return freenasTarget{}, err
+	}
+	if len(l) == 0 {
+		return freenasTarget{}, fmt.Errorf("target %s not found", name)
+	}
+	return l[0], nil
+}
+
+func (t *T) getDataset(id string) (freenasDataset, error) {
+	var d freenasDataset
+	err := t.do("GET", datasetURI(id), nil, &d)
+	return d, err
+}
+
+// extentDataset returns the dataset id of a "zvol/<dataset>" extent disk.
+func extentDataset(e freenasExtent) string {
+	return strings.TrimPrefix(e.Disk, "zvol/")
+}
+
+func (t *T) lookupExtent(name, id string) (freenasExtent, error) {
+	switch {
+	case id != "":
+		return t.getExtentByID(id)
+	case name != "":
+		return t.getExtent(name)
+	default:
+		return freenasExtent{}, fmt.Errorf("a disk name or id is required")
+	}
+}
+
+func newDisk(e freenasExtent, d freenasDataset, mappings []string) array.Disk {
+	return array.Disk{
+		ID:       strconv.Itoa(e.ID),
+		Name:     e.Name,
+		DiskID:   strings.TrimPrefix(e.NAA, "0x"),
+		Size:     d.VolSize.Parsed,
+		Mappings: mappings,
+	}
+}
+
+// AddDisk creates a zvol in the disk group, exports it as an iscsi
+// extent, and maps the extent to the requested targets.
+func (t *T) AddDisk(options array.OptsAddDisk) (array.Disk, error) {
+	if options.Name == "" {
+		return array.Disk{}, fmt.Errorf("a disk name is required")
+	}
+	if options.DiskGroup == "" {
+		return array.Disk{}, fmt.Errorf("a disk group is required")
+	}
+	size, err := sizeconv.FromSize(options.Size)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	var d freenasDataset
+	err = t.do("POST", "/pool/dataset", map[string]interface{}{
+		"name":    options.DiskGroup + "/" + options.Name,
+		"type":    "VOLUME",
+		"volsize": size,
+	}, &d)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	var e freenasExtent
+	err = t.do("POST", "/iscsi/extent", map[string]interface{}{
+		"name": options.Name,
+		"type": "DISK",
+		"disk": "zvol/" + d.ID,
+	}, &e)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	for _, mapping := range options.Mappings {
+		target, err := t.getTarget(mapping)
+		if err != nil {
+			return array.Disk{}, err
+		}
+		err = t.do("POST", "/iscsi/targetextent", map[string]interface{}{
+			"target": target.ID,
+			"extent": e.ID,
+		}, nil)
+		if err != nil {
+			return array.Disk{}, err
+		}
+	}
+	d.VolSize.Parsed = size
+	return newDisk(e, d, options.Mappings), nil
+}
+
+// DelDisk deletes the iscsi extent of the disk and its zvol.
+func (t *T) DelDisk(options array.OptsDelDisk) (array.Disk, error) {
+	e, err := t.lookupExtent(options.Name, options.ID)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	d, err := t.getDataset(extentDataset(e))
+	if err != nil {
+		return array.Disk{}, err
+	}
+	if err := t.do("DELETE", "/iscsi/extent/id/"+strconv.Itoa(e.ID), nil, nil); err != nil {
+		return array.Disk{}, err
+	}
+	if err := t.do("DELETE", datasetURI(d.ID), nil, nil); err != nil {
+		return array.Disk{}, err
+	}
+	return newDisk(e, d, nil), nil
+}
+
+// ResizeDisk sets the new size of the zvol of the disk.
+func (t *T) ResizeDisk(options array.OptsResizeDisk) (array.Disk, error) {
+	e, err := t.lookupExtent(options.Name, options.ID)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	d, err := t.getDataset(extentDataset(e))
+	if err != nil {
+		return array.Disk{}, err
+	}
+	size, err := array.ResizedSize(d.VolSize.Parsed, options.Size)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	err = t.do("PUT", datasetURI(d.ID), map[string]interface{}{
+		"volsize": size,
+	}, nil)
+	if err != nil {
+		return array.Disk{}, err
+	}
+	d.VolSize.Parsed = size
+	return newDisk(e, d, nil), nil
+}
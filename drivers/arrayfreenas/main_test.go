@@ -0,0 +1,111 @@
+package arrayfreenas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/array"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+type fakeAPI struct {
+	calls   []string
+	volsize int64
+}
+
+func (t *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.calls = append(t.calls, r.Method+" "+r.URL.RequestURI())
+	if u, p, _ := r.BasicAuth(); u != "root" || p != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var in map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&in)
+	var out interface{}
+	switch r.Method + " " + r.URL.Path {
+	case "POST /pool/dataset":
+		t.volsize = int64(in["volsize"].(float64))
+		out = map[string]interface{}{"id": in["name"], "name": in["name"]}
+	case "POST /iscsi/extent":
+		out = map[string]interface{}{"id": 12, "name": in["name"], "disk": in["disk"], "naa": "0x6589cfc0000001"}
+	case "GET /iscsi/extent":
+		out = []map[string]interface{}{{"id": 12, "name": r.URL.Query().Get("name"), "disk": "zvol/tank/d1", "naa": "0x6589cfc0000001"}}
+	case "GET /iscsi/target":
+		out = []map[string]interface{}{{"id": 3, "name": r.URL.Query().Get("name")}}
+	case "GET /pool/dataset/id/tank/d1":
+		out = map[string]interface{}{"id": "tank/d1", "name": "tank/d1", "volsize": map[string]interface{}{"parsed": t.volsize}}
+	case "PUT /pool/dataset/id/tank/d1":
+		t.volsize = int64(in["volsize"].(float64))
+	case "POST /iscsi/targetextent", "DELETE /iscsi/extent/id/12", "DELETE /pool/dataset/id/tank/d1":
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if out != nil {
+		_ = json.NewEncoder(w).Encode(out)
+	}
+}
+
+func newTestDriver(t *testing.T, api string) (*T, func()) {
+	root, err := ioutil.TempDir("", "arrayfreenas")
+	require.NoError(t, err)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte(fmt.Sprintf("[array#a1]\ntype = freenas\napi = %s\nusername = root\npassword = system/sec/a1\n", api))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "node.conf"), b, 0644))
+	n := object.NewNode()
+	assert.Equal(t, []string{"a1"}, n.ListArrays())
+	d, err := n.Array("a1")
+	require.NoError(t, err)
+	drv := d.(*T)
+	drv.password = "secret"
+	return drv, func() { os.RemoveAll(root) }
+}
+
+func TestDisk(t *testing.T) {
+	api := &fakeAPI{}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	drv, cleanup := newTestDriver(t, srv.URL)
+	defer cleanup()
+
+	disk, err := drv.AddDisk(array.OptsAddDisk{Name: "d1", DiskGroup: "tank", Size: "1g", Mappings: []string{"iqn.t1"}})
+	require.NoError(t, err)
+	assert.Equal(t, array.Disk{ID: "12", Name: "d1", DiskID: "6589cfc0000001", Size: 1024 * 1024 * 1024, Mappings: []string{"iqn.t1"}}, disk)
+	assert.Equal(t, []string{
+		"POST /pool/dataset",
+		"POST /iscsi/extent",
+		"GET /iscsi/target?name=iqn.t1",
+		"POST /iscsi/targetextent",
+	}, api.calls)
+
+	disk, err = drv.ResizeDisk(array.OptsResizeDisk{Name: "d1", Size: "+1g"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*1024*1024*1024), disk.Size)
+	assert.Equal(t, int64(2*1024*1024*1024), api.volsize)
+
+	api.calls = nil
+	_, err = drv.DelDisk(array.OptsDelDisk{Name: "d1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /iscsi/extent?name=d1",
+		"GET /pool/dataset/id/tank%2Fd1",
+		"DELETE /iscsi/extent/id/12",
+		"DELETE /pool/dataset/id/tank%2Fd1",
+	}, api.calls)
+}
+
+func TestAddDiskRequiresDiskGroup(t *testing.T) {
+	drv := &T{}
+	_, err := drv.AddDisk(array.OptsAddDisk{Name: "d1", Size: "1g"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package drivertest
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/statusbus"
+)
+
+//
+// NewContext returns an action context with a started status bus for the
+// object p, as expected by the resource actions checking the resources
+// requirements, and an action rollback stack. The returned function stops
+// the status bus.
+//
+func NewContext(p path.T) (context.Context, func()) {
+	ctx := actionrollback.NewContext(context.Background())
+	return statusbus.WithContext(ctx, p)
+}
+
+// PostStatus sets the status of the rid resource in the status bus of the
+// context, to simulate the state of a required resource.
+func PostStatus(ctx context.Context, rid string, state status.T) {
+	statusbus.FromContext(ctx).Post(rid, state, false)
+}
+
+// Status returns the status of the rid resource posted in the status bus
+// of the context.
+func Status(ctx context.Context, rid string) status.T {
+	return statusbus.FromContext(ctx).Get(rid)
+}
@@ -0,0 +1,68 @@
+package drivertest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/file"
+)
+
+func TestRoot(t *testing.T) {
+	root, cleanup := NewRoot(t)
+	assert.Equal(t, filepath.Join(root, "etc"), rawconfig.Node.Paths.Etc)
+	assert.True(t, file.ExistsAndDir(filepath.Join(root, "var")))
+	cleanup()
+	assert.False(t, file.Exists(root))
+}
+
+func TestRecorder(t *testing.T) {
+	rec, cleanup := NewRecorder(t)
+	defer cleanup()
+	log := NewLogger()
+	rec.Fake("lvs", Reply{Stdout: "vg1 lv1\n"})
+	rec.Fake("lvremove", Reply{Stderr: "in use\n", ExitCode: 5})
+
+	cmd := command.New(
+		command.WithName("lvs"),
+		command.WithVarArgs("--noheadings", "", "vg1/lv1"),
+		command.WithLogger(&log.Logger),
+		command.WithBufferedStdout(),
+	)
+	require.NoError(t, cmd.Run())
+	assert.Equal(t, "vg1 lv1", string(cmd.Stdout()))
+	assert.True(t, log.Contains("lvs"))
+
+	cmd = command.New(
+		command.WithName("/sbin/lvremove"),
+		command.WithVarArgs("-f", "vg1/lv1"),
+		command.WithBufferedStderr(),
+	)
+	assert.Error(t, cmd.Run())
+	assert.Equal(t, 5, cmd.ExitCode())
+	assert.Equal(t, "in use", string(cmd.Stderr()))
+
+	assert.Equal(t, []Call{
+		{Name: "lvs", Args: []string{"--noheadings", "", "vg1/lv1"}},
+		{Name: "/sbin/lvremove", Args: []string{"-f", "vg1/lv1"}},
+	}, rec.Calls())
+	assert.Equal(t, "/sbin/lvremove -f vg1/lv1", rec.CallsOf("lvremove")[0].String())
+
+	rec.Reset()
+	assert.Empty(t, rec.Calls())
+}
+
+func TestContext(t *testing.T) {
+	p, _ := path.Parse("svc1")
+	ctx, stop := NewContext(p)
+	defer stop()
+	assert.Equal(t, status.Undef, Status(ctx, "fs#1"))
+	PostStatus(ctx, "fs#1", status.Up)
+	assert.Equal(t, status.Up, Status(ctx, "fs#1"))
+}
@@ -0,0 +1,52 @@
+package drivertest
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// Logger is a debug level logger recording the driver logs in
+	// memory, as json lines.
+	Logger struct {
+		zerolog.Logger
+		mu   sync.Mutex
+		buff bytes.Buffer
+	}
+)
+
+// NewLogger allocates and returns a recording logger.
+func NewLogger() *Logger {
+	t := &Logger{}
+	t.Logger = zerolog.New(t).Level(zerolog.DebugLevel)
+	return t
+}
+
+// Write implements io.Writer for the zerolog logger.
+func (t *Logger) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buff.Write(b)
+}
+
+// String returns the recorded logs.
+func (t *Logger) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buff.String()
+}
+
+// Contains returns true if the recorded logs contain s.
+func (t *Logger) Contains(s string) bool {
+	return strings.Contains(t.String(), s)
+}
+
+// Reset drops the recorded logs.
+func (t *Logger) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buff.Reset()
+}
@@ -0,0 +1,66 @@
+package drivertest
+
+import (
+	"testing"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	//
+	// Recorder fakes the commands executed by the drivers through the
+	// util/command package, and records their calls. It is a command.Fake
+	// set as the package default executor, with string replies.
+	//
+	Recorder struct {
+		fake *command.Fake
+	}
+
+	// Call is a recorded command execution.
+	Call = command.Call
+
+	// Reply is the canned result of a fake command.
+	Reply struct {
+		Stdout   string
+		Stderr   string
+		ExitCode int
+	}
+)
+
+//
+// NewRecorder allocates a recorder and sets it as the util/command
+// executor. The returned function restores the previous executor.
+//
+func NewRecorder(t testing.TB) (*Recorder, func()) {
+	r := &Recorder{
+		fake: command.NewFake(),
+	}
+	restore := command.SetExecutor(r.fake)
+	return r, restore
+}
+
+// Fake fakes the name command, replying the canned result. The commands
+// executed by absolute path are matched by their base name. Faking an
+// already faked command replaces its reply.
+func (t *Recorder) Fake(name string, reply Reply) {
+	t.fake.Set(name, command.Result{
+		Stdout:   []byte(reply.Stdout),
+		Stderr:   []byte(reply.Stderr),
+		ExitCode: reply.ExitCode,
+	})
+}
+
+// Calls returns the recorded calls, in execution order.
+func (t *Recorder) Calls() []Call {
+	return t.fake.Calls()
+}
+
+// CallsOf returns the recorded calls of the name command.
+func (t *Recorder) CallsOf(name string) []Call {
+	return t.fake.CallsOf(name)
+}
+
+// Reset drops the recorded calls.
+func (t *Recorder) Reset() {
+	t.fake.Reset()
+}
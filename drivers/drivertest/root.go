@@ -0,0 +1,49 @@
+//
+// Package drivertest provides the helpers to unit test the resource
+// drivers Start, Stop and Status logic without root privileges nor the
+// real system tools:
+//
+//   NewRoot      a temporary agent root with its etc and var trees
+//   NewLogger    a logger recording the driver logs in memory
+//   NewContext   an action context with a status bus and a rollback stack
+//   NewRecorder  a recorder faking and recording the executed commands
+//
+// The helpers change process-wide settings, like the agent root or the
+// command executor, so the tests using them must not run in parallel.
+//
+package drivertest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+// rootDirs are the directories created in the temporary agent roots.
+var rootDirs = []string{"etc", "var", "tmp", "log"}
+
+//
+// NewRoot creates a temporary agent root with the etc, var, tmp and log
+// directories, and loads it as the agent root path. The returned function
+// restores the default configuration and removes the tree.
+//
+func NewRoot(t testing.TB) (string, func()) {
+	root, err := ioutil.TempDir("", "drivertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range rootDirs {
+		if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+			os.RemoveAll(root)
+			t.Fatal(err)
+		}
+	}
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	return root, func() {
+		rawconfig.Load(map[string]string{})
+		os.RemoveAll(root)
+	}
+}
@@ -70,7 +70,7 @@ func (t *T) BlkTranslate(name string, size float64, shared bool) []string {
 	return []string{
 		"disk#0.type=loop",
 		"disk#0.file=" + t.loopFile(name),
-		"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		"disk#0.size=" + sizeconv.Compact(uint64(size), sizeconv.IEC),
 	}
 }
 
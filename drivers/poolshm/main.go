@@ -56,7 +56,7 @@ func (t T) Usage() (pool.StatusUsage, error) {
 }
 
 func (t *T) mntOpt(size float64) string {
-	sizeOpt := "size=" + sizeconv.ExactBSizeCompact(size)
+	sizeOpt := "size=" + sizeconv.Compact(uint64(size), sizeconv.IEC)
 	opts := t.GetString("mnt_opt")
 	if opts != "" {
 		opts = strings.Join([]string{opts, sizeOpt}, ",")
@@ -83,6 +83,6 @@ func (t *T) BlkTranslate(name string, size float64, shared bool) []string {
 	return []string{
 		"disk#0.type=loop",
 		"disk#0.file=" + t.loopFile(name),
-		"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		"disk#0.size=" + sizeconv.Compact(uint64(size), sizeconv.IEC),
 	}
 }
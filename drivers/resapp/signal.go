@@ -0,0 +1,81 @@
+// +build !windows
+
+package resapp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// procDir is the directory scanned for the app processes environment.
+	procDir = "/proc"
+
+	// kill sends the signal to the process. Replaced in tests.
+	kill = syscall.Kill
+)
+
+//
+// Signal sends the signal to the processes started by the resource. The
+// processes are identified by the OPENSVC_ID and OPENSVC_RID variables
+// set in their environment by the start command.
+//
+func (t T) Signal(sig syscall.Signal) error {
+	pids, err := t.pids()
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		t.Log().Debug().Msgf("no process to send %s to", sig)
+		return nil
+	}
+	var errs error
+	for _, pid := range pids {
+		t.Log().Info().Msgf("send %s to pid %d", sig, pid)
+		if err := kill(pid, sig); err != nil {
+			errs = errors.Wrapf(err, "send %s to pid %d", sig, pid)
+		}
+	}
+	return errs
+}
+
+// pids returns the pids of the processes having the resource
+// identification variables in their environment.
+func (t T) pids() ([]int, error) {
+	matches, err := filepath.Glob(filepath.Join(procDir, "[0-9]*", "environ"))
+	if err != nil {
+		return nil, err
+	}
+	id := []byte("OPENSVC_ID=" + t.ObjectID.String())
+	rid := []byte("OPENSVC_RID=" + t.RID())
+	pids := make([]int, 0)
+	for _, p := range matches {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(p)))
+		if err != nil {
+			continue
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			// the process is gone or not ours to read
+			continue
+		}
+		var hasID, hasRID bool
+		for _, v := range bytes.Split(b, []byte{0}) {
+			switch {
+			case bytes.Equal(v, id):
+				hasID = true
+			case bytes.Equal(v, rid):
+				hasRID = true
+			}
+		}
+		if hasID && hasRID {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
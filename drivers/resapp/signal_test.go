@@ -0,0 +1,59 @@
+package resapp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/resource"
+)
+
+func TestT_Signal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resapp-proc")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer func(s string) { procDir = s }(procDir)
+	procDir = dir
+
+	app := T{}
+	app.ObjectID = uuid.New()
+	app.SetRID("app#1")
+	environ := map[string][]string{
+		"100": {"PATH=/bin", "OPENSVC_RID=app#1", "OPENSVC_ID=" + app.ObjectID.String()},
+		"101": {"OPENSVC_RID=app#2", "OPENSVC_ID=" + app.ObjectID.String()},
+		"102": {"OPENSVC_RID=app#1", "OPENSVC_ID=" + uuid.New().String()},
+		"103": {"OPENSVC_RID=app#10", "OPENSVC_ID=" + app.ObjectID.String()},
+		"self": {"OPENSVC_RID=app#1", "OPENSVC_ID=" + app.ObjectID.String()},
+	}
+	for pid, env := range environ {
+		require.Nil(t, os.Mkdir(filepath.Join(dir, pid), 0755))
+		b := []byte(strings.Join(env, "\x00") + "\x00")
+		require.Nil(t, ioutil.WriteFile(filepath.Join(dir, pid, "environ"), b, 0600))
+	}
+
+	sent := make(map[int]syscall.Signal)
+	defer func(f func(int, syscall.Signal) error) { kill = f }(kill)
+	kill = func(pid int, sig syscall.Signal) error {
+		sent[pid] = sig
+		return nil
+	}
+
+	var r interface{} = &app
+	_, ok := r.(resource.Signaler)
+	require.True(t, ok, "the app driver implements resource.Signaler")
+	require.Nil(t, app.Signal(syscall.SIGHUP))
+	assert.Equal(t, map[int]syscall.Signal{100: syscall.SIGHUP}, sent)
+
+	t.Run("kill error is returned", func(t *testing.T) {
+		kill = func(pid int, sig syscall.Signal) error {
+			return syscall.ESRCH
+		}
+		assert.NotNil(t, app.Signal(syscall.SIGHUP))
+	})
+}
@@ -77,8 +77,8 @@ func (t T) SortKey() string {
 	}
 }
 
-func (t T) Abort(ctx context.Context) bool {
-	return false
+func (t T) Abort(ctx context.Context) error {
+	return nil
 }
 
 // Stop the Resource
@@ -349,6 +349,17 @@ func (t T) GetTimeout(action string) time.Duration {
 	return *timeout
 }
 
+// ActionTimeout returns the timeout of the resource action, like
+// GetTimeout, for the action engine. The generic <action>_timeout
+// keywords are stored in the app driver fields.
+func (t T) ActionTimeout(action string) *time.Duration {
+	timeout := t.GetTimeout(action)
+	if timeout == 0 {
+		return nil
+	}
+	return &timeout
+}
+
 func (t T) exitCodeToStatus(exitCode int) status.T {
 	convertMap := t.exitCodeToStatusMap()
 	if s, ok := convertMap[exitCode]; ok {
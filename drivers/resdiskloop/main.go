@@ -24,6 +24,7 @@ import (
 const (
 	driverGroup = drivergroup.Disk
 	driverName  = "loop"
+	capability  = "drivers.resource.disk.loop"
 )
 
 type (
@@ -38,7 +39,7 @@ func capabilitiesScanner() ([]string, error) {
 	if !loop.IsCapable() {
 		return []string{}, nil
 	}
-	return []string{"drivers.resource.disk.loop"}, nil
+	return []string{capability}, nil
 }
 
 func New() resource.Driver {
@@ -73,7 +74,7 @@ func (t T) Manifest() *manifest.T {
 
 func init() {
 	capabilities.Register(capabilitiesScanner)
-	resource.Register(driverGroup, driverName, New)
+	resource.Register(driverGroup, driverName, New, resource.WithCapability(capability))
 }
 
 func (t T) loop() *loop.T {
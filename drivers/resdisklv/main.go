@@ -5,6 +5,7 @@ package resdisklv
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"opensvc.com/opensvc/core/actionrollback"
 	"opensvc.com/opensvc/core/drivergroup"
@@ -16,12 +17,18 @@ import (
 	"opensvc.com/opensvc/drivers/resdisk"
 	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/device"
+	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/udevadm"
+	"opensvc.com/opensvc/util/waitfor"
 )
 
 const (
 	driverGroup = drivergroup.Disk
 	driverName  = "lv"
+
+	// devPathTimeout is the maximum delay for the device node to appear
+	// after the logical volume activation.
+	devPathTimeout = 10 * time.Second
 )
 
 type (
@@ -38,6 +45,7 @@ type (
 		IsActive() (bool, error)
 		Exists() (bool, error)
 		FQN() string
+		DevPath() string
 		Devices() ([]*device.T, error)
 		DriverName() string
 	}
@@ -116,6 +124,23 @@ func (t T) Start(ctx context.Context) error {
 	actionrollback.Register(ctx, func() error {
 		return t.lv().Deactivate()
 	})
+	return t.waitDevPath(ctx)
+}
+
+// waitDevPath waits for udev to create the activated logical volume
+// device node, so the resources stacked over it can use it.
+func (t T) waitDevPath(ctx context.Context) error {
+	p := t.lv().DevPath()
+	err := waitfor.WaitFor(ctx, 100*time.Millisecond, devPathTimeout, func() (bool, error) {
+		return file.Exists(p), nil
+	}, waitfor.WithBackoff(2, time.Second), waitfor.WithOnRetry(func(attempt int, _ time.Duration) {
+		if attempt == 1 {
+			t.Log().Info().Msgf("wait for %s to appear", p)
+		}
+	}))
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
 	return nil
 }
 
@@ -232,6 +257,12 @@ func (t T) SubDevices() []*device.T {
 	}
 }
 
+// ReservableDevices returns the physical volumes of the logical volume,
+// which can be protected by a scsi persistent reservation.
+func (t T) ReservableDevices() []*device.T {
+	return t.SubDevices()
+}
+
 func (t T) Boot(ctx context.Context) error {
 	return t.Stop(ctx)
 }
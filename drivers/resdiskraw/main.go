@@ -24,11 +24,13 @@ import (
 	"opensvc.com/opensvc/util/device"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/raw"
+	"opensvc.com/opensvc/util/zone"
 )
 
 const (
 	driverGroup = drivergroup.Disk
 	driverName  = "raw"
+	capability  = "drivers.resource.disk.raw"
 )
 
 type (
@@ -40,6 +42,18 @@ type (
 		Perm              *os.FileMode `json:"perm"`
 		CreateCharDevices bool         `json:"create_char_devices"`
 		Zone              string       `json:"zone"`
+		CheckRead         bool         `json:"check_read"`
+
+		// binder is a test hook replacing the raw device driver.
+		binder binder
+	}
+
+	// binder is the raw device driver interface used by the resource.
+	binder interface {
+		Bind(bDevPath string) (int, error)
+		UnbindMinor(minor int) error
+		UnbindBDevPath(bDevPath string) error
+		FindByMajorMinor(major, minor int) (*raw.Entry, error)
 	}
 	DevPair struct {
 		Src *device.T
@@ -55,7 +69,7 @@ func capabilitiesScanner() ([]string, error) {
 	if _, err := exec.LookPath("mknod"); err != nil {
 		return []string{}, nil
 	}
-	return []string{"drivers.resource.disk.raw"}, nil
+	return []string{capability}, nil
 }
 
 func New() resource.Driver {
@@ -116,20 +130,36 @@ func (t T) Manifest() *manifest.T {
 			Text:     "The zone name the raw resource is linked to. If set, the raw files are configured from the global reparented to the zonepath.",
 			Example:  "zone1",
 		},
+		{
+			Option:    "check_read",
+			Attr:      "CheckRead",
+			Scopable:  true,
+			Converter: converters.Bool,
+			Text:      "If set to true, the status evaluation tries to read the first block of each src device, bypassing the page cache, to detect dead paths or luns. Read failures are reported as warnings. Defaults to false to avoid io on passive nodes.",
+			Example:   "true",
+		},
 	}...)
 	return m
 }
 
 func init() {
 	capabilities.Register(capabilitiesScanner)
-	resource.Register(driverGroup, driverName, New)
+	resource.Register(driverGroup, driverName, New, resource.WithCapability(capability))
 }
 
-func (t T) raw() *raw.T {
+// raw returns the raw device driver, or an error if the node is not raw
+// capable.
+func (t T) raw() (binder, error) {
+	if t.binder != nil {
+		return t.binder, nil
+	}
+	if !raw.IsCapable() {
+		return nil, fmt.Errorf("not raw capable")
+	}
 	l := raw.New(
 		raw.WithLogger(t.Log()),
 	)
-	return l
+	return l, nil
 }
 
 func (t T) devices() DevPairs {
@@ -137,8 +167,13 @@ func (t T) devices() DevPairs {
 	for _, e := range t.Devices {
 		x := strings.SplitN(e, ":", 2)
 		if len(x) == 2 {
+			dstPath, err := zone.Reparent(t.Zone, x[1])
+			if err != nil {
+				t.Log().Debug().Err(err).Msgf("reparent %s", x[1])
+				continue
+			}
 			src := device.New(x[0], device.WithLogger(t.Log()))
-			dst := device.New(x[1], device.WithLogger(t.Log()))
+			dst := device.New(dstPath, device.WithLogger(t.Log()))
 			l = l.Add(src, dst)
 			continue
 		}
@@ -171,10 +206,11 @@ func (t T) stopBlockDevice(ctx context.Context, pair DevPair) error {
 }
 
 func (t *T) statusBlockDevice(pair DevPair) (status.T, []string) {
-	p := pair.Dst.Path()
 	s, issues := t.statusCreateBlockDevice(pair)
-	issues = t.checkMode(p)
-	issues = append(issues, t.checkOwnership(p)...)
+	if s == status.NotApplicable || s == status.Down {
+		return s, issues
+	}
+	issues = append(issues, t.checkPermissions(pair.Dst.Path())...)
 	return s, issues
 }
 
@@ -196,6 +232,10 @@ func (t *T) statusCreateBlockDevice(pair DevPair) (status.T, []string) {
 		issues = append(issues, fmt.Sprintf("%s does not exist", p))
 		return status.Down, issues
 	}
+	if !file.IsBlockDevice(p) {
+		issues = append(issues, fmt.Sprintf("%s is not a block device", p))
+		return status.Warn, issues
+	}
 	if majorCur, minorCur, err := pair.Dst.MajorMinor(); err == nil {
 		switch {
 		case majorCur == major && minorCur == minor:
@@ -224,6 +264,11 @@ func (t T) startBlockDevice(ctx context.Context, pair DevPair) error {
 		return err
 	}
 	p := pair.Dst.Path()
+	return t.setPermissions(ctx, p)
+}
+
+// setPermissions applies the user, group and perm keywords to the device <p>.
+func (t T) setPermissions(ctx context.Context, p string) error {
 	if err := t.setOwnership(ctx, p); err != nil {
 		return err
 	}
@@ -243,11 +288,11 @@ func (t T) setOwnership(ctx context.Context, p string) error {
 	if err != nil {
 		return err
 	}
-	if uid != t.uid() {
+	if t.User != nil && uid != t.uid() {
 		t.Log().Info().Msgf("set %s user to %d (%s)", p, t.uid(), t.User.Username)
 		newUID = t.uid()
 	}
-	if gid != t.gid() {
+	if t.Group != nil && gid != t.gid() {
 		t.Log().Info().Msgf("set %s group to %d (%s)", p, t.gid(), t.Group.Name)
 		newGID = t.gid()
 	}
@@ -305,12 +350,17 @@ func (t *T) checkOwnership(p string) []string {
 	if t.User != nil && uid != t.uid() {
 		return []string{fmt.Sprintf("%s user should be %s (%s) but is %d", p, t.User.Uid, t.User.Username, uid)}
 	}
-	if t.Group == nil && gid != t.gid() {
-		return []string{fmt.Sprintf("%s group should be %s (%s) but is %d", p, t.User.Gid, t.Group.Name, gid)}
+	if t.Group != nil && gid != t.gid() {
+		return []string{fmt.Sprintf("%s group should be %s (%s) but is %d", p, t.Group.Gid, t.Group.Name, gid)}
 	}
 	return []string{}
 }
 
+// checkPermissions returns the mode and ownership issues of the device <p>.
+func (t *T) checkPermissions(p string) []string {
+	return append(t.checkMode(p), t.checkOwnership(p)...)
+}
+
 func (t T) setMode(ctx context.Context, p string) error {
 	if t.Perm == nil {
 		return nil
@@ -329,7 +379,7 @@ func (t T) setMode(ctx context.Context, p string) error {
 	}
 	actionrollback.Register(ctx, func() error {
 		t.Log().Info().Msgf("set %s mode back to %s", p, mode)
-		return os.Chmod(p, currentMode&os.ModeType)
+		return os.Chmod(p, currentMode)
 	})
 	return nil
 }
@@ -340,6 +390,9 @@ func (t T) createBlockDevice(ctx context.Context, pair DevPair) error {
 		return err
 	}
 	p := pair.Dst.Path()
+	if file.Exists(p) && !file.IsBlockDevice(p) {
+		return fmt.Errorf("%s already exists and is not a block device", p)
+	}
 	if file.Exists(p) {
 		if majorCur, minorCur, err := pair.Dst.MajorMinor(); err == nil {
 			switch {
@@ -391,33 +444,44 @@ func (t T) startCharDevices(ctx context.Context) error {
 	if !t.CreateCharDevices {
 		return nil
 	}
-	ra := t.raw()
-	if !raw.IsCapable() {
-		return fmt.Errorf("not raw capable")
+	ra, err := t.raw()
+	if err != nil {
+		return err
 	}
 	for _, pair := range t.devices() {
-		minor, err := ra.Bind(pair.Src.Path())
-		switch {
-		case errors.Is(err, raw.ErrExist):
-			t.Log().Info().Msgf("%s", err)
-			return nil
-		case err != nil:
+		if err := t.startCharDevice(ctx, ra, pair); err != nil {
 			return err
-		default:
-			actionrollback.Register(ctx, func() error {
-				return ra.UnbindMinor(minor)
-			})
 		}
 	}
 	return nil
 }
 
+//
+// startCharDevice binds a raw device to the src device of <pair>, and
+// registers its unbind as a rollback. A binding found already in place is
+// not rolled back, as it was not created by this action.
+//
+func (t T) startCharDevice(ctx context.Context, ra binder, pair DevPair) error {
+	minor, err := ra.Bind(pair.Src.Path())
+	switch {
+	case errors.Is(err, raw.ErrExist):
+		t.Log().Info().Msgf("%s", err)
+	case err != nil:
+		return err
+	default:
+		actionrollback.Register(ctx, func() error {
+			return ra.UnbindMinor(minor)
+		})
+	}
+	return t.setPermissions(ctx, raw.CDevPath(minor))
+}
+
 func (t T) stopCharDevices(ctx context.Context) error {
 	if !t.CreateCharDevices {
 		return nil
 	}
-	ra := t.raw()
-	if !raw.IsCapable() {
+	ra, err := t.raw()
+	if err != nil {
 		return nil
 	}
 	for _, pair := range t.devices() {
@@ -451,14 +515,28 @@ func (t *T) statusCharDevices() status.T {
 	if !t.CreateCharDevices {
 		return s
 	}
-	ra := t.raw()
+	ra, err := t.raw()
+	if err != nil {
+		t.StatusLog().Warn("%s", err)
+		return status.Undef
+	}
 	for _, pair := range t.devices() {
-		v, err := ra.Has(pair.Src.Path())
+		major, minor, err := pair.Src.MajorMinor()
+		if err != nil {
+			t.StatusLog().Warn("%s", err)
+			continue
+		}
+		e, err := ra.FindByMajorMinor(int(major), int(minor))
 		if err != nil {
 			t.StatusLog().Warn("%s", err)
 			continue
 		}
-		if v {
+		if e != nil {
+			p := e.CDevPath()
+			t.StatusLog().Info("%s bound to %s", pair.Src.Path(), p)
+			for _, issue := range t.checkPermissions(p) {
+				t.StatusLog().Warn("%s", issue)
+			}
 			s.Add(status.Up)
 		} else {
 			if dev := pair.Src.Path(); len(dev) > 0 {
@@ -475,7 +553,40 @@ func (t *T) statusCharDevices() status.T {
 	return s
 }
 
+func (t *T) statusRead() status.T {
+	s := status.NotApplicable
+	if !t.CheckRead {
+		return s
+	}
+	for _, pair := range t.devices() {
+		if pair.Src == nil || pair.Src.Path() == "" {
+			continue
+		}
+		elapsed, err := pair.Src.CheckRead()
+		switch {
+		case errors.Is(err, device.ErrNotApplicable):
+			continue
+		case err != nil:
+			t.StatusLog().Warn("%s read failed after %s: %s", pair.Src, elapsed, err)
+			s.Add(status.Warn)
+		default:
+			t.Log().Debug().Msgf("%s read in %s", pair.Src, elapsed)
+		}
+	}
+	return s
+}
+
+// checkZone verifies the zone root is found, so the dst device paths can
+// be reparented.
+func (t T) checkZone() error {
+	_, err := zone.Reparent(t.Zone, "/")
+	return err
+}
+
 func (t T) Start(ctx context.Context) error {
+	if err := t.checkZone(); err != nil {
+		return err
+	}
 	if err := t.startCharDevices(ctx); err != nil {
 		return err
 	}
@@ -486,6 +597,9 @@ func (t T) Start(ctx context.Context) error {
 }
 
 func (t T) Stop(ctx context.Context) error {
+	if err := t.checkZone(); err != nil {
+		return err
+	}
 	if err := t.stopBlockDevices(ctx); err != nil {
 		return err
 	}
@@ -499,8 +613,13 @@ func (t *T) Status(ctx context.Context) status.T {
 	if len(t.Devices) == 0 {
 		return status.NotApplicable
 	}
+	if err := t.checkZone(); err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	}
 	s := t.statusCharDevices()
 	s.Add(t.statusBlockDevices())
+	s.Add(t.statusRead())
 	return s
 }
 
@@ -537,6 +656,22 @@ func (t T) ExposedDevices() []*device.T {
 	return l
 }
 
+// BaseDevices returns the source devices, so the resources providing
+// these devices are provisioned and started first.
+func (t T) BaseDevices() []*device.T {
+	l := make([]*device.T, 0)
+	for _, pair := range t.devices() {
+		l = append(l, pair.Src)
+	}
+	return l
+}
+
+// ReservableDevices returns the source devices, which can be protected
+// by a scsi persistent reservation.
+func (t T) ReservableDevices() []*device.T {
+	return t.BaseDevices()
+}
+
 func NewDevPairs() DevPairs {
 	return DevPairs(make([]DevPair, 0))
 }
@@ -0,0 +1,107 @@
+package resdiskraw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/opensvc/testhelper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/util/raw"
+)
+
+type fakeBinder struct {
+	bound  map[string]int
+	failOn string
+	next   int
+}
+
+func newFakeBinder() *fakeBinder {
+	return &fakeBinder{bound: make(map[string]int)}
+}
+
+func (t *fakeBinder) Bind(bDevPath string) (int, error) {
+	if bDevPath == t.failOn {
+		return 0, errors.New("simulated bind failure")
+	}
+	if minor, ok := t.bound[bDevPath]; ok {
+		return minor, fmt.Errorf("%s: %w", bDevPath, raw.ErrExist)
+	}
+	t.next++
+	t.bound[bDevPath] = t.next
+	return t.next, nil
+}
+
+func (t *fakeBinder) UnbindMinor(minor int) error {
+	for p, m := range t.bound {
+		if m == minor {
+			delete(t.bound, p)
+		}
+	}
+	return nil
+}
+
+func (t *fakeBinder) UnbindBDevPath(bDevPath string) error {
+	delete(t.bound, bDevPath)
+	return nil
+}
+
+func (t *fakeBinder) FindByMajorMinor(major, minor int) (*raw.Entry, error) {
+	return nil, nil
+}
+
+func prepareDevices(t *testing.T, n int) ([]string, func()) {
+	td, cleanup := testhelper.Tempdir(t)
+	l := make([]string, n)
+	for i := range l {
+		l[i] = filepath.Join(td, fmt.Sprintf("dev%d", i))
+		require.NoError(t, ioutil.WriteFile(l[i], []byte{}, 0600))
+	}
+	return l, cleanup
+}
+
+func TestStartCharDevices(t *testing.T) {
+	t.Run("rollback unbinds the devices bound before a failure", func(t *testing.T) {
+		devs, cleanup := prepareDevices(t, 3)
+		defer cleanup()
+		b := newFakeBinder()
+		b.failOn = devs[2]
+		r := &T{Devices: devs, CreateCharDevices: true, binder: b}
+		ctx := actionrollback.NewContext(context.Background())
+		assert.Error(t, r.Start(ctx))
+		assert.Len(t, b.bound, 2)
+		require.NoError(t, actionrollback.Rollback(ctx))
+		assert.Len(t, b.bound, 0)
+	})
+
+	t.Run("rollback preserves the bindings found in place", func(t *testing.T) {
+		devs, cleanup := prepareDevices(t, 3)
+		defer cleanup()
+		b := newFakeBinder()
+		b.bound[devs[0]] = 10
+		b.failOn = devs[2]
+		r := &T{Devices: devs, CreateCharDevices: true, binder: b}
+		ctx := actionrollback.NewContext(context.Background())
+		assert.Error(t, r.Start(ctx))
+		require.NoError(t, actionrollback.Rollback(ctx))
+		assert.Equal(t, map[string]int{devs[0]: 10}, b.bound)
+	})
+
+	t.Run("stop unbinds all the devices bound by start", func(t *testing.T) {
+		devs, cleanup := prepareDevices(t, 3)
+		defer cleanup()
+		b := newFakeBinder()
+		b.bound[devs[1]] = 10
+		r := &T{Devices: devs, CreateCharDevices: true, binder: b}
+		ctx := actionrollback.NewContext(context.Background())
+		require.NoError(t, r.Start(ctx))
+		assert.Len(t, b.bound, 3)
+		require.NoError(t, r.Stop(ctx))
+		assert.Len(t, b.bound, 0)
+	})
+}
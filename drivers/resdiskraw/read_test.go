@@ -0,0 +1,52 @@
+// +build linux
+
+package resdiskraw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/command"
+)
+
+func TestStatusRead(t *testing.T) {
+	devs, cleanup := prepareDevices(t, 2)
+	defer cleanup()
+	fake := command.NewFake()
+	restore := command.SetExecutor(fake)
+	defer restore()
+
+	t.Run("disabled check does not read", func(t *testing.T) {
+		r := &T{Devices: devs}
+		assert.Equal(t, status.NotApplicable, r.statusRead())
+		assert.Empty(t, fake.Calls())
+	})
+
+	t.Run("successful reads", func(t *testing.T) {
+		fake.Reset()
+		fake.Set("dd", command.Result{})
+		r := &T{Devices: devs, CheckRead: true}
+		assert.Equal(t, status.NotApplicable, r.statusRead())
+		assert.Empty(t, r.StatusLog().Entries())
+		calls := fake.CallsOf("dd")
+		require.Len(t, calls, 2)
+		assert.Equal(t, "dd if="+devs[0]+" of=/dev/null bs=4096 count=1 iflag=direct", calls[0].String())
+	})
+
+	t.Run("failed read warns", func(t *testing.T) {
+		fake.Reset()
+		fake.Set("dd", command.Result{
+			Stderr:   []byte("dd: error reading '" + devs[0] + "': Input/output error\n"),
+			ExitCode: 1,
+		})
+		r := &T{Devices: devs[:1], CheckRead: true}
+		assert.Equal(t, status.Warn, r.statusRead())
+		entries := r.StatusLog().Entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "warn", string(entries[0].Level))
+		assert.Contains(t, entries[0].Message, devs[0]+" read failed")
+		assert.Contains(t, entries[0].Message, "Input/output error")
+	})
+}
@@ -0,0 +1,218 @@
+package resdiskscsireserv
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/resdisk"
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/device"
+	"opensvc.com/opensvc/util/scsi"
+)
+
+const (
+	driverGroup = drivergroup.Disk
+	driverName  = "scsireserv"
+	capability  = "drivers.resource.disk.scsireserv"
+)
+
+type (
+	// T is the disk.scsireserv driver, claiming a scsi3 persistent
+	// reservation on the devices of other resources of the object.
+	T struct {
+		resource.T
+		RIDs           []string `json:"rids"`
+		PRKey          string   `json:"prkey"`
+		NoPreemptAbort bool     `json:"no_preempt_abort"`
+	}
+
+	resourceLister interface {
+		Resources() resource.Drivers
+	}
+)
+
+func capabilitiesScanner() ([]string, error) {
+	if _, err := exec.LookPath("sg_persist"); err != nil {
+		return []string{}, nil
+	}
+	return []string{capability}, nil
+}
+
+func New() resource.Driver {
+	t := &T{}
+	return t
+}
+
+// Manifest exposes to the core the input expected by the driver.
+func (t T) Manifest() *manifest.T {
+	m := manifest.New(driverGroup, driverName, t)
+	m.AddKeyword(resdisk.KWPRKey, resdisk.KWNoPreemptAbort)
+	m.AddKeyword([]keywords.Keyword{
+		{
+			Option:    "rids",
+			Attr:      "RIDs",
+			Required:  true,
+			Scopable:  true,
+			Converter: converters.List,
+			Text:      "The list of resource ids whose devices are claimed by the scsi3 persistent reservation. The reservable devices of a resource are used if the driver defines them, its exposed devices otherwise.",
+			Example:   "disk#0 disk#1",
+		},
+	}...)
+	return m
+}
+
+func init() {
+	capabilities.Register(capabilitiesScanner)
+	resource.Register(driverGroup, driverName, New, resource.WithCapability(capability))
+}
+
+// key returns the resource prkey if set, the node prkey otherwise.
+func (t T) key() (string, error) {
+	if t.PRKey != "" {
+		return t.PRKey, nil
+	}
+	return object.NewNode().PRKey()
+}
+
+func (t T) handles() ([]scsi.PersistentReservationHandle, error) {
+	key, err := t.key()
+	if err != nil {
+		return nil, err
+	}
+	devs := t.ClaimedDevices()
+	l := make([]scsi.PersistentReservationHandle, len(devs))
+	for i, dev := range devs {
+		l[i] = scsi.PersistentReservationHandle{
+			Key:            key,
+			Device:         dev.Path(),
+			NoPreemptAbort: t.NoPreemptAbort,
+			Log:            t.Log(),
+		}
+	}
+	return l, nil
+}
+
+// Abort vetoes the start if a device is reserved by another node. The
+// veto is bypassed by --force, in which case the start preempts the
+// reservation.
+func (t T) Abort(ctx context.Context) error {
+	l, err := t.handles()
+	if err != nil {
+		return err
+	}
+	for _, h := range l {
+		holder, err := h.ForeignHolder()
+		if err != nil {
+			return err
+		}
+		if holder != "" {
+			return resource.NewErrAbortStart(resource.AbortReservationConflict, "%s is reserved by %s", h.Device, holder)
+		}
+	}
+	return nil
+}
+
+func (t T) Start(ctx context.Context) error {
+	l, err := t.handles()
+	if err != nil {
+		return err
+	}
+	for _, h := range l {
+		if err := h.Start(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t T) Stop(ctx context.Context) error {
+	l, err := t.handles()
+	if err != nil {
+		return err
+	}
+	for _, h := range l {
+		if err := h.Stop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	l, err := t.handles()
+	if err != nil {
+		t.StatusLog().Warn("%s", err)
+		return status.Undef
+	}
+	if len(l) == 0 {
+		return status.NotApplicable
+	}
+	s := status.NotApplicable
+	for _, h := range l {
+		devStatus, issues := h.Status()
+		for _, issue := range issues {
+			t.StatusLog().Warn("%s", issue)
+		}
+		s.Add(devStatus)
+	}
+	return s
+}
+
+func (t T) Provisioned() (provisioned.T, error) {
+	return provisioned.NotApplicable, nil
+}
+
+func (t T) Label() string {
+	return strings.Join(t.RIDs, " ")
+}
+
+func (t T) Info() map[string]string {
+	m := make(map[string]string)
+	m["rids"] = t.Label()
+	return m
+}
+
+//
+// ClaimedDevices returns the devices of the resources referenced by the
+// rids keyword. The devices are protected by the reservation, but not
+// stacked on.
+//
+func (t T) ClaimedDevices() []*device.T {
+	l := make([]*device.T, 0)
+	o, ok := t.GetObjectDriver().(resourceLister)
+	if !ok {
+		return l
+	}
+	seen := make(map[string]interface{})
+	add := func(devs []*device.T) {
+		for _, dev := range devs {
+			if _, ok := seen[dev.Path()]; ok {
+				continue
+			}
+			seen[dev.Path()] = nil
+			l = append(l, dev)
+		}
+	}
+	for _, rid := range t.RIDs {
+		for _, r := range o.Resources() {
+			if r.RID() != rid {
+				continue
+			}
+			if i, ok := r.(resource.ReservableDeviceser); ok {
+				add(i.ReservableDevices())
+			} else if i, ok := r.(resource.ExposedDeviceser); ok {
+				add(i.ExposedDevices())
+			}
+		}
+	}
+	return l
+}
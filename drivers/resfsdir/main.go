@@ -16,6 +16,7 @@ import (
 	"opensvc.com/opensvc/core/status"
 	"opensvc.com/opensvc/util/converters"
 	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/zone"
 )
 
 const (
@@ -83,7 +84,7 @@ func (t T) Manifest() *manifest.T {
 			Option:   "zone",
 			Attr:     "Zone",
 			Scopable: true,
-			Text:     "The zone name the fs refers to. If set, the fs mount point is reparented into the zonepath rootfs.",
+			Text:     "The zone name the directory refers to. If set, the directory path is reparented into the zonepath rootfs.",
 		},
 	}...)
 	return m
@@ -107,11 +108,15 @@ func (t T) Stop(ctx context.Context) error {
 }
 
 func (t *T) Status(ctx context.Context) status.T {
-	p := t.path()
-	if p == "" {
+	if t.Path == "" {
 		t.StatusLog().Error("path is not defined")
 		return status.Undef
 	}
+	p, err := t.zonePath()
+	if err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	}
 	if !file.ExistsAndDir(p) {
 		t.Log().Debug().Msgf("dir does not exist: %s", p)
 		return status.Down
@@ -128,8 +133,20 @@ func (t T) Label() string {
 	return t.path()
 }
 
+// path returns the directory path, reparented into the zone root if the
+// zone keyword is set, or an empty string if the zone root is not found.
 func (t T) path() string {
-	return t.Path
+	p, _ := t.zonePath()
+	return p
+}
+
+func (t T) zonePath() (string, error) {
+	return zone.Reparent(t.Zone, t.Path)
+}
+
+// Head returns the directory path.
+func (t T) Head() string {
+	return t.path()
 }
 
 func (t T) Provision(ctx context.Context) error {
@@ -145,7 +162,10 @@ func (t T) Provisioned() (provisioned.T, error) {
 }
 
 func (t T) create(ctx context.Context) error {
-	p := t.path()
+	p, err := t.zonePath()
+	if err != nil {
+		return err
+	}
 	if file.ExistsAndDir(p) {
 		return nil
 	}
@@ -212,8 +232,8 @@ func (t T) setOwnership(ctx context.Context) error {
 			return err
 		}
 		actionrollback.Register(ctx, func() error {
-			t.Log().Info().Msgf("set %s group back to %s", p, gid)
-			t.Log().Info().Msgf("set %s user back to %s", p, uid)
+			t.Log().Info().Msgf("set %s group back to %d", p, gid)
+			t.Log().Info().Msgf("set %s user back to %d", p, uid)
 			return os.Chown(p, uid, gid)
 		})
 	}
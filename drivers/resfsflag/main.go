@@ -14,19 +14,19 @@ import (
 )
 
 func init() {
-	resource.Register(driverGroup, driverName, New)
+	resource.Register(driverGroup, driverName, New, resource.WithCapability(capability))
 }
 
 func New() resource.Driver {
 	return &T{}
 }
 
-func (t T) Abort(ctx context.Context) bool {
+func (t T) Abort(ctx context.Context) error {
 	if len(t.Nodes) > 1 {
 		// TODO
-		return true
+		return resource.NewErrAbortStart(resource.AbortConfig, "multi-node flag is not supported")
 	}
-	return false
+	return nil
 }
 
 // Start the Resource
@@ -135,3 +135,9 @@ func main() {
 	}
 	resource.Action(context.TODO(), r)
 }
+
+// Boot removes the flag file left over by a previous node boot, in case
+// the flag directory is not on a volatile filesystem.
+func (t T) Boot(ctx context.Context) error {
+	return t.Stop(ctx)
+}
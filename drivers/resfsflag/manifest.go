@@ -10,6 +10,7 @@ import (
 const (
 	driverGroup = drivergroup.FS
 	driverName  = "flag"
+	capability  = "drivers.resource.fs.flag"
 )
 
 // T is the driver structure.
@@ -7,7 +7,7 @@ import (
 )
 
 func capabilitiesScanner() ([]string, error) {
-	return []string{"drivers.resource.fs.flag"}, nil
+	return []string{capability}, nil
 }
 
 func init() {
@@ -21,7 +21,8 @@ import (
 	"opensvc.com/opensvc/util/device"
 	"opensvc.com/opensvc/util/file"
 	"opensvc.com/opensvc/util/filesystems"
-	"opensvc.com/opensvc/util/findmnt"
+	"opensvc.com/opensvc/util/mountinfo"
+	"opensvc.com/opensvc/util/zone"
 )
 
 const (
@@ -270,6 +271,9 @@ func (t T) Start(ctx context.Context) error {
 }
 
 func (t T) Stop(ctx context.Context) error {
+	if _, err := t.zoneMountPoint(); err != nil {
+		return err
+	}
 	if v, err := t.isMounted(); err != nil {
 		return err
 	} else if !v {
@@ -291,6 +295,10 @@ func (t *T) Status(ctx context.Context) status.T {
 		t.StatusLog().Info("mnt is not defined")
 		return status.NotApplicable
 	}
+	if _, err := t.zoneMountPoint(); err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	}
 	if v, err := t.isMounted(); err != nil {
 		t.StatusLog().Error("%s", err)
 		return status.Undef
@@ -334,6 +342,7 @@ func (t T) fsDir() *resfsdir.T {
 	r.SetRID(t.RID())
 	r.SetObjectDriver(t.GetObjectDriver())
 	r.Path = t.MountPoint
+	r.Zone = t.Zone
 	r.User = t.User
 	r.Group = t.Group
 	r.Perm = t.Perm
@@ -349,15 +358,36 @@ func (t T) mountOptions() string {
 	return t.MountOptions
 }
 
+// Head returns the fs mount point.
+func (t T) Head() string {
+	return t.mountPoint()
+}
+
+// mountPoint returns the mount point, reparented into the zone root if
+// the zone keyword is set, or an empty string if the zone root is not
+// found.
 func (t T) mountPoint() string {
-	// add zonepath translation, and cache ?
-	return filepath.Clean(t.MountPoint)
+	p, _ := t.zoneMountPoint()
+	return p
+}
+
+func (t T) zoneMountPoint() (string, error) {
+	return zone.Reparent(t.Zone, filepath.Clean(t.MountPoint))
 }
 
 func (t T) device() *device.T {
 	return device.New(t.devpath(), device.WithLogger(t.Log()))
 }
 
+// BaseDevices returns the device the filesystem is created on, so the
+// resource providing this device is provisioned and started first.
+func (t T) BaseDevices() []*device.T {
+	if t.Device == "" {
+		return []*device.T{}
+	}
+	return []*device.T{t.device()}
+}
+
 func (t T) devpath() string {
 	// lazy ref
 	switch {
@@ -385,6 +415,9 @@ func (t *T) mount(ctx context.Context) error {
 	if err := t.validateDevice(); err != nil {
 		return err
 	}
+	if _, err := t.zoneMountPoint(); err != nil {
+		return err
+	}
 	if err := t.promoteDevicesReadWrite(ctx); err != nil {
 		return err
 	}
@@ -410,15 +443,16 @@ func (t *T) mount(ctx context.Context) error {
 }
 
 func (t *T) createMountPoint(ctx context.Context) error {
-	if file.ExistsAndDir(t.MountPoint) {
+	p := t.mountPoint()
+	if file.ExistsAndDir(p) {
 		return nil
 	}
-	if file.Exists(t.MountPoint) {
-		return fmt.Errorf("mountpoint %s already exists but is not a directory", t.MountPoint)
+	if file.Exists(p) {
+		return fmt.Errorf("mountpoint %s already exists but is not a directory", p)
 	}
-	t.Log().Info().Msgf("create missing mountpoint %s", t.MountPoint)
-	if err := os.MkdirAll(t.MountPoint, 0755); err != nil {
-		return fmt.Errorf("error creating mountpoint %s: %s", t.MountPoint, err)
+	t.Log().Info().Msgf("create missing mountpoint %s", p)
+	if err := os.MkdirAll(p, 0755); err != nil {
+		return fmt.Errorf("error creating mountpoint %s: %s", p, err)
 	}
 	return nil
 }
@@ -515,7 +549,7 @@ func (t *T) fsck() error {
 }
 
 func (t *T) isMounted() (bool, error) {
-	return findmnt.Has(t.devpath(), t.mountPoint())
+	return mountinfo.IsMounted(t.devpath(), t.mountPoint())
 }
 
 func (t *T) ProvisionLeader(ctx context.Context) error {
@@ -525,15 +559,17 @@ func (t *T) ProvisionLeader(ctx context.Context) error {
 		t.Log().Info().Msgf("skip mkfs, formatted detection is not implemented for type %s", fs)
 		return nil
 	}
-	if v, err := i1.IsFormated(t.Device); err != nil {
+	dev := t.devpath()
+	if v, err := i1.IsFormated(dev); err != nil {
 		t.Log().Warn().Msgf("skip mkfs: %s", err)
+		return nil
 	} else if v {
 		t.Log().Info().Msgf("%s is already formated", fs)
 		return nil
 	}
 	i2, ok := fs.(filesystems.MKFSer)
 	if ok {
-		return i2.MKFS(t.Device, t.MKFSOptions)
+		return i2.MKFS(dev, t.MKFSOptions)
 	}
 	t.Log().Info().Msgf("skip mkfs, not implemented for type %s", fs)
 	return nil
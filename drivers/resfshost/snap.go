@@ -0,0 +1,82 @@
+package resfshost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/filesystems"
+)
+
+// snapPrefix is prepended to the snapshot names, so the snapshots not
+// created by the agent are ignored.
+const snapPrefix = "osvc_"
+
+//
+// snapshotter returns the snapshot backend of the filesystem: the
+// filesystem native snapshots if supported, the logical volume snapshots
+// if the device is a logical volume.
+//
+func (t T) snapshotter() (filesystems.Snapshotter, error) {
+	fs := t.fs()
+	if i, ok := fs.(filesystems.Snapshotter); ok {
+		return i, nil
+	}
+	if i, err := t.lvSnapshotter(); err != nil {
+		return nil, err
+	} else if i != nil {
+		return i, nil
+	}
+	return nil, fmt.Errorf("%s on %s: %w", fs, t.devpath(), resource.ErrSnapNotSupported)
+}
+
+// SnapCreate creates the <name> snapshot of the filesystem.
+func (t *T) SnapCreate(ctx context.Context, name string) error {
+	i, err := t.snapshotter()
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("create snapshot %s", name)
+	return i.SnapCreate(t.devpath(), t.mountPoint(), snapPrefix+name)
+}
+
+// SnapRollback restores the filesystem data from the <name> snapshot.
+func (t *T) SnapRollback(ctx context.Context, name string) error {
+	i, err := t.snapshotter()
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("rollback to snapshot %s", name)
+	return i.SnapRollback(t.devpath(), t.mountPoint(), snapPrefix+name)
+}
+
+// SnapRemove removes the <name> snapshot of the filesystem.
+func (t *T) SnapRemove(ctx context.Context, name string) error {
+	i, err := t.snapshotter()
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("remove snapshot %s", name)
+	return i.SnapRemove(t.devpath(), t.mountPoint(), snapPrefix+name)
+}
+
+// Snaps returns the snapshots of the filesystem, oldest first.
+func (t *T) Snaps(ctx context.Context) ([]resource.Snap, error) {
+	l := make([]resource.Snap, 0)
+	i, err := t.snapshotter()
+	if err != nil {
+		return l, err
+	}
+	snaps, err := i.Snaps(t.devpath(), t.mountPoint())
+	if err != nil {
+		return l, err
+	}
+	for _, snap := range snaps {
+		if !strings.HasPrefix(snap.Name, snapPrefix) {
+			continue
+		}
+		l = append(l, resource.Snap{RID: t.RID(), Name: strings.TrimPrefix(snap.Name, snapPrefix), Created: snap.Created})
+	}
+	return l, nil
+}
@@ -0,0 +1,94 @@
+// +build linux
+
+package resfshost
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/filesystems"
+	"opensvc.com/opensvc/util/lvm2"
+)
+
+type (
+	// lvSnapshotter implements the filesystem snapshots with snapshot
+	// logical volumes named <lv>_<name>.
+	lvSnapshotter struct {
+		lv   *lvm2.LV
+		size string
+		log  *zerolog.Logger
+	}
+)
+
+// lvTimeFormat is the lv_time format of the lvs reports.
+const lvTimeFormat = "2006-01-02 15:04:05 -0700"
+
+//
+// lvSnapshotter returns the logical volume snapshot backend if the
+// device is a /dev/<vg>/<lv> logical volume, nil otherwise. The snapshot
+// size is set by the snap_size keyword, and defaults to 10% of the
+// logical volume size.
+//
+func (t T) lvSnapshotter() (filesystems.Snapshotter, error) {
+	l := strings.Split(strings.TrimPrefix(filepath.Clean(t.devpath()), "/dev/"), "/")
+	if len(l) != 2 || l[0] == "mapper" {
+		return nil, nil
+	}
+	lv := lvm2.NewLV(l[0], l[1], lvm2.WithLogger(t.Log()))
+	if v, err := lv.Exists(); err != nil || !v {
+		return nil, nil
+	}
+	var size uint64
+	if t.SnapSize != nil {
+		size = uint64(*t.SnapSize)
+	} else {
+		devSize, err := t.device().Size()
+		if err != nil {
+			return nil, err
+		}
+		size = devSize / 10
+	}
+	return lvSnapshotter{lv: lv, size: fmt.Sprintf("%dB", size), log: t.Log()}, nil
+}
+
+func (t lvSnapshotter) snapName(name string) string {
+	return t.lv.LVName + "_" + name
+}
+
+func (t lvSnapshotter) snapLV(name string) *lvm2.LV {
+	return lvm2.NewLV(t.lv.VGName, t.snapName(name), lvm2.WithLogger(t.log))
+}
+
+func (t lvSnapshotter) SnapCreate(dev string, mnt string, name string) error {
+	return t.lv.Snapshot(t.snapName(name), t.size)
+}
+
+func (t lvSnapshotter) SnapRollback(dev string, mnt string, name string) error {
+	return t.snapLV(name).Merge()
+}
+
+func (t lvSnapshotter) SnapRemove(dev string, mnt string, name string) error {
+	return t.snapLV(name).Remove([]string{"-f"})
+}
+
+func (t lvSnapshotter) Snaps(dev string, mnt string) ([]filesystems.Snap, error) {
+	l := make([]filesystems.Snap, 0)
+	infos, err := t.lv.Snapshots()
+	if err != nil {
+		return l, err
+	}
+	prefix := t.snapName("")
+	for _, info := range infos {
+		if !strings.HasPrefix(info.LVName, prefix) {
+			continue
+		}
+		created, _ := time.Parse(lvTimeFormat, info.LVTime)
+		l = append(l, filesystems.Snap{Name: strings.TrimPrefix(info.LVName, prefix), Created: created})
+	}
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Created.Before(l[j].Created) })
+	return l, nil
+}
@@ -0,0 +1,11 @@
+// +build !linux
+
+package resfshost
+
+import "opensvc.com/opensvc/util/filesystems"
+
+// lvSnapshotter returns nil: the logical volume snapshots are only
+// supported on linux.
+func (t T) lvSnapshotter() (filesystems.Snapshotter, error) {
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package resiphost
+
+import (
+	"context"
+	"fmt"
+
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/network"
+	"opensvc.com/opensvc/core/object"
+)
+
+const (
+	provisionerIPAM = "ipam"
+)
+
+type (
+	keywordSetter interface {
+		SetKeywords([]string) error
+		Unset(object.OptsUnset) error
+	}
+)
+
+// ipamOwner returns the owner recorded in the network leases.
+func (t T) ipamOwner() string {
+	return t.Path.String() + " " + t.RID()
+}
+
+func (t T) ipamNetwork() (*network.T, error) {
+	if t.Network == "" {
+		return nil, fmt.Errorf("the network keyword must be set to a network name with provisioner=%s", provisionerIPAM)
+	}
+	return object.NewNode().Network(t.Network)
+}
+
+func (t T) keywordSetter() (keywordSetter, error) {
+	o, ok := t.GetObjectDriver().(keywordSetter)
+	if !ok {
+		return nil, fmt.Errorf("the object does not support keyword changes")
+	}
+	return o, nil
+}
+
+//
+// ipamAllocate allocates an address from the network, and sets it as
+// the ipname and, if not set, the netmask of the resource. The ipname
+// already set, for example by an operator, is kept.
+//
+func (t *T) ipamAllocate(ctx context.Context) error {
+	if t.IpName != "" {
+		t.Log().Debug().Msgf("ipname is already set to %s, skip allocation", t.IpName)
+		return nil
+	}
+	n, err := t.ipamNetwork()
+	if err != nil {
+		return err
+	}
+	o, err := t.keywordSetter()
+	if err != nil {
+		return err
+	}
+	ip, err := n.Allocate(t.ipamOwner())
+	if err != nil {
+		return err
+	}
+	t.Log().Info().Msgf("allocated %s from network %s", ip, n.Name)
+	kws := []string{t.RID() + ".ipname=" + ip.String()}
+	if t.Netmask == "" {
+		ones, _ := n.Network.Mask.Size()
+		kws = append(kws, fmt.Sprintf("%s.netmask=%d", t.RID(), ones))
+	}
+	if err := o.SetKeywords(kws); err != nil {
+		_, _ = n.Release(t.ipamOwner())
+		return err
+	}
+	t.IpName = ip.String()
+	actionrollback.Register(ctx, func() error {
+		return t.ipamRelease()
+	})
+	return nil
+}
+
+// ipamRelease releases the address leased to the resource, and unsets
+// the ipname.
+func (t *T) ipamRelease() error {
+	n, err := t.ipamNetwork()
+	if err != nil {
+		return err
+	}
+	released, err := n.Release(t.ipamOwner())
+	if err != nil {
+		return err
+	}
+	if len(released) == 0 {
+		return nil
+	}
+	for _, ip := range released {
+		t.Log().Info().Msgf("released %s to network %s", ip, n.Name)
+	}
+	o, err := t.keywordSetter()
+	if err != nil {
+		return err
+	}
+	t.IpName = ""
+	return o.Unset(object.OptsUnset{Keywords: []string{t.RID() + ".ipname"}})
+}
@@ -9,11 +9,11 @@ import (
 	"strings"
 	"time"
 
-	"opensvc.com/opensvc/core/actioncontext"
 	"opensvc.com/opensvc/core/actionrollback"
 	"opensvc.com/opensvc/core/drivergroup"
 	"opensvc.com/opensvc/core/keywords"
 	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/provisioned"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/status"
@@ -21,6 +21,7 @@ import (
 	"opensvc.com/opensvc/util/fqdn"
 	"opensvc.com/opensvc/util/hostname"
 	"opensvc.com/opensvc/util/netif"
+	"opensvc.com/opensvc/util/zone"
 
 	"github.com/go-ping/ping"
 )
@@ -37,6 +38,9 @@ type (
 	T struct {
 		resource.T
 
+		// context
+		Path path.T `json:"path"`
+
 		// config
 		IpName        string   `json:"ipname"`
 		IpDev         string   `json:"ipdev"`
@@ -50,6 +54,7 @@ type (
 		CheckCarrier  bool     `json:"check_carrier"`
 		Alias         bool     `json:"alias"`
 		Expose        []string `json:"expose"`
+		Zone          string   `json:"zone"`
 
 		// cache
 		_ipaddr net.IP
@@ -72,6 +77,13 @@ func New() resource.Driver {
 // Manifest exposes to the core the input expected by the driver.
 func (t T) Manifest() *manifest.T {
 	m := manifest.New(driverGroup, driverName, t)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+	}...)
 	m.AddKeyword([]keywords.Keyword{
 		{
 			Option:   "ipname",
@@ -119,9 +131,9 @@ func (t T) Manifest() *manifest.T {
 			Option:       "provisioner",
 			Attr:         "Provisioner",
 			Scopable:     true,
-			Candidates:   []string{"collector", ""},
+			Candidates:   []string{"collector", provisionerIPAM, ""},
 			Example:      "collector",
-			Text:         "The IPAM driver to use to provision the ip.",
+			Text:         "The IPAM driver to use to provision the ip. With ``ipam``, the address is allocated from the cluster network named by :kw:`network`, set as :kw:`ipname`, and released on unprovision.",
 			Provisioning: true,
 		},
 		{
@@ -129,7 +141,7 @@ func (t T) Manifest() *manifest.T {
 			Attr:         "Network",
 			Scopable:     true,
 			Example:      "10.0.0.0/16",
-			Text:         "The network, in dotted notation, from where the ip provisioner allocates. Also used by the docker ip driver to delete the network route if :kw:`del_net_route` is set to ``true``.",
+			Text:         "The network, in dotted notation, from where the ip provisioner allocates. With :kw:`provisioner` set to ``ipam``, the name of the ``network#<name>`` node or cluster configuration section to allocate from. Also used by the docker ip driver to delete the network route if :kw:`del_net_route` is set to ``true``.",
 			Provisioning: true,
 		},
 		{
@@ -163,6 +175,13 @@ func (t T) Manifest() *manifest.T {
 			Example:   "443/tcp:8443 53/udp",
 			Text:      "A whitespace-separated list of ``<port>/<protocol>[:<host port>]`` describing socket services that mandate a SRV exposition. With <host_port> set, the ip.cni driver configures port mappings too.",
 		},
+		{
+			Option:   "zone",
+			Attr:     "Zone",
+			Scopable: true,
+			Example:  "zone1",
+			Text:     "The zone name the ip refers to. If set, the ip is plumbed from the global in the zone, on Solaris, or added to the jail addresses, on FreeBSD.",
+		},
 	}...)
 	return m
 }
@@ -251,47 +270,66 @@ func (t *T) Unprovision(ctx context.Context) error {
 	return nil
 }
 
+// ProvisionLeader allocates the address from the ipam, if configured.
+func (t *T) ProvisionLeader(ctx context.Context) error {
+	if t.Provisioner == provisionerIPAM {
+		return t.ipamAllocate(ctx)
+	}
+	return nil
+}
+
+// UnprovisionLeader releases the address to the ipam, if configured.
+func (t *T) UnprovisionLeader(ctx context.Context) error {
+	if t.Provisioner == provisionerIPAM {
+		return t.ipamRelease()
+	}
+	return nil
+}
+
 func (t T) Provisioned() (provisioned.T, error) {
+	if t.Provisioner == provisionerIPAM {
+		return provisioned.FromBool(t.IpName != ""), nil
+	}
 	return provisioned.NotApplicable, nil
 }
 
-func (t T) Abort(ctx context.Context) bool {
+func (t T) Abort(ctx context.Context) error {
 	if t.Tags.Has(tagNonRouted) || t.Tags.Has(tagNoAction) {
-		return false // let start fail with an explicit error message
+		return nil // let start fail with an explicit error message
 	}
 	if t.ipaddr() == nil {
-		return false // let start fail with an explicit error message
+		return nil // let start fail with an explicit error message
 	}
 	if initialStatus := t.Status(ctx); initialStatus == status.Up {
-		return false // let start fail with an explicit error message
-	}
-	if carrier, err := t.hasCarrier(); err == nil && carrier == false && !actioncontext.IsForce(ctx) {
-		t.Log().Error().Msgf("interface %s no-carrier.", t.IpDev)
-		return true
+		return nil // let start fail with an explicit error message
 	}
-	if t.abortPing() {
-		return true
+	if carrier, err := t.hasCarrier(); err == nil && carrier == false {
+		return resource.NewErrAbortStart(resource.AbortNoCarrier, "interface %s no-carrier", t.IpDev)
 	}
-	return false
+	return t.abortPing()
 }
 
 func (t T) hasCarrier() (bool, error) {
 	return netif.HasCarrier(t.IpDev)
 }
 
-func (t T) abortPing() bool {
+// abortPing vetoes the start if the ip address answers to ping,
+// meaning it is already in use on the network (duplicate address).
+func (t T) abortPing() error {
 	ip := t.ipaddr()
 	pinger, err := ping.NewPinger(ip.String())
 	if err != nil {
-		t.Log().Error().Err(err).Msg("abort: ping")
-		return true
+		return fmt.Errorf("abort: ping: %w", err)
 	}
 	pinger.Count = 5
 	pinger.Timeout = 5 * time.Second
 	pinger.Interval = time.Second
 	t.Log().Info().Msgf("checking %s availability (5s)", ip)
 	pinger.Run()
-	return pinger.Statistics().PacketsRecv > 0
+	if pinger.Statistics().PacketsRecv > 0 {
+		return resource.NewErrAbortStart(resource.AbortAddrInUse, "%s is alive", ip)
+	}
+	return nil
 }
 
 func (t T) ipnet() *net.IPNet {
@@ -446,11 +484,19 @@ func (t T) arpAnnounce() error {
 }
 
 func (t T) start() error {
+	if t.Zone != "" {
+		t.Log().Info().Msgf("add %s to %s in zone %s", t.ipnet(), t.IpDev, t.Zone)
+		return zone.AddAddr(t.Zone, t.IpDev, t.ipnet())
+	}
 	t.Log().Info().Msgf("add %s to %s", t.ipnet(), t.IpDev)
 	return netif.AddAddr(t.IpDev, t.ipnet())
 }
 
 func (t T) stop() error {
+	if t.Zone != "" {
+		t.Log().Info().Msgf("delete %s from %s in zone %s", t.ipnet(), t.IpDev, t.Zone)
+		return zone.DelAddr(t.Zone, t.IpDev, t.ipnet())
+	}
 	t.Log().Info().Msgf("delete %s from %s", t.ipnet(), t.IpDev)
 	return netif.DelAddr(t.IpDev, t.ipnet())
 }
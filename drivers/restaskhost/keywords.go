@@ -0,0 +1,43 @@
+package restaskhost
+
+import (
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/util/converters"
+)
+
+var (
+	Keywords = []keywords.Keyword{
+		{
+			Option:   "command",
+			Attr:     "RunCmd",
+			Scopable: true,
+			Required: true,
+			Text: "``true`` execute :cmd:`<script> run` on run action. ``<shlex expression>`` execute the command on" +
+				" run action. The command stdout and stderr lines are logged in the object log and in the task run log," +
+				" which can be displayed with :cmd:`om <path> print run --last`.",
+			Example: "/srv/{name}/bin/backup.sh",
+		},
+		{
+			Option:    "confirmation",
+			Attr:      "Confirmation",
+			Scopable:  true,
+			Converter: converters.Bool,
+			Text: "If set to ``true``, the run action is refused unless the :opt:`--confirm` flag is set." +
+				" Use for the tasks doing destructive or disruptive operations.",
+			Default: "false",
+		},
+	}
+
+	// appKeywordsExcluded are the app keywords not applicable to tasks.
+	appKeywordsExcluded = map[string]interface{}{
+		"start":         nil,
+		"stop":          nil,
+		"check":         nil,
+		"info":          nil,
+		"status_log":    nil,
+		"check_timeout": nil,
+		"info_timeout":  nil,
+		"stop_timeout":  nil,
+		"retcodes":      nil,
+	}
+)
@@ -0,0 +1,93 @@
+package restaskhost
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/resapp"
+	"opensvc.com/opensvc/util/command"
+)
+
+// T is the driver structure.
+type T struct {
+	resapp.T
+	RunCmd       string `json:"command"`
+	Confirmation bool   `json:"confirmation"`
+}
+
+func New() resource.Driver {
+	return &T{}
+}
+
+func init() {
+	resource.Register(driverGroup, driverName, New)
+}
+
+// Start is a noop. A task is only executed by the run action.
+func (t T) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop is a noop. A task is only executed by the run action.
+func (t T) Stop(ctx context.Context) error {
+	return nil
+}
+
+// Status returns n/a. A task has no running state.
+func (t *T) Status(ctx context.Context) status.T {
+	return status.NotApplicable
+}
+
+// NeedsConfirmation returns true if the run action requires the --confirm flag.
+func (t T) NeedsConfirmation() bool {
+	return t.Confirmation
+}
+
+//
+// Run executes the task command. The command stdout and stderr lines are
+// logged in the object log, and written in a new run log file, displayed
+// by "print run".
+//
+func (t *T) Run(ctx context.Context) error {
+	opts, err := t.GetFuncOpts(t.RunCmd, "run")
+	if err != nil {
+		return err
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	f, err := resource.OpenRunLog(t)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	writeLine := func(stream string) func(string) {
+		return func(s string) {
+			fmt.Fprintf(f, "%s %s %s\n", time.Now().Format(time.RFC3339), stream, s)
+		}
+	}
+	opts = append(opts,
+		command.WithLogger(t.Log()),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.WarnLevel),
+		command.WithOnStdoutLine(writeLine("out")),
+		command.WithOnStderrLine(writeLine("err")),
+	)
+	if deadline, ok := ctx.Deadline(); ok {
+		opts = append(opts, command.WithTimeout(time.Until(deadline)))
+	}
+	cmd := command.New(opts...)
+	t.Log().Info().Msgf("running %s", cmd.String())
+	err = cmd.Run()
+	fmt.Fprintf(f, "%s exit code %d\n", time.Now().Format(time.RFC3339), cmd.ExitCode())
+	return err
+}
+
+// Label returns a formatted short description of the Resource
+func (t T) Label() string {
+	return t.RunCmd
+}
@@ -0,0 +1,63 @@
+package restaskhost
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/resourceselector"
+)
+
+func TestRun(t *testing.T) {
+	root, err := ioutil.TempDir("", "task")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	b := []byte("[DEFAULT]\nid = 5f0a1a8e-1d8a-4a3e-9d5b-2d1cba0a1d3e\n\n" +
+		"[task#1]\ncommand = echo hello\n\n" +
+		"[task#2]\ncommand = echo danger\nconfirmation = true\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "etc", "s1.conf"), b, 0644))
+	p, _ := path.Parse("s1")
+	rid := func(s string) resourceselector.Options {
+		return resourceselector.Options{RID: s}
+	}
+
+	t.Run("captures the task output in a run log", func(t *testing.T) {
+		o := object.NewSvc(p)
+		require.NoError(t, o.Run(context.Background(), object.OptsRun{Options: rid("task#1")}))
+		l, err := o.PrintRun(object.OptsPrintRun{Options: rid("task#1"), Last: true})
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		assert.Equal(t, "task#1", l[0].RID)
+		assert.Contains(t, l[0].Content, "out hello")
+		assert.Contains(t, l[0].Content, "exit code 0")
+	})
+
+	t.Run("refuses a confirmation=true task without --confirm", func(t *testing.T) {
+		o := object.NewSvc(p)
+		err := o.Run(context.Background(), object.OptsRun{Options: rid("task#2")})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), resource.ErrConfirmationRequired.Error())
+		l, err := o.PrintRun(object.OptsPrintRun{Options: rid("task#2")})
+		require.NoError(t, err)
+		assert.Len(t, l, 0)
+
+		opts := object.OptsRun{Options: rid("task#2")}
+		opts.Confirm = true
+		require.NoError(t, o.Run(context.Background(), opts))
+		l, err = o.PrintRun(object.OptsPrintRun{Options: rid("task#2"), Last: true})
+		require.NoError(t, err)
+		require.Len(t, l, 1)
+		assert.Contains(t, l[0].Content, "out danger")
+	})
+}
@@ -0,0 +1,47 @@
+package restaskhost
+
+import (
+	"opensvc.com/opensvc/core/drivergroup"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/drivers/resapp"
+)
+
+const (
+	driverGroup = drivergroup.Task
+	driverName  = "host"
+)
+
+// Manifest ...
+func (t T) Manifest() *manifest.T {
+	var keywordL []keywords.Keyword
+	for _, l := range [][]keywords.Keyword{resapp.BaseKeywords, resapp.UnixKeywords} {
+		for _, kw := range l {
+			if _, ok := appKeywordsExcluded[kw.Option]; ok {
+				continue
+			}
+			keywordL = append(keywordL, kw)
+		}
+	}
+	keywordL = append(keywordL, Keywords...)
+	m := manifest.New(driverGroup, driverName, t)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+		{
+			Key:  "nodes",
+			Attr: "Nodes",
+			Ref:  "object.nodes",
+		},
+		{
+			Key:  "objectID",
+			Attr: "ObjectID",
+			Ref:  "object.id",
+		},
+	}...)
+	m.AddKeyword(keywordL...)
+	return m
+}
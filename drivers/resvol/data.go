@@ -0,0 +1,216 @@
+package resvol
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/volsignal"
+	"opensvc.com/opensvc/util/usergroup"
+)
+
+type (
+	// dataEntry is a parsed element of the configs and secrets keywords:
+	// <name>/<key>:<volume relative path>:<options>
+	dataEntry struct {
+		Name    string
+		Key     string
+		Path    string
+		Options string
+	}
+)
+
+func parseDataEntry(s string) (dataEntry, error) {
+	e := dataEntry{}
+	l := strings.SplitN(s, ":", 3)
+	if len(l) < 2 {
+		return e, fmt.Errorf("invalid data entry %s: expected <name>/<key>:<path>[:<options>]", s)
+	}
+	nk := strings.SplitN(l[0], "/", 2)
+	if len(nk) != 2 || nk[0] == "" || nk[1] == "" {
+		return e, fmt.Errorf("invalid data entry %s: expected <name>/<key> as source", s)
+	}
+	e.Name = nk[0]
+	e.Key = nk[1]
+	e.Path = l[1]
+	if len(l) == 3 {
+		e.Options = l[2]
+	}
+	return e, nil
+}
+
+func (t T) perm(s string, def os.FileMode) (os.FileMode, error) {
+	if s == "" {
+		return def, nil
+	}
+	i, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid permissions %s: %s", s, err)
+	}
+	return os.FileMode(i), nil
+}
+
+func (t T) ownership() (int, int, error) {
+	uid := -1
+	gid := -1
+	if t.User != "" {
+		i, err := usergroup.UidFromS(t.User)
+		if err != nil {
+			return uid, gid, err
+		}
+		uid = int(i)
+	}
+	if t.Group != "" {
+		i, err := usergroup.GidFromS(t.Group)
+		if err != nil {
+			return uid, gid, err
+		}
+		gid = int(i)
+	}
+	return uid, gid, nil
+}
+
+// installData creates the directories and installs the configs and secrets
+// keys in the volume. Consumer resources are signaled if an installed key
+// value changed.
+func (t T) installData(ctx context.Context, volume *object.Vol) error {
+	if len(t.Configs) == 0 && len(t.Secrets) == 0 && len(t.Directories) == 0 {
+		return nil
+	}
+	head := volume.MountPoint()
+	if head == "" {
+		return fmt.Errorf("%s has no mount point to install data into", volume.Path)
+	}
+	return t.installDataIn(head)
+}
+
+// installDataIn installs the data in the head directory, the volume
+// mount point.
+func (t T) installDataIn(head string) error {
+	options := object.OptsInstallKey{}
+	var err error
+	if options.Perm, err = t.perm(t.Perm, object.DefaultInstalledFileMode); err != nil {
+		return err
+	}
+	if options.DirPerm, err = t.perm(t.DirPerm, os.ModePerm); err != nil {
+		return err
+	}
+	if options.UID, options.GID, err = t.ownership(); err != nil {
+		return err
+	}
+	if err := t.installDirectories(head, options); err != nil {
+		return err
+	}
+	changed := make([]string, 0)
+	for _, e := range []struct {
+		kind    kind.T
+		entries []string
+	}{
+		{kind.Cfg, t.Configs},
+		{kind.Sec, t.Secrets},
+	} {
+		for _, s := range e.entries {
+			l, err := t.installDataEntry(head, e.kind, s, options)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, l...)
+		}
+	}
+	if len(changed) > 0 {
+		t.Log().Info().Msgf("installed data changed: %s", strings.Join(changed, " "))
+		t.sendSignals()
+	}
+	return nil
+}
+
+func (t T) installDirectories(head string, options object.OptsInstallKey) error {
+	for _, s := range t.Directories {
+		p := filepath.Join(head, s)
+		if !isUnder(p, head) {
+			return fmt.Errorf("directory %s is not in the volume", s)
+		}
+		if _, err := os.Stat(p); err == nil {
+			continue
+		}
+		t.Log().Info().Msgf("create directory %s", p)
+		if err := os.MkdirAll(p, options.DirPerm); err != nil {
+			return err
+		}
+		if options.UID != -1 || options.GID != -1 {
+			if err := os.Chown(p, options.UID, options.GID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t T) installDataEntry(head string, k kind.T, s string, options object.OptsInstallKey) ([]string, error) {
+	e, err := parseDataEntry(s)
+	if err != nil {
+		return nil, err
+	}
+	p, err := path.New(e.Name, t.Path.Namespace, k.String())
+	if err != nil {
+		return nil, err
+	}
+	ks, ok := object.NewFromPath(p, object.WithVolatile(true)).(object.Keystorer)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a keystore", p)
+	}
+	options.Key = e.Key
+	options.Path = filepath.Join(head, e.Path)
+	if strings.HasSuffix(e.Path, "/") {
+		options.Path += "/"
+	}
+	if !isUnder(options.Path, head) {
+		return nil, fmt.Errorf("install path %s is not in the volume", e.Path)
+	}
+	return ks.InstallKey(options)
+}
+
+// isUnder returns true if p is the head directory or a descendant.
+func isUnder(p, head string) bool {
+	rel, err := filepath.Rel(head, p)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator))
+}
+
+// sendSignals sends the signals configured by the signal keyword to the
+// targeted resources of the consumer object.
+func (t T) sendSignals() {
+	if t.Signal == "" {
+		return
+	}
+	o, ok := t.GetObjectDriver().(interface {
+		Resources() resource.Drivers
+	})
+	if !ok {
+		return
+	}
+	m := volsignal.Parse(t.Signal)
+	for sig := range m {
+		for _, r := range o.Resources() {
+			if !m.Targets(sig, r.RID()) {
+				continue
+			}
+			if resource.SkipUnimplemented(r, resource.FeatureSignal, "signal") {
+				continue
+			}
+			t.Log().Info().Msgf("send %s to %s", sig, r.RID())
+			if err := r.(resource.Signaler).Signal(sig); err != nil {
+				t.Log().Warn().Err(err).Msgf("send %s to %s", sig, r.RID())
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package resvol
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/resource"
+)
+
+type (
+	// signaled is a resource recording the signals it receives.
+	signaled struct {
+		T
+		sigs []syscall.Signal
+	}
+
+	// consumer is the object driver of the volume resource.
+	consumer struct {
+		resources resource.Drivers
+	}
+)
+
+func (t *signaled) Signal(sig syscall.Signal) error {
+	t.sigs = append(t.sigs, sig)
+	return nil
+}
+
+func (t consumer) Log() *zerolog.Logger {
+	l := zerolog.Nop()
+	return &l
+}
+
+func (t consumer) VarDir() string {
+	return ""
+}
+
+func (t consumer) Resources() resource.Drivers {
+	return t.resources
+}
+
+func TestInstallData(t *testing.T) {
+	root, err := ioutil.TempDir("", "resvol")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	rawconfig.Load(map[string]string{"osvc_root_path": root})
+	defer rawconfig.Load(map[string]string{})
+
+	p, _ := path.Parse("test/cfg/c1")
+	cfg := object.NewCfg(p)
+	require.NoError(t, cfg.Add(object.OptsAdd{Key: "app.conf", Value: "a=1"}))
+
+	head := filepath.Join(root, "mnt")
+	require.NoError(t, os.Mkdir(head, 0755))
+
+	app1 := &signaled{}
+	app1.SetRID("app#1")
+	app2 := &signaled{}
+	app2.SetRID("app#2")
+	vol := T{
+		Configs:     []string{"c1/app.conf:etc/app.conf"},
+		Directories: []string{"data", "log/app"},
+		Perm:        "600",
+		DirPerm:     "750",
+		Signal:      "hup:app#1",
+		Path:        path.T{Name: "svc1", Namespace: "test", Kind: kind.Svc},
+	}
+	vol.SetRID("volume#1")
+	vol.SetObjectDriver(consumer{resources: resource.Drivers{app1, app2}})
+
+	require.NoError(t, vol.installDataIn(head))
+	b, err := ioutil.ReadFile(filepath.Join(head, "etc", "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "a=1", string(b))
+	info, err := os.Stat(filepath.Join(head, "etc", "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	for _, s := range []string{"data", "log/app"} {
+		info, err := os.Stat(filepath.Join(head, s))
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+		assert.Equal(t, os.FileMode(0750), info.Mode().Perm())
+	}
+	assert.Equal(t, []syscall.Signal{syscall.SIGHUP}, app1.sigs, "the target is signaled on first install")
+	assert.Empty(t, app2.sigs, "the non-target is not signaled")
+
+	t.Run("unchanged data does not signal", func(t *testing.T) {
+		require.NoError(t, vol.installDataIn(head))
+		assert.Len(t, app1.sigs, 1)
+	})
+
+	t.Run("changed data signals", func(t *testing.T) {
+		require.NoError(t, cfg.Change(object.OptsAdd{Key: "app.conf", Value: "a=2"}))
+		require.NoError(t, vol.installDataIn(head))
+		assert.Len(t, app1.sigs, 2)
+		b, err := ioutil.ReadFile(filepath.Join(head, "etc", "app.conf"))
+		require.NoError(t, err)
+		assert.Equal(t, "a=2", string(b))
+	})
+
+	t.Run("paths out of the volume are rejected", func(t *testing.T) {
+		sibling := head + "-sibling"
+		for _, v := range []T{
+			{Directories: []string{"../mnt-sibling"}},
+			{Directories: []string{"../../etc"}},
+			{Configs: []string{"c1/app.conf:../mnt-sibling/app.conf"}},
+			{Configs: []string{"c1/app.conf:../x"}},
+		} {
+			v.Path = vol.Path
+			assert.Error(t, v.installDataIn(head), "%v", v)
+		}
+		_, err := os.Stat(sibling)
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(root, "x"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestIsUnder(t *testing.T) {
+	assert.True(t, isUnder("/srv/vol", "/srv/vol"))
+	assert.True(t, isUnder("/srv/vol/a/b", "/srv/vol"))
+	assert.True(t, isUnder("/srv/vol/..data", "/srv/vol"))
+	assert.False(t, isUnder("/srv/vol-other/a", "/srv/vol"))
+	assert.False(t, isUnder("/srv/vol/../other", "/srv/vol"))
+	assert.False(t, isUnder("/srv", "/srv/vol"))
+}
@@ -228,23 +228,21 @@ func (t T) Manifest() *manifest.T {
 func (t T) startVolume(ctx context.Context, volume *object.Vol) error {
 	options := object.OptsStart{}
 	options.Local = true
-	//ctxOptions := actioncontext.Options(ctx).(object.OptsStart)
-	//options.Leader = ctxOptions.Leader
-	return volume.Start(options)
+	options.DryRun = actioncontext.IsDryRun(ctx)
+	return volume.Start(ctx, options)
 }
 
 func (t T) stopVolume(ctx context.Context, volume *object.Vol, force bool) error {
 	options := object.OptsStop{}
 	options.Local = true
 	options.Force = force
-	//ctxOptions := actioncontext.Options(ctx).(object.OptsStop)
-	//options.Leader = ctxOptions.Leader
+	options.DryRun = actioncontext.IsDryRun(ctx)
 	holders := volume.HoldersExcept(ctx, t.Path)
 	if len(holders) > 0 {
 		t.Log().Info().Msgf("skip %s stop: active users: %s", volume.Path, holders)
 		return nil
 	}
-	return volume.Stop(options)
+	return volume.Stop(ctx, options)
 }
 
 func (t T) statusVolume(ctx context.Context, volume *object.Vol) (instance.Status, error) {
@@ -273,6 +271,9 @@ func (t T) Start(ctx context.Context) error {
 	actionrollback.Register(ctx, func() error {
 		return t.stopVolume(ctx, volume, false)
 	})
+	if err = t.installData(ctx, volume); err != nil {
+		return err
+	}
 	if err = t.startFlag(ctx); err != nil {
 		return err
 	}
@@ -353,6 +354,23 @@ func (t *T) Status(ctx context.Context) status.T {
 	return data.Avail
 }
 
+//
+// SyncUpdate refreshes the configs and secrets keys installed in the
+// started volume, and signals the consumer resources if a key value
+// changed.
+//
+func (t T) SyncUpdate(ctx context.Context) error {
+	if !t.flagInstalled() {
+		t.Log().Debug().Msg("skip data refresh: the volume is not started")
+		return nil
+	}
+	volume, err := t.volume()
+	if err != nil {
+		return err
+	}
+	return t.installData(ctx, volume)
+}
+
 func (t T) flagFile() string {
 	return filepath.Join(t.VarDir(), "flag")
 }
@@ -497,7 +515,9 @@ func (t T) ProvisionLeader(ctx context.Context) error {
 	if volume, err = t.createVolume(volume); err != nil {
 		return err
 	}
-	return volume.Provision(object.OptsProvision{})
+	options := object.OptsProvision{}
+	options.DryRun = actioncontext.IsDryRun(ctx)
+	return volume.Provision(ctx, options)
 }
 
 func (t T) UnprovisionLeader(ctx context.Context) error {
@@ -509,7 +529,9 @@ func (t T) UnprovisionLeader(ctx context.Context) error {
 		t.Log().Info().Msgf("%s is already unprovisioned", volume.Path)
 		return nil
 	}
-	return volume.Unprovision(object.OptsUnprovision{})
+	options := object.OptsUnprovision{}
+	options.DryRun = actioncontext.IsDryRun(ctx)
+	return volume.Unprovision(ctx, options)
 }
 
 func (t T) Provisioned() (provisioned.T, error) {
@@ -0,0 +1,138 @@
+/*
+Vault secret backend driver
+
+Resolves the vault://<path>#<field> sec key values using the Hashicorp
+Vault http api. Both the kv version 1 and version 2 secret engines are
+supported.
+
+The vault server address and token are read from the VAULT_ADDR and
+VAULT_TOKEN environment variables, or from the node configuration:
+
+	[vault]
+	addr = https://vault.mycorp:8200
+	token_file = /etc/opensvc/vault.token
+*/
+package secvault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/secbackend"
+)
+
+const (
+	scheme = "vault"
+)
+
+type (
+	T struct {
+		client *http.Client
+	}
+
+	response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+)
+
+func init() {
+	secbackend.Register(scheme, New())
+}
+
+func New() *T {
+	return &T{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t T) addr() string {
+	if s := os.Getenv("VAULT_ADDR"); s != "" {
+		return strings.TrimSuffix(s, "/")
+	}
+	return strings.TrimSuffix(rawconfig.NodeViper.GetString("vault.addr"), "/")
+}
+
+func (t T) token() (string, error) {
+	if s := os.Getenv("VAULT_TOKEN"); s != "" {
+		return s, nil
+	}
+	if s := rawconfig.NodeViper.GetString("vault.token"); s != "" {
+		return s, nil
+	}
+	if p := rawconfig.NodeViper.GetString("vault.token_file"); p != "" {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return "", fmt.Errorf("no vault token: set VAULT_TOKEN or vault.token_file")
+}
+
+// Get returns the value of the field of the secret stored at the ref path.
+func (t T) Get(ref secbackend.Ref) ([]byte, error) {
+	addr := t.addr()
+	if addr == "" {
+		return nil, fmt.Errorf("no vault address: set VAULT_ADDR or vault.addr")
+	}
+	token, err := t.token()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", addr+"/v1/"+ref.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := rawconfig.NodeViper.GetString("vault.namespace"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var data response
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.Join(data.Errors, ", "))
+	}
+	return extract(data.Data, ref.Field)
+}
+
+// extract returns the field value from the secret data, handling the kv
+// version 2 nesting of the fields in a "data" sub-map.
+func extract(data map[string]interface{}, field string) ([]byte, error) {
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	if field == "" {
+		if len(data) != 1 {
+			return nil, fmt.Errorf("the secret has %d fields: a #<field> is required", len(data))
+		}
+		for k := range data {
+			field = k
+		}
+	}
+	v, ok := data[field]
+	if !ok {
+		return nil, fmt.Errorf("field %s not found", field)
+	}
+	switch s := v.(type) {
+	case string:
+		return []byte(s), nil
+	default:
+		return json.Marshal(s)
+	}
+}
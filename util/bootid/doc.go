@@ -0,0 +1,4 @@
+// Package bootid provides the identifier of the current operating system
+// boot, used to detect node reboots.
+
+package bootid
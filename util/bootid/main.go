@@ -0,0 +1,21 @@
+// +build linux
+
+package bootid
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	procBootID = "/proc/sys/kernel/random/boot_id"
+)
+
+// Get returns the identifier of the current operating system boot.
+func Get() (string, error) {
+	b, err := ioutil.ReadFile(procBootID)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
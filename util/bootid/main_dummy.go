@@ -0,0 +1,9 @@
+// +build !linux
+
+package bootid
+
+// Get returns the identifier of the current operating system boot.
+// It always returns an empty string on non linux systems.
+func Get() (string, error) {
+	return "", nil
+}
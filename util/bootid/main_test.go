@@ -0,0 +1,25 @@
+// +build linux
+
+package bootid
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	td, err := ioutil.TempDir("", "bootid")
+	require.Nil(t, err)
+	defer os.RemoveAll(td)
+	defer func(s string) { procBootID = s }(procBootID)
+	procBootID = filepath.Join(td, "boot_id")
+	require.Nil(t, ioutil.WriteFile(procBootID, []byte("4b4b7b57-1d3e-4c4c-8e8e-0f0f0f0f0f0f\n"), 0644))
+	s, err := Get()
+	require.Nil(t, err)
+	assert.Equal(t, "4b4b7b57-1d3e-4c4c-8e8e-0f0f0f0f0f0f", s)
+}
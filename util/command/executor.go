@@ -0,0 +1,163 @@
+package command
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	//
+	// Executor executes the commands in place of the os/exec package, so
+	// the tests can intercept the commands and fake their outputs and
+	// exit codes. The commands are executed for real when no executor is
+	// set, by WithExecutor or SetExecutor.
+	//
+	Executor interface {
+		Execute(name string, args []string) Result
+	}
+
+	//
+	// Result is the outcome of a command execution by an Executor. Err
+	// is the error returned by Start, like a command not found, in which
+	// case the other fields are ignored.
+	//
+	Result struct {
+		Stdout   []byte
+		Stderr   []byte
+		ExitCode int
+		Err      error
+	}
+
+	// ExecutorFunc adapts a function to the Executor interface.
+	ExecutorFunc func(name string, args []string) Result
+
+	//
+	// Fake is an Executor replying the canned results of the faked
+	// commands, and recording their calls. The commands are matched by
+	// name, then by base name, so the commands executed by absolute path
+	// are matched by their name too. The not faked commands are not
+	// found.
+	//
+	Fake struct {
+		mu      sync.Mutex
+		results map[string]Result
+		calls   []Call
+	}
+
+	// Call is a command execution recorded by a Fake executor.
+	Call struct {
+		Name string
+		Args []string
+	}
+)
+
+var (
+	executorMu sync.RWMutex
+
+	// executor is the package default executor. nil executes for real.
+	executor Executor
+)
+
+// Execute implements the Executor interface.
+func (f ExecutorFunc) Execute(name string, args []string) Result {
+	return f(name, args)
+}
+
+//
+// SetExecutor sets the executor of the commands not having their own,
+// and returns the function restoring the previous one. A nil executor
+// restores the real executions.
+//
+func SetExecutor(e Executor) func() {
+	executorMu.Lock()
+	defer executorMu.Unlock()
+	previous := executor
+	executor = e
+	return func() {
+		executorMu.Lock()
+		defer executorMu.Unlock()
+		executor = previous
+	}
+}
+
+func defaultExecutor() Executor {
+	executorMu.RLock()
+	defer executorMu.RUnlock()
+	return executor
+}
+
+// WithExecutor sets the executor of the command, overriding the package
+// default executor set by SetExecutor.
+func WithExecutor(e Executor) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.executor = e
+		return nil
+	})
+}
+
+// NewFake allocates and returns a Fake executor with no faked command.
+func NewFake() *Fake {
+	return &Fake{
+		results: make(map[string]Result),
+		calls:   make([]Call, 0),
+	}
+}
+
+// Set fakes the name command, replying the result. Faking an already
+// faked command replaces its result.
+func (f *Fake) Set(name string, result Result) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[name] = result
+}
+
+// Execute implements the Executor interface.
+func (f *Fake) Execute(name string, args []string) Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Name: name, Args: append([]string{}, args...)})
+	if result, ok := f.results[name]; ok {
+		return result
+	}
+	if result, ok := f.results[filepath.Base(name)]; ok {
+		return result
+	}
+	return Result{Err: fmt.Errorf("%s: %w", name, exec.ErrNotFound)}
+}
+
+// Calls returns the recorded calls, faked or not, in execution order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call{}, f.calls...)
+}
+
+// CallsOf returns the recorded calls of the name command, matched by
+// name or base name.
+func (f *Fake) CallsOf(name string) []Call {
+	l := make([]Call, 0)
+	for _, c := range f.Calls() {
+		if c.Name == name || filepath.Base(c.Name) == name {
+			l = append(l, c)
+		}
+	}
+	return l
+}
+
+// Reset drops the recorded calls.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = make([]Call, 0)
+}
+
+// String returns the command line of the call.
+func (t Call) String() string {
+	return strings.Join(append([]string{t.Name}, t.Args...), " ")
+}
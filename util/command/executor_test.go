@@ -0,0 +1,77 @@
+package command
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeExecutor(t *testing.T) {
+	t.Run("reply the canned stdout, stderr and exit code", func(t *testing.T) {
+		fake := NewFake()
+		fake.Set("lvs", Result{Stdout: []byte("line1\nline2\n"), Stderr: []byte("warn\n"), ExitCode: 5})
+		lines := make([]string, 0)
+		cmd := New(
+			WithName("lvs"),
+			WithVarArgs("--reportformat", "json"),
+			WithExecutor(fake),
+			WithBufferedStdout(),
+			WithBufferedStderr(),
+			WithOnStdoutLine(func(s string) { lines = append(lines, s) }),
+		)
+		err := cmd.Run()
+		var exitErr *ErrExitCode
+		require.True(t, errors.As(err, &exitErr))
+		assert.Equal(t, 5, cmd.ExitCode())
+		assert.Equal(t, "line1\nline2", string(cmd.Stdout()))
+		assert.Equal(t, "warn", string(cmd.Stderr()))
+		assert.Equal(t, []string{"line1", "line2"}, lines)
+		assert.Equal(t, []Call{{Name: "lvs", Args: []string{"--reportformat", "json"}}}, fake.Calls())
+	})
+
+	t.Run("match the absolute path commands by base name", func(t *testing.T) {
+		fake := NewFake()
+		fake.Set("losetup", Result{})
+		cmd := New(WithName("/sbin/losetup"), WithVarArgs("-d", "/dev/loop0"), WithExecutor(fake))
+		assert.Nil(t, cmd.Run())
+		assert.Equal(t, 0, cmd.ExitCode())
+		require.Len(t, fake.CallsOf("losetup"), 1)
+		assert.Equal(t, "/sbin/losetup -d /dev/loop0", fake.CallsOf("losetup")[0].String())
+	})
+
+	t.Run("not faked commands are not found", func(t *testing.T) {
+		fake := NewFake()
+		cmd := New(WithName("vgs"), WithExecutor(fake))
+		assert.True(t, errors.Is(cmd.Run(), exec.ErrNotFound))
+		assert.Len(t, fake.Calls(), 1)
+		fake.Reset()
+		assert.Len(t, fake.Calls(), 0)
+	})
+}
+
+func TestSetExecutor(t *testing.T) {
+	fake := NewFake()
+	fake.Set("true", Result{ExitCode: 1})
+	restore := SetExecutor(fake)
+	cmd := New(WithName("true"))
+	assert.NotNil(t, cmd.Run())
+	assert.Equal(t, 1, cmd.ExitCode())
+
+	t.Run("the command executor overrides the default", func(t *testing.T) {
+		other := ExecutorFunc(func(name string, args []string) Result {
+			return Result{ExitCode: 2}
+		})
+		cmd := New(WithName("true"), WithExecutor(other), WithIgnoredExitCodes(2))
+		assert.Nil(t, cmd.Run())
+		assert.Equal(t, 2, cmd.ExitCode())
+	})
+
+	restore()
+	cmd = New(WithName("true"))
+	assert.Nil(t, cmd.Run())
+	assert.Equal(t, 0, cmd.ExitCode())
+	assert.Len(t, fake.Calls(), 1)
+}
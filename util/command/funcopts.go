@@ -22,6 +22,14 @@ func (t T) valid() error {
 			return missingLog("WithCommandLogLevel")
 		}
 	}
+	if t.interactive {
+		switch {
+		case t.stdoutLogLevel != disabledLog || t.bufferStdout || t.onStdoutLine != nil:
+			return fmt.Errorf("funcopt WithInteractive can not be used with a stdout watcher")
+		case t.stderrLogLevel != disabledLog || t.bufferStderr || t.onStderrLine != nil:
+			return fmt.Errorf("funcopt WithInteractive can not be used with a stderr watcher")
+		}
+	}
 	return nil
 }
 
@@ -167,3 +175,16 @@ func WithOnStderrLine(f func(string)) funcopt.O {
 		return nil
 	})
 }
+
+// WithInteractive connects the command stdin, stdout and stderr to the
+// process ones, so the operator can interact with the command, like a
+// shell or a console. The terminal is passed through to the command, and
+// its state is restored when the command exits.
+//   - can not be combined with the stdout and stderr watchers
+func WithInteractive() funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.interactive = true
+		return nil
+	})
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// interactiveSignals are the signals sent by the terminal to the whole
+// foreground process group. They are ignored by the parent process while
+// an interactive command runs, so they only reach the command.
+var interactiveSignals = []os.Signal{os.Interrupt, syscall.SIGQUIT}
+
+//
+// IsTerminal returns true if the process stdin and stdout are terminals.
+// The drivers use it to decide if the container runtime must allocate a
+// pseudo-terminal for an interactive command, like the docker exec -t
+// option.
+//
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// startInteractive connects the command to the process stdio, and saves
+// the terminal state so it can be restored after the command exits, even
+// if the command left the terminal in raw mode.
+func (t *T) startInteractive() {
+	t.cmd.Stdin = os.Stdin
+	t.cmd.Stdout = os.Stdout
+	t.cmd.Stderr = os.Stderr
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		if state, err := term.GetState(fd); err == nil {
+			t.termState = state
+		}
+	}
+	signal.Ignore(interactiveSignals...)
+}
+
+func (t *T) stopInteractive() {
+	signal.Reset(interactiveSignals...)
+	if t.termState != nil {
+		_ = term.Restore(int(os.Stdin.Fd()), t.termState)
+	}
+}
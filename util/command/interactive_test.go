@@ -0,0 +1,23 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInteractive(t *testing.T) {
+	t.Run("runs the command attached to the process stdio", func(t *testing.T) {
+		cmd := New(WithName("/bin/sh"), WithVarArgs("-c", "exit 3"), WithInteractive(), WithIgnoredExitCodes(3))
+		require.NoError(t, cmd.Run())
+		assert.Equal(t, 3, cmd.ExitCode())
+	})
+
+	t.Run("refuses the stdout and stderr watchers", func(t *testing.T) {
+		cmd := New(WithName("/bin/true"), WithInteractive(), WithBufferedStdout())
+		assert.Error(t, cmd.Run())
+		cmd = New(WithName("/bin/true"), WithInteractive(), WithOnStderrLine(func(string) {}))
+		assert.Error(t, cmd.Run())
+	})
+}
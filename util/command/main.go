@@ -2,6 +2,7 @@ package command
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"github.com/anmitsu/go-shlex"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	"golang.org/x/term"
 	"opensvc.com/opensvc/util/funcopt"
 )
 
@@ -37,6 +39,8 @@ type (
 		onStdoutLine    func(string)
 		onStderrLine    func(string)
 		okExitCodes     []int
+		interactive     bool
+		executor        Executor
 
 		pid             int
 		commandString   string
@@ -49,6 +53,8 @@ type (
 		stderr          []byte
 		started         bool // Prevent relaunch
 		waited          bool // Prevent relaunch
+		termState       *term.State
+		result          *Result // the outcome of a faked execution
 	}
 
 	ErrExitCode struct {
@@ -117,7 +123,13 @@ func (t *T) Start() (err error) {
 	if err = t.update(); err != nil {
 		return err
 	}
+	if e := t.getExecutor(); e != nil {
+		return t.startExecutor(e)
+	}
 	log := t.log
+	if t.interactive {
+		t.startInteractive()
+	}
 	if t.stdoutLogLevel != zerolog.Disabled || t.bufferStdout || t.onStdoutLine != nil {
 		var r io.ReadCloser
 		if r, err = cmd.StdoutPipe(); err != nil {
@@ -130,15 +142,7 @@ func (t *T) Start() (err error) {
 		t.goroutine = append(t.goroutine, func() {
 			s := bufio.NewScanner(r)
 			for s.Scan() {
-				if t.stdoutLogLevel != zerolog.Disabled {
-					log.WithLevel(t.stdoutLogLevel).Str("out", s.Text()).Int("pid", t.pid).Send()
-				}
-				if t.onStdoutLine != nil {
-					t.onStdoutLine(s.Text())
-				}
-				if t.bufferStdout {
-					t.stdout = append(t.stdout, append([]byte("\n"), s.Bytes()...)...)
-				}
+				t.stdoutLine(s.Bytes())
 			}
 			t.done <- "stdout"
 		})
@@ -155,15 +159,7 @@ func (t *T) Start() (err error) {
 		t.goroutine = append(t.goroutine, func() {
 			s := bufio.NewScanner(r)
 			for s.Scan() {
-				if t.stderrLogLevel != zerolog.Disabled {
-					log.WithLevel(t.stderrLogLevel).Str("err", s.Text()).Int("pid", t.pid).Send()
-				}
-				if t.onStderrLine != nil {
-					t.onStderrLine(s.Text())
-				}
-				if t.bufferStderr {
-					t.stderr = append(t.stderr, append([]byte("\n"), s.Bytes()...)...)
-				}
+				t.stderrLine(s.Bytes())
 			}
 			t.done <- "stderr"
 		})
@@ -220,6 +216,9 @@ func (t *T) Start() (err error) {
 		log.WithLevel(t.logLevel).Str("cmd", cmd.String()).Msg("running")
 	}
 	if err = cmd.Start(); err != nil {
+		if t.interactive {
+			t.stopInteractive()
+		}
 		if log != nil {
 			log.WithLevel(t.logLevel).Err(err).Str("cmd", cmd.String()).Msg("running")
 		}
@@ -242,6 +241,9 @@ func (t *T) Cmd() *exec.Cmd {
 }
 
 func (t *T) ExitCode() int {
+	if t.result != nil {
+		return t.result.ExitCode
+	}
 	return t.cmd.ProcessState.ExitCode()
 }
 
@@ -250,6 +252,9 @@ func (t *T) Wait() (err error) {
 		return ErrAlreadyWaited
 	}
 	t.waited = true
+	if t.result != nil {
+		return t.checkExitCode(t.result.ExitCode)
+	}
 	waitCount := len(t.goroutine)
 	if t.cancel != nil {
 		waitCount = waitCount - 1
@@ -265,6 +270,9 @@ func (t *T) Wait() (err error) {
 		}
 	}
 	cmd := t.cmd
+	if t.interactive {
+		defer t.stopInteractive()
+	}
 	if err := cmd.Wait(); err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			return t.checkExitCode(exitError.ExitCode())
@@ -277,6 +285,73 @@ func (t *T) Wait() (err error) {
 	return t.checkExitCode(t.ExitCode())
 }
 
+// getExecutor returns the executor of the command, or the package
+// default executor. nil executes for real.
+func (t *T) getExecutor() Executor {
+	if t.executor != nil {
+		return t.executor
+	}
+	return defaultExecutor()
+}
+
+//
+// startExecutor executes the command with the executor, and feeds its
+// stdout and stderr lines to the watchers. The outcome is saved for
+// Wait and ExitCode.
+//
+func (t *T) startExecutor(e Executor) error {
+	if t.log != nil && t.commandLogLevel != zerolog.Disabled {
+		t.log.WithLevel(t.commandLogLevel).Str("cmd", t.cmd.String()).Msg("running")
+	}
+	result := e.Execute(t.name, t.args)
+	if result.Err != nil {
+		if t.log != nil {
+			t.log.WithLevel(t.logLevel).Err(result.Err).Str("cmd", t.cmd.String()).Msg("running")
+		}
+		return result.Err
+	}
+	t.result = &result
+	if t.stdoutLogLevel != zerolog.Disabled || t.bufferStdout || t.onStdoutLine != nil {
+		s := bufio.NewScanner(bytes.NewReader(result.Stdout))
+		for s.Scan() {
+			t.stdoutLine(s.Bytes())
+		}
+	}
+	if t.stderrLogLevel != zerolog.Disabled || t.bufferStderr || t.onStderrLine != nil {
+		s := bufio.NewScanner(bytes.NewReader(result.Stderr))
+		for s.Scan() {
+			t.stderrLine(s.Bytes())
+		}
+	}
+	return nil
+}
+
+// stdoutLine logs, watches and buffers a stdout line of the command.
+func (t *T) stdoutLine(b []byte) {
+	if t.stdoutLogLevel != zerolog.Disabled {
+		t.log.WithLevel(t.stdoutLogLevel).Str("out", string(b)).Int("pid", t.pid).Send()
+	}
+	if t.onStdoutLine != nil {
+		t.onStdoutLine(string(b))
+	}
+	if t.bufferStdout {
+		t.stdout = append(t.stdout, append([]byte("\n"), b...)...)
+	}
+}
+
+// stderrLine logs, watches and buffers a stderr line of the command.
+func (t *T) stderrLine(b []byte) {
+	if t.stderrLogLevel != zerolog.Disabled {
+		t.log.WithLevel(t.stderrLogLevel).Str("err", string(b)).Int("pid", t.pid).Send()
+	}
+	if t.onStderrLine != nil {
+		t.onStderrLine(string(b))
+	}
+	if t.bufferStderr {
+		t.stderr = append(t.stderr, append([]byte("\n"), b...)...)
+	}
+}
+
 func (t T) checkExitCode(exitCode int) error {
 	if len(t.okExitCodes) == 0 {
 		t.logExitCode(exitCode)
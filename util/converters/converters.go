@@ -2,6 +2,7 @@ package converters
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -27,6 +28,16 @@ type (
 	TUmask         string
 	TSize          string
 	TFileMode      string
+	TTristate      string
+
+	//
+	// Renderer is implemented by the converters able to format a
+	// converted value back to its canonical string form, so
+	// Convert(Render(v)) returns v.
+	//
+	Renderer interface {
+		Render(interface{}) (string, error)
+	}
 )
 
 var (
@@ -43,8 +54,28 @@ var (
 	Umask         TUmask
 	Size          TSize
 	FileMode      TFileMode
+	Tristate      TTristate
+
+	// ErrInvalid is wrapped by the errors returned for values not
+	// convertible to the converter type.
+	ErrInvalid = errors.New("invalid value")
+
+	// shlexQuoter escapes the characters special in a double-quoted
+	// shlex element.
+	shlexQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
 )
 
+func invalid(t fmt.Stringer, s string, err error) error {
+	if err == nil {
+		return fmt.Errorf("%w for type %s: '%s'", ErrInvalid, t, s)
+	}
+	return fmt.Errorf("%w for type %s: '%s': %s", ErrInvalid, t, s, err)
+}
+
+func wrongType(t fmt.Stringer, i interface{}) error {
+	return fmt.Errorf("can not render a %T as type %s", i, t)
+}
+
 //
 func (t TString) Convert(s string) (interface{}, error) {
 	return s, nil
@@ -56,7 +87,19 @@ func (t TString) String() string {
 
 //
 func (t TInt) Convert(s string) (interface{}, error) {
-	return strconv.Atoi(s)
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, invalid(t, s, nil)
+	}
+	return i, nil
+}
+
+func (t TInt) Render(i interface{}) (string, error) {
+	v, ok := i.(int)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strconv.Itoa(v), nil
 }
 
 func (t TInt) String() string {
@@ -65,7 +108,19 @@ func (t TInt) String() string {
 
 //
 func (t TInt64) Convert(s string) (interface{}, error) {
-	return strconv.ParseInt(s, 10, 64)
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return int64(0), invalid(t, s, nil)
+	}
+	return i, nil
+}
+
+func (t TInt64) Render(i interface{}) (string, error) {
+	v, ok := i.(int64)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strconv.FormatInt(v, 10), nil
 }
 
 func (t TInt64) String() string {
@@ -74,7 +129,19 @@ func (t TInt64) String() string {
 
 //
 func (t TFloat64) Convert(s string) (interface{}, error) {
-	return strconv.ParseFloat(s, 64)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return float64(0), invalid(t, s, nil)
+	}
+	return f, nil
+}
+
+func (t TFloat64) Render(i interface{}) (string, error) {
+	v, ok := i.(float64)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64), nil
 }
 
 func (t TFloat64) String() string {
@@ -86,7 +153,19 @@ func (t TBool) Convert(s string) (interface{}, error) {
 	if s == "" {
 		return false, nil
 	}
-	return strconv.ParseBool(s)
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, invalid(t, s, nil)
+	}
+	return b, nil
+}
+
+func (t TBool) Render(i interface{}) (string, error) {
+	v, ok := i.(bool)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strconv.FormatBool(v), nil
 }
 
 func (t TBool) String() string {
@@ -98,6 +177,14 @@ func (t TList) Convert(s string) (interface{}, error) {
 	return strings.Fields(s), nil
 }
 
+func (t TList) Render(i interface{}) (string, error) {
+	l, ok := i.([]string)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strings.Join(l, " "), nil
+}
+
 func (t TList) String() string {
 	return "list"
 }
@@ -111,6 +198,14 @@ func (t TListLowercase) Convert(s string) (interface{}, error) {
 	return l, nil
 }
 
+func (t TListLowercase) Render(i interface{}) (string, error) {
+	l, ok := i.([]string)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return strings.ToLower(strings.Join(l, " ")), nil
+}
+
 func (t TListLowercase) String() string {
 	return "list-lowercase"
 }
@@ -130,7 +225,36 @@ func (t TSet) String() string {
 
 //
 func (t TShlex) Convert(s string) (interface{}, error) {
-	return shlex.Split(s, true)
+	l, err := shlex.Split(s, true)
+	if err != nil {
+		return nil, invalid(t, s, err)
+	}
+	return l, nil
+}
+
+//
+// Render joins the elements, double-quoting those containing
+// whitespaces, quotes or backslashes, so they are preserved by Convert.
+//
+func (t TShlex) Render(i interface{}) (string, error) {
+	l, ok := i.([]string)
+	if !ok {
+		return "", wrongType(t, i)
+	}
+	return ShlexJoin(l), nil
+}
+
+// ShlexJoin is the reverse of a posix shlex split.
+func ShlexJoin(l []string) string {
+	quoted := make([]string, len(l))
+	for i, e := range l {
+		if e != "" && !strings.ContainsAny(e, " \t\n'\"\\") {
+			quoted[i] = e
+			continue
+		}
+		quoted[i] = `"` + shlexQuoter.Replace(e) + `"`
+	}
+	return strings.Join(quoted, " ")
 }
 
 func (t TShlex) String() string {
@@ -142,6 +266,8 @@ func (t TShlex) String() string {
 //
 // nil is returned when duration is unset
 // Default unit is second when not specified
+// The d, w and y units are accepted as leading components, as in 1y or
+// 1d12h, a year being 365 days.
 //
 func (t TDuration) Convert(s string) (interface{}, error) {
 	return t.convert(s)
@@ -154,13 +280,68 @@ func (t TDuration) convert(s string) (*time.Duration, error) {
 	if _, err := strconv.Atoi(s); err == nil {
 		s = s + "s"
 	}
-	duration, err := time.ParseDuration(s)
-	if err != nil {
-		return nil, err
+	var duration time.Duration
+	rest := s
+	for _, u := range longDurationUnits {
+		i := strings.Index(rest, u.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil || n < 0 {
+			return nil, invalid(t, s, nil)
+		}
+		duration += time.Duration(n) * u.d
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return nil, invalid(t, s, nil)
+		}
+		duration += d
 	}
 	return &duration, nil
 }
 
+// longDurationUnits are the duration units not supported by
+// time.ParseDuration, in the order they must appear.
+var longDurationUnits = []struct {
+	suffix string
+	d      time.Duration
+}{
+	{"y", 365 * 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"d", 24 * time.Hour},
+}
+
+//
+// Render returns the shortest string parsed as the same duration, like
+// 1h30m instead of 1h30m0s. A nil duration renders as an empty string.
+//
+func (t TDuration) Render(i interface{}) (string, error) {
+	var d time.Duration
+	switch v := i.(type) {
+	case *time.Duration:
+		if v == nil {
+			return "", nil
+		}
+		d = *v
+	case time.Duration:
+		d = v
+	default:
+		return "", wrongType(t, i)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s, nil
+}
+
 func (t TDuration) String() string {
 	return "duration"
 }
@@ -176,12 +357,16 @@ func (t TUmask) convert(s string) (*os.FileMode, error) {
 	}
 	i, err := strconv.ParseInt(s, 8, 32)
 	if err != nil {
-		return nil, errors.New("unexpected umask value: " + s + " " + err.Error())
+		return nil, invalid(t, s, nil)
 	}
 	umask := os.FileMode(i)
 	return &umask, nil
 }
 
+func (t TUmask) Render(i interface{}) (string, error) {
+	return renderMode(t, i)
+}
+
 func (t TUmask) String() string {
 	return "umask"
 }
@@ -200,11 +385,32 @@ func (t TSize) convert(s string) (*int64, error) {
 		return nil, err
 	}
 	if i, err = sizeconv.FromSize(s); err != nil {
-		return nil, err
+		return nil, invalid(t, s, nil)
 	}
 	return &i, err
 }
 
+// Render returns the size in the largest binary unit dividing it, like
+// 2g for 2GiB. A nil size renders as an empty string.
+func (t TSize) Render(i interface{}) (string, error) {
+	var n int64
+	switch v := i.(type) {
+	case *int64:
+		if v == nil {
+			return "", nil
+		}
+		n = *v
+	case int64:
+		n = v
+	default:
+		return "", wrongType(t, i)
+	}
+	if n < 0 {
+		return strconv.FormatInt(n, 10), nil
+	}
+	return sizeconv.Compact(uint64(n), sizeconv.IEC), nil
+}
+
 func (t TSize) String() string {
 	return "size"
 }
@@ -220,12 +426,65 @@ func (t TFileMode) convert(s string) (*os.FileMode, error) {
 	}
 	i, err := strconv.ParseInt(s, 8, 32)
 	if err != nil {
-		return nil, err
+		return nil, invalid(t, s, nil)
 	}
 	mode := os.FileMode(i)
 	return &mode, nil
 }
 
+func (t TFileMode) Render(i interface{}) (string, error) {
+	return renderMode(t, i)
+}
+
 func (t TFileMode) String() string {
 	return "file-mode"
 }
+
+func renderMode(t fmt.Stringer, i interface{}) (string, error) {
+	var mode os.FileMode
+	switch v := i.(type) {
+	case *os.FileMode:
+		if v == nil {
+			return "", nil
+		}
+		mode = *v
+	case os.FileMode:
+		mode = v
+	default:
+		return "", wrongType(t, i)
+	}
+	return fmt.Sprintf("%04o", uint32(mode)), nil
+}
+
+//
+// Convert returns a *bool, nil when the value is empty, meaning the
+// decision is left to the driver.
+//
+func (t TTristate) Convert(s string) (interface{}, error) {
+	if s == "" {
+		return (*bool)(nil), nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, invalid(t, s, nil)
+	}
+	return &b, nil
+}
+
+func (t TTristate) Render(i interface{}) (string, error) {
+	switch v := i.(type) {
+	case *bool:
+		if v == nil {
+			return "", nil
+		}
+		return strconv.FormatBool(*v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", wrongType(t, i)
+	}
+}
+
+func (t TTristate) String() string {
+	return "tristate"
+}
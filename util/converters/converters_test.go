@@ -1,8 +1,10 @@
 package converters
 
 import (
-	"github.com/stretchr/testify/assert"
+	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -61,3 +63,72 @@ func TestSizeConvert(t *testing.T) {
 		}
 	})
 }
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		converter interface {
+			Convert(string) (interface{}, error)
+			Renderer
+		}
+		in  string
+		out string
+	}{
+		{converter: Duration, in: "90", out: "1m30s"},
+		{converter: Duration, in: "1h0m0s", out: "1h"},
+		{converter: Duration, in: "1h30m", out: "1h30m"},
+		{converter: Duration, in: "1d12h", out: "36h"},
+		{converter: Duration, in: "1y", out: "8760h"},
+		{converter: Duration, in: "2w", out: "336h"},
+		{converter: Duration, in: "", out: ""},
+		{converter: Size, in: "2GiB", out: "2g"},
+		{converter: Size, in: "1000", out: "1000"},
+		{converter: Size, in: "", out: ""},
+		{converter: Umask, in: "22", out: "0022"},
+		{converter: FileMode, in: "644", out: "0644"},
+		{converter: Int, in: "12", out: "12"},
+		{converter: Bool, in: "True", out: "true"},
+		{converter: Tristate, in: "", out: ""},
+		{converter: Tristate, in: "0", out: "false"},
+		{converter: List, in: " a  b ", out: "a b"},
+		{converter: ListLowercase, in: "A b", out: "a b"},
+		{converter: Shlex, in: `a "b c" 'd"e' f\\g`, out: `a "b c" "d\"e" "f\\g"`},
+	}
+	for _, c := range cases {
+		t.Run(c.converter.(fmt.Stringer).String()+" "+c.in, func(t *testing.T) {
+			v, err := c.converter.Convert(c.in)
+			assert.NoError(t, err)
+			s, err := c.converter.Render(v)
+			assert.NoError(t, err)
+			assert.Equal(t, c.out, s)
+			v2, err := c.converter.Convert(s)
+			assert.NoError(t, err)
+			assert.Equal(t, v, v2, "the rendered value converts back to the same value")
+		})
+	}
+}
+
+func TestConvertInvalid(t *testing.T) {
+	for _, c := range []struct {
+		converter interface {
+			Convert(string) (interface{}, error)
+		}
+		in string
+	}{
+		{Int, "a"},
+		{Int64, "1.5"},
+		{Float64, "x"},
+		{Bool, "maybe"},
+		{Tristate, "maybe"},
+		{Duration, "1x"},
+		{Duration, "d"},
+		{Duration, "1d1y"},
+		{Size, "-1"},
+		{Umask, "9"},
+		{FileMode, "abc"},
+		{Shlex, `"a`},
+	} {
+		_, err := c.converter.Convert(c.in)
+		assert.ErrorIs(t, err, ErrInvalid, "%s", c.in)
+		assert.Contains(t, err.Error(), c.in)
+	}
+}
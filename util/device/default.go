@@ -2,9 +2,7 @@
 
 package device
 
-import "errors"
-
-var ErrNotApplicable = errors.New("not applicable")
+import "time"
 
 func (t T) IsReadWrite() (bool, error) {
 	return false, ErrNotApplicable
@@ -29,3 +27,11 @@ func (t T) SetReadWrite() error {
 func (t T) SetReadOnly() error {
 	return ErrNotApplicable
 }
+
+func (t T) CheckRead() (time.Duration, error) {
+	return 0, ErrNotApplicable
+}
+
+func (t T) Size() (uint64, error) {
+	return 0, ErrNotApplicable
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/yookoala/realpath"
@@ -15,6 +17,9 @@ import (
 	"opensvc.com/opensvc/util/file"
 )
 
+// checkReadTimeout is the max duration of the CheckRead block read.
+const checkReadTimeout = 10 * time.Second
+
 func (t T) IsReadWrite() (bool, error) {
 	if ro, err := t.IsReadOnly(); err != nil {
 		return false, err
@@ -60,6 +65,24 @@ func (t T) sysfsFileRO() (string, error) {
 	return fmt.Sprintf("%s/ro", p), nil
 }
 
+// Size returns the device size in bytes, read from the sysfs size file
+// counting 512 bytes sectors whatever the device logical block size.
+func (t T) Size() (uint64, error) {
+	canon, err := realpath.Realpath(t.path)
+	if err != nil {
+		return 0, err
+	}
+	b, err := file.ReadAll(fmt.Sprintf("/sys/class/block/%s/size", filepath.Base(canon)))
+	if err != nil {
+		return 0, err
+	}
+	sectors, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return sectors * 512, nil
+}
+
 func (t T) setRO(v bool) error {
 	var action string
 	if v {
@@ -143,3 +166,29 @@ func (t T) Wipe() error {
 	}
 	return nil
 }
+
+//
+// CheckRead reads the first block of the device with dd, bypassing the
+// page cache, and returns the time spent waiting for the read to
+// complete. The read is aborted after checkReadTimeout, so a dead path
+// does not hang the status evaluation.
+//
+func (t T) CheckRead() (time.Duration, error) {
+	cmd := command.New(
+		command.WithName("dd"),
+		command.WithVarArgs("if="+t.path, "of=/dev/null", "bs=4096", "count=1", "iflag=direct"),
+		command.WithLogger(t.log),
+		command.WithBufferedStderr(),
+		command.WithTimeout(checkReadTimeout),
+	)
+	begin := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(begin)
+	if err != nil {
+		if msg := strings.TrimSpace(string(cmd.Stderr())); msg != "" {
+			return elapsed, fmt.Errorf("%s: %s", err, msg)
+		}
+		return elapsed, err
+	}
+	return elapsed, nil
+}
@@ -1,6 +1,7 @@
 package device
 
 import (
+	"errors"
 	"syscall"
 
 	"github.com/rs/zerolog"
@@ -14,6 +15,8 @@ type (
 	}
 )
 
+var ErrNotApplicable = errors.New("not applicable")
+
 const (
 	ModeBlock uint = syscall.S_IFBLK
 	ModeChar  uint = syscall.S_IFCHR
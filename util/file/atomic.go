@@ -0,0 +1,63 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//
+// AtomicWrite installs data in the file p with the perm permissions, so
+// the readers see either the previous or the new content, even after a
+// crash: the data is written and synced to a temporary file in the same
+// directory, renamed over p, and the directory is synced.
+//
+func AtomicWrite(p string, data []byte, perm os.FileMode) error {
+	return AtomicWriteFunc(p, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+//
+// AtomicWriteFunc is the AtomicWrite variant for the producers writing
+// their content to an io.Writer, like the ini and json encoders.
+//
+func AtomicWriteFunc(p string, perm os.FileMode, fn func(io.Writer) error) error {
+	dir := filepath.Dir(p)
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return err
+	}
+	return SyncDir(dir)
+}
+
+// SyncDir commits the directory entries changes, like a rename, to disk.
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
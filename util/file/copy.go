@@ -0,0 +1,43 @@
+package file
+
+import (
+	"io"
+	"os"
+)
+
+//
+// Copy copies the file content from src file path to dst file path,
+// preserving the src file mode, ownership and extended attributes. The
+// dst file is replaced atomically, so an interrupted copy never leaves
+// a truncated dst file.
+//
+// The ownership is not preserved when the process is not privileged
+// enough to change it.
+//
+func Copy(src string, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	info, err := r.Stat()
+	if err != nil {
+		return err
+	}
+	attrs, err := xattrs(src)
+	if err != nil {
+		return err
+	}
+	return AtomicWriteFunc(dst, info.Mode().Perm(), func(w io.Writer) error {
+		if _, err := io.Copy(w, r); err != nil {
+			return err
+		}
+		f := w.(*os.File)
+		if uid, gid, err := Ownership(src); err == nil && uid >= 0 {
+			if err := f.Chown(uid, gid); err != nil && !os.IsPermission(err) {
+				return err
+			}
+		}
+		return setXattrs(f.Name(), attrs)
+	})
+}
@@ -0,0 +1,81 @@
+package file
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "atomic")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p := filepath.Join(dir, "f")
+
+	require.NoError(t, AtomicWrite(p, []byte("foo"), 0600))
+	require.NoError(t, AtomicWrite(p, []byte("bar"), 0640))
+	b, err := ioutil.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, "bar", string(b))
+	mode, err := Mode(p)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), mode.Perm())
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+
+	assert.Error(t, AtomicWrite(filepath.Join(dir, "nodir", "f"), []byte("foo"), 0600))
+}
+
+func TestCopy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	require.NoError(t, ioutil.WriteFile(src, []byte("foo"), 0600))
+	require.NoError(t, os.Chmod(src, 0751))
+	require.NoError(t, ioutil.WriteFile(dst, []byte("previous content"), 0644))
+
+	require.NoError(t, Copy(src, dst))
+	b, err := ioutil.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+	mode, err := Mode(dst)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0751), mode.Perm())
+	srcUID, srcGID, _ := Ownership(src)
+	uid, gid, _ := Ownership(dst)
+	assert.Equal(t, srcUID, uid)
+	assert.Equal(t, srcGID, gid)
+
+	assert.Error(t, Copy(filepath.Join(dir, "nosrc"), dst))
+}
+
+func TestDevicePredicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "predicates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	regular := filepath.Join(dir, "f")
+	require.NoError(t, ioutil.WriteFile(regular, []byte{}, 0600))
+	sock := filepath.Join(dir, "s")
+	l, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	defer l.Close()
+
+	assert.False(t, IsBlockDevice(regular))
+	assert.False(t, IsCharDevice(regular))
+	assert.False(t, IsSocket(regular))
+	assert.True(t, IsSocket(sock))
+	assert.False(t, IsSocket(filepath.Join(dir, "nofile")))
+	if Exists("/dev/null") {
+		assert.True(t, IsCharDevice("/dev/null"))
+		assert.False(t, IsBlockDevice("/dev/null"))
+	}
+}
@@ -1,7 +1,6 @@
 package file
 
 import (
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -53,38 +52,45 @@ func ExistsAndDir(path string) bool {
 	return info.IsDir()
 }
 
-// ExistsAndRegular returns true if the file path exists and is a regular file.
-func ExistsAndRegular(path string) bool {
+// IsBlockDevice returns true if the file path, or the file it links to,
+// is a block device.
+func IsBlockDevice(path string) bool {
 	info, err := os.Stat(path)
-	if os.IsNotExist(err) {
+	if err != nil {
 		return false
 	}
-	return info.Mode().IsRegular()
+	mode := info.Mode()
+	return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice == 0
 }
 
-//
-// Copy copies the file content from src file path to dst file path.
-// If dst does not exist, it is created.
-//
-func Copy(src string, dst string) (err error) {
-	var (
-		r *os.File
-		w *os.File
-	)
-	if r, err = os.Open(src); err != nil {
-		return err
+// IsCharDevice returns true if the file path, or the file it links to,
+// is a character device.
+func IsCharDevice(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
 	}
-	defer r.Close()
-	if w, err = os.Create(dst); err != nil {
-		return err
+	mode := info.Mode()
+	return mode&os.ModeDevice != 0 && mode&os.ModeCharDevice != 0
+}
+
+// IsSocket returns true if the file path, or the file it links to, is a
+// unix socket.
+func IsSocket(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
 	}
-	defer w.Close()
-	if _, err := io.Copy(w, r); err != nil {
-		return err
+	return info.Mode()&os.ModeSocket != 0
+}
+
+// ExistsAndRegular returns true if the file path exists and is a regular file.
+func ExistsAndRegular(path string) bool {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false
 	}
-	r.Close()
-	w.Close()
-	return nil
+	return info.Mode().IsRegular()
 }
 
 //
@@ -0,0 +1,58 @@
+// +build linux
+
+package file
+
+import (
+	"bytes"
+
+	"golang.org/x/sys/unix"
+)
+
+// xattrs returns the extended attributes of the file p, indexed by name.
+func xattrs(p string) (map[string][]byte, error) {
+	m := make(map[string][]byte)
+	size, err := unix.Listxattr(p, nil)
+	switch {
+	case err == unix.ENOTSUP:
+		return m, nil
+	case err != nil:
+		return m, err
+	case size == 0:
+		return m, nil
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Listxattr(p, buf); err != nil {
+		return m, err
+	}
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		n, err := unix.Getxattr(p, string(name), nil)
+		if err != nil {
+			return m, err
+		}
+		value := make([]byte, n)
+		if n, err = unix.Getxattr(p, string(name), value); err != nil {
+			return m, err
+		}
+		m[string(name)] = value[:n]
+	}
+	return m, nil
+}
+
+//
+// setXattrs sets the extended attributes on the file p. The attributes
+// the process is not allowed to set, like the trusted and security
+// namespaces for an unprivileged user, are ignored.
+//
+func setXattrs(p string, m map[string][]byte) error {
+	for name, value := range m {
+		switch err := unix.Setxattr(p, name, value, 0); err {
+		case nil, unix.EPERM, unix.ENOTSUP:
+		default:
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package file
+
+// xattrs returns no extended attributes on the operating systems not
+// supported yet.
+func xattrs(p string) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
+}
+
+func setXattrs(p string, m map[string][]byte) error {
+	return nil
+}
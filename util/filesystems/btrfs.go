@@ -0,0 +1,83 @@
+package filesystems
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	T_BTRFS struct{ T }
+)
+
+// btrfsSnapDir is the directory, relative to the mount point, hosting the
+// read-only snapshot subvolumes.
+const btrfsSnapDir = ".osvc-snap"
+
+func init() {
+	registerFS(NewBTRFS())
+}
+
+func NewBTRFS() *T_BTRFS {
+	t := T_BTRFS{
+		T{fsType: "btrfs", isMultiDevice: true},
+	}
+	return &t
+}
+
+func (t T_BTRFS) subvolume(args ...string) *command.T {
+	return command.New(
+		command.WithName("btrfs"),
+		command.WithArgs(append([]string{"subvolume"}, args...)),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+}
+
+// SnapCreate creates a read-only snapshot subvolume of the mount point
+// in the <mnt>/.osvc-snap/<name> directory.
+func (t T_BTRFS) SnapCreate(dev string, mnt string, name string) error {
+	if err := os.MkdirAll(filepath.Join(mnt, btrfsSnapDir), 0700); err != nil {
+		return err
+	}
+	return t.subvolume("snapshot", "-r", mnt, filepath.Join(mnt, btrfsSnapDir, name)).Run()
+}
+
+// SnapRollback is not supported: the mounted subvolume can not be
+// replaced by its snapshot.
+func (t T_BTRFS) SnapRollback(dev string, mnt string, name string) error {
+	return fmt.Errorf("btrfs rollback: %w, the data can be restored from %s", ErrNotSupported, filepath.Join(mnt, btrfsSnapDir, name))
+}
+
+// SnapRemove deletes the snapshot subvolume.
+func (t T_BTRFS) SnapRemove(dev string, mnt string, name string) error {
+	return t.subvolume("delete", filepath.Join(mnt, btrfsSnapDir, name)).Run()
+}
+
+// Snaps returns the snapshots found in the <mnt>/.osvc-snap directory,
+// oldest first.
+func (t T_BTRFS) Snaps(dev string, mnt string) ([]Snap, error) {
+	l := make([]Snap, 0)
+	entries, err := ioutil.ReadDir(filepath.Join(mnt, btrfsSnapDir))
+	switch {
+	case os.IsNotExist(err):
+		return l, nil
+	case err != nil:
+		return l, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		l = append(l, Snap{Name: e.Name(), Created: e.ModTime()})
+	}
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Created.Before(l[j].Created) })
+	return l, nil
+}
@@ -2,6 +2,7 @@ package filesystems
 
 import (
 	"errors"
+	"time"
 
 	"github.com/rs/zerolog"
 	"opensvc.com/opensvc/util/device"
@@ -47,10 +48,32 @@ type (
 	MKFSer interface {
 		MKFS(string, []string) error
 	}
+
+	// Snap is a named filesystem snapshot.
+	Snap struct {
+		Name    string
+		Created time.Time
+	}
+
+	//
+	// Snapshotter is implemented by the filesystems natively supporting
+	// snapshots. The methods are passed the filesystem device and mount
+	// point.
+	//
+	Snapshotter interface {
+		SnapCreate(dev string, mnt string, name string) error
+		SnapRollback(dev string, mnt string, name string) error
+		SnapRemove(dev string, mnt string, name string) error
+		Snaps(dev string, mnt string) ([]Snap, error)
+	}
 )
 
 var (
 	db = make(map[string]interface{})
+
+	// ErrNotSupported is returned by the snapshot methods not supported
+	// by a filesystem.
+	ErrNotSupported = errors.New("not supported")
 )
 
 func init() {
@@ -58,8 +81,6 @@ func init() {
 	registerFS(&T{fsType: "none", isVirtual: true})
 	registerFS(&T{fsType: "bind", isFileBacked: true})
 	registerFS(&T{fsType: "lofs", isFileBacked: true})
-	registerFS(&T{fsType: "btrfs", isMultiDevice: true})
-	registerFS(&T{fsType: "zfs", isMultiDevice: true})
 	registerFS(&T{fsType: "vfat"})
 	registerFS(&T{fsType: "reiserfs"})
 	registerFS(&T{fsType: "jfs"})
@@ -0,0 +1,62 @@
+package filesystems
+
+import (
+	"strings"
+
+	"opensvc.com/opensvc/util/zfs"
+)
+
+type (
+	T_ZFS struct{ T }
+)
+
+func init() {
+	registerFS(NewZFS())
+}
+
+func NewZFS() *T_ZFS {
+	t := T_ZFS{
+		T{fsType: "zfs", isMultiDevice: true},
+	}
+	return &t
+}
+
+func (t T_ZFS) dataset(dev string) *zfs.Dataset {
+	if t.log == nil {
+		return zfs.NewDataset(dev)
+	}
+	return zfs.NewDataset(dev, zfs.WithLogger(t.log))
+}
+
+// SnapCreate creates the <dataset>@<name> snapshot.
+func (t T_ZFS) SnapCreate(dev string, mnt string, name string) error {
+	return t.dataset(dev).Snapshot(name)
+}
+
+// SnapRollback rolls the dataset back to the <dataset>@<name> snapshot,
+// destroying the more recent snapshots.
+func (t T_ZFS) SnapRollback(dev string, mnt string, name string) error {
+	return t.dataset(dev).Rollback(name)
+}
+
+// SnapRemove destroys the <dataset>@<name> snapshot.
+func (t T_ZFS) SnapRemove(dev string, mnt string, name string) error {
+	return t.dataset(dev + "@" + name).Destroy(false)
+}
+
+// Snaps returns the snapshots of the dataset, oldest first.
+func (t T_ZFS) Snaps(dev string, mnt string) ([]Snap, error) {
+	l := make([]Snap, 0)
+	snaps, err := t.dataset(dev).Snapshots()
+	if err != nil {
+		return l, err
+	}
+	for _, snap := range snaps {
+		name := strings.TrimPrefix(snap.Name, dev+"@")
+		if name == snap.Name {
+			continue
+		}
+		l = append(l, Snap{Name: name, Created: snap.Created})
+	}
+	return l, nil
+}
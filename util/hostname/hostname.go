@@ -1,34 +1,129 @@
+//
+// Package hostname resolves and validates the local node name.
+//
+// The node name is the lowercased operating system hostname, unless
+// overridden by the OSVC_HOSTNAME environment variable or by the node
+// configuration, which is useful in containers and tests. The resolved
+// name is cached, and the cache is invalidated by the overrides changes
+// and by Invalidate.
+//
 package hostname
 
 import (
+	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const regexStringRFC952 = `^[a-zA-Z]([a-zA-Z0-9\-]+[\.]?)*[a-zA-Z0-9]$` // https://tools.ietf.org/html/rfc952
 
+// EnvOverride is the environment variable overriding the node name.
+const EnvOverride = "OSVC_HOSTNAME"
+
 var (
 	regexRFC952 = regexp.MustCompile(regexStringRFC952)
-	hostname    string
+
+	// regexLabel is a RFC 1123 label, lowercased.
+	regexLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+	// reserved are the names refused as node names, because they would
+	// designate a different node on each cluster member.
+	reserved = map[string]interface{}{
+		"localhost": nil,
+	}
+
+	mu           sync.RWMutex
+	hostname     string
+	impersonated string
+	configured   string
+
+	// osHostname is the operating system hostname getter, replaced by
+	// the tests.
+	osHostname = os.Hostname
 )
 
 func IsValid(s string) bool {
 	return regexRFC952.MatchString(s)
 }
 
-// StrictHostname is like os.StrictHostname except it returns a lowercased hostname,
-// and it caches the result to avoid repeating syscalls
+//
+// Validate returns an error if s is not usable as a node name: it must
+// be a lowercase RFC 1123 hostname, with a non-numeric last label so it
+// is never mistaken for an ip address, and it must not be a reserved
+// name like localhost.
+//
+func Validate(s string) error {
+	switch {
+	case s == "":
+		return fmt.Errorf("empty hostname")
+	case len(s) > 253:
+		return fmt.Errorf("hostname %s: longer than 253 characters", s)
+	case s != strings.ToLower(s):
+		return fmt.Errorf("hostname %s: not lowercase", s)
+	}
+	if _, ok := reserved[s]; ok {
+		return fmt.Errorf("hostname %s: reserved name", s)
+	}
+	labels := strings.Split(s, ".")
+	for _, label := range labels {
+		if !regexLabel.MatchString(label) {
+			return fmt.Errorf("hostname %s: invalid label '%s'", s, label)
+		}
+	}
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return fmt.Errorf("hostname %s: numeric last label", s)
+	}
+	return nil
+}
+
+//
+// StrictHostname returns the lowercased node name, or an error if it can
+// not be resolved or is not valid. The result is cached to avoid
+// repeating syscalls.
+//
 func StrictHostname() (string, error) {
+	mu.RLock()
+	s := hostname
+	mu.RUnlock()
+	if s != "" {
+		return s, nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
 	if hostname != "" {
 		return hostname, nil
 	}
-	if s, err := os.Hostname(); err == nil {
-		hostname = strings.ToLower(s)
-		return hostname, nil
-	} else {
+	s, err := resolve()
+	if err != nil {
 		return "", err
 	}
+	if impersonated == "" {
+		if err := Validate(s); err != nil {
+			return "", err
+		}
+	}
+	hostname = s
+	return hostname, nil
+}
+
+// resolve returns the lowercased node name from the highest priority
+// source: impersonation, environment, configuration, operating system.
+func resolve() (string, error) {
+	switch {
+	case impersonated != "":
+		return impersonated, nil
+	case os.Getenv(EnvOverride) != "":
+		return strings.ToLower(os.Getenv(EnvOverride)), nil
+	case configured != "":
+		return configured, nil
+	}
+	s, err := osHostname()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(s), nil
 }
 
 func Hostname() string {
@@ -43,10 +138,48 @@ func Error() error {
 	return nil
 }
 
-// Impersonate eases testing
+// Invalidate drops the cached node name, so the next call resolves it
+// again, for example after a hostname change.
+func Invalidate() {
+	mu.Lock()
+	defer mu.Unlock()
+	hostname = ""
+}
+
+//
+// SetOverride sets the node name configured in the node configuration,
+// used when the OSVC_HOSTNAME environment variable is not set. An empty
+// string removes the override.
+//
+func SetOverride(s string) error {
+	s = strings.ToLower(s)
+	if s != "" {
+		if err := Validate(s); err != nil {
+			return err
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if s != configured {
+		configured = s
+		hostname = ""
+	}
+	return nil
+}
+
+// Impersonate eases testing. The impersonated name is not validated, and
+// has precedence over all the other sources until the returned function
+// is called.
 func Impersonate(s string) func() {
-	saved := "" + hostname
-	fn := func() { hostname = saved }
+	mu.Lock()
+	defer mu.Unlock()
+	saved := impersonated
+	impersonated = s
 	hostname = s
-	return fn
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		impersonated = saved
+		hostname = saved
+	}
 }
@@ -0,0 +1,64 @@
+package hostname
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []string{"node1", "n", "1node", "node-1.example.com", "a0.b1"}
+	for _, s := range valid {
+		assert.NoError(t, Validate(s), s)
+	}
+	invalid := []string{"", "Node1", "node_1", "-node", "node-", "node..com", "node.", "10.0.0.1", "localhost", "a.123"}
+	for _, s := range invalid {
+		assert.Error(t, Validate(s), s)
+	}
+}
+
+func TestOverride(t *testing.T) {
+	savedGetter := osHostname
+	defer func() {
+		osHostname = savedGetter
+		Invalidate()
+	}()
+	os.Unsetenv(EnvOverride)
+	osHostname = func() (string, error) { return "OS-Node", nil }
+	Invalidate()
+	assert.Equal(t, "os-node", Hostname(), "the os hostname is lowercased")
+
+	osHostname = func() (string, error) { return "other", nil }
+	assert.Equal(t, "os-node", Hostname(), "the hostname is cached")
+	Invalidate()
+	assert.Equal(t, "other", Hostname())
+
+	require.NoError(t, SetOverride("cfg-node"))
+	defer func() { _ = SetOverride("") }()
+	assert.Equal(t, "cfg-node", Hostname(), "the configuration overrides the os hostname")
+	assert.Error(t, SetOverride("cfg_node"))
+	assert.Equal(t, "cfg-node", Hostname(), "an invalid override is ignored")
+
+	os.Setenv(EnvOverride, "env-node")
+	defer os.Unsetenv(EnvOverride)
+	Invalidate()
+	assert.Equal(t, "env-node", Hostname(), "the environment overrides the configuration")
+
+	restore := Impersonate("test-node")
+	assert.Equal(t, "test-node", Hostname())
+	restore()
+	assert.Equal(t, "env-node", Hostname())
+
+	os.Setenv(EnvOverride, "env_node")
+	Invalidate()
+	assert.Error(t, Error(), "an invalid hostname is an error")
+	assert.Equal(t, "", Hostname())
+
+	os.Unsetenv(EnvOverride)
+	require.NoError(t, SetOverride(""))
+	osHostname = func() (string, error) { return "", errors.New("no hostname") }
+	assert.Error(t, Error())
+}
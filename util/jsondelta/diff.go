@@ -0,0 +1,78 @@
+package jsondelta
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNotObject is returned by Diff when a document is not a json
+	// object.
+	ErrNotObject = errors.New("not a json object")
+)
+
+//
+// Diff returns the patch transforming the json object a into the json
+// object b. The objects are compared member by member, recursively. The
+// arrays and the values of different types are replaced as a whole. The
+// operations are sorted by path, so the patch is stable.
+//
+func Diff(a, b []byte) (Patch, error) {
+	var da, db map[string]interface{}
+	if err := json.Unmarshal(a, &da); err != nil || da == nil {
+		return nil, errors.Wrap(ErrNotObject, "diff source")
+	}
+	if err := json.Unmarshal(b, &db); err != nil || db == nil {
+		return nil, errors.Wrap(ErrNotObject, "diff target")
+	}
+	p := make(Patch, 0)
+	if err := diffObject(&p, OperationPath{}, da, db); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func diffObject(p *Patch, path OperationPath, a, b map[string]interface{}) error {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		va, inA := a[k]
+		vb, inB := b[k]
+		kpath := append(append(OperationPath{}, path...), k)
+		if !inB {
+			*p = append(*p, Operation{OpPath: kpath, OpKind: "remove"})
+			continue
+		}
+		if inA {
+			ma, okA := va.(map[string]interface{})
+			mb, okB := vb.(map[string]interface{})
+			if okA && okB {
+				if err := diffObject(p, kpath, ma, mb); err != nil {
+					return err
+				}
+				continue
+			}
+			if reflect.DeepEqual(va, vb) {
+				continue
+			}
+		}
+		buff, err := json.Marshal(vb)
+		if err != nil {
+			return err
+		}
+		raw := json.RawMessage(buff)
+		*p = append(*p, Operation{OpPath: kpath, OpKind: "replace", OpValue: &raw})
+	}
+	return nil
+}
@@ -16,6 +16,9 @@ type (
 		OpPath  OperationPath
 		OpValue *json.RawMessage
 		OpKind  string
+
+		// OpFrom is the source path of the move and copy operations
+		OpFrom OperationPath
 	}
 
 	// Patch is a list of Operation
@@ -33,6 +36,26 @@ func NewPatch(b []byte) Patch {
 	return ps
 }
 
+//
+// Marshal returns the daemon encoding of the patch, decoded by NewPatch:
+// a list of [<path>, <value>] replace operations and [<path>] remove
+// operations. The other operations have no daemon encoding.
+//
+func (p Patch) Marshal() ([]byte, error) {
+	l := make([][]interface{}, len(p))
+	for i, o := range p {
+		switch o.OpKind {
+		case "replace":
+			l[i] = []interface{}{o.OpPath, o.OpValue}
+		case "remove":
+			l[i] = []interface{}{o.OpPath}
+		default:
+			return nil, fmt.Errorf("%s operation has no daemon patch encoding", o.OpKind)
+		}
+	}
+	return json.Marshal(l)
+}
+
 // NewOperation allocates and initializes a patch
 func NewOperation(b *json.RawMessage) Operation {
 	o := Operation{}
@@ -67,9 +90,12 @@ func (o Operation) Path() (OperationPath, error) {
 	return o.OpPath, nil
 }
 
-// From is not implemented
+// From returns the source path of the move and copy operations.
 func (o Operation) From() (OperationPath, error) {
-	return nil, errors.New("From() not implemented")
+	if o.OpFrom == nil {
+		return nil, errors.Wrapf(ErrMissing, "%s operation, missing from field", o.OpKind)
+	}
+	return o.OpFrom, nil
 }
 
 // Value returns the value at the specified path
@@ -0,0 +1,145 @@
+package jsondelta
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchApply(t *testing.T) {
+	doc := []byte(`{"a": {"b": 1, "c": [1, 2]}}`)
+	cases := map[string]struct {
+		patch    string
+		expected string
+	}{
+		"replace": {
+			patch:    `[[["a", "b"], 2]]`,
+			expected: `{"a": {"b": 2, "c": [1, 2]}}`,
+		},
+		"create": {
+			patch:    `[[["a", "d"], {"e": true}]]`,
+			expected: `{"a": {"b": 1, "c": [1, 2], "d": {"e": true}}}`,
+		},
+		"remove": {
+			patch:    `[[["a", "b"]]]`,
+			expected: `{"a": {"c": [1, 2]}}`,
+		},
+		"array": {
+			patch:    `[[["a", "c", 1], 3]]`,
+			expected: `{"a": {"b": 1, "c": [1, 3]}}`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := NewPatch([]byte(c.patch)).Apply(doc)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.expected, string(b))
+		})
+	}
+}
+
+func TestPatchApplyMissingPath(t *testing.T) {
+	_, err := NewPatch([]byte(`[[["x", "y"]]]`)).Apply([]byte(`{"a": 1}`))
+	assert.True(t, errors.Is(err, ErrMissing))
+}
+
+func TestPointer(t *testing.T) {
+	p := OperationPath{"monitor", "services", "ns1/svc/s~1", float64(0)}
+	assert.Equal(t, "/monitor/services/ns1~1svc~1s~01/0", p.Pointer())
+	parsed, err := ParsePointer(p.Pointer())
+	require.NoError(t, err)
+	assert.Equal(t, OperationPath{"monitor", "services", "ns1/svc/s~1", "0"}, parsed)
+
+	_, err = ParsePointer("a/b")
+	assert.Error(t, err)
+}
+
+func TestRFC6902(t *testing.T) {
+	p := NewPatch([]byte(`[[["a", "b"], 2], [["a", "c", 0], 3], [["a", "d"]]]`))
+	b, err := p.MarshalRFC6902()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "add", "path": "/a/b", "value": 2},
+		{"op": "replace", "path": "/a/c/0", "value": 3},
+		{"op": "remove", "path": "/a/d"}
+	]`, string(b))
+
+	decoded, err := DecodeRFC6902(b)
+	require.NoError(t, err)
+	doc, err := decoded.Apply([]byte(`{"a": {"b": 1, "c": [1, 2], "d": 4}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": {"b": 2, "c": [3, 2]}}`, string(doc))
+}
+
+func TestRFC6902MoveCopy(t *testing.T) {
+	p, err := DecodeRFC6902([]byte(`[
+		{"op": "copy", "from": "/a", "path": "/b"},
+		{"op": "move", "from": "/a", "path": "/c"},
+		{"op": "test", "path": "/c", "value": 1}
+	]`))
+	require.NoError(t, err)
+	doc, err := p.Apply([]byte(`{"a": 1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"b": 1, "c": 1}`, string(doc))
+
+	_, err = DecodeRFC6902([]byte(`[{"op": "add", "path": "/a"}]`))
+	assert.True(t, errors.Is(err, ErrMissing), "add without value")
+
+	_, err = DecodeRFC6902([]byte(`[{"op": "foo", "path": "/a"}]`))
+	assert.Error(t, err)
+}
+
+func TestSequence(t *testing.T) {
+	patch := func(s string) Patch {
+		return NewPatch([]byte(s))
+	}
+	seq := NewSequence([]byte(`{"a": 0}`), 10, true)
+	require.NoError(t, seq.Apply(11, patch(`[[["a"], 1]]`)))
+	require.NoError(t, seq.Apply(0, patch(`[[["b"], 1]]`)), "unidentified patch")
+	assert.JSONEq(t, `{"a": 1, "b": 1}`, string(seq.Bytes()))
+	assert.Equal(t, uint64(11), seq.Last())
+
+	err := seq.Apply(13, patch(`[[["a"], 3]]`))
+	assert.True(t, errors.Is(err, ErrOutOfOrder), "missed patch")
+	err = seq.Apply(11, patch(`[[["a"], 3]]`))
+	assert.True(t, errors.Is(err, ErrOutOfOrder), "replayed patch")
+	assert.JSONEq(t, `{"a": 1, "b": 1}`, string(seq.Bytes()), "refused patches are not applied")
+
+	lax := NewSequence([]byte(`{"a": 0}`), 10, false)
+	require.NoError(t, lax.Apply(13, patch(`[[["a"], 3]]`)))
+	assert.Equal(t, uint64(13), lax.Last())
+}
+
+func TestDiff(t *testing.T) {
+	a := []byte(`{"a": {"b": 1, "c": [1, 2], "d": "x"}, "e": true}`)
+	b := []byte(`{"a": {"b": 2, "c": [1, 2, 3], "f": {"g": null}}, "e": true}`)
+	p, err := Diff(a, b)
+	require.NoError(t, err)
+	assert.Len(t, p, 4, "b and c replaced, d removed, f added")
+	applied, err := p.Apply(a)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(applied))
+
+	encoded, err := p.MarshalRFC6902()
+	require.NoError(t, err)
+	decoded, err := DecodeRFC6902(encoded)
+	require.NoError(t, err)
+	applied, err = decoded.Apply(a)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(applied))
+
+	encoded, err = p.Marshal()
+	require.NoError(t, err)
+	applied, err = NewPatch(encoded).Apply(a)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(applied), "the daemon encoding decodes to the same patch")
+
+	p, err = Diff(b, b)
+	require.NoError(t, err)
+	assert.Empty(t, p)
+
+	_, err = Diff([]byte(`[1]`), b)
+	assert.True(t, errors.Is(err, ErrNotObject))
+}
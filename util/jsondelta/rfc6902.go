@@ -0,0 +1,146 @@
+package jsondelta
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// RFC6902Operation is a standard JSON Patch operation, as described
+	// in https://tools.ietf.org/html/rfc6902.
+	RFC6902Operation struct {
+		Op    string           `json:"op"`
+		Path  string           `json:"path"`
+		From  string           `json:"from,omitempty"`
+		Value *json.RawMessage `json:"value,omitempty"`
+	}
+
+	// RFC6902Patch is a standard JSON Patch.
+	RFC6902Patch []RFC6902Operation
+)
+
+var (
+	rfc6901Encoder = strings.NewReplacer("~", "~0", "/", "~1")
+)
+
+// ToRFC6902 converts the patch to a standard JSON Patch, so it can be
+// applied by off-the-shelf libraries.
+//
+// The daemon patch replace operation creates the missing keys, so it
+// converts to an "add" operation for object members, which has the same
+// semantic, and to a "replace" operation for array indices.
+func (p Patch) ToRFC6902() RFC6902Patch {
+	l := make(RFC6902Patch, len(p))
+	for i, o := range p {
+		op := RFC6902Operation{
+			Op:    o.OpKind,
+			Path:  o.OpPath.Pointer(),
+			Value: o.OpValue,
+		}
+		if o.OpFrom != nil {
+			op.From = o.OpFrom.Pointer()
+		}
+		if o.OpKind == "replace" && !o.OpPath.isIndexed() {
+			op.Op = "add"
+		}
+		l[i] = op
+	}
+	return l
+}
+
+// MarshalRFC6902 returns the standard JSON Patch encoding of the patch.
+func (p Patch) MarshalRFC6902() ([]byte, error) {
+	return json.Marshal(p.ToRFC6902())
+}
+
+// DecodeRFC6902 decodes a standard JSON Patch into a patch.
+func DecodeRFC6902(b []byte) (Patch, error) {
+	var l RFC6902Patch
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, err
+	}
+	return l.Patch()
+}
+
+// Patch converts the standard JSON Patch to a patch.
+func (l RFC6902Patch) Patch() (Patch, error) {
+	p := make(Patch, len(l))
+	for i, op := range l {
+		o := Operation{
+			OpKind:  op.Op,
+			OpValue: op.Value,
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, errors.Wrapf(ErrMissing, "%s operation on %s", op.Op, op.Path)
+			}
+		case "move", "copy":
+			from, err := ParsePointer(op.From)
+			if err != nil {
+				return nil, err
+			}
+			o.OpFrom = from
+		case "remove":
+		default:
+			return nil, fmt.Errorf("unexpected kind: %s", op.Op)
+		}
+		path, err := ParsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		o.OpPath = path
+		p[i] = o
+	}
+	return p, nil
+}
+
+// ParsePointer returns the operation path from a JSON Pointer, as
+// described in https://tools.ietf.org/html/rfc6901.
+func ParsePointer(s string) (OperationPath, error) {
+	if s == "" {
+		return nil, errors.Wrapf(ErrMissing, "empty json pointer")
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("invalid json pointer %s: must start with /", s)
+	}
+	p := OperationPath{}
+	for _, e := range strings.Split(s[1:], "/") {
+		p = append(p, decodePatchKey(e))
+	}
+	return p, nil
+}
+
+// Pointer returns the JSON Pointer representation of the operation path.
+func (p OperationPath) Pointer() string {
+	var b strings.Builder
+	for _, e := range p {
+		b.WriteString("/")
+		switch v := e.(type) {
+		case float64:
+			b.WriteString(strconv.FormatUint(uint64(v), 10))
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		default:
+			b.WriteString(rfc6901Encoder.Replace(fmt.Sprint(v)))
+		}
+	}
+	return b.String()
+}
+
+// isIndexed returns true if the last element of the path is an array index.
+func (p OperationPath) isIndexed() bool {
+	if len(p) == 0 {
+		return false
+	}
+	switch p[len(p)-1].(type) {
+	case float64, int:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package jsondelta
+
+import (
+	"github.com/pkg/errors"
+)
+
+type (
+	// Sequence applies a stream of identified patches to a json document.
+	//
+	// In strict mode, a patch id not following the previous one is
+	// refused, so the consumer can detect the missed or replayed patches
+	// and resync its document.
+	Sequence struct {
+		strict bool
+		doc    []byte
+		last   uint64
+	}
+)
+
+var (
+	// ErrOutOfOrder is returned in strict mode when a patch id does not
+	// follow the previously applied patch id.
+	ErrOutOfOrder = errors.New("out of order patch")
+)
+
+// NewSequence returns a patch sequence applying to doc, whose version is
+// identified by id. A zero id means the document version is unknown, in
+// which case the first identified patch is accepted whatever its id.
+func NewSequence(doc []byte, id uint64, strict bool) *Sequence {
+	return &Sequence{
+		strict: strict,
+		doc:    doc,
+		last:   id,
+	}
+}
+
+// Apply applies the patch identified by id to the document. The patches
+// with a zero id are not verified.
+func (t *Sequence) Apply(id uint64, p Patch) error {
+	if t.strict && id != 0 && t.last != 0 && id != t.last+1 {
+		return errors.Wrapf(ErrOutOfOrder, "patch id %d received after %d", id, t.last)
+	}
+	doc, err := p.Apply(t.doc)
+	if err != nil {
+		return err
+	}
+	t.doc = doc
+	if id != 0 {
+		t.last = id
+	}
+	return nil
+}
+
+// Bytes returns the patched document.
+func (t Sequence) Bytes() []byte {
+	return t.doc
+}
+
+// Last returns the id of the last applied patch.
+func (t Sequence) Last() uint64 {
+	return t.last
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// JournaldConfig is the configuration of a journald writer.
+	JournaldConfig struct {
+		// Socket is the journald native protocol socket path. Default is
+		// DefaultJournaldSocket.
+		Socket string
+
+		// Level is the minimum level of the events written.
+		Level zerolog.Level
+
+		// Identifier is the SYSLOG_IDENTIFIER field value. Default is
+		// "opensvc".
+		Identifier string
+	}
+
+	// JournaldWriter is a zerolog level writer duplicating the events to
+	// journald, using the native protocol. The event fields are written as
+	// OPENSVC_<NAME> journal fields.
+	JournaldWriter struct {
+		sync.Mutex
+		config JournaldConfig
+		conn   net.Conn
+	}
+)
+
+// DefaultJournaldSocket is the journald native protocol socket path.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// NewJournaldWriter returns a journald writer connected to the journald
+// socket.
+func NewJournaldWriter(config JournaldConfig) (*JournaldWriter, error) {
+	if config.Socket == "" {
+		config.Socket = DefaultJournaldSocket
+	}
+	if config.Identifier == "" {
+		config.Identifier = "opensvc"
+	}
+	conn, err := net.Dial("unixgram", config.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("journald connect: %w", err)
+	}
+	return &JournaldWriter{
+		config: config,
+		conn:   conn,
+	}, nil
+}
+
+// Write writes the event at the info level.
+func (t *JournaldWriter) Write(p []byte) (int, error) {
+	return t.WriteLevel(zerolog.InfoLevel, p)
+}
+
+// WriteLevel writes the event if its level is at least the configured
+// level.
+func (t *JournaldWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < t.config.Level || level == zerolog.Disabled {
+		return len(p), nil
+	}
+	e, err := decodeEntry(p)
+	if err != nil {
+		return 0, err
+	}
+	t.Lock()
+	defer t.Unlock()
+	if _, err := t.conn.Write(t.format(level, e)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// format returns the journald native protocol datagram of the event, as
+// described in https://systemd.io/JOURNAL_NATIVE_PROTOCOL/.
+func (t *JournaldWriter) format(level zerolog.Level, e entry) []byte {
+	var b bytes.Buffer
+	writeJournalField(&b, "MESSAGE", e.message)
+	writeJournalField(&b, "PRIORITY", strconv.Itoa(severity(level)))
+	writeJournalField(&b, "SYSLOG_IDENTIFIER", t.config.Identifier)
+	for _, f := range e.fields {
+		writeJournalField(&b, "OPENSVC_"+journalName(f.name), f.value)
+	}
+	return b.Bytes()
+}
+
+// writeJournalField writes a KEY=value line, or the binary safe
+// KEY\n<little endian uint64 length>value\n form if the value contains a
+// newline.
+func writeJournalField(b *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name + "=" + value + "\n")
+		return
+	}
+	b.WriteString(name + "\n")
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value + "\n")
+}
+
+// journalName returns the name converted to the journal field name
+// charset: uppercase letters, digits and underscores.
+func journalName(s string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(s) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// Close closes the journald connection.
+func (t *JournaldWriter) Close() error {
+	return t.conn.Close()
+}
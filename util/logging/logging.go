@@ -73,7 +73,8 @@ func Configure(config Config) *Logger {
 			writers = append(writers, fileWriter)
 		}
 	}
-	mw := io.MultiWriter(writers...)
+	writers = append(writers, sinks...)
+	mw := zerolog.MultiLevelWriter(writers...)
 
 	// zerolog.SetGlobalLevel(zerolog.DebugLevel)
 	logger := zerolog.New(mw).With().Timestamp().Logger()
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/rs/zerolog"
+)
+
+type (
+	// entry is a decoded zerolog json event.
+	entry struct {
+		message string
+
+		// fields are the event fields but the level, timestamp and
+		// message, sorted by name.
+		fields []field
+	}
+
+	field struct {
+		name  string
+		value string
+	}
+)
+
+var (
+	sinks []io.Writer
+)
+
+// SetDefaultSinks sets the writers the log events are duplicated to, like
+// the syslog or journald writers, in addition to the console and file
+// writers.
+func SetDefaultSinks(l ...io.Writer) {
+	sinks = l
+}
+
+// ParseLevel returns the zerolog level of a syslog style criticity name:
+// critical, error, warning, info or debug.
+func ParseLevel(s string) (zerolog.Level, error) {
+	switch s {
+	case "critical":
+		return zerolog.FatalLevel, nil
+	case "error":
+		return zerolog.ErrorLevel, nil
+	case "warning":
+		return zerolog.WarnLevel, nil
+	case "info", "":
+		return zerolog.InfoLevel, nil
+	case "debug":
+		return zerolog.DebugLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("invalid log level %q: critical, error, warning, info or debug expected", s)
+	}
+}
+
+// severity returns the syslog severity of a zerolog level, as described in
+// https://tools.ietf.org/html/rfc5424#section-6.2.1.
+func severity(level zerolog.Level) int {
+	switch level {
+	case zerolog.PanicLevel:
+		return 1 // alert
+	case zerolog.FatalLevel:
+		return 2 // critical
+	case zerolog.ErrorLevel:
+		return 3 // error
+	case zerolog.WarnLevel:
+		return 4 // warning
+	case zerolog.DebugLevel, zerolog.TraceLevel:
+		return 7 // debug
+	default:
+		return 6 // informational
+	}
+}
+
+// decodeEntry decodes a zerolog json event.
+func decodeEntry(p []byte) (entry, error) {
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(p, &m); err != nil {
+		return entry{}, err
+	}
+	e := entry{}
+	if v, ok := m[zerolog.MessageFieldName]; ok {
+		e.message = fmt.Sprint(v)
+	}
+	for k, v := range m {
+		switch k {
+		case zerolog.MessageFieldName, zerolog.LevelFieldName, zerolog.TimestampFieldName:
+			continue
+		}
+		f := field{name: k}
+		if s, ok := v.(string); ok {
+			f.value = s
+		} else {
+			b, _ := json.Marshal(v)
+			f.value = string(b)
+		}
+		e.fields = append(e.fields, f)
+	}
+	sort.Slice(e.fields, func(i, j int) bool {
+		return e.fields[i].name < e.fields[j].name
+	})
+	return e, nil
+}
@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPacket(t *testing.T, conn net.PacketConn) []byte {
+	buf := make([]byte, 65536)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	return buf[:n]
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("warning")
+	require.NoError(t, err)
+	assert.Equal(t, zerolog.WarnLevel, level)
+	_, err = ParseLevel("notice")
+	assert.Error(t, err)
+}
+
+func TestSyslogWriter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewSyslogWriter(SyslogConfig{
+		Network:  "udp",
+		Addr:     conn.LocalAddr().String(),
+		Facility: "local0",
+		Level:    zerolog.InfoLevel,
+		Hostname: "node1",
+	})
+	require.NoError(t, err)
+	defer w.Close()
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(w))
+	logger.Debug().Msg("filtered out")
+	logger.Warn().Str("o", "svc1").Str("quote", `a "b" [c]`).Int("n", 3).Msg("hello")
+
+	b := readPacket(t, conn)
+	prefix := fmt.Sprintf("<%d>1 ", 16*8+4)
+	assert.True(t, bytes.HasPrefix(b, []byte(prefix)), "priority: %s", b)
+	suffix := fmt.Sprintf(` node1 opensvc %d - [opensvc@32473 n="3" o="svc1" quote="a \"b\" [c\]"] hello`, os.Getpid())
+	assert.True(t, bytes.HasSuffix(b, []byte(suffix)), "got %s", b)
+}
+
+func TestSyslogWriterNoField(t *testing.T) {
+	w := &SyslogWriter{config: SyslogConfig{Hostname: "node1", AppName: "opensvc"}, facility: 3}
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	b := w.format(zerolog.ErrorLevel, now, entry{message: "failed"})
+	assert.Equal(t, fmt.Sprintf("<27>1 2020-01-02T03:04:05.000006Z node1 opensvc %d - - failed", os.Getpid()), string(b))
+}
+
+func TestSdName(t *testing.T) {
+	assert.Equal(t, "ab", sdName(`a= "]b`))
+	assert.Len(t, sdName("abcdefghijklmnopqrstuvwxyz0123456789"), 32)
+}
+
+func TestJournaldWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "socket")
+	conn, err := net.ListenPacket("unixgram", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewJournaldWriter(JournaldConfig{Socket: socket, Level: zerolog.InfoLevel})
+	require.NoError(t, err)
+	defer w.Close()
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(w))
+	logger.Error().Str("rid", "fs#1").Str("out", "a\nb").Msg("failed")
+
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE=failed\nPRIORITY=3\nSYSLOG_IDENTIFIER=opensvc\n")
+	expected.WriteString("OPENSVC_OUT\n")
+	_ = binary.Write(&expected, binary.LittleEndian, uint64(3))
+	expected.WriteString("a\nb\n")
+	expected.WriteString("OPENSVC_RID=fs#1\n")
+	assert.Equal(t, expected.String(), string(readPacket(t, conn)))
+}
@@ -36,6 +36,7 @@ type (
 		ConvertPV       string `json:"convert_pv"`
 		MirrorLog       string `json:"mirror_log"`
 		Devices         string `json:"devices"`
+		LVTime          string `json:"lv_time"`
 	}
 	driver struct{}
 	LV     struct {
@@ -284,3 +285,71 @@ func (t *LV) Remove(args []string) error {
 	}
 	return nil
 }
+
+// Snapshot creates the <name> snapshot logical volume of the logical
+// volume, with <size> copy-on-write space.
+func (t *LV) Snapshot(name string, size string) error {
+	if i, err := sizeconv.FromSize(size); err == nil {
+		// default unit is not "B", explicitely tell
+		size = fmt.Sprintf("%dB", i)
+	}
+	cmd := command.New(
+		command.WithName("lvcreate"),
+		command.WithVarArgs("--yes", "-s", "-L", size, "-n", name, t.FQN()),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// Merge merges the snapshot logical volume into its origin. If the
+// origin is open, the merge is deferred to its next activation.
+func (t *LV) Merge() error {
+	cmd := command.New(
+		command.WithName("lvconvert"),
+		command.WithVarArgs("--merge", t.FQN()),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	cmd.Run()
+	if cmd.ExitCode() != 0 {
+		return fmt.Errorf("%s error %d", cmd, cmd.ExitCode())
+	}
+	return nil
+}
+
+// Snapshots returns the snapshot logical volumes of the logical volume.
+func (t *LV) Snapshots() ([]LVInfo, error) {
+	l := make([]LVInfo, 0)
+	data := LVData{}
+	cmd := command.New(
+		command.WithName("lvs"),
+		command.WithVarArgs("-o", "lv_name,vg_name,origin,lv_time", "--reportformat", "json", t.VGName),
+		command.WithLogger(t.log),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(cmd.Stdout(), &data); err != nil {
+		return nil, err
+	}
+	for _, report := range data.Report {
+		for _, info := range report.LV {
+			if info.Origin == t.LVName {
+				l = append(l, info)
+			}
+		}
+	}
+	return l, nil
+}
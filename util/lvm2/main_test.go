@@ -0,0 +1,47 @@
+// +build linux
+
+package lvm2
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/util/command"
+)
+
+func TestLV(t *testing.T) {
+	fake := command.NewFake()
+	restore := command.SetExecutor(fake)
+	defer restore()
+	log := zerolog.Nop()
+	lv := NewLV("vg1", "lv1", WithLogger(&log))
+
+	t.Run("active", func(t *testing.T) {
+		fake.Set("lvs", command.Result{Stdout: []byte(`{"report": [{"lv": [{"vg_name": "vg1", "lv_attr": "-wi-a-----"}]}]}`)})
+		active, err := lv.IsActive()
+		require.Nil(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("not existing", func(t *testing.T) {
+		fake.Set("lvs", command.Result{ExitCode: 5})
+		exists, err := lv.Exists()
+		require.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("activate", func(t *testing.T) {
+		fake.Set("lvchange", command.Result{})
+		require.Nil(t, lv.Activate())
+		calls := fake.CallsOf("lvchange")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "lvchange -ay vg1/lv1", calls[0].String())
+	})
+
+	t.Run("activate error", func(t *testing.T) {
+		fake.Set("lvchange", command.Result{ExitCode: 3})
+		assert.NotNil(t, lv.Activate())
+	})
+}
@@ -0,0 +1,290 @@
+//
+// Package mountinfo parses the kernel mount table, so the mount checks
+// don't depend on the mount or findmnt commands output format.
+//
+// The /proc/self/mountinfo lines format is:
+//
+//   36 35 98:0 /mnt1 /mnt/parent rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//   (1)(2)(3)   (4)   (5)         (6)       (7)     (8) (9)  (10)      (11)
+//
+// with zero or more optional fields (7) ended by the "-" separator (8).
+//
+package mountinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	// Entry is a mount of the mount table.
+	Entry struct {
+		ID             int
+		ParentID       int
+		Major          int
+		Minor          int
+		Root           string
+		MountPoint     string
+		Options        string
+		OptionalFields []string
+		FSType         string
+		Source         string
+		SuperOptions   string
+	}
+)
+
+var (
+	// File is the mount table file parsed by Load.
+	File = "/proc/self/mountinfo"
+)
+
+// Load returns the entries of the mount table, in mount order.
+func Load() ([]Entry, error) {
+	f, err := os.Open(File)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse returns the entries of the mountinfo formatted table read from
+// r, in read order.
+func Parse(r io.Reader) ([]Entry, error) {
+	l := make([]Entry, 0)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		if s.Text() == "" {
+			continue
+		}
+		e, err := parseLine(s.Text())
+		if err != nil {
+			return l, err
+		}
+		l = append(l, e)
+	}
+	return l, s.Err()
+}
+
+func parseLine(line string) (Entry, error) {
+	var (
+		e   Entry
+		err error
+	)
+	fields := strings.Fields(line)
+	sep := -1
+	for i := 6; i < len(fields); i++ {
+		if fields[i] == "-" {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 || len(fields) < sep+4 {
+		return e, fmt.Errorf("mountinfo: unexpected line %q", line)
+	}
+	if e.ID, err = strconv.Atoi(fields[0]); err != nil {
+		return e, fmt.Errorf("mountinfo: id: %w", err)
+	}
+	if e.ParentID, err = strconv.Atoi(fields[1]); err != nil {
+		return e, fmt.Errorf("mountinfo: parent id: %w", err)
+	}
+	majorMinor := strings.SplitN(fields[2], ":", 2)
+	if len(majorMinor) != 2 {
+		return e, fmt.Errorf("mountinfo: unexpected major:minor %s", fields[2])
+	}
+	if e.Major, err = strconv.Atoi(majorMinor[0]); err != nil {
+		return e, fmt.Errorf("mountinfo: major: %w", err)
+	}
+	if e.Minor, err = strconv.Atoi(majorMinor[1]); err != nil {
+		return e, fmt.Errorf("mountinfo: minor: %w", err)
+	}
+	e.Root = unescape(fields[3])
+	e.MountPoint = unescape(fields[4])
+	e.Options = fields[5]
+	e.OptionalFields = append([]string{}, fields[6:sep]...)
+	e.FSType = fields[sep+1]
+	e.Source = unescape(fields[sep+2])
+	e.SuperOptions = fields[sep+3]
+	return e, nil
+}
+
+// unescape decodes the \ooo octal escapes of the space, tab, newline
+// and backslash characters of the paths.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+//
+// IsMounted returns true if dev is mounted on mnt. A dev directory is
+// matched as the source of a bind mount. An empty dev matches any
+// source.
+//
+func IsMounted(dev, mnt string) (bool, error) {
+	l, err := Load()
+	if err != nil {
+		return false, err
+	}
+	return isMounted(l, dev, mnt), nil
+}
+
+func isMounted(l []Entry, dev, mnt string) bool {
+	mnt = filepath.Clean(mnt)
+	if dev != "" && filepath.IsAbs(dev) {
+		if info, err := os.Stat(dev); err == nil && info.IsDir() {
+			return isBindMounted(l, filepath.Clean(dev), mnt)
+		}
+	}
+	for _, e := range l {
+		if e.MountPoint != mnt {
+			continue
+		}
+		if dev == "" || sameSource(e.Source, dev) {
+			return true
+		}
+	}
+	return false
+}
+
+//
+// isBindMounted returns true if the dir is bind mounted on mnt: the mnt
+// entry is on the same device and has for root the dir path relative to
+// the mount point of its filesystem.
+//
+func isBindMounted(l []Entry, dir, mnt string) bool {
+	host, ok := mountOf(l, dir)
+	if !ok {
+		return false
+	}
+	rel, err := filepath.Rel(host.MountPoint, dir)
+	if err != nil {
+		return false
+	}
+	root := filepath.Join(host.Root, rel)
+	for _, e := range l {
+		if e.MountPoint == mnt && e.Major == host.Major && e.Minor == host.Minor && e.Root == root {
+			return true
+		}
+	}
+	return false
+}
+
+// mountOf returns the last mounted entry having the longest mount point
+// containing the path.
+func mountOf(l []Entry, p string) (Entry, bool) {
+	var (
+		found Entry
+		ok    bool
+	)
+	for _, e := range l {
+		if !isUnder(p, e.MountPoint) {
+			continue
+		}
+		if !ok || len(e.MountPoint) >= len(found.MountPoint) {
+			found = e
+			ok = true
+		}
+	}
+	return found, ok
+}
+
+// sameSource returns true if the mount source is dev, or if both are
+// links resolving to the same path, like /dev/vg/lv and /dev/mapper/vg-lv.
+func sameSource(source, dev string) bool {
+	if source == dev {
+		return true
+	}
+	if !filepath.IsAbs(source) || !filepath.IsAbs(dev) {
+		return false
+	}
+	a, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return false
+	}
+	b, err := filepath.EvalSymlinks(dev)
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+// isUnder returns true if p is the dir or a descendant of the dir.
+func isUnder(p, dir string) bool {
+	if dir == "/" || p == dir {
+		return true
+	}
+	return strings.HasPrefix(p, dir+"/")
+}
+
+// MountsUnder returns the entries mounted on the path or below, in mount
+// order, so the parents are before their children.
+func MountsUnder(p string) ([]Entry, error) {
+	l, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return mountsUnder(l, p), nil
+}
+
+func mountsUnder(l []Entry, p string) []Entry {
+	p = filepath.Clean(p)
+	under := make([]Entry, 0)
+	for _, e := range l {
+		if isUnder(e.MountPoint, p) {
+			under = append(under, e)
+		}
+	}
+	return under
+}
+
+//
+// UmountTree unmounts the mounts on the path and below, children first,
+// with the umount command. It stops on the first umount failure. The
+// commands are not logged if log is nil.
+//
+func UmountTree(p string, log *zerolog.Logger) error {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+	l, err := MountsUnder(p)
+	if err != nil {
+		return err
+	}
+	for i := len(l) - 1; i >= 0; i-- {
+		mnt := l[i].MountPoint
+		cmd := command.New(
+			command.WithName("umount"),
+			command.WithVarArgs(mnt),
+			command.WithLogger(log),
+			command.WithCommandLogLevel(zerolog.InfoLevel),
+			command.WithStdoutLogLevel(zerolog.InfoLevel),
+			command.WithStderrLogLevel(zerolog.ErrorLevel),
+		)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("umount %s: %w", mnt, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package mountinfo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/util/command"
+)
+
+const table = `22 1 253:0 / / rw,relatime shared:1 - ext4 /dev/mapper/root rw
+23 22 0:21 / /proc rw,nosuid - proc proc rw
+40 22 253:3 / /srv/svc1 rw,relatime shared:20 - xfs /dev/mapper/vg1-svc1 rw,attr2
+41 40 0:45 / /srv/svc1/tmp rw - tmpfs tmpfs rw
+42 22 253:0 /srv/data /srv/bind rw,relatime shared:1 - ext4 /dev/mapper/root rw
+43 22 0:50 / /srv/with\040space rw master:3 - zfs tank/svc2 rw
+`
+
+func TestParse(t *testing.T) {
+	l, err := Parse(strings.NewReader(table))
+	require.Nil(t, err)
+	require.Len(t, l, 6)
+	assert.Equal(t, Entry{
+		ID:             40,
+		ParentID:       22,
+		Major:          253,
+		Minor:          3,
+		Root:           "/",
+		MountPoint:     "/srv/svc1",
+		Options:        "rw,relatime",
+		OptionalFields: []string{"shared:20"},
+		FSType:         "xfs",
+		Source:         "/dev/mapper/vg1-svc1",
+		SuperOptions:   "rw,attr2",
+	}, l[2])
+	assert.Equal(t, []string{}, l[1].OptionalFields)
+	assert.Equal(t, "/srv/with space", l[5].MountPoint)
+
+	t.Run("invalid line", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("22 1 253:0 / / rw\n"))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestIsMounted(t *testing.T) {
+	l, err := Parse(strings.NewReader(table))
+	require.Nil(t, err)
+	assert.True(t, isMounted(l, "/dev/mapper/vg1-svc1", "/srv/svc1/"))
+	assert.True(t, isMounted(l, "", "/srv/svc1"))
+	assert.True(t, isMounted(l, "tank/svc2", "/srv/with space"))
+	assert.False(t, isMounted(l, "/dev/mapper/vg1-svc2", "/srv/svc1"))
+	assert.False(t, isMounted(l, "", "/srv/svc2"))
+
+	t.Run("bind mount", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "mountinfo")
+		require.Nil(t, err)
+		defer os.RemoveAll(dir)
+		data := filepath.Join(dir, "data")
+		require.Nil(t, os.Mkdir(data, 0755))
+		l := []Entry{
+			{Major: 253, Minor: 0, Root: "/", MountPoint: "/"},
+			{Major: 253, Minor: 0, Root: data, MountPoint: "/srv/bind"},
+			{Major: 253, Minor: 0, Root: "/other", MountPoint: "/srv/other"},
+		}
+		assert.True(t, isMounted(l, data, "/srv/bind"))
+		assert.False(t, isMounted(l, data, "/srv/other"))
+	})
+}
+
+func TestUmountTree(t *testing.T) {
+	f, err := ioutil.TempFile("", "mountinfo")
+	require.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(table)
+	require.Nil(t, err)
+	f.Close()
+	defer func(s string) { File = s }(File)
+	File = f.Name()
+
+	l, err := MountsUnder("/srv/svc1")
+	require.Nil(t, err)
+	require.Len(t, l, 2)
+	assert.Equal(t, "/srv/svc1", l[0].MountPoint)
+
+	fake := command.NewFake()
+	fake.Set("umount", command.Result{})
+	restore := command.SetExecutor(fake)
+	defer restore()
+	require.Nil(t, UmountTree("/srv/svc1", nil))
+	calls := fake.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "umount /srv/svc1/tmp", calls[0].String())
+	assert.Equal(t, "umount /srv/svc1", calls[1].String())
+}
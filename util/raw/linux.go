@@ -10,6 +10,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"time"
 
@@ -57,6 +58,12 @@ func (t Entry) CDevPath() string {
 	return CDevPath(t.Index)
 }
 
+// BDevMajorMinor returns the "<major>:<minor>" identifier of the block
+// device bound to the raw device.
+func (t Entry) BDevMajorMinor() string {
+	return fmt.Sprintf("%d:%d", t.BDevMajor, t.BDevMinor)
+}
+
 func (t Entry) BDevPath() string {
 	sys := fmt.Sprintf("/sys/dev/block/%d:%d", t.BDevMajor, t.BDevMinor)
 	p, err := os.Readlink(sys)
@@ -130,7 +137,7 @@ func (t T) NextMinor() int {
 }
 
 func (t Entries) NextMinor() int {
-	for i := 1; i < 2^20; i++ {
+	for i := 1; i < 1<<20; i++ {
 		if !t.HasIndex(i) {
 			return i
 		}
@@ -183,6 +190,28 @@ func (t T) Data() (Entries, error) {
 	return Entries(data), nil
 }
 
+// List returns the raw device bindings, sorted by raw device minor.
+func (t T) List() (Entries, error) {
+	data, err := t.Data()
+	if err != nil {
+		return data, err
+	}
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Index < data[j].Index
+	})
+	return data, nil
+}
+
+// FindByMajorMinor returns the binding of the block device <major>:<minor>,
+// or nil if the block device is not bound to a raw device.
+func (t T) FindByMajorMinor(major, minor int) (*Entry, error) {
+	data, err := t.List()
+	if err != nil {
+		return nil, err
+	}
+	return data.BDev(major, minor), nil
+}
+
 func (t T) Has(bDevPath string) (bool, error) {
 	data, err := t.Data()
 	if err != nil {
@@ -314,7 +343,7 @@ func (t Entries) BDevPath(s string) *Entry {
 }
 
 func (t Entries) HasBDevPath(s string) bool {
-	e := t.CDevPath(s)
+	e := t.BDevPath(s)
 	return e != nil
 }
 
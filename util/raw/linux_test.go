@@ -31,3 +31,18 @@ func TestRaw(t *testing.T) {
 	//err = ra.Unbind(minor)
 	//assert.Nil(t, err)
 }
+
+func TestEntries(t *testing.T) {
+	data := Entries{
+		{Index: 1, BDevMajor: 8, BDevMinor: 0},
+		{Index: 2, BDevMajor: 8, BDevMinor: 16},
+	}
+	e := data.BDev(8, 16)
+	if assert.NotNil(t, e) {
+		assert.Equal(t, 2, e.Index)
+		assert.Equal(t, "8:16", e.BDevMajorMinor())
+		assert.Equal(t, "/dev/raw/raw2", e.CDevPath())
+	}
+	assert.Nil(t, data.BDev(8, 32))
+	assert.Equal(t, 3, data.NextMinor())
+}
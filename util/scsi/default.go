@@ -0,0 +1,19 @@
+// +build !linux
+
+package scsi
+
+import "github.com/rs/zerolog"
+
+func Rescan(log *zerolog.Logger) error {
+	return ErrNotApplicable
+}
+
+func ReloadMultipath(log *zerolog.Logger) error {
+	return ErrNotApplicable
+}
+
+func Settle() {}
+
+func Scan() (Inventory, error) {
+	return nil, ErrNotApplicable
+}
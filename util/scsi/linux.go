@@ -0,0 +1,109 @@
+// +build linux
+
+package scsi
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/udevadm"
+)
+
+var (
+	// devicePatterns are the /sys/block entries inventoried.
+	devicePatterns = []string{"sd*", "vd*", "nvme*", "dm-*"}
+)
+
+// Rescan asks all the scsi hosts to scan for new targets and logical units.
+func Rescan(log *zerolog.Logger) error {
+	l, err := filepath.Glob("/sys/class/scsi_host/host*/scan")
+	if err != nil {
+		return err
+	}
+	for _, p := range l {
+		log.Info().Msgf("rescan %s", filepath.Base(filepath.Dir(p)))
+		if err := ioutil.WriteFile(p, []byte("- - -"), 0200); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReloadMultipath asks multipathd to rebuild its maps, so the paths of the
+// new logical units are assembled. It is a noop if multipathd is not
+// installed.
+func ReloadMultipath(log *zerolog.Logger) error {
+	if _, err := exec.LookPath("multipathd"); err != nil {
+		return nil
+	}
+	cmd := command.New(
+		command.WithName("multipathd"),
+		command.WithVarArgs("reconfigure"),
+		command.WithLogger(log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.WarnLevel),
+	)
+	return cmd.Run()
+}
+
+// Settle waits for the udev events queue to drain, so the device files of
+// the new logical units are created.
+func Settle() {
+	udevadm.Settle()
+}
+
+// Scan returns the block devices inventory.
+func Scan() (Inventory, error) {
+	l := make(Inventory, 0)
+	for _, pattern := range devicePatterns {
+		matches, err := filepath.Glob(filepath.Join("/sys/block", pattern))
+		if err != nil {
+			return l, err
+		}
+		for _, p := range matches {
+			l = append(l, scanOne(p))
+		}
+	}
+	l.sort()
+	return l, nil
+}
+
+func scanOne(p string) Device {
+	name := filepath.Base(p)
+	d := Device{
+		Name: name,
+		Path: "/dev/" + name,
+	}
+	if s, err := readString(filepath.Join(p, "size")); err == nil {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			d.Size = i * 512
+		}
+	}
+	for _, e := range []string{"device/wwid", "wwid", "dm/uuid"} {
+		if s, err := readString(filepath.Join(p, e)); err == nil && s != "" {
+			d.WWID = s
+			break
+		}
+	}
+	if s, err := readString(filepath.Join(p, "dm/name")); err == nil && s != "" {
+		if file.Exists("/dev/mapper/" + s) {
+			d.Path = "/dev/mapper/" + s
+		}
+	}
+	return d
+}
+
+func readString(p string) (string, error) {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,59 @@
+// Package scsi rescans the scsi hosts for new logical units and
+// inventories the block devices, so the devices appeared after a rescan
+// can be reported.
+package scsi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	// Device is a block device inventory entry.
+	Device struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Size int64  `json:"size"`
+		WWID string `json:"wwid,omitempty"`
+	}
+
+	// Inventory is a list of block devices, sorted by name.
+	Inventory []Device
+)
+
+// ErrNotApplicable is returned by the functions not supported on the
+// operating system.
+var ErrNotApplicable = errors.New("not applicable")
+
+func (t Device) String() string {
+	s := fmt.Sprintf("%s %s", t.Path, sizeconv.BSizeCompact(float64(t.Size)))
+	if t.WWID != "" {
+		s += " " + t.WWID
+	}
+	return s
+}
+
+func (t Inventory) sort() {
+	sort.Slice(t, func(i, j int) bool {
+		return t[i].Name < t[j].Name
+	})
+}
+
+// Diff returns the devices of the inventory absent from the previous
+// inventory.
+func (t Inventory) Diff(previous Inventory) Inventory {
+	m := make(map[string]interface{})
+	for _, d := range previous {
+		m[d.Name] = nil
+	}
+	l := make(Inventory, 0)
+	for _, d := range t {
+		if _, ok := m[d.Name]; !ok {
+			l = append(l, d)
+		}
+	}
+	return l
+}
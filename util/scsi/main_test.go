@@ -0,0 +1,14 @@
+package scsi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	previous := Inventory{{Name: "sda"}, {Name: "sdb"}}
+	current := Inventory{{Name: "sda"}, {Name: "sdb"}, {Name: "sdc", Path: "/dev/sdc"}}
+	assert.Equal(t, Inventory{{Name: "sdc", Path: "/dev/sdc"}}, current.Diff(previous))
+	assert.Len(t, previous.Diff(current), 0)
+}
@@ -0,0 +1,281 @@
+package scsi
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	//
+	// PersistentReservationHandle drives the scsi3 persistent reservation
+	// of a device, using the sg_persist command. The reservation type is 5,
+	// write exclusive registrants only.
+	//
+	PersistentReservationHandle struct {
+		Key            string
+		Device         string
+		NoPreemptAbort bool
+		Log            *zerolog.Logger
+	}
+)
+
+const (
+	// prType is the write exclusive registrants only reservation type.
+	prType = "5"
+)
+
+// NormalizeKey returns the lowercased, 0x-prefixed, zero-stripped
+// hexadecimal representation of a persistent reservation key.
+func NormalizeKey(s string) (string, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+	i, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid persistent reservation key '%s'", s)
+	}
+	return fmt.Sprintf("0x%x", i), nil
+}
+
+func (t PersistentReservationHandle) sgPersist(args ...string) ([]byte, error) {
+	args = append([]string{"-n"}, args...)
+	args = append(args, t.Device)
+	cmd := command.New(
+		command.WithName("sg_persist"),
+		command.WithArgs(args),
+		command.WithLogger(t.Log),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.WarnLevel),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return cmd.Stdout(), nil
+}
+
+func (t PersistentReservationHandle) sgPersistOut(args ...string) error {
+	t.Log.Info().Msgf("%s: %s", t.Device, strings.Join(args, " "))
+	_, err := t.sgPersist(append([]string{"--out"}, args...)...)
+	return err
+}
+
+// ReadRegistrations returns the normalized keys registered on the device.
+func (t PersistentReservationHandle) ReadRegistrations() ([]string, error) {
+	b, err := t.sgPersist("--in", "--read-keys")
+	if err != nil {
+		return nil, err
+	}
+	return parseRegistrations(b), nil
+}
+
+// ReadReservation returns the normalized key of the reservation holder,
+// or an empty string if the device is not reserved.
+func (t PersistentReservationHandle) ReadReservation() (string, error) {
+	b, err := t.sgPersist("--in", "--read-reservation")
+	if err != nil {
+		return "", err
+	}
+	return parseReservation(b), nil
+}
+
+//
+// parseRegistrations parses the sg_persist --read-keys output:
+//
+//     PR generation=0x4, 2 registered reservation keys follow:
+//       0x7d3f0000
+//       0x7d3f0001
+//
+func parseRegistrations(b []byte) []string {
+	l := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(s, "0x") {
+			continue
+		}
+		if k, err := NormalizeKey(s); err == nil {
+			l = append(l, k)
+		}
+	}
+	return l
+}
+
+//
+// parseReservation parses the sg_persist --read-reservation output:
+//
+//     PR generation=0x4, Reservation follows:
+//       Key=0x7d3f0000
+//       scope: LU_SCOPE,  type: Write Exclusive, registrants only
+//
+func parseReservation(b []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(s, "Key=") {
+			continue
+		}
+		if k, err := NormalizeKey(s[4:]); err == nil {
+			return k
+		}
+	}
+	return ""
+}
+
+func (t PersistentReservationHandle) key() (string, error) {
+	return NormalizeKey(t.Key)
+}
+
+// Register registers the handle key on the device.
+func (t PersistentReservationHandle) Register() error {
+	return t.sgPersistOut("--register-ignore", "--param-sark="+t.Key)
+}
+
+// Unregister removes the handle key registration from the device.
+func (t PersistentReservationHandle) Unregister() error {
+	return t.sgPersistOut("--register", "--param-rk="+t.Key)
+}
+
+// Reserve acquires the reservation of the device with the handle key.
+func (t PersistentReservationHandle) Reserve() error {
+	return t.sgPersistOut("--reserve", "--param-rk="+t.Key, "--prout-type="+prType)
+}
+
+// Release releases the reservation held by the handle key.
+func (t PersistentReservationHandle) Release() error {
+	return t.sgPersistOut("--release", "--param-rk="+t.Key, "--prout-type="+prType)
+}
+
+// Preempt takes over the reservation held by the <holder> key. The
+// holder's commands are aborted unless NoPreemptAbort is set.
+func (t PersistentReservationHandle) Preempt(holder string) error {
+	action := "--preempt-abort"
+	if t.NoPreemptAbort {
+		action = "--preempt"
+	}
+	return t.sgPersistOut(action, "--param-rk="+t.Key, "--param-sark="+holder, "--prout-type="+prType)
+}
+
+// ForeignHolder returns the key of the reservation holder if the device
+// is reserved with a key other than the handle key, or an empty string.
+func (t PersistentReservationHandle) ForeignHolder() (string, error) {
+	key, err := t.key()
+	if err != nil {
+		return "", err
+	}
+	holder, err := t.ReadReservation()
+	if err != nil {
+		return "", err
+	}
+	if holder == key {
+		return "", nil
+	}
+	return holder, nil
+}
+
+// Start registers the handle key and acquires the reservation, preempting
+// the current reservation holder if any.
+func (t PersistentReservationHandle) Start() error {
+	key, err := t.key()
+	if err != nil {
+		return err
+	}
+	registrations, err := t.ReadRegistrations()
+	if err != nil {
+		return err
+	}
+	if !hasKey(registrations, key) {
+		if err := t.Register(); err != nil {
+			return err
+		}
+	}
+	holder, err := t.ReadReservation()
+	if err != nil {
+		return err
+	}
+	switch holder {
+	case key:
+		t.Log.Info().Msgf("%s is already reserved by %s", t.Device, key)
+		return nil
+	case "":
+		return t.Reserve()
+	default:
+		return t.Preempt(holder)
+	}
+}
+
+// Stop releases the reservation and unregisters the handle key.
+func (t PersistentReservationHandle) Stop() error {
+	key, err := t.key()
+	if err != nil {
+		return err
+	}
+	holder, err := t.ReadReservation()
+	if err != nil {
+		return err
+	}
+	if holder == key {
+		if err := t.Release(); err != nil {
+			return err
+		}
+	}
+	registrations, err := t.ReadRegistrations()
+	if err != nil {
+		return err
+	}
+	if hasKey(registrations, key) {
+		return t.Unregister()
+	}
+	return nil
+}
+
+//
+// Status returns up if the device is reserved by the handle key, down if
+// the handle key is neither registered nor holding the reservation, warn
+// otherwise. The issues are returned as a list of messages.
+//
+func (t PersistentReservationHandle) Status() (status.T, []string) {
+	key, err := t.key()
+	if err != nil {
+		return status.Undef, []string{err.Error()}
+	}
+	registrations, err := t.ReadRegistrations()
+	if err != nil {
+		return status.Undef, []string{fmt.Sprintf("%s: %s", t.Device, err)}
+	}
+	holder, err := t.ReadReservation()
+	if err != nil {
+		return status.Undef, []string{fmt.Sprintf("%s: %s", t.Device, err)}
+	}
+	return prStatus(t.Device, key, registrations, holder)
+}
+
+func prStatus(dev, key string, registrations []string, holder string) (status.T, []string) {
+	registered := hasKey(registrations, key)
+	switch {
+	case registered && holder == key:
+		return status.Up, nil
+	case !registered && holder != key:
+		return status.Down, nil
+	case registered:
+		return status.Warn, []string{fmt.Sprintf("%s is registered but not reserved by %s", dev, key)}
+	default:
+		return status.Warn, []string{fmt.Sprintf("%s is reserved but not registered by %s", dev, key)}
+	}
+}
+
+func hasKey(l []string, key string) bool {
+	for _, s := range l {
+		if s == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package scsi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"opensvc.com/opensvc/core/status"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	for _, s := range []string{"0x7D3F", "7d3f", "0x0000000000007d3f", " 0x7d3f "} {
+		k, err := NormalizeKey(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, "0x7d3f", k, s)
+	}
+	_, err := NormalizeKey("0xfoo")
+	assert.Error(t, err)
+}
+
+func TestParseRegistrations(t *testing.T) {
+	b := []byte(`  PR generation=0x4, 2 registered reservation keys follow:
+    0x7d3f0000
+    0x7D3F0001
+`)
+	assert.Equal(t, []string{"0x7d3f0000", "0x7d3f0001"}, parseRegistrations(b))
+	assert.Len(t, parseRegistrations([]byte("  PR generation=0x0, there are NO registered reservation keys\n")), 0)
+}
+
+func TestParseReservation(t *testing.T) {
+	b := []byte(`  PR generation=0x4, Reservation follows:
+    Key=0x7d3f0000
+    scope: LU_SCOPE,  type: Write Exclusive, registrants only
+`)
+	assert.Equal(t, "0x7d3f0000", parseReservation(b))
+	assert.Equal(t, "", parseReservation([]byte("  PR generation=0x4, there is NO reservation held\n")))
+}
+
+func TestPRStatus(t *testing.T) {
+	s, _ := prStatus("/dev/sda", "0x1", []string{"0x1", "0x2"}, "0x1")
+	assert.Equal(t, status.Up, s)
+	s, _ = prStatus("/dev/sda", "0x1", []string{"0x2"}, "0x2")
+	assert.Equal(t, status.Down, s)
+	s, issues := prStatus("/dev/sda", "0x1", []string{"0x1", "0x2"}, "0x2")
+	assert.Equal(t, status.Warn, s)
+	assert.Len(t, issues, 1)
+}
@@ -3,8 +3,8 @@ package sizeconv
 import (
 	"fmt"
 	"math"
+	"math/big"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -38,7 +38,19 @@ const (
 	defaultPrecision = 3
 )
 
-type unitMap map[string]int64
+type (
+	unitMap map[string]int64
+
+	// Units is the unit system of the exact size renderings.
+	Units int
+)
+
+const (
+	// IEC renders sizes in binary units, 1k being 1024 bytes.
+	IEC Units = iota
+	// SI renders sizes in decimal units, 1kB being 1000 bytes.
+	SI
+)
 
 var (
 	dMap = unitMap{"k": KB, "m": MB, "g": GB, "t": TB, "p": PB, "e": EB}
@@ -46,6 +58,7 @@ var (
 	dAbb = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
 	bAbb = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB", "ZiB", "YiB"}
 	cAbb = []string{"", "k", "m", "g", "t", "p", "e", "z", "y"}
+	sAbb = []string{"", "kB", "MB", "GB", "TB", "PB", "EB"}
 	sReg = regexp.MustCompile(`^(\d+(\.\d+)*) ?([kKmMgGtTpPeE])?([iI])?([bB])?$`)
 )
 
@@ -104,10 +117,32 @@ func BSizeCompact(f float64) string {
 }
 
 func ExactBSizeCompact(f float64) string {
+	if f >= 0 && f < math.MaxUint64 && f == math.Trunc(f) {
+		return Compact(uint64(f), IEC)
+	}
 	size, unit := getSizeAndUnit(f, 1024.0, cAbb, true)
 	return fmt.Sprintf("%.0f%s", size, unit)
 }
 
+//
+// Compact returns the exact representation of n in the largest unit
+// dividing it. The IEC renderings use the single letter suffixes, like
+// 2g, and the SI renderings the byte suffixes, like 2GB, so FromSize
+// and FromSizeUint64 parse the rendering back to n.
+//
+func Compact(n uint64, u Units) string {
+	base, abb := uint64(1024), cAbb
+	if u == SI {
+		base, abb = 1000, sAbb
+	}
+	i := 0
+	for n >= base && n%base == 0 && i < len(abb)-1 {
+		n = n / base
+		i++
+	}
+	return fmt.Sprintf("%d%s", n, abb[i])
+}
+
 func ExactDSizeCompact(f float64) string {
 	size, unit := getSizeAndUnit(f, 1000.0, dAbb, true)
 	return fmt.Sprintf("%.0f%s", size, unit)
@@ -117,34 +152,56 @@ func ExactDSizeCompact(f float64) string {
 // size using Metric and IEC standard (eg. "44KiB", "17MiB", "20MB", "7.5EiB").
 // Max possible value is MaxInt64 (< 8EiB)
 func FromSize(sizeStr string) (int64, error) {
-	matches := sReg.FindStringSubmatch(sizeStr)
-	if len(matches) != 6 {
-		return -1, fmt.Errorf("invalid size: '%s'", sizeStr)
-	}
-
-	var convertMap unitMap
-	if strings.ToLower(matches[4]) == "i" {
-		convertMap = bMap
-	} else if strings.ToLower(matches[5]) == "" {
-		// eg. "100m" interpreted implicitely as "100MiB"
-		convertMap = bMap
-	} else {
-		convertMap = dMap
-	}
-	size, err := strconv.ParseFloat(matches[1], 64)
+	size, err := fromSize(sizeStr, nil)
 	if err != nil {
 		return -1, err
 	}
+	if !size.IsInt64() {
+		return -1, fmt.Errorf("max size for int64: '%s'", sizeStr)
+	}
+	return size.Int64(), nil
+}
 
-	unitPrefix := strings.ToLower(matches[3])
+// FromSizeUint64 is like FromSize, with a max possible value of
+// MaxUint64 (< 16EiB).
+func FromSizeUint64(sizeStr string) (uint64, error) {
+	size, err := fromSize(sizeStr, nil)
+	if err != nil {
+		return 0, err
+	}
+	if !size.IsUint64() {
+		return 0, fmt.Errorf("max size for uint64: '%s'", sizeStr)
+	}
+	return size.Uint64(), nil
+}
 
-	if mul, ok := convertMap[unitPrefix]; ok {
-		size *= float64(mul)
+//
+// fromSize returns the number of bytes of the size string, computed
+// without float rounding, and truncated to the byte. The unit map is
+// guessed from the suffix if uMap is nil: "100m" and "100MiB" are binary,
+// "100MB" is decimal.
+//
+func fromSize(sizeStr string, uMap unitMap) (*big.Int, error) {
+	matches := sReg.FindStringSubmatch(sizeStr)
+	if len(matches) != 6 {
+		return nil, fmt.Errorf("invalid size: '%s'", sizeStr)
 	}
-	if size > math.MaxInt64 || int64(size) < 0 {
-		return -1, fmt.Errorf("max size for int64: '%s'", sizeStr)
+	if uMap == nil {
+		if strings.ToLower(matches[4]) == "i" || matches[5] == "" {
+			uMap = bMap
+		} else {
+			uMap = dMap
+		}
+	}
+	size, ok := new(big.Rat).SetString(matches[1])
+	if !ok {
+		return nil, fmt.Errorf("invalid size: '%s'", sizeStr)
 	}
-	return int64(size), nil
+	unitPrefix := strings.ToLower(matches[3])
+	if mul, ok := uMap[unitPrefix]; ok {
+		size.Mul(size, new(big.Rat).SetInt64(mul))
+	}
+	return new(big.Int).Quo(size.Num(), size.Denom()), nil
 }
 
 // FromDSize returns an integer from a human-readable representation of a
@@ -155,22 +212,14 @@ func FromDSize(size string) (int64, error) {
 
 // Parses the human-readable size string into a bytes count.
 func parseSize(sizeStr string, uMap unitMap) (int64, error) {
-	matches := sReg.FindStringSubmatch(sizeStr)
-	if len(matches) != 6 {
-		return -1, fmt.Errorf("invalid size: '%s'", sizeStr)
-	}
-
-	size, err := strconv.ParseFloat(matches[1], 64)
+	size, err := fromSize(sizeStr, uMap)
 	if err != nil {
 		return -1, err
 	}
-
-	unitPrefix := strings.ToLower(matches[3])
-	if mul, ok := uMap[unitPrefix]; ok {
-		size *= float64(mul)
+	if !size.IsInt64() {
+		return -1, fmt.Errorf("max size for int64: '%s'", sizeStr)
 	}
-
-	return int64(size), nil
+	return size.Int64(), nil
 }
 
 // BSizeCompactFromMB returns a compact human readable version of n
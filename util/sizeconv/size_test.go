@@ -1,6 +1,7 @@
 package sizeconv
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,21 @@ var (
 		float64(100 * MiB):  "100m",
 		float64(1 * GiB):    "1g",
 	}
+	compactRenderings = []struct {
+		n     uint64
+		units Units
+		s     string
+	}{
+		{0, IEC, "0"},
+		{1000, IEC, "1000"},
+		{3 * GiB, IEC, "3g"},
+		{15 * EiB, IEC, "15e"},
+		{math.MaxUint64, IEC, "18446744073709551615"},
+		{1000, SI, "1kB"},
+		{1024, SI, "1024"},
+		{8500 * PB, SI, "8500PB"},
+		{18 * EB, SI, "18EB"},
+	}
 )
 
 func TestFromSize(t *testing.T) {
@@ -62,3 +78,31 @@ func TestFromSize(t *testing.T) {
 		}
 	})
 }
+
+func TestFromSizeUint64(t *testing.T) {
+	for s, expected := range map[string]uint64{
+		"8EiB":                 8 * EiB,
+		"15.5EiB":              15*EiB + EiB/2,
+		"9.223372036854775809": 9,
+		"18446744073709551615": math.MaxUint64,
+		"1.5k":                 1536,
+	} {
+		result, err := FromSizeUint64(s)
+		assert.NoErrorf(t, err, s)
+		assert.Equalf(t, expected, result, "FromSizeUint64('%v') -> %v", s, result)
+	}
+	for _, s := range []string{"16EiB", "18446744073709551616", "-1", "1.2.3k"} {
+		_, err := FromSizeUint64(s)
+		assert.Errorf(t, err, s)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	for _, c := range compactRenderings {
+		result := Compact(c.n, c.units)
+		assert.Equalf(t, c.s, result, "Compact(%d, %d)", c.n, c.units)
+		n, err := FromSizeUint64(result)
+		assert.NoError(t, err)
+		assert.Equalf(t, c.n, n, "FromSizeUint64('%s')", result)
+	}
+}
@@ -0,0 +1,151 @@
+package snmptrap
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+type (
+	// ObjectIdentifier is a varbind value encoded as an OBJECT IDENTIFIER,
+	// like "1.3.6.1.2.1.1.3.0".
+	ObjectIdentifier string
+
+	// TimeTicks is a varbind value encoded as TimeTicks, in hundredths of
+	// seconds.
+	TimeTicks uint32
+
+	// Gauge32 is a varbind value encoded as Gauge32.
+	Gauge32 uint32
+
+	// Counter32 is a varbind value encoded as Counter32.
+	Counter32 uint32
+)
+
+// BER tags
+const (
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagNull        = 0x05
+	tagOID         = 0x06
+	tagSequence    = 0x30
+	tagIPAddress   = 0x40
+	tagCounter32   = 0x41
+	tagGauge32     = 0x42
+	tagTimeTicks   = 0x43
+	tagTrapV2      = 0xa7
+)
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+func tlv(tag byte, v []byte) []byte {
+	b := append([]byte{tag}, encodeLength(len(v))...)
+	return append(b, v...)
+}
+
+func concat(l ...[]byte) []byte {
+	b := make([]byte, 0)
+	for _, e := range l {
+		b = append(b, e...)
+	}
+	return b
+}
+
+func sequence(l ...[]byte) []byte {
+	return tlv(tagSequence, concat(l...))
+}
+
+func octetString(b []byte) []byte {
+	return tlv(tagOctetString, b)
+}
+
+// integer encodes i as a minimal two's complement big-endian integer.
+func integer(tag byte, i int64) []byte {
+	b := []byte{byte(i)}
+	for i >>= 8; i != 0 && i != -1; i >>= 8 {
+		b = append([]byte{byte(i)}, b...)
+	}
+	// the sign bit must match the value sign
+	if i == 0 && b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	} else if i == -1 && b[0]&0x80 == 0 {
+		b = append([]byte{0xff}, b...)
+	}
+	return tlv(tag, b)
+}
+
+func encodeOID(s string) ([]byte, error) {
+	elements := strings.Split(strings.TrimPrefix(s, "."), ".")
+	if len(elements) < 2 {
+		return nil, fmt.Errorf("invalid oid %s: at least 2 elements expected", s)
+	}
+	l := make([]uint64, len(elements))
+	for i, e := range elements {
+		n, err := strconv.ParseUint(e, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oid %s: %w", s, err)
+		}
+		l[i] = n
+	}
+	if l[0] > 2 || (l[0] < 2 && l[1] >= 40) {
+		return nil, fmt.Errorf("invalid oid %s: bad first elements", s)
+	}
+	b := encodeSubIdentifier(l[0]*40 + l[1])
+	for _, n := range l[2:] {
+		b = append(b, encodeSubIdentifier(n)...)
+	}
+	return tlv(tagOID, b), nil
+}
+
+// encodeSubIdentifier encodes n in base 128, the high bit of each byte but
+// the last set.
+func encodeSubIdentifier(n uint64) []byte {
+	b := []byte{byte(n & 0x7f)}
+	for n >>= 7; n > 0; n >>= 7 {
+		b = append([]byte{byte(n&0x7f) | 0x80}, b...)
+	}
+	return b
+}
+
+func encodeValue(v interface{}) ([]byte, error) {
+	switch o := v.(type) {
+	case nil:
+		return tlv(tagNull, nil), nil
+	case string:
+		return octetString([]byte(o)), nil
+	case []byte:
+		return octetString(o), nil
+	case int:
+		return integer(tagInteger, int64(o)), nil
+	case int32:
+		return integer(tagInteger, int64(o)), nil
+	case int64:
+		return integer(tagInteger, o), nil
+	case ObjectIdentifier:
+		return encodeOID(string(o))
+	case TimeTicks:
+		return integer(tagTimeTicks, int64(o)), nil
+	case Gauge32:
+		return integer(tagGauge32, int64(o)), nil
+	case Counter32:
+		return integer(tagCounter32, int64(o)), nil
+	case net.IP:
+		ip := o.To4()
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip address %s: not ipv4", o)
+		}
+		return tlv(tagIPAddress, ip), nil
+	default:
+		return nil, fmt.Errorf("unsupported varbind value type %T", v)
+	}
+}
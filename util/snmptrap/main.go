@@ -0,0 +1,300 @@
+// Package snmptrap encodes and sends SNMPv2c and SNMPv3 traps.
+//
+// The SNMPv3 traps support the noAuthNoPriv, authNoPriv (md5 or sha) and
+// authPriv (aes) security levels. The sender is the authoritative engine
+// of the traps it sends, so the receivers must know the sender engine id
+// to accept the authenticated traps.
+//
+// Example:
+//
+//	s, err := snmptrap.New("10.0.0.1:162", snmptrap.WithCommunity("public"))
+//	...
+//	err = s.Send(snmptrap.Trap{
+//		OID:      "1.3.6.1.4.1.8072.2.3.0.1",
+//		Varbinds: []snmptrap.Varbind{{OID: "1.3.6.1.4.1.8072.2.3.2.1", Value: "hello"}},
+//	})
+package snmptrap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// Varbind is a trap variable binding.
+	Varbind struct {
+		OID   string
+		Value interface{}
+	}
+
+	// Trap is a SNMPv2-Trap-PDU content. The sysUpTime.0 and
+	// snmpTrapOID.0 varbinds are prepended by the sender.
+	Trap struct {
+		OID      string
+		Varbinds []Varbind
+	}
+
+	// T is a trap sender.
+	T struct {
+		addr      string
+		community string
+		user      string
+		engineID  []byte
+		authProto string
+		authPass  string
+		privProto string
+		privPass  string
+		authKey   []byte
+		privKey   []byte
+		start     time.Time
+		requestID uint32
+		salt      uint64
+	}
+)
+
+const (
+	// SysUpTimeOID is the OID of the sysUpTime.0 varbind.
+	SysUpTimeOID = "1.3.6.1.2.1.1.3.0"
+
+	// SnmpTrapOID is the OID of the snmpTrapOID.0 varbind.
+	SnmpTrapOID = "1.3.6.1.6.3.1.1.4.1.0"
+
+	// DefaultPort is the standard snmp trap receiver port.
+	DefaultPort = 162
+
+	engineBoots    = 1
+	maxMessageSize = 65507
+
+	flagAuth = 0x01
+	flagPriv = 0x02
+
+	securityModelUSM = 3
+)
+
+// New allocates a sender of traps to addr, a "host:port" udp address. The
+// sender sends SNMPv2c traps, unless WithUser is set.
+func New(addr string, opts ...funcopt.O) (*T, error) {
+	t := &T{
+		addr:      addr,
+		community: "public",
+		start:     time.Now(),
+	}
+	if err := funcopt.Apply(t, opts...); err != nil {
+		return nil, err
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	t.salt = binary.BigEndian.Uint64(b)
+	t.requestID = binary.BigEndian.Uint32(b) & 0x7fffffff
+	if t.user == "" {
+		return t, nil
+	}
+	if len(t.engineID) < 5 || len(t.engineID) > 32 {
+		return nil, fmt.Errorf("invalid engine id length %d: 5 to 32 octets expected", len(t.engineID))
+	}
+	if t.authProto == "" {
+		if t.privProto != "" {
+			return nil, fmt.Errorf("privacy protocol set without authentication protocol")
+		}
+		return t, nil
+	}
+	h, err := authHash(t.authProto)
+	if err != nil {
+		return nil, err
+	}
+	if t.authPass == "" {
+		return nil, fmt.Errorf("empty authentication password")
+	}
+	t.authKey = localizeKey(h, passwordToKey(h, t.authPass), t.engineID)
+	switch t.privProto {
+	case "":
+	case AES:
+		if t.privPass == "" {
+			return nil, fmt.Errorf("empty privacy password")
+		}
+		t.privKey = localizeKey(h, passwordToKey(h, t.privPass), t.engineID)
+	default:
+		return nil, fmt.Errorf("unsupported privacy protocol %q", t.privProto)
+	}
+	return t, nil
+}
+
+// WithCommunity sets the SNMPv2c community. Default is "public".
+func WithCommunity(s string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.community = s
+		return nil
+	})
+}
+
+// WithUser switches the sender to SNMPv3, sending the traps as the user
+// name, from the engineID authoritative engine.
+func WithUser(name string, engineID []byte) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.user = name
+		t.engineID = engineID
+		return nil
+	})
+}
+
+// WithAuth sets the SNMPv3 authentication protocol (md5 or sha) and
+// password.
+func WithAuth(protocol, password string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.authProto = protocol
+		t.authPass = password
+		return nil
+	})
+}
+
+// WithPriv sets the SNMPv3 privacy protocol (aes) and password.
+func WithPriv(protocol, password string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.privProto = protocol
+		t.privPass = password
+		return nil
+	})
+}
+
+// EngineID returns a text format engine id, as described in
+// https://tools.ietf.org/html/rfc3411, built from name. The name is
+// truncated to fit the 32 octets maximum length.
+func EngineID(name string) []byte {
+	b := []byte{0x80, 0x00, 0x00, 0x00, 0x04}
+	if len(name) > 27 {
+		name = name[:27]
+	}
+	return append(b, name...)
+}
+
+// Send encodes and sends the trap.
+func (t *T) Send(trap Trap) error {
+	b, err := t.Encode(trap)
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial("udp", t.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write(b)
+	return err
+}
+
+// Encode returns the trap message.
+func (t *T) Encode(trap Trap) ([]byte, error) {
+	id := atomic.AddUint32(&t.requestID, 1) & 0x7fffffff
+	pdu, err := t.pdu(id, trap)
+	if err != nil {
+		return nil, err
+	}
+	if t.user == "" {
+		return sequence(
+			integer(tagInteger, 1),
+			octetString([]byte(t.community)),
+			pdu,
+		), nil
+	}
+	return t.encodeV3(id, pdu)
+}
+
+func (t *T) uptime() time.Duration {
+	return time.Since(t.start)
+}
+
+func (t *T) pdu(id uint32, trap Trap) ([]byte, error) {
+	l := []Varbind{
+		{OID: SysUpTimeOID, Value: TimeTicks(t.uptime() / (10 * time.Millisecond))},
+		{OID: SnmpTrapOID, Value: ObjectIdentifier(trap.OID)},
+	}
+	l = append(l, trap.Varbinds...)
+	varbinds := make([]byte, 0)
+	for _, v := range l {
+		oid, err := encodeOID(v.OID)
+		if err != nil {
+			return nil, err
+		}
+		value, err := encodeValue(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("varbind %s: %w", v.OID, err)
+		}
+		varbinds = append(varbinds, sequence(oid, value)...)
+	}
+	return tlv(tagTrapV2, concat(
+		integer(tagInteger, int64(id)),
+		integer(tagInteger, 0), // error-status
+		integer(tagInteger, 0), // error-index
+		tlv(tagSequence, varbinds),
+	)), nil
+}
+
+func (t *T) encodeV3(id uint32, pdu []byte) ([]byte, error) {
+	var (
+		flags      byte
+		authParams []byte
+		privParams []byte
+	)
+	engineTime := uint32(t.uptime() / time.Second)
+	data := sequence(
+		octetString(t.engineID), // contextEngineID
+		octetString(nil),        // contextName
+		pdu,
+	)
+	if t.authKey != nil {
+		flags |= flagAuth
+		authParams = make([]byte, authParamsLen)
+	}
+	if t.privKey != nil {
+		flags |= flagPriv
+		privParams = make([]byte, 8)
+		binary.BigEndian.PutUint64(privParams, atomic.AddUint64(&t.salt, 1))
+		encrypted, err := encrypt(t.privKey, engineBoots, engineTime, privParams, data)
+		if err != nil {
+			return nil, err
+		}
+		data = octetString(encrypted)
+	}
+	header := sequence(
+		integer(tagInteger, int64(id)),
+		integer(tagInteger, maxMessageSize),
+		octetString([]byte{flags}),
+		integer(tagInteger, securityModelUSM),
+	)
+	secBefore := concat(
+		octetString(t.engineID),
+		integer(tagInteger, engineBoots),
+		integer(tagInteger, int64(engineTime)),
+		octetString([]byte(t.user)),
+	)
+	secContent := concat(secBefore, octetString(authParams), octetString(privParams))
+	secSeq := tlv(tagSequence, secContent)
+	sec := octetString(secSeq)
+	version := integer(tagInteger, 3)
+	content := concat(version, header, sec, data)
+	msg := tlv(tagSequence, content)
+	if t.authKey == nil {
+		return msg, nil
+	}
+	// offset of the zeroed authentication parameters value in msg
+	offset := len(msg) - len(content) +
+		len(version) + len(header) +
+		len(sec) - len(secSeq) +
+		len(secSeq) - len(secContent) +
+		len(secBefore) + 2
+	h, _ := authHash(t.authProto)
+	copy(msg[offset:], authenticate(h, t.authKey, msg))
+	return msg, nil
+}
@@ -0,0 +1,211 @@
+package snmptrap
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readTLV splits the first tlv of b.
+func readTLV(t *testing.T, b []byte) (byte, []byte, []byte) {
+	require.True(t, len(b) >= 2, "tlv too short")
+	tag, n, b := b[0], int(b[1]), b[2:]
+	if n&0x80 != 0 {
+		size := n & 0x7f
+		n = 0
+		for _, c := range b[:size] {
+			n = n<<8 | int(c)
+		}
+		b = b[size:]
+	}
+	require.True(t, len(b) >= n, "tlv value truncated")
+	return tag, b[:n], b[n:]
+}
+
+// readSequence returns the tlv values of the sequence content b.
+func readSequence(t *testing.T, b []byte) [][]byte {
+	l := make([][]byte, 0)
+	for len(b) > 0 {
+		var v []byte
+		_, v, b = readTLV(t, b)
+		l = append(l, v)
+	}
+	return l
+}
+
+func TestEncodeInteger(t *testing.T) {
+	cases := map[int64]string{
+		0:     "020100",
+		127:   "02017f",
+		128:   "02020080",
+		256:   "02020100",
+		-1:    "0201ff",
+		-128:  "020180",
+		-129:  "0202ff7f",
+		65507: "020300ffe3",
+	}
+	for i, s := range cases {
+		assert.Equal(t, s, hex.EncodeToString(integer(tagInteger, i)), "%d", i)
+	}
+}
+
+func TestEncodeLength(t *testing.T) {
+	assert.Equal(t, []byte{0x7f}, encodeLength(127))
+	assert.Equal(t, []byte{0x81, 0x80}, encodeLength(128))
+	assert.Equal(t, []byte{0x82, 0x01, 0x00}, encodeLength(256))
+}
+
+func TestEncodeOID(t *testing.T) {
+	b, err := encodeOID("1.3.6.1.6.3.1.1.4.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "060a2b060106030101040100", hex.EncodeToString(b))
+
+	b, err = encodeOID(".1.3.6.1.4.1.2636")
+	require.NoError(t, err)
+	assert.Equal(t, "06072b06010401944c", hex.EncodeToString(b))
+
+	_, err = encodeOID("1")
+	assert.Error(t, err)
+	_, err = encodeOID("1.3.x")
+	assert.Error(t, err)
+}
+
+func TestEncodeValue(t *testing.T) {
+	b, err := encodeValue(net.ParseIP("10.0.0.1"))
+	require.NoError(t, err)
+	assert.Equal(t, "40040a000001", hex.EncodeToString(b))
+
+	b, err = encodeValue(TimeTicks(4294967295))
+	require.NoError(t, err)
+	assert.Equal(t, "430500ffffffff", hex.EncodeToString(b))
+
+	_, err = encodeValue(1.5)
+	assert.Error(t, err)
+}
+
+// TestLocalizeKey verifies the key localization with the
+// https://tools.ietf.org/html/rfc3414#appendix-A.3 vectors.
+func TestLocalizeKey(t *testing.T) {
+	engineID, _ := hex.DecodeString("000000000000000000000002")
+	key := localizeKey(md5.New, passwordToKey(md5.New, "maplesyrup"), engineID)
+	assert.Equal(t, "526f5eed9fcce26f8964c2930787d82b", hex.EncodeToString(key))
+	key = localizeKey(sha1.New, passwordToKey(sha1.New, "maplesyrup"), engineID)
+	assert.Equal(t, "6695febc9288e36282235fc7151f128497b38f3f", hex.EncodeToString(key))
+}
+
+func TestEncodeV2c(t *testing.T) {
+	s, err := New("127.0.0.1:162", WithCommunity("secret"))
+	require.NoError(t, err)
+	b, err := s.Encode(Trap{
+		OID:      "1.3.6.1.4.1.99.0.1",
+		Varbinds: []Varbind{{OID: "1.3.6.1.4.1.99.1.1.0", Value: "svc1"}},
+	})
+	require.NoError(t, err)
+
+	tag, v, rest := readTLV(t, b)
+	assert.Equal(t, byte(tagSequence), tag)
+	assert.Empty(t, rest)
+	msg := readSequence(t, v)
+	require.Len(t, msg, 3)
+	assert.Equal(t, []byte{1}, msg[0], "version 2c")
+	assert.Equal(t, []byte("secret"), msg[1])
+
+	pdu := readSequence(t, msg[2])
+	require.Len(t, pdu, 4)
+	varbinds := readSequence(t, pdu[3])
+	require.Len(t, varbinds, 3)
+	trapOID, _ := encodeOID("1.3.6.1.4.1.99.0.1")
+	assert.Equal(t, concat(mustOID(t, SnmpTrapOID), trapOID), varbinds[1])
+	assert.Equal(t, concat(mustOID(t, "1.3.6.1.4.1.99.1.1.0"), octetString([]byte("svc1"))), varbinds[2])
+}
+
+func mustOID(t *testing.T, s string) []byte {
+	b, err := encodeOID(s)
+	require.NoError(t, err)
+	return b
+}
+
+func TestEncodeV3AuthPriv(t *testing.T) {
+	engineID := EngineID("node1")
+	s, err := New("127.0.0.1:162",
+		WithUser("osvc", engineID),
+		WithAuth(SHA, "authpassword"),
+		WithPriv(AES, "privpassword"),
+	)
+	require.NoError(t, err)
+	trap := Trap{OID: "1.3.6.1.4.1.99.0.1"}
+	b, err := s.Encode(trap)
+	require.NoError(t, err)
+
+	_, v, _ := readTLV(t, b)
+	msg := readSequence(t, v)
+	require.Len(t, msg, 4)
+	assert.Equal(t, []byte{3}, msg[0], "version 3")
+	header := readSequence(t, msg[1])
+	assert.Equal(t, []byte{flagAuth | flagPriv}, header[2])
+
+	_, secSeq, _ := readTLV(t, msg[2])
+	sec := readSequence(t, secSeq)
+	require.Len(t, sec, 6)
+	assert.Equal(t, engineID, sec[0])
+	assert.Equal(t, []byte("osvc"), sec[3])
+	authParams, privParams := sec[4], sec[5]
+	require.Len(t, authParams, authParamsLen)
+	require.Len(t, privParams, 8)
+
+	// verify the hmac, computed with the zeroed authentication parameters
+	zeroed := bytes.Replace(b, authParams, make([]byte, authParamsLen), 1)
+	assert.Equal(t, authParams, authenticate(sha1.New, s.authKey, zeroed))
+
+	// decrypt the scoped pdu
+	boots := binary.BigEndian.Uint32(append(make([]byte, 4-len(sec[1])), sec[1]...))
+	engineTime := binary.BigEndian.Uint32(append(make([]byte, 4-len(sec[2])), sec[2]...))
+	iv := make([]byte, 16)
+	binary.BigEndian.PutUint32(iv[0:], boots)
+	binary.BigEndian.PutUint32(iv[4:], engineTime)
+	copy(iv[8:], privParams)
+	block, err := aes.NewCipher(s.privKey[:16])
+	require.NoError(t, err)
+	encrypted := msg[3]
+	plain := make([]byte, len(encrypted))
+	cipher.NewCFBDecrypter(block, iv).XORKeyStream(plain, encrypted)
+	_, scoped, _ := readTLV(t, plain)
+	l := readSequence(t, scoped)
+	require.Len(t, l, 3)
+	assert.Equal(t, engineID, l[0])
+	assert.Empty(t, l[1])
+}
+
+func TestNewErrors(t *testing.T) {
+	_, err := New("127.0.0.1:162", WithUser("osvc", []byte("x")))
+	assert.Error(t, err, "short engine id")
+	_, err = New("127.0.0.1:162", WithUser("osvc", EngineID("n1")), WithAuth("sha256", "password"))
+	assert.Error(t, err, "unsupported auth protocol")
+	_, err = New("127.0.0.1:162", WithUser("osvc", EngineID("n1")), WithPriv(AES, "password"))
+	assert.Error(t, err, "priv without auth")
+}
+
+func TestSend(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	s, err := New(conn.LocalAddr().String())
+	require.NoError(t, err)
+	require.NoError(t, s.Send(Trap{OID: "1.3.6.1.4.1.99.0.1"}))
+	buf := make([]byte, 1500)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	tag, _, _ := readTLV(t, buf[:n])
+	assert.Equal(t, byte(tagSequence), tag)
+}
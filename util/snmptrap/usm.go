@@ -0,0 +1,90 @@
+package snmptrap
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// The SNMPv3 User-based Security Model, as described in
+// https://tools.ietf.org/html/rfc3414 and, for the AES privacy protocol,
+// https://tools.ietf.org/html/rfc3826.
+
+const (
+	// MD5 is the HMAC-MD5-96 authentication protocol.
+	MD5 = "md5"
+
+	// SHA is the HMAC-SHA-96 authentication protocol.
+	SHA = "sha"
+
+	// AES is the CFB128-AES-128 privacy protocol.
+	AES = "aes"
+
+	authParamsLen = 12
+)
+
+func authHash(protocol string) (func() hash.Hash, error) {
+	switch protocol {
+	case MD5:
+		return md5.New, nil
+	case SHA:
+		return sha1.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication protocol %q", protocol)
+	}
+}
+
+// passwordToKey returns the user key derived from the password, hashing
+// one megabyte of the repeated password.
+func passwordToKey(h func() hash.Hash, password string) []byte {
+	hh := h()
+	buf := make([]byte, 64)
+	idx := 0
+	for count := 0; count < 1048576; count += len(buf) {
+		for i := range buf {
+			buf[i] = password[idx%len(password)]
+			idx++
+		}
+		hh.Write(buf)
+	}
+	return hh.Sum(nil)
+}
+
+// localizeKey returns the user key localized to the authoritative engine.
+func localizeKey(h func() hash.Hash, key, engineID []byte) []byte {
+	hh := h()
+	hh.Write(key)
+	hh.Write(engineID)
+	hh.Write(key)
+	return hh.Sum(nil)
+}
+
+// authenticate returns the truncated hmac of the message, computed with
+// the authentication parameters zeroed.
+func authenticate(h func() hash.Hash, key, msg []byte) []byte {
+	mac := hmac.New(h, key)
+	mac.Write(msg)
+	return mac.Sum(nil)[:authParamsLen]
+}
+
+// encrypt returns the scoped pdu encrypted with the localized privacy key.
+// The iv is made of the engine boots and time, and of the salt sent as the
+// privacy parameters.
+func encrypt(key []byte, boots, engineTime uint32, salt, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, 16)
+	binary.BigEndian.PutUint32(iv[0:], boots)
+	binary.BigEndian.PutUint32(iv[4:], engineTime)
+	copy(iv[8:], salt)
+	out := make([]byte, len(data))
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
@@ -0,0 +1,5 @@
+// Package syspower provides the operating system power management
+// actions used by the node shutdown and reboot actions, and by the object
+// monitor actions.
+
+package syspower
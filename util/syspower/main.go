@@ -0,0 +1,15 @@
+package syspower
+
+import (
+	"os/exec"
+)
+
+// Reboot asks the operating system to reboot gracefully.
+func Reboot() error {
+	return exec.Command("shutdown", "-r", "now").Run()
+}
+
+// Poweroff asks the operating system to halt and power off gracefully.
+func Poweroff() error {
+	return exec.Command("shutdown", "-h", "now").Run()
+}
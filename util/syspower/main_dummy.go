@@ -0,0 +1,21 @@
+// +build !linux
+
+package syspower
+
+import "errors"
+
+// ErrNotSupported is returned by the actions not implemented on the
+// current operating system.
+var ErrNotSupported = errors.New("not supported on this operating system")
+
+// ForceReboot reboots the node immediately.
+// It is not supported on non linux systems.
+func ForceReboot() error {
+	return ErrNotSupported
+}
+
+// Crash crashes the node kernel.
+// It is not supported on non linux systems.
+func Crash() error {
+	return ErrNotSupported
+}
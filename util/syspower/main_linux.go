@@ -0,0 +1,29 @@
+// +build linux
+
+package syspower
+
+import (
+	"io/ioutil"
+	"syscall"
+)
+
+var (
+	procSysrqTrigger = "/proc/sysrq-trigger"
+)
+
+func sysrq(c string) error {
+	return ioutil.WriteFile(procSysrqTrigger, []byte(c), 0200)
+}
+
+// ForceReboot reboots the node immediately, without stopping the
+// processes nor unmounting the filesystems.
+func ForceReboot() error {
+	syscall.Sync()
+	return sysrq("b")
+}
+
+// Crash crashes the node kernel, so the peer nodes can take over the
+// object instances as soon as possible.
+func Crash() error {
+	return sysrq("c")
+}
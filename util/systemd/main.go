@@ -2,7 +2,11 @@
 
 package systemd
 
-import "io/ioutil"
+import (
+	"io/ioutil"
+	"os/exec"
+	"strings"
+)
 
 var (
 	procOneComm = "/proc/1/comm"
@@ -19,3 +23,16 @@ func HasSystemd() bool {
 	}
 	return string(b) == "systemd\n"
 }
+
+// IsUnitLoaded return true if the unit file of the systemd unit is
+// installed and loaded by systemd.
+func IsUnitLoaded(name string) bool {
+	if !HasSystemd() {
+		return false
+	}
+	b, err := exec.Command("systemctl", "show", "--property=LoadState", "--value", name).Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(b)) == "loaded"
+}
@@ -7,3 +7,10 @@ package systemd
 func HasSystemd() bool {
 	return false
 }
+
+// IsUnitLoaded return true if the unit file of the systemd unit is
+// installed and loaded by systemd.
+// It always return false on non linux systems
+func IsUnitLoaded(name string) bool {
+	return false
+}
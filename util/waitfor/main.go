@@ -0,0 +1,157 @@
+// Package waitfor polls a condition until it is met, the timeout is
+// reached or the context is done.
+//
+// The drivers use it to wait for devices to appear, for containers to be
+// ready or for an address to be released, so all those waits share the
+// same timeout behaviour and error.
+//
+// Example:
+//
+//	err := waitfor.WaitFor(ctx, 100*time.Millisecond, 5*time.Second, func() (bool, error) {
+//		return file.Exists(p), nil
+//	}, waitfor.WithBackoff(2, time.Second))
+package waitfor
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// Condition returns true when the wait is over. A non-nil error
+	// aborts the wait.
+	Condition func() (bool, error)
+
+	// Hook is called after each unmet condition evaluation, with the
+	// number of evaluations done and the delay before the next one.
+	Hook func(attempt int, delay time.Duration)
+
+	// T is a configured poller.
+	T struct {
+		interval    time.Duration
+		timeout     time.Duration
+		factor      float64
+		maxInterval time.Duration
+		jitter      float64
+		onRetry     Hook
+	}
+)
+
+var (
+	// ErrTimeout is returned when the condition is not met before the
+	// timeout.
+	ErrTimeout = errors.New("wait timeout")
+)
+
+// New allocates and returns a poller.
+func New(interval, timeout time.Duration, opts ...funcopt.O) *T {
+	t := &T{
+		interval: interval,
+		timeout:  timeout,
+		factor:   1,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// WithBackoff multiplies the polling interval by factor after each unmet
+// condition evaluation, without exceeding max. A zero max means no limit.
+func WithBackoff(factor float64, max time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.factor = factor
+		t.maxInterval = max
+		return nil
+	})
+}
+
+// WithJitter randomizes each delay by up to ratio of its value, so
+// concurrent waiters do not poll in sync. Ex: 0.1 for +/- 10%.
+func WithJitter(ratio float64) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.jitter = ratio
+		return nil
+	})
+}
+
+// WithOnRetry sets a function called after each unmet condition
+// evaluation, typically to log the wait progress.
+func WithOnRetry(fn Hook) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.onRetry = fn
+		return nil
+	})
+}
+
+// WaitFor polls the condition every interval until it is met. It returns
+// ErrTimeout if the condition is still unmet after timeout, the context
+// error if the context is done first, or the condition error. A zero
+// timeout means wait until the context is done.
+func WaitFor(ctx context.Context, interval, timeout time.Duration, fn Condition, opts ...funcopt.O) error {
+	return New(interval, timeout, opts...).Do(ctx, fn)
+}
+
+// Do polls the condition until it is met.
+func (t T) Do(ctx context.Context, fn Condition) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	begin := time.Now()
+	interval := t.interval
+	for attempt := 1; ; attempt++ {
+		if ok, err := fn(); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		delay := t.delay(interval)
+		if t.timeout > 0 {
+			remaining := t.timeout - time.Since(begin)
+			if remaining <= 0 {
+				return errors.Wrapf(ErrTimeout, "condition unmet after %s and %d attempts", t.timeout, attempt)
+			}
+			if delay > remaining {
+				delay = remaining
+			}
+		}
+		if t.onRetry != nil {
+			t.onRetry(attempt, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("wait aborted after %d attempts: %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+		interval = t.next(interval)
+	}
+}
+
+// next returns the interval to use after interval, applying the backoff.
+func (t T) next(interval time.Duration) time.Duration {
+	if t.factor <= 1 {
+		return interval
+	}
+	next := time.Duration(float64(interval) * t.factor)
+	if t.maxInterval > 0 && next > t.maxInterval {
+		return t.maxInterval
+	}
+	return next
+}
+
+// delay returns the interval randomized by the jitter ratio.
+func (t T) delay(interval time.Duration) time.Duration {
+	if t.jitter <= 0 {
+		return interval
+	}
+	d := float64(interval) * t.jitter * (2*rand.Float64() - 1)
+	return interval + time.Duration(d)
+}
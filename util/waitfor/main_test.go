@@ -0,0 +1,56 @@
+package waitfor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitFor(t *testing.T) {
+	n := 0
+	err := WaitFor(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+		n++
+		return n == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	attempts := 0
+	err := WaitFor(context.Background(), 10*time.Millisecond, 50*time.Millisecond, func() (bool, error) {
+		return false, nil
+	}, WithOnRetry(func(attempt int, _ time.Duration) {
+		attempts = attempt
+	}), WithJitter(0.1))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.Greater(t, attempts, 1)
+}
+
+func TestWaitForError(t *testing.T) {
+	errFoo := errors.New("foo")
+	err := WaitFor(context.Background(), time.Millisecond, time.Second, func() (bool, error) {
+		return false, errFoo
+	})
+	assert.Equal(t, errFoo, err)
+}
+
+func TestWaitForContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := WaitFor(ctx, 5*time.Millisecond, 0, func() (bool, error) {
+		return false, nil
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestBackoff(t *testing.T) {
+	p := New(10*time.Millisecond, 0, WithBackoff(2, 30*time.Millisecond))
+	assert.Equal(t, 20*time.Millisecond, p.next(10*time.Millisecond))
+	assert.Equal(t, 30*time.Millisecond, p.next(20*time.Millisecond))
+	p = New(10*time.Millisecond, 0)
+	assert.Equal(t, 10*time.Millisecond, p.next(10*time.Millisecond))
+}
@@ -0,0 +1,202 @@
+// Package xmlrpc is a minimal XML-RPC client, as described in
+// http://xmlrpc.com/spec.md, used to report to the collector.
+//
+// The call parameters can be strings, integers, booleans, floats, slices
+// and string keyed maps of those. The call responses are only decoded to
+// detect the faults.
+package xmlrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	// T is a XML-RPC client of the server at URL.
+	T struct {
+		URL    string
+		Client *http.Client
+	}
+
+	// Fault is the error returned when the server responds with a fault.
+	Fault struct {
+		Code   int
+		String string
+	}
+
+	response struct {
+		Fault *struct {
+			Members []struct {
+				Name  string   `xml:"name"`
+				Value rawValue `xml:"value"`
+			} `xml:"value>struct>member"`
+		} `xml:"fault"`
+	}
+
+	rawValue struct {
+		Int    string `xml:"int"`
+		I4     string `xml:"i4"`
+		String string `xml:"string"`
+		Text   string `xml:",chardata"`
+	}
+)
+
+// New returns a client of the XML-RPC server at url.
+func New(url string) *T {
+	return &T{
+		URL:    url,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (t Fault) Error() string {
+	return fmt.Sprintf("xmlrpc fault %d: %s", t.Code, t.String)
+}
+
+// Call posts the method call to the server, and returns a Fault error if
+// the server responds with a fault.
+func (t T) Call(ctx context.Context, method string, params ...interface{}) error {
+	b, err := Marshal(method, params...)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, t.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/xml")
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("xmlrpc %s: unexpected response status %s", method, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return parseResponse(body)
+}
+
+func parseResponse(b []byte) error {
+	var r response
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return fmt.Errorf("xmlrpc response: %w", err)
+	}
+	if r.Fault == nil {
+		return nil
+	}
+	fault := Fault{}
+	for _, m := range r.Fault.Members {
+		switch m.Name {
+		case "faultCode":
+			s := m.Value.Int
+			if s == "" {
+				s = m.Value.I4
+			}
+			fault.Code, _ = strconv.Atoi(s)
+		case "faultString":
+			fault.String = m.Value.String
+			if fault.String == "" {
+				fault.String = m.Value.Text
+			}
+		}
+	}
+	return fault
+}
+
+// Marshal returns the XML-RPC method call document.
+func Marshal(method string, params ...interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString("<methodCall><methodName>")
+	if err := xml.EscapeText(&b, []byte(method)); err != nil {
+		return nil, err
+	}
+	b.WriteString("</methodName><params>")
+	for _, p := range params {
+		b.WriteString("<param>")
+		if err := writeValue(&b, reflect.ValueOf(p)); err != nil {
+			return nil, err
+		}
+		b.WriteString("</param>")
+	}
+	b.WriteString("</params></methodCall>")
+	return b.Bytes(), nil
+}
+
+func writeValue(b *bytes.Buffer, v reflect.Value) error {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			b.WriteString("<value><nil/></value>")
+			return nil
+		}
+		return writeValue(b, v.Elem())
+	}
+	b.WriteString("<value>")
+	switch v.Kind() {
+	case reflect.String:
+		b.WriteString("<string>")
+		if err := xml.EscapeText(b, []byte(v.String())); err != nil {
+			return err
+		}
+		b.WriteString("</string>")
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteString("<boolean>1</boolean>")
+		} else {
+			b.WriteString("<boolean>0</boolean>")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(b, "<int>%d</int>", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fmt.Fprintf(b, "<int>%d</int>", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprintf(b, "<double>%s</double>", strconv.FormatFloat(v.Float(), 'f', -1, 64))
+	case reflect.Slice, reflect.Array:
+		b.WriteString("<array><data>")
+		for i := 0; i < v.Len(); i++ {
+			if err := writeValue(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		b.WriteString("</data></array>")
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("xmlrpc: unsupported %s map key type", v.Type().Key())
+		}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		b.WriteString("<struct>")
+		for _, k := range keys {
+			b.WriteString("<member><name>")
+			if err := xml.EscapeText(b, []byte(k)); err != nil {
+				return err
+			}
+			b.WriteString("</name>")
+			if err := writeValue(b, v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))); err != nil {
+				return err
+			}
+			b.WriteString("</member>")
+		}
+		b.WriteString("</struct>")
+	default:
+		return fmt.Errorf("xmlrpc: unsupported %s value type", v.Type())
+	}
+	b.WriteString("</value>")
+	return nil
+}
@@ -0,0 +1,54 @@
+package xmlrpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	b, err := Marshal("m", "a<b", 1, true, []string{"x", "y"}, map[string]interface{}{"k": 1.5})
+	require.NoError(t, err)
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<methodCall><methodName>m</methodName><params>` +
+		`<param><value><string>a&lt;b</string></value></param>` +
+		`<param><value><int>1</int></value></param>` +
+		`<param><value><boolean>1</boolean></value></param>` +
+		`<param><value><array><data><value><string>x</string></value><value><string>y</string></value></data></array></value></param>` +
+		`<param><value><struct><member><name>k</name><value><double>1.5</double></value></member></struct></value></param>` +
+		`</params></methodCall>`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	_, err := Marshal("m", make(chan int))
+	assert.Error(t, err)
+}
+
+func TestCall(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><string>ok</string></value></param></params></methodResponse>`))
+	}))
+	defer srv.Close()
+	require.NoError(t, New(srv.URL).Call(context.Background(), "m", "a"))
+	assert.Contains(t, string(body), "<methodName>m</methodName>")
+}
+
+func TestCallFault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><fault><value><struct>` +
+			`<member><name>faultCode</name><value><int>4</int></value></member>` +
+			`<member><name>faultString</name><value><string>Too many parameters.</string></value></member>` +
+			`</struct></value></fault></methodResponse>`))
+	}))
+	defer srv.Close()
+	err := New(srv.URL).Call(context.Background(), "m")
+	assert.Equal(t, Fault{Code: 4, String: "Too many parameters."}, err)
+}
@@ -47,6 +47,10 @@ func newID() string {
 // for init() test
 func initID() {
 	ID = getID()
+
+	// Export the ID so the spawned commands, like the resource triggers
+	// or the om subcommands, log their entries with the same session id.
+	_ = os.Setenv("OSVC_SESSION_ID", ID)
 }
 
 func init() {
@@ -0,0 +1,238 @@
+package zfs
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// Dataset is a zfs filesystem, volume or snapshot, like "pool/fs" or
+	// "pool/fs@snap".
+	Dataset struct {
+		base
+		Name string
+	}
+
+	// DatasetInfo is a dataset listed by "zfs list".
+	DatasetInfo struct {
+		Name       string
+		Type       string
+		Used       int64
+		Avail      int64
+		Refer      int64
+		Mountpoint string
+		Created    time.Time
+	}
+)
+
+// listProps are the properties of the "zfs list -o" listings, in the
+// DatasetInfo parsing order.
+const listProps = "name,type,used,avail,refer,mountpoint,creation"
+
+// NewDataset allocates and returns the named dataset.
+func NewDataset(name string, opts ...funcopt.O) *Dataset {
+	t := &Dataset{
+		base: newBase(),
+		Name: name,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// Pool returns the name of the pool hosting the dataset.
+func (t Dataset) Pool() string {
+	return strings.SplitN(strings.SplitN(t.Name, "@", 2)[0], "/", 2)[0]
+}
+
+//
+// Create creates the filesystem dataset and its missing parents, with
+// the properties set at creation, like mountpoint or compression.
+//
+func (t *Dataset) Create(props map[string]string) error {
+	args := append([]string{"create", "-p"}, propArgs(props)...)
+	return t.run("zfs", append(args, t.Name)...)
+}
+
+// CreateVolume creates the size bytes volume dataset and its missing
+// parents, with the properties set at creation.
+func (t *Dataset) CreateVolume(size int64, props map[string]string) error {
+	args := append([]string{"create", "-p", "-V", strconv.FormatInt(size, 10)}, propArgs(props)...)
+	return t.run("zfs", append(args, t.Name)...)
+}
+
+// Destroy destroys the dataset, and its descendants if recursive is set.
+func (t *Dataset) Destroy(recursive bool) error {
+	args := []string{"destroy"}
+	if recursive {
+		args = append(args, "-r")
+	}
+	return t.run("zfs", append(args, t.Name)...)
+}
+
+// Exists returns true if the dataset exists.
+func (t *Dataset) Exists() (bool, error) {
+	_, err := t.output("zfs", "list", "-H", "-o", "name", t.Name)
+	return exists(err)
+}
+
+// Info returns the listing of the dataset.
+func (t *Dataset) Info() (DatasetInfo, error) {
+	b, err := t.output("zfs", "list", "-H", "-p", "-o", listProps, t.Name)
+	if err != nil {
+		return DatasetInfo{}, err
+	}
+	l, err := parseDatasets(b)
+	if err != nil {
+		return DatasetInfo{}, err
+	}
+	if len(l) != 1 {
+		return DatasetInfo{}, fmt.Errorf("zfs list %s: unexpected %d datasets", t.Name, len(l))
+	}
+	return l[0], nil
+}
+
+//
+// Children returns the datasets of the types, like "filesystem,volume",
+// descending from the dataset down to depth levels, including the
+// dataset itself. A negative depth lists all the descendants. The
+// datasets are sorted by creation date.
+//
+func (t *Dataset) Children(types string, depth int) ([]DatasetInfo, error) {
+	args := []string{"list", "-H", "-p", "-o", listProps, "-s", "creation", "-t", types}
+	if depth < 0 {
+		args = append(args, "-r")
+	} else {
+		args = append(args, "-d", strconv.Itoa(depth))
+	}
+	b, err := t.output("zfs", append(args, t.Name)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseDatasets(b)
+}
+
+// Snapshots returns the snapshots of the dataset, oldest first.
+func (t *Dataset) Snapshots() ([]DatasetInfo, error) {
+	return t.Children("snapshot", 1)
+}
+
+// GetProperty returns the parsable value of the dataset property.
+func (t *Dataset) GetProperty(name string) (string, error) {
+	b, err := t.output("zfs", "get", "-H", "-p", "-o", "value", name, t.Name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// SetProperty sets the dataset property value.
+func (t *Dataset) SetProperty(name, value string) error {
+	return t.run("zfs", "set", name+"="+value, t.Name)
+}
+
+// Snapshot creates the <dataset>@<name> snapshot.
+func (t *Dataset) Snapshot(name string) error {
+	return t.run("zfs", "snapshot", t.Name+"@"+name)
+}
+
+// Rollback rolls the dataset back to the <dataset>@<name> snapshot,
+// destroying the more recent snapshots.
+func (t *Dataset) Rollback(name string) error {
+	return t.run("zfs", "rollback", "-r", t.Name+"@"+name)
+}
+
+//
+// Send writes the replication stream of the <dataset>@<snap> snapshot
+// to w. The stream is incremental from the <dataset>@<from> snapshot if
+// from is set. The binary stream is not line oriented, so it is not
+// executed by the util/command package.
+//
+func (t *Dataset) Send(w io.Writer, snap, from string) error {
+	args := []string{"send"}
+	if from != "" {
+		args = append(args, "-i", t.Name+"@"+from)
+	}
+	args = append(args, t.Name+"@"+snap)
+	cmd := exec.Command("zfs", args...)
+	cmd.Stdout = w
+	return t.stream(cmd)
+}
+
+//
+// Receive creates or updates the dataset from the replication stream
+// read from r. The dataset is rolled back to its most recent snapshot
+// before the receive if force is set.
+//
+func (t *Dataset) Receive(r io.Reader, force bool) error {
+	args := []string{"receive"}
+	if force {
+		args = append(args, "-F")
+	}
+	cmd := exec.Command("zfs", append(args, t.Name)...)
+	cmd.Stdin = r
+	return t.stream(cmd)
+}
+
+func (t *Dataset) stream(cmd *exec.Cmd) error {
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	t.log.Info().Str("cmd", cmd.String()).Msg("running")
+	if err := cmd.Run(); err != nil {
+		s := strings.TrimSpace(stderr.String())
+		t.log.WithLevel(zerolog.ErrorLevel).Err(err).Str("cmd", cmd.String()).Msg(s)
+		return wrapNotExist(fmt.Errorf("%s: %w: %s", cmd, err, s), []byte(s))
+	}
+	return nil
+}
+
+// parseDatasets parses the "zfs list -H -p -o <listProps>" output.
+func parseDatasets(b []byte) ([]DatasetInfo, error) {
+	l := make([]DatasetInfo, 0)
+	for _, line := range strings.Split(string(b), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return l, fmt.Errorf("zfs list: unexpected line %q", line)
+		}
+		info := DatasetInfo{
+			Name:       fields[0],
+			Type:       fields[1],
+			Mountpoint: fields[5],
+		}
+		var err error
+		if info.Used, err = parseSize(fields[2]); err != nil {
+			return l, fmt.Errorf("zfs list %s: used: %w", info.Name, err)
+		}
+		if info.Avail, err = parseSize(fields[3]); err != nil {
+			return l, fmt.Errorf("zfs list %s: avail: %w", info.Name, err)
+		}
+		if info.Refer, err = parseSize(fields[4]); err != nil {
+			return l, fmt.Errorf("zfs list %s: refer: %w", info.Name, err)
+		}
+		i, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return l, fmt.Errorf("zfs list %s: creation: %w", info.Name, err)
+		}
+		info.Created = time.Unix(i, 0)
+		l = append(l, info)
+	}
+	return l, nil
+}
+
+// parseSize parses a -p size value. The "-" value of the properties not
+// applicable to the dataset type is parsed as zero.
+func parseSize(s string) (int64, error) {
+	if s == "-" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,122 @@
+//
+// Package zfs wraps the zfs and zpool commands managing the datasets and
+// the pools, for the drivers and the filesystems handling zfs objects.
+//
+// The listings use the -H and -p flags, so the parsed values are tab
+// separated and the sizes and dates are exact numbers.
+//
+package zfs
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// base holds the logger shared by the Dataset and Pool types.
+	base struct {
+		log *zerolog.Logger
+	}
+)
+
+var (
+	// ErrNotExist is wrapped by the errors of the commands targeting a
+	// dataset or a pool that does not exist.
+	ErrNotExist = errors.New("does not exist")
+)
+
+// WithLogger sets the logger of the commands executed on the dataset or
+// the pool. The default is the global logger.
+func WithLogger(l *zerolog.Logger) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		switch t := i.(type) {
+		case *Dataset:
+			t.log = l
+		case *Pool:
+			t.log = l
+		}
+		return nil
+	})
+}
+
+func newBase() base {
+	return base{log: &log.Logger}
+}
+
+// run executes a command changing the zfs objects, logging its outputs
+// at info and error levels.
+func (t base) run(name string, args ...string) error {
+	cmd := command.New(
+		command.WithName(name),
+		command.WithVarArgs(args...),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+		command.WithBufferedStderr(),
+	)
+	if err := cmd.Run(); err != nil {
+		return wrapNotExist(err, cmd.Stderr())
+	}
+	return nil
+}
+
+// output executes a command reading the zfs objects, and returns its
+// stdout.
+func (t base) output(name string, args ...string) ([]byte, error) {
+	cmd := command.New(
+		command.WithName(name),
+		command.WithVarArgs(args...),
+		command.WithLogger(t.log),
+		command.WithBufferedStdout(),
+		command.WithBufferedStderr(),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, wrapNotExist(err, cmd.Stderr())
+	}
+	return cmd.Stdout(), nil
+}
+
+// wrapNotExist wraps ErrNotExist in the command error if the command
+// stderr reports a missing dataset or pool.
+func wrapNotExist(err error, stderr []byte) error {
+	s := string(stderr)
+	if strings.Contains(s, "does not exist") || strings.Contains(s, "no such pool") {
+		return errors.Wrap(ErrNotExist, strings.TrimSpace(s))
+	}
+	return err
+}
+
+// propArgs returns the "-o <name>=<value>" args of the properties,
+// sorted by name.
+func propArgs(props map[string]string) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	l := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		l = append(l, "-o", name+"="+props[name])
+	}
+	return l
+}
+
+// exists returns false if the listing of the zfs object failed because it
+// does not exist.
+func exists(err error) (bool, error) {
+	switch {
+	case errors.Is(err, ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
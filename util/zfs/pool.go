@@ -0,0 +1,131 @@
+package zfs
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	// Pool is a zfs storage pool.
+	Pool struct {
+		base
+		Name string
+	}
+
+	// PoolInfo is a pool listed by "zpool list".
+	PoolInfo struct {
+		Name   string
+		Size   int64
+		Alloc  int64
+		Free   int64
+		Health string
+	}
+)
+
+// NewPool allocates and returns the named pool.
+func NewPool(name string, opts ...funcopt.O) *Pool {
+	t := &Pool{
+		base: newBase(),
+		Name: name,
+	}
+	_ = funcopt.Apply(t, opts...)
+	return t
+}
+
+// Dataset returns the root dataset of the pool.
+func (t *Pool) Dataset() *Dataset {
+	return &Dataset{base: t.base, Name: t.Name}
+}
+
+//
+// Create creates the pool on the vdevs, like "mirror /dev/sdb /dev/sdc",
+// with the pool properties set at creation, like ashift.
+//
+func (t *Pool) Create(vdevs []string, props map[string]string) error {
+	args := append([]string{"create", "-f"}, propArgs(props)...)
+	args = append(args, t.Name)
+	return t.run("zpool", append(args, vdevs...)...)
+}
+
+// Destroy destroys the pool and its datasets.
+func (t *Pool) Destroy(force bool) error {
+	args := []string{"destroy"}
+	if force {
+		args = append(args, "-f")
+	}
+	return t.run("zpool", append(args, t.Name)...)
+}
+
+// Import imports the pool, searching its devices in the dirs if set.
+func (t *Pool) Import(dirs ...string) error {
+	args := []string{"import"}
+	for _, dir := range dirs {
+		args = append(args, "-d", dir)
+	}
+	return t.run("zpool", append(args, t.Name)...)
+}
+
+// Export exports the pool, unmounting its datasets.
+func (t *Pool) Export(force bool) error {
+	args := []string{"export"}
+	if force {
+		args = append(args, "-f")
+	}
+	return t.run("zpool", append(args, t.Name)...)
+}
+
+// Exists returns true if the pool is imported.
+func (t *Pool) Exists() (bool, error) {
+	_, err := t.output("zpool", "list", "-H", "-o", "name", t.Name)
+	return exists(err)
+}
+
+// Info returns the listing of the imported pool.
+func (t *Pool) Info() (PoolInfo, error) {
+	b, err := t.output("zpool", "list", "-H", "-p", "-o", "name,size,alloc,free,health", t.Name)
+	if err != nil {
+		return PoolInfo{}, err
+	}
+	return parsePool(b)
+}
+
+// GetProperty returns the parsable value of the pool property.
+func (t *Pool) GetProperty(name string) (string, error) {
+	b, err := t.output("zpool", "get", "-H", "-p", "-o", "value", name, t.Name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// SetProperty sets the pool property value.
+func (t *Pool) SetProperty(name, value string) error {
+	return t.run("zpool", "set", name+"="+value, t.Name)
+}
+
+// parsePool parses the "zpool list -H -p -o name,size,alloc,free,health"
+// output of a pool.
+func parsePool(b []byte) (PoolInfo, error) {
+	line := strings.TrimSpace(string(b))
+	fields := strings.Split(line, "\t")
+	if len(fields) != 5 {
+		return PoolInfo{}, fmt.Errorf("zpool list: unexpected output %q", line)
+	}
+	info := PoolInfo{
+		Name:   fields[0],
+		Health: fields[4],
+	}
+	var err error
+	if info.Size, err = parseSize(fields[1]); err != nil {
+		return info, fmt.Errorf("zpool list %s: size: %w", info.Name, err)
+	}
+	if info.Alloc, err = parseSize(fields[2]); err != nil {
+		return info, fmt.Errorf("zpool list %s: alloc: %w", info.Name, err)
+	}
+	if info.Free, err = parseSize(fields[3]); err != nil {
+		return info, fmt.Errorf("zpool list %s: free: %w", info.Name, err)
+	}
+	return info, nil
+}
@@ -0,0 +1,87 @@
+package zfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/util/command"
+)
+
+func TestDataset(t *testing.T) {
+	fake := command.NewFake()
+	restore := command.SetExecutor(fake)
+	defer restore()
+	ds := NewDataset("tank/svc1")
+
+	t.Run("pool", func(t *testing.T) {
+		assert.Equal(t, "tank", ds.Pool())
+		assert.Equal(t, "tank", NewDataset("tank/svc1@daily").Pool())
+	})
+
+	t.Run("create", func(t *testing.T) {
+		fake.Reset()
+		fake.Set("zfs", command.Result{})
+		require.Nil(t, ds.Create(map[string]string{"mountpoint": "/srv/svc1", "compression": "lz4"}))
+		assert.Equal(t, "zfs create -p -o compression=lz4 -o mountpoint=/srv/svc1 tank/svc1", fake.Calls()[0].String())
+	})
+
+	t.Run("snapshots", func(t *testing.T) {
+		fake.Set("zfs", command.Result{Stdout: []byte("tank/svc1@a\tsnapshot\t0\t-\t1024\t-\t1600000000\ntank/svc1@b\tsnapshot\t512\t-\t2048\t-\t1600000060\n")})
+		l, err := ds.Snapshots()
+		require.Nil(t, err)
+		require.Len(t, l, 2)
+		assert.Equal(t, DatasetInfo{Name: "tank/svc1@b", Type: "snapshot", Used: 512, Refer: 2048, Mountpoint: "-", Created: time.Unix(1600000060, 0)}, l[1])
+	})
+
+	t.Run("not existing", func(t *testing.T) {
+		fake.Set("zfs", command.Result{Stderr: []byte("cannot open 'tank/svc1': dataset does not exist\n"), ExitCode: 1})
+		ok, err := ds.Exists()
+		require.Nil(t, err)
+		assert.False(t, ok)
+		_, err = ds.GetProperty("mountpoint")
+		assert.True(t, errors.Is(err, ErrNotExist))
+	})
+
+	t.Run("get property", func(t *testing.T) {
+		fake.Set("zfs", command.Result{Stdout: []byte("/srv/svc1\n")})
+		s, err := ds.GetProperty("mountpoint")
+		require.Nil(t, err)
+		assert.Equal(t, "/srv/svc1", s)
+	})
+
+	t.Run("invalid listing", func(t *testing.T) {
+		fake.Set("zfs", command.Result{Stdout: []byte("tank/svc1\tfilesystem\n")})
+		_, err := ds.Info()
+		assert.NotNil(t, err)
+	})
+}
+
+func TestPool(t *testing.T) {
+	fake := command.NewFake()
+	restore := command.SetExecutor(fake)
+	defer restore()
+	pool := NewPool("tank")
+
+	t.Run("create", func(t *testing.T) {
+		fake.Set("zpool", command.Result{})
+		require.Nil(t, pool.Create([]string{"mirror", "/dev/sdb", "/dev/sdc"}, map[string]string{"ashift": "12"}))
+		assert.Equal(t, "zpool create -f -o ashift=12 tank mirror /dev/sdb /dev/sdc", fake.Calls()[0].String())
+	})
+
+	t.Run("info", func(t *testing.T) {
+		fake.Set("zpool", command.Result{Stdout: []byte("tank\t1000\t400\t600\tONLINE\n")})
+		info, err := pool.Info()
+		require.Nil(t, err)
+		assert.Equal(t, PoolInfo{Name: "tank", Size: 1000, Alloc: 400, Free: 600, Health: "ONLINE"}, info)
+	})
+
+	t.Run("not imported", func(t *testing.T) {
+		fake.Set("zpool", command.Result{Stderr: []byte("cannot open 'tank': no such pool\n"), ExitCode: 1})
+		ok, err := pool.Exists()
+		require.Nil(t, err)
+		assert.False(t, ok)
+	})
+}
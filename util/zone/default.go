@@ -0,0 +1,17 @@
+// +build !solaris,!freebsd
+
+package zone
+
+import "net"
+
+func RootPath(name string) (string, error) {
+	return "", ErrNotApplicable
+}
+
+func AddAddr(name, ifName string, ipnet *net.IPNet) error {
+	return ErrNotApplicable
+}
+
+func DelAddr(name, ifName string, ipnet *net.IPNet) error {
+	return ErrNotApplicable
+}
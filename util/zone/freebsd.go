@@ -0,0 +1,114 @@
+// +build freebsd
+
+package zone
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/util/command"
+)
+
+// RootPath returns the root path of the jail <name>.
+func RootPath(name string) (string, error) {
+	cmd := command.New(
+		command.WithName("jls"),
+		command.WithVarArgs("-j", name, "path"),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "jail %s", name)
+	}
+	p, err := parseJailPath(cmd.Stdout())
+	if err != nil {
+		return "", errors.Wrapf(err, "jail %s", name)
+	}
+	return p, nil
+}
+
+func family(ipnet *net.IPNet) string {
+	if ipnet.IP.To4() != nil {
+		return "ip4"
+	}
+	return "ip6"
+}
+
+func jailAddrs(name, param string) ([]string, error) {
+	cmd := command.New(
+		command.WithName("jls"),
+		command.WithVarArgs("-j", name, param),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "jail %s", name)
+	}
+	l := make([]string, 0)
+	for _, s := range strings.Split(strings.TrimSpace(string(cmd.Stdout())), ",") {
+		if s = strings.TrimSpace(s); s != "" && s != "-" {
+			l = append(l, s)
+		}
+	}
+	return l, nil
+}
+
+func setJailAddrs(name, param string, l []string) error {
+	cmd := command.New(
+		command.WithName("jail"),
+		command.WithVarArgs("-m", "name="+name, param+"="+strings.Join(l, ",")),
+	)
+	return cmd.Run()
+}
+
+// AddAddr adds the address <ipnet> as an alias of <ifName>, and to the
+// addresses of the jail <name>.
+func AddAddr(name, ifName string, ipnet *net.IPNet) error {
+	ones, _ := ipnet.Mask.Size()
+	cmd := command.New(
+		command.WithName("ifconfig"),
+		command.WithVarArgs(ifName, family(ipnet), fmt.Sprintf("%s/%d", ipnet.IP, ones), "alias"),
+	)
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	param := family(ipnet) + ".addr"
+	l, err := jailAddrs(name, param)
+	if err != nil {
+		return err
+	}
+	ip := ipnet.IP.String()
+	for _, s := range l {
+		if s == ip {
+			return nil
+		}
+	}
+	return setJailAddrs(name, param, append(l, ip))
+}
+
+// DelAddr removes the address <ipnet> from the addresses of the jail
+// <name>, and from the aliases of <ifName>.
+func DelAddr(name, ifName string, ipnet *net.IPNet) error {
+	param := family(ipnet) + ".addr"
+	l, err := jailAddrs(name, param)
+	if err != nil {
+		return err
+	}
+	ip := ipnet.IP.String()
+	kept := make([]string, 0, len(l))
+	for _, s := range l {
+		if s != ip {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) != len(l) {
+		if err := setJailAddrs(name, param, kept); err != nil {
+			return err
+		}
+	}
+	cmd := command.New(
+		command.WithName("ifconfig"),
+		command.WithVarArgs(ifName, family(ipnet), ip, "-alias"),
+	)
+	return cmd.Run()
+}
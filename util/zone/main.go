@@ -0,0 +1,64 @@
+//
+// Package zone resolves the root path of Solaris zones and BSD jails, so
+// the drivers can reparent the paths they manage into the zone, and plumbs
+// ip addresses into zones.
+//
+package zone
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotApplicable is returned by the functions not supported on the
+// operating system.
+var ErrNotApplicable = errors.New("zones are not supported on this operating system")
+
+//
+// Reparent returns the path <p> relocated under the root path of the zone
+// <name>. The path is returned unchanged if <name> is empty, so the drivers
+// can call Reparent with their zone keyword value, set or not.
+//
+func Reparent(name, p string) (string, error) {
+	if name == "" {
+		return p, nil
+	}
+	root, err := RootPath(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, p), nil
+}
+
+//
+// parseZonepath parses the zonecfg info zonepath output:
+//
+//     zonepath: /zones/zone1
+//
+func parseZonepath(b []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(s, "zonepath:") {
+			continue
+		}
+		if p := strings.TrimSpace(s[9:]); p != "" {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("zonepath not found")
+}
+
+// parseJailPath parses the jls -j <name> path output, a single line
+// holding the jail root path.
+func parseJailPath(b []byte) (string, error) {
+	p := strings.TrimSpace(string(b))
+	if p == "" {
+		return "", fmt.Errorf("jail path not found")
+	}
+	return p, nil
+}
@@ -0,0 +1,29 @@
+package zone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReparent(t *testing.T) {
+	p, err := Reparent("", "/srv/data")
+	assert.NoError(t, err)
+	assert.Equal(t, "/srv/data", p, "the path is unchanged without zone")
+}
+
+func TestParseZonepath(t *testing.T) {
+	p, err := parseZonepath([]byte("zonepath: /zones/zone1\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/zones/zone1", p)
+	_, err = parseZonepath([]byte("zonecfg: No such zone configured\n"))
+	assert.Error(t, err)
+}
+
+func TestParseJailPath(t *testing.T) {
+	p, err := parseJailPath([]byte("/usr/jails/j1\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/usr/jails/j1", p)
+	_, err = parseJailPath([]byte("\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+// +build solaris
+
+package zone
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/util/command"
+)
+
+// RootPath returns the root path of the zone <name>, <zonepath>/root.
+func RootPath(name string) (string, error) {
+	cmd := command.New(
+		command.WithName("zonecfg"),
+		command.WithVarArgs("-z", name, "info", "zonepath"),
+		command.WithBufferedStdout(),
+	)
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "zone %s", name)
+	}
+	p, err := parseZonepath(cmd.Stdout())
+	if err != nil {
+		return "", errors.Wrapf(err, "zone %s", name)
+	}
+	return filepath.Join(p, "root"), nil
+}
+
+// AddAddr plumbs the address <ipnet> on a logical interface of <ifName>
+// assigned to the zone <name>.
+func AddAddr(name, ifName string, ipnet *net.IPNet) error {
+	ones, _ := ipnet.Mask.Size()
+	cmd := command.New(
+		command.WithName("ifconfig"),
+		command.WithVarArgs(ifName, "addif", fmt.Sprintf("%s/%d", ipnet.IP, ones), "zone", name, "up"),
+	)
+	return cmd.Run()
+}
+
+// DelAddr unplumbs the address <ipnet> from the logical interfaces of
+// <ifName>.
+func DelAddr(name, ifName string, ipnet *net.IPNet) error {
+	cmd := command.New(
+		command.WithName("ifconfig"),
+		command.WithVarArgs(ifName, "removeif", ipnet.IP.String()),
+	)
+	return cmd.Run()
+}